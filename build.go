@@ -0,0 +1,58 @@
+package ipld
+
+import (
+	"fmt"
+)
+
+// ListFromNodes builds a list, using the given NodeStyle,
+// which contains each of the given nodes in order.
+//
+// Each node is assigned with AssignNode, so the style's child assemblers
+// get their usual chance to reject (or convert) values of the wrong kind,
+// and any such error is returned.
+func ListFromNodes(ns NodeStyle, nodes []Node) (Node, error) {
+	nb := ns.NewBuilder()
+	la, err := nb.BeginList(len(nodes))
+	if err != nil {
+		return nil, err
+	}
+	for _, v := range nodes {
+		if err := la.AssembleValue().AssignNode(v); err != nil {
+			return nil, err
+		}
+	}
+	if err := la.Finish(); err != nil {
+		return nil, err
+	}
+	return nb.Build(), nil
+}
+
+// MapFromEntries builds a map, using the given NodeStyle,
+// which contains an entry for each of the keys, with the value at the same position.
+// The entries are inserted in order.
+//
+// It's an error for keys and values to be of different lengths.
+// Otherwise, errors are those from the map assembler:
+// for example, a repeated key returns ErrRepeatedMapKey.
+func MapFromEntries(ns NodeStyle, keys []Node, values []Node) (Node, error) {
+	if len(keys) != len(values) {
+		return nil, fmt.Errorf("cannot build map: %d keys but %d values", len(keys), len(values))
+	}
+	nb := ns.NewBuilder()
+	ma, err := nb.BeginMap(len(keys))
+	if err != nil {
+		return nil, err
+	}
+	for i := range keys {
+		if err := ma.AssembleKey().AssignNode(keys[i]); err != nil {
+			return nil, err
+		}
+		if err := ma.AssembleValue().AssignNode(values[i]); err != nil {
+			return nil, err
+		}
+	}
+	if err := ma.Finish(); err != nil {
+		return nil, err
+	}
+	return nb.Build(), nil
+}
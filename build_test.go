@@ -0,0 +1,51 @@
+package ipld_test
+
+import (
+	"testing"
+
+	. "github.com/warpfork/go-wish"
+
+	ipld "github.com/ipld/go-ipld-prime"
+	basicnode "github.com/ipld/go-ipld-prime/node/basic"
+)
+
+func TestListFromNodes(t *testing.T) {
+	nodes := []ipld.Node{
+		basicnode.NewInt(1),
+		basicnode.NewString("x"),
+		ipld.Null,
+		basicnode.NewBytes([]byte{0xa}),
+	}
+	n, err := ipld.ListFromNodes(basicnode.Style__List{}, nodes)
+	Wish(t, err, ShouldEqual, nil)
+	Wish(t, n.Length(), ShouldEqual, 4)
+	Wish(t, ipld.Sprint(n), ShouldEqual, `[1, "x", null, bytes(0a)]`)
+
+	t.Run("empty", func(t *testing.T) {
+		n, err := ipld.ListFromNodes(basicnode.Style__List{}, nil)
+		Wish(t, err, ShouldEqual, nil)
+		Wish(t, n.Length(), ShouldEqual, 0)
+	})
+	t.Run("wrong style", func(t *testing.T) {
+		_, err := ipld.ListFromNodes(basicnode.Style__Map{}, nodes)
+		Wish(t, err, ShouldBeSameTypeAs, ipld.ErrWrongKind{})
+	})
+}
+
+func TestMapFromEntries(t *testing.T) {
+	keys := []ipld.Node{basicnode.NewString("a"), basicnode.NewString("b"), basicnode.NewString("c")}
+	values := []ipld.Node{basicnode.NewBool(true), basicnode.NewFloat(1.5), ipld.Null}
+	n, err := ipld.MapFromEntries(basicnode.Style__Map{}, keys, values)
+	Wish(t, err, ShouldEqual, nil)
+	Wish(t, n.Length(), ShouldEqual, 3)
+	Wish(t, ipld.Sprint(n), ShouldEqual, `{"a": true, "b": 1.5, "c": null}`)
+
+	t.Run("length mismatch", func(t *testing.T) {
+		_, err := ipld.MapFromEntries(basicnode.Style__Map{}, keys, values[:2])
+		Wish(t, err.Error(), ShouldEqual, "cannot build map: 3 keys but 2 values")
+	})
+	t.Run("repeated key", func(t *testing.T) {
+		_, err := ipld.MapFromEntries(basicnode.Style__Map{}, append(keys, keys[0]), append(values, ipld.Null))
+		Wish(t, err, ShouldBeSameTypeAs, ipld.ErrRepeatedMapKey{})
+	})
+}
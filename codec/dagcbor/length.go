@@ -0,0 +1,75 @@
+package dagcbor
+
+import (
+	"math"
+
+	"github.com/polydawn/refmt/tok"
+
+	ipld "github.com/ipld/go-ipld-prime"
+)
+
+// EncodedLength returns the number of bytes Encoder would produce for n,
+// without producing them: it walks n as Marshal does, and adds up the size of each token
+// as the CBOR encoder would write it.  (Ints and lengths take the shortest form
+// which holds them; floats are always written in full, as 64 bits.)
+//
+// Nodes are walked generically, even if they have a fast path for encoding,
+// so the result is the length of the bytes the generic path would produce.
+// For codecs which can't do this, see codec.EncodedLength, which encodes and counts.
+func EncodedLength(n ipld.Node) (int, error) {
+	var sink lengthSink
+	err := Marshal(n, &sink)
+	return sink.length, err
+}
+
+// lengthSink is a TokenSink which adds up the length of the CBOR for each token, and writes nothing.
+type lengthSink struct {
+	length int
+}
+
+func (s *lengthSink) Step(tk *tok.Token) (done bool, err error) {
+	if tk.Tagged {
+		s.length += headLength(uint64(tk.Tag))
+	}
+	switch tk.Type {
+	case tok.TMapOpen, tok.TArrOpen:
+		s.length += headLength(uint64(tk.Length)) // (Marshal always gives the length up front.)
+	case tok.TMapClose, tok.TArrClose:
+		// Nothing is written for these, when the length was given up front.
+	case tok.TNull, tok.TBool:
+		s.length += 1
+	case tok.TInt:
+		if tk.Int >= 0 {
+			s.length += headLength(uint64(tk.Int))
+		} else {
+			s.length += headLength(uint64(-1 - tk.Int))
+		}
+	case tok.TUint:
+		s.length += headLength(tk.Uint)
+	case tok.TFloat64:
+		s.length += 9
+	case tok.TString:
+		s.length += headLength(uint64(len(tk.Str))) + len(tk.Str)
+	case tok.TBytes:
+		s.length += headLength(uint64(len(tk.Bytes))) + len(tk.Bytes)
+	default:
+		panic("unhandled token type")
+	}
+	return false, nil
+}
+
+// headLength is the length of a CBOR head (the major type and its argument) for the value v.
+func headLength(v uint64) int {
+	switch {
+	case v <= 0x17:
+		return 1
+	case v <= math.MaxUint8:
+		return 2
+	case v <= math.MaxUint16:
+		return 3
+	case v <= math.MaxUint32:
+		return 5
+	default:
+		return 9
+	}
+}
@@ -0,0 +1,86 @@
+package dagcbor
+
+import (
+	"bytes"
+	"math"
+	"strings"
+	"testing"
+
+	. "github.com/warpfork/go-wish"
+
+	cid "github.com/ipfs/go-cid"
+	ipld "github.com/ipld/go-ipld-prime"
+	"github.com/ipld/go-ipld-prime/codec"
+	"github.com/ipld/go-ipld-prime/fluent"
+	cidlink "github.com/ipld/go-ipld-prime/linking/cid"
+	basicnode "github.com/ipld/go-ipld-prime/node/basic"
+)
+
+func TestEncodedLength(t *testing.T) {
+	t.Run("fixtures", func(t *testing.T) {
+		l, err := EncodedLength(n)
+		Wish(t, err, ShouldEqual, nil)
+		Wish(t, l, ShouldEqual, len(serial))
+		l, err = EncodedLength(basicnode.NewString("applesauce"))
+		Wish(t, err, ShouldEqual, nil)
+		Wish(t, l, ShouldEqual, len(`japplesauce`))
+	})
+	t.Run("matches the encoder", func(t *testing.T) {
+		// Values either side of each size boundary of a CBOR head.
+		lnk := cidlink.Link{cid.NewCidV1(0x71, []byte{0x00, 0x03, 'a', 'b', 'c'})}
+		for _, tcase := range []ipld.Node{
+			basicnode.NewInt(0x17),
+			basicnode.NewInt(0x18),
+			basicnode.NewInt(0xff),
+			basicnode.NewInt(0x100),
+			basicnode.NewInt(0xffff),
+			basicnode.NewInt(0x10000),
+			basicnode.NewInt(math.MaxUint32),
+			basicnode.NewInt(math.MaxUint32 + 1),
+			basicnode.NewInt(math.MaxInt64),
+			basicnode.NewInt(-0x18),
+			basicnode.NewInt(-0x19),
+			basicnode.NewInt(math.MinInt64),
+			basicnode.NewFloat(1.5),
+			basicnode.NewBool(true),
+			ipld.Null,
+			basicnode.NewString(""),
+			basicnode.NewString(strings.Repeat("x", 0x17)),
+			basicnode.NewString(strings.Repeat("x", 0x18)),
+			basicnode.NewBytes(bytes.Repeat([]byte{1}, 0x100)),
+			basicnode.NewLink(lnk),
+			fluent.MustBuildList(basicnode.Style__List{}, 0x18, func(na fluent.ListAssembler) {
+				na.AssembleValue().AssignLink(lnk)
+				for i := 1; i < 0x18; i++ {
+					na.AssembleValue().AssignInt(i)
+				}
+			}),
+			fluent.MustBuildMap(basicnode.Style__Map{}, 2, func(na fluent.MapAssembler) {
+				na.AssembleEntry("link").AssignLink(lnk)
+				na.AssembleEntry("after").AssignInt(1)
+			}),
+		} {
+			var buf bytes.Buffer
+			Require(t, Encoder(tcase, &buf), ShouldEqual, nil)
+			l, err := EncodedLength(tcase)
+			Wish(t, err, ShouldEqual, nil)
+			Wish(t, l, ShouldEqual, buf.Len())
+			l, err = codec.EncodedLength(tcase, Encoder)
+			Wish(t, err, ShouldEqual, nil)
+			Wish(t, l, ShouldEqual, buf.Len())
+		}
+	})
+	t.Run("only the link is tagged", func(t *testing.T) {
+		lnk := cidlink.Link{cid.NewCidV1(0x71, []byte{0x00, 0x03, 'a', 'b', 'c'})}
+		list := fluent.MustBuildList(basicnode.Style__List{}, 2, func(na fluent.ListAssembler) {
+			na.AssembleValue().AssignLink(lnk)
+			na.AssembleValue().AssignInt(1)
+		})
+		var buf bytes.Buffer
+		Require(t, Encoder(list, &buf), ShouldEqual, nil)
+		Wish(t, buf.String(), ShouldEqual, "\x82\xd8\x2a\x48\x00\x01\x71\x00\x03abc\x01")
+		nb := basicnode.Style__Any{}.NewBuilder()
+		Require(t, Decoder(nb, &buf), ShouldEqual, nil)
+		Wish(t, ipld.DeepEqual(nb.Build(), list), ShouldEqual, true)
+	})
+}
@@ -6,6 +6,7 @@ import (
 	"github.com/polydawn/refmt/cbor"
 
 	ipld "github.com/ipld/go-ipld-prime"
+	"github.com/ipld/go-ipld-prime/codec"
 	cidlink "github.com/ipld/go-ipld-prime/linking/cid"
 )
 
@@ -32,6 +33,19 @@ func Decoder(na ipld.NodeAssembler, r io.Reader) error {
 	return Unmarshal(na, cbor.NewDecoder(cbor.DecodeOptions{}, r))
 }
 
+// DecoderWithOptions returns a Decoder which applies the limits in opts.
+// Use it (rather than Decoder) when decoding untrusted data.
+// The fast path used by Decoder is skipped, since it can't be limited.
+func DecoderWithOptions(opts codec.DecodeOptions) cidlink.MulticodecDecoder {
+	return func(na ipld.NodeAssembler, r io.Reader) error {
+		if opts.MaxScalarLength > 0 {
+			// The cborScanner checks the length in each string's head, before the decoder reads the rest.
+			r = &cborScanner{r: r, maxLen: opts.MaxScalarLength}
+		}
+		return UnmarshalWithOptions(na, cbor.NewDecoder(cbor.DecodeOptions{}, r), opts)
+	}
+}
+
 func Encoder(n ipld.Node, w io.Writer) error {
 	// Probe for a builtin fast path.  Shortcut to that if possible.
 	//  (ipldcbor.Node supports this, for example.)
@@ -2,10 +2,13 @@ package dagcbor
 
 import (
 	"bytes"
+	"strings"
 	"testing"
 
 	. "github.com/warpfork/go-wish"
 
+	ipld "github.com/ipld/go-ipld-prime"
+	"github.com/ipld/go-ipld-prime/codec"
 	"github.com/ipld/go-ipld-prime/fluent"
 	basicnode "github.com/ipld/go-ipld-prime/node/basic"
 )
@@ -62,3 +65,100 @@ func TestRoundtripScalar(t *testing.T) {
 		Wish(t, nb.Build(), ShouldEqual, simple)
 	})
 }
+
+func TestDecodeBudget(t *testing.T) {
+	opts := codec.DecodeOptions{MaxScalarLength: 1 << 20}
+	var buf bytes.Buffer
+	err := Encoder(basicnode.NewString(strings.Repeat("a", 10<<20)), &buf)
+	Require(t, err, ShouldEqual, nil)
+	t.Run("string over budget", func(t *testing.T) {
+		nb := basicnode.Style__Any{}.NewBuilder()
+		err := DecoderWithOptions(opts)(nb, bytes.NewReader(buf.Bytes()))
+		Wish(t, err, ShouldEqual, ipld.ErrBudgetExceeded{Kind: ipld.ReprKind_String, Length: 10 << 20, Budget: 1 << 20})
+	})
+	t.Run("checked before reading", func(t *testing.T) {
+		// Just the head of a 10MiB string, with none of it following:
+		// the error is the budget's, not an unexpected EOF from trying to read the rest.
+		nb := basicnode.Style__Any{}.NewBuilder()
+		err := DecoderWithOptions(opts)(nb, bytes.NewReader(buf.Bytes()[:5]))
+		Wish(t, err, ShouldEqual, ipld.ErrBudgetExceeded{Kind: ipld.ReprKind_String, Length: 10 << 20, Budget: 1 << 20})
+	})
+	t.Run("indefinite length", func(t *testing.T) {
+		// Bytes in chunks of 3 and 2, under a budget of 4; and a string of one chunk of 2, which fits.
+		serial := []byte{0x82, 0x5f, 0x43, 1, 2, 3, 0x42, 4, 5, 0xff, 0x7f, 0x62, 'h', 'i', 0xff}
+		nb := basicnode.Style__Any{}.NewBuilder()
+		err := DecoderWithOptions(codec.DecodeOptions{MaxScalarLength: 4})(nb, bytes.NewReader(serial))
+		Wish(t, err, ShouldEqual, ipld.ErrBudgetExceeded{Kind: ipld.ReprKind_Bytes, Length: 5, Budget: 4})
+		nb = basicnode.Style__Any{}.NewBuilder()
+		err = DecoderWithOptions(codec.DecodeOptions{MaxScalarLength: 5})(nb, bytes.NewReader(serial))
+		Require(t, err, ShouldEqual, nil)
+		Wish(t, ipld.Sprint(nb.Build()), ShouldEqual, `[bytes(0102030405), "hi"]`)
+	})
+	t.Run("no budget", func(t *testing.T) {
+		nb := basicnode.Style__Any{}.NewBuilder()
+		err := DecoderWithOptions(codec.DecodeOptions{})(nb, bytes.NewReader(buf.Bytes()))
+		Wish(t, err, ShouldEqual, nil)
+	})
+	t.Run("within budget", func(t *testing.T) {
+		nb := basicnode.Style__Map{}.NewBuilder()
+		err := DecoderWithOptions(opts)(nb, bytes.NewBufferString(serial))
+		Require(t, err, ShouldEqual, nil)
+		Wish(t, nb.Build(), ShouldEqual, n)
+	})
+}
+
+func TestDecodeRepeatedKeys(t *testing.T) {
+	// {"a": 1, "a": 2}, as a definite-length map of two entries.
+	serial := []byte{0xa2, 0x61, 'a', 0x01, 0x61, 'a', 0x02}
+	t.Run("strict", func(t *testing.T) {
+		nb := basicnode.Style__Any{}.NewBuilder()
+		err := Decoder(nb, bytes.NewReader(serial))
+		Wish(t, err, ShouldBeSameTypeAs, ipld.ErrRepeatedMapKey{})
+		Wish(t, err.Error(), ShouldEqual, `cannot repeat map key ("a")`)
+	})
+	t.Run("last wins", func(t *testing.T) {
+		nb := basicnode.Style__Any{}.NewBuilder()
+		err := DecoderWithOptions(codec.DecodeOptions{RepeatedKeysLastWins: true})(nb, bytes.NewReader(serial))
+		Require(t, err, ShouldEqual, nil)
+		Wish(t, ipld.Sprint(nb.Build()), ShouldEqual, `{"a": 2}`)
+	})
+}
+
+func TestDecodeIndefiniteList(t *testing.T) {
+	// An indefinite-length array (0x9f ... 0xff) of 1, 2, 3.
+	nb := basicnode.Style__List{}.NewBuilder()
+	err := Decoder(nb, bytes.NewReader([]byte{0x9f, 0x01, 0x02, 0x03, 0xff}))
+	Require(t, err, ShouldEqual, nil)
+	Wish(t, nb.Build(), ShouldEqual, fluent.MustBuildList(basicnode.Style__List{}, 3, func(na fluent.ListAssembler) {
+		na.AssembleValue().AssignInt(1)
+		na.AssembleValue().AssignInt(2)
+		na.AssembleValue().AssignInt(3)
+	}))
+}
+
+func TestDecodeIntegerOverflow(t *testing.T) {
+	// The largest uint64 (0x1b, then 8 bytes of 0xff), which is too big for an int.
+	nb := basicnode.Style__Any{}.NewBuilder()
+	err := Decoder(nb, bytes.NewReader([]byte{0x1b, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff}))
+	Wish(t, err, ShouldEqual, ipld.ErrIntegerOverflow{Value: "18446744073709551615"})
+}
+
+func TestDecodeStrictUTF8(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		cbor []byte
+	}{
+		{"string", []byte{0x63, 'a', 0xff, 'b'}},
+		{"map key", []byte{0xa1, 0x63, 'a', 0xff, 'b', 0x01}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			// DAG-CBOR can carry strings which aren't UTF-8, so by default they're let through...
+			nb := basicnode.Style__Any{}.NewBuilder()
+			Wish(t, Decoder(nb, bytes.NewReader(tc.cbor)), ShouldEqual, nil)
+			// ... but they can be rejected.
+			nb = basicnode.Style__Any{}.NewBuilder()
+			err := DecoderWithOptions(codec.DecodeOptions{StrictUTF8: true})(nb, bytes.NewReader(tc.cbor))
+			Wish(t, err, ShouldEqual, ipld.ErrInvalidUTF8{Offset: 1})
+		})
+	}
+}
@@ -0,0 +1,107 @@
+package dagcbor
+
+import (
+	"io"
+
+	ipld "github.com/ipld/go-ipld-prime"
+)
+
+// cborScanner passes through the bytes of a CBOR document as they're read,
+// watching the head of each string and bytes value, and returning ipld.ErrBudgetExceeded
+// from Read if one gives a length over maxLen -- before any of the value is read,
+// so the decoder never allocates room for it.
+// (Indefinite-length values are checked as each chunk's head arrives,
+// against the total of the chunks so far.)
+//
+// The Read which would deliver the last byte of such a head returns only the bytes before it,
+// since refmt's decoder discards an error which comes with all the bytes it asked for.
+type cborScanner struct {
+	r      io.Reader
+	maxLen int
+	err    error // once set, returned from every Read.
+
+	need  int    // the number of bytes of the current head's argument still to come.
+	major byte   // if need > 0: the major type of the current head.
+	arg   uint64 // if need > 0: the argument of the current head, so far.
+	skip  uint64 // the number of bytes of the current string or bytes value still to come.
+	indef bool   // whether we're within the chunks of an indefinite-length string or bytes value.
+	total uint64 // if indef: the length of its chunks so far.
+}
+
+func (s *cborScanner) Read(p []byte) (int, error) {
+	if s.err != nil {
+		return 0, s.err
+	}
+	n, err := s.r.Read(p)
+	for i := 0; i < n; {
+		if s.skip > 0 {
+			k := uint64(n - i)
+			if k > s.skip {
+				k = s.skip
+			}
+			s.skip -= k
+			i += int(k)
+			continue
+		}
+		if !s.scan(p[i]) {
+			return i, s.err
+		}
+		i++
+	}
+	return n, err
+}
+
+// scan takes one byte of a head, returning false if it completes a head which is over budget.
+func (s *cborScanner) scan(b byte) bool {
+	if s.need > 0 {
+		s.arg = s.arg<<8 | uint64(b)
+		s.need--
+		if s.need == 0 {
+			return s.endHead()
+		}
+		return true
+	}
+	s.major = b >> 5
+	switch ai := b & 0x1f; {
+	case ai < 24:
+		s.arg = uint64(ai)
+		return s.endHead()
+	case ai <= 27:
+		s.arg = 0
+		s.need = 1 << (ai - 24)
+	case ai == 31 && (s.major == 2 || s.major == 3):
+		s.indef = true
+		s.total = 0
+	case b == 0xff:
+		s.indef = false
+	}
+	return true
+}
+
+// endHead checks the length given by a string or bytes head, and arranges to skip over the value.
+func (s *cborScanner) endHead() bool {
+	if s.major != 2 && s.major != 3 {
+		return true
+	}
+	length := s.arg
+	if s.indef && length <= uint64(s.maxLen) { // (checking first, so the sum can't overflow.)
+		s.total += s.arg
+		length = s.total
+	}
+	if length > uint64(s.maxLen) {
+		k := ipld.ReprKind_Bytes
+		if s.major == 3 {
+			k = ipld.ReprKind_String
+		}
+		n := maxInt
+		if length < uint64(maxInt) {
+			n = int(length)
+		}
+		s.err = ipld.ErrBudgetExceeded{Kind: k, Length: n, Budget: s.maxLen}
+		return false
+	}
+	s.skip = s.arg
+	return true
+}
+
+const maxInt = int(^uint(0) >> 1)
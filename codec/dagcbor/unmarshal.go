@@ -10,6 +10,7 @@ import (
 	"github.com/polydawn/refmt/tok"
 
 	ipld "github.com/ipld/go-ipld-prime"
+	"github.com/ipld/go-ipld-prime/codec"
 	cidlink "github.com/ipld/go-ipld-prime/linking/cid"
 )
 
@@ -22,6 +23,12 @@ var (
 // which has dag-cbor's special sauce for detecting schemafree links.
 
 func Unmarshal(na ipld.NodeAssembler, tokSrc shared.TokenSource) error {
+	return UnmarshalWithOptions(na, tokSrc, codec.DecodeOptions{})
+}
+
+// UnmarshalWithOptions is Unmarshal, with limits and strictness as configured by codec.DecodeOptions.
+func UnmarshalWithOptions(na ipld.NodeAssembler, tokSrc shared.TokenSource, opts codec.DecodeOptions) error {
+	tokSrc = opts.TokenSource(tokSrc)
 	var tk tok.Token
 	done, err := tokSrc.Step(&tk)
 	if err != nil {
@@ -30,12 +37,12 @@ func Unmarshal(na ipld.NodeAssembler, tokSrc shared.TokenSource) error {
 	if done && !tk.Type.IsValue() {
 		return fmt.Errorf("unexpected eof")
 	}
-	return unmarshal(na, tokSrc, &tk)
+	return unmarshal(na, tokSrc, &tk, opts)
 }
 
 // starts with the first token already primed.  Necessary to get recursion
 //  to flow right without a peek+unpeek system.
-func unmarshal(na ipld.NodeAssembler, tokSrc shared.TokenSource, tk *tok.Token) error {
+func unmarshal(na ipld.NodeAssembler, tokSrc shared.TokenSource, tk *tok.Token, opts codec.DecodeOptions) error {
 	// FUTURE: check for schema.TypedNodeBuilder that's going to parse a Link (they can slurp any token kind they want).
 	switch tk.Type {
 	case tok.TMapOpen:
@@ -70,11 +77,17 @@ func unmarshal(na ipld.NodeAssembler, tokSrc shared.TokenSource, tk *tok.Token)
 			if observedLen > expectLen {
 				return fmt.Errorf("unexpected continuation of map elements beyond declared length")
 			}
+			if err := opts.CheckString(len(tk.Str)); err != nil {
+				return err
+			}
+			if err := opts.CheckUTF8(tk.Str); err != nil {
+				return err
+			}
 			mva, err := ma.AssembleEntry(tk.Str)
 			if err != nil { // return in error if the key was rejected
 				return err
 			}
-			err = Unmarshal(mva, tokSrc)
+			err = UnmarshalWithOptions(mva, tokSrc, opts)
 			if err != nil { // return in error if some part of the recursion errored
 				return err
 			}
@@ -82,13 +95,13 @@ func unmarshal(na ipld.NodeAssembler, tokSrc shared.TokenSource, tk *tok.Token)
 	case tok.TMapClose:
 		return fmt.Errorf("unexpected mapClose token")
 	case tok.TArrOpen:
+		// An indefinite length is passed on to BeginList as -1, rather than
+		//  as a zero-length hint, so the assembler knows not to treat it as a bound.
 		expectLen := tk.Length
-		allocLen := tk.Length
 		if tk.Length == -1 {
 			expectLen = math.MaxInt32
-			allocLen = 0
 		}
-		la, err := na.BeginList(allocLen)
+		la, err := na.BeginList(tk.Length)
 		if err != nil {
 			return err
 		}
@@ -109,7 +122,7 @@ func unmarshal(na ipld.NodeAssembler, tokSrc shared.TokenSource, tk *tok.Token)
 				if observedLen > expectLen {
 					return fmt.Errorf("unexpected continuation of array elements beyond declared length")
 				}
-				err := unmarshal(la.AssembleValue(), tokSrc, tk)
+				err := unmarshal(la.AssembleValue(), tokSrc, tk, opts)
 				if err != nil { // return in error if some part of the recursion errored
 					return err
 				}
@@ -120,8 +133,17 @@ func unmarshal(na ipld.NodeAssembler, tokSrc shared.TokenSource, tk *tok.Token)
 	case tok.TNull:
 		return na.AssignNull()
 	case tok.TString:
+		if err := opts.CheckString(len(tk.Str)); err != nil {
+			return err
+		}
+		if err := opts.CheckUTF8(tk.Str); err != nil {
+			return err
+		}
 		return na.AssignString(tk.Str)
 	case tok.TBytes:
+		if err := opts.CheckBytes(len(tk.Bytes)); err != nil {
+			return err
+		}
 		if !tk.Tagged {
 			return na.AssignBytes(tk.Bytes)
 		}
@@ -140,10 +162,12 @@ func unmarshal(na ipld.NodeAssembler, tokSrc shared.TokenSource, tk *tok.Token)
 		}
 	case tok.TBool:
 		return na.AssignBool(tk.Bool)
-	case tok.TInt:
-		return na.AssignInt(int(tk.Int)) // FIXME overflow check
-	case tok.TUint:
-		return na.AssignInt(int(tk.Uint)) // FIXME overflow check
+	case tok.TInt, tok.TUint:
+		i, err := codec.TokenInt(tk)
+		if err != nil {
+			return err
+		}
+		return na.AssignInt(i)
 	case tok.TFloat64:
 		return na.AssignFloat(tk.Float64)
 	default:
@@ -2,6 +2,7 @@ package dagjson
 
 import (
 	"fmt"
+	"sort"
 
 	"github.com/polydawn/refmt/shared"
 	"github.com/polydawn/refmt/tok"
@@ -15,6 +16,12 @@ import (
 // which is dag-json's special sauce for schemafree links.
 
 func Marshal(n ipld.Node, sink shared.TokenSink) error {
+	return marshal(n, sink, false)
+}
+
+// marshal is Marshal, optionally emitting the entries of each map sorted by key
+// (bytewise, which is DAG-JSON's canonical order), rather than in the map's own order.
+func marshal(n ipld.Node, sink shared.TokenSink, sortKeys bool) error {
 	var tk tok.Token
 	switch n.ReprKind() {
 	case ipld.ReprKind_Invalid:
@@ -31,26 +38,38 @@ func Marshal(n ipld.Node, sink shared.TokenSink) error {
 			return err
 		}
 		// Emit map contents (and recurse).
-		for itr := n.MapIterator(); !itr.Done(); {
-			k, v, err := itr.Next()
-			if err != nil {
+		emit := func(k string, v ipld.Node) error {
+			tk.Type = tok.TString
+			tk.Str = k
+			if _, err := sink.Step(&tk); err != nil {
 				return err
 			}
-			tk.Type = tok.TString
-			tk.Str, err = k.AsString()
+			return marshal(v, sink, sortKeys)
+		}
+		var entries []mapEntry // only used if sorting.
+		err := ipld.DrainMapIterator(n.MapIterator(), func(k ipld.Node, v ipld.Node) error {
+			ks, err := k.AsString()
 			if err != nil {
 				return err
 			}
-			if _, err := sink.Step(&tk); err != nil {
-				return err
+			if sortKeys {
+				entries = append(entries, mapEntry{ks, v})
+				return nil
 			}
-			if err := Marshal(v, sink); err != nil {
+			return emit(ks, v)
+		})
+		if err != nil {
+			return err
+		}
+		sort.Slice(entries, func(i, j int) bool { return entries[i].k < entries[j].k })
+		for _, e := range entries {
+			if err := emit(e.k, e.v); err != nil {
 				return err
 			}
 		}
 		// Emit map close.
 		tk.Type = tok.TMapClose
-		_, err := sink.Step(&tk)
+		_, err = sink.Step(&tk)
 		return err
 	case ipld.ReprKind_List:
 		// Emit start of list.
@@ -66,7 +85,7 @@ func Marshal(n ipld.Node, sink shared.TokenSink) error {
 			if err != nil {
 				return err
 			}
-			if err := Marshal(v, sink); err != nil {
+			if err := marshal(v, sink, sortKeys); err != nil {
 				return err
 			}
 		}
@@ -153,3 +172,8 @@ func Marshal(n ipld.Node, sink shared.TokenSink) error {
 		panic("unreachable")
 	}
 }
+
+type mapEntry struct {
+	k string
+	v ipld.Node
+}
@@ -7,6 +7,7 @@ import (
 	"github.com/polydawn/refmt/json"
 
 	ipld "github.com/ipld/go-ipld-prime"
+	"github.com/ipld/go-ipld-prime/codec"
 	cidlink "github.com/ipld/go-ipld-prime/linking/cid"
 )
 
@@ -21,9 +22,24 @@ func init() {
 }
 
 func Decoder(na ipld.NodeAssembler, r io.Reader) error {
+	return decode(na, r, codec.DecodeOptions{})
+}
+
+// DecoderWithOptions returns a Decoder which applies the limits in opts.
+// Use it (rather than Decoder) when decoding untrusted data.
+func DecoderWithOptions(opts codec.DecodeOptions) cidlink.MulticodecDecoder {
+	return func(na ipld.NodeAssembler, r io.Reader) error {
+		return decode(na, r, opts)
+	}
+}
+
+func decode(na ipld.NodeAssembler, r io.Reader, opts codec.DecodeOptions) error {
 	// Shell out directly to generic builder path.
 	//  (There's not really any fastpaths of note for json.)
-	err := Unmarshal(na, json.NewDecoder(r))
+	//  (The jsonScanner is in the way so that integers too big for an int64 are errors, rather than floats,
+	//   so that invalid UTF-8 can be rejected before refmt replaces it,
+	//   and so that strings over budget are rejected before refmt has read them whole.)
+	err := UnmarshalWithOptions(na, json.NewDecoder(&jsonScanner{r: r, strictUTF8: opts.StrictUTF8, maxLen: opts.MaxScalarLength}), opts)
 	if err != nil {
 		return err
 	}
@@ -49,15 +65,48 @@ func Decoder(na ipld.NodeAssembler, r io.Reader) error {
 			return err
 		}
 	}
-	return err
 }
 
 func Encoder(n ipld.Node, w io.Writer) error {
 	// Shell out directly to generic inspection path.
 	//  (There's not really any fastpaths of note for json.)
-	// Write another function if you need to tune encoding options about whitespace.
+	// See Encode if you need to tune encoding options about whitespace.
 	return Marshal(n, json.NewEncoder(w, json.EncodeOptions{
 		Line:   []byte{'\n'},
 		Indent: []byte{'\t'},
 	}))
 }
+
+// EncodeOptions holds settings for the layout of encoded DAG-JSON.
+// The zero value means compact output, with map entries in each map's own order.
+//
+// None of these settings change what the data means:
+// the output decodes to the same data, whatever they are.
+type EncodeOptions struct {
+	// Indent, if not empty, puts each map entry and list member on a line of its own,
+	// indented by Indent once per level of nesting (e.g. "\t", or two spaces).
+	Indent string
+
+	// SortKeys, if true, emits the entries of each map sorted by key, bytewise,
+	// which is DAG-JSON's canonical order.
+	// (Sorting means each map's entries are gathered before any of them are emitted.)
+	SortKeys bool
+}
+
+// Encode encodes a node as DAG-JSON, laid out according to opts.
+// (Encoder is the same as Encode with an Indent of "\t", and without sorting.)
+func Encode(n ipld.Node, w io.Writer, opts EncodeOptions) error {
+	var jopts json.EncodeOptions
+	if opts.Indent != "" {
+		jopts.Line = []byte{'\n'}
+		jopts.Indent = []byte(opts.Indent)
+	}
+	return marshal(n, json.NewEncoder(w, jopts), opts.SortKeys)
+}
+
+// EncoderWithOptions returns an Encoder which lays out its output according to opts.
+func EncoderWithOptions(opts EncodeOptions) cidlink.MulticodecEncoder {
+	return func(n ipld.Node, w io.Writer) error {
+		return Encode(n, w, opts)
+	}
+}
@@ -2,10 +2,14 @@ package dagjson
 
 import (
 	"bytes"
+	"strconv"
+	"strings"
 	"testing"
 
 	. "github.com/warpfork/go-wish"
 
+	ipld "github.com/ipld/go-ipld-prime"
+	"github.com/ipld/go-ipld-prime/codec"
 	"github.com/ipld/go-ipld-prime/fluent"
 	basicnode "github.com/ipld/go-ipld-prime/node/basic"
 )
@@ -78,3 +82,180 @@ func TestRoundtripScalar(t *testing.T) {
 		Wish(t, nb.Build(), ShouldEqual, simple)
 	})
 }
+
+func TestEncodeOptions(t *testing.T) {
+	encode := func(n ipld.Node, opts EncodeOptions) string {
+		var buf bytes.Buffer
+		Require(t, Encode(n, &buf, opts), ShouldEqual, nil)
+		return buf.String()
+	}
+	t.Run("zero value is compact, in map order", func(t *testing.T) {
+		Wish(t, encode(n, EncodeOptions{}), ShouldEqual, `{"plain":"olde string","map":{"one":1,"two":2},"list":["three","four"],"nested":{"deeper":["things"]}}`)
+	})
+	t.Run("sorted", func(t *testing.T) {
+		Wish(t, encode(n, EncodeOptions{SortKeys: true}), ShouldEqual, `{"list":["three","four"],"map":{"one":1,"two":2},"nested":{"deeper":["things"]},"plain":"olde string"}`)
+	})
+	t.Run("sorted bytewise", func(t *testing.T) {
+		n := fluent.MustBuildMap(basicnode.Style__Map{}, 4, func(na fluent.MapAssembler) {
+			na.AssembleEntry("b").AssignInt(1)
+			na.AssembleEntry("aa").AssignInt(2)
+			na.AssembleEntry("é").AssignInt(3)
+			na.AssembleEntry("B").AssignInt(4)
+		})
+		Wish(t, encode(n, EncodeOptions{SortKeys: true}), ShouldEqual, `{"B":4,"aa":2,"b":1,"é":3}`)
+	})
+	t.Run("sorted and indented", func(t *testing.T) {
+		out := encode(n, EncodeOptions{Indent: "  ", SortKeys: true})
+		Wish(t, out, ShouldEqual, `{
+  "list": [
+    "three",
+    "four"
+  ],
+  "map": {
+    "one": 1,
+    "two": 2
+  },
+  "nested": {
+    "deeper": [
+      "things"
+    ]
+  },
+  "plain": "olde string"
+}
+`)
+		// It's still the same data.
+		nb := basicnode.Style__Map{}.NewBuilder()
+		Require(t, Decoder(nb, strings.NewReader(out)), ShouldEqual, nil)
+		Wish(t, encode(nb.Build(), EncodeOptions{SortKeys: true}), ShouldEqual, encode(n, EncodeOptions{SortKeys: true}))
+	})
+	t.Run("Encoder's layout, with options", func(t *testing.T) {
+		var buf bytes.Buffer
+		Require(t, EncoderWithOptions(EncodeOptions{Indent: "\t"})(n, &buf), ShouldEqual, nil)
+		Wish(t, buf.String(), ShouldEqual, serial)
+	})
+}
+
+func TestDecodeBudget(t *testing.T) {
+	opts := codec.DecodeOptions{MaxScalarLength: 1 << 20}
+	big := strings.Repeat("a", 10<<20)
+	// The scanner stops reading as soon as a string is over budget,
+	// so the Length in the error is one past the budget, not the whole length.
+	t.Run("string value over budget", func(t *testing.T) {
+		nb := basicnode.Style__Any{}.NewBuilder()
+		r := strings.NewReader(`{"k": "` + big + `"}`)
+		err := DecoderWithOptions(opts)(nb, r)
+		Wish(t, err, ShouldEqual, ipld.ErrBudgetExceeded{Kind: ipld.ReprKind_String, Length: 1<<20 + 1, Budget: 1 << 20})
+		Wish(t, r.Len() > 8<<20, ShouldEqual, true) // most of it was never read.
+	})
+	t.Run("map key over budget", func(t *testing.T) {
+		nb := basicnode.Style__Any{}.NewBuilder()
+		err := DecoderWithOptions(opts)(nb, strings.NewReader(`{"`+big+`": 1}`))
+		Wish(t, err, ShouldEqual, ipld.ErrBudgetExceeded{Kind: ipld.ReprKind_String, Length: 1<<20 + 1, Budget: 1 << 20})
+	})
+	t.Run("escapes count as they decode", func(t *testing.T) {
+		// (The scanner counts the "\u" escape as one byte; it's the later check which finds it's two.)
+		opts := codec.DecodeOptions{MaxScalarLength: 5}
+		nb := basicnode.Style__Any{}.NewBuilder()
+		err := DecoderWithOptions(opts)(nb, strings.NewReader(`"\\n\u00e9\""`))
+		Require(t, err, ShouldEqual, nil)
+		Wish(t, nb.Build(), ShouldEqual, basicnode.NewString(`\né"`))
+		nb = basicnode.Style__Any{}.NewBuilder()
+		err = DecoderWithOptions(opts)(nb, strings.NewReader(`"\\n\u00e9\"!!"`))
+		Wish(t, err, ShouldEqual, ipld.ErrBudgetExceeded{Kind: ipld.ReprKind_String, Length: 6, Budget: 5})
+	})
+	t.Run("within budget", func(t *testing.T) {
+		nb := basicnode.Style__Map{}.NewBuilder()
+		err := DecoderWithOptions(opts)(nb, bytes.NewBufferString(serial))
+		Require(t, err, ShouldEqual, nil)
+		Wish(t, nb.Build(), ShouldEqual, n)
+	})
+}
+
+func TestDecodeRepeatedKeys(t *testing.T) {
+	t.Run("strict", func(t *testing.T) {
+		nb := basicnode.Style__Any{}.NewBuilder()
+		err := Decoder(nb, strings.NewReader(`{"a":1,"a":2}`))
+		Wish(t, err, ShouldBeSameTypeAs, ipld.ErrRepeatedMapKey{})
+		Wish(t, err.Error(), ShouldEqual, `cannot repeat map key ("a")`)
+	})
+	t.Run("last wins", func(t *testing.T) {
+		opts := codec.DecodeOptions{RepeatedKeysLastWins: true}
+		nb := basicnode.Style__Any{}.NewBuilder()
+		err := DecoderWithOptions(opts)(nb, strings.NewReader(`{"a":1,"a":2}`))
+		Require(t, err, ShouldEqual, nil)
+		Wish(t, ipld.Sprint(nb.Build()), ShouldEqual, `{"a": 2}`)
+
+		// Repeats are dropped at every depth, before links are recognized.
+		const linkCid = "QmUNLLsPACCz1vLxQVkXqqLX5R1X345qqfHbsf67hvA3Nn"
+		nb = basicnode.Style__Any{}.NewBuilder()
+		err = DecoderWithOptions(opts)(nb, strings.NewReader(`{"b":[{"x":1,"x":{"y":1,"y":2}}],"a":1,"b":{"/":"`+linkCid+`"}}`))
+		Require(t, err, ShouldEqual, nil)
+		Wish(t, ipld.Sprint(nb.Build()), ShouldEqual, `{"b": link(`+linkCid+`), "a": 1}`)
+	})
+}
+
+func TestDecodeLargeList(t *testing.T) {
+	const size = 1000000
+	var buf bytes.Buffer
+	buf.WriteByte('[')
+	for i := 0; i < size; i++ {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		buf.WriteString(strconv.Itoa(i))
+	}
+	buf.WriteByte(']')
+
+	// JSON never declares lengths up front, so this goes through BeginList(-1).
+	nb := basicnode.Style__List{}.NewBuilder()
+	err := Decoder(nb, &buf)
+	Require(t, err, ShouldEqual, nil)
+	n := nb.Build()
+	Wish(t, n.Length(), ShouldEqual, size)
+	for _, i := range []int{0, 1, size / 2, size - 1} {
+		v, err := n.LookupIndex(i)
+		Require(t, err, ShouldEqual, nil)
+		Wish(t, v, ShouldEqual, basicnode.NewInt(i))
+	}
+}
+
+func TestDecodeIntegerOverflow(t *testing.T) {
+	for _, tc := range []struct {
+		json string
+		err  error
+	}{
+		{`99999999999999999999`, ipld.ErrIntegerOverflow{Value: "99999999999999999999"}},
+		{`-9223372036854775809`, ipld.ErrIntegerOverflow{Value: "-9223372036854775809"}},
+		{`{"a":[1,99999999999999999999],"b":2}`, ipld.ErrIntegerOverflow{Value: "99999999999999999999"}},
+		{`9223372036854775807`, nil},
+		{`"99999999999999999999"`, nil},
+		{`["\"", 99999999999999999999.0, 1e20]`, nil},
+	} {
+		nb := basicnode.Style__Any{}.NewBuilder()
+		err := Decoder(nb, strings.NewReader(tc.json))
+		Wish(t, err, ShouldEqual, tc.err)
+	}
+}
+
+func TestDecodeStrictUTF8(t *testing.T) {
+	for _, tc := range []struct {
+		json string
+		err  error
+	}{
+		{"\"a\xffb\"", ipld.ErrInvalidUTF8{Offset: 1}},
+		{"{\"k\xe4\xb8\":1}", ipld.ErrInvalidUTF8{Offset: 1}},      // a truncated rune, ended by the quote.
+		{"[\"\\n\\\"\xe4\xb8x\"]", ipld.ErrInvalidUTF8{Offset: 4}}, // (offsets count escapes as written.)
+		{"\"\xc0\x80\"", ipld.ErrInvalidUTF8{Offset: 0}},
+		{"[\"héllo, 世界\", \"\\u00e9\", \"\\ud83d\\ude00\"]", nil},
+	} {
+		nb := basicnode.Style__Any{}.NewBuilder()
+		err := DecoderWithOptions(codec.DecodeOptions{StrictUTF8: true})(nb, strings.NewReader(tc.json))
+		Wish(t, err, ShouldEqual, tc.err)
+	}
+
+	// Without StrictUTF8, refmt replaces the invalid bytes.
+	nb := basicnode.Style__Any{}.NewBuilder()
+	Wish(t, Decoder(nb, strings.NewReader("\"a\xffb\"")), ShouldEqual, nil)
+	s, _ := nb.Build().AsString()
+	Wish(t, s, ShouldEqual, "a\uFFFDb")
+}
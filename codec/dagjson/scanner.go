@@ -0,0 +1,171 @@
+package dagjson
+
+import (
+	"io"
+	"strconv"
+	"unicode/utf8"
+
+	ipld "github.com/ipld/go-ipld-prime"
+)
+
+// jsonScanner passes through the bytes of a JSON document as they're read,
+// watching for integers which are too big for an int64,
+// and returning ipld.ErrIntegerOverflow from Read if it sees one.
+// If strictUTF8 is set, it also watches for strings which aren't valid UTF-8,
+// returning ipld.ErrInvalidUTF8 if it sees one.
+//
+// refmt's JSON decoder would give us those as float tokens (after failing to parse them as ints),
+// which would quietly lose their value; and by then, there's no telling them apart
+// from numbers which really were written as floats, so this has to happen on the way in.
+// The error can come a little before the decoder reaches the integer (since it reads ahead),
+// but since it fails the whole decode either way, that doesn't matter.
+//
+// Invalid UTF-8 has to be caught on the way in for a similar reason:
+// refmt's JSON decoder replaces it with U+FFFD, so the strings in the tokens are always valid.
+// The offset in the error counts bytes of the string as it's written in the document,
+// so escape sequences before the invalid byte count for their full length.
+//
+// If maxLen is positive, it also counts the length of each string (map keys included)
+// as it goes, returning ipld.ErrBudgetExceeded as soon as it's over maxLen,
+// so the decoder never holds more than that much of it.
+// The count is of the string as it will be decoded, at the least: each escape sequence counts as one byte,
+// although "\u" escapes may decode to more.  So the Length in the error is maxLen+1, not the whole length.
+//
+// The Read which would deliver the byte that's in error returns only the bytes before it.
+type jsonScanner struct {
+	r          io.Reader
+	strictUTF8 bool
+	maxLen     int
+	err        error // once set, returned from every Read.
+	inString   bool
+	escaped    bool // if inString: whether the last byte was a backslash.
+	hexLeft    int  // if inString: the number of hex digits of a "\u" escape still to come.
+	strLen     int  // if inString: the decoded length of the string so far (at least).
+	inNumber   bool
+	num        []byte // if inNumber: the number literal so far.
+
+	strPos    int     // if inString: the offset of the current byte within the string.
+	runeStart int     // if runeLen > 0: the offset of the rune's first byte.
+	runeBuf   [4]byte // if runeLen > 0: the bytes of a multibyte rune read so far.
+	runeLen   int
+}
+
+func (s *jsonScanner) Read(p []byte) (int, error) {
+	if s.err != nil {
+		return 0, s.err
+	}
+	n, err := s.r.Read(p)
+	for i, b := range p[:n] {
+		s.scan(b)
+		if s.err != nil {
+			return i, s.err
+		}
+	}
+	if err == io.EOF && s.inNumber {
+		s.endNumber()
+	}
+	if s.err != nil {
+		return n, s.err
+	}
+	return n, err
+}
+
+func (s *jsonScanner) scan(b byte) {
+	if s.inNumber {
+		switch {
+		case b >= '0' && b <= '9', b == '-', b == '+', b == '.', b == 'e', b == 'E':
+			s.num = append(s.num, b)
+			return
+		}
+		s.endNumber()
+	}
+	if s.inString && s.strictUTF8 {
+		s.scanUTF8(b)
+	}
+	switch {
+	case s.inString && s.hexLeft > 0:
+		s.hexLeft--
+	case s.inString && s.escaped:
+		s.escaped = false
+		if b == 'u' {
+			s.hexLeft = 4
+		}
+	case s.inString:
+		s.escaped = b == '\\'
+		s.inString = b != '"'
+		if s.inString {
+			s.countByte()
+		}
+	case b == '"':
+		s.inString = true
+		s.strLen = 0
+		s.strPos = -1 // (scanUTF8 counts each byte before looking at it.)
+		s.runeLen = 0
+	case b >= '0' && b <= '9', b == '-':
+		s.inNumber = true
+		s.num = append(s.num[:0], b)
+	}
+}
+
+// countByte counts one more byte of the current string, against maxLen.
+func (s *jsonScanner) countByte() {
+	s.strLen++
+	if s.maxLen > 0 && s.strLen > s.maxLen && s.err == nil {
+		s.err = ipld.ErrBudgetExceeded{Kind: ipld.ReprKind_String, Length: s.strLen, Budget: s.maxLen}
+	}
+}
+
+func (s *jsonScanner) endNumber() {
+	s.inNumber = false
+	if len(s.num) < 19 {
+		return // too short to be out of range, whatever it is.  (The largest int64 is 19 digits long.)
+	}
+	for _, b := range s.num {
+		if b == '.' || b == 'e' || b == 'E' {
+			return // it's a float.
+		}
+	}
+	lit := string(s.num)
+	if _, err := strconv.ParseInt(lit, 10, 64); err != nil && err.(*strconv.NumError).Err == strconv.ErrRange && s.err == nil {
+		s.err = ipld.ErrIntegerOverflow{Value: lit}
+	}
+}
+
+func (s *jsonScanner) scanUTF8(b byte) {
+	s.strPos++
+	if s.runeLen > 0 {
+		if b&0xC0 != 0x80 { // not a continuation byte; the rune ended early.
+			s.invalidUTF8()
+			s.runeLen = 0
+		} else {
+			s.runeBuf[s.runeLen] = b
+			s.runeLen++
+			s.endRune()
+			return
+		}
+	}
+	if b < utf8.RuneSelf {
+		return
+	}
+	s.runeStart = s.strPos
+	s.runeBuf[0] = b
+	s.runeLen = 1
+	s.endRune()
+}
+
+// endRune checks the rune in runeBuf, if it's complete (or can already be told to be invalid).
+func (s *jsonScanner) endRune() {
+	if !utf8.FullRune(s.runeBuf[:s.runeLen]) {
+		return
+	}
+	if r, size := utf8.DecodeRune(s.runeBuf[:s.runeLen]); r == utf8.RuneError && size == 1 {
+		s.invalidUTF8()
+	}
+	s.runeLen = 0
+}
+
+func (s *jsonScanner) invalidUTF8() {
+	if s.err == nil {
+		s.err = ipld.ErrInvalidUTF8{Offset: s.runeStart}
+	}
+}
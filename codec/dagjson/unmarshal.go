@@ -8,6 +8,7 @@ import (
 	"github.com/polydawn/refmt/tok"
 
 	ipld "github.com/ipld/go-ipld-prime"
+	"github.com/ipld/go-ipld-prime/codec"
 	cidlink "github.com/ipld/go-ipld-prime/linking/cid"
 )
 
@@ -20,7 +21,13 @@ import (
 //        tokens before deciding what kind of value to create).
 
 func Unmarshal(na ipld.NodeAssembler, tokSrc shared.TokenSource) error {
-	var st unmarshalState
+	return UnmarshalWithOptions(na, tokSrc, codec.DecodeOptions{})
+}
+
+// UnmarshalWithOptions is Unmarshal, with limits and strictness as configured by codec.DecodeOptions.
+func UnmarshalWithOptions(na ipld.NodeAssembler, tokSrc shared.TokenSource, opts codec.DecodeOptions) error {
+	tokSrc = opts.TokenSource(tokSrc)
+	st := unmarshalState{opts: opts}
 	done, err := tokSrc.Step(&st.tk[0])
 	if err != nil {
 		return err
@@ -34,6 +41,7 @@ func Unmarshal(na ipld.NodeAssembler, tokSrc shared.TokenSource) error {
 type unmarshalState struct {
 	tk    [4]tok.Token // mostly, only 0'th is used... but [1:4] are used during lookahead for links.
 	shift int          // how many times to slide something out of tk[1:4] instead of getting a new token.
+	opts  codec.DecodeOptions
 }
 
 // step leaves a "new" token in tk[0],
@@ -161,6 +169,12 @@ func (st *unmarshalState) unmarshal(na ipld.NodeAssembler, tokSrc shared.TokenSo
 			default:
 				return fmt.Errorf("unexpected %s token while expecting map key", st.tk[0].Type)
 			}
+			if err := st.opts.CheckString(len(st.tk[0].Str)); err != nil {
+				return err
+			}
+			if err := st.opts.CheckUTF8(st.tk[0].Str); err != nil {
+				return err
+			}
 			mva, err := ma.AssembleEntry(st.tk[0].Str)
 			if err != nil { // return in error if the key was rejected
 				return err
@@ -202,15 +216,26 @@ func (st *unmarshalState) unmarshal(na ipld.NodeAssembler, tokSrc shared.TokenSo
 	case tok.TNull:
 		return na.AssignNull()
 	case tok.TString:
+		if err := st.opts.CheckString(len(st.tk[0].Str)); err != nil {
+			return err
+		}
+		if err := st.opts.CheckUTF8(st.tk[0].Str); err != nil {
+			return err
+		}
 		return na.AssignString(st.tk[0].Str)
 	case tok.TBytes:
+		if err := st.opts.CheckBytes(len(st.tk[0].Bytes)); err != nil {
+			return err
+		}
 		return na.AssignBytes(st.tk[0].Bytes)
 	case tok.TBool:
 		return na.AssignBool(st.tk[0].Bool)
-	case tok.TInt:
-		return na.AssignInt(int(st.tk[0].Int)) // FIXME overflow check
-	case tok.TUint:
-		return na.AssignInt(int(st.tk[0].Uint)) // FIXME overflow check
+	case tok.TInt, tok.TUint:
+		i, err := codec.TokenInt(&st.tk[0])
+		if err != nil {
+			return err
+		}
+		return na.AssignInt(i)
 	case tok.TFloat64:
 		return na.AssignFloat(st.tk[0].Float64)
 	default:
@@ -0,0 +1,141 @@
+package dagpb
+
+import (
+	"encoding/binary"
+	"fmt"
+
+	ipld "github.com/ipld/go-ipld-prime"
+	cidlink "github.com/ipld/go-ipld-prime/linking/cid"
+)
+
+// Marshal encodes a node of the DAG-PB shape (see the package docs) into a block.
+//
+// The fields of the node may be in any order; the output is always in the
+// order DAG-PB requires, so the same data always encodes to the same bytes.
+func Marshal(n ipld.Node) ([]byte, error) {
+	var pbn pbNode
+	hasLinks := false
+	if n.ReprKind() != ipld.ReprKind_Map {
+		return nil, fmt.Errorf("dagpb: PBNode must be a map, not %s", n.ReprKind())
+	}
+	err := ipld.ForEach(n, func(k, v ipld.Node) error {
+		ks, err := k.AsString()
+		if err != nil {
+			return err
+		}
+		switch ks {
+		case "Data":
+			pbn.data, err = v.AsBytes()
+			pbn.hasData = true
+			return err
+		case "Links":
+			if v.ReprKind() != ipld.ReprKind_List {
+				return fmt.Errorf("dagpb: PBNode Links must be a list, not %s", v.ReprKind())
+			}
+			hasLinks = true
+			for itr := v.ListIterator(); !itr.Done(); {
+				_, lv, err := itr.Next()
+				if err != nil {
+					return err
+				}
+				l, err := unpackLink(lv)
+				if err != nil {
+					return err
+				}
+				pbn.links = append(pbn.links, l)
+			}
+			return nil
+		default:
+			return fmt.Errorf("dagpb: PBNode has unknown field %q", ks)
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	if !hasLinks {
+		return nil, fmt.Errorf("dagpb: PBNode has no Links")
+	}
+	return pbn.encode(), nil
+}
+
+func unpackLink(n ipld.Node) (pbLink, error) {
+	var l pbLink
+	if n.ReprKind() != ipld.ReprKind_Map {
+		return l, fmt.Errorf("dagpb: PBLink must be a map, not %s", n.ReprKind())
+	}
+	err := ipld.ForEach(n, func(k, v ipld.Node) error {
+		ks, err := k.AsString()
+		if err != nil {
+			return err
+		}
+		switch ks {
+		case "Hash":
+			lnk, err := v.AsLink()
+			if err != nil {
+				return err
+			}
+			cl, ok := lnk.(cidlink.Link)
+			if !ok {
+				return fmt.Errorf("dagpb: PBLink Hash must be a cidlink.Link, not %T", lnk)
+			}
+			l.hash = cl.Cid
+			return nil
+		case "Name":
+			l.name, err = v.AsString()
+			l.hasName = true
+			return err
+		case "Tsize":
+			l.tsize, err = v.AsInt()
+			if err == nil && l.tsize < 0 {
+				return fmt.Errorf("dagpb: PBLink Tsize must not be negative")
+			}
+			l.hasTsize = true
+			return err
+		default:
+			return fmt.Errorf("dagpb: PBLink has unknown field %q", ks)
+		}
+	})
+	if err != nil {
+		return l, err
+	}
+	if !l.hash.Defined() {
+		return l, fmt.Errorf("dagpb: PBLink has no Hash")
+	}
+	return l, nil
+}
+
+// encode writes the Links, and then the Data, as DAG-PB requires.
+func (pbn pbNode) encode() []byte {
+	var b []byte
+	for _, l := range pbn.links {
+		b = appendBytesField(b, 2, l.encode())
+	}
+	if pbn.hasData {
+		b = appendBytesField(b, 1, pbn.data)
+	}
+	return b
+}
+
+// encode writes the fields of a link in the order Hash, Name, Tsize, as DAG-PB requires.
+func (l pbLink) encode() []byte {
+	b := appendBytesField(nil, 1, l.hash.Bytes())
+	if l.hasName {
+		b = appendBytesField(b, 2, []byte(l.name))
+	}
+	if l.hasTsize {
+		b = appendVarint(b, 3<<3|wireVarint)
+		b = appendVarint(b, uint64(l.tsize))
+	}
+	return b
+}
+
+func appendVarint(b []byte, v uint64) []byte {
+	var buf [binary.MaxVarintLen64]byte
+	return append(b, buf[:binary.PutUvarint(buf[:], v)]...)
+}
+
+func appendBytesField(b []byte, field uint64, v []byte) []byte {
+	b = appendVarint(b, field<<3|wireBytes)
+	b = appendVarint(b, uint64(len(v)))
+	return append(b, v...)
+}
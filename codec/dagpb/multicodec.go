@@ -0,0 +1,76 @@
+// Package dagpb implements the DAG-PB multicodec (0x70), the Protobuf-based
+// format used by IPFS (and UnixFS in particular).
+//
+// DAG-PB data always has the same shape, which in the Data Model is:
+//
+//	{
+//		"Data": bytes,  # optional
+//		"Links": [      # required, but may be empty
+//			{
+//				"Hash": link,    # required
+//				"Name": string,  # optional
+//				"Tsize": int,    # optional
+//			},
+//		],
+//	}
+//
+// Decoding produces a map of that shape, and encoding accepts only that shape
+// (any other field is an error).  The Hash of a link must be a cidlink.Link.
+//
+// The wire format has strict ordering rules, which this package follows:
+// a node's Links are written before its Data, and a link's fields are
+// written in the order Hash, Name, Tsize.  The decoder rejects data
+// which breaks those rules, repeats a field, or pads a varint with
+// trailing zero bytes, so that every block has
+// exactly one encoding.  (Links are kept in the order given; they aren't sorted.)
+//
+// Importing this package registers it with cidlink, so that links with the
+// dag-pb codec can be loaded (and built) like any other.
+package dagpb
+
+import (
+	"io"
+	"io/ioutil"
+
+	ipld "github.com/ipld/go-ipld-prime"
+	"github.com/ipld/go-ipld-prime/codec"
+	cidlink "github.com/ipld/go-ipld-prime/linking/cid"
+)
+
+var (
+	_ cidlink.MulticodecDecoder = Decoder
+	_ cidlink.MulticodecEncoder = Encoder
+)
+
+func init() {
+	cidlink.RegisterMulticodecDecoder(0x70, Decoder)
+	cidlink.RegisterMulticodecEncoder(0x70, Encoder)
+}
+
+// Decoder reads a whole DAG-PB block and assembles it into the NodeAssembler,
+// which must accept a map.
+func Decoder(na ipld.NodeAssembler, r io.Reader) error {
+	return DecoderWithOptions(codec.DecodeOptions{})(na, r)
+}
+
+// DecoderWithOptions returns a Decoder which applies the limits in opts
+// to the Data bytes and to each link's Name.
+func DecoderWithOptions(opts codec.DecodeOptions) cidlink.MulticodecDecoder {
+	return func(na ipld.NodeAssembler, r io.Reader) error {
+		b, err := ioutil.ReadAll(r)
+		if err != nil {
+			return err
+		}
+		return Unmarshal(na, b, opts)
+	}
+}
+
+// Encoder writes a node of the DAG-PB shape (see the package docs).
+func Encoder(n ipld.Node, w io.Writer) error {
+	b, err := Marshal(n)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(b)
+	return err
+}
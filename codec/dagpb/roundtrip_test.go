@@ -0,0 +1,113 @@
+package dagpb
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"testing"
+
+	cid "github.com/ipfs/go-cid"
+	. "github.com/warpfork/go-wish"
+
+	ipld "github.com/ipld/go-ipld-prime"
+	"github.com/ipld/go-ipld-prime/codec"
+	"github.com/ipld/go-ipld-prime/fluent"
+	cidlink "github.com/ipld/go-ipld-prime/linking/cid"
+	basicnode "github.com/ipld/go-ipld-prime/node/basic"
+)
+
+// emptyDir is the well-known empty UnixFS directory: just a Data field holding
+// the UnixFS header for a directory, and no links.
+var (
+	emptyDir    = []byte{0x0a, 0x02, 0x08, 0x01}
+	emptyDirCid = "QmUNLLsPACCz1vLxQVkXqqLX5R1X345qqfHbsf67hvA3Nn"
+)
+
+func decode(t *testing.T, b []byte) ipld.Node {
+	nb := basicnode.Style__Any{}.NewBuilder()
+	Require(t, Unmarshal(nb, b, codec.DecodeOptions{}), ShouldEqual, nil)
+	return nb.Build()
+}
+
+func TestEmptyDirFixture(t *testing.T) {
+	n := decode(t, emptyDir)
+	Wish(t, n, ShouldEqual, fluent.MustBuildMap(basicnode.Style__Map{}, 2, func(na fluent.MapAssembler) {
+		na.AssembleEntry("Data").AssignBytes([]byte{0x08, 0x01})
+		na.AssembleEntry("Links").CreateList(0, func(na fluent.ListAssembler) {})
+	}))
+	b, err := Marshal(n)
+	Wish(t, err, ShouldEqual, nil)
+	Wish(t, b, ShouldEqual, emptyDir)
+
+	// Building a CIDv0 link to it must give the well-known CID.
+	lb := cidlink.LinkBuilder{cid.Prefix{Version: 0, Codec: 0x70, MhType: 0x12, MhLength: 32}}
+	lnk, err := lb.Build(context.Background(), ipld.LinkContext{}, n,
+		func(ipld.LinkContext) (io.Writer, ipld.StoreCommitter, error) {
+			return &bytes.Buffer{}, func(ipld.Link) error { return nil }, nil
+		},
+	)
+	Require(t, err, ShouldEqual, nil)
+	Wish(t, lnk.String(), ShouldEqual, emptyDirCid)
+}
+
+func TestRoundtripWithLinks(t *testing.T) {
+	c, err := cid.Decode(emptyDirCid)
+	Require(t, err, ShouldEqual, nil)
+	n := fluent.MustBuildMap(basicnode.Style__Map{}, 2, func(na fluent.MapAssembler) {
+		// Deliberately in the "wrong" order: the encoder must reorder.
+		na.AssembleEntry("Links").CreateList(1, func(na fluent.ListAssembler) {
+			na.AssembleValue().CreateMap(3, func(na fluent.MapAssembler) {
+				na.AssembleEntry("Tsize").AssignInt(4)
+				na.AssembleEntry("Name").AssignString("foo")
+				na.AssembleEntry("Hash").AssignLink(cidlink.Link{c})
+			})
+		})
+		na.AssembleEntry("Data").AssignBytes([]byte{0x08, 0x01})
+	})
+	var want []byte
+	want = append(want, 0x12, 0x2b, 0x0a, 0x22)
+	want = append(want, c.Bytes()...)
+	want = append(want, 0x12, 0x03, 'f', 'o', 'o', 0x18, 0x04)
+	want = append(want, emptyDir...)
+
+	b, err := Marshal(n)
+	Require(t, err, ShouldEqual, nil)
+	Wish(t, b, ShouldEqual, want)
+	Wish(t, ipld.Sprint(decode(t, b)), ShouldEqual, `{"Data": bytes(0801), "Links": [{"Hash": link(`+emptyDirCid+`), "Name": "foo", "Tsize": 4}]}`)
+}
+
+func TestDecodeRejectsNoncanonical(t *testing.T) {
+	c, _ := cid.Decode(emptyDirCid)
+	link := append([]byte{0x0a, 0x22}, c.Bytes()...)
+	for _, tc := range []struct {
+		name string
+		b    []byte
+		err  string
+	}{
+		{"Links after Data", append(append([]byte{}, emptyDir...), append([]byte{0x12, 0x24}, link...)...), "dagpb: PBNode has Links after Data"},
+		{"repeated Data", append(append([]byte{}, emptyDir...), emptyDir...), "dagpb: PBNode has more than one Data field"},
+		{"link fields out of order", append([]byte{0x12, 0x26, 0x12, 0x00}, link...), "dagpb: PBLink field 1 is out of order or repeated"},
+		{"link without Hash", []byte{0x12, 0x02, 0x18, 0x01}, "dagpb: PBLink has no Hash"},
+		{"unknown field", []byte{0x1a, 0x00}, "dagpb: PBNode has unknown field 3"},
+		{"truncated", []byte{0x0a, 0x05, 0x08}, "dagpb: unexpected eof"},
+		{"non-minimal varint", []byte{0x0a, 0x82, 0x00, 0x08, 0x01}, "dagpb: varint is not minimally encoded"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			err := Unmarshal(basicnode.Style__Any{}.NewBuilder(), tc.b, codec.DecodeOptions{})
+			Require(t, err == nil, ShouldEqual, false)
+			Wish(t, err.Error(), ShouldEqual, tc.err)
+		})
+	}
+}
+
+func TestEncodeRejectsWrongShape(t *testing.T) {
+	_, err := Marshal(fluent.MustBuildMap(basicnode.Style__Map{}, 1, func(na fluent.MapAssembler) {
+		na.AssembleEntry("Data").AssignBytes(nil)
+	}))
+	Wish(t, err.Error(), ShouldEqual, "dagpb: PBNode has no Links")
+	_, err = Marshal(fluent.MustBuildMap(basicnode.Style__Map{}, 2, func(na fluent.MapAssembler) {
+		na.AssembleEntry("Links").CreateList(0, func(na fluent.ListAssembler) {})
+		na.AssembleEntry("Extra").AssignNull()
+	}))
+	Wish(t, err.Error(), ShouldEqual, `dagpb: PBNode has unknown field "Extra"`)
+}
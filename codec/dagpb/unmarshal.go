@@ -0,0 +1,257 @@
+package dagpb
+
+import (
+	"encoding/binary"
+	"fmt"
+
+	cid "github.com/ipfs/go-cid"
+
+	ipld "github.com/ipld/go-ipld-prime"
+	"github.com/ipld/go-ipld-prime/codec"
+	cidlink "github.com/ipld/go-ipld-prime/linking/cid"
+)
+
+// Protobuf wire types.  DAG-PB only uses these two.
+const (
+	wireVarint = 0
+	wireBytes  = 2
+)
+
+// pbNode and pbLink hold a decoded block before it's given to a NodeAssembler.
+// A block's Links come before its Data on the wire, but the Data Model form
+// puts "Data" first, so we need the whole thing in hand before assembling.
+type pbNode struct {
+	links   []pbLink
+	data    []byte
+	hasData bool
+}
+
+type pbLink struct {
+	hash     cid.Cid
+	name     string
+	hasName  bool
+	tsize    int
+	hasTsize bool
+}
+
+// Unmarshal decodes the DAG-PB block in b and assembles it into na.
+func Unmarshal(na ipld.NodeAssembler, b []byte, opts codec.DecodeOptions) error {
+	pbn, err := decodeNode(b, opts)
+	if err != nil {
+		return err
+	}
+	return pbn.assemble(na)
+}
+
+func decodeNode(b []byte, opts codec.DecodeOptions) (pbNode, error) {
+	var pbn pbNode
+	for len(b) > 0 {
+		field, wt, rest, err := readTag(b)
+		if err != nil {
+			return pbn, err
+		}
+		if wt != wireBytes {
+			return pbn, fmt.Errorf("dagpb: PBNode field %d has wire type %d; expected %d", field, wt, wireBytes)
+		}
+		var v []byte
+		v, b, err = readBytes(rest)
+		if err != nil {
+			return pbn, err
+		}
+		switch field {
+		case 1:
+			if pbn.hasData {
+				return pbn, fmt.Errorf("dagpb: PBNode has more than one Data field")
+			}
+			if err := opts.CheckBytes(len(v)); err != nil {
+				return pbn, err
+			}
+			pbn.data = v
+			pbn.hasData = true
+		case 2:
+			if pbn.hasData {
+				return pbn, fmt.Errorf("dagpb: PBNode has Links after Data")
+			}
+			l, err := decodeLink(v, opts)
+			if err != nil {
+				return pbn, err
+			}
+			pbn.links = append(pbn.links, l)
+		default:
+			return pbn, fmt.Errorf("dagpb: PBNode has unknown field %d", field)
+		}
+	}
+	return pbn, nil
+}
+
+func decodeLink(b []byte, opts codec.DecodeOptions) (pbLink, error) {
+	var l pbLink
+	var last uint64
+	for len(b) > 0 {
+		field, wt, rest, err := readTag(b)
+		if err != nil {
+			return l, err
+		}
+		if field <= last {
+			return l, fmt.Errorf("dagpb: PBLink field %d is out of order or repeated", field)
+		}
+		last = field
+		switch field {
+		case 1, 2:
+			if wt != wireBytes {
+				return l, fmt.Errorf("dagpb: PBLink field %d has wire type %d; expected %d", field, wt, wireBytes)
+			}
+			var v []byte
+			v, b, err = readBytes(rest)
+			if err != nil {
+				return l, err
+			}
+			if field == 1 {
+				if l.hash, err = cid.Cast(v); err != nil {
+					return l, fmt.Errorf("dagpb: PBLink has an invalid Hash: %s", err)
+				}
+			} else {
+				if err := opts.CheckString(len(v)); err != nil {
+					return l, err
+				}
+				l.name = string(v)
+				if err := opts.CheckUTF8(l.name); err != nil {
+					return l, err
+				}
+				l.hasName = true
+			}
+		case 3:
+			if wt != wireVarint {
+				return l, fmt.Errorf("dagpb: PBLink field %d has wire type %d; expected %d", field, wt, wireVarint)
+			}
+			var v uint64
+			v, b, err = readVarint(rest)
+			if err != nil {
+				return l, err
+			}
+			if v > uint64(maxInt) {
+				return l, fmt.Errorf("dagpb: PBLink Tsize %d is too large", v)
+			}
+			l.tsize = int(v)
+			l.hasTsize = true
+		default:
+			return l, fmt.Errorf("dagpb: PBLink has unknown field %d", field)
+		}
+	}
+	if !l.hash.Defined() {
+		return l, fmt.Errorf("dagpb: PBLink has no Hash")
+	}
+	return l, nil
+}
+
+const maxInt = int(^uint(0) >> 1)
+
+// readVarint reads an unsigned varint, which must be in its minimal form:
+// a varint padded with trailing zero bytes (e.g. 0x82 0x00 for 2) would be
+// another encoding of the same value, so it's rejected.
+func readVarint(b []byte) (uint64, []byte, error) {
+	v, n := binary.Uvarint(b)
+	if n <= 0 {
+		return 0, nil, fmt.Errorf("dagpb: invalid varint")
+	}
+	if n > 1 && b[n-1] == 0 {
+		return 0, nil, fmt.Errorf("dagpb: varint is not minimally encoded")
+	}
+	return v, b[n:], nil
+}
+
+func readTag(b []byte) (field uint64, wt uint64, rest []byte, err error) {
+	v, rest, err := readVarint(b)
+	if err != nil {
+		return 0, 0, nil, err
+	}
+	return v >> 3, v & 7, rest, nil
+}
+
+func readBytes(b []byte) (v []byte, rest []byte, err error) {
+	l, b, err := readVarint(b)
+	if err != nil {
+		return nil, nil, err
+	}
+	if l > uint64(len(b)) {
+		return nil, nil, fmt.Errorf("dagpb: unexpected eof")
+	}
+	return b[:l], b[l:], nil
+}
+
+func (pbn pbNode) assemble(na ipld.NodeAssembler) error {
+	size := 1
+	if pbn.hasData {
+		size++
+	}
+	ma, err := na.BeginMap(size)
+	if err != nil {
+		return err
+	}
+	if pbn.hasData {
+		va, err := ma.AssembleEntry("Data")
+		if err != nil {
+			return err
+		}
+		if err := va.AssignBytes(pbn.data); err != nil {
+			return err
+		}
+	}
+	va, err := ma.AssembleEntry("Links")
+	if err != nil {
+		return err
+	}
+	la, err := va.BeginList(len(pbn.links))
+	if err != nil {
+		return err
+	}
+	for _, l := range pbn.links {
+		if err := l.assemble(la.AssembleValue()); err != nil {
+			return err
+		}
+	}
+	if err := la.Finish(); err != nil {
+		return err
+	}
+	return ma.Finish()
+}
+
+func (l pbLink) assemble(na ipld.NodeAssembler) error {
+	size := 1
+	if l.hasName {
+		size++
+	}
+	if l.hasTsize {
+		size++
+	}
+	ma, err := na.BeginMap(size)
+	if err != nil {
+		return err
+	}
+	va, err := ma.AssembleEntry("Hash")
+	if err != nil {
+		return err
+	}
+	if err := va.AssignLink(cidlink.Link{l.hash}); err != nil {
+		return err
+	}
+	if l.hasName {
+		va, err := ma.AssembleEntry("Name")
+		if err != nil {
+			return err
+		}
+		if err := va.AssignString(l.name); err != nil {
+			return err
+		}
+	}
+	if l.hasTsize {
+		va, err := ma.AssembleEntry("Tsize")
+		if err != nil {
+			return err
+		}
+		if err := va.AssignInt(l.tsize); err != nil {
+			return err
+		}
+	}
+	return ma.Finish()
+}
@@ -0,0 +1,29 @@
+package codec
+
+import (
+	"io"
+
+	ipld "github.com/ipld/go-ipld-prime"
+)
+
+// EncodedLength returns the number of bytes the given encoder
+// (e.g. dagjson.Encoder, or any other cidlink.MulticodecEncoder) produces for n.
+// It's meant for size estimates, like deciding where to split data into blocks.
+//
+// This works with any encoder, by encoding and counting the bytes
+// (which are then thrown away, so nothing is buffered).
+// Some codecs can do better, and compute the length by walking the node
+// without encoding anything; see e.g. dagcbor.EncodedLength.
+func EncodedLength(n ipld.Node, encoder func(ipld.Node, io.Writer) error) (int, error) {
+	var cw countingWriter
+	err := encoder(n, &cw)
+	return int(cw), err
+}
+
+// countingWriter discards what's written to it, counting the bytes.
+type countingWriter int
+
+func (cw *countingWriter) Write(p []byte) (int, error) {
+	*cw += countingWriter(len(p))
+	return len(p), nil
+}
@@ -23,112 +23,118 @@ func Marshal(n ipld.Node, sink shared.TokenSink) error {
 }
 
 func marshal(n ipld.Node, tk *tok.Token, sink shared.TokenSink) error {
-	switch n.ReprKind() {
-	case ipld.ReprKind_Invalid:
-		return fmt.Errorf("cannot traverse a node that is undefined")
-	case ipld.ReprKind_Null:
-		tk.Type = tok.TNull
+	step := func() error {
 		_, err := sink.Step(tk)
 		return err
-	case ipld.ReprKind_Map:
-		// Emit start of map.
-		tk.Type = tok.TMapOpen
-		tk.Length = n.Length()
-		if _, err := sink.Step(tk); err != nil {
-			return err
-		}
-		// Emit map contents (and recurse).
-		for itr := n.MapIterator(); !itr.Done(); {
-			k, v, err := itr.Next()
-			if err != nil {
+	}
+	var kd ipld.KindDispatch
+	kd = ipld.KindDispatch{
+		OnNull: func(n ipld.Node) error {
+			tk.Type = tok.TNull
+			return step()
+		},
+		OnMap: func(n ipld.Node) error {
+			// Emit start of map.
+			tk.Type = tok.TMapOpen
+			tk.Length = n.Length()
+			if err := step(); err != nil {
 				return err
 			}
-			tk.Type = tok.TString
-			tk.Str, err = k.AsString()
+			// Emit map contents (and recurse).
+			for itr := n.MapIterator(); !itr.Done(); {
+				k, v, err := itr.Next()
+				if err != nil {
+					return err
+				}
+				tk.Type = tok.TString
+				tk.Str, err = k.AsString()
+				if err != nil {
+					return err
+				}
+				if err := step(); err != nil {
+					return err
+				}
+				if err := kd.Do(v); err != nil {
+					return err
+				}
+			}
+			// Emit map close.
+			tk.Type = tok.TMapClose
+			return step()
+		},
+		OnList: func(n ipld.Node) error {
+			// Emit start of list.
+			tk.Type = tok.TArrOpen
+			l := n.Length()
+			tk.Length = l
+			if err := step(); err != nil {
+				return err
+			}
+			// Emit list contents (and recurse).
+			for i := 0; i < l; i++ {
+				v, err := n.LookupIndex(i)
+				if err != nil {
+					return err
+				}
+				if err := kd.Do(v); err != nil {
+					return err
+				}
+			}
+			// Emit list close.
+			tk.Type = tok.TArrClose
+			return step()
+		},
+		OnBool: func(n ipld.Node) error {
+			v, err := n.AsBool()
 			if err != nil {
 				return err
 			}
-			if _, err := sink.Step(tk); err != nil {
+			tk.Type = tok.TBool
+			tk.Bool = v
+			return step()
+		},
+		OnInt: func(n ipld.Node) error {
+			v, err := n.AsInt()
+			if err != nil {
 				return err
 			}
-			if err := marshal(v, tk, sink); err != nil {
+			tk.Type = tok.TInt
+			tk.Int = int64(v)
+			return step()
+		},
+		OnFloat: func(n ipld.Node) error {
+			v, err := n.AsFloat()
+			if err != nil {
 				return err
 			}
-		}
-		// Emit map close.
-		tk.Type = tok.TMapClose
-		_, err := sink.Step(tk)
-		return err
-	case ipld.ReprKind_List:
-		// Emit start of list.
-		tk.Type = tok.TArrOpen
-		l := n.Length()
-		tk.Length = l
-		if _, err := sink.Step(tk); err != nil {
-			return err
-		}
-		// Emit list contents (and recurse).
-		for i := 0; i < l; i++ {
-			v, err := n.LookupIndex(i)
+			tk.Type = tok.TFloat64
+			tk.Float64 = v
+			return step()
+		},
+		OnString: func(n ipld.Node) error {
+			v, err := n.AsString()
 			if err != nil {
 				return err
 			}
-			if err := marshal(v, tk, sink); err != nil {
+			tk.Type = tok.TString
+			tk.Str = v
+			return step()
+		},
+		OnBytes: func(n ipld.Node) error {
+			v, err := n.AsBytes()
+			if err != nil {
 				return err
 			}
-		}
-		// Emit list close.
-		tk.Type = tok.TArrClose
-		_, err := sink.Step(tk)
-		return err
-	case ipld.ReprKind_Bool:
-		v, err := n.AsBool()
-		if err != nil {
-			return err
-		}
-		tk.Type = tok.TBool
-		tk.Bool = v
-		_, err = sink.Step(tk)
-		return err
-	case ipld.ReprKind_Int:
-		v, err := n.AsInt()
-		if err != nil {
-			return err
-		}
-		tk.Type = tok.TInt
-		tk.Int = int64(v)
-		_, err = sink.Step(tk)
-		return err
-	case ipld.ReprKind_Float:
-		v, err := n.AsFloat()
-		if err != nil {
-			return err
-		}
-		tk.Type = tok.TFloat64
-		tk.Float64 = v
-		_, err = sink.Step(tk)
-		return err
-	case ipld.ReprKind_String:
-		v, err := n.AsString()
-		if err != nil {
-			return err
-		}
-		tk.Type = tok.TString
-		tk.Str = v
-		_, err = sink.Step(tk)
-		return err
-	case ipld.ReprKind_Bytes:
-		v, err := n.AsBytes()
-		if err != nil {
-			return err
-		}
-		tk.Type = tok.TBytes
-		tk.Bytes = v
-		_, err = sink.Step(tk)
-		return err
-	case ipld.ReprKind_Link:
-		return fmt.Errorf("link emission not supported by this codec without a schema!  (maybe you want dag-cbor or dag-json)")
-	default:
-		panic("unreachable")
+			tk.Type = tok.TBytes
+			tk.Bytes = v
+			return step()
+		},
+		OnLink: func(n ipld.Node) error {
+			return fmt.Errorf("link emission not supported by this codec without a schema!  (maybe you want dag-cbor or dag-json)")
+		},
+		Default: func(n ipld.Node) error {
+			return fmt.Errorf("cannot traverse a node that is undefined")
+		},
 	}
+	return kd.Do(n)
 }
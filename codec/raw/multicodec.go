@@ -0,0 +1,68 @@
+// Package raw implements the "raw" multicodec (0x55),
+// which is no encoding at all: the block's bytes are the data,
+// and the Node is a single bytes-kind node holding them.
+//
+// Importing this package registers it with cidlink, so that links with the
+// raw codec can be loaded (and built) like any other.
+package raw
+
+import (
+	"io"
+	"io/ioutil"
+
+	ipld "github.com/ipld/go-ipld-prime"
+	"github.com/ipld/go-ipld-prime/codec"
+	cidlink "github.com/ipld/go-ipld-prime/linking/cid"
+)
+
+var (
+	_ cidlink.MulticodecDecoder = Decoder
+	_ cidlink.MulticodecEncoder = Encoder
+)
+
+func init() {
+	cidlink.RegisterMulticodecDecoder(0x55, Decoder)
+	cidlink.RegisterMulticodecEncoder(0x55, Encoder)
+}
+
+// Decoder reads all the bytes and assigns them to the NodeAssembler,
+// which must accept bytes.
+func Decoder(na ipld.NodeAssembler, r io.Reader) error {
+	b, err := ioutil.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	return na.AssignBytes(b)
+}
+
+// DecoderWithOptions returns a Decoder which applies the limits in opts.
+// With a MaxScalarLength set, it reads at most one byte more than the limit,
+// so an oversized block is rejected without ever being held in memory.
+func DecoderWithOptions(opts codec.DecodeOptions) cidlink.MulticodecDecoder {
+	if opts.MaxScalarLength <= 0 {
+		return Decoder
+	}
+	return func(na ipld.NodeAssembler, r io.Reader) error {
+		b, err := ioutil.ReadAll(io.LimitReader(r, int64(opts.MaxScalarLength)+1))
+		if err != nil {
+			return err
+		}
+		if err := opts.CheckBytes(len(b)); err != nil {
+			return err
+		}
+		return na.AssignBytes(b)
+	}
+}
+
+// Encoder writes the content of a bytes-kind node.
+// (If the node supports ipld.NodeSupportingBytesStream, it's streamed
+// rather than copied out with AsBytes.)
+// Nodes of any other kind are rejected with ipld.ErrWrongKind.
+func Encoder(n ipld.Node, w io.Writer) error {
+	r, err := ipld.AsBytesStream(n)
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(w, r)
+	return err
+}
@@ -0,0 +1,59 @@
+package raw
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"strings"
+	"testing"
+
+	cid "github.com/ipfs/go-cid"
+	. "github.com/warpfork/go-wish"
+
+	ipld "github.com/ipld/go-ipld-prime"
+	"github.com/ipld/go-ipld-prime/codec"
+	cidlink "github.com/ipld/go-ipld-prime/linking/cid"
+	basicnode "github.com/ipld/go-ipld-prime/node/basic"
+)
+
+func TestRoundtripCidlink(t *testing.T) {
+	lb := cidlink.LinkBuilder{cid.Prefix{
+		Version:  1,
+		Codec:    0x55,
+		MhType:   0x17,
+		MhLength: 4,
+	}}
+	n := basicnode.NewBytes([]byte("some bytes"))
+
+	buf := bytes.Buffer{}
+	lnk, err := lb.Build(context.Background(), ipld.LinkContext{}, n,
+		func(ipld.LinkContext) (io.Writer, ipld.StoreCommitter, error) {
+			return &buf, func(lnk ipld.Link) error { return nil }, nil
+		},
+	)
+	Require(t, err, ShouldEqual, nil)
+	Wish(t, buf.String(), ShouldEqual, "some bytes")
+
+	nb := basicnode.Style__Any{}.NewBuilder()
+	err = lnk.Load(context.Background(), ipld.LinkContext{}, nb,
+		func(lnk ipld.Link, _ ipld.LinkContext) (io.Reader, error) {
+			return bytes.NewBuffer(buf.Bytes()), nil
+		},
+	)
+	Require(t, err, ShouldEqual, nil)
+	Wish(t, nb.Build(), ShouldEqual, n)
+}
+
+func TestEncoderRejectsNonBytes(t *testing.T) {
+	err := Encoder(basicnode.NewString("nope"), &bytes.Buffer{})
+	Wish(t, err, ShouldBeSameTypeAs, ipld.ErrWrongKind{})
+}
+
+func TestDecodeBudget(t *testing.T) {
+	dec := DecoderWithOptions(codec.DecodeOptions{MaxScalarLength: 4})
+	nb := basicnode.Style__Bytes{}.NewBuilder()
+	Wish(t, dec(nb, strings.NewReader("abcd")), ShouldEqual, nil)
+	Wish(t, nb.Build(), ShouldEqual, basicnode.NewBytes([]byte("abcd")))
+	nb.Reset()
+	Wish(t, dec(nb, strings.NewReader("abcdefgh")), ShouldEqual, ipld.ErrBudgetExceeded{Kind: ipld.ReprKind_Bytes, Length: 5, Budget: 4})
+}
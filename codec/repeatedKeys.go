@@ -0,0 +1,142 @@
+package codec
+
+import (
+	"github.com/polydawn/refmt/shared"
+	"github.com/polydawn/refmt/tok"
+)
+
+// TokenSource returns the TokenSource a decoder should read from, given the one it was handed.
+// Unless RepeatedKeysLastWins is set, that's tokSrc itself;
+// if it is set, tokSrc is wrapped so that repeated map keys are dropped
+// before the NodeAssembler ever sees them, and only the last value for each key remains
+// (in the place where the key first appeared).
+//
+// Decoders that recurse through their own entry point may call this again
+// on a TokenSource it returned; it won't be wrapped twice.
+func (opts DecodeOptions) TokenSource(tokSrc shared.TokenSource) shared.TokenSource {
+	if !opts.RepeatedKeysLastWins {
+		return tokSrc
+	}
+	if _, ok := tokSrc.(*lastWinsTokenSource); ok {
+		return tokSrc
+	}
+	return &lastWinsTokenSource{src: tokSrc}
+}
+
+// lastWinsTokenSource reads ahead through each map in the token stream,
+// and yields it again with the entries for repeated keys removed.
+// Scalars outside of any map pass straight through, but once a map is open,
+// the whole value containing it is buffered, since any entry could turn out to be repeated later on.
+type lastWinsTokenSource struct {
+	src shared.TokenSource
+	buf []tokenStep // tokens read ahead and deduplicated, waiting to be yielded.
+	pos int
+}
+
+type tokenStep struct {
+	tk   tok.Token
+	done bool
+}
+
+func (ts *lastWinsTokenSource) Step(fillme *tok.Token) (bool, error) {
+	if ts.pos < len(ts.buf) {
+		st := ts.buf[ts.pos]
+		ts.pos++
+		*fillme = st.tk
+		return st.done, nil
+	}
+	ts.buf, ts.pos = ts.buf[:0], 0
+	st, err := ts.step()
+	if err != nil {
+		return st.done, err
+	}
+	if st.tk.Type != tok.TMapOpen && st.tk.Type != tok.TArrOpen {
+		*fillme = st.tk
+		return st.done, nil
+	}
+	if ts.buf, err = ts.readValue(st, ts.buf); err != nil {
+		return false, err
+	}
+	return ts.Step(fillme)
+}
+
+// step reads one token from the underlying source.
+// Bytes are copied, since the source may reuse its buffer for them.
+func (ts *lastWinsTokenSource) step() (tokenStep, error) {
+	var st tokenStep
+	var err error
+	st.done, err = ts.src.Step(&st.tk)
+	if st.tk.Type == tok.TBytes {
+		st.tk.Bytes = append([]byte(nil), st.tk.Bytes...)
+	}
+	return st, err
+}
+
+// readValue appends the value which starts with the token 'first' to 'out',
+// deduplicating any maps within it.
+func (ts *lastWinsTokenSource) readValue(first tokenStep, out []tokenStep) ([]tokenStep, error) {
+	switch first.tk.Type {
+	case tok.TMapOpen:
+		return ts.readMap(first, out)
+	case tok.TArrOpen:
+		out = append(out, first)
+		for {
+			st, err := ts.step()
+			if err != nil {
+				return out, err
+			}
+			if st.tk.Type == tok.TArrClose {
+				return append(out, st), nil
+			}
+			if out, err = ts.readValue(st, out); err != nil {
+				return out, err
+			}
+		}
+	default:
+		return append(out, first), nil
+	}
+}
+
+func (ts *lastWinsTokenSource) readMap(open tokenStep, out []tokenStep) ([]tokenStep, error) {
+	type entry struct {
+		key tokenStep
+		val []tokenStep
+	}
+	var entries []entry
+	seen := make(map[string]int)
+	for {
+		key, err := ts.step()
+		if err != nil {
+			return out, err
+		}
+		if key.tk.Type == tok.TMapClose {
+			if open.tk.Length >= 0 {
+				open.tk.Length = len(entries)
+			}
+			out = append(out, open)
+			for _, e := range entries {
+				out = append(out, e.key)
+				out = append(out, e.val...)
+			}
+			return append(out, key), nil
+		}
+		// Anything but a string key is left for the decoder to reject.
+		// Values can't be read into 'out' directly, since a later repeat would have to remove them again.
+		first, err := ts.step()
+		if err != nil {
+			return out, err
+		}
+		val, err := ts.readValue(first, nil)
+		if err != nil {
+			return out, err
+		}
+		if key.tk.Type == tok.TString {
+			if i, exists := seen[key.tk.Str]; exists {
+				entries[i].val = val
+				continue
+			}
+			seen[key.tk.Str] = len(entries)
+		}
+		entries = append(entries, entry{key, val})
+	}
+}
@@ -3,6 +3,8 @@ package codec
 import (
 	"fmt"
 	"math"
+	"strconv"
+	"unicode/utf8"
 
 	"github.com/polydawn/refmt/shared"
 	"github.com/polydawn/refmt/tok"
@@ -37,6 +39,101 @@ import (
 // (The dag-cbor and dag-json formats can be used if links are of CID
 // implementation and need to be decoded in a schemafree way.)
 func Unmarshal(na ipld.NodeAssembler, tokSrc shared.TokenSource) error {
+	return UnmarshalWithOptions(na, tokSrc, DecodeOptions{})
+}
+
+// DecodeOptions holds limits for decoding untrusted data,
+// and settings for how strictly to parse it.
+// The zero value means no limits, and strict parsing.
+//
+// The codec packages (dagcbor, dagjson, dagpb, raw) each accept DecodeOptions too,
+// and apply them the same way.
+type DecodeOptions struct {
+	// MaxScalarLength, if positive, is the longest string or bytes value
+	// (including map keys) that will be accepted.
+	// Longer values are rejected with ipld.ErrBudgetExceeded,
+	// before they're given to the NodeAssembler, so they're never retained in the result.
+	//
+	// The codecs check as they read, so an oversized value isn't read into memory either:
+	// dag-cbor checks the length in each value's head, before reading the value;
+	// dag-json counts the length of each string as it goes, and stops reading once it's over;
+	// raw reads at most one byte past the limit.
+	// (dag-pb reads the whole block first, as it always does, and checks each value in it.)
+	// UnmarshalWithOptions itself, given just a TokenSource, can only check each token it's given,
+	// which the tokenizer has already read in full.
+	//
+	// To limit values assembled by other means, such as ipld.Copy,
+	// see the MaxScalarLength field of basicnode.Style__Map (and of the other basicnode styles).
+	MaxScalarLength int
+
+	// RepeatedKeysLastWins, if true, accepts maps which repeat a key,
+	// keeping only the last value for it (in the place where the key first appeared).
+	// Otherwise, a repeated key is rejected with ipld.ErrRepeatedMapKey
+	// (which the NodeAssembler returns; all the map assemblers in this project check for repeats).
+	//
+	// To find repeats, each map is read in full before any of it is assembled,
+	// so this costs memory in proportion to the data; see DecodeOptions.TokenSource.
+	RepeatedKeysLastWins bool
+
+	// StrictUTF8, if true, rejects strings (including map keys) which aren't valid UTF-8,
+	// with ipld.ErrInvalidUTF8, before they're given to the NodeAssembler.
+	// Otherwise, strings are passed on as they are: DAG-CBOR in particular
+	// is sometimes used to carry strings which aren't UTF-8, so this is off by default.
+	StrictUTF8 bool
+}
+
+// CheckString returns ipld.ErrBudgetExceeded if a string of length n is over budget.
+func (opts DecodeOptions) CheckString(n int) error {
+	return opts.check(ipld.ReprKind_String, n)
+}
+
+// CheckBytes returns ipld.ErrBudgetExceeded if a bytes value of length n is over budget.
+func (opts DecodeOptions) CheckBytes(n int) error {
+	return opts.check(ipld.ReprKind_Bytes, n)
+}
+
+// CheckUTF8 returns ipld.ErrInvalidUTF8 if StrictUTF8 is set and s isn't valid UTF-8.
+func (opts DecodeOptions) CheckUTF8(s string) error {
+	if !opts.StrictUTF8 || utf8.ValidString(s) {
+		return nil
+	}
+	for i := 0; i < len(s); {
+		r, size := utf8.DecodeRuneInString(s[i:])
+		if r == utf8.RuneError && size == 1 {
+			return ipld.ErrInvalidUTF8{Offset: i}
+		}
+		i += size
+	}
+	return nil
+}
+
+func (opts DecodeOptions) check(k ipld.ReprKind, n int) error {
+	if opts.MaxScalarLength > 0 && n > opts.MaxScalarLength {
+		return ipld.ErrBudgetExceeded{Kind: k, Length: n, Budget: opts.MaxScalarLength}
+	}
+	return nil
+}
+
+const maxInt = int(^uint(0) >> 1)
+
+// TokenInt returns the value of an integer token (TInt or TUint) as an int,
+// or ipld.ErrIntegerOverflow if it's out of the range of int.
+func TokenInt(tk *tok.Token) (int, error) {
+	if tk.Type == tok.TUint {
+		if tk.Uint > uint64(maxInt) {
+			return 0, ipld.ErrIntegerOverflow{Value: strconv.FormatUint(tk.Uint, 10)}
+		}
+		return int(tk.Uint), nil
+	}
+	if int64(int(tk.Int)) != tk.Int {
+		return 0, ipld.ErrIntegerOverflow{Value: strconv.FormatInt(tk.Int, 10)}
+	}
+	return int(tk.Int), nil
+}
+
+// UnmarshalWithOptions is Unmarshal, with limits and strictness as configured by DecodeOptions.
+func UnmarshalWithOptions(na ipld.NodeAssembler, tokSrc shared.TokenSource, opts DecodeOptions) error {
+	tokSrc = opts.TokenSource(tokSrc)
 	var tk tok.Token
 	done, err := tokSrc.Step(&tk)
 	if err != nil {
@@ -45,12 +142,12 @@ func Unmarshal(na ipld.NodeAssembler, tokSrc shared.TokenSource) error {
 	if done && !tk.Type.IsValue() {
 		return fmt.Errorf("unexpected eof")
 	}
-	return unmarshal(na, tokSrc, &tk)
+	return unmarshal(na, tokSrc, &tk, opts)
 }
 
 // starts with the first token already primed.  Necessary to get recursion
 //  to flow right without a peek+unpeek system.
-func unmarshal(na ipld.NodeAssembler, tokSrc shared.TokenSource, tk *tok.Token) error {
+func unmarshal(na ipld.NodeAssembler, tokSrc shared.TokenSource, tk *tok.Token, opts DecodeOptions) error {
 	// FUTURE: check for schema.TypedNodeBuilder that's going to parse a Link (they can slurp any token kind they want).
 	switch tk.Type {
 	case tok.TMapOpen:
@@ -85,11 +182,17 @@ func unmarshal(na ipld.NodeAssembler, tokSrc shared.TokenSource, tk *tok.Token)
 			if observedLen > expectLen {
 				return fmt.Errorf("unexpected continuation of map elements beyond declared length")
 			}
+			if err := opts.CheckString(len(tk.Str)); err != nil {
+				return err
+			}
+			if err := opts.CheckUTF8(tk.Str); err != nil {
+				return err
+			}
 			mva, err := ma.AssembleEntry(tk.Str)
 			if err != nil { // return in error if the key was rejected
 				return err
 			}
-			err = Unmarshal(mva, tokSrc)
+			err = UnmarshalWithOptions(mva, tokSrc, opts)
 			if err != nil { // return in error if some part of the recursion errored
 				return err
 			}
@@ -97,13 +200,13 @@ func unmarshal(na ipld.NodeAssembler, tokSrc shared.TokenSource, tk *tok.Token)
 	case tok.TMapClose:
 		return fmt.Errorf("unexpected mapClose token")
 	case tok.TArrOpen:
+		// An indefinite length is passed on to BeginList as -1, rather than
+		//  as a zero-length hint, so the assembler knows not to treat it as a bound.
 		expectLen := tk.Length
-		allocLen := tk.Length
 		if tk.Length == -1 {
 			expectLen = math.MaxInt32
-			allocLen = 0
 		}
-		la, err := na.BeginList(allocLen)
+		la, err := na.BeginList(tk.Length)
 		if err != nil {
 			return err
 		}
@@ -124,7 +227,7 @@ func unmarshal(na ipld.NodeAssembler, tokSrc shared.TokenSource, tk *tok.Token)
 				if observedLen > expectLen {
 					return fmt.Errorf("unexpected continuation of array elements beyond declared length")
 				}
-				err := unmarshal(la.AssembleValue(), tokSrc, tk)
+				err := unmarshal(la.AssembleValue(), tokSrc, tk, opts)
 				if err != nil { // return in error if some part of the recursion errored
 					return err
 				}
@@ -135,15 +238,26 @@ func unmarshal(na ipld.NodeAssembler, tokSrc shared.TokenSource, tk *tok.Token)
 	case tok.TNull:
 		return na.AssignNull()
 	case tok.TString:
+		if err := opts.CheckString(len(tk.Str)); err != nil {
+			return err
+		}
+		if err := opts.CheckUTF8(tk.Str); err != nil {
+			return err
+		}
 		return na.AssignString(tk.Str)
 	case tok.TBytes:
+		if err := opts.CheckBytes(len(tk.Bytes)); err != nil {
+			return err
+		}
 		return na.AssignBytes(tk.Bytes)
 	case tok.TBool:
 		return na.AssignBool(tk.Bool)
-	case tok.TInt:
-		return na.AssignInt(int(tk.Int)) // FIXME overflow check
-	case tok.TUint:
-		return na.AssignInt(int(tk.Uint)) // FIXME overflow check
+	case tok.TInt, tok.TUint:
+		i, err := TokenInt(tk)
+		if err != nil {
+			return err
+		}
+		return na.AssignInt(i)
 	case tok.TFloat64:
 		return na.AssignFloat(tk.Float64)
 	default:
@@ -0,0 +1,119 @@
+// Package coerce provides lenient conversions from Nodes to golang scalars,
+// for interop code dealing with formats or producers that don't use the
+// Data Model kinds you'd hope for (e.g. booleans written as 0 and 1).
+//
+// The methods on ipld.Node (AsBool, AsInt, etc) are strict: they only work
+// on nodes of exactly the matching kind, and that won't change.
+// The functions in this package accept a few more representations,
+// which are listed precisely on each function; anything not listed is rejected.
+// Nodes of a kind that isn't accepted at all are rejected with ipld.ErrWrongKind;
+// nodes of an accepted kind but with a value that can't be converted
+// are rejected with ErrCannotCoerce.
+package coerce
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+
+	ipld "github.com/ipld/go-ipld-prime"
+)
+
+// ErrCannotCoerce is returned when a node is of a kind that a coercion accepts,
+// but its value doesn't convert (e.g. the int 2 can't be a bool).
+type ErrCannotCoerce struct {
+	To    ipld.ReprKind
+	Value ipld.Node
+}
+
+func (e ErrCannotCoerce) Error() string {
+	return fmt.Sprintf("cannot coerce %s to %s", ipld.Sprint(e.Value), e.To)
+}
+
+// AsBool returns the boolean value of a node, accepting:
+//
+//   - a bool: its value;
+//   - an int: 0 is false, 1 is true;
+//   - a string: exactly "false" or "true" (lowercase, no whitespace).
+//
+// Other int and string values are rejected with ErrCannotCoerce,
+// and nodes of any other kind (including null) with ipld.ErrWrongKind.
+func AsBool(n ipld.Node) (bool, error) {
+	if n.IsUndefined() {
+		return false, wrongKind("coerce.AsBool", ipld.ReprKindSet{ipld.ReprKind_Bool, ipld.ReprKind_Int, ipld.ReprKind_String}, n)
+	}
+	switch n.ReprKind() {
+	case ipld.ReprKind_Bool:
+		return n.AsBool()
+	case ipld.ReprKind_Int:
+		v, err := n.AsInt()
+		if err != nil {
+			return false, err
+		}
+		switch v {
+		case 0:
+			return false, nil
+		case 1:
+			return true, nil
+		}
+	case ipld.ReprKind_String:
+		v, err := n.AsString()
+		if err != nil {
+			return false, err
+		}
+		switch v {
+		case "false":
+			return false, nil
+		case "true":
+			return true, nil
+		}
+	default:
+		return false, wrongKind("coerce.AsBool", ipld.ReprKindSet{ipld.ReprKind_Bool, ipld.ReprKind_Int, ipld.ReprKind_String}, n)
+	}
+	return false, ErrCannotCoerce{ipld.ReprKind_Bool, n}
+}
+
+// AsInt returns the integer value of a node, accepting:
+//
+//   - an int: its value;
+//   - a float: if it has no fractional part and is within the range of int;
+//   - a string: base-10 digits with an optional leading '+' or '-' sign
+//     (as strconv.Atoi parses; no whitespace, no other bases), within the range of int.
+//
+// Other float and string values are rejected with ErrCannotCoerce,
+// and nodes of any other kind (including bool and null) with ipld.ErrWrongKind.
+func AsInt(n ipld.Node) (int, error) {
+	if n.IsUndefined() {
+		return 0, wrongKind("coerce.AsInt", ipld.ReprKindSet{ipld.ReprKind_Int, ipld.ReprKind_Float, ipld.ReprKind_String}, n)
+	}
+	switch n.ReprKind() {
+	case ipld.ReprKind_Int:
+		return n.AsInt()
+	case ipld.ReprKind_Float:
+		v, err := n.AsFloat()
+		if err != nil {
+			return 0, err
+		}
+		// -minInt is a power of two, so it (unlike maxInt) is exact as a float64.
+		if v == math.Trunc(v) && v >= float64(minInt) && v < -float64(minInt) {
+			return int(v), nil
+		}
+	case ipld.ReprKind_String:
+		v, err := n.AsString()
+		if err != nil {
+			return 0, err
+		}
+		if i, err := strconv.Atoi(v); err == nil {
+			return i, nil
+		}
+	default:
+		return 0, wrongKind("coerce.AsInt", ipld.ReprKindSet{ipld.ReprKind_Int, ipld.ReprKind_Float, ipld.ReprKind_String}, n)
+	}
+	return 0, ErrCannotCoerce{ipld.ReprKind_Int, n}
+}
+
+const minInt = -int(^uint(0)>>1) - 1
+
+func wrongKind(methodName string, appropriateKind ipld.ReprKindSet, n ipld.Node) error {
+	return ipld.ErrWrongKind{MethodName: methodName, AppropriateKind: appropriateKind, ActualKind: n.ReprKind()}
+}
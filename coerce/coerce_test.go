@@ -0,0 +1,92 @@
+package coerce
+
+import (
+	"math"
+	"testing"
+
+	. "github.com/warpfork/go-wish"
+
+	ipld "github.com/ipld/go-ipld-prime"
+	basicnode "github.com/ipld/go-ipld-prime/node/basic"
+)
+
+func TestAsBool(t *testing.T) {
+	for _, tc := range []struct {
+		n    ipld.Node
+		want bool
+	}{
+		{basicnode.NewBool(true), true},
+		{basicnode.NewBool(false), false},
+		{basicnode.NewInt(1), true},
+		{basicnode.NewInt(0), false},
+		{basicnode.NewString("true"), true},
+		{basicnode.NewString("false"), false},
+	} {
+		v, err := AsBool(tc.n)
+		Wish(t, err, ShouldEqual, nil)
+		Wish(t, v, ShouldEqual, tc.want)
+	}
+	for _, n := range []ipld.Node{
+		basicnode.NewInt(2),
+		basicnode.NewInt(-1),
+		basicnode.NewString("True"),
+		basicnode.NewString("1"),
+		basicnode.NewString(" true"),
+		basicnode.NewString(""),
+	} {
+		_, err := AsBool(n)
+		Wish(t, err, ShouldEqual, ErrCannotCoerce{ipld.ReprKind_Bool, n})
+	}
+	for _, n := range []ipld.Node{
+		ipld.Null,
+		basicnode.NewFloat(1),
+		basicnode.NewBytes([]byte{1}),
+	} {
+		_, err := AsBool(n)
+		Wish(t, err, ShouldEqual, ipld.ErrWrongKind{MethodName: "coerce.AsBool", AppropriateKind: ipld.ReprKindSet{ipld.ReprKind_Bool, ipld.ReprKind_Int, ipld.ReprKind_String}, ActualKind: n.ReprKind()})
+	}
+	_, err := AsBool(ipld.Undef)
+	Wish(t, err, ShouldEqual, ipld.ErrWrongKind{MethodName: "coerce.AsBool", AppropriateKind: ipld.ReprKindSet{ipld.ReprKind_Bool, ipld.ReprKind_Int, ipld.ReprKind_String}, ActualKind: ipld.ReprKind_Null})
+}
+
+func TestAsInt(t *testing.T) {
+	for _, tc := range []struct {
+		n    ipld.Node
+		want int
+	}{
+		{basicnode.NewInt(-7), -7},
+		{basicnode.NewFloat(3), 3},
+		{basicnode.NewFloat(-2), -2},
+		{basicnode.NewString("42"), 42},
+		{basicnode.NewString("-42"), -42},
+		{basicnode.NewString("+42"), 42},
+	} {
+		v, err := AsInt(tc.n)
+		Wish(t, err, ShouldEqual, nil)
+		Wish(t, v, ShouldEqual, tc.want)
+	}
+	for _, n := range []ipld.Node{
+		basicnode.NewFloat(1.5),
+		basicnode.NewFloat(math.Ldexp(1, 64)),
+		basicnode.NewString("4.2"),
+		basicnode.NewString("0x10"),
+		basicnode.NewString(" 1"),
+		basicnode.NewString("99999999999999999999"),
+		basicnode.NewString(""),
+	} {
+		_, err := AsInt(n)
+		Wish(t, err, ShouldEqual, ErrCannotCoerce{ipld.ReprKind_Int, n})
+	}
+	for _, n := range []ipld.Node{
+		ipld.Null,
+		basicnode.NewBool(true),
+	} {
+		_, err := AsInt(n)
+		Wish(t, err, ShouldEqual, ipld.ErrWrongKind{MethodName: "coerce.AsInt", AppropriateKind: ipld.ReprKindSet{ipld.ReprKind_Int, ipld.ReprKind_Float, ipld.ReprKind_String}, ActualKind: n.ReprKind()})
+	}
+}
+
+func TestErrCannotCoerce(t *testing.T) {
+	_, err := AsBool(basicnode.NewString("yes"))
+	Wish(t, err.Error(), ShouldEqual, `cannot coerce "yes" to Bool`)
+}
@@ -0,0 +1,312 @@
+package ipld
+
+import (
+	"bytes"
+	"math"
+	"reflect"
+	"strings"
+)
+
+// kindOrder is the rank of each kind in the order used by Compare.
+var kindOrder = map[ReprKind]int{
+	ReprKind_Invalid: 0,
+	ReprKind_Null:    1,
+	ReprKind_Bool:    2,
+	ReprKind_Int:     3,
+	ReprKind_Float:   4,
+	ReprKind_String:  5,
+	ReprKind_Bytes:   6,
+	ReprKind_Link:    7,
+	ReprKind_List:    8,
+	ReprKind_Map:     9,
+}
+
+// Compare defines a total order over nodes, returning -1 if a sorts before b,
+// 1 if a sorts after b, and 0 if they're equal.
+// It's meant for sorting (e.g. to get deterministic output), and for equality checks.
+//
+// Nodes of different kinds are ordered by kind, in this order:
+//
+//	undefined < null < bool < int < float < string < bytes < link < list < map
+//
+// (Note that an int and a float never compare equal, even if they're numerically the same.)
+// Within a kind:
+//
+//   - false sorts before true;
+//   - ints and floats sort numerically, with NaN before all other floats
+//     (and equal to itself), and -0 equal to +0;
+//   - strings and bytes sort bytewise, with a prefix before anything longer;
+//   - links sort by their String form, since that's all the Link interface offers
+//     to compare links of any implementation by; for CIDs, the String form
+//     is canonical, so two CID links are equal exactly when their CIDs are.
+//     (traversal.Diff compares links by this rule too.)
+//   - lists sort lexicographically by their elements, with a prefix first;
+//   - maps sort lexicographically by their entries (comparing each key, then
+//     each value), in iteration order, with a prefix first.
+//
+// Because maps are compared in iteration order, two maps with the same entries
+// in different orders are not equal; sort them (e.g. with a sorted-map style) first,
+// if that matters.  Typed nodes are compared by their Data Model content alone.
+//
+// Nodes are immutable, so a node (or subtree) which is pointer-equal to the
+// one it's being compared to is equal to it, and isn't walked.
+//
+// Compare has no error return, so that it can be used directly in sorting.
+// If reading a node returns an error (which well-behaved nodes don't do,
+// since Compare only reads each node as the kind it reports; but a node which
+// loads or decodes its content lazily might), the node which failed sorts first:
+// see CompareErr, which Compare is built on, for the details and the error itself.
+func Compare(a, b Node) int {
+	c, _ := CompareErr(a, b)
+	return c
+}
+
+// CompareErr is Compare, but also returns the first error from reading either node.
+// When there's an error, the comparison stops there, and the result is -1 if
+// it was reading a which failed, or 1 if it was b; so nodes which can't be
+// read never compare equal to anything, and the result is still consistent
+// for a given pair of nodes which fail the same way each time.
+func CompareErr(a, b Node) (int, error) {
+	if samePointer(a, b) {
+		return 0, nil
+	}
+	ka, kb := compareKind(a), compareKind(b)
+	if ka != kb {
+		return compareInts(kindOrder[ka], kindOrder[kb]), nil
+	}
+	switch ka {
+	case ReprKind_Invalid, ReprKind_Null:
+		return 0, nil
+	case ReprKind_Bool:
+		va, err := a.AsBool()
+		if err != nil {
+			return -1, err
+		}
+		vb, err := b.AsBool()
+		if err != nil {
+			return 1, err
+		}
+		switch {
+		case va == vb:
+			return 0, nil
+		case !va:
+			return -1, nil
+		default:
+			return 1, nil
+		}
+	case ReprKind_Int:
+		va, err := a.AsInt()
+		if err != nil {
+			return -1, err
+		}
+		vb, err := b.AsInt()
+		if err != nil {
+			return 1, err
+		}
+		return compareInts(va, vb), nil
+	case ReprKind_Float:
+		va, err := a.AsFloat()
+		if err != nil {
+			return -1, err
+		}
+		vb, err := b.AsFloat()
+		if err != nil {
+			return 1, err
+		}
+		return compareFloats(va, vb), nil
+	case ReprKind_String:
+		va, err := a.AsString()
+		if err != nil {
+			return -1, err
+		}
+		vb, err := b.AsString()
+		if err != nil {
+			return 1, err
+		}
+		return strings.Compare(va, vb), nil
+	case ReprKind_Bytes:
+		va, err := a.AsBytes()
+		if err != nil {
+			return -1, err
+		}
+		vb, err := b.AsBytes()
+		if err != nil {
+			return 1, err
+		}
+		return bytes.Compare(va, vb), nil
+	case ReprKind_Link:
+		va, err := a.AsLink()
+		if err != nil {
+			return -1, err
+		}
+		vb, err := b.AsLink()
+		if err != nil {
+			return 1, err
+		}
+		return strings.Compare(va.String(), vb.String()), nil
+	case ReprKind_List:
+		ia, ib := a.ListIterator(), b.ListIterator()
+		for !ia.Done() && !ib.Done() {
+			_, va, err := ia.Next()
+			if err != nil {
+				return -1, err
+			}
+			_, vb, err := ib.Next()
+			if err != nil {
+				return 1, err
+			}
+			if c, err := CompareErr(va, vb); c != 0 || err != nil {
+				return c, err
+			}
+		}
+		return compareDone(ia.Done(), ib.Done()), nil
+	case ReprKind_Map:
+		ia, ib := a.MapIterator(), b.MapIterator()
+		for !ia.Done() && !ib.Done() {
+			ka, va, err := ia.Next()
+			if err != nil {
+				return -1, err
+			}
+			kb, vb, err := ib.Next()
+			if err != nil {
+				return 1, err
+			}
+			if c, err := CompareErr(ka, kb); c != 0 || err != nil {
+				return c, err
+			}
+			if c, err := CompareErr(va, vb); c != 0 || err != nil {
+				return c, err
+			}
+		}
+		return compareDone(ia.Done(), ib.Done()), nil
+	default:
+		panic("unreachable")
+	}
+}
+
+// DeepEqual reports whether two nodes have the same content;
+// that is, whether Compare would return 0 for them.
+//
+// It's cheaper than Compare when the answer is no: maps and lists of
+// different lengths are unequal without looking at their contents.
+// And as with Compare, pointer-equal nodes are equal without being walked,
+// which makes comparing a tree with a copy-on-write update of itself
+// (e.g. from traversal.FocusedTransform) cost only as much as the path that changed.
+//
+// Like Compare, DeepEqual is built on CompareErr: nodes which return an error
+// when read are not equal to anything.
+func DeepEqual(a, b Node) bool {
+	eq, err := deepEqual(a, b)
+	return eq && err == nil
+}
+
+func deepEqual(a, b Node) (bool, error) {
+	if samePointer(a, b) {
+		return true, nil
+	}
+	ka, kb := compareKind(a), compareKind(b)
+	if ka != kb {
+		return false, nil
+	}
+	switch ka {
+	case ReprKind_List:
+		if a.Length() != b.Length() {
+			return false, nil
+		}
+		for ia, ib := a.ListIterator(), b.ListIterator(); !ia.Done() && !ib.Done(); {
+			_, va, err := ia.Next()
+			if err != nil {
+				return false, err
+			}
+			_, vb, err := ib.Next()
+			if err != nil {
+				return false, err
+			}
+			if eq, err := deepEqual(va, vb); !eq || err != nil {
+				return false, err
+			}
+		}
+		return true, nil
+	case ReprKind_Map:
+		if a.Length() != b.Length() {
+			return false, nil
+		}
+		for ia, ib := a.MapIterator(), b.MapIterator(); !ia.Done() && !ib.Done(); {
+			ka, va, err := ia.Next()
+			if err != nil {
+				return false, err
+			}
+			kb, vb, err := ib.Next()
+			if err != nil {
+				return false, err
+			}
+			if eq, err := deepEqual(ka, kb); !eq || err != nil {
+				return false, err
+			}
+			if eq, err := deepEqual(va, vb); !eq || err != nil {
+				return false, err
+			}
+		}
+		return true, nil
+	default:
+		c, err := CompareErr(a, b)
+		return c == 0, err
+	}
+}
+
+// samePointer reports whether a and b are the very same node: pointers, of the same type, to the same place.
+// (Nodes of non-pointer types are never reported as the same; but those are generally scalars, which are cheap to compare anyway.)
+func samePointer(a, b Node) bool {
+	va, vb := reflect.ValueOf(a), reflect.ValueOf(b)
+	return va.Kind() == reflect.Ptr && va.Type() == vb.Type() && va.Pointer() == vb.Pointer()
+}
+
+// compareKind is the kind a node is ordered by: its ReprKind,
+// except that undefined nodes are always ReprKind_Invalid.
+func compareKind(n Node) ReprKind {
+	if n.IsUndefined() {
+		return ReprKind_Invalid
+	}
+	return n.ReprKind()
+}
+
+func compareInts(a, b int) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	default:
+		return 0
+	}
+}
+
+func compareFloats(a, b float64) int {
+	switch aNaN, bNaN := math.IsNaN(a), math.IsNaN(b); {
+	case aNaN && bNaN:
+		return 0
+	case aNaN:
+		return -1
+	case bNaN:
+		return 1
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// compareDone orders two iterators which agreed on every step they both had:
+// the one which finished first (the prefix) sorts first.
+func compareDone(aDone, bDone bool) int {
+	switch {
+	case aDone && bDone:
+		return 0
+	case aDone:
+		return -1
+	default:
+		return 1
+	}
+}
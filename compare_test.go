@@ -0,0 +1,225 @@
+package ipld_test
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"testing"
+
+	cid "github.com/ipfs/go-cid"
+	. "github.com/warpfork/go-wish"
+
+	ipld "github.com/ipld/go-ipld-prime"
+	"github.com/ipld/go-ipld-prime/fluent"
+	cidlink "github.com/ipld/go-ipld-prime/linking/cid"
+	basicnode "github.com/ipld/go-ipld-prime/node/basic"
+	"github.com/ipld/go-ipld-prime/traversal"
+)
+
+func TestCompare(t *testing.T) {
+	list := func(vs ...int) ipld.Node {
+		return fluent.MustBuildList(basicnode.Style__List{}, len(vs), func(na fluent.ListAssembler) {
+			for _, v := range vs {
+				na.AssembleValue().AssignInt(v)
+			}
+		})
+	}
+	mapOf := func(kvs ...interface{}) ipld.Node {
+		return fluent.MustBuildMap(basicnode.Style__Map{}, len(kvs)/2, func(na fluent.MapAssembler) {
+			for i := 0; i < len(kvs); i += 2 {
+				na.AssembleEntry(kvs[i].(string)).AssignInt(kvs[i+1].(int))
+			}
+		})
+	}
+	// Already in order; each sorts strictly before the next.
+	ordered := []ipld.Node{
+		ipld.Undef,
+		ipld.Null,
+		basicnode.NewBool(false),
+		basicnode.NewBool(true),
+		basicnode.NewInt(-3),
+		basicnode.NewInt(0),
+		basicnode.NewInt(7),
+		basicnode.NewFloat(math.NaN()),
+		basicnode.NewFloat(math.Inf(-1)),
+		basicnode.NewFloat(-0.5),
+		basicnode.NewFloat(2),
+		basicnode.NewString(""),
+		basicnode.NewString("a"),
+		basicnode.NewString("ab"),
+		basicnode.NewString("b"),
+		basicnode.NewBytes([]byte{}),
+		basicnode.NewBytes([]byte{0x00}),
+		basicnode.NewBytes([]byte{0x01}),
+		list(),
+		list(1),
+		list(1, 2),
+		list(2),
+		mapOf(),
+		mapOf("a", 1),
+		mapOf("a", 1, "b", 0),
+		mapOf("a", 2),
+		mapOf("b", 0),
+	}
+	t.Run("each pair compares as expected", func(t *testing.T) {
+		for i, a := range ordered {
+			for j, b := range ordered {
+				want := 0
+				switch {
+				case i < j:
+					want = -1
+				case i > j:
+					want = 1
+				}
+				if got := ipld.Compare(a, b); got != want {
+					t.Errorf("Compare(%s, %s) = %d, want %d", ipld.Sprint(a), ipld.Sprint(b), got, want)
+				}
+			}
+		}
+	})
+	t.Run("sorting a shuffled mixed slice", func(t *testing.T) {
+		mixed := make([]ipld.Node, len(ordered))
+		for i := range ordered {
+			mixed[i] = ordered[(i*7)%len(ordered)] // 7 is coprime with the length, so this is a permutation.
+		}
+		sort.SliceStable(mixed, func(i, j int) bool { return ipld.Compare(mixed[i], mixed[j]) < 0 })
+		render := func(ns []ipld.Node) string {
+			ss := make([]string, len(ns))
+			for i, n := range ns {
+				ss[i] = ipld.Sprint(n)
+			}
+			return strings.Join(ss, " ")
+		}
+		Wish(t, render(mixed), ShouldEqual, render(ordered))
+	})
+	t.Run("equal values", func(t *testing.T) {
+		Wish(t, ipld.Compare(basicnode.NewFloat(0), basicnode.NewFloat(math.Copysign(0, -1))), ShouldEqual, 0)
+		Wish(t, ipld.Compare(mapOf("a", 1, "b", 2), mapOf("a", 1, "b", 2)), ShouldEqual, 0)
+		Wish(t, ipld.Compare(basicnode.NewInt(1), basicnode.NewFloat(1)), ShouldEqual, -1)
+	})
+}
+
+func TestCompareLinks(t *testing.T) {
+	link := func(codec uint64, data string) ipld.Node {
+		c, err := cid.Prefix{Version: 1, Codec: codec, MhType: 0x12, MhLength: -1}.Sum([]byte(data))
+		Require(t, err, ShouldEqual, nil)
+		return basicnode.NewLink(cidlink.Link{c})
+	}
+	a := link(0x71, "x")
+	for _, tc := range []struct {
+		b     ipld.Node
+		equal bool
+	}{
+		{link(0x71, "x"), true},  // the same CID, built separately.
+		{link(0x55, "x"), false}, // the same hash, with another codec.
+		{link(0x71, "y"), false},
+	} {
+		Wish(t, ipld.Compare(a, tc.b) == 0, ShouldEqual, tc.equal)
+		d, err := traversal.Diff(a, tc.b)
+		Wish(t, err, ShouldEqual, nil)
+		Wish(t, len(d) == 0, ShouldEqual, tc.equal)
+	}
+}
+
+// iterCounter counts how many times its map or list is iterated.
+type iterCounter struct {
+	ipld.Node
+	iterations int
+}
+
+func (n *iterCounter) MapIterator() ipld.MapIterator {
+	n.iterations++
+	return n.Node.MapIterator()
+}
+func (n *iterCounter) ListIterator() ipld.ListIterator {
+	n.iterations++
+	return n.Node.ListIterator()
+}
+
+func TestDeepEqual(t *testing.T) {
+	big := &iterCounter{Node: fluent.MustBuildList(basicnode.Style__List{}, 100, func(na fluent.ListAssembler) {
+		for i := 0; i < 100; i++ {
+			na.AssembleValue().AssignInt(i)
+		}
+	})}
+	a := fluent.MustBuildMap(basicnode.Style__Map{}, 2, func(na fluent.MapAssembler) {
+		na.AssembleEntry("big").AssignNode(big)
+		na.AssembleEntry("small").CreateMap(1, func(na fluent.MapAssembler) {
+			na.AssembleEntry("x").AssignInt(1)
+		})
+	})
+
+	t.Run("copy-on-write update shares the untouched subtree", func(t *testing.T) {
+		b, err := traversal.SetPath(a, "small/x", basicnode.NewInt(2))
+		Require(t, err, ShouldEqual, nil)
+		big.iterations = 0
+		Wish(t, ipld.DeepEqual(a, b), ShouldEqual, false)
+		Wish(t, ipld.Compare(a, b), ShouldEqual, -1)
+		c, err := traversal.SetPath(b, "small/x", basicnode.NewInt(1))
+		Require(t, err, ShouldEqual, nil)
+		Wish(t, ipld.DeepEqual(a, c), ShouldEqual, true)
+		Wish(t, ipld.Compare(a, c), ShouldEqual, 0)
+		Wish(t, big.iterations, ShouldEqual, 0)
+	})
+	t.Run("distinct but equal subtrees are walked", func(t *testing.T) {
+		big.iterations = 0
+		other := fluent.MustBuildList(basicnode.Style__List{}, 100, func(na fluent.ListAssembler) {
+			for i := 0; i < 100; i++ {
+				na.AssembleValue().AssignInt(i)
+			}
+		})
+		Wish(t, ipld.DeepEqual(big, other), ShouldEqual, true)
+		Wish(t, big.iterations, ShouldEqual, 1)
+	})
+	t.Run("different lengths are unequal without iterating", func(t *testing.T) {
+		big.iterations = 0
+		Wish(t, ipld.DeepEqual(big, fluent.MustBuildList(basicnode.Style__List{}, 0, func(fluent.ListAssembler) {})), ShouldEqual, false)
+		Wish(t, big.iterations, ShouldEqual, 0)
+	})
+	t.Run("scalars of different kinds", func(t *testing.T) {
+		Wish(t, ipld.DeepEqual(basicnode.NewInt(1), basicnode.NewFloat(1)), ShouldEqual, false)
+		Wish(t, ipld.DeepEqual(basicnode.NewBytes([]byte("x")), basicnode.NewBytes([]byte("x"))), ShouldEqual, true)
+	})
+}
+
+// brokenList is a list whose iterator fails, as a lazily-loaded one might.
+type brokenList struct {
+	ipld.Node
+}
+
+var errBrokenList = fmt.Errorf("cannot load list")
+
+func (brokenList) ListIterator() ipld.ListIterator {
+	return brokenListIterator{}
+}
+
+type brokenListIterator struct{}
+
+func (brokenListIterator) Next() (int, ipld.Node, error) { return -1, nil, errBrokenList }
+func (brokenListIterator) Done() bool                    { return false }
+
+func TestCompareErr(t *testing.T) {
+	good := fluent.MustBuildList(basicnode.Style__List{}, 1, func(na fluent.ListAssembler) {
+		na.AssembleValue().AssignInt(1)
+	})
+	broken := brokenList{good}
+	t.Run("errors are returned, with the failing side first", func(t *testing.T) {
+		c, err := ipld.CompareErr(broken, good)
+		Wish(t, c, ShouldEqual, -1)
+		Wish(t, err, ShouldEqual, errBrokenList)
+		c, err = ipld.CompareErr(good, broken)
+		Wish(t, c, ShouldEqual, 1)
+		Wish(t, err, ShouldEqual, errBrokenList)
+	})
+	t.Run("Compare and DeepEqual don't panic", func(t *testing.T) {
+		Wish(t, ipld.Compare(broken, good), ShouldEqual, -1)
+		Wish(t, ipld.DeepEqual(broken, good), ShouldEqual, false)
+		Wish(t, ipld.DeepEqual(good, broken), ShouldEqual, false)
+	})
+	t.Run("no error when reading works", func(t *testing.T) {
+		c, err := ipld.CompareErr(good, basicnode.NewInt(1))
+		Wish(t, c, ShouldEqual, 1)
+		Wish(t, err, ShouldEqual, nil)
+	})
+}
@@ -0,0 +1,149 @@
+package ipld
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// SameStyle reports whether two NodeStyles describe the same node implementation,
+// such that a node of one style can be taken apart and reused by an assembler of the other
+// (which is what the "own type" shortcut in most AssignNode implementations does).
+//
+// NodeStyles are compared by value: they must have the same concrete type,
+// and, if that type has fields (i.e. the style carries configuration),
+// the fields must be equal.  Most styles are empty structs, so any two
+// values of the same style type are the same style; but for example a style
+// with a validation option set is not the same as one without it.
+//
+// Styles whose concrete type isn't comparable (e.g. one which contains a slice
+// or map) are never reported as the same, even to themselves;
+// and a nil NodeStyle is never the same as anything.
+// The answer is only ever used to pick fast paths, so false negatives are safe.
+func SameStyle(a, b NodeStyle) bool {
+	if a == nil || b == nil {
+		return false
+	}
+	ta := reflect.TypeOf(a)
+	if ta != reflect.TypeOf(b) || !ta.Comparable() {
+		return false
+	}
+	return a == b
+}
+
+// Copy assembles the content of a node into a NodeAssembler.
+//
+// If the node and the assembler have the same style (see SameStyle),
+// Copy just calls AssignNode, which is expected to take the shortcut of
+// copying the node's internals wholesale.
+// Otherwise, Copy walks the node generically: maps (via ForEach) and lists are copied
+// entry by entry (using AssignNode on each child, so each of those still
+// gets its own chance at a shortcut), and scalars are copied by value.
+//
+// BeginMap and BeginList are given the node's Length as their size hint,
+// so the assembler can allocate room for all the entries up front;
+// a node which doesn't know its length (reporting -1) passes that on, meaning "unknown".
+//
+// Map keys which are strings go through AssembleEntry.
+// Any other key (e.g. a struct, for a typed map with complex keys) is handed
+// whole to AssembleKey().AssignNode, so it's never flattened into a string
+// on the way through.
+//
+// Undefined nodes cannot be copied, and result in an error.
+func Copy(n Node, na NodeAssembler) error {
+	if SameStyle(n.Style(), na.Style()) {
+		return na.AssignNode(n)
+	}
+	return KindDispatch{
+		OnMap: func(n Node) error {
+			ma, err := na.BeginMap(n.Length())
+			if err != nil {
+				return err
+			}
+			err = ForEach(n, func(k Node, v Node) error {
+				if k.ReprKind() == ReprKind_String {
+					ks, err := k.AsString()
+					if err != nil {
+						return err
+					}
+					va, err := ma.AssembleEntry(ks)
+					if err != nil {
+						return err
+					}
+					return va.AssignNode(v)
+				}
+				if err := ma.AssembleKey().AssignNode(k); err != nil {
+					return err
+				}
+				return ma.AssembleValue().AssignNode(v)
+			})
+			if err != nil {
+				return err
+			}
+			return ma.Finish()
+		},
+		OnList: func(n Node) error {
+			la, err := na.BeginList(n.Length())
+			if err != nil {
+				return err
+			}
+			for itr := n.ListIterator(); !itr.Done(); {
+				_, v, err := itr.Next()
+				if err != nil {
+					return err
+				}
+				if err := la.AssembleValue().AssignNode(v); err != nil {
+					return err
+				}
+			}
+			return la.Finish()
+		},
+		OnNull: func(n Node) error {
+			return na.AssignNull()
+		},
+		OnBool: func(n Node) error {
+			v, err := n.AsBool()
+			if err != nil {
+				return err
+			}
+			return na.AssignBool(v)
+		},
+		OnInt: func(n Node) error {
+			v, err := n.AsInt()
+			if err != nil {
+				return err
+			}
+			return na.AssignInt(v)
+		},
+		OnFloat: func(n Node) error {
+			v, err := n.AsFloat()
+			if err != nil {
+				return err
+			}
+			return na.AssignFloat(v)
+		},
+		OnString: func(n Node) error {
+			v, err := n.AsString()
+			if err != nil {
+				return err
+			}
+			return na.AssignString(v)
+		},
+		OnBytes: func(n Node) error {
+			v, err := n.AsBytes()
+			if err != nil {
+				return err
+			}
+			return na.AssignBytes(v)
+		},
+		OnLink: func(n Node) error {
+			v, err := n.AsLink()
+			if err != nil {
+				return err
+			}
+			return na.AssignLink(v)
+		},
+		Default: func(n Node) error {
+			return fmt.Errorf("cannot copy an undefined node")
+		},
+	}.Do(n)
+}
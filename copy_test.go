@@ -0,0 +1,207 @@
+package ipld_test
+
+import (
+	"fmt"
+	"testing"
+
+	. "github.com/warpfork/go-wish"
+
+	ipld "github.com/ipld/go-ipld-prime"
+	"github.com/ipld/go-ipld-prime/fluent"
+	basicnode "github.com/ipld/go-ipld-prime/node/basic"
+)
+
+func TestSameStyle(t *testing.T) {
+	Wish(t, ipld.SameStyle(basicnode.Style__Map{}, basicnode.Style.Map), ShouldEqual, true)
+	Wish(t, ipld.SameStyle(basicnode.NewString("x").Style(), basicnode.Style__String{}), ShouldEqual, true)
+	Wish(t, ipld.SameStyle(basicnode.Style__Map{}, basicnode.Style__Any{}), ShouldEqual, false)
+	Wish(t, ipld.SameStyle(basicnode.Style__Float{}, basicnode.Style__Float{AllowNonFinite: true}), ShouldEqual, false)
+	Wish(t, ipld.SameStyle(nil, nil), ShouldEqual, false)
+	Wish(t, ipld.SameStyle(basicnode.Style__Map{}, nil), ShouldEqual, false)
+	type uncomparable struct {
+		basicnode.Style__Map
+		x []int
+	}
+	Wish(t, ipld.SameStyle(uncomparable{}, uncomparable{}), ShouldEqual, false)
+}
+
+func TestCopy(t *testing.T) {
+	n := fluent.MustBuildMap(basicnode.Style__Map{}, 3, func(na fluent.MapAssembler) {
+		na.AssembleEntry("a").AssignString("x")
+		na.AssembleEntry("b").CreateList(2, func(na fluent.ListAssembler) {
+			na.AssembleValue().AssignInt(1)
+			na.AssembleValue().AssignNull()
+		})
+		na.AssembleEntry("c").AssignBytes([]byte{0xa})
+	})
+	t.Run("same style", func(t *testing.T) {
+		nb := basicnode.Style__Map{}.NewBuilder()
+		Wish(t, ipld.Copy(n, nb), ShouldEqual, nil)
+		Wish(t, ipld.Sprint(nb.Build()), ShouldEqual, ipld.Sprint(n))
+	})
+	t.Run("different style", func(t *testing.T) {
+		nb := basicnode.Style__Any{}.NewBuilder()
+		Wish(t, ipld.Copy(n, nb), ShouldEqual, nil)
+		Wish(t, ipld.Sprint(nb.Build()), ShouldEqual, `{"a": "x", "b": [1, null], "c": bytes(0a)}`)
+	})
+	t.Run("scalars", func(t *testing.T) {
+		nb := basicnode.Style__Any{}.NewBuilder()
+		Wish(t, ipld.Copy(basicnode.NewFloat(1.5), nb), ShouldEqual, nil)
+		Wish(t, ipld.Sprint(nb.Build()), ShouldEqual, "1.5")
+	})
+	t.Run("wrong kind for the assembler", func(t *testing.T) {
+		err := ipld.Copy(n, basicnode.Style__String{}.NewBuilder())
+		Wish(t, err, ShouldBeSameTypeAs, ipld.ErrWrongKind{})
+	})
+	t.Run("undefined", func(t *testing.T) {
+		err := ipld.Copy(ipld.Undef, basicnode.Style__Any{}.NewBuilder())
+		Wish(t, err.Error(), ShouldEqual, "cannot copy an undefined node")
+	})
+}
+
+func TestCopySizeHints(t *testing.T) {
+	n := fluent.MustBuildMap(basicnode.Style__Map{}, 2, func(na fluent.MapAssembler) {
+		na.AssembleEntry("a").AssignInt(1)
+		na.AssembleEntry("b").CreateList(3, func(na fluent.ListAssembler) {
+			na.AssembleValue().AssignInt(1)
+			na.AssembleValue().AssignInt(2)
+			na.AssembleValue().AssignInt(3)
+		})
+	})
+	t.Run("map", func(t *testing.T) {
+		na := &hintRecorder{NodeAssembler: basicnode.Style__Any{}.NewBuilder()}
+		Wish(t, ipld.Copy(n, na), ShouldEqual, nil)
+		Wish(t, na.hints, ShouldEqual, []int{2})
+	})
+	t.Run("list", func(t *testing.T) {
+		l, _ := n.LookupString("b")
+		na := &hintRecorder{NodeAssembler: basicnode.Style__Any{}.NewBuilder()}
+		Wish(t, ipld.Copy(l, na), ShouldEqual, nil)
+		Wish(t, na.hints, ShouldEqual, []int{3})
+	})
+	t.Run("unknown length", func(t *testing.T) {
+		nb := basicnode.Style__Any{}.NewBuilder()
+		na := &hintRecorder{NodeAssembler: nb}
+		Wish(t, ipld.Copy(unknownLength{n}, na), ShouldEqual, nil)
+		Wish(t, na.hints, ShouldEqual, []int{-1})
+		Wish(t, ipld.Sprint(nb.Build()), ShouldEqual, `{"a": 1, "b": [1, 2, 3]}`)
+	})
+}
+
+func BenchmarkCopyMap50k(b *testing.B) {
+	n := fluent.MustBuildMap(basicnode.Style__Map{}, 50000, func(na fluent.MapAssembler) {
+		for i := 0; i < 50000; i++ {
+			na.AssembleEntry(fmt.Sprintf("k%d", i)).AssignInt(i)
+		}
+	})
+	for _, bc := range []struct {
+		name string
+		n    ipld.Node
+	}{
+		{"WithSizeHint", n},
+		{"WithoutSizeHint", unknownLength{n}},
+	} {
+		b.Run(bc.name, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				// (Style__Any, so Copy can't take the same-style shortcut.)
+				if err := ipld.Copy(bc.n, basicnode.Style__Any{}.NewBuilder()); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+// hintRecorder is a NodeAssembler which records the size hints it's given.
+type hintRecorder struct {
+	ipld.NodeAssembler
+	hints []int
+}
+
+func (na *hintRecorder) BeginMap(sizeHint int) (ipld.MapAssembler, error) {
+	na.hints = append(na.hints, sizeHint)
+	return na.NodeAssembler.BeginMap(sizeHint)
+}
+func (na *hintRecorder) BeginList(sizeHint int) (ipld.ListAssembler, error) {
+	na.hints = append(na.hints, sizeHint)
+	return na.NodeAssembler.BeginList(sizeHint)
+}
+
+// unknownLength is a node which doesn't know its Length (as a lazily loaded map, for example, might not).
+type unknownLength struct {
+	ipld.Node
+}
+
+func (unknownLength) Length() int {
+	return -1
+}
+
+// failingMapIterator yields entries from a real map iterator,
+// but fails on the third call to Next, like an incrementally loaded map
+// whose next chunk can't be loaded.
+type failingMapIterator struct {
+	ipld.MapIterator
+	calls int
+}
+
+var errFakeLoad = fmt.Errorf("fake load failure")
+
+func (itr *failingMapIterator) Next() (ipld.Node, ipld.Node, error) {
+	itr.calls++
+	if itr.calls == 3 {
+		return nil, nil, errFakeLoad
+	}
+	return itr.MapIterator.Next()
+}
+
+// failingMap is a map node whose iterator is a failingMapIterator.
+type failingMap struct {
+	ipld.Node
+}
+
+func (n failingMap) MapIterator() ipld.MapIterator {
+	return &failingMapIterator{MapIterator: n.Node.MapIterator()}
+}
+
+func TestDrainMapIterator(t *testing.T) {
+	n := fluent.MustBuildMap(basicnode.Style__Map{}, 4, func(na fluent.MapAssembler) {
+		na.AssembleEntry("a").AssignInt(1)
+		na.AssembleEntry("b").AssignInt(2)
+		na.AssembleEntry("c").AssignInt(3)
+		na.AssembleEntry("d").AssignInt(4)
+	})
+	t.Run("visits all entries", func(t *testing.T) {
+		var keys []string
+		err := ipld.DrainMapIterator(n.MapIterator(), func(k, v ipld.Node) error {
+			ks, _ := k.AsString()
+			keys = append(keys, ks)
+			return nil
+		})
+		Wish(t, err, ShouldEqual, nil)
+		Wish(t, keys, ShouldEqual, []string{"a", "b", "c", "d"})
+	})
+	t.Run("stops at an iterator error", func(t *testing.T) {
+		var keys []string
+		err := ipld.DrainMapIterator(failingMap{n}.MapIterator(), func(k, v ipld.Node) error {
+			ks, _ := k.AsString()
+			keys = append(keys, ks)
+			return nil
+		})
+		Wish(t, err, ShouldEqual, errFakeLoad)
+		Wish(t, keys, ShouldEqual, []string{"a", "b"})
+	})
+	t.Run("stops at a callback error", func(t *testing.T) {
+		calls := 0
+		err := ipld.DrainMapIterator(n.MapIterator(), func(k, v ipld.Node) error {
+			calls++
+			return errFakeLoad
+		})
+		Wish(t, err, ShouldEqual, errFakeLoad)
+		Wish(t, calls, ShouldEqual, 1)
+	})
+	t.Run("Copy propagates iterator errors", func(t *testing.T) {
+		err := ipld.Copy(failingMap{n}, basicnode.Style__Any{}.NewBuilder())
+		Wish(t, err, ShouldEqual, errFakeLoad)
+	})
+}
@@ -2,6 +2,7 @@ package ipld
 
 import (
 	"fmt"
+	"strings"
 )
 
 // ErrWrongKind may be returned from functions on the Node interface when
@@ -30,13 +31,24 @@ type ErrWrongKind struct {
 	// In the case of typed nodes, this will typically refer to the 'natural'
 	// data-model kind for such a type (e.g., structs will say 'map' here).
 	ActualKind ReprKind
+
+	// RepresentationKind may optionally be set by typed nodes to the kind
+	// of the type's representation, when that differs from ActualKind
+	// (e.g., a struct with a stringjoin representation will say 'string' here).
+	// It's ReprKind_Invalid (the zero value) if not set.
+	//
+	// It's only reported if TypeName is also set.
+	RepresentationKind ReprKind
 }
 
 func (e ErrWrongKind) Error() string {
-	if e.TypeName == "" {
+	switch {
+	case e.TypeName == "":
 		return fmt.Sprintf("func called on wrong kind: %s called on a %s node, but only makes sense on %s", e.MethodName, e.ActualKind, e.AppropriateKind)
-	} else {
+	case e.RepresentationKind == ReprKind_Invalid:
 		return fmt.Sprintf("func called on wrong kind: %s called on a %s node (kind: %s), but only makes sense on %s", e.MethodName, e.TypeName, e.ActualKind, e.AppropriateKind)
+	default:
+		return fmt.Sprintf("func called on wrong kind: %s called on a %s node (kind: %s, repr kind: %s), but only makes sense on %s", e.MethodName, e.TypeName, e.ActualKind, e.RepresentationKind, e.AppropriateKind)
 	}
 }
 
@@ -56,18 +68,50 @@ func (e ErrNotExists) Error() string {
 	return fmt.Sprintf("key not found: %q", e.Segment)
 }
 
+// ErrUndefinedNode is returned from the lookup and AsX methods of an undefined node
+// (see Node.IsUndefined) -- e.g. the value of a struct field which was never set.
+// An undefined node isn't of any kind, so ErrWrongKind wouldn't be the whole story.
+type ErrUndefinedNode struct {
+	// MethodName is the method which was called, e.g. "AsString".
+	MethodName string
+}
+
+func (e ErrUndefinedNode) Error() string {
+	return fmt.Sprintf("func called on undefined node: %s called on a value which is absent", e.MethodName)
+}
+
 // ErrRepeatedMapKey is an error indicating that a key was inserted
 // into a map that already contains that key.
 //
 // This error may be returned by any methods that add data to a map --
 // any of the methods on a NodeAssembler that was yielded by MapAssembler.AssignKey(),
 // or from the MapAssembler.AssignDirectly() method.
+//
+// The Key may be any Node, including a complex (e.g. struct-typed) key;
+// the error message renders it with Sprint.
+// Implementations which often report repeats of a fixed set of keys
+// (e.g. struct field names) can avoid allocating a new Node per error
+// by keeping those keys in package-level variables of type Node.
 type ErrRepeatedMapKey struct {
 	Key Node
 }
 
 func (e ErrRepeatedMapKey) Error() string {
-	return fmt.Sprintf("cannot repeat map key (\"%s\")", e.Key)
+	if e.Key == nil {
+		return "cannot repeat map key"
+	}
+	return fmt.Sprintf("cannot repeat map key (%s)", Sprint(e.Key))
+}
+
+// ErrSliceBounds is returned by SubList when the bounds asked for
+// don't fit within the list (or are backwards).
+type ErrSliceBounds struct {
+	From, To int
+	Length   int
+}
+
+func (e ErrSliceBounds) Error() string {
+	return fmt.Sprintf("slice bounds out of range: [%d:%d] of a list of length %d", e.From, e.To, e.Length)
 }
 
 // ErrIteratorOverread is returned when calling 'Next' on a MapIterator or
@@ -78,9 +122,181 @@ func (e ErrIteratorOverread) Error() string {
 	return "iterator overread"
 }
 
+// ErrListOverrun is returned when a ListAssembler is asked to accept more
+// values than it can hold.
+//
+// For Data Model lists, this happens when BeginList was called with a
+// positive sizeHint, and then more values than that were assembled.
+// It's also possible for typed nodes -- specifically, struct types with
+// list (aka tuple) representations -- in which case Limit is the field count.
+type ErrListOverrun struct {
+	Limit int
+}
+
+func (e ErrListOverrun) Error() string {
+	return fmt.Sprintf("list overrun: cannot assemble more than %d values", e.Limit)
+}
+
+// ErrAssemblerMisuse is returned when an assembler method is called
+// out of order; for example, AssembleValue on a MapAssembler that isn't
+// waiting for a value, or anything at all after Finish.
+//
+// This is always a bug in the calling code.  By default, assemblers panic
+// on it instead; node implementations may offer a way to get this error
+// back instead (see basicnode.Style__Map.ErrorOnMisuse), so a misbehaving caller
+// doesn't bring down the whole program.
+type ErrAssemblerMisuse struct {
+	State  string // the state the assembler was in, e.g. "midValue".
+	Method string // the method which was called, e.g. "AssembleValue".
+}
+
+func (e ErrAssemblerMisuse) Error() string {
+	return fmt.Sprintf("assembler misuse: %s called in state %s", e.Method, e.State)
+}
+
+// ErrBuilderConsumed is returned when a NodeBuilder is assigned to (or begun)
+// again after Build, without a Reset in between.
+//
+// The node which Build returned is immutable, but a builder may still hold
+// the memory it was built in; so rather than risk changing that node,
+// the builder refuses.  Call Reset to use the builder again.
+type ErrBuilderConsumed struct {
+	Method string // the method which was called, e.g. "AssignString".
+}
+
+func (e ErrBuilderConsumed) Error() string {
+	return fmt.Sprintf("builder consumed: %s called after Build, without a Reset", e.Method)
+}
+
+// ErrInvalidFloat is returned when assigning a float value which is not finite:
+// NaN, or positive or negative infinity.
+//
+// DAG-CBOR forbids these values, and JSON can't express them at all,
+// so accepting them would produce data that can't round-trip.
+// Node implementations may offer a policy to allow them in non-strict contexts.
+type ErrInvalidFloat struct {
+	Value float64
+}
+
+func (e ErrInvalidFloat) Error() string {
+	return fmt.Sprintf("invalid float: %v is not a finite number", e.Value)
+}
+
+// ErrInexactWidening is returned when an int is assigned to a float
+// (by a Node implementation which allows that at all) but the float can't hold it exactly:
+// float64 holds ints exactly only up to 2^53, plus or minus.
+type ErrInexactWidening struct {
+	Value int
+}
+
+func (e ErrInexactWidening) Error() string {
+	return fmt.Sprintf("cannot widen int %d to a float exactly", e.Value)
+}
+
+// ErrIntegerOverflow is returned when decoding an integer which is too large
+// (or too small) to be held in an int, which is what Node.AsInt and NodeAssembler.AssignInt use.
+// Decoders return it rather than silently wrapping the value, or rounding it to a float.
+// (So does AsInt on a node wrapping a Go integer which is out of range, such as a big uint64 in a reflectnode.)
+//
+// Holding such integers needs a Node implementation with support for larger integers;
+// none of the Node implementations in this project have that yet.
+type ErrIntegerOverflow struct {
+	Value string // the integer, in decimal.
+}
+
+func (e ErrIntegerOverflow) Error() string {
+	return fmt.Sprintf("integer overflow: %s is out of the range of int", e.Value)
+}
+
+// ErrInvalidUTF8 is returned when assigning (or decoding) a string which isn't valid UTF-8,
+// where that's checked: see basicnode.Style__String's StrictUTF8, and codec.DecodeOptions.StrictUTF8.
+// It isn't checked by default, because not all data is so tidy:
+// DAG-CBOR, for example, is sometimes used to carry strings which aren't UTF-8.
+type ErrInvalidUTF8 struct {
+	Offset int // the byte offset, within the string, of the first invalid sequence.
+}
+
+func (e ErrInvalidUTF8) Error() string {
+	return fmt.Sprintf("invalid UTF-8 in string at byte offset %d", e.Offset)
+}
+
+// ErrBudgetExceeded is returned when a value is larger than a configured limit;
+// for example, when a decoder reading untrusted data meets a string or bytes
+// value longer than the maximum it was told to accept.
+// It's returned before the value is handed to a NodeAssembler,
+// so the oversized value is never retained in the result
+// (and the codecs return it before reading the value; see codec.DecodeOptions.MaxScalarLength).
+type ErrBudgetExceeded struct {
+	Kind   ReprKind // the kind of value which was too large (e.g. String or Bytes).
+	Length int      // the length of the value, or as much of it as was read before it was over budget.
+	Budget int
+}
+
+func (e ErrBudgetExceeded) Error() string {
+	return fmt.Sprintf("budget exceeded: %s of length %d is longer than the limit of %d", e.Kind, e.Length, e.Budget)
+}
+
 type ErrCannotBeNull struct{} // Review: arguably either ErrInvalidKindForNodeStyle.
 
-type ErrInvalidStructKey struct{}         // only possible for typed nodes -- specifically, struct types.
-type ErrMissingRequiredField struct{}     // only possible for typed nodes -- specifically, struct types.
-type ErrListOverrun struct{}              // only possible for typed nodes -- specifically, struct types with list (aka tuple) representations.
-type ErrInvalidUnionDiscriminant struct{} // only possible for typed nodes -- specifically, union types.
+// ErrInvalidStructKey is returned when assembling a struct, if a key
+// isn't the name of one of its fields.
+// (Only possible for typed nodes -- specifically, struct types.)
+type ErrInvalidStructKey struct {
+	TypeName string
+	Key      string
+}
+
+func (e ErrInvalidStructKey) Error() string {
+	return fmt.Sprintf("invalid key for struct %s: %q is not a field", e.TypeName, e.Key)
+}
+
+// ErrMissingRequiredField is returned when finishing the assembly of a struct,
+// if some of its required fields were never assigned.
+// (Only possible for typed nodes -- specifically, struct types.)
+type ErrMissingRequiredField struct {
+	TypeName string
+	Missing  []string
+}
+
+func (e ErrMissingRequiredField) Error() string {
+	return fmt.Sprintf("missing required fields for struct %s: %s", e.TypeName, strings.Join(e.Missing, ", "))
+}
+
+// ErrInvalidUnionDiscriminant is returned when assembling a union, if a key
+// isn't the discriminant of one of its members.
+// (Only possible for typed nodes -- specifically, union types.)
+type ErrInvalidUnionDiscriminant struct {
+	TypeName string
+	Key      string
+}
+
+func (e ErrInvalidUnionDiscriminant) Error() string {
+	return fmt.Sprintf("invalid discriminant for union %s: %q is not a member", e.TypeName, e.Key)
+}
+
+// ErrUnionMemberCount is returned when assembling a union, if it isn't given exactly one member:
+// either when a second member is given, or when finishing with none.
+// (Only possible for typed nodes -- specifically, union types.)
+type ErrUnionMemberCount struct {
+	TypeName string
+	Count    int // how many members were given (when a second is given, this is 2).
+}
+
+func (e ErrUnionMemberCount) Error() string {
+	return fmt.Sprintf("union %s must have exactly one member, but %d were given", e.TypeName, e.Count)
+}
+
+// ErrHashMismatch is returned when loading a link, if the content which
+// was loaded doesn't hash to what the link says it should.
+// This means the storage the Loader read from is corrupt (or lying).
+//
+// Actual is a link to the content that was actually loaded;
+// Expected is the link that was being loaded.
+type ErrHashMismatch struct {
+	Actual   Link
+	Expected Link
+}
+
+func (e ErrHashMismatch) Error() string {
+	return fmt.Sprintf("hash mismatch!  %q (actual) != %q (expected)", e.Actual, e.Expected)
+}
@@ -0,0 +1,100 @@
+package ipld
+
+import (
+	"context"
+)
+
+// MapEntry is a key and value from a map node, as sent by IterMapChan.
+type MapEntry struct {
+	Key   Node
+	Value Node
+}
+
+// ListEntry is an index and value from a list node, as sent by IterListChan.
+type ListEntry struct {
+	Index int
+	Value Node
+}
+
+// IterMapChan iterates over a map-kind node in a new goroutine,
+// sending its entries, in iteration order, on the first channel it returns.
+// That channel is closed when iteration stops, for whatever reason;
+// if the reason was an error, the error is sent on the second channel first.
+// So the usual way to consume them is:
+//
+//	entries, errs := ipld.IterMapChan(ctx, n)
+//	for e := range entries {
+//		// ...
+//	}
+//	if err := <-errs; err != nil {
+//		// ...
+//	}
+//
+// (The error channel has room for the one error, and is closed too,
+// so receiving from it after the entries are done never blocks.)
+//
+// The error is the one from the iterator, if iterating failed partway through
+// (see MapIterator.Next); or ErrWrongKind if the node isn't a map;
+// or the context's error, if it was cancelled.
+// A consumer which stops early must cancel the context,
+// or the goroutine will wait forever to send the next entry.
+//
+// The iteration is done with ForEach, so nodes implementing NodeSupportingForEach use that.
+func IterMapChan(ctx context.Context, n Node) (<-chan MapEntry, <-chan error) {
+	entries := make(chan MapEntry)
+	errs := make(chan error, 1)
+	if n.ReprKind() != ReprKind_Map {
+		errs <- ErrWrongKind{MethodName: "IterMapChan", AppropriateKind: ReprKindSet_JustMap, ActualKind: n.ReprKind()}
+		close(errs)
+		close(entries)
+		return entries, errs
+	}
+	go func() {
+		defer close(entries)
+		defer close(errs)
+		err := ForEach(n, func(k Node, v Node) error {
+			select {
+			case entries <- MapEntry{k, v}:
+				return nil
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		})
+		if err != nil {
+			errs <- err
+		}
+	}()
+	return entries, errs
+}
+
+// IterListChan is IterMapChan for list-kind nodes: it sends the list's entries,
+// in order, on the first channel, and any error on the second.
+// See IterMapChan for how to consume them, and why to cancel the context.
+func IterListChan(ctx context.Context, n Node) (<-chan ListEntry, <-chan error) {
+	entries := make(chan ListEntry)
+	errs := make(chan error, 1)
+	if n.ReprKind() != ReprKind_List {
+		errs <- ErrWrongKind{MethodName: "IterListChan", AppropriateKind: ReprKindSet_JustList, ActualKind: n.ReprKind()}
+		close(errs)
+		close(entries)
+		return entries, errs
+	}
+	go func() {
+		defer close(entries)
+		defer close(errs)
+		for itr := n.ListIterator(); !itr.Done(); {
+			idx, v, err := itr.Next()
+			if err != nil {
+				errs <- err
+				return
+			}
+			select {
+			case entries <- ListEntry{idx, v}:
+			case <-ctx.Done():
+				errs <- ctx.Err()
+				return
+			}
+		}
+	}()
+	return entries, errs
+}
@@ -0,0 +1,94 @@
+package ipld_test
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/warpfork/go-wish"
+
+	ipld "github.com/ipld/go-ipld-prime"
+	"github.com/ipld/go-ipld-prime/fluent"
+	basicnode "github.com/ipld/go-ipld-prime/node/basic"
+)
+
+func TestIterMapChan(t *testing.T) {
+	n := fluent.MustBuildMap(basicnode.Style__Map{}, 4, func(na fluent.MapAssembler) {
+		na.AssembleEntry("a").AssignInt(1)
+		na.AssembleEntry("b").AssignInt(2)
+		na.AssembleEntry("c").AssignInt(3)
+		na.AssembleEntry("d").AssignInt(4)
+	})
+	collect := func(entries <-chan ipld.MapEntry, errs <-chan error) ([]string, error) {
+		var keys []string
+		for e := range entries {
+			ks, _ := e.Key.AsString()
+			keys = append(keys, ks)
+		}
+		return keys, <-errs
+	}
+	t.Run("sends all entries", func(t *testing.T) {
+		keys, err := collect(ipld.IterMapChan(context.Background(), n))
+		Wish(t, err, ShouldEqual, nil)
+		Wish(t, keys, ShouldEqual, []string{"a", "b", "c", "d"})
+	})
+	t.Run("sends an iterator error", func(t *testing.T) {
+		keys, err := collect(ipld.IterMapChan(context.Background(), failingMap{n}))
+		Wish(t, err, ShouldEqual, errFakeLoad)
+		Wish(t, keys, ShouldEqual, []string{"a", "b"})
+	})
+	t.Run("stops when abandoned", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		entries, errs := ipld.IterMapChan(ctx, n)
+		e := <-entries
+		Wish(t, e.Key, ShouldEqual, basicnode.NewString("a"))
+		cancel()
+		// The goroutine may already be offering the next entry; but after that, it must give up,
+		// closing the channel (so this loop ends), rather than waiting forever.
+		var more int
+		for range entries {
+			more++
+		}
+		Wish(t, more <= 1, ShouldEqual, true)
+		Wish(t, <-errs, ShouldEqual, context.Canceled)
+	})
+	t.Run("not a map", func(t *testing.T) {
+		_, err := collect(ipld.IterMapChan(context.Background(), basicnode.NewInt(1)))
+		Wish(t, err, ShouldEqual, ipld.ErrWrongKind{MethodName: "IterMapChan", AppropriateKind: ipld.ReprKindSet_JustMap, ActualKind: ipld.ReprKind_Int})
+	})
+}
+
+func TestIterListChan(t *testing.T) {
+	n := fluent.MustBuildList(basicnode.Style__List{}, 3, func(na fluent.ListAssembler) {
+		na.AssembleValue().AssignString("x")
+		na.AssembleValue().AssignString("y")
+		na.AssembleValue().AssignString("z")
+	})
+	t.Run("sends all entries", func(t *testing.T) {
+		entries, errs := ipld.IterListChan(context.Background(), n)
+		var got []ipld.ListEntry
+		for e := range entries {
+			got = append(got, e)
+		}
+		Wish(t, <-errs, ShouldEqual, nil)
+		Wish(t, got, ShouldEqual, []ipld.ListEntry{
+			{0, basicnode.NewString("x")},
+			{1, basicnode.NewString("y")},
+			{2, basicnode.NewString("z")},
+		})
+	})
+	t.Run("stops when abandoned", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		entries, errs := ipld.IterListChan(ctx, n)
+		<-entries
+		cancel()
+		for range entries {
+		}
+		Wish(t, <-errs, ShouldEqual, context.Canceled)
+	})
+	t.Run("not a list", func(t *testing.T) {
+		entries, errs := ipld.IterListChan(context.Background(), basicnode.NewInt(1))
+		_, ok := <-entries
+		Wish(t, ok, ShouldEqual, false)
+		Wish(t, <-errs, ShouldEqual, ipld.ErrWrongKind{MethodName: "IterListChan", AppropriateKind: ipld.ReprKindSet_JustList, ActualKind: ipld.ReprKind_Int})
+	})
+}
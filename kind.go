@@ -51,6 +51,17 @@ func (k ReprKind) String() string {
 	}
 }
 
+// AllReprKinds returns every valid ReprKind (that is, all of them but ReprKind_Invalid),
+// in the order they're declared.
+//
+// It's meant for checking exhaustiveness: code which switches on ReprKind can be
+// tested by running every kind through it, so that a kind which isn't handled
+// shows up as a test failure rather than at runtime.
+// The slice is new on every call, so callers may modify it.
+func AllReprKinds() ReprKindSet {
+	return ReprKindSet{ReprKind_Map, ReprKind_List, ReprKind_Null, ReprKind_Bool, ReprKind_Int, ReprKind_Float, ReprKind_String, ReprKind_Bytes, ReprKind_Link}
+}
+
 // ReprKindSet is a type with a few enumerated consts that are commonly used
 // (mostly, in error messages).
 type ReprKindSet []ReprKind
@@ -70,7 +81,20 @@ var (
 	ReprKindSet_JustLink   = ReprKindSet{ReprKind_Link}
 )
 
+// Contains reports whether the set includes the given kind.
+func (x ReprKindSet) Contains(k ReprKind) bool {
+	for _, k2 := range x {
+		if k2 == k {
+			return true
+		}
+	}
+	return false
+}
+
 func (x ReprKindSet) String() string {
+	if len(x) == 0 {
+		return "nothing"
+	}
 	s := ""
 	for i := 0; i < len(x)-1; i++ {
 		s += x[i].String() + " or "
@@ -0,0 +1,92 @@
+package ipld
+
+// KindDispatch calls one of several handler functions, chosen by a node's ReprKind.
+//
+// It's a replacement for the `switch n.ReprKind() { ... }` block that almost
+// every generic consumer of Nodes ends up writing.
+// Any of the handlers may be left nil; if the handler for a node's kind is nil,
+// Default is called instead; and if Default is also nil, Do returns ErrWrongKind
+// (listing the kinds which did have handlers as the appropriate ones).
+// Undefined nodes (see Node.IsUndefined) have no handler of their own,
+// regardless of what kind they report, so they always go to Default.
+//
+// KindDispatch is a plain struct of funcs, so it's cheap to build one once
+// and call Do repeatedly; handlers which need to recurse can close over it.
+// (Print, and the generic codec.Marshal, are both written this way.)
+type KindDispatch struct {
+	OnMap    func(Node) error
+	OnList   func(Node) error
+	OnNull   func(Node) error
+	OnBool   func(Node) error
+	OnInt    func(Node) error
+	OnFloat  func(Node) error
+	OnString func(Node) error
+	OnBytes  func(Node) error
+	OnLink   func(Node) error
+
+	Default func(Node) error
+}
+
+// Do calls the handler for the node's kind (or Default), and returns its error.
+func (kd KindDispatch) Do(n Node) error {
+	var fn func(Node) error
+	if n.IsUndefined() {
+		return kd.orDefault(nil, n)
+	}
+	switch n.ReprKind() {
+	case ReprKind_Map:
+		fn = kd.OnMap
+	case ReprKind_List:
+		fn = kd.OnList
+	case ReprKind_Null:
+		fn = kd.OnNull
+	case ReprKind_Bool:
+		fn = kd.OnBool
+	case ReprKind_Int:
+		fn = kd.OnInt
+	case ReprKind_Float:
+		fn = kd.OnFloat
+	case ReprKind_String:
+		fn = kd.OnString
+	case ReprKind_Bytes:
+		fn = kd.OnBytes
+	case ReprKind_Link:
+		fn = kd.OnLink
+	}
+	return kd.orDefault(fn, n)
+}
+
+// orDefault calls fn, or Default if fn is nil, or returns ErrWrongKind if both are nil.
+func (kd KindDispatch) orDefault(fn func(Node) error, n Node) error {
+	if fn == nil {
+		fn = kd.Default
+	}
+	if fn == nil {
+		return ErrWrongKind{MethodName: "KindDispatch.Do", AppropriateKind: kd.handledKinds(), ActualKind: n.ReprKind()}
+	}
+	return fn(n)
+}
+
+// handledKinds returns the set of kinds which have a handler (not counting Default).
+func (kd KindDispatch) handledKinds() ReprKindSet {
+	var ks ReprKindSet
+	for _, x := range []struct {
+		fn func(Node) error
+		k  ReprKind
+	}{
+		{kd.OnMap, ReprKind_Map},
+		{kd.OnList, ReprKind_List},
+		{kd.OnNull, ReprKind_Null},
+		{kd.OnBool, ReprKind_Bool},
+		{kd.OnInt, ReprKind_Int},
+		{kd.OnFloat, ReprKind_Float},
+		{kd.OnString, ReprKind_String},
+		{kd.OnBytes, ReprKind_Bytes},
+		{kd.OnLink, ReprKind_Link},
+	} {
+		if x.fn != nil {
+			ks = append(ks, x.k)
+		}
+	}
+	return ks
+}
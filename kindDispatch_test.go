@@ -0,0 +1,49 @@
+package ipld_test
+
+import (
+	"testing"
+
+	. "github.com/warpfork/go-wish"
+
+	ipld "github.com/ipld/go-ipld-prime"
+	"github.com/ipld/go-ipld-prime/fluent"
+	basicnode "github.com/ipld/go-ipld-prime/node/basic"
+)
+
+func TestKindDispatch(t *testing.T) {
+	var got string
+	kd := ipld.KindDispatch{
+		OnString: func(n ipld.Node) error {
+			got = "string"
+			return nil
+		},
+		OnInt: func(n ipld.Node) error {
+			got = "int"
+			return nil
+		},
+	}
+	t.Run("handlers are chosen by kind", func(t *testing.T) {
+		Wish(t, kd.Do(basicnode.NewString("x")), ShouldEqual, nil)
+		Wish(t, got, ShouldEqual, "string")
+		Wish(t, kd.Do(basicnode.NewInt(1)), ShouldEqual, nil)
+		Wish(t, got, ShouldEqual, "int")
+	})
+	t.Run("unhandled kinds error without a default", func(t *testing.T) {
+		err := kd.Do(basicnode.NewBool(true))
+		Wish(t, err, ShouldEqual, ipld.ErrWrongKind{MethodName: "KindDispatch.Do", AppropriateKind: ipld.ReprKindSet{ipld.ReprKind_Int, ipld.ReprKind_String}, ActualKind: ipld.ReprKind_Bool})
+		err = ipld.KindDispatch{}.Do(ipld.Null)
+		Wish(t, err.Error(), ShouldEqual, "func called on wrong kind: KindDispatch.Do called on a Null node, but only makes sense on nothing")
+	})
+	t.Run("default catches unhandled kinds and undefined", func(t *testing.T) {
+		kd2 := kd
+		kd2.Default = func(n ipld.Node) error {
+			got = "default"
+			return nil
+		}
+		Wish(t, kd2.Do(fluent.MustBuildMap(basicnode.Style__Map{}, 0, func(fluent.MapAssembler) {})), ShouldEqual, nil)
+		Wish(t, got, ShouldEqual, "default")
+		got = ""
+		Wish(t, kd2.Do(ipld.Undef), ShouldEqual, nil)
+		Wish(t, got, ShouldEqual, "default")
+	})
+}
@@ -0,0 +1,85 @@
+package ipld_test
+
+import (
+	"testing"
+
+	. "github.com/warpfork/go-wish"
+
+	ipld "github.com/ipld/go-ipld-prime"
+)
+
+// unhandledKinds returns the kinds (out of all of them) for which handled returns false.
+func unhandledKinds(handled func(ipld.ReprKind) bool) ipld.ReprKindSet {
+	var ks ipld.ReprKindSet
+	for _, k := range ipld.AllReprKinds() {
+		if !handled(k) {
+			ks = append(ks, k)
+		}
+	}
+	return ks
+}
+
+// kindOnly is a node which reports a kind, and nothing else;
+// it's enough to get through KindDispatch.Do.
+type kindOnly struct {
+	ipld.Node
+	k ipld.ReprKind
+}
+
+func (n kindOnly) ReprKind() ipld.ReprKind { return n.k }
+
+func TestAllReprKinds(t *testing.T) {
+	t.Run("kinds are distinct and valid", func(t *testing.T) {
+		seen := map[ipld.ReprKind]bool{}
+		for _, k := range ipld.AllReprKinds() {
+			Wish(t, k == ipld.ReprKind_Invalid, ShouldEqual, false)
+			Wish(t, seen[k], ShouldEqual, false)
+			seen[k] = true
+		}
+		Wish(t, len(seen), ShouldEqual, len(ipld.ReprKindSet_Recursive)+len(ipld.ReprKindSet_Scalar))
+	})
+	t.Run("String handles every kind", func(t *testing.T) {
+		Wish(t, unhandledKinds(func(k ipld.ReprKind) bool {
+			defer func() { recover() }()
+			return k.String() != ""
+		}), ShouldEqual, ipld.ReprKindSet(nil))
+	})
+	t.Run("KindDispatch handles every kind", func(t *testing.T) {
+		got := map[ipld.ReprKind]ipld.ReprKind{}
+		on := func(k ipld.ReprKind) func(ipld.Node) error {
+			return func(n ipld.Node) error {
+				got[n.ReprKind()] = k
+				return nil
+			}
+		}
+		kd := ipld.KindDispatch{
+			OnMap:    on(ipld.ReprKind_Map),
+			OnList:   on(ipld.ReprKind_List),
+			OnNull:   on(ipld.ReprKind_Null),
+			OnBool:   on(ipld.ReprKind_Bool),
+			OnInt:    on(ipld.ReprKind_Int),
+			OnFloat:  on(ipld.ReprKind_Float),
+			OnString: on(ipld.ReprKind_String),
+			OnBytes:  on(ipld.ReprKind_Bytes),
+			OnLink:   on(ipld.ReprKind_Link),
+		}
+		for _, k := range ipld.AllReprKinds() {
+			Wish(t, kd.Do(kindOnly{ipld.Null, k}), ShouldEqual, nil)
+		}
+		Wish(t, unhandledKinds(func(k ipld.ReprKind) bool { return got[k] == k }), ShouldEqual, ipld.ReprKindSet(nil))
+	})
+	t.Run("an unhandled kind is caught", func(t *testing.T) {
+		handlers := map[ipld.ReprKind]func(){}
+		for _, k := range ipld.AllReprKinds() {
+			if k != ipld.ReprKind_Link {
+				handlers[k] = func() {}
+			}
+		}
+		Wish(t, unhandledKinds(func(k ipld.ReprKind) bool { return handlers[k] != nil }), ShouldEqual, ipld.ReprKindSet_JustLink)
+	})
+	t.Run("callers may modify the result", func(t *testing.T) {
+		ks := ipld.AllReprKinds()
+		ks[0] = ipld.ReprKind_Invalid
+		Wish(t, ipld.AllReprKinds()[0], ShouldEqual, ipld.ReprKind_Map)
+	})
+}
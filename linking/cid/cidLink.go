@@ -19,6 +19,14 @@ type Link struct {
 	cid.Cid
 }
 
+// Load reads the block for this CID using the loader, and decodes it into the NodeAssembler
+// using the decoder registered for the CID's multicodec.
+//
+// The loaded bytes are always hashed and checked against the CID's multihash;
+// if they don't match, Load returns ipld.ErrHashMismatch.
+// There's no option to skip this: an unverified load isn't content addressing.
+// Note that if the hash doesn't match, the NodeAssembler may still have been
+// partly (or entirely) fed with the unverified content, so should be discarded.
 func (lnk Link) Load(ctx context.Context, lnkCtx ipld.LinkContext, na ipld.NodeAssembler, loader ipld.Loader) error {
 	// Open the byte reader.
 	r, err := loader(lnk, lnkCtx)
@@ -48,7 +56,7 @@ func (lnk Link) Load(ctx context.Context, lnkCtx ipld.LinkContext, na ipld.NodeA
 		return err
 	}
 	if cid != lnk.Cid {
-		return fmt.Errorf("hash mismatch!  %q (actual) != %q (expected)", cid, lnk.Cid)
+		return ipld.ErrHashMismatch{Actual: Link{cid}, Expected: lnk}
 	}
 	if decodeErr != nil {
 		return decodeErr
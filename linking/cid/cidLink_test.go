@@ -0,0 +1,53 @@
+package cidlink_test
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"testing"
+
+	cid "github.com/ipfs/go-cid"
+	. "github.com/warpfork/go-wish"
+
+	ipld "github.com/ipld/go-ipld-prime"
+	_ "github.com/ipld/go-ipld-prime/codec/dagjson"
+	cidlink "github.com/ipld/go-ipld-prime/linking/cid"
+	basicnode "github.com/ipld/go-ipld-prime/node/basic"
+)
+
+func TestLoadVerifiesHash(t *testing.T) {
+	storage := make(map[ipld.Link][]byte)
+	storer := func(ipld.LinkContext) (io.Writer, ipld.StoreCommitter, error) {
+		buf := bytes.Buffer{}
+		return &buf, func(lnk ipld.Link) error {
+			storage[lnk] = buf.Bytes()
+			return nil
+		}, nil
+	}
+	loader := func(lnk ipld.Link, _ ipld.LinkContext) (io.Reader, error) {
+		return bytes.NewReader(storage[lnk]), nil
+	}
+	lb := cidlink.LinkBuilder{cid.Prefix{
+		Version:  1,
+		Codec:    0x0129, // dag-json
+		MhType:   0x17,   // sha3-224
+		MhLength: 4,
+	}}
+	lnk, err := lb.Build(context.Background(), ipld.LinkContext{}, basicnode.NewString("alpha"), storer)
+	Require(t, err, ShouldEqual, nil)
+
+	t.Run("matching block loads", func(t *testing.T) {
+		nb := basicnode.Style__Any{}.NewBuilder()
+		err := lnk.Load(context.Background(), ipld.LinkContext{}, nb, loader)
+		Wish(t, err, ShouldEqual, nil)
+		Wish(t, nb.Build(), ShouldEqual, basicnode.NewString("alpha"))
+	})
+	t.Run("corrupted block is rejected", func(t *testing.T) {
+		storage[lnk] = []byte(`"alphb"`)
+		nb := basicnode.Style__Any{}.NewBuilder()
+		err := lnk.Load(context.Background(), ipld.LinkContext{}, nb, loader)
+		Wish(t, err, ShouldBeSameTypeAs, ipld.ErrHashMismatch{})
+		Wish(t, err.(ipld.ErrHashMismatch).Expected, ShouldEqual, lnk)
+		Wish(t, err.(ipld.ErrHashMismatch).Actual == lnk, ShouldEqual, false)
+	})
+}
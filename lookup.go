@@ -0,0 +1,97 @@
+package ipld
+
+// LookupIndexRelative is like Node.LookupIndex, but also accepts negative
+// indexes, which count back from the end of the list: -1 is the last element,
+// -2 the one before it, and so on.
+//
+// This is opt-in ergonomics: the Data Model itself has no negative indexes,
+// and Node.LookupIndex on a list will always report them as not existing.
+//
+// An index which is out of range in either direction yields ErrNotExists
+// (mentioning the index as given); a node which is not a list yields
+// whatever its LookupIndex method does (typically ErrWrongKind).
+func LookupIndexRelative(n Node, idx int) (Node, error) {
+	if idx >= 0 || n.ReprKind() != ReprKind_List {
+		return n.LookupIndex(idx)
+	}
+	abs := n.Length() + idx
+	if abs < 0 {
+		return nil, ErrNotExists{PathSegmentOfInt(idx)}
+	}
+	return n.LookupIndex(abs)
+}
+
+// LookupSegmentRelative is like Node.LookupSegment, but when the node is a
+// list, negative indexes are handled as by LookupIndexRelative.
+// (On maps, a segment like "-1" is just an ordinary key.)
+func LookupSegmentRelative(n Node, seg PathSegment) (Node, error) {
+	if n.ReprKind() != ReprKind_List {
+		return n.LookupSegment(seg)
+	}
+	idx, err := seg.Index()
+	if err != nil {
+		return n.LookupSegment(seg)
+	}
+	return LookupIndexRelative(n, idx)
+}
+
+// LookupPath looks up a path given as a string of segments separated by ".",
+// such as "a.b.0.c", starting from n.
+// It's LookupPathSeparated, with "." as the separator.
+func LookupPath(n Node, path string) (Node, error) {
+	return LookupPathSeparated(n, path, '.')
+}
+
+// LookupPathSeparated looks up a path given as a string of segments separated by sep,
+// starting from n, by calling LookupSegment with each segment in turn.
+// (So a segment indexes into a list if the node reached so far is a list,
+// and is a key otherwise.)
+//
+// A backslash makes the character after it part of the segment, whatever it is,
+// so keys containing the separator (or a backslash) can still be reached:
+// with "." as the separator, `a\.b.c` is the two segments "a.b" and "c",
+// and `a\\.b` is the two segments `a\` and "b".
+// (A backslash at the very end of the path stands for itself.)
+// Segments may be empty: "a..b" has an empty segment between "a" and "b".
+// The empty path has no segments, and returns n itself.
+//
+// The first error from LookupSegment is returned as-is; e.g. a key which
+// isn't in its map yields ErrNotExists, mentioning the segment.
+func LookupPathSeparated(n Node, path string, sep rune) (Node, error) {
+	for _, seg := range splitEscaped(path, sep) {
+		var err error
+		if n, err = n.LookupSegment(PathSegmentOfString(seg)); err != nil {
+			return nil, err
+		}
+	}
+	return n, nil
+}
+
+// splitEscaped splits s at each sep which isn't escaped by a backslash,
+// and removes the escaping backslashes.
+func splitEscaped(s string, sep rune) []string {
+	if s == "" {
+		return nil
+	}
+	var segs []string
+	var seg []rune
+	escaped := false
+	for _, r := range s {
+		switch {
+		case escaped:
+			seg = append(seg, r)
+			escaped = false
+		case r == '\\':
+			escaped = true
+		case r == sep:
+			segs = append(segs, string(seg))
+			seg = seg[:0]
+		default:
+			seg = append(seg, r)
+		}
+	}
+	if escaped {
+		seg = append(seg, '\\')
+	}
+	return append(segs, string(seg))
+}
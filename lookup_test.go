@@ -0,0 +1,66 @@
+package ipld_test
+
+import (
+	"testing"
+
+	. "github.com/warpfork/go-wish"
+
+	ipld "github.com/ipld/go-ipld-prime"
+	"github.com/ipld/go-ipld-prime/fluent"
+	basicnode "github.com/ipld/go-ipld-prime/node/basic"
+)
+
+func TestLookupPath(t *testing.T) {
+	n := fluent.MustBuildMap(basicnode.Style__Map{}, 4, func(na fluent.MapAssembler) {
+		na.AssembleEntry("a").CreateMap(1, func(na fluent.MapAssembler) {
+			na.AssembleEntry("b").AssignString("plain")
+		})
+		na.AssembleEntry("a.b").AssignString("dotted")
+		na.AssembleEntry(`back\slash`).CreateMap(1, func(na fluent.MapAssembler) {
+			na.AssembleEntry("").AssignString("empty")
+		})
+		na.AssembleEntry("list").CreateList(2, func(na fluent.ListAssembler) {
+			na.AssembleValue().AssignString("zero")
+			na.AssembleValue().CreateMap(1, func(na fluent.MapAssembler) {
+				na.AssembleEntry("x/y").AssignString("slashed")
+			})
+		})
+	})
+	for _, tcase := range []struct {
+		path string
+		sep  rune
+		want string
+	}{
+		{"a.b", '.', "plain"},
+		{`a\.b`, '.', "dotted"},
+		{`back\\slash.`, '.', "empty"},
+		{"list.0", '.', "zero"},
+		{"list.1.x/y", '.', "slashed"},
+		{"list/1/x\\/y", '/', "slashed"},
+		{"a.b", '/', "dotted"},
+	} {
+		t.Run(tcase.path, func(t *testing.T) {
+			v, err := ipld.LookupPathSeparated(n, tcase.path, tcase.sep)
+			Require(t, err, ShouldEqual, nil)
+			s, err := v.AsString()
+			Wish(t, err, ShouldEqual, nil)
+			Wish(t, s, ShouldEqual, tcase.want)
+		})
+	}
+	t.Run("LookupPath separates with dots", func(t *testing.T) {
+		v, err := ipld.LookupPath(n, `a\.b`)
+		Wish(t, err, ShouldEqual, nil)
+		Wish(t, v, ShouldEqual, basicnode.NewString("dotted"))
+	})
+	t.Run("empty path", func(t *testing.T) {
+		v, err := ipld.LookupPath(n, "")
+		Wish(t, err, ShouldEqual, nil)
+		Wish(t, v, ShouldEqual, n)
+	})
+	t.Run("missing keys", func(t *testing.T) {
+		_, err := ipld.LookupPath(n, "a.c")
+		Wish(t, err, ShouldEqual, ipld.ErrNotExists{ipld.PathSegmentOfString("c")})
+		_, err = ipld.LookupPath(n, "a.b.c")
+		Wish(t, err, ShouldEqual, ipld.ErrWrongKind{TypeName: "string", MethodName: "LookupSegment", AppropriateKind: ipld.ReprKindSet_Recursive, ActualKind: ipld.ReprKind_String})
+	})
+}
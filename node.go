@@ -1,5 +1,10 @@
 package ipld
 
+import (
+	"bytes"
+	"io"
+)
+
 // Node represents a value in IPLD.  Any point in a tree of data is a node:
 // scalar values (like int, string, etc) are nodes, and
 // so are recursive values (like map and list).
@@ -121,7 +126,7 @@ type Node interface {
 	ListIterator() ListIterator
 
 	// Length returns the length of a list, or the number of entries in a map,
-	// or -1 if the node is not of list nor map kind.
+	// or -1 if the node is not of list nor map kind (including if it's undefined).
 	Length() int
 
 	// Undefined nodes are returned when traversing a struct field that is
@@ -130,6 +135,11 @@ type Node interface {
 	// The undefined flag is necessary so iterating over structs can
 	// unambiguously make the distinction between values that are
 	// present-and-null versus values that are absent.
+	// (By default, struct iterators skip absent fields entirely;
+	// see MapIteratorSupportingUndefined for how to see them.)
+	// An undefined node has a Length of -1, and its lookup and AsX methods
+	// return ErrUndefinedNode (not ErrWrongKind), so callers can tell
+	// "this field was never set" apart from "this value is the wrong kind".
 	IsUndefined() bool
 
 	IsNull() bool
@@ -199,6 +209,96 @@ type NodeStyleSupportingAmend interface {
 	// FUTURE: consider putting this (and others like it) in a `feature` package, if there begin to be enough of them and docs get crowded.
 }
 
+// NodeSupportingBytesStream is a feature-detection interface that can be
+// used on a bytes-kind Node to read its content sequentially, without first
+// concatenating it into one contiguous slice (as AsBytes must).
+//
+// Nodes which hold their bytes in several chunks -- e.g. a large-bytes ADL --
+// will typically support this.  Use the AsBytesStream function to get a
+// reader from any bytes node, using this feature if it's available.
+type NodeSupportingBytesStream interface {
+	// AsBytesStream returns a reader over the content of the node.
+	// Each call returns a new reader, starting from the beginning.
+	AsBytesStream() (io.Reader, error)
+
+	// ByteLength returns the total length of the content in bytes,
+	// or -1 if that isn't known without reading it all.
+	// (Length itself remains -1, as for any other non-recursive node.)
+	ByteLength() int
+}
+
+// AsBytesStream returns a reader over the content of a bytes-kind node.
+// If the node implements NodeSupportingBytesStream, that's used;
+// otherwise, this falls back to reading the result of AsBytes.
+func AsBytesStream(n Node) (io.Reader, error) {
+	if n2, ok := n.(NodeSupportingBytesStream); ok {
+		return n2.AsBytesStream()
+	}
+	b, err := n.AsBytes()
+	if err != nil {
+		return nil, err
+	}
+	return bytes.NewReader(b), nil
+}
+
+// NodeSupportingHas is a feature-detection interface that can be used on
+// a map-kind Node to check whether it contains a key, without looking up
+// (and possibly having to produce a Node for) the value.
+//
+// Use the Has function to check for a key in any map node,
+// using this feature if it's available.
+type NodeSupportingHas interface {
+	// Has returns true if the map contains the key.
+	// Errors are reserved for keys which couldn't possibly be in this map
+	// (e.g. a key of the wrong kind), or problems reaching the data.
+	Has(key Node) (bool, error)
+}
+
+// Has reports whether a map-kind node contains the given key.
+// If the node implements NodeSupportingHas, that's used;
+// otherwise, this falls back to Lookup, treating ErrNotExists as false.
+func Has(n Node, key Node) (bool, error) {
+	if n2, ok := n.(NodeSupportingHas); ok {
+		return n2.Has(key)
+	}
+	_, err := n.Lookup(key)
+	switch err.(type) {
+	case nil:
+		return true, nil
+	case ErrNotExists:
+		return false, nil
+	default:
+		return false, err
+	}
+}
+
+// NodeSupportingForEach is a feature-detection interface that can be used on
+// a map-kind Node to visit its entries without allocating a MapIterator.
+//
+// Use the ForEach function to visit the entries of any map node,
+// using this feature if it's available.
+type NodeSupportingForEach interface {
+	// ForEach calls fn for each entry of the map, in the same order as
+	// the MapIterator would yield them.  It stops at the first error
+	// returned by fn (or encountered while reaching the data), and returns it.
+	ForEach(fn func(k Node, v Node) error) error
+}
+
+// ForEach calls fn for each entry of a map-kind node, in iteration order,
+// stopping at the first error.
+// If the node implements NodeSupportingForEach, that's used;
+// otherwise, this falls back to DrainMapIterator.
+func ForEach(n Node, fn func(k Node, v Node) error) error {
+	if n2, ok := n.(NodeSupportingForEach); ok {
+		return n2.ForEach(fn)
+	}
+	itr := n.MapIterator()
+	if itr == nil {
+		return ErrWrongKind{MethodName: "ForEach", AppropriateKind: ReprKindSet_JustMap, ActualKind: n.ReprKind()}
+	}
+	return DrainMapIterator(itr, fn)
+}
+
 // MapIterator is an interface for traversing map nodes.
 // Sequential calls to Next() will yield key-value pairs;
 // Done() describes whether iteration should continue.
@@ -232,6 +332,44 @@ type MapIterator interface {
 	Done() bool
 }
 
+// MapIteratorSupportingUndefined is a feature-detection interface that can be
+// used on the MapIterator of a struct with optional fields to choose whether
+// absent fields are skipped or yielded.
+//
+// By default, absent fields are skipped: iteration yields only the fields
+// which are present (agreeing with Node.Length, and with what a codec would
+// serialize).  Calling YieldUndefined(true) instead makes the iterator yield
+// every field the struct defines, in order, with absent fields having a value
+// for which IsUndefined returns true.
+// In both modes, a field which is present but null is yielded as a null node.
+//
+// The mode should be chosen before the first call to Next or Done.
+type MapIteratorSupportingUndefined interface {
+	MapIterator
+
+	YieldUndefined(bool)
+}
+
+// DrainMapIterator calls fn for each remaining entry of the iterator, in order.
+//
+// It stops at the first error, either from the iterator's Next method
+// (e.g. an I/O error partway through an incrementally loaded map)
+// or from fn, and returns that error as-is.
+// Generic code which walks maps should prefer this over hand-writing the
+// Done/Next loop, so that errors from Next can't be accidentally dropped.
+func DrainMapIterator(itr MapIterator, fn func(k Node, v Node) error) error {
+	for !itr.Done() {
+		k, v, err := itr.Next()
+		if err != nil {
+			return err
+		}
+		if err := fn(k, v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // ListIterator is an interface for traversing list nodes.
 // Sequential calls to Next() will yield index-value pairs;
 // Done() describes whether iteration should continue.
@@ -0,0 +1,229 @@
+package basicnode
+
+import (
+	ipld "github.com/ipld/go-ipld-prime"
+)
+
+var (
+	_ Allocator      = &Arena{}
+	_ ipld.NodeStyle = allocatingStyle{}
+)
+
+// Allocator supplies the memory for the nodes built by a style from NewStyleWithAllocator.
+//
+// The default (as used by Style__Any and the other styles in this package)
+// is to allocate each node on the heap separately.  An Allocator can do better
+// when many nodes are built at once (e.g. by a decoder), by handing out nodes
+// from larger blocks of memory: see Arena.
+//
+// Each method returns a pointer to a new zero value of one of the node types below,
+// which the assemblers then fill in.  The contents of those types are private to this package;
+// an Allocator only decides where they live.  It mustn't hand out the same memory twice.
+type Allocator interface {
+	AllocMap() *MapNode
+	AllocList() *ListNode
+	AllocBool() *BoolNode
+	AllocInt() *IntNode
+	AllocFloat() *FloatNode
+	AllocString() *StringNode
+	AllocBytes() *BytesNode
+	AllocLink() *LinkNode
+}
+
+// The node types which an Allocator hands out: these are the nodes built by Style__Any.
+type (
+	MapNode    = plainMap
+	ListNode   = plainList
+	BoolNode   = plainBool
+	IntNode    = plainInt
+	FloatNode  = plainFloat
+	StringNode = plainString
+	BytesNode  = plainBytes
+	LinkNode   = plainLink
+)
+
+// NewStyleWithAllocator returns a style which builds any kind of node, like Style__Any,
+// but which draws the nodes it builds from the given Allocator.
+// This applies all the way down: the values in maps and lists come from the Allocator too.
+//
+// The nodes built are the same as those built by Style__Any, and report the same styles;
+// only where their memory comes from is different.
+func NewStyleWithAllocator(alloc Allocator) ipld.NodeStyle {
+	return allocatingStyle{alloc}
+}
+
+type allocatingStyle struct {
+	alloc Allocator
+}
+
+func (ns allocatingStyle) NewBuilder() ipld.NodeBuilder {
+	return &anyBuilder{alloc: ns.alloc}
+}
+
+// The following helpers are how the assemblers in this package get new nodes.
+// A nil Allocator means the default: a separate heap allocation for each node.
+
+func newMap(a Allocator) *plainMap {
+	if a == nil {
+		return &plainMap{}
+	}
+	return a.AllocMap()
+}
+func newList(a Allocator) *plainList {
+	if a == nil {
+		return &plainList{}
+	}
+	return a.AllocList()
+}
+func newBool(a Allocator, v bool) *plainBool {
+	if a == nil {
+		vb := plainBool(v)
+		return &vb
+	}
+	p := a.AllocBool()
+	*p = plainBool(v)
+	return p
+}
+func newInt(a Allocator, v int) *plainInt {
+	if a == nil {
+		vb := plainInt(v)
+		return &vb
+	}
+	p := a.AllocInt()
+	*p = plainInt(v)
+	return p
+}
+func newFloat(a Allocator, v float64) *plainFloat {
+	if a == nil {
+		vb := plainFloat(v)
+		return &vb
+	}
+	p := a.AllocFloat()
+	*p = plainFloat(v)
+	return p
+}
+func newString(a Allocator, v string) *plainString {
+	if a == nil {
+		vb := plainString(v)
+		return &vb
+	}
+	p := a.AllocString()
+	*p = plainString(v)
+	return p
+}
+func newBytes(a Allocator, v []byte) *plainBytes {
+	if a == nil {
+		vb := plainBytes(v)
+		return &vb
+	}
+	p := a.AllocBytes()
+	*p = plainBytes(v)
+	return p
+}
+func newLink(a Allocator, v ipld.Link) *plainLink {
+	if a == nil {
+		return &plainLink{v}
+	}
+	p := a.AllocLink()
+	*p = plainLink{v}
+	return p
+}
+
+// Arena is an Allocator which hands out nodes from blocks ("chunks") of memory,
+// so that building many nodes costs only one allocation per chunk of each kind.
+//
+// The tradeoff is in how memory is freed: a chunk can't be garbage collected
+// until none of the nodes in it are reachable.  So an Arena suits building
+// whole trees which are then used and dropped together (which is the usual
+// pattern for decoding), and suits less well keeping a few nodes out of many.
+// The Arena itself only holds on to its current chunks, so using one Arena
+// to build one tree after another doesn't grow without bound.
+//
+// Fewer allocations isn't the same as faster, though.  In this package's unmarshal
+// benchmark, an Arena makes about 15% fewer allocations for the same bytes,
+// but is no faster in time per op on small trees: at 32 entries it measured about 8% slower.
+// Benchmark your own workload before reaching for one.
+//
+// An Arena isn't safe for concurrent use.  Builders from the same style share
+// its Arena, so they mustn't be used concurrently either.
+type Arena struct {
+	chunkSize int
+
+	maps    []plainMap
+	lists   []plainList
+	bools   []plainBool
+	ints    []plainInt
+	floats  []plainFloat
+	strings []plainString
+	bytes   []plainBytes
+	links   []plainLink
+}
+
+// NewArena returns an Arena which allocates chunks of chunkSize nodes at a time.
+// If chunkSize isn't positive, a default of 256 is used.
+func NewArena(chunkSize int) *Arena {
+	if chunkSize <= 0 {
+		chunkSize = 256
+	}
+	return &Arena{chunkSize: chunkSize}
+}
+
+// Each of the following appends to the current chunk of its kind,
+// starting a new one when it's full; the old chunk lives on for as long as its nodes do.
+
+func (a *Arena) AllocMap() *MapNode {
+	if len(a.maps) == cap(a.maps) {
+		a.maps = make([]plainMap, 0, a.chunkSize)
+	}
+	a.maps = a.maps[:len(a.maps)+1]
+	return &a.maps[len(a.maps)-1]
+}
+func (a *Arena) AllocList() *ListNode {
+	if len(a.lists) == cap(a.lists) {
+		a.lists = make([]plainList, 0, a.chunkSize)
+	}
+	a.lists = a.lists[:len(a.lists)+1]
+	return &a.lists[len(a.lists)-1]
+}
+func (a *Arena) AllocBool() *BoolNode {
+	if len(a.bools) == cap(a.bools) {
+		a.bools = make([]plainBool, 0, a.chunkSize)
+	}
+	a.bools = a.bools[:len(a.bools)+1]
+	return &a.bools[len(a.bools)-1]
+}
+func (a *Arena) AllocInt() *IntNode {
+	if len(a.ints) == cap(a.ints) {
+		a.ints = make([]plainInt, 0, a.chunkSize)
+	}
+	a.ints = a.ints[:len(a.ints)+1]
+	return &a.ints[len(a.ints)-1]
+}
+func (a *Arena) AllocFloat() *FloatNode {
+	if len(a.floats) == cap(a.floats) {
+		a.floats = make([]plainFloat, 0, a.chunkSize)
+	}
+	a.floats = a.floats[:len(a.floats)+1]
+	return &a.floats[len(a.floats)-1]
+}
+func (a *Arena) AllocString() *StringNode {
+	if len(a.strings) == cap(a.strings) {
+		a.strings = make([]plainString, 0, a.chunkSize)
+	}
+	a.strings = a.strings[:len(a.strings)+1]
+	return &a.strings[len(a.strings)-1]
+}
+func (a *Arena) AllocBytes() *BytesNode {
+	if len(a.bytes) == cap(a.bytes) {
+		a.bytes = make([]plainBytes, 0, a.chunkSize)
+	}
+	a.bytes = a.bytes[:len(a.bytes)+1]
+	return &a.bytes[len(a.bytes)-1]
+}
+func (a *Arena) AllocLink() *LinkNode {
+	if len(a.links) == cap(a.links) {
+		a.links = make([]plainLink, 0, a.chunkSize)
+	}
+	a.links = a.links[:len(a.links)+1]
+	return &a.links[len(a.links)-1]
+}
@@ -0,0 +1,73 @@
+package basicnode
+
+import (
+	"testing"
+
+	. "github.com/warpfork/go-wish"
+
+	ipld "github.com/ipld/go-ipld-prime"
+	"github.com/ipld/go-ipld-prime/fluent"
+	"github.com/ipld/go-ipld-prime/node/tests"
+)
+
+func TestStyleWithAllocator(t *testing.T) {
+	tests.SpecTestMapStrInt(t, NewStyleWithAllocator(NewArena(0)))
+	tests.SpecTestMapStrMapStrInt(t, NewStyleWithAllocator(NewArena(0)))
+
+	// A tiny chunk size, so that building this spans several chunks of each kind.
+	build := func(ns ipld.NodeStyle) ipld.Node {
+		return fluent.MustBuildMap(ns, 3, func(na fluent.MapAssembler) {
+			na.AssembleEntry("ints").CreateList(5, func(na fluent.ListAssembler) {
+				for i := 0; i < 5; i++ {
+					na.AssembleValue().AssignInt(i)
+				}
+			})
+			na.AssembleEntry("maps").CreateList(3, func(na fluent.ListAssembler) {
+				for _, s := range []string{"a", "b", "c"} {
+					na.AssembleValue().CreateMap(2, func(na fluent.MapAssembler) {
+						na.AssembleEntry("s").AssignString(s)
+						na.AssembleEntry("b").AssignBytes([]byte(s))
+					})
+				}
+			})
+			na.AssembleEntry("f").AssignFloat(1.5)
+		})
+	}
+	ns := NewStyleWithAllocator(NewArena(2))
+	n := build(ns)
+	Wish(t, ipld.DeepEqual(n, build(Style__Any{})), ShouldEqual, true)
+	Wish(t, ipld.Sprint(n), ShouldEqual, `{"ints": [0, 1, 2, 3, 4], "maps": [{"s": "a", "b": bytes(61)}, {"s": "b", "b": bytes(62)}, {"s": "c", "b": bytes(63)}], "f": 1.5}`)
+
+	t.Run("builders keep the allocator across Reset", func(t *testing.T) {
+		nb := ns.NewBuilder()
+		Wish(t, nb.Style(), ShouldEqual, ns)
+		Require(t, nb.AssignInt(1), ShouldEqual, nil)
+		nb.Reset()
+		Wish(t, nb.Style(), ShouldEqual, ns)
+		Require(t, nb.AssignString("x"), ShouldEqual, nil)
+		Wish(t, nb.Build(), ShouldEqual, NewString("x"))
+	})
+}
+
+// countingAllocator is an Allocator from outside the Arena: it wraps one, counting the maps.
+type countingAllocator struct {
+	*Arena
+	maps int
+}
+
+func (a *countingAllocator) AllocMap() *MapNode {
+	a.maps++
+	return a.Arena.AllocMap()
+}
+
+func TestCustomAllocator(t *testing.T) {
+	alloc := &countingAllocator{Arena: NewArena(0)}
+	n := fluent.MustBuildMap(NewStyleWithAllocator(alloc), 2, func(na fluent.MapAssembler) {
+		na.AssembleEntry("a").CreateMap(1, func(na fluent.MapAssembler) {
+			na.AssembleEntry("b").AssignInt(1)
+		})
+		na.AssembleEntry("c").AssignString("d")
+	})
+	Wish(t, alloc.maps, ShouldEqual, 2)
+	Wish(t, ipld.Sprint(n), ShouldEqual, `{"a": {"b": 1}, "c": "d"}`)
+}
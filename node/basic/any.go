@@ -35,10 +35,14 @@ var (
 
 // -- NodeStyle -->
 
-type Style__Any struct{}
+// Style__Any builds nodes of any kind.
+type Style__Any struct {
+	ErrorOnMisuse   bool // as for Style__Map.
+	MaxScalarLength int  // as for Style__Map.
+}
 
-func (Style__Any) NewBuilder() ipld.NodeBuilder {
-	return &anyBuilder{}
+func (ns Style__Any) NewBuilder() ipld.NodeBuilder {
+	return &anyBuilder{errorOnMisuse: ns.ErrorOnMisuse, maxScalarLength: ns.MaxScalarLength}
 }
 
 // -- NodeBuilder -->
@@ -71,99 +75,160 @@ type anyBuilder struct {
 	mapBuilder  plainMap__Builder
 	listBuilder plainList__Builder
 	scalarNode  ipld.Node
+
+	built bool // set by Build; see HACKME.md.
+
+	// alloc is where new nodes come from, if this builder is from NewStyleWithAllocator; otherwise nil.
+	alloc Allocator
+
+	errorOnMisuse   bool // see Style__Map.
+	maxScalarLength int  // see Style__Map.
 }
 
 func (nb *anyBuilder) Reset() {
-	*nb = anyBuilder{}
+	*nb = anyBuilder{alloc: nb.alloc, errorOnMisuse: nb.errorOnMisuse, maxScalarLength: nb.maxScalarLength}
 }
 
 func (nb *anyBuilder) BeginMap(sizeHint int) (ipld.MapAssembler, error) {
+	if nb.built {
+		return nil, ipld.ErrBuilderConsumed{"BeginMap"}
+	}
 	if nb.kind != ipld.ReprKind_Invalid {
-		panic("misuse")
+		return nil, misuse(nb.errorOnMisuse, "begun", "BeginMap")
 	}
 	nb.kind = ipld.ReprKind_Map
-	nb.mapBuilder.w = &plainMap{}
+	nb.mapBuilder.w = newMap(nb.alloc)
+	nb.mapBuilder.alloc = nb.alloc
+	nb.mapBuilder.errorOnMisuse = nb.errorOnMisuse
+	nb.mapBuilder.maxScalarLength = nb.maxScalarLength
 	return nb.mapBuilder.BeginMap(sizeHint)
 }
 func (nb *anyBuilder) BeginList(sizeHint int) (ipld.ListAssembler, error) {
+	if nb.built {
+		return nil, ipld.ErrBuilderConsumed{"BeginList"}
+	}
 	if nb.kind != ipld.ReprKind_Invalid {
-		panic("misuse")
+		return nil, misuse(nb.errorOnMisuse, "begun", "BeginList")
 	}
 	nb.kind = ipld.ReprKind_List
-	nb.listBuilder.w = &plainList{}
+	nb.listBuilder.w = newList(nb.alloc)
+	nb.listBuilder.alloc = nb.alloc
+	nb.listBuilder.errorOnMisuse = nb.errorOnMisuse
+	nb.listBuilder.maxScalarLength = nb.maxScalarLength
 	return nb.listBuilder.BeginList(sizeHint)
 }
 func (nb *anyBuilder) AssignNull() error {
+	if nb.built {
+		return ipld.ErrBuilderConsumed{"AssignNull"}
+	}
 	if nb.kind != ipld.ReprKind_Invalid {
-		panic("misuse")
+		return misuse(nb.errorOnMisuse, "begun", "AssignNull")
 	}
 	nb.kind = ipld.ReprKind_Null
 	return nil
 }
 func (nb *anyBuilder) AssignBool(v bool) error {
+	if nb.built {
+		return ipld.ErrBuilderConsumed{"AssignBool"}
+	}
 	if nb.kind != ipld.ReprKind_Invalid {
-		panic("misuse")
+		return misuse(nb.errorOnMisuse, "begun", "AssignBool")
 	}
 	nb.kind = ipld.ReprKind_Bool
-	nb.scalarNode = NewBool(v)
+	nb.scalarNode = newBool(nb.alloc, v)
 	return nil
 }
 func (nb *anyBuilder) AssignInt(v int) error {
+	if nb.built {
+		return ipld.ErrBuilderConsumed{"AssignInt"}
+	}
 	if nb.kind != ipld.ReprKind_Invalid {
-		panic("misuse")
+		return misuse(nb.errorOnMisuse, "begun", "AssignInt")
 	}
 	nb.kind = ipld.ReprKind_Int
-	nb.scalarNode = NewInt(v)
+	nb.scalarNode = newInt(nb.alloc, v)
 	return nil
 }
 func (nb *anyBuilder) AssignFloat(v float64) error {
+	if nb.built {
+		return ipld.ErrBuilderConsumed{"AssignFloat"}
+	}
 	if nb.kind != ipld.ReprKind_Invalid {
-		panic("misuse")
+		return misuse(nb.errorOnMisuse, "begun", "AssignFloat")
+	}
+	if err := checkFloat(v); err != nil {
+		return err
 	}
 	nb.kind = ipld.ReprKind_Float
-	nb.scalarNode = NewFloat(v)
+	nb.scalarNode = newFloat(nb.alloc, v)
 	return nil
 }
 func (nb *anyBuilder) AssignString(v string) error {
+	if nb.built {
+		return ipld.ErrBuilderConsumed{"AssignString"}
+	}
 	if nb.kind != ipld.ReprKind_Invalid {
-		panic("misuse")
+		return misuse(nb.errorOnMisuse, "begun", "AssignString")
+	}
+	if err := checkLength(nb.maxScalarLength, ipld.ReprKind_String, len(v)); err != nil {
+		return err
 	}
 	nb.kind = ipld.ReprKind_String
-	nb.scalarNode = NewString(v)
+	nb.scalarNode = newString(nb.alloc, v)
 	return nil
 }
 func (nb *anyBuilder) AssignBytes(v []byte) error {
+	if nb.built {
+		return ipld.ErrBuilderConsumed{"AssignBytes"}
+	}
 	if nb.kind != ipld.ReprKind_Invalid {
-		panic("misuse")
+		return misuse(nb.errorOnMisuse, "begun", "AssignBytes")
+	}
+	if err := checkLength(nb.maxScalarLength, ipld.ReprKind_Bytes, len(v)); err != nil {
+		return err
 	}
 	nb.kind = ipld.ReprKind_Bytes
-	nb.scalarNode = NewBytes(v)
+	nb.scalarNode = newBytes(nb.alloc, v)
 	return nil
 }
 func (nb *anyBuilder) AssignLink(v ipld.Link) error {
+	if nb.built {
+		return ipld.ErrBuilderConsumed{"AssignLink"}
+	}
 	if nb.kind != ipld.ReprKind_Invalid {
-		panic("misuse")
+		return misuse(nb.errorOnMisuse, "begun", "AssignLink")
 	}
 	nb.kind = ipld.ReprKind_Link
-	nb.scalarNode = NewLink(v)
+	nb.scalarNode = newLink(nb.alloc, v)
 	return nil
 }
 func (nb *anyBuilder) AssignNode(v ipld.Node) error {
+	if nb.built {
+		return ipld.ErrBuilderConsumed{"AssignNode"}
+	}
 	if nb.kind != ipld.ReprKind_Invalid {
-		panic("misuse")
+		return misuse(nb.errorOnMisuse, "begun", "AssignNode")
+	}
+	if err := checkFloatNode(v); err != nil {
+		return err
 	}
 	nb.kind = 99
 	nb.scalarNode = v
 	return nil
 }
-func (anyBuilder) Style() ipld.NodeStyle {
-	return Style__Any{}
+func (nb *anyBuilder) Style() ipld.NodeStyle {
+	if nb.alloc != nil {
+		return allocatingStyle{nb.alloc}
+	}
+	return Style__Any{nb.errorOnMisuse, nb.maxScalarLength}
 }
 
 func (nb *anyBuilder) Build() ipld.Node {
-	switch nb.kind {
-	case ipld.ReprKind_Invalid:
+	if nb.kind == ipld.ReprKind_Invalid {
 		panic("misuse")
+	}
+	nb.built = true
+	switch nb.kind {
 	case ipld.ReprKind_Map:
 		return nb.mapBuilder.Build()
 	case ipld.ReprKind_List:
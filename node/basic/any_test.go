@@ -17,3 +17,16 @@ func TestAnyBeingMapStrInt(t *testing.T) {
 func TestAnyBeingMapStrMapStrInt(t *testing.T) {
 	tests.SpecTestMapStrMapStrInt(t, Style__Any{})
 }
+
+func TestAnyBeingList(t *testing.T) {
+	tests.SpecTestListAssembler(t, Style__Any{})
+}
+
+func TestAnyConformance(t *testing.T) {
+	tests.SpecTestConformance(t, Style__Any{}, []string{
+		`{"a": 1, "b": "two", "c": [3, 4.5, null, true], "d": {"e": {}, "f": []}}`,
+		`[{"x": 1}, [2, [3]], "four", false]`,
+		`{}`,
+		`[]`,
+	})
+}
@@ -13,3 +13,11 @@ func BenchmarkSpec_Walk_Map3StrInt(b *testing.B) {
 func BenchmarkSpec_Walk_MapNStrMap3StrInt(b *testing.B) {
 	tests.BenchmarkSpec_Walk_MapNStrMap3StrInt(b, Style__Any{})
 }
+
+func BenchmarkSpec_Unmarshal_MapNStrMap3StrInt_Any(b *testing.B) {
+	tests.BenchmarkSpec_Unmarshal_MapNStrMap3StrInt(b, Style__Any{})
+}
+
+func BenchmarkSpec_Unmarshal_MapNStrMap3StrInt_Arena(b *testing.B) {
+	tests.BenchmarkSpec_Unmarshal_MapNStrMap3StrInt(b, NewStyleWithAllocator(NewArena(0)))
+}
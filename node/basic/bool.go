@@ -90,6 +90,7 @@ type plainBool__Builder struct {
 }
 
 func (nb *plainBool__Builder) Build() ipld.Node {
+	nb.built = true // we mustn't mutate the node we hand out; see HACKME.md.
 	return nb.w
 }
 func (nb *plainBool__Builder) Reset() {
@@ -100,7 +101,8 @@ func (nb *plainBool__Builder) Reset() {
 // -- NodeAssembler -->
 
 type plainBool__Assembler struct {
-	w *plainBool
+	w     *plainBool
+	built bool // set by Build, after which w belongs to the node it returned; see HACKME.md.
 }
 
 func (plainBool__Assembler) BeginMap(sizeHint int) (ipld.MapAssembler, error) {
@@ -113,6 +115,9 @@ func (plainBool__Assembler) AssignNull() error {
 	return mixins.BoolAssembler{"bool"}.AssignNull()
 }
 func (na *plainBool__Assembler) AssignBool(v bool) error {
+	if na.built {
+		return ipld.ErrBuilderConsumed{"AssignBool"}
+	}
 	*na.w = plainBool(v)
 	return nil
 }
@@ -132,6 +137,9 @@ func (plainBool__Assembler) AssignLink(ipld.Link) error {
 	return mixins.BoolAssembler{"bool"}.AssignLink(nil)
 }
 func (na *plainBool__Assembler) AssignNode(v ipld.Node) error {
+	if na.built {
+		return ipld.ErrBuilderConsumed{"AssignNode"}
+	}
 	if v2, err := v.AsBool(); err != nil {
 		return err
 	} else {
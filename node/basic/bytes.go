@@ -76,11 +76,17 @@ func (plainBytes) Style() ipld.NodeStyle {
 
 // -- NodeStyle -->
 
-type Style__Bytes struct{}
+// Style__Bytes builds bytes nodes.
+//
+// MaxScalarLength, if positive, is the longest value AssignBytes accepts,
+// as for Style__Map.
+type Style__Bytes struct {
+	MaxScalarLength int
+}
 
-func (Style__Bytes) NewBuilder() ipld.NodeBuilder {
+func (ns Style__Bytes) NewBuilder() ipld.NodeBuilder {
 	var w plainBytes
-	return &plainBytes__Builder{plainBytes__Assembler{w: &w}}
+	return &plainBytes__Builder{plainBytes__Assembler{w: &w, maxScalarLength: ns.MaxScalarLength}}
 }
 
 // -- NodeBuilder -->
@@ -90,17 +96,21 @@ type plainBytes__Builder struct {
 }
 
 func (nb *plainBytes__Builder) Build() ipld.Node {
+	nb.built = true // we mustn't mutate the node we hand out; see HACKME.md.
 	return nb.w
 }
 func (nb *plainBytes__Builder) Reset() {
 	var w plainBytes
-	*nb = plainBytes__Builder{plainBytes__Assembler{w: &w}}
+	*nb = plainBytes__Builder{plainBytes__Assembler{w: &w, maxScalarLength: nb.maxScalarLength}}
 }
 
 // -- NodeAssembler -->
 
 type plainBytes__Assembler struct {
-	w *plainBytes
+	w     *plainBytes
+	built bool // set by Build, after which w belongs to the node it returned; see HACKME.md.
+
+	maxScalarLength int
 }
 
 func (plainBytes__Assembler) BeginMap(sizeHint int) (ipld.MapAssembler, error) {
@@ -125,6 +135,12 @@ func (plainBytes__Assembler) AssignString(string) error {
 	return mixins.BytesAssembler{"bytes"}.AssignString("")
 }
 func (na *plainBytes__Assembler) AssignBytes(v []byte) error {
+	if na.built {
+		return ipld.ErrBuilderConsumed{"AssignBytes"}
+	}
+	if err := checkLength(na.maxScalarLength, ipld.ReprKind_Bytes, len(v)); err != nil {
+		return err
+	}
 	*na.w = plainBytes(v)
 	return nil
 }
@@ -132,13 +148,15 @@ func (plainBytes__Assembler) AssignLink(ipld.Link) error {
 	return mixins.BytesAssembler{"bytes"}.AssignLink(nil)
 }
 func (na *plainBytes__Assembler) AssignNode(v ipld.Node) error {
+	if na.built {
+		return ipld.ErrBuilderConsumed{"AssignNode"}
+	}
 	if v2, err := v.AsBytes(); err != nil {
 		return err
 	} else {
-		*na.w = plainBytes(v2)
-		return nil
+		return na.AssignBytes(v2)
 	}
 }
-func (plainBytes__Assembler) Style() ipld.NodeStyle {
-	return Style__Bytes{}
+func (na plainBytes__Assembler) Style() ipld.NodeStyle {
+	return Style__Bytes{na.maxScalarLength}
 }
@@ -0,0 +1,225 @@
+package basicnode
+
+import (
+	"io"
+
+	ipld "github.com/ipld/go-ipld-prime"
+	"github.com/ipld/go-ipld-prime/node/mixins"
+)
+
+var (
+	_ ipld.Node                      = &plainChunkedBytes{}
+	_ ipld.NodeSupportingBytesStream = &plainChunkedBytes{}
+	_ ipld.NodeStyle                 = Style__ChunkedBytes{}
+	_ ipld.NodeBuilder               = &plainChunkedBytes__Builder{}
+	_ ipld.NodeAssembler             = &plainChunkedBytes__Assembler{}
+)
+
+// plainChunkedBytes is a bytes-kind ipld.Node which holds its content
+// as a sequence of chunks rather than one contiguous slice.
+//
+// It's a stand-in for the sort of large-bytes ADL that would load chunks
+// from separate blocks: it supports ipld.NodeSupportingBytesStream,
+// so reading it sequentially never needs to concatenate the chunks.
+// AsBytes still works, but has to allocate and copy everything.
+type plainChunkedBytes struct {
+	chunks [][]byte
+	length int
+}
+
+// -- Node interface methods -->
+
+func (plainChunkedBytes) ReprKind() ipld.ReprKind {
+	return ipld.ReprKind_Bytes
+}
+func (plainChunkedBytes) LookupString(string) (ipld.Node, error) {
+	return mixins.Bytes{"bytes"}.LookupString("")
+}
+func (plainChunkedBytes) Lookup(key ipld.Node) (ipld.Node, error) {
+	return mixins.Bytes{"bytes"}.Lookup(nil)
+}
+func (plainChunkedBytes) LookupIndex(idx int) (ipld.Node, error) {
+	return mixins.Bytes{"bytes"}.LookupIndex(0)
+}
+func (plainChunkedBytes) LookupSegment(seg ipld.PathSegment) (ipld.Node, error) {
+	return mixins.Bytes{"bytes"}.LookupSegment(seg)
+}
+func (plainChunkedBytes) MapIterator() ipld.MapIterator {
+	return nil
+}
+func (plainChunkedBytes) ListIterator() ipld.ListIterator {
+	return nil
+}
+func (plainChunkedBytes) Length() int {
+	return -1
+}
+func (plainChunkedBytes) IsUndefined() bool {
+	return false
+}
+func (plainChunkedBytes) IsNull() bool {
+	return false
+}
+func (plainChunkedBytes) AsBool() (bool, error) {
+	return mixins.Bytes{"bytes"}.AsBool()
+}
+func (plainChunkedBytes) AsInt() (int, error) {
+	return mixins.Bytes{"bytes"}.AsInt()
+}
+func (plainChunkedBytes) AsFloat() (float64, error) {
+	return mixins.Bytes{"bytes"}.AsFloat()
+}
+func (plainChunkedBytes) AsString() (string, error) {
+	return mixins.Bytes{"bytes"}.AsString()
+}
+func (n *plainChunkedBytes) AsBytes() ([]byte, error) {
+	if len(n.chunks) == 1 {
+		return n.chunks[0], nil
+	}
+	v := make([]byte, 0, n.length)
+	for _, chunk := range n.chunks {
+		v = append(v, chunk...)
+	}
+	return v, nil
+}
+func (plainChunkedBytes) AsLink() (ipld.Link, error) {
+	return mixins.Bytes{"bytes"}.AsLink()
+}
+func (plainChunkedBytes) Style() ipld.NodeStyle {
+	return Style__ChunkedBytes{}
+}
+
+// -- NodeSupportingBytesStream -->
+
+func (n *plainChunkedBytes) AsBytesStream() (io.Reader, error) {
+	return &plainChunkedBytes_Reader{chunks: n.chunks}, nil
+}
+func (n *plainChunkedBytes) ByteLength() int {
+	return n.length
+}
+
+// plainChunkedBytes_Reader reads through the chunks in order.
+// It also implements io.WriterTo, so that io.Copy can hand each chunk
+// straight to the destination without copying it into a buffer first.
+type plainChunkedBytes_Reader struct {
+	chunks [][]byte
+	idx    int // which chunk we're in.
+	off    int // offset within that chunk.
+}
+
+func (r *plainChunkedBytes_Reader) Read(p []byte) (int, error) {
+	n := 0
+	for n < len(p) {
+		if r.idx >= len(r.chunks) {
+			break
+		}
+		c := copy(p[n:], r.chunks[r.idx][r.off:])
+		n += c
+		r.off += c
+		if r.off >= len(r.chunks[r.idx]) {
+			r.idx++
+			r.off = 0
+		}
+	}
+	if n == 0 && len(p) > 0 {
+		return 0, io.EOF
+	}
+	return n, nil
+}
+func (r *plainChunkedBytes_Reader) WriteTo(w io.Writer) (int64, error) {
+	var total int64
+	for ; r.idx < len(r.chunks); r.idx++ {
+		n, err := w.Write(r.chunks[r.idx][r.off:])
+		total += int64(n)
+		if err != nil {
+			r.off += n
+			return total, err
+		}
+		r.off = 0
+	}
+	return total, nil
+}
+
+// -- NodeStyle -->
+
+// Style__ChunkedBytes builds bytes nodes which keep their content in chunks.
+//
+// Unlike other scalar assemblers, its assembler accepts AssignBytes
+// repeatedly: each call appends another chunk.
+// The chunks are retained, not copied, so they must not be mutated afterwards.
+type Style__ChunkedBytes struct{}
+
+func (Style__ChunkedBytes) NewBuilder() ipld.NodeBuilder {
+	return &plainChunkedBytes__Builder{plainChunkedBytes__Assembler{w: &plainChunkedBytes{}}}
+}
+
+// -- NodeBuilder -->
+
+type plainChunkedBytes__Builder struct {
+	plainChunkedBytes__Assembler
+}
+
+func (nb *plainChunkedBytes__Builder) Build() ipld.Node {
+	nb.built = true // we mustn't mutate the node we hand out; see HACKME.md.
+	return nb.w
+}
+func (nb *plainChunkedBytes__Builder) Reset() {
+	*nb = plainChunkedBytes__Builder{plainChunkedBytes__Assembler{w: &plainChunkedBytes{}}}
+}
+
+// -- NodeAssembler -->
+
+type plainChunkedBytes__Assembler struct {
+	w     *plainChunkedBytes
+	built bool // set by Build, after which w belongs to the node it returned; see HACKME.md.
+}
+
+func (plainChunkedBytes__Assembler) BeginMap(sizeHint int) (ipld.MapAssembler, error) {
+	return mixins.BytesAssembler{"bytes"}.BeginMap(0)
+}
+func (plainChunkedBytes__Assembler) BeginList(sizeHint int) (ipld.ListAssembler, error) {
+	return mixins.BytesAssembler{"bytes"}.BeginList(0)
+}
+func (plainChunkedBytes__Assembler) AssignNull() error {
+	return mixins.BytesAssembler{"bytes"}.AssignNull()
+}
+func (plainChunkedBytes__Assembler) AssignBool(bool) error {
+	return mixins.BytesAssembler{"bytes"}.AssignBool(false)
+}
+func (plainChunkedBytes__Assembler) AssignInt(int) error {
+	return mixins.BytesAssembler{"bytes"}.AssignInt(0)
+}
+func (plainChunkedBytes__Assembler) AssignFloat(float64) error {
+	return mixins.BytesAssembler{"bytes"}.AssignFloat(0)
+}
+func (plainChunkedBytes__Assembler) AssignString(string) error {
+	return mixins.BytesAssembler{"bytes"}.AssignString("")
+}
+func (na *plainChunkedBytes__Assembler) AssignBytes(v []byte) error {
+	if na.built {
+		return ipld.ErrBuilderConsumed{"AssignBytes"}
+	}
+	na.w.chunks = append(na.w.chunks, v)
+	na.w.length += len(v)
+	return nil
+}
+func (plainChunkedBytes__Assembler) AssignLink(ipld.Link) error {
+	return mixins.BytesAssembler{"bytes"}.AssignLink(nil)
+}
+func (na *plainChunkedBytes__Assembler) AssignNode(v ipld.Node) error {
+	if na.built {
+		return ipld.ErrBuilderConsumed{"AssignNode"}
+	}
+	if v2, ok := v.(*plainChunkedBytes); ok {
+		na.w.chunks = append(na.w.chunks, v2.chunks...)
+		na.w.length += v2.length
+		return nil
+	}
+	if v2, err := v.AsBytes(); err != nil {
+		return err
+	} else {
+		return na.AssignBytes(v2)
+	}
+}
+func (plainChunkedBytes__Assembler) Style() ipld.NodeStyle {
+	return Style__ChunkedBytes{}
+}
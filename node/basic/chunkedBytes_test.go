@@ -0,0 +1,97 @@
+package basicnode
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"testing"
+
+	. "github.com/warpfork/go-wish"
+
+	ipld "github.com/ipld/go-ipld-prime"
+)
+
+func TestChunkedBytes(t *testing.T) {
+	nb := Style__ChunkedBytes{}.NewBuilder()
+	Wish(t, nb.AssignBytes([]byte("abc")), ShouldEqual, nil)
+	Wish(t, nb.AssignBytes([]byte("")), ShouldEqual, nil)
+	Wish(t, nb.AssignBytes([]byte("defgh")), ShouldEqual, nil)
+	n := nb.Build()
+
+	Wish(t, n.ReprKind(), ShouldEqual, ipld.ReprKind_Bytes)
+	Wish(t, n.Length(), ShouldEqual, -1)
+	Wish(t, n.(ipld.NodeSupportingBytesStream).ByteLength(), ShouldEqual, 8)
+	t.Run("AsBytes concatenates", func(t *testing.T) {
+		v, err := n.AsBytes()
+		Wish(t, err, ShouldEqual, nil)
+		Wish(t, string(v), ShouldEqual, "abcdefgh")
+	})
+	t.Run("AsBytesStream reads sequentially", func(t *testing.T) {
+		r, err := ipld.AsBytesStream(n)
+		Require(t, err, ShouldEqual, nil)
+		buf := make([]byte, 2)
+		var got []string
+		for {
+			c, err := r.Read(buf)
+			if err == io.EOF {
+				break
+			}
+			Require(t, err, ShouldEqual, nil)
+			got = append(got, string(buf[:c]))
+		}
+		Wish(t, got, ShouldEqual, []string{"ab", "cd", "ef", "gh"})
+	})
+	t.Run("AsBytesStream supports WriteTo", func(t *testing.T) {
+		r, _ := ipld.AsBytesStream(n)
+		var buf bytes.Buffer
+		c, err := io.Copy(&buf, r)
+		Wish(t, err, ShouldEqual, nil)
+		Wish(t, c, ShouldEqual, int64(8))
+		Wish(t, buf.String(), ShouldEqual, "abcdefgh")
+	})
+	t.Run("AsBytesStream falls back for plain bytes", func(t *testing.T) {
+		r, err := ipld.AsBytesStream(NewBytes([]byte("xyz")))
+		Require(t, err, ShouldEqual, nil)
+		v, _ := ioutil.ReadAll(r)
+		Wish(t, string(v), ShouldEqual, "xyz")
+	})
+}
+
+const benchChunkedBytesSize = 100 << 20 // 100MB, in 1MB chunks.
+
+func buildBenchChunkedBytes() ipld.Node {
+	chunk := bytes.Repeat([]byte{'x'}, 1<<20)
+	nb := Style__ChunkedBytes{}.NewBuilder()
+	for i := 0; i < benchChunkedBytesSize/len(chunk); i++ {
+		nb.AssignBytes(chunk)
+	}
+	return nb.Build()
+}
+
+func BenchmarkChunkedBytes_100MB_AsBytes(b *testing.B) {
+	n := buildBenchChunkedBytes()
+	b.SetBytes(benchChunkedBytesSize)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		v, err := n.AsBytes()
+		if err != nil {
+			b.Fatal(err)
+		}
+		ioutil.Discard.Write(v)
+	}
+}
+
+func BenchmarkChunkedBytes_100MB_AsBytesStream(b *testing.B) {
+	n := buildBenchChunkedBytes()
+	b.SetBytes(benchChunkedBytesSize)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		r, err := ipld.AsBytesStream(n)
+		if err != nil {
+			b.Fatal(err)
+		}
+		if _, err := io.Copy(ioutil.Discard, r); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
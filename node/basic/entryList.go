@@ -0,0 +1,219 @@
+package basicnode
+
+import (
+	ipld "github.com/ipld/go-ipld-prime"
+	"github.com/ipld/go-ipld-prime/node/mixins"
+)
+
+var (
+	_ ipld.Node = &entryList{}
+	_ ipld.Node = &entryPair{}
+)
+
+// AsEntryList presents a map as a list of its entries, in the map's iteration order.
+// Each element of the list is itself a two-element list: [key, value].
+// This is useful for canonical forms which need a stable order, or which can't have maps at all.
+//
+// The result is a view of the map, not a copy: it holds only the map,
+// and each pair is made when it's reached, holding the map's own key and value nodes.
+// Iterating it is as cheap as iterating the map.  LookupIndex is too,
+// for maps from this package; for other maps, it has to iterate up to the index.
+// The node must be a map; otherwise ErrWrongKind is returned.
+func AsEntryList(n ipld.Node) (ipld.Node, error) {
+	if n.ReprKind() != ipld.ReprKind_Map {
+		return nil, ipld.ErrWrongKind{MethodName: "basicnode.AsEntryList", AppropriateKind: ipld.ReprKindSet_JustMap, ActualKind: n.ReprKind()}
+	}
+	return &entryList{n}, nil
+}
+
+// entryList is the list-kind view of a map which AsEntryList returns.
+type entryList struct {
+	m ipld.Node
+}
+
+// -- Node interface methods -->
+
+func (entryList) ReprKind() ipld.ReprKind {
+	return ipld.ReprKind_List
+}
+func (entryList) LookupString(string) (ipld.Node, error) {
+	return mixins.List{"list"}.LookupString("")
+}
+func (entryList) Lookup(ipld.Node) (ipld.Node, error) {
+	return mixins.List{"list"}.Lookup(nil)
+}
+func (n *entryList) LookupIndex(idx int) (ipld.Node, error) {
+	if idx < 0 || n.Length() <= idx {
+		return nil, ipld.ErrNotExists{ipld.PathSegmentOfInt(idx)}
+	}
+	// Our own maps have an entry table we can index directly.
+	switch m := n.m.(type) {
+	case *plainMap:
+		return &entryPair{m.t[idx].key(), m.t[idx].v}, nil
+	case *plainSortedMap:
+		return &entryPair{m.t[idx].key(), m.t[idx].v}, nil
+	}
+	// Any other map, we have to iterate to get there.
+	itr := n.m.MapIterator()
+	for i := 0; ; i++ {
+		k, v, err := itr.Next()
+		if err != nil {
+			return nil, err
+		}
+		if i == idx {
+			return &entryPair{k, v}, nil
+		}
+	}
+}
+func (n *entryList) LookupSegment(seg ipld.PathSegment) (ipld.Node, error) {
+	idx, err := seg.Index()
+	if err != nil {
+		return nil, ipld.ErrNotExists{seg} // a segment that isn't a number can't be in a list.
+	}
+	return n.LookupIndex(idx)
+}
+func (entryList) MapIterator() ipld.MapIterator {
+	return nil
+}
+func (n *entryList) ListIterator() ipld.ListIterator {
+	return &entryList_ListIterator{n.m.MapIterator(), 0}
+}
+func (n *entryList) Length() int {
+	return n.m.Length()
+}
+func (entryList) IsUndefined() bool {
+	return false
+}
+func (entryList) IsNull() bool {
+	return false
+}
+func (entryList) AsBool() (bool, error) {
+	return mixins.List{"list"}.AsBool()
+}
+func (entryList) AsInt() (int, error) {
+	return mixins.List{"list"}.AsInt()
+}
+func (entryList) AsFloat() (float64, error) {
+	return mixins.List{"list"}.AsFloat()
+}
+func (entryList) AsString() (string, error) {
+	return mixins.List{"list"}.AsString()
+}
+func (entryList) AsBytes() ([]byte, error) {
+	return mixins.List{"list"}.AsBytes()
+}
+func (entryList) AsLink() (ipld.Link, error) {
+	return mixins.List{"list"}.AsLink()
+}
+func (entryList) Style() ipld.NodeStyle {
+	return Style__List{}
+}
+
+type entryList_ListIterator struct {
+	itr ipld.MapIterator
+	idx int
+}
+
+func (itr *entryList_ListIterator) Next() (idx int, v ipld.Node, _ error) {
+	if itr.Done() {
+		return -1, nil, ipld.ErrIteratorOverread{}
+	}
+	k, v, err := itr.itr.Next()
+	if err != nil {
+		return -1, nil, err
+	}
+	idx = itr.idx
+	itr.idx++
+	return idx, &entryPair{k, v}, nil
+}
+func (itr *entryList_ListIterator) Done() bool {
+	return itr.itr.Done()
+}
+
+// entryPair is an element of an entryList: the two-element list [k, v].
+type entryPair struct {
+	k, v ipld.Node
+}
+
+// -- Node interface methods -->
+
+func (entryPair) ReprKind() ipld.ReprKind {
+	return ipld.ReprKind_List
+}
+func (entryPair) LookupString(string) (ipld.Node, error) {
+	return mixins.List{"list"}.LookupString("")
+}
+func (entryPair) Lookup(ipld.Node) (ipld.Node, error) {
+	return mixins.List{"list"}.Lookup(nil)
+}
+func (n *entryPair) LookupIndex(idx int) (ipld.Node, error) {
+	switch idx {
+	case 0:
+		return n.k, nil
+	case 1:
+		return n.v, nil
+	default:
+		return nil, ipld.ErrNotExists{ipld.PathSegmentOfInt(idx)}
+	}
+}
+func (n *entryPair) LookupSegment(seg ipld.PathSegment) (ipld.Node, error) {
+	idx, err := seg.Index()
+	if err != nil {
+		return nil, ipld.ErrNotExists{seg} // a segment that isn't a number can't be in a list.
+	}
+	return n.LookupIndex(idx)
+}
+func (entryPair) MapIterator() ipld.MapIterator {
+	return nil
+}
+func (n *entryPair) ListIterator() ipld.ListIterator {
+	return &entryPair_ListIterator{n, 0}
+}
+func (entryPair) Length() int {
+	return 2
+}
+func (entryPair) IsUndefined() bool {
+	return false
+}
+func (entryPair) IsNull() bool {
+	return false
+}
+func (entryPair) AsBool() (bool, error) {
+	return mixins.List{"list"}.AsBool()
+}
+func (entryPair) AsInt() (int, error) {
+	return mixins.List{"list"}.AsInt()
+}
+func (entryPair) AsFloat() (float64, error) {
+	return mixins.List{"list"}.AsFloat()
+}
+func (entryPair) AsString() (string, error) {
+	return mixins.List{"list"}.AsString()
+}
+func (entryPair) AsBytes() ([]byte, error) {
+	return mixins.List{"list"}.AsBytes()
+}
+func (entryPair) AsLink() (ipld.Link, error) {
+	return mixins.List{"list"}.AsLink()
+}
+func (entryPair) Style() ipld.NodeStyle {
+	return Style__List{}
+}
+
+type entryPair_ListIterator struct {
+	n   *entryPair
+	idx int
+}
+
+func (itr *entryPair_ListIterator) Next() (idx int, v ipld.Node, _ error) {
+	if itr.Done() {
+		return -1, nil, ipld.ErrIteratorOverread{}
+	}
+	v, _ = itr.n.LookupIndex(itr.idx)
+	idx = itr.idx
+	itr.idx++
+	return
+}
+func (itr *entryPair_ListIterator) Done() bool {
+	return itr.idx >= 2
+}
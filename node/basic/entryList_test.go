@@ -0,0 +1,84 @@
+package basicnode
+
+import (
+	"testing"
+
+	. "github.com/warpfork/go-wish"
+
+	ipld "github.com/ipld/go-ipld-prime"
+	"github.com/ipld/go-ipld-prime/fluent"
+)
+
+func TestAsEntryList(t *testing.T) {
+	n := fluent.MustBuildMap(Style__Map{}, 3, func(na fluent.MapAssembler) {
+		na.AssembleEntry("zed").AssignInt(1)
+		na.AssembleEntry("alpha").CreateList(1, func(na fluent.ListAssembler) {
+			na.AssembleValue().AssignString("x")
+		})
+		na.AssembleEntry("mu").AssignNull()
+	})
+	l, err := AsEntryList(n)
+	Require(t, err, ShouldEqual, nil)
+	Wish(t, ipld.Sprint(l), ShouldEqual, ipld.Sprint(fluent.MustBuildList(Style__List{}, 3, func(na fluent.ListAssembler) {
+		na.AssembleValue().CreateList(2, func(na fluent.ListAssembler) {
+			na.AssembleValue().AssignString("zed")
+			na.AssembleValue().AssignInt(1)
+		})
+		na.AssembleValue().CreateList(2, func(na fluent.ListAssembler) {
+			na.AssembleValue().AssignString("alpha")
+			na.AssembleValue().CreateList(1, func(na fluent.ListAssembler) {
+				na.AssembleValue().AssignString("x")
+			})
+		})
+		na.AssembleValue().CreateList(2, func(na fluent.ListAssembler) {
+			na.AssembleValue().AssignString("mu")
+			na.AssembleValue().AssignNull()
+		})
+	})))
+
+	t.Run("values are shared, not copied", func(t *testing.T) {
+		pair, _ := l.LookupIndex(1)
+		v, _ := pair.LookupIndex(1)
+		v2, _ := n.LookupString("alpha")
+		Wish(t, v == v2, ShouldEqual, true)
+	})
+	t.Run("is a view of the map", func(t *testing.T) {
+		Wish(t, l.(*entryList).m == n, ShouldEqual, true)
+		var keys []string
+		for itr := l.ListIterator(); !itr.Done(); {
+			_, pair, err := itr.Next()
+			Require(t, err, ShouldEqual, nil)
+			k, _ := pair.LookupIndex(0)
+			ks, _ := k.AsString()
+			keys = append(keys, ks)
+		}
+		Wish(t, keys, ShouldEqual, []string{"zed", "alpha", "mu"})
+	})
+	t.Run("lookups in maps from elsewhere", func(t *testing.T) {
+		l, err := AsEntryList(struct{ ipld.Node }{n}) // hides the type, as any other map implementation would.
+		Require(t, err, ShouldEqual, nil)
+		for i, want := range []string{"zed", "alpha", "mu"} {
+			pair, err := l.LookupIndex(i)
+			Require(t, err, ShouldEqual, nil)
+			k, _ := pair.LookupIndex(0)
+			Wish(t, k, ShouldEqual, NewString(want))
+		}
+		_, err = l.LookupIndex(3)
+		Wish(t, err, ShouldEqual, ipld.ErrNotExists{ipld.PathSegmentOfInt(3)})
+	})
+	t.Run("pairs have exactly two elements", func(t *testing.T) {
+		pair, _ := l.LookupIndex(0)
+		Wish(t, pair.Length(), ShouldEqual, 2)
+		_, err := pair.LookupIndex(2)
+		Wish(t, err, ShouldEqual, ipld.ErrNotExists{ipld.PathSegmentOfInt(2)})
+	})
+	t.Run("empty map", func(t *testing.T) {
+		l, err := AsEntryList(fluent.MustBuildMap(Style__Map{}, 0, func(na fluent.MapAssembler) {}))
+		Wish(t, err, ShouldEqual, nil)
+		Wish(t, l.Length(), ShouldEqual, 0)
+	})
+	t.Run("not a map", func(t *testing.T) {
+		_, err := AsEntryList(NewString("nope"))
+		Wish(t, err, ShouldBeSameTypeAs, ipld.ErrWrongKind{})
+	})
+}
@@ -0,0 +1,52 @@
+package basicnode
+
+import (
+	ipld "github.com/ipld/go-ipld-prime"
+)
+
+var (
+	_ ipld.NodeAssembler = errorAssembler{}
+)
+
+// errorAssembler is a NodeAssembler which returns the same error from every method.
+//
+// It's used where an interface method has to yield a NodeAssembler but has
+// no error return of its own (e.g. ListAssembler.AssembleValue):
+// the error is deferred until the caller tries to use the assembler.
+type errorAssembler struct {
+	err error
+}
+
+func (ea errorAssembler) BeginMap(int) (ipld.MapAssembler, error) {
+	return nil, ea.err
+}
+func (ea errorAssembler) BeginList(int) (ipld.ListAssembler, error) {
+	return nil, ea.err
+}
+func (ea errorAssembler) AssignNull() error {
+	return ea.err
+}
+func (ea errorAssembler) AssignBool(bool) error {
+	return ea.err
+}
+func (ea errorAssembler) AssignInt(int) error {
+	return ea.err
+}
+func (ea errorAssembler) AssignFloat(float64) error {
+	return ea.err
+}
+func (ea errorAssembler) AssignString(string) error {
+	return ea.err
+}
+func (ea errorAssembler) AssignBytes([]byte) error {
+	return ea.err
+}
+func (ea errorAssembler) AssignLink(ipld.Link) error {
+	return ea.err
+}
+func (ea errorAssembler) AssignNode(ipld.Node) error {
+	return ea.err
+}
+func (errorAssembler) Style() ipld.NodeStyle {
+	return Style__Any{}
+}
@@ -1,6 +1,8 @@
 package basicnode
 
 import (
+	"math"
+
 	ipld "github.com/ipld/go-ipld-prime"
 	"github.com/ipld/go-ipld-prime/node/mixins"
 )
@@ -76,11 +78,54 @@ func (plainFloat) Style() ipld.NodeStyle {
 
 // -- NodeStyle -->
 
-type Style__Float struct{}
+// Style__Float builds float nodes.
+//
+// By default, non-finite values (NaN, and positive or negative infinity)
+// are rejected by AssignFloat with ipld.ErrInvalidFloat,
+// because no codec we support can round-trip them.
+// Set AllowNonFinite for non-strict contexts where that doesn't matter.
+//
+// Also by default, ints are rejected (by AssignInt, and by AssignNode given an int node)
+// with ipld.ErrWrongKind, as for any other kind that isn't a float.
+// Set AllowIntWidening to accept them, converted to floats, for interop with
+// sources which don't distinguish the two (e.g. writing 1 for 1.0).
+// Ints which a float can't hold exactly (those beyond +/- 2^53, roughly)
+// are still rejected, with ipld.ErrInexactWidening.
+//
+// (Floats assembled as values inside maps, lists, or Style__Any always use the strict policy,
+// whether by AssignFloat or by AssignNode of a float node.  A rejected value
+// leaves the map or list as it was, so the caller may carry on.)
+type Style__Float struct {
+	AllowNonFinite   bool
+	AllowIntWidening bool
+}
 
-func (Style__Float) NewBuilder() ipld.NodeBuilder {
+func (ns Style__Float) NewBuilder() ipld.NodeBuilder {
 	var w plainFloat
-	return &plainFloat__Builder{plainFloat__Assembler{w: &w}}
+	return &plainFloat__Builder{plainFloat__Assembler{w: &w, allowNonFinite: ns.AllowNonFinite, allowIntWidening: ns.AllowIntWidening}}
+}
+
+// checkFloat implements the strict float policy: it returns ErrInvalidFloat for NaN and infinities.
+func checkFloat(v float64) error {
+	if math.IsNaN(v) || math.IsInf(v, 0) {
+		return ipld.ErrInvalidFloat{v}
+	}
+	return nil
+}
+
+// checkFloatNode applies checkFloat to this package's own float nodes
+// (which may hold a non-finite value, if built with AllowNonFinite).
+// Nodes of other implementations are taken as they are: even asking their kind
+// may be costly (a lazynode, for example, decodes itself to answer).
+func checkFloatNode(v ipld.Node) error {
+	switch v2 := v.(type) {
+	case *plainFloat:
+		return checkFloat(float64(*v2))
+	case plainFloat:
+		return checkFloat(float64(v2))
+	default:
+		return nil
+	}
 }
 
 // -- NodeBuilder -->
@@ -90,17 +135,22 @@ type plainFloat__Builder struct {
 }
 
 func (nb *plainFloat__Builder) Build() ipld.Node {
+	nb.built = true // we mustn't mutate the node we hand out; see HACKME.md.
 	return nb.w
 }
 func (nb *plainFloat__Builder) Reset() {
 	var w plainFloat
-	*nb = plainFloat__Builder{plainFloat__Assembler{w: &w}}
+	*nb = plainFloat__Builder{plainFloat__Assembler{w: &w, allowNonFinite: nb.allowNonFinite, allowIntWidening: nb.allowIntWidening}}
 }
 
 // -- NodeAssembler -->
 
 type plainFloat__Assembler struct {
-	w *plainFloat
+	w     *plainFloat
+	built bool // set by Build, after which w belongs to the node it returned; see HACKME.md.
+
+	allowNonFinite   bool
+	allowIntWidening bool
 }
 
 func (plainFloat__Assembler) BeginMap(sizeHint int) (ipld.MapAssembler, error) {
@@ -115,10 +165,31 @@ func (plainFloat__Assembler) AssignNull() error {
 func (plainFloat__Assembler) AssignBool(bool) error {
 	return mixins.FloatAssembler{"float"}.AssignBool(false)
 }
-func (plainFloat__Assembler) AssignInt(int) error {
-	return mixins.FloatAssembler{"float"}.AssignInt(0)
+func (na *plainFloat__Assembler) AssignInt(v int) error {
+	if na.built {
+		return ipld.ErrBuilderConsumed{"AssignInt"}
+	}
+	if !na.allowIntWidening {
+		return mixins.FloatAssembler{"float"}.AssignInt(0)
+	}
+	// A float64 holds ints exactly only up to 2^53; beyond that, check the conversion round-trips.
+	//  (float64(v) may round up to 2^63, which doesn't convert back to an int at all, hence the first check.)
+	f := float64(v)
+	if f >= math.MaxInt64 || int(f) != v {
+		return ipld.ErrInexactWidening{v}
+	}
+	*na.w = plainFloat(f)
+	return nil
 }
 func (na *plainFloat__Assembler) AssignFloat(v float64) error {
+	if na.built {
+		return ipld.ErrBuilderConsumed{"AssignFloat"}
+	}
+	if !na.allowNonFinite {
+		if err := checkFloat(v); err != nil {
+			return err
+		}
+	}
 	*na.w = plainFloat(v)
 	return nil
 }
@@ -132,13 +203,19 @@ func (plainFloat__Assembler) AssignLink(ipld.Link) error {
 	return mixins.FloatAssembler{"float"}.AssignLink(nil)
 }
 func (na *plainFloat__Assembler) AssignNode(v ipld.Node) error {
+	if na.allowIntWidening && v.ReprKind() == ipld.ReprKind_Int {
+		v2, err := v.AsInt()
+		if err != nil {
+			return err
+		}
+		return na.AssignInt(v2)
+	}
 	if v2, err := v.AsFloat(); err != nil {
 		return err
 	} else {
-		*na.w = plainFloat(v2)
-		return nil
+		return na.AssignFloat(v2)
 	}
 }
-func (plainFloat__Assembler) Style() ipld.NodeStyle {
-	return Style__Float{}
+func (na *plainFloat__Assembler) Style() ipld.NodeStyle {
+	return Style__Float{na.allowNonFinite, na.allowIntWidening}
 }
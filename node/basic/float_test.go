@@ -0,0 +1,96 @@
+package basicnode
+
+import (
+	"math"
+	"testing"
+
+	. "github.com/warpfork/go-wish"
+
+	ipld "github.com/ipld/go-ipld-prime"
+)
+
+func TestFloatNonFinite(t *testing.T) {
+	t.Run("strict by default", func(t *testing.T) {
+		for _, v := range []float64{math.NaN(), math.Inf(1), math.Inf(-1)} {
+			nb := Style__Float{}.NewBuilder()
+			Wish(t, nb.AssignFloat(v), ShouldBeSameTypeAs, ipld.ErrInvalidFloat{})
+		}
+		nb := Style__Float{}.NewBuilder()
+		Wish(t, nb.AssignFloat(1.5), ShouldEqual, nil)
+		Wish(t, mustFloat(nb.Build().AsFloat()), ShouldEqual, 1.5)
+	})
+	t.Run("strict inside recursives", func(t *testing.T) {
+		nb := Style__List{}.NewBuilder()
+		la, _ := nb.BeginList(1)
+		Wish(t, la.AssembleValue().AssignFloat(math.NaN()), ShouldBeSameTypeAs, ipld.ErrInvalidFloat{})
+		Wish(t, Style__Any{}.NewBuilder().AssignFloat(math.Inf(1)), ShouldBeSameTypeAs, ipld.ErrInvalidFloat{})
+	})
+	t.Run("strict for AssignNode too, and the parent stays usable", func(t *testing.T) {
+		nb := Style__Float{AllowNonFinite: true}.NewBuilder()
+		nb.AssignFloat(math.NaN())
+		nan := nb.Build()
+		Wish(t, Style__Any{}.NewBuilder().AssignNode(nan), ShouldBeSameTypeAs, ipld.ErrInvalidFloat{})
+
+		lb := Style__List{}.NewBuilder()
+		la, _ := lb.BeginList(2)
+		Wish(t, la.AssembleValue().AssignFloat(math.Inf(-1)), ShouldBeSameTypeAs, ipld.ErrInvalidFloat{})
+		Wish(t, la.AssembleValue().AssignNode(nan), ShouldBeSameTypeAs, ipld.ErrInvalidFloat{})
+		Wish(t, la.AssembleValue().AssignFloat(1.5), ShouldEqual, nil)
+		Wish(t, la.Finish(), ShouldEqual, nil)
+		Wish(t, ipld.Sprint(lb.Build()), ShouldEqual, `[1.5]`)
+
+		mb := Style__Map{}.NewBuilder()
+		ma, _ := mb.BeginMap(1)
+		va, _ := ma.AssembleEntry("a")
+		Wish(t, va.AssignFloat(math.NaN()), ShouldBeSameTypeAs, ipld.ErrInvalidFloat{})
+		Wish(t, ma.AssembleKey().AssignString("a"), ShouldEqual, nil)
+		Wish(t, ma.AssembleValue().AssignNode(nan), ShouldBeSameTypeAs, ipld.ErrInvalidFloat{})
+		va, _ = ma.AssembleEntry("a")
+		Wish(t, va.AssignFloat(1.5), ShouldEqual, nil)
+		Wish(t, ma.Finish(), ShouldEqual, nil)
+		Wish(t, ipld.Sprint(mb.Build()), ShouldEqual, `{"a": 1.5}`)
+	})
+	t.Run("lenient when allowed", func(t *testing.T) {
+		nb := Style__Float{AllowNonFinite: true}.NewBuilder()
+		Wish(t, nb.AssignFloat(math.Inf(1)), ShouldEqual, nil)
+		Wish(t, mustFloat(nb.Build().AsFloat()), ShouldEqual, math.Inf(1))
+		nb.Reset()
+		Wish(t, nb.AssignFloat(math.NaN()), ShouldEqual, nil)
+	})
+}
+
+func TestFloatIntWidening(t *testing.T) {
+	t.Run("strict by default", func(t *testing.T) {
+		nb := Style__Float{}.NewBuilder()
+		Wish(t, nb.AssignNode(NewInt(3)), ShouldBeSameTypeAs, ipld.ErrWrongKind{})
+		Wish(t, nb.AssignInt(3), ShouldBeSameTypeAs, ipld.ErrWrongKind{})
+	})
+	t.Run("widens when allowed", func(t *testing.T) {
+		ns := Style__Float{AllowIntWidening: true}
+		nb := ns.NewBuilder()
+		Wish(t, nb.AssignNode(NewInt(3)), ShouldEqual, nil)
+		Wish(t, mustFloat(nb.Build().AsFloat()), ShouldEqual, 3.0)
+		nb.Reset()
+		Wish(t, nb.Style(), ShouldEqual, ns)
+		Wish(t, ipld.Copy(NewInt(-2), nb), ShouldEqual, nil)
+		Wish(t, mustFloat(nb.Build().AsFloat()), ShouldEqual, -2.0)
+		nb.Reset()
+		Wish(t, nb.AssignNode(NewFloat(1.5)), ShouldEqual, nil)
+		Wish(t, mustFloat(nb.Build().AsFloat()), ShouldEqual, 1.5)
+	})
+	t.Run("only when exact", func(t *testing.T) {
+		nb := Style__Float{AllowIntWidening: true}.NewBuilder()
+		Wish(t, nb.AssignInt(1<<53), ShouldEqual, nil)
+		err := nb.AssignInt(1<<53 + 1)
+		Wish(t, err, ShouldEqual, ipld.ErrInexactWidening{1<<53 + 1})
+		Wish(t, err.Error(), ShouldEqual, "cannot widen int 9007199254740993 to a float exactly")
+		Wish(t, nb.AssignInt(math.MaxInt64), ShouldEqual, ipld.ErrInexactWidening{math.MaxInt64})
+	})
+}
+
+func mustFloat(v float64, err error) float64 {
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
@@ -90,6 +90,7 @@ type plainInt__Builder struct {
 }
 
 func (nb *plainInt__Builder) Build() ipld.Node {
+	nb.built = true // we mustn't mutate the node we hand out; see HACKME.md.
 	return nb.w
 }
 func (nb *plainInt__Builder) Reset() {
@@ -100,7 +101,8 @@ func (nb *plainInt__Builder) Reset() {
 // -- NodeAssembler -->
 
 type plainInt__Assembler struct {
-	w *plainInt
+	w     *plainInt
+	built bool // set by Build, after which w belongs to the node it returned; see HACKME.md.
 }
 
 func (plainInt__Assembler) BeginMap(sizeHint int) (ipld.MapAssembler, error) {
@@ -116,6 +118,9 @@ func (plainInt__Assembler) AssignBool(bool) error {
 	return mixins.IntAssembler{"int"}.AssignBool(false)
 }
 func (na *plainInt__Assembler) AssignInt(v int) error {
+	if na.built {
+		return ipld.ErrBuilderConsumed{"AssignInt"}
+	}
 	*na.w = plainInt(v)
 	return nil
 }
@@ -132,6 +137,9 @@ func (plainInt__Assembler) AssignLink(ipld.Link) error {
 	return mixins.IntAssembler{"int"}.AssignLink(nil)
 }
 func (na *plainInt__Assembler) AssignNode(v ipld.Node) error {
+	if na.built {
+		return ipld.ErrBuilderConsumed{"AssignNode"}
+	}
 	if v2, err := v.AsInt(); err != nil {
 		return err
 	} else {
@@ -0,0 +1,331 @@
+package basicnode
+
+import (
+	"fmt"
+	"strings"
+
+	ipld "github.com/ipld/go-ipld-prime"
+)
+
+var (
+	_ ipld.MapAssembler  = &lenientMapAssembler{}
+	_ ipld.NodeAssembler = &lenientKeyAssembler{}
+	_ ipld.NodeAssembler = &lenientValueAssembler{}
+	_ ipld.NodeAssembler = discardAssembler{}
+)
+
+// LenientMapAssembler wraps a MapAssembler so that errors in assembling
+// its entries don't halt the assembly: each is recorded, with the key of
+// the entry it happened in, and the entry is left out of the map.
+// Finish then finishes the inner map (with all the entries that did work out),
+// and returns ErrFields listing all the recorded errors, if there were any.
+// This suits form-like uses, where it's more helpful to report every problem at once.
+//
+// Each entry's value is built with a builder of the inner assembler's
+// ValueStyle, and is only given to the inner assembler (with AssignNode)
+// once it's complete, so a failed entry never leaves the inner map half-done.
+// (If the inner assembler then rejects that value anyway -- which a well-behaved
+// one wouldn't do, as the value is of its own ValueStyle -- it can't be used
+// any further: the rest of the entries are discarded, and Finish returns
+// the errors without finishing the inner map.)
+// Errors in assembling a key are recorded too (with an empty Key, since there isn't one),
+// and the value which follows it is discarded.
+// Note that the leniency is for this map's entries as a whole:
+// within a value that's a map or list, errors are returned as usual
+// (and the entry is then recorded as failed when the value is finished,
+// or when it's abandoned by moving on to the next entry).
+func LenientMapAssembler(ma ipld.MapAssembler) ipld.MapAssembler {
+	return &lenientMapAssembler{ma: ma, seen: make(map[string]struct{})}
+}
+
+// FieldError is an error in assembling one entry of a map, as recorded by a LenientMapAssembler.
+type FieldError struct {
+	Key string
+	Err error
+}
+
+func (e FieldError) Error() string {
+	return fmt.Sprintf("%q: %s", e.Key, e.Err)
+}
+
+// ErrFields is returned from the Finish of a LenientMapAssembler
+// when one or more entries failed to assemble.  Errors are in the order they happened.
+type ErrFields []FieldError
+
+func (e ErrFields) Error() string {
+	msgs := make([]string, len(e))
+	for i, fe := range e {
+		msgs[i] = fe.Error()
+	}
+	return fmt.Sprintf("%d map entries failed to assemble: %s", len(e), strings.Join(msgs, "; "))
+}
+
+type lenientMapAssembler struct {
+	ma   ipld.MapAssembler
+	errs ErrFields
+
+	ka     *lenientKeyAssembler   // the key given by AssembleKey, until AssembleValue uses it.
+	val    *lenientValueAssembler // the entry in progress, if any.
+	broken bool                   // set if the inner assembler failed mid-entry, and so can't be used any further.
+	seen   map[string]struct{}    // keys given so far; repeats are caught here, since not every assembler can carry on after rejecting one.
+}
+
+func (lma *lenientMapAssembler) record(k string, err error) {
+	lma.errs = append(lma.errs, FieldError{k, err})
+}
+
+// abandon records the entry in progress as failed, if it was never completed.
+func (lma *lenientMapAssembler) abandon() {
+	if lma.val != nil && !lma.val.done {
+		lma.record(lma.val.key, fmt.Errorf("value was never completed"))
+	}
+	lma.val = nil
+}
+
+func (lma *lenientMapAssembler) AssembleKey() ipld.NodeAssembler {
+	lma.abandon()
+	lma.ka = &lenientKeyAssembler{nb: lma.ma.KeyStyle().NewBuilder()}
+	return lma.ka
+}
+func (lma *lenientMapAssembler) AssembleValue() ipld.NodeAssembler {
+	if lma.ka == nil {
+		panic("misuse")
+	}
+	ka := lma.ka
+	lma.ka = nil
+	if !ka.done {
+		ka.complete(fmt.Errorf("key was never completed"))
+	}
+	if ka.err != nil {
+		lma.record("", ka.err)
+		return discardAssembler{}
+	}
+	k, err := ka.nb.Build().AsString()
+	if err != nil {
+		lma.record(k, err)
+		return discardAssembler{}
+	}
+	return lma.beginEntry(k)
+}
+func (lma *lenientMapAssembler) AssembleEntry(k string) (ipld.NodeAssembler, error) {
+	lma.abandon()
+	return lma.beginEntry(k), nil
+}
+func (lma *lenientMapAssembler) beginEntry(k string) ipld.NodeAssembler {
+	if lma.broken {
+		return discardAssembler{}
+	}
+	if _, exists := lma.seen[k]; exists {
+		lma.record(k, ipld.ErrRepeatedMapKey{NewString(k)})
+		return discardAssembler{}
+	}
+	lma.seen[k] = struct{}{}
+	lma.val = &lenientValueAssembler{lma, k, lma.ma.ValueStyle(k).NewBuilder(), false}
+	return lma.val
+}
+func (lma *lenientMapAssembler) Finish() error {
+	lma.abandon()
+	if lma.broken {
+		return lma.errs
+	}
+	if err := lma.ma.Finish(); err != nil {
+		return err
+	}
+	if len(lma.errs) > 0 {
+		return lma.errs
+	}
+	return nil
+}
+func (lma *lenientMapAssembler) KeyStyle() ipld.NodeStyle {
+	return lma.ma.KeyStyle()
+}
+func (lma *lenientMapAssembler) ValueStyle(k string) ipld.NodeStyle {
+	return lma.ma.ValueStyle(k)
+}
+
+// lenientKeyAssembler builds the key of one entry for a lenientMapAssembler.
+// Errors are kept for AssembleValue to record, rather than returned.
+type lenientKeyAssembler struct {
+	nb   ipld.NodeBuilder
+	err  error
+	done bool // set once the key is either complete or has failed.
+}
+
+// complete marks the key as done, keeping err if it's not nil.
+func (ka *lenientKeyAssembler) complete(err error) error {
+	ka.done = true
+	ka.err = err
+	return nil
+}
+
+func (ka *lenientKeyAssembler) BeginMap(sizeHint int) (ipld.MapAssembler, error) {
+	ma, err := ka.nb.BeginMap(sizeHint)
+	if err != nil {
+		ka.complete(err)
+		return discardMapAssembler{}, nil
+	}
+	return completingMapAssembler{ma, ka.complete}, nil
+}
+func (ka *lenientKeyAssembler) BeginList(sizeHint int) (ipld.ListAssembler, error) {
+	la, err := ka.nb.BeginList(sizeHint)
+	if err != nil {
+		ka.complete(err)
+		return discardListAssembler{}, nil
+	}
+	return completingListAssembler{la, ka.complete}, nil
+}
+func (ka *lenientKeyAssembler) AssignNull() error {
+	return ka.complete(ka.nb.AssignNull())
+}
+func (ka *lenientKeyAssembler) AssignBool(v bool) error {
+	return ka.complete(ka.nb.AssignBool(v))
+}
+func (ka *lenientKeyAssembler) AssignInt(v int) error {
+	return ka.complete(ka.nb.AssignInt(v))
+}
+func (ka *lenientKeyAssembler) AssignFloat(v float64) error {
+	return ka.complete(ka.nb.AssignFloat(v))
+}
+func (ka *lenientKeyAssembler) AssignString(v string) error {
+	return ka.complete(ka.nb.AssignString(v))
+}
+func (ka *lenientKeyAssembler) AssignBytes(v []byte) error {
+	return ka.complete(ka.nb.AssignBytes(v))
+}
+func (ka *lenientKeyAssembler) AssignLink(v ipld.Link) error {
+	return ka.complete(ka.nb.AssignLink(v))
+}
+func (ka *lenientKeyAssembler) AssignNode(v ipld.Node) error {
+	return ka.complete(ka.nb.AssignNode(v))
+}
+func (ka *lenientKeyAssembler) Style() ipld.NodeStyle {
+	return ka.nb.Style()
+}
+
+// lenientValueAssembler builds the value of one entry for a lenientMapAssembler,
+// and commits it to the inner map when it's complete.
+// Errors are recorded, rather than returned.
+type lenientValueAssembler struct {
+	lma  *lenientMapAssembler
+	key  string
+	nb   ipld.NodeBuilder
+	done bool // set once the value is either committed or recorded as failed.
+}
+
+// complete commits the value, or records err if it's not nil.
+func (va *lenientValueAssembler) complete(err error) error {
+	va.done = true
+	if err == nil {
+		var na ipld.NodeAssembler
+		if na, err = va.lma.ma.AssembleEntry(va.key); err == nil {
+			if err = na.AssignNode(va.nb.Build()); err != nil {
+				va.lma.broken = true
+			}
+		}
+	}
+	if err != nil {
+		va.lma.record(va.key, err)
+	}
+	return nil
+}
+
+func (va *lenientValueAssembler) BeginMap(sizeHint int) (ipld.MapAssembler, error) {
+	ma, err := va.nb.BeginMap(sizeHint)
+	if err != nil {
+		va.complete(err)
+		return discardMapAssembler{}, nil
+	}
+	return completingMapAssembler{ma, va.complete}, nil
+}
+func (va *lenientValueAssembler) BeginList(sizeHint int) (ipld.ListAssembler, error) {
+	la, err := va.nb.BeginList(sizeHint)
+	if err != nil {
+		va.complete(err)
+		return discardListAssembler{}, nil
+	}
+	return completingListAssembler{la, va.complete}, nil
+}
+func (va *lenientValueAssembler) AssignNull() error {
+	return va.complete(va.nb.AssignNull())
+}
+func (va *lenientValueAssembler) AssignBool(v bool) error {
+	return va.complete(va.nb.AssignBool(v))
+}
+func (va *lenientValueAssembler) AssignInt(v int) error {
+	return va.complete(va.nb.AssignInt(v))
+}
+func (va *lenientValueAssembler) AssignFloat(v float64) error {
+	return va.complete(va.nb.AssignFloat(v))
+}
+func (va *lenientValueAssembler) AssignString(v string) error {
+	return va.complete(va.nb.AssignString(v))
+}
+func (va *lenientValueAssembler) AssignBytes(v []byte) error {
+	return va.complete(va.nb.AssignBytes(v))
+}
+func (va *lenientValueAssembler) AssignLink(v ipld.Link) error {
+	return va.complete(va.nb.AssignLink(v))
+}
+func (va *lenientValueAssembler) AssignNode(v ipld.Node) error {
+	return va.complete(va.nb.AssignNode(v))
+}
+func (va *lenientValueAssembler) Style() ipld.NodeStyle {
+	return va.nb.Style()
+}
+
+// completingMapAssembler and completingListAssembler complete
+// the key or value they're assembling when they're finished.
+type completingMapAssembler struct {
+	ipld.MapAssembler
+	complete func(error) error
+}
+
+func (ma completingMapAssembler) Finish() error {
+	return ma.complete(ma.MapAssembler.Finish())
+}
+
+type completingListAssembler struct {
+	ipld.ListAssembler
+	complete func(error) error
+}
+
+func (la completingListAssembler) Finish() error {
+	return la.complete(la.ListAssembler.Finish())
+}
+
+// discardAssembler accepts anything, and builds nothing.
+// It stands in for the assembler of an entry which has already failed.
+type discardAssembler struct{}
+
+func (discardAssembler) BeginMap(int) (ipld.MapAssembler, error) {
+	return discardMapAssembler{}, nil
+}
+func (discardAssembler) BeginList(int) (ipld.ListAssembler, error) {
+	return discardListAssembler{}, nil
+}
+func (discardAssembler) AssignNull() error          { return nil }
+func (discardAssembler) AssignBool(bool) error      { return nil }
+func (discardAssembler) AssignInt(int) error        { return nil }
+func (discardAssembler) AssignFloat(float64) error  { return nil }
+func (discardAssembler) AssignString(string) error  { return nil }
+func (discardAssembler) AssignBytes([]byte) error   { return nil }
+func (discardAssembler) AssignLink(ipld.Link) error { return nil }
+func (discardAssembler) AssignNode(ipld.Node) error { return nil }
+func (discardAssembler) Style() ipld.NodeStyle      { return Style__Any{} }
+
+type discardMapAssembler struct{}
+
+func (discardMapAssembler) AssembleKey() ipld.NodeAssembler   { return discardAssembler{} }
+func (discardMapAssembler) AssembleValue() ipld.NodeAssembler { return discardAssembler{} }
+func (discardMapAssembler) AssembleEntry(string) (ipld.NodeAssembler, error) {
+	return discardAssembler{}, nil
+}
+func (discardMapAssembler) Finish() error                    { return nil }
+func (discardMapAssembler) KeyStyle() ipld.NodeStyle         { return Style__String{} }
+func (discardMapAssembler) ValueStyle(string) ipld.NodeStyle { return Style__Any{} }
+
+type discardListAssembler struct{}
+
+func (discardListAssembler) AssembleValue() ipld.NodeAssembler { return discardAssembler{} }
+func (discardListAssembler) Finish() error                     { return nil }
+func (discardListAssembler) ValueStyle(int) ipld.NodeStyle     { return Style__Any{} }
@@ -0,0 +1,93 @@
+package basicnode
+
+import (
+	"fmt"
+	"testing"
+
+	. "github.com/warpfork/go-wish"
+
+	ipld "github.com/ipld/go-ipld-prime"
+	"github.com/ipld/go-ipld-prime/schema"
+)
+
+// int8ValuesMapAssembler is a map assembler whose values must be 8-bit ints,
+// as a typed map's would be.
+type int8ValuesMapAssembler struct {
+	ipld.MapAssembler
+}
+
+func (int8ValuesMapAssembler) ValueStyle(string) ipld.NodeStyle {
+	return schema.Style__TypedInt{schema.SpawnIntWidth("Int8", 8)}
+}
+
+func TestLenientMapAssembler(t *testing.T) {
+	t.Run("bad entries are all reported, and left out", func(t *testing.T) {
+		nb := Style__Map{}.NewBuilder()
+		ma, err := nb.BeginMap(5)
+		Require(t, err, ShouldEqual, nil)
+		lma := LenientMapAssembler(int8ValuesMapAssembler{ma})
+
+		va, err := lma.AssembleEntry("a")
+		Wish(t, err, ShouldEqual, nil)
+		Wish(t, va.AssignInt(1), ShouldEqual, nil)
+		va, _ = lma.AssembleEntry("b")
+		Wish(t, va.AssignInt(300), ShouldEqual, nil) // recorded, not returned.
+		va, _ = lma.AssembleEntry("c")
+		Wish(t, va.AssignString("three"), ShouldEqual, nil)
+		Wish(t, lma.AssembleKey().AssignString("a"), ShouldEqual, nil)
+		Wish(t, lma.AssembleValue().AssignInt(4), ShouldEqual, nil)
+		va, _ = lma.AssembleEntry("e")
+		Wish(t, va.AssignInt(-5), ShouldEqual, nil)
+
+		err = lma.Finish()
+		Require(t, err, ShouldBeSameTypeAs, ErrFields{})
+		errs := err.(ErrFields)
+		Require(t, len(errs), ShouldEqual, 3)
+		Wish(t, errs[0].Key, ShouldEqual, "b")
+		Wish(t, errs[0].Err, ShouldEqual, schema.ErrValueExceedsRange{schema.SpawnIntWidth("Int8", 8), 300})
+		Wish(t, errs[1].Key, ShouldEqual, "c")
+		Wish(t, errs[2], ShouldEqual, FieldError{"a", ipld.ErrRepeatedMapKey{NewString("a")}})
+		Wish(t, err.Error(), ShouldEqual, `3 map entries failed to assemble: "b": value 300 exceeds the range of Int8 (8 bits); "c": func called on wrong kind: AssignString called on a Int8 node (kind: Int), but only makes sense on String; "a": cannot repeat map key ("a")`)
+
+		// The entries which worked out are all there.
+		Wish(t, ipld.Sprint(nb.Build()), ShouldEqual, `{"a": 1, "e": -5}`)
+	})
+	t.Run("bad keys are reported, and their values discarded", func(t *testing.T) {
+		nb := Style__Map{}.NewBuilder()
+		ma, err := nb.BeginMap(2)
+		Require(t, err, ShouldEqual, nil)
+		lma := LenientMapAssembler(ma)
+
+		Wish(t, lma.AssembleKey().AssignInt(1), ShouldEqual, nil) // recorded, not returned.
+		Wish(t, lma.AssembleValue().AssignInt(1), ShouldEqual, nil)
+		lma.AssembleKey()
+		Wish(t, lma.AssembleValue().AssignInt(2), ShouldEqual, nil)
+		Wish(t, lma.AssembleKey().AssignString("c"), ShouldEqual, nil)
+		Wish(t, lma.AssembleValue().AssignInt(3), ShouldEqual, nil)
+
+		err = lma.Finish()
+		Require(t, err, ShouldBeSameTypeAs, ErrFields{})
+		errs := err.(ErrFields)
+		Require(t, len(errs), ShouldEqual, 2)
+		Wish(t, errs[0].Key, ShouldEqual, "")
+		Wish(t, errs[0].Err, ShouldBeSameTypeAs, ipld.ErrWrongKind{})
+		Wish(t, errs[1].Err.Error(), ShouldEqual, "key was never completed")
+		Wish(t, ipld.Sprint(nb.Build()), ShouldEqual, `{"c": 3}`)
+	})
+	t.Run("an inner assembler which rejects its own values stops the assembly", func(t *testing.T) {
+		errNegative := fmt.Errorf("negative ints not allowed")
+		ma, err := TeeAssembler(Style__Map{}.NewBuilder(), func(n ipld.Node) error {
+			if v, err := n.AsInt(); err == nil && v < 0 {
+				return errNegative
+			}
+			return nil
+		}).BeginMap(2)
+		Require(t, err, ShouldEqual, nil)
+		lma := LenientMapAssembler(ma)
+		va, _ := lma.AssembleEntry("a")
+		Wish(t, va.AssignInt(-1), ShouldEqual, nil)
+		va, _ = lma.AssembleEntry("b")
+		Wish(t, va.AssignInt(2), ShouldEqual, nil)
+		Wish(t, lma.Finish(), ShouldEqual, ErrFields{{"a", errNegative}})
+	})
+}
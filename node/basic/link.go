@@ -91,6 +91,7 @@ type plainLink__Builder struct {
 }
 
 func (nb *plainLink__Builder) Build() ipld.Node {
+	nb.built = true // we mustn't mutate the node we hand out; see HACKME.md.
 	return nb.w
 }
 func (nb *plainLink__Builder) Reset() {
@@ -101,7 +102,8 @@ func (nb *plainLink__Builder) Reset() {
 // -- NodeAssembler -->
 
 type plainLink__Assembler struct {
-	w *plainLink
+	w     *plainLink
+	built bool // set by Build, after which w belongs to the node it returned; see HACKME.md.
 }
 
 func (plainLink__Assembler) BeginMap(sizeHint int) (ipld.MapAssembler, error) {
@@ -129,10 +131,16 @@ func (plainLink__Assembler) AssignBytes([]byte) error {
 	return mixins.LinkAssembler{"link"}.AssignBytes(nil)
 }
 func (na *plainLink__Assembler) AssignLink(v ipld.Link) error {
+	if na.built {
+		return ipld.ErrBuilderConsumed{"AssignLink"}
+	}
 	na.w.x = v
 	return nil
 }
 func (na *plainLink__Assembler) AssignNode(v ipld.Node) error {
+	if na.built {
+		return ipld.ErrBuilderConsumed{"AssignNode"}
+	}
 	if v2, err := v.AsLink(); err != nil {
 		return err
 	} else {
@@ -0,0 +1,41 @@
+package basicnode
+
+import (
+	"testing"
+
+	cid "github.com/ipfs/go-cid"
+	. "github.com/warpfork/go-wish"
+
+	ipld "github.com/ipld/go-ipld-prime"
+	cidlink "github.com/ipld/go-ipld-prime/linking/cid"
+)
+
+func TestLink(t *testing.T) {
+	c, _ := cid.Decode("bafyreidykglsfhoixmivffc5uwhcgshx4j465xwqntbmu43nb2dzqwfvae")
+	lnk := cidlink.Link{c}
+	for _, ns := range []ipld.NodeStyle{Style__Link{}, Style__Any{}} {
+		nb := ns.NewBuilder()
+		Wish(t, nb.AssignLink(lnk), ShouldEqual, nil)
+		n := nb.Build()
+		Wish(t, n.ReprKind(), ShouldEqual, ipld.ReprKind_Link)
+		v, err := n.AsLink()
+		Wish(t, err, ShouldEqual, nil)
+		Wish(t, v, ShouldEqual, lnk)
+
+		_, err = n.AsBool()
+		Wish(t, err, ShouldBeSameTypeAs, ipld.ErrWrongKind{})
+		_, err = n.AsInt()
+		Wish(t, err, ShouldBeSameTypeAs, ipld.ErrWrongKind{})
+		_, err = n.AsFloat()
+		Wish(t, err, ShouldBeSameTypeAs, ipld.ErrWrongKind{})
+		_, err = n.AsString()
+		Wish(t, err, ShouldBeSameTypeAs, ipld.ErrWrongKind{})
+		_, err = n.AsBytes()
+		Wish(t, err, ShouldBeSameTypeAs, ipld.ErrWrongKind{})
+		_, err = n.LookupString("x")
+		Wish(t, err, ShouldBeSameTypeAs, ipld.ErrWrongKind{})
+	}
+	v, err := NewLink(lnk).AsLink()
+	Wish(t, err, ShouldEqual, nil)
+	Wish(t, v, ShouldEqual, lnk)
+}
@@ -6,10 +6,11 @@ import (
 )
 
 var (
-	_ ipld.Node          = &plainList{}
-	_ ipld.NodeStyle     = Style__List{}
-	_ ipld.NodeBuilder   = &plainList__Builder{}
-	_ ipld.NodeAssembler = &plainList__Assembler{}
+	_ ipld.Node                     = &plainList{}
+	_ ipld.NodeStyle                = Style__List{}
+	_ ipld.NodeStyleSupportingAmend = Style__List{}
+	_ ipld.NodeBuilder              = &plainList__Builder{}
+	_ ipld.NodeAssembler            = &plainList__Assembler{}
 )
 
 // plainList is a concrete type that provides a list-kind ipld.Node.
@@ -31,7 +32,7 @@ func (plainList) Lookup(ipld.Node) (ipld.Node, error) {
 	return mixins.List{"list"}.Lookup(nil)
 }
 func (n *plainList) LookupIndex(idx int) (ipld.Node, error) {
-	if n.Length() <= idx {
+	if idx < 0 || n.Length() <= idx {
 		return nil, ipld.ErrNotExists{ipld.PathSegmentOfInt(idx)}
 	}
 	return n.x[idx], nil
@@ -39,7 +40,7 @@ func (n *plainList) LookupIndex(idx int) (ipld.Node, error) {
 func (n *plainList) LookupSegment(seg ipld.PathSegment) (ipld.Node, error) {
 	idx, err := seg.Index()
 	if err != nil {
-		panic("todo name this kind of error")
+		return nil, ipld.ErrNotExists{seg} // a segment that isn't a number can't be in a list.
 	}
 	return n.LookupIndex(idx)
 }
@@ -100,10 +101,31 @@ func (itr *plainList_ListIterator) Done() bool {
 
 // -- NodeStyle -->
 
-type Style__List struct{}
+// Style__List builds list nodes.
+type Style__List struct {
+	ErrorOnMisuse   bool // as for Style__Map.
+	MaxScalarLength int  // as for Style__Map.
+}
+
+func (ns Style__List) NewBuilder() ipld.NodeBuilder {
+	return &plainList__Builder{plainList__Assembler{w: &plainList{}, errorOnMisuse: ns.ErrorOnMisuse, maxScalarLength: ns.MaxScalarLength}}
+}
 
-func (Style__List) NewBuilder() ipld.NodeBuilder {
-	return &plainList__Builder{plainList__Assembler{w: &plainList{}}}
+// AmendingBuilder returns a builder for a new list which starts out with
+// the elements of base, and then has any values assembled appended after them.
+// base itself is unchanged (Nodes are immutable, after all), so the same base
+// can be amended any number of times, e.g. to append to a log.
+//
+// The elements of base are shared with the new list, not copied or assembled again;
+// if base is a list from this package, even the slice holding them is shared,
+// until the first value is appended, which copies it once (into room for the size hint).
+// So the cost of amending is at most one copy of that slice.
+//
+// The size hint given to BeginList counts only the values to be appended.
+// If base isn't a list, BeginList returns ErrWrongKind.
+// (Using AssignNode instead of BeginList replaces the whole list, as usual, and base is ignored.)
+func (ns Style__List) AmendingBuilder(base ipld.Node) ipld.NodeBuilder {
+	return &plainList__Builder{plainList__Assembler{w: &plainList{}, base: base, errorOnMisuse: ns.ErrorOnMisuse, maxScalarLength: ns.MaxScalarLength}}
 }
 
 // -- NodeBuilder -->
@@ -116,21 +138,30 @@ func (nb *plainList__Builder) Build() ipld.Node {
 	if nb.state != laState_finished {
 		panic("invalid state: assembler must be 'finished' before Build can be called!")
 	}
+	nb.built = true
 	return nb.w
 }
 func (nb *plainList__Builder) Reset() {
-	*nb = plainList__Builder{}
+	*nb = plainList__Builder{plainList__Assembler{base: nb.base, errorOnMisuse: nb.errorOnMisuse, maxScalarLength: nb.maxScalarLength}}
 	nb.w = &plainList{}
 }
 
 // -- NodeAssembler -->
 
 type plainList__Assembler struct {
-	w *plainList
+	w     *plainList
+	alloc Allocator // where child values come from; nil for the default (see NewStyleWithAllocator).
+	base  ipld.Node // the list whose elements come first, if this is an AmendingBuilder.
+
+	errorOnMisuse   bool // see Style__Map.
+	maxScalarLength int  // see Style__Map.
 
 	va plainList__ValueAssembler
 
 	state laState
+	limit int  // if positive, the sizeHint given to BeginList, which we won't allow to be exceeded.
+	begun bool // set by BeginList or AssignNode; either may only be called once.
+	built bool // set by Build; see HACKME.md.
 }
 type plainList__ValueAssembler struct {
 	la *plainList__Assembler
@@ -151,14 +182,56 @@ func (plainList__Assembler) BeginMap(sizeHint int) (ipld.MapAssembler, error) {
 	return mixins.ListAssembler{"list"}.BeginMap(0)
 }
 func (na *plainList__Assembler) BeginList(sizeHint int) (ipld.ListAssembler, error) {
+	if na.built {
+		return nil, ipld.ErrBuilderConsumed{"BeginList"}
+	}
+	if na.begun {
+		return nil, misuse(na.errorOnMisuse, "begun", "BeginList")
+	}
 	if sizeHint < 0 {
 		sizeHint = 0
 	}
-	// Allocate storage space.
-	na.w.x = make([]ipld.Node, 0, sizeHint)
+	// If amending, start with the base's elements; otherwise, allocate storage space.
+	//  Either way, remember the hint as our limit (counting only the values still to come).
+	if na.base != nil {
+		x, err := amendListBase(na.base, sizeHint)
+		if err != nil {
+			return nil, err
+		}
+		na.w.x = x
+	} else {
+		na.w.x = make([]ipld.Node, 0, sizeHint)
+	}
+	na.begun = true
+	na.limit = sizeHint
+	if na.limit > 0 {
+		na.limit += len(na.w.x)
+	}
 	// That's it; return self as the ListAssembler.  We already have all the right methods on this structure.
 	return na, nil
 }
+
+// amendListBase returns a slice holding the elements of base, with room for sizeHint more.
+// If base is our own type, the slice is base's own, clipped to its length,
+// so that the first append copies it rather than writing into base's spare capacity.
+func amendListBase(base ipld.Node, sizeHint int) ([]ipld.Node, error) {
+	if v2, ok := base.(*plainList); ok { // if our own type: shortcut.
+		return v2.x[:len(v2.x):len(v2.x)], nil
+	}
+	if base.ReprKind() != ipld.ReprKind_List {
+		return nil, ipld.ErrWrongKind{TypeName: "list", MethodName: "AmendingBuilder", AppropriateKind: ipld.ReprKindSet_JustList, ActualKind: base.ReprKind()}
+	}
+	x := make([]ipld.Node, 0, base.Length()+sizeHint)
+	for itr := base.ListIterator(); !itr.Done(); {
+		_, v, err := itr.Next()
+		if err != nil {
+			return nil, err
+		}
+		x = append(x, v)
+	}
+	return x, nil
+}
+
 func (plainList__Assembler) AssignNull() error {
 	return mixins.ListAssembler{"list"}.AssignNull()
 }
@@ -182,23 +255,32 @@ func (plainList__Assembler) AssignLink(ipld.Link) error {
 }
 func (na *plainList__Assembler) AssignNode(v ipld.Node) error {
 	// Sanity check, then update, assembler state.
-	if na.state != laState_initial {
-		panic("misuse")
+	if na.built {
+		return ipld.ErrBuilderConsumed{"AssignNode"}
+	}
+	if na.begun {
+		return misuse(na.errorOnMisuse, "begun", "AssignNode")
 	}
-	na.state = laState_finished
 	// Copy the content.
 	if v2, ok := v.(*plainList); ok { // if our own type: shortcut.
 		// Copy the structure by value.
 		//  This means we'll have pointers into the same internal maps and slices;
 		//   this is okay, because the Node type promises it's immutable, and we are going to instantly finish ourselves to also maintain that.
+		na.begun = true
+		na.state = laState_finished
 		*na.w = *v2
 		return nil
 	}
-	// If the above shortcut didn't work, resort to a generic copy.
+	// If the above shortcut didn't work, resort to a generic copy: begin, assemble each value, and finish, as any caller would.
+	//  (Not with BeginList, though, since AssignNode ignores any amending base.)
 	//  We call AssignNode for all the child values, giving them a chance to hit shortcuts even if we didn't.
 	if v.ReprKind() != ipld.ReprKind_List {
 		return ipld.ErrWrongKind{TypeName: "list", MethodName: "AssignNode", AppropriateKind: ipld.ReprKindSet_JustList, ActualKind: v.ReprKind()}
 	}
+	na.begun = true
+	if l := v.Length(); l > 0 {
+		na.w.x = make([]ipld.Node, 0, l)
+	}
 	itr := v.ListIterator()
 	for !itr.Done() {
 		_, v, err := itr.Next()
@@ -210,10 +292,10 @@ func (na *plainList__Assembler) AssignNode(v ipld.Node) error {
 		}
 	}
 	// validators could run and report errors promptly, if this type had any -- same as for regular Finish.
-	return nil
+	return na.Finish()
 }
-func (plainList__Assembler) Style() ipld.NodeStyle {
-	return Style__List{}
+func (na *plainList__Assembler) Style() ipld.NodeStyle {
+	return Style__List{na.errorOnMisuse, na.maxScalarLength}
 }
 
 // -- ListAssembler -->
@@ -223,7 +305,12 @@ func (plainList__Assembler) Style() ipld.NodeStyle {
 func (la *plainList__Assembler) AssembleValue() ipld.NodeAssembler {
 	// Sanity check, then update, assembler state.
 	if la.state != laState_initial {
-		panic("misuse")
+		return errorAssembler{misuse(la.errorOnMisuse, la.state.String(), "AssembleValue")}
+	}
+	// Check that we haven't been asked for more values than the sizeHint promised.
+	//  (We don't change state in this case; the caller can still Finish.)
+	if la.limit > 0 && len(la.w.x) >= la.limit {
+		return errorAssembler{ipld.ErrListOverrun{la.limit}}
 	}
 	la.state = laState_midValue
 	// Make value assembler valid by giving it pointer back to whole 'la'; yield it.
@@ -236,28 +323,52 @@ func (la *plainList__Assembler) AssembleValue() ipld.NodeAssembler {
 func (la *plainList__Assembler) Finish() error {
 	// Sanity check, then update, assembler state.
 	if la.state != laState_initial {
-		panic("misuse")
+		return misuse(la.errorOnMisuse, la.state.String(), "Finish")
 	}
 	la.state = laState_finished
 	// validators could run and report errors promptly, if this type had any.
 	return nil
 }
-func (plainList__Assembler) ValueStyle(_ int) ipld.NodeStyle {
-	return Style__Any{}
+func (la *plainList__Assembler) ValueStyle(_ int) ipld.NodeStyle {
+	return Style__Any{la.errorOnMisuse, la.maxScalarLength}
+}
+
+// AssembleKey isn't part of ListAssembler; lists don't have keys.
+// It's present only so that misuse (e.g. via interface sniffing) gets an error rather than a panic.
+func (plainList__Assembler) AssembleKey() ipld.NodeAssembler {
+	return errorAssembler{ipld.ErrWrongKind{TypeName: "list", MethodName: "AssembleKey", AppropriateKind: ipld.ReprKindSet_JustMap, ActualKind: ipld.ReprKind_List}}
+}
+
+// AssembleEntry isn't part of ListAssembler; lists don't have keys.
+// It's present only so that misuse (e.g. via interface sniffing) gets an error rather than a panic.
+func (plainList__Assembler) AssembleEntry(string) (ipld.NodeAssembler, error) {
+	return nil, ipld.ErrWrongKind{TypeName: "list", MethodName: "AssembleEntry", AppropriateKind: ipld.ReprKindSet_JustMap, ActualKind: ipld.ReprKind_List}
 }
 
 // -- ListAssembler.ValueAssembler -->
 
 func (lva *plainList__ValueAssembler) BeginMap(sizeHint int) (ipld.MapAssembler, error) {
+	if err := lva.stale("BeginMap"); err != nil {
+		return nil, err
+	}
 	ma := plainList__ValueAssemblerMap{}
-	ma.ca.w = &plainMap{}
+	ma.ca.w = newMap(lva.la.alloc)
+	ma.ca.alloc = lva.la.alloc
+	ma.ca.errorOnMisuse = lva.la.errorOnMisuse
+	ma.ca.maxScalarLength = lva.la.maxScalarLength
 	ma.p = lva.la
 	_, err := ma.ca.BeginMap(sizeHint)
 	return &ma, err
 }
 func (lva *plainList__ValueAssembler) BeginList(sizeHint int) (ipld.ListAssembler, error) {
+	if err := lva.stale("BeginList"); err != nil {
+		return nil, err
+	}
 	la := plainList__ValueAssemblerList{}
-	la.ca.w = &plainList{}
+	la.ca.w = newList(lva.la.alloc)
+	la.ca.alloc = lva.la.alloc
+	la.ca.errorOnMisuse = lva.la.errorOnMisuse
+	la.ca.maxScalarLength = lva.la.maxScalarLength
 	la.p = lva.la
 	_, err := la.ca.BeginList(sizeHint)
 	return &la, err
@@ -266,37 +377,79 @@ func (lva *plainList__ValueAssembler) AssignNull() error {
 	return lva.AssignNode(ipld.Null)
 }
 func (lva *plainList__ValueAssembler) AssignBool(v bool) error {
-	vb := plainBool(v)
-	return lva.AssignNode(&vb)
+	return lva.AssignNode(newBool(lva.la.alloc, v))
 }
 func (lva *plainList__ValueAssembler) AssignInt(v int) error {
-	vb := plainInt(v)
-	return lva.AssignNode(&vb)
+	return lva.AssignNode(newInt(lva.la.alloc, v))
 }
 func (lva *plainList__ValueAssembler) AssignFloat(v float64) error {
-	vb := plainFloat(v)
-	return lva.AssignNode(&vb)
+	if err := lva.stale("AssignFloat"); err != nil {
+		return err
+	}
+	if err := checkFloat(v); err != nil {
+		lva.rollback()
+		return err
+	}
+	return lva.AssignNode(newFloat(lva.la.alloc, v))
 }
 func (lva *plainList__ValueAssembler) AssignString(v string) error {
-	vb := plainString(v)
-	return lva.AssignNode(&vb)
+	if err := lva.stale("AssignString"); err != nil {
+		return err
+	}
+	if err := checkLength(lva.la.maxScalarLength, ipld.ReprKind_String, len(v)); err != nil {
+		lva.rollback()
+		return err
+	}
+	return lva.AssignNode(newString(lva.la.alloc, v))
 }
 func (lva *plainList__ValueAssembler) AssignBytes(v []byte) error {
-	vb := plainBytes(v)
-	return lva.AssignNode(&vb)
+	if err := lva.stale("AssignBytes"); err != nil {
+		return err
+	}
+	if err := checkLength(lva.la.maxScalarLength, ipld.ReprKind_Bytes, len(v)); err != nil {
+		lva.rollback()
+		return err
+	}
+	return lva.AssignNode(newBytes(lva.la.alloc, v))
 }
 func (lva *plainList__ValueAssembler) AssignLink(v ipld.Link) error {
-	vb := plainLink{v}
-	return lva.AssignNode(&vb)
+	return lva.AssignNode(newLink(lva.la.alloc, v))
 }
 func (lva *plainList__ValueAssembler) AssignNode(v ipld.Node) error {
+	if err := lva.stale("AssignNode"); err != nil {
+		return err
+	}
+	if err := checkFloatNode(v); err != nil {
+		lva.rollback()
+		return err
+	}
+	if x := lva.la.w.x; len(x) == cap(x) && lva.la.limit > len(x) {
+		// Out of room, with more values hinted (e.g. an amended base's slice, shared until now):
+		// grow to fit them all, rather than leaving it to append.
+		lva.la.w.x = make([]ipld.Node, len(x), lva.la.limit)
+		copy(lva.la.w.x, x)
+	}
 	lva.la.w.x = append(lva.la.w.x, v)
 	lva.la.state = laState_initial
-	lva.la = nil // invalidate self to prevent further incorrect use.
 	return nil
 }
-func (plainList__ValueAssembler) Style() ipld.NodeStyle {
-	return Style__Any{}
+
+// stale returns misuse if the list isn't waiting on this value any more:
+// it's already been assigned, or it failed.
+func (lva *plainList__ValueAssembler) stale(method string) error {
+	if lva.la.state != laState_midValue {
+		return misuse(lva.la.errorOnMisuse, lva.la.state.String(), method)
+	}
+	return nil
+}
+
+// rollback returns the list assembler to its initial state without appending anything,
+// so the caller may carry on.
+func (lva *plainList__ValueAssembler) rollback() {
+	lva.la.state = laState_initial
+}
+func (lva *plainList__ValueAssembler) Style() ipld.NodeStyle {
+	return Style__Any{lva.la.errorOnMisuse, lva.la.maxScalarLength}
 }
 
 type plainList__ValueAssemblerMap struct {
@@ -317,11 +470,11 @@ func (ma *plainList__ValueAssemblerMap) AssembleKey() ipld.NodeAssembler {
 func (ma *plainList__ValueAssemblerMap) AssembleValue() ipld.NodeAssembler {
 	return ma.ca.AssembleValue()
 }
-func (plainList__ValueAssemblerMap) KeyStyle() ipld.NodeStyle {
-	return Style__String{}
+func (ma *plainList__ValueAssemblerMap) KeyStyle() ipld.NodeStyle {
+	return ma.ca.KeyStyle()
 }
-func (plainList__ValueAssemblerMap) ValueStyle(_ string) ipld.NodeStyle {
-	return Style__Any{}
+func (ma *plainList__ValueAssemblerMap) ValueStyle(_ string) ipld.NodeStyle {
+	return Style__Any{ma.ca.errorOnMisuse, ma.ca.maxScalarLength}
 }
 
 func (ma *plainList__ValueAssemblerMap) Finish() error {
@@ -345,8 +498,8 @@ type plainList__ValueAssemblerList struct {
 func (la *plainList__ValueAssemblerList) AssembleValue() ipld.NodeAssembler {
 	return la.ca.AssembleValue()
 }
-func (plainList__ValueAssemblerList) ValueStyle(_ int) ipld.NodeStyle {
-	return Style__Any{}
+func (la *plainList__ValueAssemblerList) ValueStyle(_ int) ipld.NodeStyle {
+	return Style__Any{la.ca.errorOnMisuse, la.ca.maxScalarLength}
 }
 
 func (la *plainList__ValueAssemblerList) Finish() error {
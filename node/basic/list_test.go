@@ -0,0 +1,186 @@
+package basicnode
+
+import (
+	"testing"
+
+	. "github.com/warpfork/go-wish"
+
+	ipld "github.com/ipld/go-ipld-prime"
+	"github.com/ipld/go-ipld-prime/fluent"
+	"github.com/ipld/go-ipld-prime/node/tests"
+)
+
+func TestList(t *testing.T) {
+	tests.SpecTestListAssembler(t, Style__List{})
+}
+
+func TestListLookupRelative(t *testing.T) {
+	n := fluent.MustBuildList(Style__List{}, 3, func(na fluent.ListAssembler) {
+		na.AssembleValue().AssignString("a")
+		na.AssembleValue().AssignString("b")
+		na.AssembleValue().AssignString("c")
+	})
+	t.Run("negative indexes count from the end", func(t *testing.T) {
+		v, err := ipld.LookupIndexRelative(n, -1)
+		Wish(t, err, ShouldEqual, nil)
+		Wish(t, v, ShouldEqual, NewString("c"))
+		v, err = ipld.LookupSegmentRelative(n, ipld.ParsePathSegment("-2"))
+		Wish(t, err, ShouldEqual, nil)
+		Wish(t, v, ShouldEqual, NewString("b"))
+		v, err = ipld.LookupIndexRelative(n, 0)
+		Wish(t, err, ShouldEqual, nil)
+		Wish(t, v, ShouldEqual, NewString("a"))
+	})
+	t.Run("out of range negative indexes don't exist", func(t *testing.T) {
+		_, err := ipld.LookupIndexRelative(n, -4)
+		Wish(t, err, ShouldEqual, ipld.ErrNotExists{ipld.PathSegmentOfInt(-4)})
+		Wish(t, err.Error(), ShouldEqual, `key not found: "-4"`)
+		_, err = ipld.LookupIndexRelative(n, 3)
+		Wish(t, err, ShouldEqual, ipld.ErrNotExists{ipld.PathSegmentOfInt(3)})
+	})
+	t.Run("plain lookups stay strict", func(t *testing.T) {
+		_, err := n.LookupIndex(-1)
+		Wish(t, err, ShouldEqual, ipld.ErrNotExists{ipld.PathSegmentOfInt(-1)})
+		_, err = n.LookupSegment(ipld.ParsePathSegment("-1"))
+		Wish(t, err, ShouldEqual, ipld.ErrNotExists{ipld.PathSegmentOfInt(-1)})
+		_, err = n.LookupSegment(ipld.ParsePathSegment("x"))
+		Wish(t, err, ShouldEqual, ipld.ErrNotExists{ipld.ParsePathSegment("x")})
+	})
+	t.Run("non-lists are unaffected", func(t *testing.T) {
+		_, err := ipld.LookupIndexRelative(NewString("x"), -1)
+		Wish(t, err, ShouldBeSameTypeAs, ipld.ErrWrongKind{})
+	})
+}
+
+func TestListAmend(t *testing.T) {
+	base := fluent.MustBuildList(Style__List{}, 2, func(na fluent.ListAssembler) {
+		na.AssembleValue().AssignString("a")
+		na.AssembleValue().AssignString("b")
+	})
+	amend := func(base ipld.Node, vs ...string) (ipld.Node, error) {
+		nb := Style__List{}.AmendingBuilder(base)
+		la, err := nb.BeginList(len(vs))
+		if err != nil {
+			return nil, err
+		}
+		for _, v := range vs {
+			if err := la.AssembleValue().AssignString(v); err != nil {
+				return nil, err
+			}
+		}
+		if err := la.Finish(); err != nil {
+			return nil, err
+		}
+		return nb.Build(), nil
+	}
+	t.Run("appends after the base's elements", func(t *testing.T) {
+		n, err := amend(base, "c")
+		Wish(t, err, ShouldEqual, nil)
+		Wish(t, ipld.Sprint(n), ShouldEqual, `["a", "b", "c"]`)
+		Wish(t, ipld.Sprint(base), ShouldEqual, `["a", "b"]`)
+	})
+	t.Run("amending the same base twice", func(t *testing.T) {
+		n1, err := amend(base, "x")
+		Wish(t, err, ShouldEqual, nil)
+		n2, err := amend(base, "y", "z")
+		Wish(t, err, ShouldEqual, nil)
+		Wish(t, ipld.Sprint(n1), ShouldEqual, `["a", "b", "x"]`)
+		Wish(t, ipld.Sprint(n2), ShouldEqual, `["a", "b", "y", "z"]`)
+	})
+	t.Run("amending an amended list", func(t *testing.T) {
+		n, err := amend(base, "c")
+		Wish(t, err, ShouldEqual, nil)
+		n, err = amend(n, "d")
+		Wish(t, err, ShouldEqual, nil)
+		Wish(t, ipld.Sprint(n), ShouldEqual, `["a", "b", "c", "d"]`)
+	})
+	t.Run("base with spare capacity isn't written into", func(t *testing.T) {
+		base := fluent.MustBuildList(Style__List{}, 10, func(na fluent.ListAssembler) {
+			na.AssembleValue().AssignString("a")
+		})
+		n1, err := amend(base, "x")
+		Wish(t, err, ShouldEqual, nil)
+		n2, err := amend(base, "y")
+		Wish(t, err, ShouldEqual, nil)
+		Wish(t, ipld.Sprint(n1), ShouldEqual, `["a", "x"]`)
+		Wish(t, ipld.Sprint(n2), ShouldEqual, `["a", "y"]`)
+		Wish(t, ipld.Sprint(base), ShouldEqual, `["a"]`)
+		Wish(t, cap(base.(*plainList).x), ShouldEqual, 10)
+	})
+	t.Run("the base's slice is shared until the first append", func(t *testing.T) {
+		nb := Style__List{}.AmendingBuilder(base)
+		la, err := nb.BeginList(2)
+		Wish(t, err, ShouldEqual, nil)
+		x := nb.(*plainList__Builder).w.x
+		Wish(t, &x[0] == &base.(*plainList).x[0], ShouldEqual, true)
+		Wish(t, la.AssembleValue().AssignString("c"), ShouldEqual, nil)
+		x = nb.(*plainList__Builder).w.x
+		Wish(t, &x[0] == &base.(*plainList).x[0], ShouldEqual, false)
+		Wish(t, cap(x), ShouldEqual, 4) // the copy has room for the rest of the size hint.
+		Wish(t, la.AssembleValue().AssignString("d"), ShouldEqual, nil)
+		Wish(t, la.Finish(), ShouldEqual, nil)
+		Wish(t, ipld.Sprint(nb.Build()), ShouldEqual, `["a", "b", "c", "d"]`)
+		Wish(t, ipld.Sprint(base), ShouldEqual, `["a", "b"]`)
+	})
+	t.Run("elements are shared", func(t *testing.T) {
+		n, err := amend(base)
+		Wish(t, err, ShouldEqual, nil)
+		v1, _ := base.LookupIndex(1)
+		v2, _ := n.LookupIndex(1)
+		Wish(t, v2 == v1, ShouldEqual, true)
+	})
+	t.Run("the size hint counts only appended values", func(t *testing.T) {
+		la, err := Style__List{}.AmendingBuilder(base).BeginList(1)
+		Wish(t, err, ShouldEqual, nil)
+		Wish(t, la.AssembleValue().AssignString("c"), ShouldEqual, nil)
+		Wish(t, la.AssembleValue().AssignString("d"), ShouldEqual, ipld.ErrListOverrun{3})
+	})
+	t.Run("base of another list implementation", func(t *testing.T) {
+		view, err := ipld.SubList(base, 1, 2)
+		Wish(t, err, ShouldEqual, nil)
+		n, err := amend(view, "c")
+		Wish(t, err, ShouldEqual, nil)
+		Wish(t, ipld.Sprint(n), ShouldEqual, `["b", "c"]`)
+	})
+	t.Run("base which isn't a list", func(t *testing.T) {
+		_, err := amend(NewString("x"), "c")
+		Wish(t, err, ShouldBeSameTypeAs, ipld.ErrWrongKind{})
+	})
+}
+
+func benchmarkListAppend_100000n(b *testing.B, appendOne func(base ipld.Node) ipld.Node) {
+	base := fluent.MustBuildList(Style__List{}, 100000, func(na fluent.ListAssembler) {
+		for i := 0; i < 100000; i++ {
+			na.AssembleValue().AssignInt(i)
+		}
+	})
+	b.ResetTimer()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if n := appendOne(base); n.Length() != 100001 {
+			b.Fatal(n.Length())
+		}
+	}
+}
+func BenchmarkListAppend_100000n_Amend(b *testing.B) {
+	benchmarkListAppend_100000n(b, func(base ipld.Node) ipld.Node {
+		nb := Style__List{}.AmendingBuilder(base)
+		la, _ := nb.BeginList(1)
+		la.AssembleValue().AssignInt(-1)
+		la.Finish()
+		return nb.Build()
+	})
+}
+func BenchmarkListAppend_100000n_Rebuild(b *testing.B) {
+	benchmarkListAppend_100000n(b, func(base ipld.Node) ipld.Node {
+		nb := Style__List{}.NewBuilder()
+		la, _ := nb.BeginList(base.Length() + 1)
+		for itr := base.ListIterator(); !itr.Done(); {
+			_, v, _ := itr.Next()
+			la.AssembleValue().AssignNode(v)
+		}
+		la.AssembleValue().AssignInt(-1)
+		la.Finish()
+		return nb.Build()
+	})
+}
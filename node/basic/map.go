@@ -1,51 +1,86 @@
 package basicnode
 
 import (
-	"fmt"
+	"strconv"
 
 	ipld "github.com/ipld/go-ipld-prime"
 	"github.com/ipld/go-ipld-prime/node/mixins"
 )
 
 var (
-	_ ipld.Node          = &plainMap{}
-	_ ipld.NodeStyle     = Style__Map{}
-	_ ipld.NodeBuilder   = &plainMap__Builder{}
-	_ ipld.NodeAssembler = &plainMap__Assembler{}
+	_ ipld.Node                  = &plainMap{}
+	_ ipld.NodeSupportingHas     = &plainMap{}
+	_ ipld.NodeSupportingForEach = &plainMap{}
+	_ ipld.NodeStyle             = Style__Map{}
+	_ ipld.NodeBuilder           = &plainMap__Builder{}
+	_ ipld.NodeAssembler         = &plainMap__Assembler{}
 )
 
 // plainMap is a concrete type that provides a map-kind ipld.Node.
 // It can contain any kind of value.
 // plainMap is also embedded in the 'any' struct and usable from there.
+//
+// Keys are usually strings, but the key assembler and Lookup also accept
+// int, bytes, and bool nodes as keys (see mapKeyOf).
+// Keys of different kinds are always different keys, and are yielded back
+// as the kind they were given as.
 type plainMap struct {
-	m map[string]ipld.Node // string key -- even if a runtime schema wrapper is using us for storage, we must have a comparable type here, and string is all we know.
+	m map[mapKey]ipld.Node // even if a runtime schema wrapper is using us for storage, we must have a comparable type here, and a kind and string form is all we know.
 	t []plainMap__Entry    // table for fast iteration, order keeping, and yielding pointers to enable alloc/conv amortization.
 }
 
 type plainMap__Entry struct {
-	k plainString // address of this used when we return keys as nodes, such as in iterators.  Need in one place to amortize shifts to heap when ptr'ing for iface.
-	v ipld.Node   // identical to map values.  keeping them here simplifies iteration.  (in codegen'd maps, this position is also part of amortization, but in this implementation, that's less useful.)
+	k  plainString // the key's string form.  address of this used when we return string keys as nodes, such as in iterators.  Need in one place to amortize shifts to heap when ptr'ing for iface.
+	kn ipld.Node   // the key, if it isn't a string; nil if it is.
+	v  ipld.Node   // identical to map values.  keeping them here simplifies iteration.  (in codegen'd maps, this position is also part of amortization, but in this implementation, that's less useful.)
 	// note on alternate implementations: 'v' could also use the 'any' type, and thus amortize value allocations.  the memory size trade would be large however, so we don't, here.
 }
 
+// key returns the entry's key, as a node.
+func (e *plainMap__Entry) key() ipld.Node {
+	if e.kn != nil {
+		return e.kn
+	}
+	return &e.k
+}
+
+// mapKey returns the entry's key, as stored in 'm'.
+func (e *plainMap__Entry) mapKey() mapKey {
+	if e.kn != nil {
+		return mapKey{e.kn.ReprKind(), string(e.k)}
+	}
+	return mapKey{ipld.ReprKind_String, string(e.k)}
+}
+
 // -- Node interface methods -->
 
 func (plainMap) ReprKind() ipld.ReprKind {
 	return ipld.ReprKind_Map
 }
 func (n *plainMap) LookupString(key string) (ipld.Node, error) {
-	v, exists := n.m[key]
+	return n.lookup(mapKey{ipld.ReprKind_String, key})
+}
+func (n *plainMap) Lookup(key ipld.Node) (ipld.Node, error) {
+	mk, err := mapKeyOf(key)
+	if err != nil {
+		return nil, err
+	}
+	return n.lookup(mk)
+}
+func (n *plainMap) lookup(mk mapKey) (ipld.Node, error) {
+	v, exists := n.m[mk]
 	if !exists {
-		return nil, ipld.ErrNotExists{ipld.PathSegmentOfString(key)}
+		return nil, ipld.ErrNotExists{ipld.PathSegmentOfString(mk.s)}
 	}
 	return v, nil
 }
-func (n *plainMap) Lookup(key ipld.Node) (ipld.Node, error) {
-	ks, err := key.AsString()
+func (n *plainMap) Has(key ipld.Node) (bool, error) {
+	mk, err := mapKeyOf(key)
 	if err != nil {
-		return nil, err
+		return false, err
 	}
-	return n.LookupString(ks)
+	_, exists := n.m[mk]
+	return exists, nil
 }
 func (plainMap) LookupIndex(idx int) (ipld.Node, error) {
 	return mixins.Map{"map"}.LookupIndex(0)
@@ -56,6 +91,14 @@ func (n *plainMap) LookupSegment(seg ipld.PathSegment) (ipld.Node, error) {
 func (n *plainMap) MapIterator() ipld.MapIterator {
 	return &plainMap_MapIterator{n, 0}
 }
+func (n *plainMap) ForEach(fn func(k ipld.Node, v ipld.Node) error) error {
+	for i := range n.t {
+		if err := fn(n.t[i].key(), n.t[i].v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
 func (plainMap) ListIterator() ipld.ListIterator {
 	return nil
 }
@@ -99,7 +142,7 @@ func (itr *plainMap_MapIterator) Next() (k ipld.Node, v ipld.Node, _ error) {
 	if itr.Done() {
 		return nil, nil, ipld.ErrIteratorOverread{}
 	}
-	k = &itr.n.t[itr.idx].k
+	k = itr.n.t[itr.idx].key()
 	v = itr.n.t[itr.idx].v
 	itr.idx++
 	return
@@ -110,10 +153,39 @@ func (itr *plainMap_MapIterator) Done() bool {
 
 // -- NodeStyle -->
 
-type Style__Map struct{}
-
-func (Style__Map) NewBuilder() ipld.NodeBuilder {
-	return &plainMap__Builder{plainMap__Assembler{w: &plainMap{}}}
+// Style__Map builds map nodes, which iterate in the order their entries were assembled.
+type Style__Map struct {
+	// ErrorOnMisuse controls what the assemblers do when their methods are
+	// called out of order (for example, AssembleValue before AssembleKey,
+	// a key or value assigned twice, or anything after Finish).
+	//
+	// By default, they panic, which is the quickest way to find the bug in
+	// the calling code.  When ErrorOnMisuse is set, they return an
+	// ipld.ErrAssemblerMisuse instead.  Methods that can't return an error
+	// (like AssembleValue) return an assembler that gives that error from
+	// every one of its methods.  The setting carries on to the maps and lists
+	// assembled as values within.
+	//
+	// (Builders assigned to again after Build, without a Reset in between,
+	// return ipld.ErrBuilderConsumed either way: see HACKME.md.)
+	ErrorOnMisuse bool
+
+	// MaxScalarLength, if positive, is the longest string or bytes value
+	// (keys included) which the assemblers will accept.  Longer ones are
+	// rejected by AssignString, AssignBytes, and AssembleEntry with
+	// ipld.ErrBudgetExceeded, before anything is allocated for them,
+	// and the map is left as it was, so the caller may carry on.
+	// Like ErrorOnMisuse, the setting carries on to the maps and lists
+	// assembled as values within.
+	//
+	// AssignNode isn't checked: a node given whole has already been allocated.
+	// (Decoders check codec.DecodeOptions.MaxScalarLength as they read,
+	// which also keeps oversized values from being read into memory at all.)
+	MaxScalarLength int
+}
+
+func (ns Style__Map) NewBuilder() ipld.NodeBuilder {
+	return &plainMap__Builder{plainMap__Assembler{w: &plainMap{}, errorOnMisuse: ns.ErrorOnMisuse, maxScalarLength: ns.MaxScalarLength}}
 }
 
 // -- NodeBuilder -->
@@ -126,22 +198,36 @@ func (nb *plainMap__Builder) Build() ipld.Node {
 	if nb.state != maState_finished {
 		panic("invalid state: assembler must be 'finished' before Build can be called!")
 	}
+	nb.built = true
 	return nb.w
 }
 func (nb *plainMap__Builder) Reset() {
-	*nb = plainMap__Builder{}
+	*nb = plainMap__Builder{plainMap__Assembler{errorOnMisuse: nb.errorOnMisuse, maxScalarLength: nb.maxScalarLength}}
 	nb.w = &plainMap{}
 }
 
 // -- NodeAssembler -->
 
+// plainMap__Assembler assembles a plainMap.
+//
+// Its recovery contract is: an error from key or value assembly (or a
+// repeated key from AssembleEntry) leaves the map exactly as it was before
+// that entry was started, and returns the assembler to maState_initial.
+// So a failed entry doesn't leave a half-set row behind,
+// and the caller may simply try the same key again.
 type plainMap__Assembler struct {
-	w *plainMap
+	w     *plainMap
+	alloc Allocator // where child values come from; nil for the default (see NewStyleWithAllocator).
+
+	errorOnMisuse   bool // see Style__Map.
+	maxScalarLength int  // see Style__Map.
 
 	ka plainMap__KeyAssembler
 	va plainMap__ValueAssembler
 
 	state maState
+	begun bool // set by BeginMap or AssignNode; either may only be called once.
+	built bool // set by Build; see HACKME.md.
 }
 type plainMap__KeyAssembler struct {
 	ma *plainMap__Assembler
@@ -163,12 +249,19 @@ const (
 )
 
 func (na *plainMap__Assembler) BeginMap(sizeHint int) (ipld.MapAssembler, error) {
+	if na.built {
+		return nil, ipld.ErrBuilderConsumed{"BeginMap"}
+	}
+	if na.begun {
+		return nil, misuse(na.errorOnMisuse, "begun", "BeginMap")
+	}
+	na.begun = true
 	if sizeHint < 0 {
 		sizeHint = 0
 	}
 	// Allocate storage space.
 	na.w.t = make([]plainMap__Entry, 0, sizeHint)
-	na.w.m = make(map[string]ipld.Node, sizeHint)
+	na.w.m = make(map[mapKey]ipld.Node, sizeHint)
 	// That's it; return self as the MapAssembler.  We already have all the right methods on this structure.
 	return na, nil
 }
@@ -198,23 +291,30 @@ func (plainMap__Assembler) AssignLink(ipld.Link) error {
 }
 func (na *plainMap__Assembler) AssignNode(v ipld.Node) error {
 	// Sanity check, then update, assembler state.
-	if na.state != maState_initial {
-		panic("misuse")
+	if na.built {
+		return ipld.ErrBuilderConsumed{"AssignNode"}
+	}
+	if na.begun {
+		return misuse(na.errorOnMisuse, "begun", "AssignNode")
 	}
-	na.state = maState_finished
 	// Copy the content.
 	if v2, ok := v.(*plainMap); ok { // if our own type: shortcut.
 		// Copy the structure by value.
 		//  This means we'll have pointers into the same internal maps and slices;
 		//   this is okay, because the Node type promises it's immutable, and we are going to instantly finish ourselves to also maintain that.
+		na.begun = true
+		na.state = maState_finished
 		*na.w = *v2
 		return nil
 	}
-	// If the above shortcut didn't work, resort to a generic copy.
+	// If the above shortcut didn't work, resort to a generic copy: begin, assemble each entry, and finish, as any caller would.
 	//  We call AssignNode for all the child values, giving them a chance to hit shortcuts even if we didn't.
 	if v.ReprKind() != ipld.ReprKind_Map {
 		return ipld.ErrWrongKind{TypeName: "map", MethodName: "AssignNode", AppropriateKind: ipld.ReprKindSet_JustMap, ActualKind: v.ReprKind()}
 	}
+	if _, err := na.BeginMap(v.Length()); err != nil {
+		return err
+	}
 	itr := v.MapIterator()
 	for !itr.Done() {
 		k, v, err := itr.Next()
@@ -229,10 +329,10 @@ func (na *plainMap__Assembler) AssignNode(v ipld.Node) error {
 		}
 	}
 	// validators could run and report errors promptly, if this type had any -- same as for regular Finish.
-	return nil
+	return na.Finish()
 }
-func (plainMap__Assembler) Style() ipld.NodeStyle {
-	return Style__Map{}
+func (na *plainMap__Assembler) Style() ipld.NodeStyle {
+	return Style__Map{na.errorOnMisuse, na.maxScalarLength}
 }
 
 // -- MapAssembler -->
@@ -242,14 +342,18 @@ func (plainMap__Assembler) Style() ipld.NodeStyle {
 func (ma *plainMap__Assembler) AssembleEntry(k string) (ipld.NodeAssembler, error) {
 	// Sanity check, then update, assembler state.
 	if ma.state != maState_initial {
-		panic("misuse")
+		return nil, misuse(ma.errorOnMisuse, ma.state.String(), "AssembleEntry")
+	}
+	if err := checkLength(ma.maxScalarLength, ipld.ReprKind_String, len(k)); err != nil {
+		return nil, err
 	}
-	ma.state = maState_midValue
 	// Check for dup keys; error if so.
-	_, exists := ma.w.m[k]
+	//  (This is before the state update, so the assembler is still usable afterwards.)
+	_, exists := ma.w.m[mapKey{ipld.ReprKind_String, k}]
 	if exists {
 		return nil, ipld.ErrRepeatedMapKey{plainString(k)}
 	}
+	ma.state = maState_midValue
 	ma.w.t = append(ma.w.t, plainMap__Entry{k: plainString(k)})
 	// Make value assembler valid by giving it pointer back to whole 'ma'; yield it.
 	ma.va.ma = ma
@@ -261,7 +365,7 @@ func (ma *plainMap__Assembler) AssembleEntry(k string) (ipld.NodeAssembler, erro
 func (ma *plainMap__Assembler) AssembleKey() ipld.NodeAssembler {
 	// Sanity check, then update, assembler state.
 	if ma.state != maState_initial {
-		panic("misuse")
+		return errorAssembler{misuse(ma.errorOnMisuse, ma.state.String(), "AssembleKey")}
 	}
 	ma.state = maState_midKey
 	// Extend entry table.
@@ -276,7 +380,7 @@ func (ma *plainMap__Assembler) AssembleKey() ipld.NodeAssembler {
 func (ma *plainMap__Assembler) AssembleValue() ipld.NodeAssembler {
 	// Sanity check, then update, assembler state.
 	if ma.state != maState_expectValue {
-		panic("misuse")
+		return errorAssembler{misuse(ma.errorOnMisuse, ma.state.String(), "AssembleValue")}
 	}
 	ma.state = maState_midValue
 	// Make value assembler valid by giving it pointer back to whole 'ma'; yield it.
@@ -289,84 +393,211 @@ func (ma *plainMap__Assembler) AssembleValue() ipld.NodeAssembler {
 func (ma *plainMap__Assembler) Finish() error {
 	// Sanity check, then update, assembler state.
 	if ma.state != maState_initial {
-		panic("misuse")
+		return misuse(ma.errorOnMisuse, ma.state.String(), "Finish")
 	}
 	ma.state = maState_finished
 	// validators could run and report errors promptly, if this type had any.
 	return nil
 }
-func (plainMap__Assembler) KeyStyle() ipld.NodeStyle {
-	return Style__String{}
-}
-func (plainMap__Assembler) ValueStyle(_ string) ipld.NodeStyle {
-	return Style__Any{}
+func (ma *plainMap__Assembler) KeyStyle() ipld.NodeStyle {
+	return Style__String{MaxScalarLength: ma.maxScalarLength}
+}
+func (ma *plainMap__Assembler) ValueStyle(_ string) ipld.NodeStyle {
+	return Style__Any{ma.errorOnMisuse, ma.maxScalarLength}
+}
+
+// mapKey is how a key is stored in a plainMap's Go map: its kind, and its string form.
+//
+// Strings are used as they are.  The other scalar kinds with exactly one
+// string form are also accepted: ints become their base-10 form (e.g. "-12"),
+// bytes become a string of the same bytes (as with a Go string conversion),
+// and bools become "true" or "false".
+// The kind is part of the key, so, e.g., the int 12 and the string "12"
+// are different keys, and may both be in the same map.
+type mapKey struct {
+	kind ipld.ReprKind
+	s    string
+}
+
+// mapKeyOf returns the mapKey for a key node.
+// Kinds other than those mapKey describes (including floats, which have no
+// single canonical string form) are rejected with ErrWrongKind.
+func mapKeyOf(k ipld.Node) (mapKey, error) {
+	switch k.ReprKind() {
+	case ipld.ReprKind_String:
+		v, err := k.AsString()
+		return mapKey{ipld.ReprKind_String, v}, err
+	case ipld.ReprKind_Int:
+		v, err := k.AsInt()
+		return mapKey{ipld.ReprKind_Int, strconv.Itoa(v)}, err
+	case ipld.ReprKind_Bytes:
+		v, err := k.AsBytes()
+		return mapKey{ipld.ReprKind_Bytes, string(v)}, err
+	case ipld.ReprKind_Bool:
+		v, err := k.AsBool()
+		return mapKey{ipld.ReprKind_Bool, strconv.FormatBool(v)}, err
+	default:
+		return mapKey{}, ipld.ErrWrongKind{TypeName: "map key", MethodName: "mapKeyOf", AppropriateKind: mapKeyKinds, ActualKind: k.ReprKind()}
+	}
 }
 
+var mapKeyKinds = ipld.ReprKindSet{ipld.ReprKind_String, ipld.ReprKind_Int, ipld.ReprKind_Bytes, ipld.ReprKind_Bool}
+
 // -- MapAssembler.KeyAssembler -->
 
-func (plainMap__KeyAssembler) BeginMap(sizeHint int) (ipld.MapAssembler, error) {
+func (mka *plainMap__KeyAssembler) BeginMap(sizeHint int) (ipld.MapAssembler, error) {
+	if err := mka.stale("BeginMap"); err != nil {
+		return nil, err
+	}
+	mka.rollback()
 	return mixins.StringAssembler{"string"}.BeginMap(0)
 }
-func (plainMap__KeyAssembler) BeginList(sizeHint int) (ipld.ListAssembler, error) {
+func (mka *plainMap__KeyAssembler) BeginList(sizeHint int) (ipld.ListAssembler, error) {
+	if err := mka.stale("BeginList"); err != nil {
+		return nil, err
+	}
+	mka.rollback()
 	return mixins.StringAssembler{"string"}.BeginList(0)
 }
-func (plainMap__KeyAssembler) AssignNull() error {
+func (mka *plainMap__KeyAssembler) AssignNull() error {
+	if err := mka.stale("AssignNull"); err != nil {
+		return err
+	}
+	mka.rollback()
 	return mixins.StringAssembler{"string"}.AssignNull()
 }
-func (plainMap__KeyAssembler) AssignBool(bool) error {
-	return mixins.StringAssembler{"string"}.AssignBool(false)
+func (mka *plainMap__KeyAssembler) AssignBool(v bool) error {
+	if err := mka.stale("AssignBool"); err != nil {
+		return err
+	}
+	return mka.assign(mapKey{ipld.ReprKind_Bool, strconv.FormatBool(v)}, NewBool(v))
 }
-func (plainMap__KeyAssembler) AssignInt(int) error {
-	return mixins.StringAssembler{"string"}.AssignInt(0)
+func (mka *plainMap__KeyAssembler) AssignInt(v int) error {
+	if err := mka.stale("AssignInt"); err != nil {
+		return err
+	}
+	return mka.assign(mapKey{ipld.ReprKind_Int, strconv.Itoa(v)}, NewInt(v))
 }
-func (plainMap__KeyAssembler) AssignFloat(float64) error {
+func (mka *plainMap__KeyAssembler) AssignFloat(float64) error {
+	if err := mka.stale("AssignFloat"); err != nil {
+		return err
+	}
+	mka.rollback()
 	return mixins.StringAssembler{"string"}.AssignFloat(0)
 }
 func (mka *plainMap__KeyAssembler) AssignString(v string) error {
+	if err := mka.stale("AssignString"); err != nil {
+		return err
+	}
+	if err := checkLength(mka.ma.maxScalarLength, ipld.ReprKind_String, len(v)); err != nil {
+		mka.rollback()
+		return err
+	}
+	return mka.assign(mapKey{ipld.ReprKind_String, v}, nil)
+}
+func (mka *plainMap__KeyAssembler) AssignBytes(v []byte) error {
+	if err := mka.stale("AssignBytes"); err != nil {
+		return err
+	}
+	if err := checkLength(mka.ma.maxScalarLength, ipld.ReprKind_Bytes, len(v)); err != nil {
+		mka.rollback()
+		return err
+	}
+	s := string(v)
+	return mka.assign(mapKey{ipld.ReprKind_Bytes, s}, NewBytes([]byte(s))) // copied, as the caller may reuse v.
+}
+func (mka *plainMap__KeyAssembler) AssignLink(ipld.Link) error {
+	if err := mka.stale("AssignLink"); err != nil {
+		return err
+	}
+	mka.rollback()
+	return mixins.StringAssembler{"string"}.AssignLink(nil)
+}
+func (mka *plainMap__KeyAssembler) AssignNode(v ipld.Node) error {
+	if err := mka.stale("AssignNode"); err != nil {
+		return err
+	}
+	mk, err := mapKeyOf(v)
+	if err != nil {
+		mka.rollback()
+		return err
+	}
+	switch mk.kind {
+	case ipld.ReprKind_String:
+		return mka.assign(mk, nil)
+	case ipld.ReprKind_Bytes:
+		return mka.assign(mk, NewBytes([]byte(mk.s)))
+	default:
+		return mka.assign(mk, v)
+	}
+}
+
+// assign sets the key of the entry being assembled: mk is how it's stored,
+// and kn is the key node to yield back (nil for strings, which the entry holds itself).
+func (mka *plainMap__KeyAssembler) assign(mk mapKey, kn ipld.Node) error {
 	// Check for dup keys; error if so.
-	_, exists := mka.ma.w.m[v]
-	if exists {
-		return ipld.ErrRepeatedMapKey{plainString(v)}
+	if _, exists := mka.ma.w.m[mk]; exists {
+		mka.rollback()
+		if kn == nil {
+			kn = plainString(mk.s)
+		}
+		return ipld.ErrRepeatedMapKey{kn}
 	}
 	// Assign the key into the end of the entry table;
 	//  we'll be doing map insertions after we get the value in hand.
 	//  (There's no need to delegate to another assembler for the key type,
-	//   because we're just at Data Model level here, which only regards plain strings.)
-	mka.ma.w.t[len(mka.ma.w.t)-1].k = plainString(v)
+	//   because we're just at Data Model level here, which only regards scalars.)
+	e := &mka.ma.w.t[len(mka.ma.w.t)-1]
+	e.k = plainString(mk.s)
+	e.kn = kn
 	// Update parent assembler state: clear to proceed.
 	mka.ma.state = maState_expectValue
-	mka.ma = nil // invalidate self to prevent further incorrect use.
 	return nil
 }
-func (plainMap__KeyAssembler) AssignBytes([]byte) error {
-	return mixins.StringAssembler{"string"}.AssignBytes(nil)
-}
-func (plainMap__KeyAssembler) AssignLink(ipld.Link) error {
-	return mixins.StringAssembler{"string"}.AssignLink(nil)
-}
-func (mka *plainMap__KeyAssembler) AssignNode(v ipld.Node) error {
-	vs, err := v.AsString()
-	if err != nil {
-		return fmt.Errorf("cannot assign non-string node into map key assembler") // FIXME:errors: this doesn't quite fit in ErrWrongKind cleanly; new error type?
+
+// stale returns misuse if the map isn't waiting on this key any more:
+// it's already been assigned, or it failed.
+func (mka *plainMap__KeyAssembler) stale(method string) error {
+	if mka.ma.state != maState_midKey {
+		return misuse(mka.ma.errorOnMisuse, mka.ma.state.String(), method)
 	}
-	return mka.AssignString(vs)
+	return nil
+}
+
+// rollback drops the entry table row AssembleKey added for the key,
+// and returns the map assembler to its initial state, so the caller may carry on.
+func (mka *plainMap__KeyAssembler) rollback() {
+	mka.ma.w.t = mka.ma.w.t[:len(mka.ma.w.t)-1]
+	mka.ma.state = maState_initial
 }
-func (plainMap__KeyAssembler) Style() ipld.NodeStyle {
-	return Style__String{}
+func (mka *plainMap__KeyAssembler) Style() ipld.NodeStyle {
+	return Style__String{MaxScalarLength: mka.ma.maxScalarLength}
 }
 
 // -- MapAssembler.ValueAssembler -->
 
 func (mva *plainMap__ValueAssembler) BeginMap(sizeHint int) (ipld.MapAssembler, error) {
+	if err := mva.stale("BeginMap"); err != nil {
+		return nil, err
+	}
 	ma := plainMap__ValueAssemblerMap{}
-	ma.ca.w = &plainMap{}
+	ma.ca.w = newMap(mva.ma.alloc)
+	ma.ca.alloc = mva.ma.alloc
+	ma.ca.errorOnMisuse = mva.ma.errorOnMisuse
+	ma.ca.maxScalarLength = mva.ma.maxScalarLength
 	ma.p = mva.ma
 	_, err := ma.ca.BeginMap(sizeHint)
 	return &ma, err
 }
 func (mva *plainMap__ValueAssembler) BeginList(sizeHint int) (ipld.ListAssembler, error) {
+	if err := mva.stale("BeginList"); err != nil {
+		return nil, err
+	}
 	la := plainMap__ValueAssemblerList{}
-	la.ca.w = &plainList{}
+	la.ca.w = newList(mva.ma.alloc)
+	la.ca.alloc = mva.ma.alloc
+	la.ca.errorOnMisuse = mva.ma.errorOnMisuse
+	la.ca.maxScalarLength = mva.ma.maxScalarLength
 	la.p = mva.ma
 	_, err := la.ca.BeginList(sizeHint)
 	return &la, err
@@ -375,39 +606,76 @@ func (mva *plainMap__ValueAssembler) AssignNull() error {
 	return mva.AssignNode(ipld.Null)
 }
 func (mva *plainMap__ValueAssembler) AssignBool(v bool) error {
-	vb := plainBool(v)
-	return mva.AssignNode(&vb)
+	return mva.AssignNode(newBool(mva.ma.alloc, v))
 }
 func (mva *plainMap__ValueAssembler) AssignInt(v int) error {
-	vb := plainInt(v)
-	return mva.AssignNode(&vb)
+	return mva.AssignNode(newInt(mva.ma.alloc, v))
 }
 func (mva *plainMap__ValueAssembler) AssignFloat(v float64) error {
-	vb := plainFloat(v)
-	return mva.AssignNode(&vb)
+	if err := mva.stale("AssignFloat"); err != nil {
+		return err
+	}
+	if err := checkFloat(v); err != nil {
+		mva.rollback()
+		return err
+	}
+	return mva.AssignNode(newFloat(mva.ma.alloc, v))
 }
 func (mva *plainMap__ValueAssembler) AssignString(v string) error {
-	vb := plainString(v)
-	return mva.AssignNode(&vb)
+	if err := mva.stale("AssignString"); err != nil {
+		return err
+	}
+	if err := checkLength(mva.ma.maxScalarLength, ipld.ReprKind_String, len(v)); err != nil {
+		mva.rollback()
+		return err
+	}
+	return mva.AssignNode(newString(mva.ma.alloc, v))
 }
 func (mva *plainMap__ValueAssembler) AssignBytes(v []byte) error {
-	vb := plainBytes(v)
-	return mva.AssignNode(&vb)
+	if err := mva.stale("AssignBytes"); err != nil {
+		return err
+	}
+	if err := checkLength(mva.ma.maxScalarLength, ipld.ReprKind_Bytes, len(v)); err != nil {
+		mva.rollback()
+		return err
+	}
+	return mva.AssignNode(newBytes(mva.ma.alloc, v))
 }
 func (mva *plainMap__ValueAssembler) AssignLink(v ipld.Link) error {
-	vb := plainLink{v}
-	return mva.AssignNode(&vb)
+	return mva.AssignNode(newLink(mva.ma.alloc, v))
 }
 func (mva *plainMap__ValueAssembler) AssignNode(v ipld.Node) error {
-	l := len(mva.ma.w.t) - 1
-	mva.ma.w.t[l].v = v
-	mva.ma.w.m[string(mva.ma.w.t[l].k)] = v
+	if err := mva.stale("AssignNode"); err != nil {
+		return err
+	}
+	if err := checkFloatNode(v); err != nil {
+		mva.rollback()
+		return err
+	}
+	e := &mva.ma.w.t[len(mva.ma.w.t)-1]
+	e.v = v
+	mva.ma.w.m[e.mapKey()] = v
 	mva.ma.state = maState_initial
-	mva.ma = nil // invalidate self to prevent further incorrect use.
 	return nil
 }
-func (plainMap__ValueAssembler) Style() ipld.NodeStyle {
-	return Style__Any{}
+
+// stale returns misuse if the map isn't waiting on this value any more:
+// it's already been assigned, or it failed.
+func (mva *plainMap__ValueAssembler) stale(method string) error {
+	if mva.ma.state != maState_midValue {
+		return misuse(mva.ma.errorOnMisuse, mva.ma.state.String(), method)
+	}
+	return nil
+}
+
+// rollback drops the entry whose value was being assembled (its key isn't in 'w.m' yet),
+// and returns the map assembler to its initial state, so the caller may carry on.
+func (mva *plainMap__ValueAssembler) rollback() {
+	mva.ma.w.t = mva.ma.w.t[:len(mva.ma.w.t)-1]
+	mva.ma.state = maState_initial
+}
+func (mva *plainMap__ValueAssembler) Style() ipld.NodeStyle {
+	return Style__Any{mva.ma.errorOnMisuse, mva.ma.maxScalarLength}
 }
 
 type plainMap__ValueAssemblerMap struct {
@@ -428,11 +696,11 @@ func (ma *plainMap__ValueAssemblerMap) AssembleKey() ipld.NodeAssembler {
 func (ma *plainMap__ValueAssemblerMap) AssembleValue() ipld.NodeAssembler {
 	return ma.ca.AssembleValue()
 }
-func (plainMap__ValueAssemblerMap) KeyStyle() ipld.NodeStyle {
-	return Style__String{}
+func (ma *plainMap__ValueAssemblerMap) KeyStyle() ipld.NodeStyle {
+	return ma.ca.KeyStyle()
 }
-func (plainMap__ValueAssemblerMap) ValueStyle(_ string) ipld.NodeStyle {
-	return Style__Any{}
+func (ma *plainMap__ValueAssemblerMap) ValueStyle(_ string) ipld.NodeStyle {
+	return Style__Any{ma.ca.errorOnMisuse, ma.ca.maxScalarLength}
 }
 
 func (ma *plainMap__ValueAssemblerMap) Finish() error {
@@ -456,8 +724,8 @@ type plainMap__ValueAssemblerList struct {
 func (la *plainMap__ValueAssemblerList) AssembleValue() ipld.NodeAssembler {
 	return la.ca.AssembleValue()
 }
-func (plainMap__ValueAssemblerList) ValueStyle(_ int) ipld.NodeStyle {
-	return Style__Any{}
+func (la *plainMap__ValueAssemblerList) ValueStyle(_ int) ipld.NodeStyle {
+	return Style__Any{la.ca.errorOnMisuse, la.ca.maxScalarLength}
 }
 
 func (la *plainMap__ValueAssemblerList) Finish() error {
@@ -1,8 +1,14 @@
 package basicnode
 
 import (
+	"fmt"
+	"math"
 	"testing"
 
+	. "github.com/warpfork/go-wish"
+
+	ipld "github.com/ipld/go-ipld-prime"
+	"github.com/ipld/go-ipld-prime/fluent"
 	"github.com/ipld/go-ipld-prime/node/tests"
 )
 
@@ -47,3 +53,218 @@ func BenchmarkSpec_Unmarshal_Map3StrInt(b *testing.B) {
 func BenchmarkSpec_Unmarshal_MapNStrMap3StrInt(b *testing.B) {
 	tests.BenchmarkSpec_Unmarshal_MapNStrMap3StrInt(b, Style__Map{})
 }
+
+func TestMapHas(t *testing.T) {
+	n := buildStrIntMap(3)
+	has, err := ipld.Has(n, NewString("k1"))
+	Wish(t, err, ShouldEqual, nil)
+	Wish(t, has, ShouldEqual, true)
+	has, err = ipld.Has(n, NewString("nope"))
+	Wish(t, err, ShouldEqual, nil)
+	Wish(t, has, ShouldEqual, false)
+	_, err = ipld.Has(n, NewFloat(1))
+	Wish(t, err, ShouldBeSameTypeAs, ipld.ErrWrongKind{})
+}
+
+func TestMapAssemblerRecovery(t *testing.T) {
+	t.Run("failed value lets the same key be retried", func(t *testing.T) {
+		nb := Style__Map{}.NewBuilder()
+		ma, _ := nb.BeginMap(2)
+		va, err := ma.AssembleEntry("whee")
+		Require(t, err, ShouldEqual, nil)
+		Wish(t, va.AssignFloat(math.NaN()), ShouldBeSameTypeAs, ipld.ErrInvalidFloat{})
+		va, err = ma.AssembleEntry("whee")
+		Require(t, err, ShouldEqual, nil)
+		Wish(t, va.AssignInt(1), ShouldEqual, nil)
+		Wish(t, ma.Finish(), ShouldEqual, nil)
+		Wish(t, ipld.Sprint(nb.Build()), ShouldEqual, `{"whee": 1}`)
+	})
+	t.Run("failed key lets assembly carry on", func(t *testing.T) {
+		nb := Style__Map{}.NewBuilder()
+		ma, _ := nb.BeginMap(2)
+		Wish(t, ma.AssembleKey().AssignFloat(1.5), ShouldBeSameTypeAs, ipld.ErrWrongKind{})
+		_, err := ma.AssembleKey().BeginList(0)
+		Wish(t, err, ShouldBeSameTypeAs, ipld.ErrWrongKind{})
+		Wish(t, ma.AssembleKey().AssignString("whee"), ShouldEqual, nil)
+		Wish(t, ma.AssembleValue().AssignInt(1), ShouldEqual, nil)
+		Wish(t, ma.Finish(), ShouldEqual, nil)
+		Wish(t, ipld.Sprint(nb.Build()), ShouldEqual, `{"whee": 1}`)
+	})
+	t.Run("repeated key leaves the assembler usable", func(t *testing.T) {
+		nb := Style__Map{}.NewBuilder()
+		ma, _ := nb.BeginMap(2)
+		va, _ := ma.AssembleEntry("whee")
+		Wish(t, va.AssignInt(1), ShouldEqual, nil)
+		_, err := ma.AssembleEntry("whee")
+		Wish(t, err, ShouldBeSameTypeAs, ipld.ErrRepeatedMapKey{})
+		Wish(t, ma.AssembleKey().AssignString("whee"), ShouldBeSameTypeAs, ipld.ErrRepeatedMapKey{})
+		va, err = ma.AssembleEntry("woot")
+		Require(t, err, ShouldEqual, nil)
+		Wish(t, va.AssignInt(2), ShouldEqual, nil)
+		Wish(t, ma.Finish(), ShouldEqual, nil)
+		Wish(t, ipld.Sprint(nb.Build()), ShouldEqual, `{"whee": 1, "woot": 2}`)
+	})
+}
+
+// otherNode hides the implementation of the node it wraps, as a node from another package would.
+type otherNode struct {
+	ipld.Node
+}
+
+func TestAssignNodeFromElsewhere(t *testing.T) {
+	m := fluent.MustBuildMap(Style__Map{}, 2, func(na fluent.MapAssembler) {
+		na.AssembleEntry("a").AssignInt(1)
+		na.AssembleEntry("b").CreateList(1, func(na fluent.ListAssembler) {
+			na.AssembleValue().AssignInt(2)
+		})
+	})
+	t.Run("map", func(t *testing.T) {
+		nb := Style__Map{}.NewBuilder()
+		Wish(t, nb.AssignNode(otherNode{m}), ShouldEqual, nil)
+		Wish(t, ipld.Sprint(nb.Build()), ShouldEqual, `{"a": 1, "b": [2]}`)
+	})
+	t.Run("list", func(t *testing.T) {
+		l, _ := m.LookupString("b")
+		nb := Style__List{}.NewBuilder()
+		Wish(t, nb.AssignNode(otherNode{l}), ShouldEqual, nil)
+		Wish(t, ipld.Sprint(nb.Build()), ShouldEqual, `[2]`)
+	})
+}
+
+func TestMapScalarKeys(t *testing.T) {
+	nb := Style__Map{}.NewBuilder()
+	ma, _ := nb.BeginMap(4)
+	Wish(t, ma.AssembleKey().AssignInt(-12), ShouldEqual, nil)
+	Wish(t, ma.AssembleValue().AssignString("int"), ShouldEqual, nil)
+	Wish(t, ma.AssembleKey().AssignBytes([]byte{0x61, 0xff}), ShouldEqual, nil)
+	Wish(t, ma.AssembleValue().AssignString("bytes"), ShouldEqual, nil)
+	Wish(t, ma.AssembleKey().AssignNode(NewBool(true)), ShouldEqual, nil)
+	Wish(t, ma.AssembleValue().AssignString("bool"), ShouldEqual, nil)
+	Wish(t, ma.AssembleKey().AssignString("s"), ShouldEqual, nil)
+	Wish(t, ma.AssembleValue().AssignString("string"), ShouldEqual, nil)
+	Wish(t, ma.Finish(), ShouldEqual, nil)
+	n := nb.Build()
+
+	// A key assembler failure rolls the map assembler back, so one can take all of these.
+	ma, _ = Style__Map{}.NewBuilder().BeginMap(1)
+	Wish(t, ma.AssembleKey().AssignFloat(1.5), ShouldBeSameTypeAs, ipld.ErrWrongKind{})
+	Wish(t, ma.AssembleKey().AssignNode(NewFloat(1.5)), ShouldBeSameTypeAs, ipld.ErrWrongKind{})
+	Wish(t, ma.AssembleKey().AssignNode(ipld.Null), ShouldBeSameTypeAs, ipld.ErrWrongKind{})
+	t.Run("keys of different kinds are different keys", func(t *testing.T) {
+		nb := Style__Map{}.NewBuilder()
+		ma, _ := nb.BeginMap(2)
+		Wish(t, ma.AssembleKey().AssignString("12"), ShouldEqual, nil)
+		Wish(t, ma.AssembleValue().AssignString("string"), ShouldEqual, nil)
+		Wish(t, ma.AssembleKey().AssignInt(12), ShouldEqual, nil)
+		Wish(t, ma.AssembleValue().AssignString("int"), ShouldEqual, nil)
+		Wish(t, ma.AssembleKey().AssignNode(NewInt(12)), ShouldEqual, ipld.ErrRepeatedMapKey{NewInt(12)})
+		Wish(t, ma.Finish(), ShouldEqual, nil)
+		n := nb.Build()
+		Wish(t, n.Length(), ShouldEqual, 2)
+		v, _ := n.Lookup(NewInt(12))
+		Wish(t, v, ShouldEqual, NewString("int"))
+		v, _ = n.LookupString("12")
+		Wish(t, v, ShouldEqual, NewString("string"))
+	})
+
+	for _, tc := range []struct {
+		key  ipld.Node
+		want string
+	}{
+		{NewInt(-12), "int"},
+		{NewBytes([]byte{0x61, 0xff}), "bytes"},
+		{NewBool(true), "bool"},
+		{NewString("s"), "string"},
+	} {
+		v, err := n.Lookup(tc.key)
+		Wish(t, err, ShouldEqual, nil)
+		Wish(t, v, ShouldEqual, NewString(tc.want))
+	}
+	for _, key := range []ipld.Node{NewString("-12"), NewString("a\xff"), NewString("true"), NewBytes([]byte("s"))} {
+		_, err := n.Lookup(key)
+		Wish(t, err, ShouldBeSameTypeAs, ipld.ErrNotExists{})
+	}
+	_, err := n.Lookup(ipld.Null)
+	Wish(t, err, ShouldBeSameTypeAs, ipld.ErrWrongKind{})
+	// Keys are yielded back as the kind they were given as.
+	Wish(t, ipld.Sprint(n), ShouldEqual, `{-12: "int", bytes(61ff): "bytes", true: "bool", "s": "string"}`)
+}
+
+func buildStrIntMap(size int) ipld.Node {
+	nb := Style__Map{}.NewBuilder()
+	ma, _ := nb.BeginMap(size)
+	for i := 0; i < size; i++ {
+		ma.AssembleKey().AssignString(fmt.Sprintf("k%d", i))
+		ma.AssembleValue().AssignInt(i)
+	}
+	ma.Finish()
+	return nb.Build()
+}
+
+func BenchmarkMapHas_10000n(b *testing.B) {
+	n := buildStrIntMap(10000)
+	k := NewString("k5000")
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		has, err := ipld.Has(n, k)
+		if !has || err != nil {
+			b.Fatal(has, err)
+		}
+	}
+}
+func BenchmarkMapHasViaLookup_10000n(b *testing.B) {
+	n := buildStrIntMap(10000)
+	k := NewString("k5000")
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, err := n.Lookup(k)
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func TestMapForEach(t *testing.T) {
+	n := buildStrIntMap(3)
+	var keys []string
+	err := ipld.ForEach(n, func(k, v ipld.Node) error {
+		ks, _ := k.AsString()
+		keys = append(keys, ks)
+		return nil
+	})
+	Wish(t, err, ShouldEqual, nil)
+	Wish(t, keys, ShouldEqual, []string{"k0", "k1", "k2"})
+
+	stop := fmt.Errorf("stop")
+	calls := 0
+	err = ipld.ForEach(n, func(k, v ipld.Node) error {
+		calls++
+		return stop
+	})
+	Wish(t, err, ShouldEqual, stop)
+	Wish(t, calls, ShouldEqual, 1)
+
+	err = ipld.ForEach(NewInt(1), func(k, v ipld.Node) error { return nil })
+	Wish(t, err, ShouldBeSameTypeAs, ipld.ErrWrongKind{})
+}
+
+func BenchmarkMapForEach_1000n(b *testing.B) {
+	n := buildStrIntMap(1000)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		ipld.ForEach(n, func(k, v ipld.Node) error {
+			return nil
+		})
+	}
+}
+func BenchmarkMapIterator_1000n(b *testing.B) {
+	n := buildStrIntMap(1000)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for itr := n.MapIterator(); !itr.Done(); {
+			itr.Next()
+		}
+	}
+}
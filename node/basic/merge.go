@@ -0,0 +1,89 @@
+package basicnode
+
+import (
+	ipld "github.com/ipld/go-ipld-prime"
+)
+
+// MergeMaps builds a new map containing the entries of both base and overlay.
+//
+// Keys present in only one of the two maps are copied as they are.
+// For keys present in both, resolve is called with the key and both values,
+// and the node it returns is used; if resolve is nil, the overlay's value wins.
+// (A resolver which calls MergeMaps again when both values are maps
+// gives a deep merge.)
+//
+// The result keeps base's key order, followed by the overlay-only keys
+// in the overlay's order.
+// Both nodes must be maps; otherwise ErrWrongKind is returned.
+// Any error from resolve is returned as-is.
+//
+// The result is always a new map built with Style__Map;
+// neither input is modified.
+// (ipld.NodeStyleSupportingAmend isn't used: it doesn't yet say how an
+// amending builder treats a key that's already present, which is exactly
+// the case a merge needs.)
+func MergeMaps(base, overlay ipld.Node, resolve func(k, bv, ov ipld.Node) (ipld.Node, error)) (ipld.Node, error) {
+	for _, n := range []ipld.Node{base, overlay} {
+		if n.ReprKind() != ipld.ReprKind_Map {
+			return nil, ipld.ErrWrongKind{MethodName: "basicnode.MergeMaps", AppropriateKind: ipld.ReprKindSet_JustMap, ActualKind: n.ReprKind()}
+		}
+	}
+	nb := Style__Map{}.NewBuilder()
+	ma, err := nb.BeginMap(base.Length() + overlay.Length())
+	if err != nil {
+		return nil, err
+	}
+	for itr := base.MapIterator(); !itr.Done(); {
+		k, bv, err := itr.Next()
+		if err != nil {
+			return nil, err
+		}
+		v := bv
+		ov, err := overlay.Lookup(k)
+		switch err.(type) {
+		case nil:
+			v = ov
+			if resolve != nil {
+				if v, err = resolve(k, bv, ov); err != nil {
+					return nil, err
+				}
+			}
+		case ipld.ErrNotExists:
+			// base only.
+		default:
+			return nil, err
+		}
+		if err := assembleEntry(ma, k, v); err != nil {
+			return nil, err
+		}
+	}
+	for itr := overlay.MapIterator(); !itr.Done(); {
+		k, ov, err := itr.Next()
+		if err != nil {
+			return nil, err
+		}
+		_, err = base.Lookup(k)
+		switch err.(type) {
+		case nil:
+			continue // already handled above.
+		case ipld.ErrNotExists:
+			// overlay only.
+		default:
+			return nil, err
+		}
+		if err := assembleEntry(ma, k, ov); err != nil {
+			return nil, err
+		}
+	}
+	if err := ma.Finish(); err != nil {
+		return nil, err
+	}
+	return nb.Build(), nil
+}
+
+func assembleEntry(ma ipld.MapAssembler, k, v ipld.Node) error {
+	if err := ma.AssembleKey().AssignNode(k); err != nil {
+		return err
+	}
+	return ma.AssembleValue().AssignNode(v)
+}
@@ -0,0 +1,56 @@
+package basicnode
+
+import (
+	"testing"
+
+	. "github.com/warpfork/go-wish"
+
+	ipld "github.com/ipld/go-ipld-prime"
+	"github.com/ipld/go-ipld-prime/fluent"
+)
+
+func TestMergeMaps(t *testing.T) {
+	base := fluent.MustBuildMap(Style__Map{}, 3, func(na fluent.MapAssembler) {
+		na.AssembleEntry("a").AssignInt(1)
+		na.AssembleEntry("b").CreateMap(2, func(na fluent.MapAssembler) {
+			na.AssembleEntry("x").AssignInt(1)
+			na.AssembleEntry("y").AssignInt(2)
+		})
+		na.AssembleEntry("c").AssignString("base")
+	})
+	overlay := fluent.MustBuildMap(Style__Map{}, 3, func(na fluent.MapAssembler) {
+		na.AssembleEntry("d").AssignBool(true)
+		na.AssembleEntry("c").AssignString("overlay")
+		na.AssembleEntry("b").CreateMap(2, func(na fluent.MapAssembler) {
+			na.AssembleEntry("z").AssignInt(3)
+			na.AssembleEntry("y").AssignInt(20)
+		})
+	})
+	t.Run("overlay wins", func(t *testing.T) {
+		n, err := MergeMaps(base, overlay, nil)
+		Wish(t, err, ShouldEqual, nil)
+		Wish(t, ipld.Sprint(n), ShouldEqual, `{"a": 1, "b": {"z": 3, "y": 20}, "c": "overlay", "d": true}`)
+	})
+	t.Run("deep merge", func(t *testing.T) {
+		var deep func(k, bv, ov ipld.Node) (ipld.Node, error)
+		deep = func(k, bv, ov ipld.Node) (ipld.Node, error) {
+			if bv.ReprKind() == ipld.ReprKind_Map && ov.ReprKind() == ipld.ReprKind_Map {
+				return MergeMaps(bv, ov, deep)
+			}
+			return ov, nil
+		}
+		n, err := MergeMaps(base, overlay, deep)
+		Wish(t, err, ShouldEqual, nil)
+		Wish(t, ipld.Sprint(n), ShouldEqual, `{"a": 1, "b": {"x": 1, "y": 20, "z": 3}, "c": "overlay", "d": true}`)
+	})
+	t.Run("resolver error", func(t *testing.T) {
+		_, err := MergeMaps(base, overlay, func(k, bv, ov ipld.Node) (ipld.Node, error) {
+			return nil, ipld.ErrNotExists{ipld.PathSegmentOfString("nope")}
+		})
+		Wish(t, err, ShouldEqual, ipld.ErrNotExists{ipld.PathSegmentOfString("nope")})
+	})
+	t.Run("not a map", func(t *testing.T) {
+		_, err := MergeMaps(base, NewInt(1), nil)
+		Wish(t, err, ShouldBeSameTypeAs, ipld.ErrWrongKind{})
+	})
+}
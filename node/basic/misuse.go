@@ -0,0 +1,45 @@
+package basicnode
+
+import (
+	ipld "github.com/ipld/go-ipld-prime"
+)
+
+// misuse is called by an assembler which finds itself in the wrong state for a method.
+// It panics, or, if errorOnMisuse is set, returns an ipld.ErrAssemblerMisuse
+// (see the ErrorOnMisuse field of Style__Map).
+func misuse(errorOnMisuse bool, state string, method string) error {
+	if !errorOnMisuse {
+		panic("misuse")
+	}
+	return ipld.ErrAssemblerMisuse{State: state, Method: method}
+}
+
+func (s maState) String() string {
+	switch s {
+	case maState_initial:
+		return "initial"
+	case maState_midKey:
+		return "midKey"
+	case maState_expectValue:
+		return "expectValue"
+	case maState_midValue:
+		return "midValue"
+	case maState_finished:
+		return "finished"
+	default:
+		return "invalid"
+	}
+}
+
+func (s laState) String() string {
+	switch s {
+	case laState_initial:
+		return "initial"
+	case laState_midValue:
+		return "midValue"
+	case laState_finished:
+		return "finished"
+	default:
+		return "invalid"
+	}
+}
@@ -0,0 +1,162 @@
+package basicnode
+
+import (
+	"testing"
+
+	. "github.com/warpfork/go-wish"
+
+	ipld "github.com/ipld/go-ipld-prime"
+)
+
+func TestAssemblerMisuse(t *testing.T) {
+	t.Run("panics by default", func(t *testing.T) {
+		ma, err := Style__Map{}.NewBuilder().BeginMap(1)
+		Wish(t, err, ShouldEqual, nil)
+		defer func() {
+			Wish(t, recover(), ShouldEqual, "misuse")
+		}()
+		ma.AssembleValue()
+	})
+	t.Run("lenient", func(t *testing.T) {
+		t.Run("map AssembleValue before key", func(t *testing.T) {
+			ma, err := Style__Map{ErrorOnMisuse: true}.NewBuilder().BeginMap(1)
+			Wish(t, err, ShouldEqual, nil)
+			err = ma.AssembleValue().AssignString("x")
+			Wish(t, err, ShouldEqual, ipld.ErrAssemblerMisuse{State: "initial", Method: "AssembleValue"})
+			Wish(t, err.Error(), ShouldEqual, "assembler misuse: AssembleValue called in state initial")
+			// Nothing changed, so the assembler can still be used correctly.
+			Wish(t, ma.AssembleKey().AssignString("k"), ShouldEqual, nil)
+			Wish(t, ma.AssembleValue().AssignString("v"), ShouldEqual, nil)
+			Wish(t, ma.Finish(), ShouldEqual, nil)
+		})
+		t.Run("map Finish mid-entry", func(t *testing.T) {
+			ma, _ := Style__Map{ErrorOnMisuse: true}.NewBuilder().BeginMap(1)
+			Wish(t, ma.AssembleKey().AssignString("k"), ShouldEqual, nil)
+			Wish(t, ma.Finish(), ShouldEqual, ipld.ErrAssemblerMisuse{State: "expectValue", Method: "Finish"})
+		})
+		t.Run("map key or value assigned twice", func(t *testing.T) {
+			nb := Style__Map{ErrorOnMisuse: true}.NewBuilder()
+			ma, _ := nb.BeginMap(1)
+			ka := ma.AssembleKey()
+			Wish(t, ka.AssignString("k"), ShouldEqual, nil)
+			Wish(t, ka.AssignString("k2"), ShouldEqual, ipld.ErrAssemblerMisuse{State: "expectValue", Method: "AssignString"})
+			va := ma.AssembleValue()
+			Wish(t, va.AssignInt(1), ShouldEqual, nil)
+			Wish(t, va.AssignInt(2), ShouldEqual, ipld.ErrAssemblerMisuse{State: "initial", Method: "AssignNode"})
+			Wish(t, ma.Finish(), ShouldEqual, nil)
+			Wish(t, ipld.Sprint(nb.Build()), ShouldEqual, `{"k": 1}`)
+		})
+		t.Run("map begun twice", func(t *testing.T) {
+			nb := Style__Map{ErrorOnMisuse: true}.NewBuilder()
+			nb.BeginMap(0)
+			_, err := nb.BeginMap(0)
+			Wish(t, err, ShouldEqual, ipld.ErrAssemblerMisuse{State: "begun", Method: "BeginMap"})
+		})
+		t.Run("list begun twice", func(t *testing.T) {
+			nb := Style__List{ErrorOnMisuse: true}.NewBuilder()
+			la, _ := nb.BeginList(1)
+			Wish(t, la.AssembleValue().AssignInt(1), ShouldEqual, nil)
+			_, err := nb.BeginList(0)
+			Wish(t, err, ShouldEqual, ipld.ErrAssemblerMisuse{State: "begun", Method: "BeginList"})
+			Wish(t, nb.AssignNode(NewInt(1)), ShouldEqual, ipld.ErrAssemblerMisuse{State: "begun", Method: "AssignNode"})
+			// The list begun first is untouched.
+			Wish(t, la.Finish(), ShouldEqual, nil)
+			Wish(t, ipld.Sprint(nb.Build()), ShouldEqual, `[1]`)
+		})
+		t.Run("list after Finish", func(t *testing.T) {
+			la, _ := Style__List{ErrorOnMisuse: true}.NewBuilder().BeginList(0)
+			Wish(t, la.Finish(), ShouldEqual, nil)
+			Wish(t, la.AssembleValue().AssignInt(1), ShouldEqual, ipld.ErrAssemblerMisuse{State: "finished", Method: "AssembleValue"})
+			Wish(t, la.Finish(), ShouldEqual, ipld.ErrAssemblerMisuse{State: "finished", Method: "Finish"})
+		})
+		t.Run("any assigned twice", func(t *testing.T) {
+			nb := Style__Any{ErrorOnMisuse: true}.NewBuilder()
+			Wish(t, nb.AssignInt(1), ShouldEqual, nil)
+			Wish(t, nb.AssignInt(2), ShouldEqual, ipld.ErrAssemblerMisuse{State: "begun", Method: "AssignInt"})
+			_, err := nb.BeginMap(0)
+			Wish(t, err, ShouldEqual, ipld.ErrAssemblerMisuse{State: "begun", Method: "BeginMap"})
+			Wish(t, ipld.Sprint(nb.Build()), ShouldEqual, `1`)
+		})
+		t.Run("carries on to nested maps and lists", func(t *testing.T) {
+			ma, _ := Style__Map{ErrorOnMisuse: true}.NewBuilder().BeginMap(1)
+			Wish(t, ma.ValueStyle("k"), ShouldEqual, Style__Any{ErrorOnMisuse: true})
+			va, _ := ma.AssembleEntry("k")
+			la, _ := va.BeginList(1)
+			Wish(t, la.Finish(), ShouldEqual, nil)
+			Wish(t, la.Finish(), ShouldEqual, ipld.ErrAssemblerMisuse{State: "finished", Method: "Finish"})
+		})
+		t.Run("only for the style which asked", func(t *testing.T) {
+			ma, _ := Style__Map{ErrorOnMisuse: true}.NewBuilder().BeginMap(1)
+			Wish(t, ma.AssembleValue(), ShouldBeSameTypeAs, errorAssembler{})
+			ma, _ = Style__Map{}.NewBuilder().BeginMap(1)
+			defer func() {
+				Wish(t, recover(), ShouldEqual, "misuse")
+			}()
+			ma.AssembleValue()
+		})
+	})
+}
+
+func TestBuilderConsumed(t *testing.T) {
+	t.Run("scalar", func(t *testing.T) {
+		nb := Style__String{}.NewBuilder()
+		Wish(t, nb.AssignString("x"), ShouldEqual, nil)
+		n := nb.Build()
+		err := nb.AssignString("y")
+		Wish(t, err, ShouldEqual, ipld.ErrBuilderConsumed{"AssignString"})
+		Wish(t, err.Error(), ShouldEqual, "builder consumed: AssignString called after Build, without a Reset")
+		Wish(t, nb.AssignNode(NewString("y")), ShouldEqual, ipld.ErrBuilderConsumed{"AssignString"})
+		s, _ := n.AsString()
+		Wish(t, s, ShouldEqual, "x")
+		// Build again just returns the same node.
+		Wish(t, nb.Build() == n, ShouldEqual, true)
+		// Reset makes the builder usable again, without touching what it built before.
+		nb.Reset()
+		Wish(t, nb.AssignString("z"), ShouldEqual, nil)
+		s, _ = nb.Build().AsString()
+		Wish(t, s, ShouldEqual, "z")
+		s, _ = n.AsString()
+		Wish(t, s, ShouldEqual, "x")
+	})
+	t.Run("every scalar kind", func(t *testing.T) {
+		for _, ns := range []ipld.NodeStyle{Style__Bool{}, Style__Int{}, Style__Float{}, Style__Bytes{}, Style__ChunkedBytes{}, Style__Link{}} {
+			nb := ns.NewBuilder()
+			n := nb.Build()
+			Wish(t, nb.AssignNode(n), ShouldBeSameTypeAs, ipld.ErrBuilderConsumed{})
+			Wish(t, nb.Build() == n, ShouldEqual, true)
+		}
+	})
+	t.Run("map", func(t *testing.T) {
+		nb := Style__Map{}.NewBuilder()
+		ma, _ := nb.BeginMap(1)
+		Wish(t, ma.AssembleKey().AssignString("k"), ShouldEqual, nil)
+		Wish(t, ma.AssembleValue().AssignString("v"), ShouldEqual, nil)
+		Wish(t, ma.Finish(), ShouldEqual, nil)
+		n := nb.Build()
+		_, err := nb.BeginMap(0)
+		Wish(t, err, ShouldEqual, ipld.ErrBuilderConsumed{"BeginMap"})
+		Wish(t, nb.AssignNode(n), ShouldEqual, ipld.ErrBuilderConsumed{"AssignNode"})
+		Wish(t, n.Length(), ShouldEqual, 1)
+		Wish(t, nb.Build() == n, ShouldEqual, true)
+	})
+	t.Run("list", func(t *testing.T) {
+		nb := Style__List{}.NewBuilder()
+		la, _ := nb.BeginList(0)
+		Wish(t, la.Finish(), ShouldEqual, nil)
+		n := nb.Build()
+		_, err := nb.BeginList(0)
+		Wish(t, err, ShouldEqual, ipld.ErrBuilderConsumed{"BeginList"})
+		Wish(t, nb.Build() == n, ShouldEqual, true)
+	})
+	t.Run("any", func(t *testing.T) {
+		nb := Style__Any{}.NewBuilder()
+		Wish(t, nb.AssignInt(1), ShouldEqual, nil)
+		n := nb.Build()
+		Wish(t, nb.AssignInt(2), ShouldEqual, ipld.ErrBuilderConsumed{"AssignInt"})
+		_, err := nb.BeginMap(0)
+		Wish(t, err, ShouldEqual, ipld.ErrBuilderConsumed{"BeginMap"})
+		Wish(t, nb.Build() == n, ShouldEqual, true)
+		nb.Reset()
+		Wish(t, nb.AssignInt(2), ShouldEqual, nil)
+	})
+}
@@ -0,0 +1,166 @@
+package basicnode
+
+import (
+	"sort"
+
+	ipld "github.com/ipld/go-ipld-prime"
+)
+
+var (
+	_ ipld.Node          = &plainSortedMap{}
+	_ ipld.NodeStyle     = Style__SortedMap{}
+	_ ipld.NodeBuilder   = &plainSortedMap__Builder{}
+	_ ipld.NodeAssembler = &plainSortedMap__Assembler{}
+)
+
+// plainSortedMap is a map-kind ipld.Node whose iterator always yields
+// entries in sorted key order (bytewise, as with Go's string comparison),
+// regardless of the order they were inserted in.
+// Keys of other kinds sort by their string form (see mapKey),
+// and keys with the same string form by kind.
+//
+// It's a plainMap in every other respect: the entry table is simply
+// sorted once, when the assembler finishes.
+type plainSortedMap struct {
+	plainMap
+}
+
+func (plainSortedMap) Style() ipld.NodeStyle {
+	return Style__SortedMap{}
+}
+
+// sortEntries sorts the entry table by key.
+func (n *plainSortedMap) sortEntries() {
+	sort.Slice(n.t, func(i, j int) bool {
+		if n.t[i].k != n.t[j].k {
+			return n.t[i].k < n.t[j].k
+		}
+		return n.t[i].mapKey().kind < n.t[j].mapKey().kind
+	})
+}
+
+// -- NodeStyle -->
+
+// Style__SortedMap builds map nodes which iterate in sorted key order.
+// Keys may be assembled in any order.
+//
+// Only the map itself is sorted: maps assembled as values inside it
+// are ordinary insertion-order maps (the value style is Style__Any).
+type Style__SortedMap struct {
+	ErrorOnMisuse   bool // as for Style__Map.
+	MaxScalarLength int  // as for Style__Map.
+}
+
+func (ns Style__SortedMap) NewBuilder() ipld.NodeBuilder {
+	w := &plainSortedMap{}
+	return &plainSortedMap__Builder{plainSortedMap__Assembler{w: w, ma: plainMap__Assembler{w: &w.plainMap, errorOnMisuse: ns.ErrorOnMisuse, maxScalarLength: ns.MaxScalarLength}}}
+}
+
+// -- NodeBuilder -->
+
+type plainSortedMap__Builder struct {
+	plainSortedMap__Assembler
+}
+
+func (nb *plainSortedMap__Builder) Build() ipld.Node {
+	if nb.ma.state != maState_finished {
+		panic("invalid state: assembler must be 'finished' before Build can be called!")
+	}
+	nb.ma.built = true
+	return nb.w
+}
+func (nb *plainSortedMap__Builder) Reset() {
+	w := &plainSortedMap{}
+	*nb = plainSortedMap__Builder{plainSortedMap__Assembler{w: w, ma: plainMap__Assembler{w: &w.plainMap, errorOnMisuse: nb.ma.errorOnMisuse, maxScalarLength: nb.ma.maxScalarLength}}}
+}
+
+// -- NodeAssembler -->
+
+// plainSortedMap__Assembler delegates everything to a plainMap__Assembler
+// writing into the embedded plainMap, and just sorts when that's finished.
+type plainSortedMap__Assembler struct {
+	w  *plainSortedMap
+	ma plainMap__Assembler
+}
+
+func (na *plainSortedMap__Assembler) BeginMap(sizeHint int) (ipld.MapAssembler, error) {
+	if _, err := na.ma.BeginMap(sizeHint); err != nil {
+		return nil, err
+	}
+	return na, nil
+}
+func (na *plainSortedMap__Assembler) BeginList(sizeHint int) (ipld.ListAssembler, error) {
+	return na.ma.BeginList(sizeHint)
+}
+func (na *plainSortedMap__Assembler) AssignNull() error {
+	return na.ma.AssignNull()
+}
+func (na *plainSortedMap__Assembler) AssignBool(v bool) error {
+	return na.ma.AssignBool(v)
+}
+func (na *plainSortedMap__Assembler) AssignInt(v int) error {
+	return na.ma.AssignInt(v)
+}
+func (na *plainSortedMap__Assembler) AssignFloat(v float64) error {
+	return na.ma.AssignFloat(v)
+}
+func (na *plainSortedMap__Assembler) AssignString(v string) error {
+	return na.ma.AssignString(v)
+}
+func (na *plainSortedMap__Assembler) AssignBytes(v []byte) error {
+	return na.ma.AssignBytes(v)
+}
+func (na *plainSortedMap__Assembler) AssignLink(v ipld.Link) error {
+	return na.ma.AssignLink(v)
+}
+func (na *plainSortedMap__Assembler) AssignNode(v ipld.Node) error {
+	if v2, ok := v.(*plainSortedMap); ok { // if our own type: shortcut; it's already sorted.
+		if na.ma.built {
+			return ipld.ErrBuilderConsumed{"AssignNode"}
+		}
+		if na.ma.begun {
+			return misuse(na.ma.errorOnMisuse, "begun", "AssignNode")
+		}
+		na.ma.begun = true
+		na.ma.state = maState_finished
+		*na.w = *v2
+		return nil
+	}
+	if err := na.ma.AssignNode(v); err != nil {
+		return err
+	}
+	if _, ok := v.(*plainMap); ok {
+		// The plainMap shortcut shares the other node's entry table; copy it before sorting.
+		na.w.t = append([]plainMap__Entry(nil), na.w.t...)
+	}
+	na.w.sortEntries()
+	return nil
+}
+func (na *plainSortedMap__Assembler) Style() ipld.NodeStyle {
+	return Style__SortedMap{na.ma.errorOnMisuse, na.ma.maxScalarLength}
+}
+
+// -- MapAssembler -->
+
+func (na *plainSortedMap__Assembler) AssembleEntry(k string) (ipld.NodeAssembler, error) {
+	return na.ma.AssembleEntry(k)
+}
+func (na *plainSortedMap__Assembler) AssembleKey() ipld.NodeAssembler {
+	return na.ma.AssembleKey()
+}
+func (na *plainSortedMap__Assembler) AssembleValue() ipld.NodeAssembler {
+	return na.ma.AssembleValue()
+}
+func (na *plainSortedMap__Assembler) Finish() error {
+	if err := na.ma.Finish(); err != nil {
+		return err
+	}
+	na.w.sortEntries()
+	return nil
+}
+func (na *plainSortedMap__Assembler) KeyStyle() ipld.NodeStyle {
+	return na.ma.KeyStyle()
+}
+func (na *plainSortedMap__Assembler) ValueStyle(k string) ipld.NodeStyle {
+	return na.ma.ValueStyle(k)
+}
@@ -0,0 +1,92 @@
+package basicnode
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+	"testing"
+
+	. "github.com/warpfork/go-wish"
+
+	ipld "github.com/ipld/go-ipld-prime"
+)
+
+func TestSortedMap(t *testing.T) {
+	keys := make([]string, 50)
+	for i := range keys {
+		keys[i] = fmt.Sprintf("k%d", i)
+	}
+	rand.New(rand.NewSource(1)).Shuffle(len(keys), func(i, j int) {
+		keys[i], keys[j] = keys[j], keys[i]
+	})
+	sorted := append([]string(nil), keys...)
+	sort.Strings(sorted)
+
+	nb := Style__SortedMap{}.NewBuilder()
+	ma, err := nb.BeginMap(len(keys))
+	Wish(t, err, ShouldEqual, nil)
+	for i, k := range keys {
+		if i%2 == 0 {
+			Wish(t, ma.AssembleKey().AssignString(k), ShouldEqual, nil)
+			Wish(t, ma.AssembleValue().AssignString(k), ShouldEqual, nil)
+		} else {
+			va, err := ma.AssembleEntry(k)
+			Wish(t, err, ShouldEqual, nil)
+			Wish(t, va.AssignString(k), ShouldEqual, nil)
+		}
+	}
+	Wish(t, ma.Finish(), ShouldEqual, nil)
+	n := nb.Build()
+
+	Wish(t, n.Style(), ShouldEqual, Style__SortedMap{})
+	Wish(t, n.Length(), ShouldEqual, len(keys))
+	Wish(t, iterKeys(t, n), ShouldEqual, sorted)
+	v, err := n.LookupString("k7")
+	Wish(t, err, ShouldEqual, nil)
+	Wish(t, v, ShouldEqual, NewString("k7"))
+
+	t.Run("repeated key", func(t *testing.T) {
+		ma, _ := Style__SortedMap{}.NewBuilder().BeginMap(2)
+		Wish(t, ma.AssembleKey().AssignString("a"), ShouldEqual, nil)
+		Wish(t, ma.AssembleValue().AssignNull(), ShouldEqual, nil)
+		Wish(t, ma.AssembleKey().AssignString("a"), ShouldEqual, ipld.ErrRepeatedMapKey{plainString("a")})
+	})
+	t.Run("keys of other kinds", func(t *testing.T) {
+		nb := Style__SortedMap{}.NewBuilder()
+		ma, _ := nb.BeginMap(3)
+		ma.AssembleKey().AssignString("2")
+		ma.AssembleValue().AssignNull()
+		ma.AssembleKey().AssignInt(2)
+		ma.AssembleValue().AssignNull()
+		ma.AssembleKey().AssignInt(10)
+		ma.AssembleValue().AssignNull()
+		Wish(t, ma.Finish(), ShouldEqual, nil)
+		Wish(t, ipld.Sprint(nb.Build()), ShouldEqual, `{10: null, 2: null, "2": null}`)
+	})
+	t.Run("AssignNode from an insertion-order map", func(t *testing.T) {
+		nb := Style__Map{}.NewBuilder()
+		ma, _ := nb.BeginMap(2)
+		ma.AssembleKey().AssignString("b")
+		ma.AssembleValue().AssignInt(1)
+		ma.AssembleKey().AssignString("a")
+		ma.AssembleValue().AssignInt(2)
+		ma.Finish()
+		plain := nb.Build()
+
+		nb2 := Style__SortedMap{}.NewBuilder()
+		Wish(t, nb2.AssignNode(plain), ShouldEqual, nil)
+		Wish(t, iterKeys(t, nb2.Build()), ShouldEqual, []string{"a", "b"})
+		Wish(t, iterKeys(t, plain), ShouldEqual, []string{"b", "a"}) // the source is untouched.
+	})
+}
+
+func iterKeys(t *testing.T, n ipld.Node) []string {
+	var keys []string
+	for itr := n.MapIterator(); !itr.Done(); {
+		k, _, err := itr.Next()
+		Wish(t, err, ShouldEqual, nil)
+		ks, _ := k.AsString()
+		keys = append(keys, ks)
+	}
+	return keys
+}
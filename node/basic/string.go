@@ -1,6 +1,8 @@
 package basicnode
 
 import (
+	"unicode/utf8"
+
 	ipld "github.com/ipld/go-ipld-prime"
 	"github.com/ipld/go-ipld-prime/node/mixins"
 )
@@ -81,11 +83,25 @@ func (plainString) Style() ipld.NodeStyle {
 
 // -- NodeStyle -->
 
-type Style__String struct{}
+// Style__String builds string nodes.
+//
+// By default, any string is accepted, whether or not it's valid UTF-8,
+// because some data (DAG-CBOR from other implementations, for example) carries strings which aren't.
+// Set StrictUTF8 to have AssignString (and AssignNode) reject those,
+// with ipld.ErrInvalidUTF8 giving the offset of the first invalid byte.
+//
+// (Strings assembled as values inside maps, lists, or Style__Any always use the permissive policy.)
+//
+// MaxScalarLength, if positive, is the longest string AssignString accepts,
+// as for Style__Map.
+type Style__String struct {
+	StrictUTF8      bool
+	MaxScalarLength int
+}
 
-func (Style__String) NewBuilder() ipld.NodeBuilder {
+func (ns Style__String) NewBuilder() ipld.NodeBuilder {
 	var w plainString
-	return &plainString__Builder{plainString__Assembler{w: &w}}
+	return &plainString__Builder{plainString__Assembler{w: &w, strictUTF8: ns.StrictUTF8, maxScalarLength: ns.MaxScalarLength}}
 }
 
 // -- NodeBuilder -->
@@ -95,17 +111,22 @@ type plainString__Builder struct {
 }
 
 func (nb *plainString__Builder) Build() ipld.Node {
+	nb.built = true // we mustn't mutate the node we hand out; see HACKME.md.
 	return nb.w
 }
 func (nb *plainString__Builder) Reset() {
 	var w plainString
-	*nb = plainString__Builder{plainString__Assembler{w: &w}}
+	*nb = plainString__Builder{plainString__Assembler{w: &w, strictUTF8: nb.strictUTF8, maxScalarLength: nb.maxScalarLength}}
 }
 
 // -- NodeAssembler -->
 
 type plainString__Assembler struct {
-	w *plainString
+	w     *plainString
+	built bool // set by Build, after which w belongs to the node it returned; see HACKME.md.
+
+	strictUTF8      bool
+	maxScalarLength int
 }
 
 func (plainString__Assembler) BeginMap(sizeHint int) (ipld.MapAssembler, error) {
@@ -127,6 +148,17 @@ func (plainString__Assembler) AssignFloat(float64) error {
 	return mixins.StringAssembler{"string"}.AssignFloat(0)
 }
 func (na *plainString__Assembler) AssignString(v string) error {
+	if na.built {
+		return ipld.ErrBuilderConsumed{"AssignString"}
+	}
+	if err := checkLength(na.maxScalarLength, ipld.ReprKind_String, len(v)); err != nil {
+		return err
+	}
+	if na.strictUTF8 {
+		if err := checkUTF8(v); err != nil {
+			return err
+		}
+	}
 	*na.w = plainString(v)
 	return nil
 }
@@ -140,10 +172,33 @@ func (na *plainString__Assembler) AssignNode(v ipld.Node) error {
 	if v2, err := v.AsString(); err != nil {
 		return err
 	} else {
-		*na.w = plainString(v2)
+		return na.AssignString(v2)
+	}
+}
+func (na plainString__Assembler) Style() ipld.NodeStyle {
+	return Style__String{na.strictUTF8, na.maxScalarLength}
+}
+
+// checkUTF8 returns ipld.ErrInvalidUTF8 if s isn't valid UTF-8.
+func checkUTF8(s string) error {
+	if utf8.ValidString(s) {
 		return nil
 	}
+	for i := 0; i < len(s); {
+		r, size := utf8.DecodeRuneInString(s[i:])
+		if r == utf8.RuneError && size == 1 {
+			return ipld.ErrInvalidUTF8{Offset: i}
+		}
+		i += size
+	}
+	return nil // unreachable: ValidString said there was something.
 }
-func (plainString__Assembler) Style() ipld.NodeStyle {
-	return Style__String{}
+
+// checkLength returns ipld.ErrBudgetExceeded if n is longer than max,
+// when max is positive (see the MaxScalarLength field of Style__Map).
+func checkLength(max int, k ipld.ReprKind, n int) error {
+	if max > 0 && n > max {
+		return ipld.ErrBudgetExceeded{Kind: k, Length: n, Budget: max}
+	}
+	return nil
 }
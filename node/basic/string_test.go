@@ -3,9 +3,82 @@ package basicnode
 import (
 	"testing"
 
+	. "github.com/warpfork/go-wish"
+
+	ipld "github.com/ipld/go-ipld-prime"
 	"github.com/ipld/go-ipld-prime/node/tests"
 )
 
 func TestString(t *testing.T) {
 	tests.SpecTestString(t, Style__String{})
 }
+
+func TestStringStrictUTF8(t *testing.T) {
+	t.Run("permissive by default", func(t *testing.T) {
+		nb := Style__String{}.NewBuilder()
+		Wish(t, nb.AssignString("a\xffb"), ShouldEqual, nil)
+		v, err := nb.Build().AsString()
+		Wish(t, err, ShouldEqual, nil)
+		Wish(t, v, ShouldEqual, "a\xffb")
+	})
+	t.Run("strict when asked", func(t *testing.T) {
+		for _, tc := range []struct {
+			s   string
+			err error
+		}{
+			{"héllo, 世界", nil},
+			{"a\xffb", ipld.ErrInvalidUTF8{Offset: 1}},
+			{"héllo\xe4\xb8", ipld.ErrInvalidUTF8{Offset: 6}},  // truncated rune at the end.
+			{"\xc0\x80", ipld.ErrInvalidUTF8{Offset: 0}},       // overlong encoding of NUL.
+			{"ok\xed\xa0\x80", ipld.ErrInvalidUTF8{Offset: 2}}, // a surrogate.
+		} {
+			nb := Style__String{StrictUTF8: true}.NewBuilder()
+			Wish(t, nb.AssignString(tc.s), ShouldEqual, tc.err)
+		}
+		nb := Style__String{StrictUTF8: true}.NewBuilder()
+		Wish(t, nb.AssignNode(NewString("a\xffb")), ShouldEqual, ipld.ErrInvalidUTF8{Offset: 1})
+		nb.Reset()
+		Wish(t, nb.AssignString("\xff"), ShouldEqual, ipld.ErrInvalidUTF8{Offset: 0})
+		Wish(t, nb.Style(), ShouldEqual, Style__String{StrictUTF8: true})
+	})
+}
+
+func TestMaxScalarLength(t *testing.T) {
+	big := "abcde"
+	over := func(k ipld.ReprKind) error {
+		return ipld.ErrBudgetExceeded{Kind: k, Length: 5, Budget: 4}
+	}
+	t.Run("scalars", func(t *testing.T) {
+		nb := Style__Any{MaxScalarLength: 4}.NewBuilder()
+		Wish(t, nb.AssignString(big), ShouldEqual, over(ipld.ReprKind_String))
+		Wish(t, nb.AssignBytes([]byte(big)), ShouldEqual, over(ipld.ReprKind_Bytes))
+		Wish(t, nb.AssignString("abcd"), ShouldEqual, nil)
+		nb = Style__String{MaxScalarLength: 4}.NewBuilder()
+		Wish(t, nb.AssignString(big), ShouldEqual, over(ipld.ReprKind_String))
+		nb = Style__Bytes{MaxScalarLength: 4}.NewBuilder()
+		Wish(t, nb.AssignBytes([]byte(big)), ShouldEqual, over(ipld.ReprKind_Bytes))
+	})
+	t.Run("within maps and lists", func(t *testing.T) {
+		// Each rejection leaves the assembler as it was, so assembly can carry on.
+		nb := Style__Map{MaxScalarLength: 4}.NewBuilder()
+		ma, _ := nb.BeginMap(4)
+		_, err := ma.AssembleEntry(big)
+		Wish(t, err, ShouldEqual, over(ipld.ReprKind_String))
+		Wish(t, ma.AssembleKey().AssignString(big), ShouldEqual, over(ipld.ReprKind_String))
+		Wish(t, ma.AssembleKey().AssignBytes([]byte(big)), ShouldEqual, over(ipld.ReprKind_Bytes))
+		va, _ := ma.AssembleEntry("a")
+		Wish(t, va.AssignString(big), ShouldEqual, over(ipld.ReprKind_String))
+		va, _ = ma.AssembleEntry("a")
+		la, _ := va.BeginList(2)
+		Wish(t, la.AssembleValue().AssignBytes([]byte(big)), ShouldEqual, over(ipld.ReprKind_Bytes))
+		ma2, _ := la.AssembleValue().BeginMap(1)
+		_, err = ma2.AssembleEntry(big)
+		Wish(t, err, ShouldEqual, over(ipld.ReprKind_String))
+		Wish(t, ma2.Finish(), ShouldEqual, nil)
+		Wish(t, la.AssembleValue().AssignString("abcd"), ShouldEqual, nil)
+		Wish(t, la.Finish(), ShouldEqual, nil)
+		Wish(t, ma.Finish(), ShouldEqual, nil)
+		Wish(t, ipld.Sprint(nb.Build()), ShouldEqual, `{"a": [{}, "abcd"]}`)
+		Wish(t, ma.KeyStyle(), ShouldEqual, Style__String{MaxScalarLength: 4})
+	})
+}
@@ -11,16 +11,23 @@ package basicnode
 //
 // Most of the styles here are for one particular Kind of node (e.g. string, int, etc);
 // you can use the "Any" style if you want a builder that can accept any kind of data.
+//
+// Per ipld.SameStyle, nodes and assemblers from this package have the same style
+// only when they have the same implementation: e.g. Style__Map and Style__Any
+// are different styles, even though both can build maps.
+// Styles with options (e.g. Style__Float) are the same only when the options are too.
 var Style style
 
 type style struct {
-	Any    Style__Any
-	Map    Style__Map
-	List   Style__List
-	Bool   Style__Bool
-	Int    Style__Int
-	Float  Style__Float
-	String Style__String
-	Bytes  Style__Bytes
-	Link   Style__Link
+	Any          Style__Any
+	Map          Style__Map
+	SortedMap    Style__SortedMap
+	List         Style__List
+	Bool         Style__Bool
+	Int          Style__Int
+	Float        Style__Float
+	String       Style__String
+	Bytes        Style__Bytes
+	ChunkedBytes Style__ChunkedBytes
+	Link         Style__Link
 }
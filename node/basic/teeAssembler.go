@@ -0,0 +1,169 @@
+package basicnode
+
+import (
+	ipld "github.com/ipld/go-ipld-prime"
+)
+
+var (
+	_ ipld.NodeAssembler = teeAssembler{}
+	_ ipld.MapAssembler  = teeMapAssembler{}
+	_ ipld.ListAssembler = teeListAssembler{}
+)
+
+// TeeAssembler wraps a NodeAssembler so that every scalar assigned anywhere
+// in the tree it builds -- map keys included -- is first shown to onScalar.
+// If onScalar returns an error, the value isn't forwarded to the inner
+// assembler, and the error is returned from the Assign call instead.
+// (As with any assembler error, the assembly should then be abandoned.)
+//
+// Everything else is delegated to the inner assembler as-is.
+// Scalars are given to onScalar boxed as basicnode values (e.g. by NewInt).
+// When a whole node is given to AssignNode, all of the scalars within it are
+// checked before it's forwarded, so the inner assembler's own AssignNode
+// (and any shortcuts it takes) still gets used.
+func TeeAssembler(inner ipld.NodeAssembler, onScalar func(ipld.Node) error) ipld.NodeAssembler {
+	return teeAssembler{inner, onScalar}
+}
+
+type teeAssembler struct {
+	na       ipld.NodeAssembler
+	onScalar func(ipld.Node) error
+}
+
+func (ta teeAssembler) BeginMap(sizeHint int) (ipld.MapAssembler, error) {
+	ma, err := ta.na.BeginMap(sizeHint)
+	if err != nil {
+		return nil, err
+	}
+	return teeMapAssembler{ma, ta.onScalar}, nil
+}
+func (ta teeAssembler) BeginList(sizeHint int) (ipld.ListAssembler, error) {
+	la, err := ta.na.BeginList(sizeHint)
+	if err != nil {
+		return nil, err
+	}
+	return teeListAssembler{la, ta.onScalar}, nil
+}
+func (ta teeAssembler) AssignNull() error {
+	if err := ta.onScalar(ipld.Null); err != nil {
+		return err
+	}
+	return ta.na.AssignNull()
+}
+func (ta teeAssembler) AssignBool(v bool) error {
+	if err := ta.onScalar(NewBool(v)); err != nil {
+		return err
+	}
+	return ta.na.AssignBool(v)
+}
+func (ta teeAssembler) AssignInt(v int) error {
+	if err := ta.onScalar(NewInt(v)); err != nil {
+		return err
+	}
+	return ta.na.AssignInt(v)
+}
+func (ta teeAssembler) AssignFloat(v float64) error {
+	if err := ta.onScalar(NewFloat(v)); err != nil {
+		return err
+	}
+	return ta.na.AssignFloat(v)
+}
+func (ta teeAssembler) AssignString(v string) error {
+	if err := ta.onScalar(NewString(v)); err != nil {
+		return err
+	}
+	return ta.na.AssignString(v)
+}
+func (ta teeAssembler) AssignBytes(v []byte) error {
+	if err := ta.onScalar(NewBytes(v)); err != nil {
+		return err
+	}
+	return ta.na.AssignBytes(v)
+}
+func (ta teeAssembler) AssignLink(v ipld.Link) error {
+	if err := ta.onScalar(NewLink(v)); err != nil {
+		return err
+	}
+	return ta.na.AssignLink(v)
+}
+func (ta teeAssembler) AssignNode(v ipld.Node) error {
+	if err := forEachScalar(v, ta.onScalar); err != nil {
+		return err
+	}
+	return ta.na.AssignNode(v)
+}
+func (ta teeAssembler) Style() ipld.NodeStyle {
+	return ta.na.Style()
+}
+
+// forEachScalar calls fn on every scalar in n (including map keys), depth-first.
+func forEachScalar(n ipld.Node, fn func(ipld.Node) error) error {
+	switch n.ReprKind() {
+	case ipld.ReprKind_Map:
+		return ipld.ForEach(n, func(k ipld.Node, v ipld.Node) error {
+			if err := forEachScalar(k, fn); err != nil {
+				return err
+			}
+			return forEachScalar(v, fn)
+		})
+	case ipld.ReprKind_List:
+		for itr := n.ListIterator(); !itr.Done(); {
+			_, v, err := itr.Next()
+			if err != nil {
+				return err
+			}
+			if err := forEachScalar(v, fn); err != nil {
+				return err
+			}
+		}
+		return nil
+	default:
+		return fn(n)
+	}
+}
+
+type teeMapAssembler struct {
+	ma       ipld.MapAssembler
+	onScalar func(ipld.Node) error
+}
+
+func (tma teeMapAssembler) AssembleKey() ipld.NodeAssembler {
+	return teeAssembler{tma.ma.AssembleKey(), tma.onScalar}
+}
+func (tma teeMapAssembler) AssembleValue() ipld.NodeAssembler {
+	return teeAssembler{tma.ma.AssembleValue(), tma.onScalar}
+}
+func (tma teeMapAssembler) AssembleEntry(k string) (ipld.NodeAssembler, error) {
+	if err := tma.onScalar(NewString(k)); err != nil {
+		return nil, err
+	}
+	va, err := tma.ma.AssembleEntry(k)
+	if err != nil {
+		return nil, err
+	}
+	return teeAssembler{va, tma.onScalar}, nil
+}
+func (tma teeMapAssembler) Finish() error {
+	return tma.ma.Finish()
+}
+func (tma teeMapAssembler) KeyStyle() ipld.NodeStyle {
+	return tma.ma.KeyStyle()
+}
+func (tma teeMapAssembler) ValueStyle(k string) ipld.NodeStyle {
+	return tma.ma.ValueStyle(k)
+}
+
+type teeListAssembler struct {
+	la       ipld.ListAssembler
+	onScalar func(ipld.Node) error
+}
+
+func (tla teeListAssembler) AssembleValue() ipld.NodeAssembler {
+	return teeAssembler{tla.la.AssembleValue(), tla.onScalar}
+}
+func (tla teeListAssembler) Finish() error {
+	return tla.la.Finish()
+}
+func (tla teeListAssembler) ValueStyle(idx int) ipld.NodeStyle {
+	return tla.la.ValueStyle(idx)
+}
@@ -0,0 +1,62 @@
+package basicnode
+
+import (
+	"fmt"
+	"testing"
+
+	. "github.com/warpfork/go-wish"
+
+	ipld "github.com/ipld/go-ipld-prime"
+	"github.com/ipld/go-ipld-prime/fluent"
+)
+
+func TestTeeAssembler(t *testing.T) {
+	errNegative := fmt.Errorf("negative ints not allowed")
+	noNegatives := func(n ipld.Node) error {
+		if v, err := n.AsInt(); err == nil && v < 0 {
+			return errNegative
+		}
+		return nil
+	}
+	t.Run("valid tree builds as usual", func(t *testing.T) {
+		var seen []string
+		nb := Style__Any{}.NewBuilder()
+		err := fluent.Recover(func() {
+			fluent.WrapAssembler(TeeAssembler(nb, func(n ipld.Node) error {
+				seen = append(seen, ipld.Sprint(n))
+				return nil
+			})).CreateMap(2, func(na fluent.MapAssembler) {
+				na.AssembleEntry("a").AssignInt(1)
+				na.AssembleEntry("b").CreateList(2, func(na fluent.ListAssembler) {
+					na.AssembleValue().AssignString("x")
+					na.AssembleValue().AssignNull()
+				})
+			})
+		})
+		Wish(t, err, ShouldEqual, nil)
+		Wish(t, seen, ShouldEqual, []string{`"a"`, `1`, `"b"`, `"x"`, `null`})
+		Wish(t, ipld.Sprint(nb.Build()), ShouldEqual, `{"a": 1, "b": ["x", null]}`)
+	})
+	t.Run("negative int nested in a list is rejected", func(t *testing.T) {
+		nb := Style__Any{}.NewBuilder()
+		err := fluent.Recover(func() {
+			fluent.WrapAssembler(TeeAssembler(nb, noNegatives)).CreateMap(1, func(na fluent.MapAssembler) {
+				na.AssembleEntry("list").CreateList(3, func(na fluent.ListAssembler) {
+					na.AssembleValue().AssignInt(1)
+					na.AssembleValue().AssignInt(-2)
+					na.AssembleValue().AssignInt(3)
+				})
+			})
+		})
+		Wish(t, err, ShouldEqual, fluent.Error{errNegative})
+	})
+	t.Run("AssignNode checks the whole node", func(t *testing.T) {
+		n := fluent.MustBuildList(Style__List{}, 1, func(na fluent.ListAssembler) {
+			na.AssembleValue().CreateList(1, func(na fluent.ListAssembler) {
+				na.AssembleValue().AssignInt(-1)
+			})
+		})
+		nb := Style__Any{}.NewBuilder()
+		Wish(t, TeeAssembler(nb, noNegatives).AssignNode(n), ShouldEqual, errNegative)
+	})
+}
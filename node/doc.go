@@ -12,11 +12,16 @@
 	This includes standardized behavioral tests (!), which are
 	in the 'node/mixins/tests' package.
 
+	The 'node/reflect' package presents golang native values as (read-only)
+	Nodes by use of reflection.
+
+	The 'node/lazy' package provides a Node which holds serial data,
+	and only decodes it when it's first used.
+
 	Other planned subpackages include:
 	a cbor-native Node implementation (which can optimize performance in some
 	cases by lazily parsing serial	data, and also retaining it as byte slice
 	references for minimizing reserialization work for small mutations);
-	a Node implementation which works over golang native types by use of reflection;
 	a Node implementation which supports Schema type constraints and works
 	without compile-time/codegen support by delegating storage to another Node implementation;
 	etc.
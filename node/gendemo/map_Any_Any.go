@@ -0,0 +1,586 @@
+package gendemo
+
+// Map_Any_Any and this file is how a codegen'd map type with keys of any kind could work.
+// In contrast with Map_K2_T2, which indexes its entries with a native Go map (and so needs its key type to be
+// a comparable Go struct), this one orders its keys with ipld.Compare, and looks them up by binary search
+// in a sorted index.  Any Node can be a key -- maps and lists included -- and lookups are O(log n) comparisons.
+// Iteration still follows insertion order.
+//
+// ipld.Compare goes through maps in their iteration order, which would make {"a":1,"b":2} and {"b":2,"a":1}
+// two different keys.  So the index compares keys in a canonical form instead, with every map's entries
+// sorted (see canonicalKey): maps with the same entries are the same key, whatever order they came in.
+
+import (
+	"sort"
+
+	ipld "github.com/ipld/go-ipld-prime"
+	basicnode "github.com/ipld/go-ipld-prime/node/basic"
+	"github.com/ipld/go-ipld-prime/node/mixins"
+)
+
+/*	ipldsch:
+	type Root struct { mp {Any:Any} } # as with Map_K2_T2, the anonymous map is the point.
+*/
+
+type Map_Any_Any struct {
+	t   []_Map_Any_Any__entry // in insertion order; used for iteration.
+	idx []int                 // indexes into 't', sorted by canonical key (per ipld.Compare); used for lookup.
+}
+
+type _Map_Any_Any__entry struct {
+	k, v ipld.Node
+	ck   ipld.Node // k in canonical form (see canonicalKey), which is what the index compares.
+}
+
+// canonicalKey returns k in the form the index compares: k itself, unless it is or contains a map;
+// then a copy, with every map's entries sorted by their own canonical keys.
+// (Sorted maps are built as Map_Any_Any, since a key may be a map with keys of any kind.)
+func canonicalKey(k ipld.Node) (ipld.Node, error) {
+	ck, _, err := canonicalize(k)
+	return ck, err
+}
+
+// canonicalize does the work of canonicalKey, and also says whether the result differs from n,
+// so that lists without maps in them needn't be copied.
+func canonicalize(n ipld.Node) (ipld.Node, bool, error) {
+	switch n.ReprKind() {
+	case ipld.ReprKind_Map:
+		m := &Map_Any_Any{t: make([]_Map_Any_Any__entry, 0, n.Length())}
+		for itr := n.MapIterator(); !itr.Done(); {
+			k, v, err := itr.Next()
+			if err != nil {
+				return nil, false, err
+			}
+			if k, _, err = canonicalize(k); err != nil {
+				return nil, false, err
+			}
+			if v, _, err = canonicalize(v); err != nil {
+				return nil, false, err
+			}
+			m.t = append(m.t, _Map_Any_Any__entry{k, v, k})
+		}
+		sort.Slice(m.t, func(i, j int) bool {
+			return ipld.Compare(m.t[i].ck, m.t[j].ck) < 0
+		})
+		m.idx = make([]int, len(m.t))
+		for i := range m.idx {
+			m.idx[i] = i
+		}
+		return m, true, nil
+	case ipld.ReprKind_List:
+		vs := make([]ipld.Node, 0, n.Length())
+		changed := false
+		for itr := n.ListIterator(); !itr.Done(); {
+			_, v, err := itr.Next()
+			if err != nil {
+				return nil, false, err
+			}
+			v, c, err := canonicalize(v)
+			if err != nil {
+				return nil, false, err
+			}
+			vs = append(vs, v)
+			changed = changed || c
+		}
+		if !changed {
+			return n, false, nil
+		}
+		nb := basicnode.Style__List{}.NewBuilder()
+		la, err := nb.BeginList(len(vs))
+		if err != nil {
+			return nil, false, err
+		}
+		for _, v := range vs {
+			if err := la.AssembleValue().AssignNode(v); err != nil {
+				return nil, false, err
+			}
+		}
+		if err := la.Finish(); err != nil {
+			return nil, false, err
+		}
+		return nb.Build(), true, nil
+	default:
+		return n, false, nil
+	}
+}
+
+// searchRun looks for key (in canonical form) among the entries of 't' which 'run' indexes (in key order),
+// and returns the entry's index in 't', if it's there.
+func searchRun(t []_Map_Any_Any__entry, run []int, key ipld.Node) (int, bool) {
+	i := sort.Search(len(run), func(i int) bool {
+		return ipld.Compare(t[run[i]].ck, key) >= 0
+	})
+	if i < len(run) && ipld.Compare(t[run[i]].ck, key) == 0 {
+		return run[i], true
+	}
+	return 0, false
+}
+
+// mergeRuns merges two runs of indexes into 't' (each in key order, with no key in both) into one.
+func mergeRuns(t []_Map_Any_Any__entry, a, b []int) []int {
+	merged := make([]int, 0, len(a)+len(b))
+	for len(a) > 0 && len(b) > 0 {
+		if ipld.Compare(t[a[0]].ck, t[b[0]].ck) < 0 {
+			merged, a = append(merged, a[0]), a[1:]
+		} else {
+			merged, b = append(merged, b[0]), b[1:]
+		}
+	}
+	merged = append(merged, a...)
+	return append(merged, b...)
+}
+
+func (Map_Any_Any) ReprKind() ipld.ReprKind {
+	return ipld.ReprKind_Map
+}
+func (n *Map_Any_Any) LookupString(key string) (ipld.Node, error) {
+	return n.Lookup(plainString(key))
+}
+func (n *Map_Any_Any) Lookup(key ipld.Node) (ipld.Node, error) {
+	ck, err := canonicalKey(key)
+	if err != nil {
+		return nil, err
+	}
+	i, exists := searchRun(n.t, n.idx, ck)
+	if !exists {
+		if ks, err := key.AsString(); err == nil {
+			return nil, ipld.ErrNotExists{ipld.PathSegmentOfString(ks)}
+		}
+		return nil, ipld.ErrNotExists{ipld.PathSegmentOfString(ipld.Sprint(key))}
+	}
+	return n.t[i].v, nil
+}
+func (Map_Any_Any) LookupIndex(idx int) (ipld.Node, error) {
+	return nil, ipld.ErrWrongKind{TypeName: "Map_Any_Any", MethodName: "LookupIndex", AppropriateKind: ipld.ReprKindSet_JustList, ActualKind: ipld.ReprKind_Map}
+}
+func (n *Map_Any_Any) LookupSegment(seg ipld.PathSegment) (ipld.Node, error) {
+	return n.LookupString(seg.String())
+}
+func (n *Map_Any_Any) MapIterator() ipld.MapIterator {
+	return &_Map_Any_Any_MapIterator{n, 0}
+}
+func (Map_Any_Any) ListIterator() ipld.ListIterator {
+	return nil
+}
+func (n *Map_Any_Any) Length() int {
+	return len(n.t)
+}
+func (Map_Any_Any) IsUndefined() bool {
+	return false
+}
+func (Map_Any_Any) IsNull() bool {
+	return false
+}
+func (Map_Any_Any) AsBool() (bool, error) {
+	return false, ipld.ErrWrongKind{TypeName: "Map_Any_Any", MethodName: "AsBool", AppropriateKind: ipld.ReprKindSet_JustBool, ActualKind: ipld.ReprKind_Map}
+}
+func (Map_Any_Any) AsInt() (int, error) {
+	return 0, ipld.ErrWrongKind{TypeName: "Map_Any_Any", MethodName: "AsInt", AppropriateKind: ipld.ReprKindSet_JustInt, ActualKind: ipld.ReprKind_Map}
+}
+func (Map_Any_Any) AsFloat() (float64, error) {
+	return 0, ipld.ErrWrongKind{TypeName: "Map_Any_Any", MethodName: "AsFloat", AppropriateKind: ipld.ReprKindSet_JustFloat, ActualKind: ipld.ReprKind_Map}
+}
+func (Map_Any_Any) AsString() (string, error) {
+	return "", ipld.ErrWrongKind{TypeName: "Map_Any_Any", MethodName: "AsString", AppropriateKind: ipld.ReprKindSet_JustString, ActualKind: ipld.ReprKind_Map}
+}
+func (Map_Any_Any) AsBytes() ([]byte, error) {
+	return nil, ipld.ErrWrongKind{TypeName: "Map_Any_Any", MethodName: "AsBytes", AppropriateKind: ipld.ReprKindSet_JustBytes, ActualKind: ipld.ReprKind_Map}
+}
+func (Map_Any_Any) AsLink() (ipld.Link, error) {
+	return nil, ipld.ErrWrongKind{TypeName: "Map_Any_Any", MethodName: "AsLink", AppropriateKind: ipld.ReprKindSet_JustLink, ActualKind: ipld.ReprKind_Map}
+}
+func (Map_Any_Any) Style() ipld.NodeStyle {
+	return Type__Map_Any_Any{}
+}
+
+type _Map_Any_Any_MapIterator struct {
+	n   *Map_Any_Any
+	idx int
+}
+
+func (itr *_Map_Any_Any_MapIterator) Next() (k ipld.Node, v ipld.Node, _ error) {
+	if itr.Done() {
+		return nil, nil, ipld.ErrIteratorOverread{}
+	}
+	k = itr.n.t[itr.idx].k
+	v = itr.n.t[itr.idx].v
+	itr.idx++
+	return
+}
+func (itr *_Map_Any_Any_MapIterator) Done() bool {
+	return itr.idx >= len(itr.n.t)
+}
+
+// Type__Map_Any_Any implements ipld.NodeStyle, the same way as Type__Map_K_T.
+type Type__Map_Any_Any struct{}
+
+func (Type__Map_Any_Any) NewBuilder() ipld.NodeBuilder {
+	return &_Map_Any_Any__Builder{_Map_Any_Any__Assembler{
+		w: &Map_Any_Any{},
+	}}
+}
+
+// The assembly flow is the same as for _Map_K2_T2__Assembler, but keys and values
+// of any kind are assembled with a basicnode builder, and handed back when they're complete.
+//
+// Repeated keys have to be caught as each key is assembled, but keeping one sorted index up to date
+// all along would cost a shift of half the index per insert.  So while assembling, the index is kept
+// as a stack of sorted runs instead, merged whenever a run is no longer than the one above it
+// (so there are at most log2(n) of them, each searched in O(log n));
+// Finish merges them into the single index the finished map uses.
+type _Map_Any_Any__Assembler struct {
+	w    *Map_Any_Any
+	ka   _Map_Any_Any__ChildAssembler
+	va   _Map_Any_Any__ChildAssembler
+	runs [][]int // the index, while assembling: runs of indexes into 'w.t', each in key order.
+
+	state maState
+}
+type _Map_Any_Any__Builder struct {
+	_Map_Any_Any__Assembler
+}
+
+// _Map_Any_Any__ChildAssembler is what the map assembler yields for each key and value.
+// It assembles with 'nb', and when the key or value is complete, hands it to the map assembler.
+type _Map_Any_Any__ChildAssembler struct {
+	ma    *_Map_Any_Any__Assembler
+	nb    ipld.NodeBuilder
+	isKey bool
+}
+
+func (nb *_Map_Any_Any__Builder) Build() ipld.Node {
+	result := nb.w
+	nb.w = nil
+	return result
+}
+func (nb *_Map_Any_Any__Builder) Reset() {
+	*nb = _Map_Any_Any__Builder{}
+	nb.w = &Map_Any_Any{}
+}
+
+func (na *_Map_Any_Any__Assembler) BeginMap(sizeHint int) (ipld.MapAssembler, error) {
+	if sizeHint < 0 {
+		sizeHint = 0
+	}
+	na.w.t = make([]_Map_Any_Any__entry, 0, sizeHint)
+	na.ka = _Map_Any_Any__ChildAssembler{na, basicnode.Style__Any{}.NewBuilder(), true}
+	na.va = _Map_Any_Any__ChildAssembler{na, basicnode.Style__Any{}.NewBuilder(), false}
+	return na, nil
+}
+func (_Map_Any_Any__Assembler) BeginList(_ int) (ipld.ListAssembler, error) {
+	return mixins.MapAssembler{"Map_Any_Any"}.BeginList(0)
+}
+func (_Map_Any_Any__Assembler) AssignNull() error {
+	return mixins.MapAssembler{"Map_Any_Any"}.AssignNull()
+}
+func (_Map_Any_Any__Assembler) AssignBool(bool) error {
+	return mixins.MapAssembler{"Map_Any_Any"}.AssignBool(false)
+}
+func (_Map_Any_Any__Assembler) AssignInt(int) error {
+	return mixins.MapAssembler{"Map_Any_Any"}.AssignInt(0)
+}
+func (_Map_Any_Any__Assembler) AssignFloat(float64) error {
+	return mixins.MapAssembler{"Map_Any_Any"}.AssignFloat(0)
+}
+func (_Map_Any_Any__Assembler) AssignString(string) error {
+	return mixins.MapAssembler{"Map_Any_Any"}.AssignString("")
+}
+func (_Map_Any_Any__Assembler) AssignBytes([]byte) error {
+	return mixins.MapAssembler{"Map_Any_Any"}.AssignBytes(nil)
+}
+func (_Map_Any_Any__Assembler) AssignLink(ipld.Link) error {
+	return mixins.MapAssembler{"Map_Any_Any"}.AssignLink(nil)
+}
+func (ta *_Map_Any_Any__Assembler) AssignNode(v ipld.Node) error {
+	if v2, ok := v.(*Map_Any_Any); ok {
+		*ta.w = *v2
+		ta.state = maState_finished
+		return nil
+	}
+	return ipld.Copy(v, ta)
+}
+func (_Map_Any_Any__Assembler) Style() ipld.NodeStyle { return Type__Map_Any_Any{} }
+
+func (ma *_Map_Any_Any__Assembler) AssembleEntry(k string) (ipld.NodeAssembler, error) {
+	if err := ma.AssembleKey().AssignString(k); err != nil {
+		return nil, err
+	}
+	return ma.AssembleValue(), nil
+}
+func (ma *_Map_Any_Any__Assembler) AssembleKey() ipld.NodeAssembler {
+	// Sanity check, then update, assembler state.
+	if ma.state != maState_initial {
+		panic("misuse")
+	}
+	ma.state = maState_midKey
+	return &ma.ka
+}
+func (ma *_Map_Any_Any__Assembler) AssembleValue() ipld.NodeAssembler {
+	// Sanity check, then update, assembler state.
+	if ma.state != maState_expectValue {
+		panic("misuse")
+	}
+	ma.state = maState_midValue
+	return &ma.va
+}
+func (ma *_Map_Any_Any__Assembler) Finish() error {
+	// Sanity check, then update, assembler state.
+	if ma.state != maState_initial {
+		panic("misuse")
+	}
+	ma.state = maState_finished
+	for len(ma.runs) > 1 {
+		ma.mergeTop()
+	}
+	if len(ma.runs) == 1 {
+		ma.w.idx = ma.runs[0]
+	}
+	ma.runs = nil
+	return nil
+}
+func (_Map_Any_Any__Assembler) KeyStyle() ipld.NodeStyle           { return basicnode.Style__Any{} }
+func (_Map_Any_Any__Assembler) ValueStyle(_ string) ipld.NodeStyle { return basicnode.Style__Any{} }
+
+// keyDone is called by the key assembler with each complete key.
+// The key goes into the entry table right away, but only into the index once its value is done,
+// so that a value which fails can take its key back out again just by truncating the table.
+func (ma *_Map_Any_Any__Assembler) keyDone(k ipld.Node) error {
+	ck, err := canonicalKey(k)
+	if err != nil {
+		ma.state = maState_initial
+		return err
+	}
+	for _, run := range ma.runs {
+		if _, exists := searchRun(ma.w.t, run, ck); exists {
+			ma.state = maState_initial
+			return ipld.ErrRepeatedMapKey{k}
+		}
+	}
+	ma.w.t = append(ma.w.t, _Map_Any_Any__entry{k: k, ck: ck})
+	ma.state = maState_expectValue
+	return nil
+}
+
+// valueDone is called by the value assembler with each complete value, or with nil if it failed.
+func (ma *_Map_Any_Any__Assembler) valueDone(v ipld.Node) {
+	l := len(ma.w.t) - 1
+	ma.state = maState_initial
+	if v == nil {
+		// Same recovery contract as _Map_K_T__ValueAssembler: forget the key, too.
+		ma.w.t = ma.w.t[:l]
+		return
+	}
+	ma.w.t[l].v = v
+	ma.runs = append(ma.runs, []int{l})
+	for n := len(ma.runs); n > 1 && len(ma.runs[n-2]) <= len(ma.runs[n-1]); n-- {
+		ma.mergeTop()
+	}
+}
+
+// mergeTop merges the two most recent runs of the index.
+func (ma *_Map_Any_Any__Assembler) mergeTop() {
+	n := len(ma.runs)
+	ma.runs[n-2] = mergeRuns(ma.w.t, ma.runs[n-2], ma.runs[n-1])
+	ma.runs = ma.runs[:n-1]
+}
+
+// done takes the key or value out of 'nb' and gives it to the map assembler,
+// or, if assembling it failed, puts the map assembler back the way it was.
+func (ca *_Map_Any_Any__ChildAssembler) done(err error) error {
+	if err != nil {
+		ca.nb.Reset()
+		if ca.isKey {
+			ca.ma.state = maState_initial
+		} else {
+			ca.ma.valueDone(nil)
+		}
+		return err
+	}
+	n := ca.nb.Build()
+	ca.nb.Reset()
+	if ca.isKey {
+		return ca.ma.keyDone(n)
+	}
+	ca.ma.valueDone(n)
+	return nil
+}
+
+func (ca *_Map_Any_Any__ChildAssembler) BeginMap(sizeHint int) (ipld.MapAssembler, error) {
+	ma, err := ca.nb.BeginMap(sizeHint)
+	if err != nil {
+		return nil, ca.done(err)
+	}
+	return &_Map_Any_Any__ChildAssemblerMap{ma, _Map_Any_Any__Nested{ca}}, nil
+}
+func (ca *_Map_Any_Any__ChildAssembler) BeginList(sizeHint int) (ipld.ListAssembler, error) {
+	la, err := ca.nb.BeginList(sizeHint)
+	if err != nil {
+		return nil, ca.done(err)
+	}
+	return &_Map_Any_Any__ChildAssemblerList{la, _Map_Any_Any__Nested{ca}}, nil
+}
+func (ca *_Map_Any_Any__ChildAssembler) AssignNull() error {
+	return ca.done(ca.nb.AssignNull())
+}
+func (ca *_Map_Any_Any__ChildAssembler) AssignBool(v bool) error {
+	return ca.done(ca.nb.AssignBool(v))
+}
+func (ca *_Map_Any_Any__ChildAssembler) AssignInt(v int) error {
+	return ca.done(ca.nb.AssignInt(v))
+}
+func (ca *_Map_Any_Any__ChildAssembler) AssignFloat(v float64) error {
+	return ca.done(ca.nb.AssignFloat(v))
+}
+func (ca *_Map_Any_Any__ChildAssembler) AssignString(v string) error {
+	return ca.done(ca.nb.AssignString(v))
+}
+func (ca *_Map_Any_Any__ChildAssembler) AssignBytes(v []byte) error {
+	return ca.done(ca.nb.AssignBytes(v))
+}
+func (ca *_Map_Any_Any__ChildAssembler) AssignLink(v ipld.Link) error {
+	return ca.done(ca.nb.AssignLink(v))
+}
+func (ca *_Map_Any_Any__ChildAssembler) AssignNode(v ipld.Node) error {
+	return ca.done(ca.nb.AssignNode(v))
+}
+func (_Map_Any_Any__ChildAssembler) Style() ipld.NodeStyle { return basicnode.Style__Any{} }
+
+// _Map_Any_Any__ChildAssemblerMap and _Map_Any_Any__ChildAssemblerList are what the child assembler
+// yields from BeginMap and BeginList.  They delegate to the basicnode assembler,
+// and on Finish, hand control back to the map assembler, just like the child assembler's AssignNode would.
+//
+// If anything in them fails -- one of their own entries or elements, or Finish -- the whole key or value fails,
+// and the map assembler is put back the way it was, as for any other failure (see done).
+// They're no use after that, and panic if used again.
+// (Errors from further down are left to the basicnode assemblers which made them.)
+type _Map_Any_Any__ChildAssemblerMap struct {
+	ma ipld.MapAssembler
+	_Map_Any_Any__Nested
+}
+type _Map_Any_Any__ChildAssemblerList struct {
+	la ipld.ListAssembler
+	_Map_Any_Any__Nested
+}
+
+// _Map_Any_Any__Nested is the part those share: the child assembler to report to, until the key or value is done.
+type _Map_Any_Any__Nested struct {
+	p *_Map_Any_Any__ChildAssembler
+}
+
+func (nd *_Map_Any_Any__Nested) check() {
+	if nd.p == nil {
+		panic("misuse")
+	}
+}
+
+// fail passes err through; if it's an error, it fails the key or value, too.
+func (nd *_Map_Any_Any__Nested) fail(err error) error {
+	if err == nil {
+		return nil
+	}
+	p := nd.p
+	nd.p = nil
+	return p.done(err)
+}
+
+// finish hands the complete key or value to the map assembler, unless err says it isn't.
+func (nd *_Map_Any_Any__Nested) finish(err error) error {
+	if err != nil {
+		return nd.fail(err)
+	}
+	p := nd.p
+	nd.p = nil
+	return p.done(nil)
+}
+
+func (ma *_Map_Any_Any__ChildAssemblerMap) AssembleEntry(k string) (ipld.NodeAssembler, error) {
+	ma.check()
+	va, err := ma.ma.AssembleEntry(k)
+	if err != nil {
+		return nil, ma.fail(err)
+	}
+	return &_Map_Any_Any__NestedAssembler{va, &ma._Map_Any_Any__Nested}, nil
+}
+func (ma *_Map_Any_Any__ChildAssemblerMap) AssembleKey() ipld.NodeAssembler {
+	ma.check()
+	return &_Map_Any_Any__NestedAssembler{ma.ma.AssembleKey(), &ma._Map_Any_Any__Nested}
+}
+func (ma *_Map_Any_Any__ChildAssemblerMap) AssembleValue() ipld.NodeAssembler {
+	ma.check()
+	return &_Map_Any_Any__NestedAssembler{ma.ma.AssembleValue(), &ma._Map_Any_Any__Nested}
+}
+func (ma *_Map_Any_Any__ChildAssemblerMap) Finish() error {
+	ma.check()
+	return ma.finish(ma.ma.Finish())
+}
+func (ma *_Map_Any_Any__ChildAssemblerMap) KeyStyle() ipld.NodeStyle {
+	return ma.ma.KeyStyle()
+}
+func (ma *_Map_Any_Any__ChildAssemblerMap) ValueStyle(k string) ipld.NodeStyle {
+	return ma.ma.ValueStyle(k)
+}
+
+func (la *_Map_Any_Any__ChildAssemblerList) AssembleValue() ipld.NodeAssembler {
+	la.check()
+	return &_Map_Any_Any__NestedAssembler{la.la.AssembleValue(), &la._Map_Any_Any__Nested}
+}
+func (la *_Map_Any_Any__ChildAssemblerList) Finish() error {
+	la.check()
+	return la.finish(la.la.Finish())
+}
+func (la *_Map_Any_Any__ChildAssemblerList) ValueStyle(idx int) ipld.NodeStyle {
+	return la.la.ValueStyle(idx)
+}
+
+// _Map_Any_Any__NestedAssembler wraps the assemblers for the entries and elements
+// of a child map or list, so that their errors fail the key or value the map or list is part of.
+type _Map_Any_Any__NestedAssembler struct {
+	na ipld.NodeAssembler
+	nd *_Map_Any_Any__Nested
+}
+
+func (na *_Map_Any_Any__NestedAssembler) BeginMap(sizeHint int) (ipld.MapAssembler, error) {
+	na.nd.check()
+	ma, err := na.na.BeginMap(sizeHint)
+	return ma, na.nd.fail(err)
+}
+func (na *_Map_Any_Any__NestedAssembler) BeginList(sizeHint int) (ipld.ListAssembler, error) {
+	na.nd.check()
+	la, err := na.na.BeginList(sizeHint)
+	return la, na.nd.fail(err)
+}
+func (na *_Map_Any_Any__NestedAssembler) AssignNull() error {
+	na.nd.check()
+	return na.nd.fail(na.na.AssignNull())
+}
+func (na *_Map_Any_Any__NestedAssembler) AssignBool(v bool) error {
+	na.nd.check()
+	return na.nd.fail(na.na.AssignBool(v))
+}
+func (na *_Map_Any_Any__NestedAssembler) AssignInt(v int) error {
+	na.nd.check()
+	return na.nd.fail(na.na.AssignInt(v))
+}
+func (na *_Map_Any_Any__NestedAssembler) AssignFloat(v float64) error {
+	na.nd.check()
+	return na.nd.fail(na.na.AssignFloat(v))
+}
+func (na *_Map_Any_Any__NestedAssembler) AssignString(v string) error {
+	na.nd.check()
+	return na.nd.fail(na.na.AssignString(v))
+}
+func (na *_Map_Any_Any__NestedAssembler) AssignBytes(v []byte) error {
+	na.nd.check()
+	return na.nd.fail(na.na.AssignBytes(v))
+}
+func (na *_Map_Any_Any__NestedAssembler) AssignLink(v ipld.Link) error {
+	na.nd.check()
+	return na.nd.fail(na.na.AssignLink(v))
+}
+func (na *_Map_Any_Any__NestedAssembler) AssignNode(v ipld.Node) error {
+	na.nd.check()
+	return na.nd.fail(na.na.AssignNode(v))
+}
+func (na *_Map_Any_Any__NestedAssembler) Style() ipld.NodeStyle {
+	return na.na.Style()
+}
@@ -0,0 +1,253 @@
+package gendemo
+
+import (
+	"math"
+	"strconv"
+	"testing"
+
+	"github.com/warpfork/go-wish"
+
+	ipld "github.com/ipld/go-ipld-prime"
+	"github.com/ipld/go-ipld-prime/fluent"
+	basicnode "github.com/ipld/go-ipld-prime/node/basic"
+	"github.com/ipld/go-ipld-prime/node/tests"
+)
+
+func TestMapAnyAny(t *testing.T) {
+	nb := Type__Map_Any_Any{}.NewBuilder()
+	ma, err := nb.BeginMap(4)
+	wish.Require(t, err, wish.ShouldEqual, nil)
+	// Keys of several kinds, in an order which isn't their sort order.
+	wish.Require(t, ma.AssembleKey().AssignNode(&K2{"a", "b"}), wish.ShouldEqual, nil)
+	wish.Require(t, ma.AssembleValue().AssignInt(1), wish.ShouldEqual, nil)
+	va, err := ma.AssembleEntry("zed")
+	wish.Require(t, err, wish.ShouldEqual, nil)
+	wish.Require(t, va.AssignInt(2), wish.ShouldEqual, nil)
+	kla, err := ma.AssembleKey().BeginList(2)
+	wish.Require(t, err, wish.ShouldEqual, nil)
+	wish.Require(t, kla.AssembleValue().AssignInt(1), wish.ShouldEqual, nil)
+	wish.Require(t, kla.AssembleValue().AssignInt(2), wish.ShouldEqual, nil)
+	wish.Require(t, kla.Finish(), wish.ShouldEqual, nil)
+	wish.Require(t, ma.AssembleValue().AssignInt(3), wish.ShouldEqual, nil)
+	wish.Require(t, ma.AssembleKey().AssignInt(7), wish.ShouldEqual, nil)
+	wish.Require(t, ma.AssembleValue().AssignString("four"), wish.ShouldEqual, nil)
+
+	t.Run("repeated keys are rejected, by content", func(t *testing.T) {
+		// A plain map with the same entries as a K2 is the same key.
+		kma, err := ma.AssembleKey().BeginMap(2)
+		wish.Require(t, err, wish.ShouldEqual, nil)
+		wish.Require(t, kma.AssembleKey().AssignString("u"), wish.ShouldEqual, nil)
+		wish.Require(t, kma.AssembleValue().AssignString("a"), wish.ShouldEqual, nil)
+		wish.Require(t, kma.AssembleKey().AssignString("i"), wish.ShouldEqual, nil)
+		wish.Require(t, kma.AssembleValue().AssignString("b"), wish.ShouldEqual, nil)
+		err = kma.Finish()
+		wish.Wish(t, err, wish.ShouldBeSameTypeAs, ipld.ErrRepeatedMapKey{})
+		wish.Wish(t, err.Error(), wish.ShouldEqual, `cannot repeat map key ({"u": "a", "i": "b"})`)
+		_, err = ma.AssembleEntry("zed")
+		wish.Wish(t, err, wish.ShouldBeSameTypeAs, ipld.ErrRepeatedMapKey{})
+		// So is one with the same entries in another order.
+		wish.Wish(t, ma.AssembleKey().AssignNode(fluent.MustBuildMap(basicnode.Style__Map{}, 2, func(na fluent.MapAssembler) {
+			na.AssembleEntry("i").AssignString("b")
+			na.AssembleEntry("u").AssignString("a")
+		})), wish.ShouldBeSameTypeAs, ipld.ErrRepeatedMapKey{})
+	})
+	t.Run("a failure inside a child map or list forgets its key", func(t *testing.T) {
+		for i := 0; i < 2; i++ { // (the second time around, the key isn't a repeat, and the map assembler is ready for it.)
+			wish.Require(t, ma.AssembleKey().AssignString("k"), wish.ShouldEqual, nil)
+			cma, err := ma.AssembleValue().BeginMap(2)
+			wish.Require(t, err, wish.ShouldEqual, nil)
+			wish.Require(t, cma.AssembleKey().AssignString("x"), wish.ShouldEqual, nil)
+			wish.Require(t, cma.AssembleValue().AssignInt(1), wish.ShouldEqual, nil)
+			_, err = cma.AssembleEntry("x")
+			wish.Wish(t, err, wish.ShouldBeSameTypeAs, ipld.ErrRepeatedMapKey{})
+		}
+		for i := 0; i < 2; i++ {
+			cla, err := ma.AssembleKey().BeginList(1)
+			wish.Require(t, err, wish.ShouldEqual, nil)
+			wish.Wish(t, cla.AssembleValue().AssignFloat(math.Inf(1)), wish.ShouldBeSameTypeAs, ipld.ErrInvalidFloat{})
+		}
+	})
+	t.Run("a failed value forgets its key", func(t *testing.T) {
+		for i := 0; i < 2; i++ { // (the second time around, the key isn't a repeat.)
+			wish.Require(t, ma.AssembleKey().AssignBool(true), wish.ShouldEqual, nil)
+			wish.Wish(t, ma.AssembleValue().AssignFloat(math.NaN()), wish.ShouldBeSameTypeAs, ipld.ErrInvalidFloat{})
+		}
+	})
+
+	wish.Require(t, ma.Finish(), wish.ShouldEqual, nil)
+	n := nb.Build()
+	wish.Wish(t, n.Length(), wish.ShouldEqual, 4)
+
+	t.Run("iteration follows insertion order", func(t *testing.T) {
+		var keys []string
+		wish.Require(t, ipld.DrainMapIterator(n.MapIterator(), func(k, _ ipld.Node) error {
+			keys = append(keys, ipld.Sprint(k))
+			return nil
+		}), wish.ShouldEqual, nil)
+		wish.Wish(t, keys, wish.ShouldEqual, []string{`{"u": "a", "i": "b"}`, `"zed"`, `[1, 2]`, `7`})
+	})
+	t.Run("lookup by any key", func(t *testing.T) {
+		for _, tcase := range []struct {
+			key   ipld.Node
+			value string
+		}{
+			{&K2{"a", "b"}, "1"},
+			{fluent.MustBuildMap(basicnode.Style__Map{}, 2, func(na fluent.MapAssembler) {
+				na.AssembleEntry("u").AssignString("a")
+				na.AssembleEntry("i").AssignString("b")
+			}), "1"},
+			{fluent.MustBuildMap(basicnode.Style__Map{}, 2, func(na fluent.MapAssembler) {
+				na.AssembleEntry("i").AssignString("b")
+				na.AssembleEntry("u").AssignString("a")
+			}), "1"},
+			{basicnode.NewString("zed"), "2"},
+			{fluent.MustBuildList(basicnode.Style__List{}, 2, func(na fluent.ListAssembler) {
+				na.AssembleValue().AssignInt(1)
+				na.AssembleValue().AssignInt(2)
+			}), "3"},
+			{basicnode.NewInt(7), `"four"`},
+		} {
+			v, err := n.Lookup(tcase.key)
+			wish.Wish(t, err, wish.ShouldEqual, nil)
+			wish.Wish(t, ipld.Sprint(v), wish.ShouldEqual, tcase.value)
+		}
+		v, err := n.LookupString("zed")
+		wish.Wish(t, err, wish.ShouldEqual, nil)
+		wish.Wish(t, ipld.Sprint(v), wish.ShouldEqual, "2")
+		_, err = n.Lookup(basicnode.NewBool(true))
+		wish.Wish(t, err, wish.ShouldEqual, ipld.ErrNotExists{ipld.PathSegmentOfString("true")})
+		_, err = n.Lookup(basicnode.NewFloat(7))
+		wish.Wish(t, err, wish.ShouldBeSameTypeAs, ipld.ErrNotExists{})
+	})
+}
+
+func TestMapAnyAnyMapsInsideKeys(t *testing.T) {
+	// Keys which are lists of maps, or maps keyed by maps, are compared regardless of entry order, too.
+	ab := fluent.MustBuildMap(basicnode.Style__Map{}, 2, func(na fluent.MapAssembler) {
+		na.AssembleEntry("a").AssignInt(1)
+		na.AssembleEntry("b").AssignInt(2)
+	})
+	ba := fluent.MustBuildMap(basicnode.Style__Map{}, 2, func(na fluent.MapAssembler) {
+		na.AssembleEntry("b").AssignInt(2)
+		na.AssembleEntry("a").AssignInt(1)
+	})
+	listOf := func(v ipld.Node) ipld.Node {
+		return fluent.MustBuildList(basicnode.Style__List{}, 1, func(na fluent.ListAssembler) {
+			na.AssembleValue().AssignNode(v)
+		})
+	}
+	keyedBy := func(k ipld.Node) ipld.Node {
+		nb := Type__Map_Any_Any{}.NewBuilder()
+		ma, _ := nb.BeginMap(1)
+		ma.AssembleKey().AssignNode(k)
+		ma.AssembleValue().AssignNull()
+		ma.Finish()
+		return nb.Build()
+	}
+
+	nb := Type__Map_Any_Any{}.NewBuilder()
+	ma, err := nb.BeginMap(2)
+	wish.Require(t, err, wish.ShouldEqual, nil)
+	wish.Require(t, ma.AssembleKey().AssignNode(listOf(ab)), wish.ShouldEqual, nil)
+	wish.Require(t, ma.AssembleValue().AssignInt(1), wish.ShouldEqual, nil)
+	wish.Wish(t, ma.AssembleKey().AssignNode(listOf(ba)), wish.ShouldBeSameTypeAs, ipld.ErrRepeatedMapKey{})
+	wish.Require(t, ma.AssembleKey().AssignNode(keyedBy(ab)), wish.ShouldEqual, nil)
+	wish.Require(t, ma.AssembleValue().AssignInt(2), wish.ShouldEqual, nil)
+	wish.Wish(t, ma.AssembleKey().AssignNode(keyedBy(ba)), wish.ShouldBeSameTypeAs, ipld.ErrRepeatedMapKey{})
+	wish.Require(t, ma.Finish(), wish.ShouldEqual, nil)
+	n := nb.Build()
+
+	v, err := n.Lookup(listOf(ba))
+	wish.Wish(t, err, wish.ShouldEqual, nil)
+	wish.Wish(t, ipld.Sprint(v), wish.ShouldEqual, "1")
+	v, err = n.Lookup(keyedBy(ba))
+	wish.Wish(t, err, wish.ShouldEqual, nil)
+	wish.Wish(t, ipld.Sprint(v), wish.ShouldEqual, "2")
+	// Iteration gives back the keys as they were assembled.
+	k, _, _ := n.MapIterator().Next()
+	wish.Wish(t, ipld.Sprint(k), wish.ShouldEqual, `[{"a": 1, "b": 2}]`)
+}
+
+func TestMapAnyAnyConformance(t *testing.T) {
+	nb := Type__Map_Any_Any{}.NewBuilder()
+	wish.Require(t, nb.AssignNode(fluent.MustBuildMap(basicnode.Style__Map{}, 3, func(na fluent.MapAssembler) {
+		na.AssembleEntry("whee").AssignInt(1)
+		na.AssembleEntry("woot").AssignString("x")
+		na.AssembleEntry("waga").AssignNull()
+	})), wish.ShouldEqual, nil)
+	tests.CheckConformance(t, nb.Build())
+}
+
+// buildMapAnyAnyOfK2 builds a Map_Any_Any of n entries, keyed by K2s.
+func buildMapAnyAnyOfK2(n int) (ipld.Node, []K2) {
+	keys := make([]K2, n)
+	nb := Type__Map_Any_Any{}.NewBuilder()
+	ma, err := nb.BeginMap(n)
+	if err != nil {
+		panic(err)
+	}
+	for i := range keys {
+		keys[i] = K2{plainString("u" + strconv.Itoa(i%1000)), plainString("i" + strconv.Itoa(i))}
+		if err := ma.AssembleKey().AssignNode(&keys[i]); err != nil {
+			panic(err)
+		}
+		if err := ma.AssembleValue().AssignNode(&T2{plainInt(i), 0, 0, 0}); err != nil {
+			panic(err)
+		}
+	}
+	if err := ma.Finish(); err != nil {
+		panic(err)
+	}
+	return nb.Build(), keys
+}
+
+func TestMapAnyAnyLarge(t *testing.T) {
+	n, keys := buildMapAnyAnyOfK2(10000)
+	wish.Wish(t, n.Length(), wish.ShouldEqual, len(keys))
+	for i := range keys {
+		v, err := n.Lookup(&keys[i])
+		wish.Require(t, err, wish.ShouldEqual, nil)
+		a, _ := v.LookupString("a")
+		ai, _ := a.AsInt()
+		wish.Require(t, ai, wish.ShouldEqual, i)
+	}
+}
+
+func BenchmarkMapAnyAny_100k_ComplexKeys_Lookup(b *testing.B) {
+	n, keys := buildMapAnyAnyOfK2(100000)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := n.Lookup(&keys[i%len(keys)]); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// BenchmarkMapK2T2_100k_Lookup is the baseline: a native Go map, which needs comparable keys.
+func BenchmarkMapK2T2_100k_Lookup(b *testing.B) {
+	keys := make([]K2, 100000)
+	nb := Type__Map_K2_T2{}.NewBuilder()
+	ma, err := nb.BeginMap(len(keys))
+	if err != nil {
+		b.Fatal(err)
+	}
+	for i := range keys {
+		keys[i] = K2{plainString("u" + strconv.Itoa(i%1000)), plainString("i" + strconv.Itoa(i))}
+		if err := ma.AssembleKey().AssignNode(&keys[i]); err != nil {
+			b.Fatal(err)
+		}
+		if err := ma.AssembleValue().AssignNode(&T2{plainInt(i), 0, 0, 0}); err != nil {
+			b.Fatal(err)
+		}
+	}
+	if err := ma.Finish(); err != nil {
+		b.Fatal(err)
+	}
+	n := nb.Build()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := n.Lookup(&keys[i%len(keys)]); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -4,9 +4,8 @@ package gendemo
 // In constrast with Map_K_T, this one has both complex keys and a struct for the value.
 
 import (
-	"fmt"
-
 	ipld "github.com/ipld/go-ipld-prime"
+	"github.com/ipld/go-ipld-prime/node/mixins"
 )
 
 // --- we need some types to use for keys and values: --->
@@ -18,6 +17,17 @@ import (
 type K2 struct{ u, i plainString }
 type T2 struct{ a, b, c, d plainInt }
 
+// Struct field names, boxed as Nodes once, up front.
+//  Iterators and ErrRepeatedMapKey can then hand these out without converting a string to a Node each time.
+var (
+	fieldName__K2_u ipld.Node = plainString("u")
+	fieldName__K2_i ipld.Node = plainString("i")
+	fieldName__T2_a ipld.Node = plainString("a")
+	fieldName__T2_b ipld.Node = plainString("b")
+	fieldName__T2_c ipld.Node = plainString("c")
+	fieldName__T2_d ipld.Node = plainString("d")
+)
+
 func (K2) ReprKind() ipld.ReprKind {
 	return ipld.ReprKind_Map
 }
@@ -28,7 +38,7 @@ func (n *K2) LookupString(key string) (ipld.Node, error) {
 	case "i":
 		return &n.i, nil
 	default:
-		return nil, fmt.Errorf("no such field")
+		return nil, ipld.ErrNotExists{ipld.PathSegmentOfString(key)}
 	}
 }
 func (n *K2) Lookup(key ipld.Node) (ipld.Node, error) {
@@ -39,7 +49,7 @@ func (n *K2) Lookup(key ipld.Node) (ipld.Node, error) {
 	return n.LookupString(ks)
 }
 func (K2) LookupIndex(idx int) (ipld.Node, error) {
-	return nil, ipld.ErrWrongKind{TypeName: "K2", MethodName: "LookupIndex", AppropriateKind: ipld.ReprKindSet_JustList, ActualKind: ipld.ReprKind_Map}
+	return nil, ipld.ErrWrongKind{TypeName: "K2", MethodName: "LookupIndex", AppropriateKind: ipld.ReprKindSet_JustList, ActualKind: ipld.ReprKind_Map, RepresentationKind: ipld.ReprKind_String}
 }
 func (n *K2) LookupSegment(seg ipld.PathSegment) (ipld.Node, error) {
 	return n.LookupString(seg.String())
@@ -51,7 +61,7 @@ func (K2) ListIterator() ipld.ListIterator {
 	return nil
 }
 func (K2) Length() int {
-	return -1
+	return 2
 }
 func (K2) IsUndefined() bool {
 	return false
@@ -60,22 +70,22 @@ func (K2) IsNull() bool {
 	return false
 }
 func (K2) AsBool() (bool, error) {
-	return false, ipld.ErrWrongKind{TypeName: "K2", MethodName: "AsBool", AppropriateKind: ipld.ReprKindSet_JustBool, ActualKind: ipld.ReprKind_Map}
+	return false, ipld.ErrWrongKind{TypeName: "K2", MethodName: "AsBool", AppropriateKind: ipld.ReprKindSet_JustBool, ActualKind: ipld.ReprKind_Map, RepresentationKind: ipld.ReprKind_String}
 }
 func (K2) AsInt() (int, error) {
-	return 0, ipld.ErrWrongKind{TypeName: "K2", MethodName: "AsInt", AppropriateKind: ipld.ReprKindSet_JustFloat, ActualKind: ipld.ReprKind_Map}
+	return 0, ipld.ErrWrongKind{TypeName: "K2", MethodName: "AsInt", AppropriateKind: ipld.ReprKindSet_JustInt, ActualKind: ipld.ReprKind_Map, RepresentationKind: ipld.ReprKind_String}
 }
 func (K2) AsFloat() (float64, error) {
-	return 0, ipld.ErrWrongKind{TypeName: "K2", MethodName: "AsFloat", AppropriateKind: ipld.ReprKindSet_JustFloat, ActualKind: ipld.ReprKind_Map}
+	return 0, ipld.ErrWrongKind{TypeName: "K2", MethodName: "AsFloat", AppropriateKind: ipld.ReprKindSet_JustFloat, ActualKind: ipld.ReprKind_Map, RepresentationKind: ipld.ReprKind_String}
 }
 func (K2) AsString() (string, error) {
-	return "", ipld.ErrWrongKind{TypeName: "K2", MethodName: "AsString", AppropriateKind: ipld.ReprKindSet_JustString, ActualKind: ipld.ReprKind_Map}
+	return "", ipld.ErrWrongKind{TypeName: "K2", MethodName: "AsString", AppropriateKind: ipld.ReprKindSet_JustString, ActualKind: ipld.ReprKind_Map, RepresentationKind: ipld.ReprKind_String}
 }
 func (K2) AsBytes() ([]byte, error) {
-	return nil, ipld.ErrWrongKind{TypeName: "K2", MethodName: "AsBytes", AppropriateKind: ipld.ReprKindSet_JustBytes, ActualKind: ipld.ReprKind_Map}
+	return nil, ipld.ErrWrongKind{TypeName: "K2", MethodName: "AsBytes", AppropriateKind: ipld.ReprKindSet_JustBytes, ActualKind: ipld.ReprKind_Map, RepresentationKind: ipld.ReprKind_String}
 }
 func (K2) AsLink() (ipld.Link, error) {
-	return nil, ipld.ErrWrongKind{TypeName: "K2", MethodName: "AsLink", AppropriateKind: ipld.ReprKindSet_JustLink, ActualKind: ipld.ReprKind_Map}
+	return nil, ipld.ErrWrongKind{TypeName: "K2", MethodName: "AsLink", AppropriateKind: ipld.ReprKindSet_JustLink, ActualKind: ipld.ReprKind_Map, RepresentationKind: ipld.ReprKind_String}
 }
 func (K2) Style() ipld.NodeStyle {
 	panic("todo")
@@ -92,10 +102,10 @@ func (itr *_K2_MapIterator) Next() (k ipld.Node, v ipld.Node, _ error) {
 	}
 	switch itr.idx {
 	case 0:
-		k = plainString("u") // TODO: I guess we should generate const pools for struct field names?
+		k = fieldName__K2_u
 		v = &itr.n.u
 	case 1:
-		k = plainString("i")
+		k = fieldName__K2_i
 		v = &itr.n.i
 	default:
 		panic("unreachable")
@@ -119,8 +129,189 @@ const (
 	maState_finished                   // 'w' will also be nil, but this is a politer statement
 )
 
+// fieldPreparer is implemented by struct assemblers, so _struct__KeyAssembler can be shared among them.
+// prepareField checks the key names a field which isn't set yet, and points the struct's field child assembler at it.
+type fieldPreparer interface {
+	prepareField(k string) error
+}
+
+// _struct__KeyAssembler is what a struct assembler yields from AssembleKey.
+// Struct keys are always strings, so it just hands the key to prepareField,
+// then moves the struct assembler along to expecting the value.
+// If the key is rejected (it's not a string, or not a field which can be set now),
+// the struct assembler goes back to expecting a key, so the caller may carry on.
+type _struct__KeyAssembler struct {
+	ma    fieldPreparer
+	state *maState
+}
+
+func (ka *_struct__KeyAssembler) BeginMap(sizeHint int) (ipld.MapAssembler, error) {
+	*ka.state = maState_initial
+	return mixins.StringAssembler{"gendemo.String"}.BeginMap(0)
+}
+func (ka *_struct__KeyAssembler) BeginList(sizeHint int) (ipld.ListAssembler, error) {
+	*ka.state = maState_initial
+	return mixins.StringAssembler{"gendemo.String"}.BeginList(0)
+}
+func (ka *_struct__KeyAssembler) AssignNull() error {
+	*ka.state = maState_initial
+	return mixins.StringAssembler{"gendemo.String"}.AssignNull()
+}
+func (ka *_struct__KeyAssembler) AssignBool(bool) error {
+	*ka.state = maState_initial
+	return mixins.StringAssembler{"gendemo.String"}.AssignBool(false)
+}
+func (ka *_struct__KeyAssembler) AssignInt(int) error {
+	*ka.state = maState_initial
+	return mixins.StringAssembler{"gendemo.String"}.AssignInt(0)
+}
+func (ka *_struct__KeyAssembler) AssignFloat(float64) error {
+	*ka.state = maState_initial
+	return mixins.StringAssembler{"gendemo.String"}.AssignFloat(0)
+}
+func (ka *_struct__KeyAssembler) AssignString(k string) error {
+	if err := ka.ma.prepareField(k); err != nil {
+		*ka.state = maState_initial
+		return err
+	}
+	*ka.state = maState_expectValue
+	return nil
+}
+func (ka *_struct__KeyAssembler) AssignBytes([]byte) error {
+	*ka.state = maState_initial
+	return mixins.StringAssembler{"gendemo.String"}.AssignBytes(nil)
+}
+func (ka *_struct__KeyAssembler) AssignLink(ipld.Link) error {
+	*ka.state = maState_initial
+	return mixins.StringAssembler{"gendemo.String"}.AssignLink(nil)
+}
+func (ka *_struct__KeyAssembler) AssignNode(v ipld.Node) error {
+	k, err := v.AsString()
+	if err != nil {
+		*ka.state = maState_initial
+		return err
+	}
+	return ka.AssignString(k)
+}
+func (_struct__KeyAssembler) Style() ipld.NodeStyle {
+	return Style__String{}
+}
+
+// _plainString__FieldAssembler is the field child assembler for a struct's string fields.
+// It assigns through 'ca', and when that succeeds, does the 'finish' handshake with the struct assembler:
+// it marks the field as set, and puts the struct assembler back to expecting a key (or Finish).
+// If the assignment fails, the field stays unset, and the struct assembler still goes back to expecting a key.
+type _plainString__FieldAssembler struct {
+	ca    plainString__Assembler
+	isset *bool
+	state *maState
+}
+
+func (fa *_plainString__FieldAssembler) finish(err error) error {
+	if err == nil {
+		*fa.isset = true
+	}
+	*fa.state = maState_initial
+	fa.ca.w = nil
+	fa.isset = nil
+	fa.state = nil
+	return err
+}
+
+func (fa *_plainString__FieldAssembler) BeginMap(sizeHint int) (ipld.MapAssembler, error) {
+	_, err := fa.ca.BeginMap(sizeHint)
+	return nil, fa.finish(err)
+}
+func (fa *_plainString__FieldAssembler) BeginList(sizeHint int) (ipld.ListAssembler, error) {
+	_, err := fa.ca.BeginList(sizeHint)
+	return nil, fa.finish(err)
+}
+func (fa *_plainString__FieldAssembler) AssignNull() error {
+	return fa.finish(fa.ca.AssignNull())
+}
+func (fa *_plainString__FieldAssembler) AssignBool(v bool) error {
+	return fa.finish(fa.ca.AssignBool(v))
+}
+func (fa *_plainString__FieldAssembler) AssignInt(v int) error {
+	return fa.finish(fa.ca.AssignInt(v))
+}
+func (fa *_plainString__FieldAssembler) AssignFloat(v float64) error {
+	return fa.finish(fa.ca.AssignFloat(v))
+}
+func (fa *_plainString__FieldAssembler) AssignString(v string) error {
+	return fa.finish(fa.ca.AssignString(v))
+}
+func (fa *_plainString__FieldAssembler) AssignBytes(v []byte) error {
+	return fa.finish(fa.ca.AssignBytes(v))
+}
+func (fa *_plainString__FieldAssembler) AssignLink(v ipld.Link) error {
+	return fa.finish(fa.ca.AssignLink(v))
+}
+func (fa *_plainString__FieldAssembler) AssignNode(v ipld.Node) error {
+	return fa.finish(fa.ca.AssignNode(v))
+}
+func (_plainString__FieldAssembler) Style() ipld.NodeStyle {
+	return Style__String{}
+}
+
+// _plainInt__FieldAssembler is the same as _plainString__FieldAssembler, but for int fields.
+type _plainInt__FieldAssembler struct {
+	ca    plainInt__Assembler
+	isset *bool
+	state *maState
+}
+
+func (fa *_plainInt__FieldAssembler) finish(err error) error {
+	if err == nil {
+		*fa.isset = true
+	}
+	*fa.state = maState_initial
+	fa.ca.w = nil
+	fa.isset = nil
+	fa.state = nil
+	return err
+}
+
+func (fa *_plainInt__FieldAssembler) BeginMap(sizeHint int) (ipld.MapAssembler, error) {
+	_, err := fa.ca.BeginMap(sizeHint)
+	return nil, fa.finish(err)
+}
+func (fa *_plainInt__FieldAssembler) BeginList(sizeHint int) (ipld.ListAssembler, error) {
+	_, err := fa.ca.BeginList(sizeHint)
+	return nil, fa.finish(err)
+}
+func (fa *_plainInt__FieldAssembler) AssignNull() error {
+	return fa.finish(fa.ca.AssignNull())
+}
+func (fa *_plainInt__FieldAssembler) AssignBool(v bool) error {
+	return fa.finish(fa.ca.AssignBool(v))
+}
+func (fa *_plainInt__FieldAssembler) AssignInt(v int) error {
+	return fa.finish(fa.ca.AssignInt(v))
+}
+func (fa *_plainInt__FieldAssembler) AssignFloat(v float64) error {
+	return fa.finish(fa.ca.AssignFloat(v))
+}
+func (fa *_plainInt__FieldAssembler) AssignString(v string) error {
+	return fa.finish(fa.ca.AssignString(v))
+}
+func (fa *_plainInt__FieldAssembler) AssignBytes(v []byte) error {
+	return fa.finish(fa.ca.AssignBytes(v))
+}
+func (fa *_plainInt__FieldAssembler) AssignLink(v ipld.Link) error {
+	return fa.finish(fa.ca.AssignLink(v))
+}
+func (fa *_plainInt__FieldAssembler) AssignNode(v ipld.Node) error {
+	return fa.finish(fa.ca.AssignNode(v))
+}
+func (_plainInt__FieldAssembler) Style() ipld.NodeStyle {
+	return Style__Int{}
+}
+
 type _K2__Assembler struct {
-	w *K2
+	w  *K2
+	ka _struct__KeyAssembler
+	fa _plainString__FieldAssembler
 
 	state maState
 
@@ -133,48 +324,81 @@ type _K2__ReprAssembler struct {
 	// note how this is totally different than the type-level assembler -- that's map-like, this is string.
 }
 
-func (ta *_K2__Assembler) BeginMap(_ int) (ipld.MapAssembler, error) { panic("no") }
+func (ta *_K2__Assembler) BeginMap(_ int) (ipld.MapAssembler, error) { return ta, nil }
 func (_K2__Assembler) BeginList(_ int) (ipld.ListAssembler, error)   { panic("no") }
-func (_K2__Assembler) AssignNull() error                                 { panic("no") }
-func (_K2__Assembler) AssignBool(bool) error                             { panic("no") }
-func (_K2__Assembler) AssignInt(v int) error                             { panic("no") }
-func (_K2__Assembler) AssignFloat(float64) error                         { panic("no") }
-func (_K2__Assembler) AssignString(v string) error                       { panic("no") }
-func (_K2__Assembler) AssignBytes([]byte) error                          { panic("no") }
+func (_K2__Assembler) AssignNull() error                             { panic("no") }
+func (_K2__Assembler) AssignBool(bool) error                         { panic("no") }
+func (_K2__Assembler) AssignInt(v int) error                         { panic("no") }
+func (_K2__Assembler) AssignFloat(float64) error                     { panic("no") }
+func (_K2__Assembler) AssignString(v string) error                   { panic("no") }
+func (_K2__Assembler) AssignBytes([]byte) error                      { panic("no") }
 func (ta *_K2__Assembler) AssignNode(v ipld.Node) error {
 	if v2, ok := v.(*K2); ok {
 		*ta.w = *v2
 		return nil
 	}
-	panic("todo implement generic copy and use it here")
+	// Not our own type; pick the fields out one at a time.
+	//  (Looking them up by name, rather than using ipld.Copy, means any extra entries in 'v' are ignored.)
+	u, err := lookupFieldString(v, "u")
+	if err != nil {
+		return err
+	}
+	i, err := lookupFieldString(v, "i")
+	if err != nil {
+		return err
+	}
+	*ta.w = K2{plainString(u), plainString(i)}
+	return nil
 }
 func (_K2__Assembler) Style() ipld.NodeStyle { panic("later") }
 
+func lookupFieldString(n ipld.Node, k string) (string, error) {
+	fv, err := n.LookupString(k)
+	if err != nil {
+		return "", err
+	}
+	return fv.AsString()
+}
+
 func (ma *_K2__Assembler) AssembleEntry(k string) (ipld.NodeAssembler, error) {
-	// Sanity check, then update, assembler state.
+	// Sanity check assembler state.
 	if ma.state != maState_initial {
 		panic("misuse")
 	}
-	ma.state = maState_midValue
 	// Figure out which field we're addressing,
 	//  check if it's already been assigned (error if so),
-	//   grab a pointer to it and init its value assembler with that,
-	//    and yield that value assembler.
+	//   and point the field child assembler at it.
 	//  (Note that `isset_foo` bools may be inside the 'ma.w' node if
 	//   that field is optional; if it's required, they stay in 'ma'.)
+	if err := ma.prepareField(k); err != nil {
+		return nil, err
+	}
+	ma.state = maState_midValue
+	return &ma.fa, nil
+}
+
+// prepareField is shared by AssembleEntry and the key assembler.
+// It leaves the assembler state alone; callers move it along only on success.
+func (ma *_K2__Assembler) prepareField(k string) error {
 	switch k {
 	case "u":
 		if ma.isset_u {
-			return nil, ipld.ErrRepeatedMapKey{plainString("u")} // REVIEW: interesting to note this is a place we *keep* needing a basic string node impl, *everywhere*.
+			return ipld.ErrRepeatedMapKey{fieldName__K2_u}
 		}
-		// TODO initialize the field child assembler 'w' *and* 'finish' callback to us; return it.
-		panic("todo")
+		ma.fa.ca.w = &ma.w.u
+		ma.fa.isset = &ma.isset_u
 	case "i":
-		// TODO same as above
-		panic("todo")
+		if ma.isset_i {
+			return ipld.ErrRepeatedMapKey{fieldName__K2_i}
+		}
+		ma.fa.ca.w = &ma.w.i
+		ma.fa.isset = &ma.isset_i
 	default:
-		panic("invalid field key")
+		return ipld.ErrInvalidStructKey{TypeName: "K2", Key: k}
 	}
+	// The field child assembler reports back to us through this when it's done.
+	ma.fa.state = &ma.state
+	return nil
 }
 
 func (ma *_K2__Assembler) AssembleKey() ipld.NodeAssembler {
@@ -183,8 +407,8 @@ func (ma *_K2__Assembler) AssembleKey() ipld.NodeAssembler {
 		panic("misuse")
 	}
 	ma.state = maState_midKey
-	// TODO return a fairly dummy assembler which just contains a string switch (probably sharing code with AssembleEntry).
-	panic("todo")
+	ma.ka = _struct__KeyAssembler{ma, &ma.state}
+	return &ma.ka
 }
 func (ma *_K2__Assembler) AssembleValue() ipld.NodeAssembler {
 	// Sanity check, then update, assembler state.
@@ -192,14 +416,25 @@ func (ma *_K2__Assembler) AssembleValue() ipld.NodeAssembler {
 		panic("misuse")
 	}
 	ma.state = maState_midValue
-	// TODO initialize the field child assembler 'w' *and* 'finish' callback to us; return it.
-	panic("todo")
+	// The key assembler already pointed 'fa' at the field.
+	return &ma.fa
 }
 func (ma *_K2__Assembler) Finish() error {
 	// Sanity check assembler state.
 	if ma.state != maState_initial {
 		panic("misuse")
 	}
+	// Every field is required.  If some are missing, stay open, so the caller can still add them.
+	var missing []string
+	if !ma.isset_u {
+		missing = append(missing, "u")
+	}
+	if !ma.isset_i {
+		missing = append(missing, "i")
+	}
+	if missing != nil {
+		return ipld.ErrMissingRequiredField{TypeName: "K2", Missing: missing}
+	}
 	ma.state = maState_finished
 	// validators could run and report errors promptly, if this type had any.
 	return nil
@@ -221,7 +456,7 @@ func (n *T2) LookupString(key string) (ipld.Node, error) {
 	case "d":
 		return &n.d, nil
 	default:
-		return nil, fmt.Errorf("no such field")
+		return nil, ipld.ErrNotExists{ipld.PathSegmentOfString(key)}
 	}
 }
 func (n *T2) Lookup(key ipld.Node) (ipld.Node, error) {
@@ -244,7 +479,7 @@ func (T2) ListIterator() ipld.ListIterator {
 	return nil
 }
 func (T2) Length() int {
-	return -1
+	return 4
 }
 func (T2) IsUndefined() bool {
 	return false
@@ -256,7 +491,7 @@ func (T2) AsBool() (bool, error) {
 	return false, ipld.ErrWrongKind{TypeName: "T2", MethodName: "AsBool", AppropriateKind: ipld.ReprKindSet_JustBool, ActualKind: ipld.ReprKind_Map}
 }
 func (T2) AsInt() (int, error) {
-	return 0, ipld.ErrWrongKind{TypeName: "T2", MethodName: "AsInt", AppropriateKind: ipld.ReprKindSet_JustFloat, ActualKind: ipld.ReprKind_Map}
+	return 0, ipld.ErrWrongKind{TypeName: "T2", MethodName: "AsInt", AppropriateKind: ipld.ReprKindSet_JustInt, ActualKind: ipld.ReprKind_Map}
 }
 func (T2) AsFloat() (float64, error) {
 	return 0, ipld.ErrWrongKind{TypeName: "T2", MethodName: "AsFloat", AppropriateKind: ipld.ReprKindSet_JustFloat, ActualKind: ipld.ReprKind_Map}
@@ -285,16 +520,16 @@ func (itr *_T2_MapIterator) Next() (k ipld.Node, v ipld.Node, _ error) {
 	}
 	switch itr.idx {
 	case 0:
-		k = plainString("a") // TODO: I guess we should generate const pools for struct field names?
+		k = fieldName__T2_a
 		v = &itr.n.a
 	case 1:
-		k = plainString("b")
+		k = fieldName__T2_b
 		v = &itr.n.b
 	case 2:
-		k = plainString("c")
+		k = fieldName__T2_c
 		v = &itr.n.c
 	case 3:
-		k = plainString("d")
+		k = fieldName__T2_d
 		v = &itr.n.d
 	default:
 		panic("unreachable")
@@ -307,7 +542,16 @@ func (itr *_T2_MapIterator) Done() bool {
 }
 
 type _T2__Assembler struct {
-	w *T2
+	w  *T2
+	ka _struct__KeyAssembler
+	fa _plainInt__FieldAssembler
+
+	state maState
+
+	isset_a bool
+	isset_b bool
+	isset_c bool
+	isset_d bool
 }
 type _T2__ReprAssembler struct {
 	w *T2
@@ -328,25 +572,99 @@ func (ta *_T2__Assembler) AssignNode(v ipld.Node) error {
 		*ta.w = *v2
 		return nil
 	}
-	// todo: apply a generic 'copy' function.
-	panic("later")
+	// Not our own type; pick the fields out one at a time, as _K2__Assembler does.
+	var fields [4]int
+	for i, k := range [4]string{"a", "b", "c", "d"} {
+		fv, err := v.LookupString(k)
+		if err != nil {
+			return err
+		}
+		if fields[i], err = fv.AsInt(); err != nil {
+			return err
+		}
+	}
+	*ta.w = T2{plainInt(fields[0]), plainInt(fields[1]), plainInt(fields[2]), plainInt(fields[3])}
+	return nil
 }
 func (_T2__Assembler) Style() ipld.NodeStyle { panic("later") }
 
-func (ta *_T2__Assembler) AssembleEntry(string) (ipld.NodeAssembler, error) {
-	// this'll be fun
-	panic("soon")
+// The map side of _T2__Assembler works just like _K2__Assembler's; see the comments there.
+
+func (ma *_T2__Assembler) AssembleEntry(k string) (ipld.NodeAssembler, error) {
+	if ma.state != maState_initial {
+		panic("misuse")
+	}
+	if err := ma.prepareField(k); err != nil {
+		return nil, err
+	}
+	ma.state = maState_midValue
+	return &ma.fa, nil
+}
+func (ma *_T2__Assembler) prepareField(k string) error {
+	switch k {
+	case "a":
+		if ma.isset_a {
+			return ipld.ErrRepeatedMapKey{fieldName__T2_a}
+		}
+		ma.fa.ca.w = &ma.w.a
+		ma.fa.isset = &ma.isset_a
+	case "b":
+		if ma.isset_b {
+			return ipld.ErrRepeatedMapKey{fieldName__T2_b}
+		}
+		ma.fa.ca.w = &ma.w.b
+		ma.fa.isset = &ma.isset_b
+	case "c":
+		if ma.isset_c {
+			return ipld.ErrRepeatedMapKey{fieldName__T2_c}
+		}
+		ma.fa.ca.w = &ma.w.c
+		ma.fa.isset = &ma.isset_c
+	case "d":
+		if ma.isset_d {
+			return ipld.ErrRepeatedMapKey{fieldName__T2_d}
+		}
+		ma.fa.ca.w = &ma.w.d
+		ma.fa.isset = &ma.isset_d
+	default:
+		return ipld.ErrInvalidStructKey{TypeName: "T2", Key: k}
+	}
+	ma.fa.state = &ma.state
+	return nil
 }
-func (ta *_T2__Assembler) AssembleKey() ipld.NodeAssembler {
-	// this'll be fun
-	panic("soon")
+func (ma *_T2__Assembler) AssembleKey() ipld.NodeAssembler {
+	if ma.state != maState_initial {
+		panic("misuse")
+	}
+	ma.state = maState_midKey
+	ma.ka = _struct__KeyAssembler{ma, &ma.state}
+	return &ma.ka
 }
-func (ta *_T2__Assembler) AssembleValue() ipld.NodeAssembler {
-	// also fun
-	panic("soon")
+func (ma *_T2__Assembler) AssembleValue() ipld.NodeAssembler {
+	if ma.state != maState_expectValue {
+		panic("misuse")
+	}
+	ma.state = maState_midValue
+	return &ma.fa
 }
-func (ta *_T2__Assembler) Finish() error {
-	panic("soon")
+func (ma *_T2__Assembler) Finish() error {
+	if ma.state != maState_initial {
+		panic("misuse")
+	}
+	var missing []string
+	for _, f := range [...]struct {
+		isset bool
+		name  string
+	}{{ma.isset_a, "a"}, {ma.isset_b, "b"}, {ma.isset_c, "c"}, {ma.isset_d, "d"}} {
+		if !f.isset {
+			missing = append(missing, f.name)
+		}
+	}
+	if missing != nil {
+		return ipld.ErrMissingRequiredField{TypeName: "T2", Missing: missing}
+	}
+	ma.state = maState_finished
+	return nil
 }
 func (_T2__Assembler) KeyStyle() ipld.NodeStyle           { panic("later") }
 func (_T2__Assembler) ValueStyle(k string) ipld.NodeStyle { panic("later") }
@@ -366,17 +684,345 @@ type _Map_K2_T2__entry struct {
 	v T2 // address of this is used in map values and to return.
 }
 
+func (Map_K2_T2) ReprKind() ipld.ReprKind {
+	return ipld.ReprKind_Map
+}
+func (n *Map_K2_T2) Get(key *K2) (*T2, error) {
+	v, exists := n.m[*key]
+	if !exists {
+		return nil, ipld.ErrNotExists{ipld.PathSegmentOfString(string(key.u) + ":" + string(key.i))}
+	}
+	return v, nil
+}
 func (n *Map_K2_T2) LookupString(key string) (ipld.Node, error) {
-	panic("decision") // FIXME: What's this supposed to do?  does this error for maps with complex keys?
+	// A K2 is a struct, so a plain string can't name one (at least not at the type level); as for AssembleEntry.
+	return nil, ipld.ErrWrongKind{TypeName: "K2", MethodName: "LookupString", AppropriateKind: ipld.ReprKindSet_JustMap, ActualKind: ipld.ReprKind_String}
+}
+func (n *Map_K2_T2) Lookup(key ipld.Node) (ipld.Node, error) {
+	if k2, ok := key.(*K2); ok {
+		return n.Get(k2)
+	}
+	var k2 K2
+	if err := (&_K2__Assembler{w: &k2}).AssignNode(key); err != nil {
+		return nil, err
+	}
+	return n.Get(&k2)
+}
+func (Map_K2_T2) LookupIndex(idx int) (ipld.Node, error) {
+	return nil, ipld.ErrWrongKind{TypeName: "Map_K2_T2", MethodName: "LookupIndex", AppropriateKind: ipld.ReprKindSet_JustList, ActualKind: ipld.ReprKind_Map}
+}
+func (n *Map_K2_T2) LookupSegment(seg ipld.PathSegment) (ipld.Node, error) {
+	return n.LookupString(seg.String())
+}
+func (n *Map_K2_T2) MapIterator() ipld.MapIterator {
+	return &_Map_K2_T2_MapIterator{n, 0}
+}
+func (Map_K2_T2) ListIterator() ipld.ListIterator {
+	return nil
+}
+func (n *Map_K2_T2) Length() int {
+	return len(n.t)
+}
+func (Map_K2_T2) IsUndefined() bool {
+	return false
+}
+func (Map_K2_T2) IsNull() bool {
+	return false
+}
+func (Map_K2_T2) AsBool() (bool, error) {
+	return false, ipld.ErrWrongKind{TypeName: "Map_K2_T2", MethodName: "AsBool", AppropriateKind: ipld.ReprKindSet_JustBool, ActualKind: ipld.ReprKind_Map}
+}
+func (Map_K2_T2) AsInt() (int, error) {
+	return 0, ipld.ErrWrongKind{TypeName: "Map_K2_T2", MethodName: "AsInt", AppropriateKind: ipld.ReprKindSet_JustInt, ActualKind: ipld.ReprKind_Map}
+}
+func (Map_K2_T2) AsFloat() (float64, error) {
+	return 0, ipld.ErrWrongKind{TypeName: "Map_K2_T2", MethodName: "AsFloat", AppropriateKind: ipld.ReprKindSet_JustFloat, ActualKind: ipld.ReprKind_Map}
+}
+func (Map_K2_T2) AsString() (string, error) {
+	return "", ipld.ErrWrongKind{TypeName: "Map_K2_T2", MethodName: "AsString", AppropriateKind: ipld.ReprKindSet_JustString, ActualKind: ipld.ReprKind_Map}
+}
+func (Map_K2_T2) AsBytes() ([]byte, error) {
+	return nil, ipld.ErrWrongKind{TypeName: "Map_K2_T2", MethodName: "AsBytes", AppropriateKind: ipld.ReprKindSet_JustBytes, ActualKind: ipld.ReprKind_Map}
+}
+func (Map_K2_T2) AsLink() (ipld.Link, error) {
+	return nil, ipld.ErrWrongKind{TypeName: "Map_K2_T2", MethodName: "AsLink", AppropriateKind: ipld.ReprKindSet_JustLink, ActualKind: ipld.ReprKind_Map}
+}
+func (Map_K2_T2) Style() ipld.NodeStyle {
+	return Type__Map_K2_T2{}
+}
+
+type _Map_K2_T2_MapIterator struct {
+	n   *Map_K2_T2
+	idx int
+}
+
+func (itr *_Map_K2_T2_MapIterator) Next() (k ipld.Node, v ipld.Node, _ error) {
+	if itr.Done() {
+		return nil, nil, ipld.ErrIteratorOverread{}
+	}
+	k = &itr.n.t[itr.idx].k
+	v = &itr.n.t[itr.idx].v
+	itr.idx++
+	return
+}
+func (itr *_Map_K2_T2_MapIterator) Done() bool {
+	return itr.idx >= len(itr.n.t)
+}
+
+// Type__Map_K2_T2 implements ipld.NodeStyle, the same way as Type__Map_K_T.
+type Type__Map_K2_T2 struct{}
+
+func (Type__Map_K2_T2) NewBuilder() ipld.NodeBuilder {
+	return &_Map_K2_T2__Builder{_Map_K2_T2__Assembler{
+		w: &Map_K2_T2{},
+	}}
 }
 
+// The assembly flow is the same as for _Map_K_T__Assembler (see the comments there),
+// with one difference: the keys are complex, so they can't be given to AssembleEntry as a string.
+// Keys must come through AssembleKey, either via AssignNode or by BeginMap and assembling the K2 field by field.
 type _Map_K2_T2__Assembler struct {
 	w  *Map_K2_T2
-	ka _K2__Assembler
-	va _T2__Assembler
+	ka _Map_K2_T2__KeyAssembler
+	va _Map_K2_T2__ValueAssembler
+
+	state maState
+}
+type _Map_K2_T2__Builder struct {
+	_Map_K2_T2__Assembler
+}
+type _Map_K2_T2__KeyAssembler struct {
+	ma *_Map_K2_T2__Assembler
+	ca _K2__Assembler
+}
+type _Map_K2_T2__ValueAssembler struct {
+	ma *_Map_K2_T2__Assembler
+	ca _T2__Assembler
 }
 type _Map_K2_T2__ReprAssembler struct {
 	w  *Map_K2_T2
 	ka _K2__ReprAssembler
 	va _T2__ReprAssembler
 }
+
+func (nb *_Map_K2_T2__Builder) Build() ipld.Node {
+	result := nb.w
+	nb.w = nil
+	return result
+}
+func (nb *_Map_K2_T2__Builder) Reset() {
+	*nb = _Map_K2_T2__Builder{}
+	nb.w = &Map_K2_T2{}
+}
+
+func (na *_Map_K2_T2__Assembler) BeginMap(sizeHint int) (ipld.MapAssembler, error) {
+	na.w.t = make([]_Map_K2_T2__entry, 0, sizeHint)
+	na.w.m = make(map[K2]*T2, sizeHint)
+	na.ka.ma = na
+	na.va.ma = na
+	return na, nil
+}
+func (_Map_K2_T2__Assembler) BeginList(_ int) (ipld.ListAssembler, error) { panic("no") }
+func (_Map_K2_T2__Assembler) AssignNull() error                           { panic("no") }
+func (_Map_K2_T2__Assembler) AssignBool(bool) error                       { panic("no") }
+func (_Map_K2_T2__Assembler) AssignInt(v int) error                       { panic("no") }
+func (_Map_K2_T2__Assembler) AssignFloat(float64) error                   { panic("no") }
+func (_Map_K2_T2__Assembler) AssignString(v string) error                 { panic("no") }
+func (_Map_K2_T2__Assembler) AssignBytes([]byte) error                    { panic("no") }
+func (_Map_K2_T2__Assembler) AssignLink(ipld.Link) error                  { panic("no") }
+func (ta *_Map_K2_T2__Assembler) AssignNode(v ipld.Node) error {
+	if v2, ok := v.(*Map_K2_T2); ok {
+		*ta.w = *v2
+		ta.state = maState_finished
+		return nil
+	}
+	return ipld.Copy(v, ta)
+}
+func (_Map_K2_T2__Assembler) Style() ipld.NodeStyle { return Type__Map_K2_T2{} }
+
+func (ma *_Map_K2_T2__Assembler) AssembleEntry(k string) (ipld.NodeAssembler, error) {
+	// Sanity check assembler state.
+	if ma.state != maState_initial {
+		panic("misuse")
+	}
+	// A K2 is a struct, so a plain string can't name one (at least not at the type level).
+	return nil, ipld.ErrWrongKind{TypeName: "K2", MethodName: "AssembleEntry", AppropriateKind: ipld.ReprKindSet_JustMap, ActualKind: ipld.ReprKind_String}
+}
+func (ma *_Map_K2_T2__Assembler) AssembleKey() ipld.NodeAssembler {
+	// Sanity check, then update, assembler state.
+	if ma.state != maState_initial {
+		panic("misuse")
+	}
+	ma.state = maState_midKey
+	// Extend entry table.
+	l := len(ma.w.t)
+	ma.w.t = append(ma.w.t, _Map_K2_T2__entry{})
+	// Init the key assembler with a pointer to its target and to whole 'ma' and yield it.
+	ma.ka.ma = ma
+	ma.ka.ca.w = &ma.w.t[l].k
+	return &ma.ka
+}
+func (ma *_Map_K2_T2__Assembler) AssembleValue() ipld.NodeAssembler {
+	// Sanity check, then update, assembler state.
+	if ma.state != maState_expectValue {
+		panic("misuse")
+	}
+	ma.state = maState_midValue
+	// Init the value assembler with a pointer to its target and yield it.
+	ma.va.ma = ma
+	ma.va.ca.w = &ma.w.t[len(ma.w.t)-1].v
+	return &ma.va
+}
+func (ma *_Map_K2_T2__Assembler) Finish() error {
+	// Sanity check, then update, assembler state.
+	if ma.state != maState_initial {
+		panic("misuse")
+	}
+	ma.state = maState_finished
+	// Appending to 'w.t' may have moved it since earlier rows were indexed; point 'w.m' at the final rows.
+	for i := range ma.w.t {
+		ma.w.m[ma.w.t[i].k] = &ma.w.t[i].v
+	}
+	return nil
+}
+func (_Map_K2_T2__Assembler) KeyStyle() ipld.NodeStyle           { panic("later") }
+func (_Map_K2_T2__Assembler) ValueStyle(_ string) ipld.NodeStyle { panic("later") }
+
+func (mka *_Map_K2_T2__KeyAssembler) BeginMap(sizeHint int) (ipld.MapAssembler, error) {
+	// The K2 assembler writes straight into the tail of the entry table, same as AssignNode;
+	//  the wrapper's Finish does the rest of what AssignNode does.
+	ma := &_Map_K2_T2__KeyAssemblerMap{p: mka}
+	ma.ca.w = mka.ca.w
+	_, err := ma.ca.BeginMap(sizeHint)
+	return ma, err
+}
+func (_Map_K2_T2__KeyAssembler) BeginList(sizeHint int) (ipld.ListAssembler, error) { panic("no") }
+func (_Map_K2_T2__KeyAssembler) AssignNull() error                                  { panic("no") }
+func (_Map_K2_T2__KeyAssembler) AssignBool(bool) error                              { panic("no") }
+func (_Map_K2_T2__KeyAssembler) AssignInt(int) error                                { panic("no") }
+func (_Map_K2_T2__KeyAssembler) AssignFloat(float64) error                          { panic("no") }
+func (_Map_K2_T2__KeyAssembler) AssignString(string) error                          { panic("no") }
+func (_Map_K2_T2__KeyAssembler) AssignBytes([]byte) error                           { panic("no") }
+func (_Map_K2_T2__KeyAssembler) AssignLink(ipld.Link) error                         { panic("no") }
+func (mka *_Map_K2_T2__KeyAssembler) AssignNode(v ipld.Node) error {
+	// Delegate to the key type's assembler, which writes straight into the tail of the entry table.
+	if err := mka.ca.AssignNode(v); err != nil {
+		mka.rollback()
+		return err
+	}
+	return mka.finish()
+}
+
+// finish is called once the whole key is known, whether it was assigned or built field by field.
+func (mka *_Map_K2_T2__KeyAssembler) finish() error {
+	// Only now that the whole key is known can we check it for repeats.
+	if err := mka.ma.checkRepeatedKey(mka.ca.w); err != nil {
+		mka.rollback()
+		return err
+	}
+	mka.ma.w.m[*mka.ca.w] = &mka.ma.w.t[len(mka.ma.w.t)-1].v
+	mka.ma.state = maState_expectValue
+	mka.ca.w = nil
+	return nil
+}
+func (mka *_Map_K2_T2__KeyAssembler) rollback() {
+	mka.ma.w.t = mka.ma.w.t[:len(mka.ma.w.t)-1]
+	mka.ma.state = maState_initial
+	mka.ca.w = nil
+}
+func (_Map_K2_T2__KeyAssembler) Style() ipld.NodeStyle { panic("later") }
+
+func (mva *_Map_K2_T2__ValueAssembler) BeginMap(sizeHint int) (ipld.MapAssembler, error) {
+	ma := &_Map_K2_T2__ValueAssemblerMap{p: mva}
+	ma.ca.w = mva.ca.w
+	_, err := ma.ca.BeginMap(sizeHint)
+	return ma, err
+}
+func (_Map_K2_T2__ValueAssembler) BeginList(sizeHint int) (ipld.ListAssembler, error) { panic("no") }
+func (_Map_K2_T2__ValueAssembler) AssignNull() error                                  { panic("no") }
+func (_Map_K2_T2__ValueAssembler) AssignBool(bool) error                              { panic("no") }
+func (_Map_K2_T2__ValueAssembler) AssignInt(int) error                                { panic("no") }
+func (_Map_K2_T2__ValueAssembler) AssignFloat(float64) error                          { panic("no") }
+func (_Map_K2_T2__ValueAssembler) AssignString(string) error                          { panic("no") }
+func (_Map_K2_T2__ValueAssembler) AssignBytes([]byte) error                           { panic("no") }
+func (_Map_K2_T2__ValueAssembler) AssignLink(ipld.Link) error                         { panic("no") }
+func (mva *_Map_K2_T2__ValueAssembler) AssignNode(v ipld.Node) error {
+	if err := mva.ca.AssignNode(v); err != nil {
+		mva.rollback()
+		return err
+	}
+	mva.ma.state = maState_initial
+	mva.ca.w = nil
+	return nil
+}
+func (mva *_Map_K2_T2__ValueAssembler) rollback() {
+	// Same recovery contract as _Map_K_T__ValueAssembler: forget the key, too.
+	ma := mva.ma
+	l := len(ma.w.t) - 1
+	delete(ma.w.m, ma.w.t[l].k)
+	ma.w.t = ma.w.t[:l]
+	ma.state = maState_initial
+	mva.ca.w = nil
+}
+func (_Map_K2_T2__ValueAssembler) Style() ipld.NodeStyle { panic("later") }
+
+// _Map_K2_T2__KeyAssemblerMap and _Map_K2_T2__ValueAssemblerMap are what the key and value assemblers
+// yield from BeginMap.  They delegate to the struct assembler, and on Finish,
+// hand control back to the map assembler, just like the key and value assemblers' AssignNode would.
+type _Map_K2_T2__KeyAssemblerMap struct {
+	ca _K2__Assembler
+	p  *_Map_K2_T2__KeyAssembler // pointer back to parent, for the repeated key check and state bump
+}
+type _Map_K2_T2__ValueAssemblerMap struct {
+	ca _T2__Assembler
+	p  *_Map_K2_T2__ValueAssembler // pointer back to parent, for the state bump
+}
+
+func (ma *_Map_K2_T2__KeyAssemblerMap) AssembleEntry(k string) (ipld.NodeAssembler, error) {
+	return ma.ca.AssembleEntry(k)
+}
+func (ma *_Map_K2_T2__KeyAssemblerMap) AssembleKey() ipld.NodeAssembler {
+	return ma.ca.AssembleKey()
+}
+func (ma *_Map_K2_T2__KeyAssemblerMap) AssembleValue() ipld.NodeAssembler {
+	return ma.ca.AssembleValue()
+}
+func (ma *_Map_K2_T2__KeyAssemblerMap) Finish() error {
+	if err := ma.ca.Finish(); err != nil {
+		return err
+	}
+	ma.ca.w = nil
+	return ma.p.finish()
+}
+func (_Map_K2_T2__KeyAssemblerMap) KeyStyle() ipld.NodeStyle           { panic("later") }
+func (_Map_K2_T2__KeyAssemblerMap) ValueStyle(_ string) ipld.NodeStyle { panic("later") }
+
+func (ma *_Map_K2_T2__ValueAssemblerMap) AssembleEntry(k string) (ipld.NodeAssembler, error) {
+	return ma.ca.AssembleEntry(k)
+}
+func (ma *_Map_K2_T2__ValueAssemblerMap) AssembleKey() ipld.NodeAssembler {
+	return ma.ca.AssembleKey()
+}
+func (ma *_Map_K2_T2__ValueAssemblerMap) AssembleValue() ipld.NodeAssembler {
+	return ma.ca.AssembleValue()
+}
+func (ma *_Map_K2_T2__ValueAssemblerMap) Finish() error {
+	if err := ma.ca.Finish(); err != nil {
+		return err
+	}
+	ma.ca.w = nil
+	ma.p.ma.state = maState_initial
+	ma.p.ca.w = nil
+	return nil
+}
+func (_Map_K2_T2__ValueAssemblerMap) KeyStyle() ipld.NodeStyle           { panic("later") }
+func (_Map_K2_T2__ValueAssemblerMap) ValueStyle(_ string) ipld.NodeStyle { panic("later") }
+
+// checkRepeatedKey is what the key assembler calls on finishing a key.
+// The error carries the whole complex key as a Node; it's rendered as a map, e.g. `{"u": "a", "i": "b"}`.
+func (ma *_Map_K2_T2__Assembler) checkRepeatedKey(k *K2) error {
+	if _, exists := ma.w.m[*k]; exists {
+		return ipld.ErrRepeatedMapKey{k}
+	}
+	return nil
+}
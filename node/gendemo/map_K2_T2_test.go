@@ -0,0 +1,204 @@
+package gendemo
+
+import (
+	"testing"
+
+	"github.com/warpfork/go-wish"
+
+	ipld "github.com/ipld/go-ipld-prime"
+	"github.com/ipld/go-ipld-prime/node/tests"
+)
+
+func TestMapK2T2RepeatedKey(t *testing.T) {
+	k := K2{"a", "b"}
+	ma := &_Map_K2_T2__Assembler{w: &Map_K2_T2{m: map[K2]*T2{}}}
+	wish.Wish(t, ma.checkRepeatedKey(&k), wish.ShouldEqual, nil)
+	ma.w.m[k] = &T2{}
+	err := ma.checkRepeatedKey(&K2{"a", "b"})
+	wish.Wish(t, err, wish.ShouldBeSameTypeAs, ipld.ErrRepeatedMapKey{})
+	wish.Wish(t, err.Error(), wish.ShouldEqual, `cannot repeat map key ({"u": "a", "i": "b"})`)
+	wish.Wish(t, ma.checkRepeatedKey(&K2{"a", "c"}), wish.ShouldEqual, nil)
+}
+
+func TestMapK2T2AssembleRepeatedKey(t *testing.T) {
+	nb := Type__Map_K2_T2{}.NewBuilder()
+	ma, err := nb.BeginMap(2)
+	wish.Require(t, err, wish.ShouldEqual, nil)
+	wish.Require(t, ma.AssembleKey().AssignNode(&K2{"a", "b"}), wish.ShouldEqual, nil)
+	wish.Require(t, ma.AssembleValue().AssignNode(&T2{1, 2, 3, 4}), wish.ShouldEqual, nil)
+
+	t.Run("by AssignNode", func(t *testing.T) {
+		err := ma.AssembleKey().AssignNode(&K2{"a", "b"})
+		wish.Wish(t, err, wish.ShouldEqual, ipld.ErrRepeatedMapKey{&K2{"a", "b"}})
+	})
+	t.Run("field by field", func(t *testing.T) {
+		kma, err := ma.AssembleKey().BeginMap(2)
+		wish.Require(t, err, wish.ShouldEqual, nil)
+		wish.Require(t, kma.AssembleKey().AssignString("i"), wish.ShouldEqual, nil)
+		wish.Require(t, kma.AssembleValue().AssignString("b"), wish.ShouldEqual, nil)
+		va, err := kma.AssembleEntry("u")
+		wish.Require(t, err, wish.ShouldEqual, nil)
+		wish.Require(t, va.AssignString("a"), wish.ShouldEqual, nil)
+		err = kma.Finish()
+		wish.Wish(t, err, wish.ShouldEqual, ipld.ErrRepeatedMapKey{&K2{"a", "b"}})
+		wish.Wish(t, err.Error(), wish.ShouldEqual, `cannot repeat map key ({"u": "a", "i": "b"})`)
+	})
+	t.Run("by AssembleEntry", func(t *testing.T) {
+		// A string can't name a K2 at all, so this never gets as far as checking for repeats.
+		_, err := ma.AssembleEntry("a:b")
+		wish.Wish(t, err, wish.ShouldBeSameTypeAs, ipld.ErrWrongKind{})
+	})
+
+	// The rejected keys left nothing behind; the first entry is intact, and assembly carries on.
+	wish.Require(t, ma.AssembleKey().AssignNode(&K2{"a", "c"}), wish.ShouldEqual, nil)
+	wish.Require(t, ma.AssembleValue().AssignNode(&T2{5, 6, 7, 8}), wish.ShouldEqual, nil)
+	wish.Require(t, ma.Finish(), wish.ShouldEqual, nil)
+	wish.Wish(t, ipld.Sprint(nb.Build()), wish.ShouldEqual, `{{"u": "a", "i": "b"}: {"a": 1, "b": 2, "c": 3, "d": 4}, {"u": "a", "i": "c"}: {"a": 5, "b": 6, "c": 7, "d": 8}}`)
+}
+
+func TestMapK2T2LookupString(t *testing.T) {
+	n := &Map_K2_T2{m: map[K2]*T2{}}
+	_, err := n.LookupString("a:b")
+	wish.Wish(t, err, wish.ShouldBeSameTypeAs, ipld.ErrWrongKind{})
+	_, err = n.LookupSegment(ipld.PathSegmentOfString("a:b"))
+	wish.Wish(t, err, wish.ShouldBeSameTypeAs, ipld.ErrWrongKind{})
+}
+
+func TestStructRepeatedField(t *testing.T) {
+	ma := &_K2__Assembler{w: &K2{}, isset_u: true}
+	_, err := ma.AssembleEntry("u")
+	wish.Wish(t, err, wish.ShouldEqual, ipld.ErrRepeatedMapKey{fieldName__K2_u})
+	wish.Wish(t, err.Error(), wish.ShouldEqual, `cannot repeat map key ("u")`)
+}
+
+func TestStructAssembleEntries(t *testing.T) {
+	w := &T2{}
+	na := &_T2__Assembler{w: w}
+	ma, err := na.BeginMap(4)
+	wish.Require(t, err, wish.ShouldEqual, nil)
+	// Each way in: AssembleEntry, then AssembleKey and AssembleValue, then AssembleEntry again.
+	va, err := ma.AssembleEntry("a")
+	wish.Require(t, err, wish.ShouldEqual, nil)
+	wish.Require(t, va.AssignInt(1), wish.ShouldEqual, nil)
+	wish.Require(t, ma.AssembleKey().AssignString("b"), wish.ShouldEqual, nil)
+	wish.Require(t, ma.AssembleValue().AssignInt(2), wish.ShouldEqual, nil)
+	va, err = ma.AssembleEntry("c")
+	wish.Require(t, err, wish.ShouldEqual, nil)
+	wish.Require(t, va.AssignInt(3), wish.ShouldEqual, nil)
+
+	t.Run("repeated and unknown keys are rejected", func(t *testing.T) {
+		_, err := ma.AssembleEntry("a")
+		wish.Wish(t, err, wish.ShouldEqual, ipld.ErrRepeatedMapKey{fieldName__T2_a})
+		err = ma.AssembleKey().AssignString("z")
+		wish.Wish(t, err, wish.ShouldEqual, ipld.ErrInvalidStructKey{TypeName: "T2", Key: "z"})
+		wish.Wish(t, err.Error(), wish.ShouldEqual, `invalid key for struct T2: "z" is not a field`)
+	})
+	t.Run("keys of the wrong kind are rejected, and assembly carries on", func(t *testing.T) {
+		wish.Wish(t, ma.AssembleKey().AssignInt(1), wish.ShouldBeSameTypeAs, ipld.ErrWrongKind{})
+		_, err := ma.AssembleKey().BeginMap(0)
+		wish.Wish(t, err, wish.ShouldBeSameTypeAs, ipld.ErrWrongKind{})
+		wish.Wish(t, ma.AssembleKey().AssignNull(), wish.ShouldBeSameTypeAs, ipld.ErrWrongKind{})
+	})
+	t.Run("a failed value leaves the field unset", func(t *testing.T) {
+		va, err := ma.AssembleEntry("d")
+		wish.Require(t, err, wish.ShouldEqual, nil)
+		wish.Wish(t, va.AssignString("four"), wish.ShouldBeSameTypeAs, ipld.ErrWrongKind{})
+		err = ma.Finish()
+		wish.Wish(t, err, wish.ShouldEqual, ipld.ErrMissingRequiredField{TypeName: "T2", Missing: []string{"d"}})
+		wish.Wish(t, err.Error(), wish.ShouldEqual, "missing required fields for struct T2: d")
+	})
+
+	va, err = ma.AssembleEntry("d")
+	wish.Require(t, err, wish.ShouldEqual, nil)
+	wish.Require(t, va.AssignInt(4), wish.ShouldEqual, nil)
+	wish.Require(t, ma.Finish(), wish.ShouldEqual, nil)
+	wish.Wish(t, w, wish.ShouldEqual, &T2{1, 2, 3, 4})
+}
+
+func TestMapK2T2AssembleStructs(t *testing.T) {
+	nb := Type__Map_K2_T2{}.NewBuilder()
+	ma, err := nb.BeginMap(1)
+	wish.Require(t, err, wish.ShouldEqual, nil)
+	kma, err := ma.AssembleKey().BeginMap(2)
+	wish.Require(t, err, wish.ShouldEqual, nil)
+	wish.Require(t, kma.AssembleKey().AssignString("u"), wish.ShouldEqual, nil)
+	wish.Require(t, kma.AssembleValue().AssignString("a"), wish.ShouldEqual, nil)
+	va, err := kma.AssembleEntry("i")
+	wish.Require(t, err, wish.ShouldEqual, nil)
+	wish.Require(t, va.AssignString("b"), wish.ShouldEqual, nil)
+	wish.Require(t, kma.Finish(), wish.ShouldEqual, nil)
+	vma, err := ma.AssembleValue().BeginMap(4)
+	wish.Require(t, err, wish.ShouldEqual, nil)
+	for i, k := range []string{"a", "b", "c", "d"} {
+		va, err := vma.AssembleEntry(k)
+		wish.Require(t, err, wish.ShouldEqual, nil)
+		wish.Require(t, va.AssignInt(i+1), wish.ShouldEqual, nil)
+	}
+	wish.Require(t, vma.Finish(), wish.ShouldEqual, nil)
+	wish.Require(t, ma.Finish(), wish.ShouldEqual, nil)
+	wish.Wish(t, ipld.Sprint(nb.Build()), wish.ShouldEqual, `{{"u": "a", "i": "b"}: {"a": 1, "b": 2, "c": 3, "d": 4}}`)
+}
+
+func TestK2WrongKind(t *testing.T) {
+	_, err := K2{"a", "b"}.AsInt()
+	wish.Wish(t, err, wish.ShouldEqual, ipld.ErrWrongKind{TypeName: "K2", MethodName: "AsInt", AppropriateKind: ipld.ReprKindSet_JustInt, ActualKind: ipld.ReprKind_Map, RepresentationKind: ipld.ReprKind_String})
+	wish.Wish(t, err.Error(), wish.ShouldEqual, "func called on wrong kind: AsInt called on a K2 node (kind: Map, repr kind: String), but only makes sense on Int")
+}
+
+func TestK2T2KindErrors(t *testing.T) {
+	t.Run("K2", func(t *testing.T) {
+		tests.AssertKindErrors(t, &K2{"a", "b"}, ipld.ReprKind_Map)
+	})
+	t.Run("T2", func(t *testing.T) {
+		tests.AssertKindErrors(t, &T2{}, ipld.ReprKind_Map)
+	})
+}
+
+func TestT2Conformance(t *testing.T) {
+	// T2 has no style of its own yet, so this checks a node made directly.
+	tests.CheckConformance(t, &T2{1, 2, 3, 4})
+}
+
+// styleless hides a node's style, so ipld.Copy can't take the same-style shortcut and has to walk it.
+type styleless struct{ ipld.Node }
+
+func (styleless) Style() ipld.NodeStyle { return nil }
+
+func TestMapK2T2Copy(t *testing.T) {
+	nb := Type__Map_K2_T2{}.NewBuilder()
+	ma, err := nb.BeginMap(2)
+	wish.Require(t, err, wish.ShouldEqual, nil)
+	wish.Require(t, ma.AssembleKey().AssignNode(&K2{"a", "b"}), wish.ShouldEqual, nil)
+	wish.Require(t, ma.AssembleValue().AssignNode(&T2{1, 2, 3, 4}), wish.ShouldEqual, nil)
+	wish.Require(t, ma.AssembleKey().AssignNode(&K2{"c", "d"}), wish.ShouldEqual, nil)
+	wish.Require(t, ma.AssembleValue().AssignNode(&T2{5, 6, 7, 8}), wish.ShouldEqual, nil)
+	wish.Require(t, ma.Finish(), wish.ShouldEqual, nil)
+	src := nb.Build()
+	want := `{{"u": "a", "i": "b"}: {"a": 1, "b": 2, "c": 3, "d": 4}, {"u": "c", "i": "d"}: {"a": 5, "b": 6, "c": 7, "d": 8}}`
+	wish.Require(t, ipld.Sprint(src), wish.ShouldEqual, want)
+
+	t.Run("same style", func(t *testing.T) {
+		nb := Type__Map_K2_T2{}.NewBuilder()
+		wish.Require(t, ipld.Copy(src, nb), wish.ShouldEqual, nil)
+		wish.Wish(t, ipld.Sprint(nb.Build()), wish.ShouldEqual, want)
+	})
+	t.Run("generic walk", func(t *testing.T) {
+		nb := Type__Map_K2_T2{}.NewBuilder()
+		wish.Require(t, ipld.Copy(styleless{src}, nb), wish.ShouldEqual, nil)
+		n := nb.Build()
+		wish.Wish(t, ipld.Sprint(n), wish.ShouldEqual, want)
+		v, err := n.Lookup(&K2{"c", "d"})
+		wish.Require(t, err, wish.ShouldEqual, nil)
+		wish.Wish(t, v, wish.ShouldEqual, &T2{5, 6, 7, 8})
+	})
+	t.Run("repeated key", func(t *testing.T) {
+		nb := Type__Map_K2_T2{}.NewBuilder()
+		ma, _ := nb.BeginMap(2)
+		wish.Require(t, ma.AssembleKey().AssignNode(&K2{"a", "b"}), wish.ShouldEqual, nil)
+		wish.Require(t, ma.AssembleValue().AssignNode(&T2{}), wish.ShouldEqual, nil)
+		err := ma.AssembleKey().AssignNode(&K2{"a", "b"})
+		wish.Wish(t, err, wish.ShouldBeSameTypeAs, ipld.ErrRepeatedMapKey{})
+		wish.Wish(t, ma.Finish(), wish.ShouldEqual, nil)
+		wish.Wish(t, nb.Build().Length(), wish.ShouldEqual, 1)
+	})
+}
@@ -70,7 +70,7 @@ func (K) Style() ipld.NodeStyle {
 }
 
 func (T) ReprKind() ipld.ReprKind {
-	return ipld.ReprKind_String
+	return ipld.ReprKind_Int
 }
 func (T) LookupString(string) (ipld.Node, error) {
 	return nil, ipld.ErrWrongKind{MethodName: "LookupString", AppropriateKind: ipld.ReprKindSet_JustMap, ActualKind: ipld.ReprKind_Int}
@@ -250,7 +250,7 @@ func (Map_K_T) AsBool() (bool, error) {
 	return false, ipld.ErrWrongKind{TypeName: "Map_K_T", MethodName: "AsBool", AppropriateKind: ipld.ReprKindSet_JustBool, ActualKind: ipld.ReprKind_Map}
 }
 func (Map_K_T) AsInt() (int, error) {
-	return 0, ipld.ErrWrongKind{TypeName: "Map_K_T", MethodName: "AsInt", AppropriateKind: ipld.ReprKindSet_JustFloat, ActualKind: ipld.ReprKind_Map}
+	return 0, ipld.ErrWrongKind{TypeName: "Map_K_T", MethodName: "AsInt", AppropriateKind: ipld.ReprKindSet_JustInt, ActualKind: ipld.ReprKind_Map}
 }
 func (Map_K_T) AsFloat() (float64, error) {
 	return 0, ipld.ErrWrongKind{TypeName: "Map_K_T", MethodName: "AsFloat", AppropriateKind: ipld.ReprKindSet_JustFloat, ActualKind: ipld.ReprKind_Map}
@@ -288,6 +288,10 @@ func (itr *_Map_K_T_MapIterator) Done() bool {
 
 // Type__Map_K_T  implements both schema.Type and ipld.NodeStyle.
 //
+// It's an empty struct, so every value of it is the same style (per ipld.SameStyle);
+// a style for a type with parameters (e.g. an ADL) would carry them as fields,
+// and differ whenever they differ.
+//
 // REVIEW: Should this just be exported?  I think probably yes.
 // Alternatives: `Types().Map_K_T().NewBuilder()`; or, `Types` as a large const?
 type Type__Map_K_T struct{}
@@ -313,6 +317,11 @@ func (Type__Map_K_T) NewBuilder() ipld.NodeBuilder {
 //
 // (Yep, basically any path through key *or* value assembly may error, and if they do,
 // the parent has to roll back the last entry in 'w.t' -- so everything has a wrapper.)
+//
+// The recovery contract is: an error from key or value assembly leaves the map
+// exactly as it was before that key was started, and returns the assembler to
+// maState_initial.  So, a failed value doesn't leave a half-set entry behind,
+// and the caller may simply try the same key again.
 type _Map_K_T__Assembler struct {
 	w  *Map_K_T
 	ka _Map_K_T__KeyAssembler
@@ -363,25 +372,27 @@ func (_Map_K_T__Assembler) AssignLink(ipld.Link) error                      { pa
 func (ta *_Map_K_T__Assembler) AssignNode(v ipld.Node) error {
 	if v2, ok := v.(*Map_K_T); ok {
 		*ta.w = *v2
-		ta.w = nil // block further mutation
+		ta.state = maState_finished // block further mutation.  (Not by nil'ing 'w': the builder still needs it for Build.)
 		return nil
 	}
-	// todo: apply a generic 'copy' function.
-	panic("later")
+	// If the above shortcut didn't work, resort to a generic copy.
+	//  (Copy would itself come back here if 'v' was our own style, but we already know it isn't.)
+	return ipld.Copy(v, ta)
 }
-func (_Map_K_T__Assembler) Style() ipld.NodeStyle { panic("later") }
+func (_Map_K_T__Assembler) Style() ipld.NodeStyle { return Type__Map_K_T{} }
 
 func (ma *_Map_K_T__Assembler) AssembleEntry(k string) (ipld.NodeAssembler, error) {
 	// Sanity check, then update, assembler state.
 	if ma.state != maState_initial {
 		panic("misuse")
 	}
-	ma.state = maState_midValue
 	// Check for dup keys; error if so.
+	//  (This is before the state update, so the assembler is still usable afterwards.)
 	_, exists := ma.w.m[K{k}]
 	if exists {
 		return nil, ipld.ErrRepeatedMapKey{&K{k}}
 	}
+	ma.state = maState_midValue
 	// Extend entry table and update map to point into the new row.
 	l := len(ma.w.t)
 	ma.w.t = append(ma.w.t, _Map_K_T__entry{k: K{k}})
@@ -437,6 +448,7 @@ func (mka *_Map_K_T__KeyAssembler) AssignString(v string) error {
 	// Check for dup keys; error if so.
 	_, exists := mka.ma.w.m[K{v}]
 	if exists {
+		mka.rollback()
 		k := K{v}
 		return ipld.ErrRepeatedMapKey{&k}
 	}
@@ -444,6 +456,7 @@ func (mka *_Map_K_T__KeyAssembler) AssignString(v string) error {
 	//  This results in the entry table memory being updated.
 	//  When it returns, the delegated assembler should've already nil'd its 'w' to prevent further mutation.
 	if err := mka.ca.AssignString(v); err != nil {
+		mka.rollback()
 		return err // REVIEW:errors: probably deserves a wrapper indicating the error came during key coersion.
 	}
 	// Update the map to point into the entry value!
@@ -454,11 +467,17 @@ func (mka *_Map_K_T__KeyAssembler) AssignString(v string) error {
 	mka.ma = nil // invalidate self to prevent further incorrect use.
 	return nil
 }
+func (mka *_Map_K_T__KeyAssembler) rollback() {
+	// AssembleKey extended the entry table but the key never made it into the map, so only the table needs un-extending.
+	mka.ma.w.t = mka.ma.w.t[:len(mka.ma.w.t)-1]
+	mka.ma.state = maState_initial
+}
 func (_Map_K_T__KeyAssembler) AssignBytes([]byte) error   { panic("no") }
 func (_Map_K_T__KeyAssembler) AssignLink(ipld.Link) error { panic("no") }
 func (mka *_Map_K_T__KeyAssembler) AssignNode(v ipld.Node) error {
 	vs, err := v.AsString()
 	if err != nil {
+		mka.rollback()
 		return fmt.Errorf("cannot assign non-string node into map key assembler") // FIXME:errors: this doesn't quite fit in ErrWrongKind cleanly; new error type?
 	}
 	return mka.AssignString(vs)
@@ -466,31 +485,63 @@ func (mka *_Map_K_T__KeyAssembler) AssignNode(v ipld.Node) error {
 func (_Map_K_T__KeyAssembler) Style() ipld.NodeStyle { panic("later") } // probably should give the style of plainString, which could say "only stores string kind" (though we haven't made such a feature part of the interface yet).
 
 func (mva *_Map_K_T__ValueAssembler) BeginMap(sizeHint int) (ipld.MapAssembler, error) {
-	panic("todo") // We would add the additional required methods to 'mva' to save another type... but in this case it's also clear to us at codegen time this method can just error.
+	// We would add the additional required methods to 'mva' to save another type... but in this case it's also clear to us at codegen time this method can just error.
+	return nil, mva.reject("BeginMap", ipld.ReprKind_Map)
 }
 func (mva *_Map_K_T__ValueAssembler) BeginList(sizeHint int) (ipld.ListAssembler, error) {
-	panic("todo") // We would add the additional required methods to 'mva' to save another type... but in this case it's also clear to us at codegen time this method can just error.
+	return nil, mva.reject("BeginList", ipld.ReprKind_List)
+}
+
+// All these scalar rejections also clear to us at codegen time.  We can report them without delegation.  Should?  Debatable; but will save SLOC.
+func (mva *_Map_K_T__ValueAssembler) AssignNull() error {
+	return mva.reject("AssignNull", ipld.ReprKind_Null)
+}
+func (mva *_Map_K_T__ValueAssembler) AssignBool(bool) error {
+	return mva.reject("AssignBool", ipld.ReprKind_Bool)
 }
-func (mva *_Map_K_T__ValueAssembler) AssignNull() error     { panic("todo") } // All these scalar rejections also clear to us at codegen time.  We can report them without delegation.  Should?  Debatable; but will save SLOC.
-func (mva *_Map_K_T__ValueAssembler) AssignBool(bool) error { panic("todo") }
 func (mva *_Map_K_T__ValueAssembler) AssignInt(v int) error {
 	if err := mva.ca.AssignInt(v); err != nil {
+		mva.rollback()
 		return err
 	}
 	mva.flush()
 	return nil
 }
-func (mva *_Map_K_T__ValueAssembler) AssignFloat(float64) error   { panic("todo") }
-func (mva *_Map_K_T__ValueAssembler) AssignString(v string) error { panic("todo") }
-func (mva *_Map_K_T__ValueAssembler) AssignBytes([]byte) error    { panic("todo") }
-func (mva *_Map_K_T__ValueAssembler) AssignLink(ipld.Link) error  { panic("todo") }
+func (mva *_Map_K_T__ValueAssembler) AssignFloat(float64) error {
+	return mva.reject("AssignFloat", ipld.ReprKind_Float)
+}
+func (mva *_Map_K_T__ValueAssembler) AssignString(v string) error {
+	return mva.reject("AssignString", ipld.ReprKind_String)
+}
+func (mva *_Map_K_T__ValueAssembler) AssignBytes([]byte) error {
+	return mva.reject("AssignBytes", ipld.ReprKind_Bytes)
+}
+func (mva *_Map_K_T__ValueAssembler) AssignLink(ipld.Link) error {
+	return mva.reject("AssignLink", ipld.ReprKind_Link)
+}
 func (mva *_Map_K_T__ValueAssembler) AssignNode(v ipld.Node) error {
 	if err := mva.ca.AssignNode(v); err != nil {
+		mva.rollback()
 		return err
 	}
 	mva.flush()
 	return nil
 }
+func (mva *_Map_K_T__ValueAssembler) reject(methodName string, kind ipld.ReprKind) error {
+	mva.rollback()
+	return ipld.ErrWrongKind{TypeName: "T", MethodName: methodName, AppropriateKind: ipld.ReprKindSet_JustInt, ActualKind: kind}
+}
+func (mva *_Map_K_T__ValueAssembler) rollback() {
+	// By the time there's a value assembler, the key is confirmed: it's in the tail of 'w.t' *and* indexed in 'w.m'.
+	//  Undo both, so it's as if the key was never assembled; then the caller may retry that key (or any other).
+	// Truncating 'w.t' doesn't invalidate the pointers 'w.m' holds to earlier rows; it's the same backing array.
+	ma := mva.ma
+	l := len(ma.w.t) - 1
+	delete(ma.w.m, ma.w.t[l].k)
+	ma.w.t = ma.w.t[:l]
+	ma.state = maState_initial
+	mva.ca.w = nil
+}
 func (mva *_Map_K_T__ValueAssembler) flush() {
 	// The child assembler already assigned directly into the target memory,
 	//  so there's not much to do here... except update the assembler state machine.
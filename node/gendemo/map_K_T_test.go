@@ -3,6 +3,12 @@ package gendemo
 import (
 	"testing"
 
+	"github.com/warpfork/go-wish"
+
+	ipld "github.com/ipld/go-ipld-prime"
+	"github.com/ipld/go-ipld-prime/fluent"
+	"github.com/ipld/go-ipld-prime/must"
+	basicnode "github.com/ipld/go-ipld-prime/node/basic"
 	"github.com/ipld/go-ipld-prime/node/tests"
 )
 
@@ -29,3 +35,107 @@ func BenchmarkMapStrInt_25n_AssembleEntry(b *testing.B) {
 func BenchmarkMapStrInt_25n_Iteration(b *testing.B) {
 	tests.SpecBenchmarkMapStrInt_25n_Iteration(b, Type__Map_K_T{})
 }
+
+func TestMapAssemblerRecovery(t *testing.T) {
+	t.Run("failed value lets the same key be retried", func(t *testing.T) {
+		nb := Type__Map_K_T{}.NewBuilder()
+		ma, err := nb.BeginMap(2)
+		wish.Require(t, err, wish.ShouldEqual, nil)
+		va, err := ma.AssembleEntry("whee")
+		wish.Require(t, err, wish.ShouldEqual, nil)
+		// T has no validations of its own, so the way to make an int assignment fail is to feed it a non-int.
+		wish.Wish(t, va.AssignNode(plainString("not an int")), wish.ShouldBeSameTypeAs, ipld.ErrWrongKind{})
+		va, err = ma.AssembleEntry("whee")
+		wish.Require(t, err, wish.ShouldEqual, nil)
+		wish.Wish(t, va.AssignInt(1), wish.ShouldEqual, nil)
+		wish.Wish(t, ma.Finish(), wish.ShouldEqual, nil)
+		n := nb.Build()
+		wish.Wish(t, n.Length(), wish.ShouldEqual, 1)
+		v, err := n.LookupString("whee")
+		wish.Require(t, err, wish.ShouldEqual, nil)
+		wish.Wish(t, must.Int(v), wish.ShouldEqual, 1)
+	})
+	t.Run("failed value via AssembleKey path rolls back too", func(t *testing.T) {
+		nb := Type__Map_K_T{}.NewBuilder()
+		ma, err := nb.BeginMap(2)
+		wish.Require(t, err, wish.ShouldEqual, nil)
+		wish.Wish(t, ma.AssembleKey().AssignString("whee"), wish.ShouldEqual, nil)
+		wish.Wish(t, ma.AssembleValue().AssignString("not an int"), wish.ShouldBeSameTypeAs, ipld.ErrWrongKind{})
+		wish.Wish(t, ma.AssembleKey().AssignString("whee"), wish.ShouldEqual, nil)
+		wish.Wish(t, ma.AssembleValue().AssignInt(2), wish.ShouldEqual, nil)
+		wish.Wish(t, ma.Finish(), wish.ShouldEqual, nil)
+		n := nb.Build()
+		wish.Wish(t, n.Length(), wish.ShouldEqual, 1)
+		_, err = n.LookupString("whee")
+		wish.Wish(t, err, wish.ShouldEqual, nil)
+	})
+	t.Run("repeated key leaves the assembler usable", func(t *testing.T) {
+		nb := Type__Map_K_T{}.NewBuilder()
+		ma, err := nb.BeginMap(2)
+		wish.Require(t, err, wish.ShouldEqual, nil)
+		va, err := ma.AssembleEntry("whee")
+		wish.Require(t, err, wish.ShouldEqual, nil)
+		wish.Wish(t, va.AssignInt(1), wish.ShouldEqual, nil)
+		_, err = ma.AssembleEntry("whee")
+		wish.Wish(t, err, wish.ShouldBeSameTypeAs, ipld.ErrRepeatedMapKey{})
+		wish.Wish(t, ma.AssembleKey().AssignString("whee"), wish.ShouldBeSameTypeAs, ipld.ErrRepeatedMapKey{})
+		va, err = ma.AssembleEntry("woot")
+		wish.Require(t, err, wish.ShouldEqual, nil)
+		wish.Wish(t, va.AssignInt(2), wish.ShouldEqual, nil)
+		wish.Wish(t, ma.Finish(), wish.ShouldEqual, nil)
+		wish.Wish(t, nb.Build().Length(), wish.ShouldEqual, 2)
+	})
+}
+
+func TestMapAssignNode(t *testing.T) {
+	src := fluent.MustBuildMap(basicnode.Style__Map{}, 2, func(na fluent.MapAssembler) {
+		na.AssembleEntry("whee").AssignInt(1)
+		na.AssembleEntry("woot").AssignInt(2)
+	})
+	t.Run("from another style uses generic copy", func(t *testing.T) {
+		nb := Type__Map_K_T{}.NewBuilder()
+		wish.Wish(t, ipld.SameStyle(src.Style(), nb.Style()), wish.ShouldEqual, false)
+		wish.Require(t, nb.AssignNode(src), wish.ShouldEqual, nil)
+		n := nb.Build()
+		wish.Wish(t, ipld.Sprint(n), wish.ShouldEqual, `{"whee": 1, "woot": 2}`)
+		t.Run("and from the same style uses the shortcut", func(t *testing.T) {
+			nb := Type__Map_K_T{}.NewBuilder()
+			wish.Wish(t, ipld.SameStyle(n.Style(), nb.Style()), wish.ShouldEqual, true)
+			wish.Require(t, ipld.Copy(n, nb), wish.ShouldEqual, nil)
+			n2 := nb.Build()
+			wish.Wish(t, &n2.(*Map_K_T).t[0], wish.ShouldEqual, &n.(*Map_K_T).t[0])
+		})
+	})
+}
+
+func TestMapHasFallback(t *testing.T) {
+	// Map_K_T doesn't implement ipld.NodeSupportingHas, so this goes through Lookup.
+	n := fluent.MustBuildMap(Type__Map_K_T{}, 1, func(na fluent.MapAssembler) {
+		na.AssembleEntry("whee").AssignInt(1)
+	})
+	_, ok := n.(ipld.NodeSupportingHas)
+	wish.Wish(t, ok, wish.ShouldEqual, false)
+	has, err := ipld.Has(n, basicnode.NewString("whee"))
+	wish.Wish(t, err, wish.ShouldEqual, nil)
+	wish.Wish(t, has, wish.ShouldEqual, true)
+	has, err = ipld.Has(n, basicnode.NewString("nope"))
+	wish.Wish(t, err, wish.ShouldEqual, nil)
+	wish.Wish(t, has, wish.ShouldEqual, false)
+	has, err = ipld.Has(n, &K{"whee"})
+	wish.Wish(t, err, wish.ShouldEqual, nil)
+	wish.Wish(t, has, wish.ShouldEqual, true)
+	_, err = ipld.Has(n, basicnode.NewInt(1))
+	wish.Wish(t, err, wish.ShouldBeSameTypeAs, ipld.ErrWrongKind{})
+}
+
+func TestMapKTKindErrors(t *testing.T) {
+	n := fluent.MustBuildMap(Type__Map_K_T{}, 0, func(na fluent.MapAssembler) {})
+	tests.AssertKindErrors(t, n, ipld.ReprKind_Map)
+}
+
+func TestMapKTConformance(t *testing.T) {
+	tests.SpecTestConformance(t, Type__Map_K_T{}, []string{
+		`{"whee": 1, "woot": 2, "waga": 3}`,
+		`{}`,
+	})
+}
@@ -0,0 +1,254 @@
+package gendemo
+
+// S and this file is how a codegen'd struct type with an optional field would work,
+// and how the type-level and representation views of a struct with renamed
+// fields share one table of keys.
+// (There's no assembler here yet; see K2 for how those go.  This is about reading.)
+
+import (
+	ipld "github.com/ipld/go-ipld-prime"
+	"github.com/ipld/go-ipld-prime/node/mixins"
+	"github.com/ipld/go-ipld-prime/schema"
+)
+
+/*	ipldsch:
+	type S struct { a string, b optional string } representation map {
+		field a "A"
+	}
+*/
+
+var (
+	_ ipld.Node                           = &S{}
+	_ schema.TypedNode                    = &S{}
+	_ ipld.Node                           = &_S__Repr{}
+	_ ipld.MapIteratorSupportingUndefined = &_S_MapIterator{}
+)
+
+// Type__S is the schema type of S.
+var Type__S = schema.SpawnStruct("S",
+	[]schema.StructField{
+		schema.SpawnStructField("a", schema.SpawnString("String"), false, false),
+		schema.SpawnStructField("b", schema.SpawnString("String"), true, false),
+	},
+	schema.SpawnStructRepresentationMap(map[string]string{"a": "A"}),
+)
+
+// _S__FieldNames and _S__ReprKeys are the keys of S's fields, in field order,
+// at the type level and in the representation respectively.
+// Both views look fields up (and iterate) by index into these,
+// so a rename can't be applied in one view and missed in the other.
+// (Codegen would emit the representation keys as literals;
+// here they're read from the schema, which is the same source codegen uses.)
+var (
+	_S__FieldNames = [2]string{"a", "b"}
+	_S__ReprKeys   = [2]string{
+		Type__S.RepresentationStrategy().(schema.StructRepresentation_Map).GetFieldKey(Type__S.Fields()[0]),
+		Type__S.RepresentationStrategy().(schema.StructRepresentation_Map).GetFieldKey(Type__S.Fields()[1]),
+	}
+)
+
+// _S__FieldIndex returns the index of the field with the given key in the table, or -1.
+func _S__FieldIndex(keys *[2]string, key string) int {
+	for i, k := range keys {
+		if k == key {
+			return i
+		}
+	}
+	return -1
+}
+
+type S struct {
+	a plainString
+	b plainString
+
+	b__exists bool // absent fields are represented by this flag, so the zero value of S has 'b' absent.
+}
+
+func (S) ReprKind() ipld.ReprKind {
+	return ipld.ReprKind_Map
+}
+func (n *S) LookupString(key string) (ipld.Node, error) {
+	idx := _S__FieldIndex(&_S__FieldNames, key)
+	if idx < 0 {
+		return nil, schema.ErrNoSuchField{Type: Type__S, FieldName: key}
+	}
+	return n.field(idx), nil
+}
+
+// field returns the value of the field at the given index, or Undef if it's absent.
+func (n *S) field(idx int) ipld.Node {
+	switch idx {
+	case 0:
+		return &n.a
+	case 1:
+		if !n.b__exists {
+			return ipld.Undef
+		}
+		return &n.b
+	default:
+		panic("unreachable")
+	}
+}
+func (n *S) Lookup(key ipld.Node) (ipld.Node, error) {
+	ks, err := key.AsString()
+	if err != nil {
+		return nil, err
+	}
+	return n.LookupString(ks)
+}
+func (S) LookupIndex(idx int) (ipld.Node, error) {
+	return mixins.Map{"gendemo.S"}.LookupIndex(0)
+}
+func (n *S) LookupSegment(seg ipld.PathSegment) (ipld.Node, error) {
+	return n.LookupString(seg.String())
+}
+func (n *S) MapIterator() ipld.MapIterator {
+	return &_S_MapIterator{n, &_S__FieldNames, 0, false}
+}
+func (S) ListIterator() ipld.ListIterator {
+	return nil
+}
+func (n *S) Length() int {
+	// Counts only the present fields, same as the default iteration mode.
+	if n.b__exists {
+		return 2
+	}
+	return 1
+}
+func (S) IsUndefined() bool {
+	return false
+}
+func (S) IsNull() bool {
+	return false
+}
+func (S) AsBool() (bool, error) {
+	return mixins.Map{"gendemo.S"}.AsBool()
+}
+func (S) AsInt() (int, error) {
+	return mixins.Map{"gendemo.S"}.AsInt()
+}
+func (S) AsFloat() (float64, error) {
+	return mixins.Map{"gendemo.S"}.AsFloat()
+}
+func (S) AsString() (string, error) {
+	return mixins.Map{"gendemo.S"}.AsString()
+}
+func (S) AsBytes() ([]byte, error) {
+	return mixins.Map{"gendemo.S"}.AsBytes()
+}
+func (S) AsLink() (ipld.Link, error) {
+	return mixins.Map{"gendemo.S"}.AsLink()
+}
+func (S) Style() ipld.NodeStyle {
+	panic("todo")
+}
+func (S) Type() schema.Type {
+	return Type__S
+}
+func (n *S) Representation() ipld.Node {
+	return &_S__Repr{n}
+}
+
+// _S_MapIterator skips absent optional fields, unless asked to yield them as undefined.
+// It yields keys from a table of field keys, so it serves both views of S.
+type _S_MapIterator struct {
+	n          *S
+	keys       *[2]string // _S__FieldNames or _S__ReprKeys.
+	idx        int
+	yieldUndef bool
+}
+
+func (itr *_S_MapIterator) YieldUndefined(yes bool) {
+	itr.yieldUndef = yes
+}
+
+// next returns the index of the next field to yield, having skipped any absent fields if in that mode.
+// It doesn't modify the iterator, so that Done can use it too.
+func (itr *_S_MapIterator) next() int {
+	idx := itr.idx
+	if idx == 1 && !itr.n.b__exists && !itr.yieldUndef {
+		idx++
+	}
+	return idx
+}
+func (itr *_S_MapIterator) Next() (k ipld.Node, v ipld.Node, _ error) {
+	itr.idx = itr.next()
+	if itr.idx >= 2 {
+		return nil, nil, ipld.ErrIteratorOverread{}
+	}
+	k = plainString(itr.keys[itr.idx])
+	v = itr.n.field(itr.idx)
+	itr.idx++
+	return
+}
+func (itr *_S_MapIterator) Done() bool {
+	return itr.next() >= 2
+}
+
+// _S__Repr is the representation view of S: a map whose keys are the
+// representation keys of its fields (see _S__ReprKeys), rather than the field names.
+// Unknown keys are ErrNotExists here (rather than ErrNoSuchField),
+// since the representation isn't a schema.TypedNode.
+type _S__Repr struct {
+	n *S
+}
+
+func (_S__Repr) ReprKind() ipld.ReprKind {
+	return ipld.ReprKind_Map
+}
+func (r *_S__Repr) LookupString(key string) (ipld.Node, error) {
+	idx := _S__FieldIndex(&_S__ReprKeys, key)
+	if idx < 0 {
+		return nil, ipld.ErrNotExists{ipld.PathSegmentOfString(key)}
+	}
+	return r.n.field(idx), nil
+}
+func (r *_S__Repr) Lookup(key ipld.Node) (ipld.Node, error) {
+	ks, err := key.AsString()
+	if err != nil {
+		return nil, err
+	}
+	return r.LookupString(ks)
+}
+func (_S__Repr) LookupIndex(idx int) (ipld.Node, error) {
+	return mixins.Map{"gendemo.S.Repr"}.LookupIndex(0)
+}
+func (r *_S__Repr) LookupSegment(seg ipld.PathSegment) (ipld.Node, error) {
+	return r.LookupString(seg.String())
+}
+func (r *_S__Repr) MapIterator() ipld.MapIterator {
+	return &_S_MapIterator{r.n, &_S__ReprKeys, 0, false}
+}
+func (_S__Repr) ListIterator() ipld.ListIterator {
+	return nil
+}
+func (r *_S__Repr) Length() int {
+	return r.n.Length()
+}
+func (_S__Repr) IsUndefined() bool {
+	return false
+}
+func (_S__Repr) IsNull() bool {
+	return false
+}
+func (_S__Repr) AsBool() (bool, error) {
+	return mixins.Map{"gendemo.S.Repr"}.AsBool()
+}
+func (_S__Repr) AsInt() (int, error) {
+	return mixins.Map{"gendemo.S.Repr"}.AsInt()
+}
+func (_S__Repr) AsFloat() (float64, error) {
+	return mixins.Map{"gendemo.S.Repr"}.AsFloat()
+}
+func (_S__Repr) AsString() (string, error) {
+	return mixins.Map{"gendemo.S.Repr"}.AsString()
+}
+func (_S__Repr) AsBytes() ([]byte, error) {
+	return mixins.Map{"gendemo.S.Repr"}.AsBytes()
+}
+func (_S__Repr) AsLink() (ipld.Link, error) {
+	return mixins.Map{"gendemo.S.Repr"}.AsLink()
+}
+func (_S__Repr) Style() ipld.NodeStyle {
+	panic("todo")
+}
@@ -0,0 +1,105 @@
+package gendemo
+
+import (
+	"testing"
+
+	"github.com/warpfork/go-wish"
+
+	ipld "github.com/ipld/go-ipld-prime"
+	"github.com/ipld/go-ipld-prime/schema"
+)
+
+func TestStructIterationUndefined(t *testing.T) {
+	type entry struct {
+		k     string
+		undef bool
+	}
+	collect := func(t *testing.T, itr ipld.MapIterator) (entries []entry) {
+		for !itr.Done() {
+			k, v, err := itr.Next()
+			wish.Require(t, err, wish.ShouldEqual, nil)
+			ks, _ := k.AsString()
+			entries = append(entries, entry{ks, v.IsUndefined()})
+		}
+		_, _, err := itr.Next()
+		wish.Wish(t, err, wish.ShouldEqual, ipld.ErrIteratorOverread{})
+		return
+	}
+	absent := &S{a: "x"}
+	present := &S{a: "x", b: "y", b__exists: true}
+
+	t.Run("absent field is skipped by default", func(t *testing.T) {
+		wish.Wish(t, collect(t, absent.MapIterator()), wish.ShouldEqual, []entry{{"a", false}})
+		wish.Wish(t, absent.Length(), wish.ShouldEqual, 1)
+		wish.Wish(t, ipld.Sprint(absent), wish.ShouldEqual, `{"a": "x"}`)
+	})
+	t.Run("absent field is yielded as undefined when asked", func(t *testing.T) {
+		itr := absent.MapIterator()
+		itr.(ipld.MapIteratorSupportingUndefined).YieldUndefined(true)
+		wish.Wish(t, collect(t, itr), wish.ShouldEqual, []entry{{"a", false}, {"b", true}})
+	})
+	t.Run("present field is yielded in both modes", func(t *testing.T) {
+		wish.Wish(t, collect(t, present.MapIterator()), wish.ShouldEqual, []entry{{"a", false}, {"b", false}})
+		itr := present.MapIterator()
+		itr.(ipld.MapIteratorSupportingUndefined).YieldUndefined(true)
+		wish.Wish(t, collect(t, itr), wish.ShouldEqual, []entry{{"a", false}, {"b", false}})
+		wish.Wish(t, present.Length(), wish.ShouldEqual, 2)
+	})
+	t.Run("lookup of absent field is undefined", func(t *testing.T) {
+		v, err := absent.LookupString("b")
+		wish.Wish(t, err, wish.ShouldEqual, nil)
+		wish.Wish(t, v.IsUndefined(), wish.ShouldEqual, true)
+	})
+	t.Run("absent field is distinguishable from a wrong kind", func(t *testing.T) {
+		v, _ := absent.LookupString("b")
+		wish.Wish(t, v.Length(), wish.ShouldEqual, -1)
+		_, err := v.AsString()
+		wish.Wish(t, err, wish.ShouldEqual, ipld.ErrUndefinedNode{MethodName: "AsString"})
+		_, err = v.LookupString("x")
+		wish.Wish(t, err, wish.ShouldEqual, ipld.ErrUndefinedNode{MethodName: "LookupString"})
+		// A present field asked for the wrong kind still says so.
+		v, _ = present.LookupString("b")
+		_, err = v.AsInt()
+		wish.Wish(t, err, wish.ShouldBeSameTypeAs, ipld.ErrWrongKind{})
+	})
+}
+
+func TestStructRenamedFieldLookups(t *testing.T) {
+	n := &S{a: "x", b: "y", b__exists: true}
+	repr := n.Representation()
+
+	t.Run("type level uses field names", func(t *testing.T) {
+		v, err := n.LookupString("a")
+		wish.Wish(t, err, wish.ShouldEqual, nil)
+		wish.Wish(t, v, wish.ShouldEqual, &n.a)
+		_, err = n.LookupString("A")
+		wish.Wish(t, err, wish.ShouldEqual, schema.ErrNoSuchField{Type: Type__S, FieldName: "A"})
+		wish.Wish(t, ipld.Sprint(n), wish.ShouldEqual, `{"a": "x", "b": "y"}`)
+	})
+	t.Run("representation uses renamed keys", func(t *testing.T) {
+		v, err := repr.LookupString("A")
+		wish.Wish(t, err, wish.ShouldEqual, nil)
+		wish.Wish(t, v, wish.ShouldEqual, &n.a)
+		_, err = repr.LookupString("a")
+		wish.Wish(t, err, wish.ShouldEqual, ipld.ErrNotExists{ipld.PathSegmentOfString("a")})
+		v, err = repr.LookupString("b") // not renamed, so the same in both views.
+		wish.Wish(t, err, wish.ShouldEqual, nil)
+		wish.Wish(t, v, wish.ShouldEqual, &n.b)
+		wish.Wish(t, ipld.Sprint(repr), wish.ShouldEqual, `{"A": "x", "b": "y"}`)
+	})
+	t.Run("every key either view iterates can be looked up in that view", func(t *testing.T) {
+		for _, view := range []ipld.Node{n, repr} {
+			for itr := view.MapIterator(); !itr.Done(); {
+				k, v, err := itr.Next()
+				wish.Require(t, err, wish.ShouldEqual, nil)
+				ks, _ := k.AsString()
+				v2, err := view.LookupString(ks)
+				wish.Wish(t, err, wish.ShouldEqual, nil)
+				wish.Wish(t, v2, wish.ShouldEqual, v)
+			}
+		}
+	})
+	t.Run("the representation matches the schema", func(t *testing.T) {
+		wish.Wish(t, schema.Validate(Type__S, repr), wish.ShouldEqual, nil)
+	})
+}
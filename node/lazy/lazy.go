@@ -0,0 +1,184 @@
+/*
+	The lazynode package provides a Node which holds serial data,
+	and only decodes it when it's first looked at.
+
+	This is useful when a large document is made up of regions
+	(or links to blocks) of which only some will ever be read:
+	for example, when a selector will only explore part of the document.
+	Each region can be a lazy node, and the ones the selector never
+	descends into are never decoded.
+
+	Any method call on a lazy node (except Style) decodes it, once;
+	the result is kept, and all method calls are answered by it.
+	Lazy nodes are safe to use concurrently, like other nodes.
+*/
+package lazynode
+
+import (
+	"bytes"
+	"io"
+	"sync"
+
+	ipld "github.com/ipld/go-ipld-prime"
+)
+
+var (
+	_ ipld.Node = &lazyNode{}
+)
+
+// New returns a Node which will decode data, using decode, into a builder from ns,
+// the first time any of its methods are called.
+//
+// decode has the shape of the Decoder functions in the codec packages
+// (e.g. dagcbor.Decoder, or one from dagjson.DecoderWithOptions).
+// The data slice is retained, and must not be changed afterwards.
+//
+// If decoding fails, the error is returned from every method which can return an error;
+// the rest report a node of ReprKind_Invalid, with a Length of -1 and nil iterators.
+func New(ns ipld.NodeStyle, decode func(ipld.NodeAssembler, io.Reader) error, data []byte) ipld.Node {
+	return &lazyNode{ns: ns, decode: decode, data: data}
+}
+
+type lazyNode struct {
+	ns     ipld.NodeStyle
+	decode func(ipld.NodeAssembler, io.Reader) error
+	data   []byte // dropped once decoded.
+
+	once sync.Once
+	n    ipld.Node // set by load, if decoding succeeded.
+	err  error     // set by load, if decoding failed.
+}
+
+// load decodes the node, if that hasn't been done yet,
+// and returns the result.
+func (n *lazyNode) load() (ipld.Node, error) {
+	n.once.Do(func() {
+		nb := n.ns.NewBuilder()
+		if err := n.decode(nb, bytes.NewReader(n.data)); err != nil {
+			n.err = err
+		} else {
+			n.n = nb.Build()
+		}
+		n.data = nil
+	})
+	return n.n, n.err
+}
+
+func (n *lazyNode) ReprKind() ipld.ReprKind {
+	v, err := n.load()
+	if err != nil {
+		return ipld.ReprKind_Invalid
+	}
+	return v.ReprKind()
+}
+func (n *lazyNode) LookupString(key string) (ipld.Node, error) {
+	v, err := n.load()
+	if err != nil {
+		return nil, err
+	}
+	return v.LookupString(key)
+}
+func (n *lazyNode) Lookup(key ipld.Node) (ipld.Node, error) {
+	v, err := n.load()
+	if err != nil {
+		return nil, err
+	}
+	return v.Lookup(key)
+}
+func (n *lazyNode) LookupIndex(idx int) (ipld.Node, error) {
+	v, err := n.load()
+	if err != nil {
+		return nil, err
+	}
+	return v.LookupIndex(idx)
+}
+func (n *lazyNode) LookupSegment(seg ipld.PathSegment) (ipld.Node, error) {
+	v, err := n.load()
+	if err != nil {
+		return nil, err
+	}
+	return v.LookupSegment(seg)
+}
+func (n *lazyNode) MapIterator() ipld.MapIterator {
+	v, err := n.load()
+	if err != nil {
+		return nil
+	}
+	return v.MapIterator()
+}
+func (n *lazyNode) ListIterator() ipld.ListIterator {
+	v, err := n.load()
+	if err != nil {
+		return nil
+	}
+	return v.ListIterator()
+}
+func (n *lazyNode) Length() int {
+	v, err := n.load()
+	if err != nil {
+		return -1
+	}
+	return v.Length()
+}
+func (n *lazyNode) IsUndefined() bool {
+	v, err := n.load()
+	if err != nil {
+		return false
+	}
+	return v.IsUndefined()
+}
+func (n *lazyNode) IsNull() bool {
+	v, err := n.load()
+	if err != nil {
+		return false
+	}
+	return v.IsNull()
+}
+func (n *lazyNode) AsBool() (bool, error) {
+	v, err := n.load()
+	if err != nil {
+		return false, err
+	}
+	return v.AsBool()
+}
+func (n *lazyNode) AsInt() (int, error) {
+	v, err := n.load()
+	if err != nil {
+		return 0, err
+	}
+	return v.AsInt()
+}
+func (n *lazyNode) AsFloat() (float64, error) {
+	v, err := n.load()
+	if err != nil {
+		return 0, err
+	}
+	return v.AsFloat()
+}
+func (n *lazyNode) AsString() (string, error) {
+	v, err := n.load()
+	if err != nil {
+		return "", err
+	}
+	return v.AsString()
+}
+func (n *lazyNode) AsBytes() ([]byte, error) {
+	v, err := n.load()
+	if err != nil {
+		return nil, err
+	}
+	return v.AsBytes()
+}
+func (n *lazyNode) AsLink() (ipld.Link, error) {
+	v, err := n.load()
+	if err != nil {
+		return nil, err
+	}
+	return v.AsLink()
+}
+
+// Style returns the NodeStyle the node is decoded with.
+// It's the one method which doesn't cause decoding.
+func (n *lazyNode) Style() ipld.NodeStyle {
+	return n.ns
+}
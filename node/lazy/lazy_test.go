@@ -0,0 +1,117 @@
+package lazynode
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+
+	. "github.com/warpfork/go-wish"
+
+	ipld "github.com/ipld/go-ipld-prime"
+	"github.com/ipld/go-ipld-prime/codec/dagjson"
+	"github.com/ipld/go-ipld-prime/fluent"
+	basicnode "github.com/ipld/go-ipld-prime/node/basic"
+	"github.com/ipld/go-ipld-prime/traversal"
+	"github.com/ipld/go-ipld-prime/traversal/selector/builder"
+)
+
+// countingDecoder is dagjson.Decoder, counting how many times it's called.
+type countingDecoder struct {
+	mu    sync.Mutex
+	count int
+}
+
+func (d *countingDecoder) decode(na ipld.NodeAssembler, r io.Reader) error {
+	d.mu.Lock()
+	d.count++
+	d.mu.Unlock()
+	return dagjson.Decoder(na, r)
+}
+
+func TestLazy(t *testing.T) {
+	t.Run("decodes once, on first use", func(t *testing.T) {
+		var d countingDecoder
+		n := New(basicnode.Style__Any{}, d.decode, []byte(`{"a": [1, 2], "b": "x"}`))
+		Wish(t, d.count, ShouldEqual, 0)
+		Wish(t, n.Style(), ShouldEqual, basicnode.Style__Any{})
+		Wish(t, d.count, ShouldEqual, 0)
+		Wish(t, n.ReprKind(), ShouldEqual, ipld.ReprKind_Map)
+		Wish(t, d.count, ShouldEqual, 1)
+		Wish(t, n.Length(), ShouldEqual, 2)
+		Wish(t, ipld.Sprint(n), ShouldEqual, `{"a": [1, 2], "b": "x"}`)
+		Wish(t, d.count, ShouldEqual, 1)
+	})
+	t.Run("decodes once under concurrent use", func(t *testing.T) {
+		var d countingDecoder
+		n := New(basicnode.Style__Any{}, d.decode, []byte(`"x"`))
+		var wg sync.WaitGroup
+		for i := 0; i < 8; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				n.AsString()
+			}()
+		}
+		wg.Wait()
+		Wish(t, d.count, ShouldEqual, 1)
+		s, err := n.AsString()
+		Wish(t, err, ShouldEqual, nil)
+		Wish(t, s, ShouldEqual, "x")
+	})
+	t.Run("decode errors are kept", func(t *testing.T) {
+		var d countingDecoder
+		n := New(basicnode.Style__Any{}, d.decode, []byte(`{"a": `))
+		_, err := n.LookupString("a")
+		if err == nil {
+			t.Fatalf("expected a decode error")
+		}
+		_, err2 := n.AsInt()
+		Wish(t, err2, ShouldEqual, err)
+		Wish(t, n.ReprKind(), ShouldEqual, ipld.ReprKind_Invalid)
+		Wish(t, n.Length(), ShouldEqual, -1)
+		Wish(t, d.count, ShouldEqual, 1)
+	})
+}
+
+func TestLazyWithSelector(t *testing.T) {
+	// A document made of lazily decoded regions, some of them holding links
+	// (which are never loaded here: only the regions' own decoding is counted).
+	decoders := map[string]*countingDecoder{}
+	region := func(name, json string) ipld.Node {
+		decoders[name] = &countingDecoder{}
+		return New(basicnode.Style__Any{}, decoders[name].decode, []byte(json))
+	}
+	n := fluent.MustBuildMap(basicnode.Style__Map{}, 3, func(na fluent.MapAssembler) {
+		na.AssembleEntry("visited").AssignNode(region("visited", `{"x": 1, "y": [2, 3]}`))
+		na.AssembleEntry("unvisited").AssignNode(region("unvisited", `{"x": 4, "big": [5, 6, 7, 8]}`))
+		na.AssembleEntry("list").CreateList(2, func(na fluent.ListAssembler) {
+			na.AssembleValue().AssignNode(region("list/0", `{"/": "bafyreib7vhfhgkwqjn6e5tyzmojqkbcwrhyf3gvlqn4duuoilvf6kfnpa4"}`))
+			na.AssembleValue().AssignNode(region("list/1", `"only this one"`))
+		})
+	})
+
+	ssb := builder.NewSelectorSpecBuilder(basicnode.Style__Any{})
+	s, err := ssb.ExploreFields(func(efsb builder.ExploreFieldsSpecBuilder) {
+		efsb.Insert("visited", ssb.ExploreAll(ssb.Matcher()))
+		efsb.Insert("list", ssb.ExploreIndex(1, ssb.Matcher()))
+	}).Selector()
+	Require(t, err, ShouldEqual, nil)
+
+	var visited []string
+	err = traversal.WalkMatching(n, s, func(prog traversal.Progress, n ipld.Node) error {
+		visited = append(visited, fmt.Sprintf("%s: %s", prog.Path, ipld.Sprint(n)))
+		return nil
+	})
+	Wish(t, err, ShouldEqual, nil)
+	Wish(t, strings.Join(visited, "\n"), ShouldEqual, strings.Join([]string{
+		`visited/x: 1`,
+		`visited/y: [2, 3]`,
+		`list/1: "only this one"`,
+	}, "\n"))
+	Wish(t, decoders["visited"].count, ShouldEqual, 1)
+	Wish(t, decoders["list/1"].count, ShouldEqual, 1)
+	Wish(t, decoders["unvisited"].count, ShouldEqual, 0)
+	Wish(t, decoders["list/0"].count, ShouldEqual, 0)
+}
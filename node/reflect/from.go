@@ -0,0 +1,110 @@
+package reflectnode
+
+import (
+	"fmt"
+
+	ipld "github.com/ipld/go-ipld-prime"
+	basicnode "github.com/ipld/go-ipld-prime/node/basic"
+)
+
+// From builds a node from a golang value, by the same rules as Wrap
+// (see the package docs); but rather than a view of the value,
+// the result is a basicnode tree holding a copy of all of its content.
+// It's slower than Wrap, but the value may be changed (or reused)
+// afterwards without affecting the node, and the node is as fast to read
+// as any other basicnode.
+//
+// Unlike Wrap, all errors (e.g. an unsupported type deep in the value)
+// are returned from From, since the whole value is read up front.
+func From(v interface{}) (ipld.Node, error) {
+	n, err := Wrap(v)
+	if err != nil {
+		return nil, err
+	}
+	nb := basicnode.Style__Any{}.NewBuilder()
+	if err := deepCopy(n, nb); err != nil {
+		return nil, err
+	}
+	return nb.Build(), nil
+}
+
+// deepCopy is like ipld.Copy, but it recurses itself instead of
+// handing children to AssignNode, so nothing from the wrapped value
+// is retained by the new node (basicnode's AssignNode keeps the node it's given).
+func deepCopy(n ipld.Node, na ipld.NodeAssembler) error {
+	switch n.ReprKind() {
+	case ipld.ReprKind_Map:
+		ma, err := na.BeginMap(n.Length())
+		if err != nil {
+			return err
+		}
+		for itr := n.MapIterator(); !itr.Done(); {
+			k, v, err := itr.Next()
+			if err != nil {
+				return err
+			}
+			if err := deepCopy(k, ma.AssembleKey()); err != nil {
+				return err
+			}
+			if err := deepCopy(v, ma.AssembleValue()); err != nil {
+				return err
+			}
+		}
+		return ma.Finish()
+	case ipld.ReprKind_List:
+		la, err := na.BeginList(n.Length())
+		if err != nil {
+			return err
+		}
+		for itr := n.ListIterator(); !itr.Done(); {
+			_, v, err := itr.Next()
+			if err != nil {
+				return err
+			}
+			if err := deepCopy(v, la.AssembleValue()); err != nil {
+				return err
+			}
+		}
+		return la.Finish()
+	case ipld.ReprKind_Bytes:
+		v, err := n.AsBytes()
+		if err != nil {
+			return err
+		}
+		return na.AssignBytes(append([]byte(nil), v...))
+	case ipld.ReprKind_Null:
+		return na.AssignNull()
+	case ipld.ReprKind_Bool:
+		v, err := n.AsBool()
+		if err != nil {
+			return err
+		}
+		return na.AssignBool(v)
+	case ipld.ReprKind_Int:
+		v, err := n.AsInt()
+		if err != nil {
+			return err
+		}
+		return na.AssignInt(v)
+	case ipld.ReprKind_Float:
+		v, err := n.AsFloat()
+		if err != nil {
+			return err
+		}
+		return na.AssignFloat(v)
+	case ipld.ReprKind_String:
+		v, err := n.AsString()
+		if err != nil {
+			return err
+		}
+		return na.AssignString(v)
+	case ipld.ReprKind_Link:
+		v, err := n.AsLink()
+		if err != nil {
+			return err
+		}
+		return na.AssignLink(v)
+	default:
+		return fmt.Errorf("reflectnode: cannot copy an undefined node")
+	}
+}
@@ -0,0 +1,54 @@
+package reflectnode
+
+import (
+	"reflect"
+	"testing"
+
+	. "github.com/warpfork/go-wish"
+
+	ipld "github.com/ipld/go-ipld-prime"
+)
+
+func TestFrom(t *testing.T) {
+	v := sample{
+		Name:  "alpha",
+		Count: 3,
+		Ratio: 0.5,
+		Tags:  []string{"x", "y"},
+		Inner: sampleInner{true, []byte{0x01}},
+		Ptr:   &sampleInner{false, []byte{0x02}},
+		Extra: map[string]int{"b": 2, "a": 1},
+	}
+	n, err := From(&v)
+	Wish(t, err, ShouldEqual, nil)
+	Wish(t, ipld.Sprint(n), ShouldEqual, `{"Name": "alpha", "n": 3, "Ratio": 0.5, "Tags": ["x", "y"], "Inner": {"Flag": true, "Blob": bytes(01)}, "Ptr": {"Flag": false, "Blob": bytes(02)}, "Extra": {"a": 1, "b": 2}}`)
+
+	t.Run("round trips through Into", func(t *testing.T) {
+		var v2 sample
+		Wish(t, Into(n, &v2), ShouldEqual, nil)
+		Wish(t, v2, ShouldEqual, v)
+	})
+	t.Run("is detached from the value", func(t *testing.T) {
+		before := ipld.Sprint(n)
+		v.Name = "beta"
+		v.Tags[0] = "z"
+		v.Inner.Blob[0] = 0xff
+		v.Ptr.Flag = true
+		v.Extra["c"] = 3
+		Wish(t, ipld.Sprint(n), ShouldEqual, before)
+	})
+	t.Run("omitempty", func(t *testing.T) {
+		type opt struct {
+			A int    `ipld:",omitempty"`
+			B string `ipld:"b,omitempty"`
+			C *int   `ipld:",optional"`
+		}
+		n, err := From(opt{B: "x"})
+		Wish(t, err, ShouldEqual, nil)
+		Wish(t, ipld.Sprint(n), ShouldEqual, `{"b": "x"}`)
+	})
+	t.Run("unsupported types are reported", func(t *testing.T) {
+		_, err := From(struct{ C chan int }{})
+		Wish(t, err.Error(), ShouldEqual, ErrUnsupportedType{reflect.TypeOf(make(chan int))}.Error())
+	})
+}
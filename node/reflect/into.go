@@ -0,0 +1,227 @@
+package reflectnode
+
+import (
+	"fmt"
+	"reflect"
+
+	ipld "github.com/ipld/go-ipld-prime"
+)
+
+// Into sets the golang value which ptr points to from the content of a node.
+// It's the reverse of Wrap, and follows the same rules (see the package docs):
+// for example, a map node is decoded into a struct field by field,
+// using the field's "ipld" tag for its key.
+//
+// Struct fields which are omitempty or optional may be missing from the map,
+// and are then left as they were; any other missing field is an error.
+// Map entries which don't match any field are ignored.
+// Nulls decode into pointers, interfaces, maps, and slices as nil.
+// Pointers are allocated as needed; an ipld.Node (or an interface{}) gets
+// the node itself; and an ipld.Link gets the node's link.
+//
+// Errors about the content of the node are ErrDecode values,
+// which carry the path within the node where the problem was found.
+func Into(n ipld.Node, ptr interface{}) error {
+	rv := reflect.ValueOf(ptr)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return fmt.Errorf("reflectnode: Into requires a non-nil pointer, not %T", ptr)
+	}
+	return into(ipld.Path{}, n, rv.Elem())
+}
+
+// ErrDecode is returned by Into when a node's content doesn't fit the golang type
+// it's being decoded into (e.g. the kinds don't match, or a field is missing).
+type ErrDecode struct {
+	Path   ipld.Path    // where, within the node given to Into, the problem is.
+	Type   reflect.Type // the golang type the node at Path was to be decoded into.
+	Reason string
+}
+
+func (e ErrDecode) Error() string {
+	return fmt.Sprintf("reflectnode: cannot decode into %s at %q: %s", e.Type, e.Path.String(), e.Reason)
+}
+
+func into(p ipld.Path, n ipld.Node, rv reflect.Value) error {
+	t := rv.Type()
+	if n.IsUndefined() {
+		return ErrDecode{p, t, "node is undefined"}
+	}
+	if n.IsNull() {
+		switch t.Kind() {
+		case reflect.Ptr, reflect.Interface, reflect.Map, reflect.Slice:
+			rv.Set(reflect.Zero(t))
+			return nil
+		}
+	}
+	switch {
+	case t.Kind() == reflect.Interface && typeOfNode.Implements(t):
+		rv.Set(reflect.ValueOf(n))
+		return nil
+	case t.Kind() == reflect.Ptr && !t.Implements(typeOfLink):
+		if rv.IsNil() {
+			rv.Set(reflect.New(t.Elem()))
+		}
+		return into(p, n, rv.Elem())
+	case t.Implements(typeOfLink):
+		if err := expectKind(p, t, n, ipld.ReprKind_Link); err != nil {
+			return err
+		}
+		lnk, err := n.AsLink()
+		if err != nil {
+			return err
+		}
+		if !reflect.TypeOf(lnk).AssignableTo(t) {
+			return ErrDecode{p, t, fmt.Sprintf("link is a %T", lnk)}
+		}
+		rv.Set(reflect.ValueOf(lnk))
+		return nil
+	case t.Kind() == reflect.Slice && t.Elem().Kind() == reflect.Uint8:
+		if err := expectKind(p, t, n, ipld.ReprKind_Bytes); err != nil {
+			return err
+		}
+		v, err := n.AsBytes()
+		if err != nil {
+			return err
+		}
+		rv.Set(reflect.ValueOf(v).Convert(t))
+		return nil
+	}
+	switch t.Kind() {
+	case reflect.Struct:
+		if err := expectKind(p, t, n, ipld.ReprKind_Map); err != nil {
+			return err
+		}
+		fields, err := structFields(t)
+		if err != nil {
+			return err
+		}
+		for _, f := range fields {
+			v, err := n.LookupString(f.name)
+			if _, ok := err.(ipld.ErrNotExists); ok {
+				if f.omitempty || f.optional {
+					continue
+				}
+				return ErrDecode{p, t, fmt.Sprintf("missing required field %q", f.name)}
+			}
+			if err != nil {
+				return err
+			}
+			if err := into(p.AppendSegmentString(f.name), v, rv.Field(f.index)); err != nil {
+				return err
+			}
+		}
+		return nil
+	case reflect.Map:
+		if t.Key().Kind() != reflect.String {
+			return ErrUnsupportedType{t}
+		}
+		if err := expectKind(p, t, n, ipld.ReprKind_Map); err != nil {
+			return err
+		}
+		m := reflect.MakeMapWithSize(t, n.Length())
+		for itr := n.MapIterator(); !itr.Done(); {
+			k, v, err := itr.Next()
+			if err != nil {
+				return err
+			}
+			ks, err := k.AsString()
+			if err != nil {
+				return err
+			}
+			ev := reflect.New(t.Elem()).Elem()
+			if err := into(p.AppendSegmentString(ks), v, ev); err != nil {
+				return err
+			}
+			m.SetMapIndex(reflect.ValueOf(ks).Convert(t.Key()), ev)
+		}
+		rv.Set(m)
+		return nil
+	case reflect.Slice, reflect.Array:
+		if err := expectKind(p, t, n, ipld.ReprKind_List); err != nil {
+			return err
+		}
+		l := n.Length()
+		if t.Kind() == reflect.Array {
+			if l != t.Len() {
+				return ErrDecode{p, t, fmt.Sprintf("expected a list of length %d, got %d", t.Len(), l)}
+			}
+		} else {
+			rv.Set(reflect.MakeSlice(t, l, l))
+		}
+		for itr := n.ListIterator(); !itr.Done(); {
+			i, v, err := itr.Next()
+			if err != nil {
+				return err
+			}
+			if err := into(p.AppendSegment(ipld.PathSegmentOfInt(i)), v, rv.Index(i)); err != nil {
+				return err
+			}
+		}
+		return nil
+	case reflect.Bool:
+		if err := expectKind(p, t, n, ipld.ReprKind_Bool); err != nil {
+			return err
+		}
+		v, err := n.AsBool()
+		if err != nil {
+			return err
+		}
+		rv.SetBool(v)
+		return nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if err := expectKind(p, t, n, ipld.ReprKind_Int); err != nil {
+			return err
+		}
+		v, err := n.AsInt()
+		if err != nil {
+			return err
+		}
+		if rv.OverflowInt(int64(v)) {
+			return ErrDecode{p, t, fmt.Sprintf("value %d overflows %s", v, t)}
+		}
+		rv.SetInt(int64(v))
+		return nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		if err := expectKind(p, t, n, ipld.ReprKind_Int); err != nil {
+			return err
+		}
+		v, err := n.AsInt()
+		if err != nil {
+			return err
+		}
+		if v < 0 || rv.OverflowUint(uint64(v)) {
+			return ErrDecode{p, t, fmt.Sprintf("value %d overflows %s", v, t)}
+		}
+		rv.SetUint(uint64(v))
+		return nil
+	case reflect.Float32, reflect.Float64:
+		if err := expectKind(p, t, n, ipld.ReprKind_Float); err != nil {
+			return err
+		}
+		v, err := n.AsFloat()
+		if err != nil {
+			return err
+		}
+		rv.SetFloat(v)
+		return nil
+	case reflect.String:
+		if err := expectKind(p, t, n, ipld.ReprKind_String); err != nil {
+			return err
+		}
+		v, err := n.AsString()
+		if err != nil {
+			return err
+		}
+		rv.SetString(v)
+		return nil
+	default:
+		return ErrUnsupportedType{t}
+	}
+}
+
+func expectKind(p ipld.Path, t reflect.Type, n ipld.Node, kind ipld.ReprKind) error {
+	if n.ReprKind() != kind {
+		return ErrDecode{p, t, fmt.Sprintf("expected %s, got %s", kind, n.ReprKind())}
+	}
+	return nil
+}
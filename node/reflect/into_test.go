@@ -0,0 +1,124 @@
+package reflectnode
+
+import (
+	"testing"
+
+	. "github.com/warpfork/go-wish"
+
+	ipld "github.com/ipld/go-ipld-prime"
+	"github.com/ipld/go-ipld-prime/fluent"
+	basicnode "github.com/ipld/go-ipld-prime/node/basic"
+)
+
+func TestInto(t *testing.T) {
+	t.Run("nested struct", func(t *testing.T) {
+		n := fluent.MustBuildMap(basicnode.Style__Map{}, 8, func(na fluent.MapAssembler) {
+			na.AssembleEntry("Name").AssignString("alpha")
+			na.AssembleEntry("n").AssignInt(3)
+			na.AssembleEntry("Ratio").AssignFloat(0.5)
+			na.AssembleEntry("Tags").CreateList(2, func(na fluent.ListAssembler) {
+				na.AssembleValue().AssignString("x")
+				na.AssembleValue().AssignString("y")
+			})
+			na.AssembleEntry("Inner").CreateMap(2, func(na fluent.MapAssembler) {
+				na.AssembleEntry("Flag").AssignBool(true)
+				na.AssembleEntry("Blob").AssignBytes([]byte{0x01})
+			})
+			na.AssembleEntry("Ptr").CreateMap(2, func(na fluent.MapAssembler) {
+				na.AssembleEntry("Flag").AssignBool(false)
+				na.AssembleEntry("Blob").AssignNull()
+			})
+			na.AssembleEntry("Extra").CreateMap(2, func(na fluent.MapAssembler) {
+				na.AssembleEntry("a").AssignInt(1)
+				na.AssembleEntry("b").AssignInt(2)
+			})
+			na.AssembleEntry("unknown").AssignString("ignored")
+		})
+		var v sample
+		Wish(t, Into(n, &v), ShouldEqual, nil)
+		Wish(t, v, ShouldEqual, sample{
+			Name:  "alpha",
+			Count: 3,
+			Ratio: 0.5,
+			Tags:  []string{"x", "y"},
+			Inner: sampleInner{true, []byte{0x01}},
+			Ptr:   &sampleInner{false, nil},
+			Extra: map[string]int{"a": 1, "b": 2},
+		})
+
+		t.Run("round trips through Wrap", func(t *testing.T) {
+			n2, err := Wrap(&v)
+			Wish(t, err, ShouldEqual, nil)
+			var v2 sample
+			Wish(t, Into(n2, &v2), ShouldEqual, nil)
+			Wish(t, v2, ShouldEqual, v)
+		})
+	})
+	t.Run("node and interface fields get the node", func(t *testing.T) {
+		var v struct {
+			N ipld.Node
+			I interface{}
+		}
+		n := fluent.MustBuildMap(basicnode.Style__Map{}, 2, func(na fluent.MapAssembler) {
+			na.AssembleEntry("N").AssignInt(1)
+			na.AssembleEntry("I").AssignString("x")
+		})
+		Wish(t, Into(n, &v), ShouldEqual, nil)
+		Wish(t, v.N, ShouldEqual, basicnode.NewInt(1))
+		Wish(t, v.I, ShouldEqual, basicnode.NewString("x"))
+	})
+	t.Run("missing required field", func(t *testing.T) {
+		n := fluent.MustBuildMap(basicnode.Style__Map{}, 1, func(na fluent.MapAssembler) {
+			na.AssembleEntry("Inner").CreateMap(1, func(na fluent.MapAssembler) {
+				na.AssembleEntry("Flag").AssignBool(true)
+			})
+		})
+		var v struct {
+			Inner sampleInner
+		}
+		err := Into(n, &v)
+		Wish(t, err.Error(), ShouldEqual, `reflectnode: cannot decode into reflectnode.sampleInner at "Inner": missing required field "Blob"`)
+	})
+	t.Run("optional fields may be missing", func(t *testing.T) {
+		var v struct {
+			A int    `ipld:",omitempty"`
+			B *int   `ipld:",optional"`
+			C string `ipld:"c"`
+		}
+		n := fluent.MustBuildMap(basicnode.Style__Map{}, 1, func(na fluent.MapAssembler) {
+			na.AssembleEntry("c").AssignString("x")
+		})
+		Wish(t, Into(n, &v), ShouldEqual, nil)
+		Wish(t, v.C, ShouldEqual, "x")
+	})
+	t.Run("kind mismatch deep inside", func(t *testing.T) {
+		n := fluent.MustBuildMap(basicnode.Style__Map{}, 1, func(na fluent.MapAssembler) {
+			na.AssembleEntry("Rows").CreateList(2, func(na fluent.ListAssembler) {
+				na.AssembleValue().CreateMap(1, func(na fluent.MapAssembler) {
+					na.AssembleEntry("X").AssignInt(1)
+				})
+				na.AssembleValue().CreateMap(1, func(na fluent.MapAssembler) {
+					na.AssembleEntry("X").AssignString("two")
+				})
+			})
+		})
+		var v struct {
+			Rows []struct{ X int }
+		}
+		err := Into(n, &v)
+		Wish(t, err, ShouldBeSameTypeAs, ErrDecode{})
+		Wish(t, err.(ErrDecode).Path.String(), ShouldEqual, "Rows/1/X")
+		Wish(t, err.Error(), ShouldEqual, `reflectnode: cannot decode into int at "Rows/1/X": expected Int, got String`)
+	})
+	t.Run("overflow", func(t *testing.T) {
+		var v uint8
+		err := Into(basicnode.NewInt(300), &v)
+		Wish(t, err.Error(), ShouldEqual, `reflectnode: cannot decode into uint8 at "": value 300 overflows uint8`)
+		err = Into(basicnode.NewInt(-1), &v)
+		Wish(t, err.Error(), ShouldEqual, `reflectnode: cannot decode into uint8 at "": value -1 overflows uint8`)
+	})
+	t.Run("non-pointer", func(t *testing.T) {
+		var v int
+		Wish(t, Into(basicnode.NewInt(1), v).Error(), ShouldEqual, "reflectnode: Into requires a non-nil pointer, not int")
+	})
+}
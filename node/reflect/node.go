@@ -0,0 +1,256 @@
+package reflectnode
+
+import (
+	"reflect"
+	"strconv"
+
+	ipld "github.com/ipld/go-ipld-prime"
+	basicnode "github.com/ipld/go-ipld-prime/node/basic"
+)
+
+var (
+	_ ipld.Node         = &node{}
+	_ ipld.MapIterator  = &structIterator{}
+	_ ipld.MapIterator  = &mapIterator{}
+	_ ipld.ListIterator = &listIterator{}
+)
+
+// node is any wrapped golang value.
+// The kind is decided once, by wrap; all the methods switch on it.
+type node struct {
+	rv   reflect.Value
+	kind ipld.ReprKind
+}
+
+func (n *node) wrongKind(methodName string, appropriateKind ipld.ReprKindSet) error {
+	return ipld.ErrWrongKind{TypeName: n.rv.Type().String(), MethodName: methodName, AppropriateKind: appropriateKind, ActualKind: n.kind}
+}
+
+// -- Node interface methods -->
+
+func (n *node) ReprKind() ipld.ReprKind {
+	return n.kind
+}
+func (n *node) LookupString(key string) (ipld.Node, error) {
+	if n.kind != ipld.ReprKind_Map {
+		return nil, n.wrongKind("LookupString", ipld.ReprKindSet_JustMap)
+	}
+	if n.rv.Kind() == reflect.Struct {
+		fields, _ := structFields(n.rv.Type()) // already checked by wrap.
+		for _, f := range fields {
+			if f.name == key && !f.absent(n.rv) {
+				return wrap(n.rv.Field(f.index))
+			}
+		}
+		return nil, ipld.ErrNotExists{ipld.PathSegmentOfString(key)}
+	}
+	v := n.rv.MapIndex(reflect.ValueOf(key).Convert(n.rv.Type().Key()))
+	if !v.IsValid() {
+		return nil, ipld.ErrNotExists{ipld.PathSegmentOfString(key)}
+	}
+	return wrap(v)
+}
+func (n *node) Lookup(key ipld.Node) (ipld.Node, error) {
+	if n.kind != ipld.ReprKind_Map {
+		return nil, n.wrongKind("Lookup", ipld.ReprKindSet_JustMap)
+	}
+	ks, err := key.AsString()
+	if err != nil {
+		return nil, err
+	}
+	return n.LookupString(ks)
+}
+func (n *node) LookupIndex(idx int) (ipld.Node, error) {
+	if n.kind != ipld.ReprKind_List {
+		return nil, n.wrongKind("LookupIndex", ipld.ReprKindSet_JustList)
+	}
+	if idx < 0 || idx >= n.rv.Len() {
+		return nil, ipld.ErrNotExists{ipld.PathSegmentOfInt(idx)}
+	}
+	return wrap(n.rv.Index(idx))
+}
+func (n *node) LookupSegment(seg ipld.PathSegment) (ipld.Node, error) {
+	switch n.kind {
+	case ipld.ReprKind_Map:
+		return n.LookupString(seg.String())
+	case ipld.ReprKind_List:
+		idx, err := seg.Index()
+		if err != nil {
+			return nil, ipld.ErrNotExists{seg} // a segment that isn't a number can't be in a list.
+		}
+		return n.LookupIndex(idx)
+	default:
+		return nil, n.wrongKind("LookupSegment", ipld.ReprKindSet_Recursive)
+	}
+}
+func (n *node) MapIterator() ipld.MapIterator {
+	if n.kind != ipld.ReprKind_Map {
+		return nil
+	}
+	if n.rv.Kind() == reflect.Struct {
+		fields, _ := structFields(n.rv.Type())
+		return &structIterator{n.rv, fields, 0}
+	}
+	return &mapIterator{n.rv, sortedMapKeys(n.rv), 0}
+}
+func (n *node) ListIterator() ipld.ListIterator {
+	if n.kind != ipld.ReprKind_List {
+		return nil
+	}
+	return &listIterator{n.rv, 0}
+}
+func (n *node) Length() int {
+	switch n.kind {
+	case ipld.ReprKind_Map:
+		if n.rv.Kind() == reflect.Struct {
+			fields, _ := structFields(n.rv.Type())
+			l := 0
+			for _, f := range fields {
+				if !f.absent(n.rv) {
+					l++
+				}
+			}
+			return l
+		}
+		return n.rv.Len()
+	case ipld.ReprKind_List:
+		return n.rv.Len()
+	default:
+		return -1
+	}
+}
+func (n *node) IsUndefined() bool {
+	return false
+}
+func (n *node) IsNull() bool {
+	return false
+}
+func (n *node) AsBool() (bool, error) {
+	if n.kind != ipld.ReprKind_Bool {
+		return false, n.wrongKind("AsBool", ipld.ReprKindSet_JustBool)
+	}
+	return n.rv.Bool(), nil
+}
+func (n *node) AsInt() (int, error) {
+	if n.kind != ipld.ReprKind_Int {
+		return 0, n.wrongKind("AsInt", ipld.ReprKindSet_JustInt)
+	}
+	switch n.rv.Kind() {
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		v := n.rv.Uint()
+		if v > uint64(maxInt) {
+			return 0, ipld.ErrIntegerOverflow{Value: strconv.FormatUint(v, 10)}
+		}
+		return int(v), nil
+	default:
+		v := n.rv.Int()
+		if v > int64(maxInt) || v < int64(minInt) {
+			return 0, ipld.ErrIntegerOverflow{Value: strconv.FormatInt(v, 10)}
+		}
+		return int(v), nil
+	}
+}
+func (n *node) AsFloat() (float64, error) {
+	if n.kind != ipld.ReprKind_Float {
+		return 0, n.wrongKind("AsFloat", ipld.ReprKindSet_JustFloat)
+	}
+	return n.rv.Float(), nil
+}
+func (n *node) AsString() (string, error) {
+	if n.kind != ipld.ReprKind_String {
+		return "", n.wrongKind("AsString", ipld.ReprKindSet_JustString)
+	}
+	return n.rv.String(), nil
+}
+func (n *node) AsBytes() ([]byte, error) {
+	if n.kind != ipld.ReprKind_Bytes {
+		return nil, n.wrongKind("AsBytes", ipld.ReprKindSet_JustBytes)
+	}
+	return n.rv.Bytes(), nil
+}
+func (n *node) AsLink() (ipld.Link, error) {
+	if n.kind != ipld.ReprKind_Link {
+		return nil, n.wrongKind("AsLink", ipld.ReprKindSet_JustLink)
+	}
+	return n.rv.Interface().(ipld.Link), nil
+}
+
+// Style returns basicnode.Style__Any: wrapped values are read-only,
+// so building a new node "like this one" means building a basicnode.
+func (n *node) Style() ipld.NodeStyle {
+	return basicnode.Style__Any{}
+}
+
+const (
+	maxInt = int(^uint(0) >> 1)
+	minInt = -maxInt - 1
+)
+
+// -- MapIterator -->
+
+// structIterator yields the fields of a struct, skipping absent ones
+// (see structField.absent).
+type structIterator struct {
+	rv     reflect.Value
+	fields []structField
+	idx    int
+}
+
+func (itr *structIterator) Next() (k ipld.Node, v ipld.Node, err error) {
+	itr.skipAbsent()
+	if itr.Done() {
+		return nil, nil, ipld.ErrIteratorOverread{}
+	}
+	f := itr.fields[itr.idx]
+	itr.idx++
+	v, err = wrap(itr.rv.Field(f.index))
+	return basicnode.NewString(f.name), v, err
+}
+func (itr *structIterator) Done() bool {
+	itr.skipAbsent()
+	return itr.idx >= len(itr.fields)
+}
+func (itr *structIterator) skipAbsent() {
+	for itr.idx < len(itr.fields) && itr.fields[itr.idx].absent(itr.rv) {
+		itr.idx++
+	}
+}
+
+type mapIterator struct {
+	rv   reflect.Value
+	keys []reflect.Value
+	idx  int
+}
+
+func (itr *mapIterator) Next() (k ipld.Node, v ipld.Node, err error) {
+	if itr.Done() {
+		return nil, nil, ipld.ErrIteratorOverread{}
+	}
+	key := itr.keys[itr.idx]
+	itr.idx++
+	v, err = wrap(itr.rv.MapIndex(key))
+	return basicnode.NewString(key.String()), v, err
+}
+func (itr *mapIterator) Done() bool {
+	return itr.idx >= len(itr.keys)
+}
+
+// -- ListIterator -->
+
+type listIterator struct {
+	rv  reflect.Value
+	idx int
+}
+
+func (itr *listIterator) Next() (idx int, v ipld.Node, err error) {
+	if itr.Done() {
+		return -1, nil, ipld.ErrIteratorOverread{}
+	}
+	idx = itr.idx
+	itr.idx++
+	v, err = wrap(itr.rv.Index(idx))
+	return idx, v, err
+}
+func (itr *listIterator) Done() bool {
+	return itr.idx >= itr.rv.Len()
+}
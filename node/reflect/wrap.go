@@ -0,0 +1,276 @@
+/*
+The reflectnode package presents plain golang values as ipld.Node,
+by use of reflection.
+
+This is a convenience for prototyping and tests: it's slow (every method
+call goes through reflection), and it's read-only.  If you want performance,
+use basicnode; if you want native golang types, use codegen.
+
+Values are wrapped, not copied: the Node reads from the original value
+each time it's asked, so the value must not be mutated while the Node is in use.
+
+The reflection rules are:
+
+  - structs are maps, with one entry per exported field, in field order;
+    the key for each field is controlled by its tag (see below);
+    embedded structs are fields like any other, not flattened;
+  - golang maps with string-kinded keys are maps, iterated in sorted key order;
+  - slices and arrays are lists, except []byte, which is bytes;
+  - bool, all int and uint types, float32, float64, and string are the matching scalar kinds;
+  - ipld.Link values are links, and ipld.Node values are used as they are;
+  - nil pointers, nil interfaces, and nil maps or slices are null;
+    any other pointer or interface is followed to the value it contains.
+
+Struct field tags use the key "ipld", and have the grammar:
+
+	`ipld:"-"`                -- the field is skipped entirely.
+	`ipld:"name"`             -- the field's map key is "name" instead of the golang field name.
+	`ipld:"name,opt,opt..."`  -- the name may be empty, meaning "keep the golang field name".
+
+The options are:
+
+	omitempty  -- the field is absent from the map when it holds its golang zero value
+	              (false, 0, "", or a nil or zero-length pointer, interface, map, or slice).
+	optional   -- the field is absent from the map when it is nil;
+	              only valid on pointer, interface, map, and slice fields.
+	              (Without this, a nil field is present, with a null value.)
+
+An absent field is skipped by the map iterator, not counted by Length,
+and LookupString returns ErrNotExists for it, exactly as if it had no such field.
+Unexported fields are always skipped.
+
+Tags that don't follow the grammar, and structs where two fields end up
+with the same key, are rejected with an ErrInvalidTag,
+from Wrap or from whatever lookup or iterator step first reaches the struct.
+
+Anything else (channels, funcs, complex numbers, maps with non-string keys...)
+can't be wrapped.  Wrap rejects them at the top level; when found deeper,
+the lookup or iterator step that reaches them returns the error.
+
+From is like Wrap, but copies the value's content into a basicnode tree,
+so the value is free to change afterwards.
+Into goes the other way: it decodes a node into a golang value,
+by the same rules (and the same tags).
+*/
+package reflectnode
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+
+	ipld "github.com/ipld/go-ipld-prime"
+)
+
+var (
+	typeOfLink  = reflect.TypeOf((*ipld.Link)(nil)).Elem()
+	typeOfNode  = reflect.TypeOf((*ipld.Node)(nil)).Elem()
+	typeOfBytes = reflect.TypeOf([]byte(nil))
+)
+
+// Wrap returns a read-only ipld.Node presenting the given value.
+// See the package docs for how golang types are mapped to the Data Model.
+func Wrap(v interface{}) (ipld.Node, error) {
+	return wrap(reflect.ValueOf(v))
+}
+
+// ErrUnsupportedType is returned when trying to wrap a value of a type
+// which has no Data Model equivalent.
+type ErrUnsupportedType struct {
+	Type reflect.Type
+}
+
+func (e ErrUnsupportedType) Error() string {
+	return fmt.Sprintf("reflectnode: cannot wrap values of type %s", e.Type)
+}
+
+func wrap(rv reflect.Value) (ipld.Node, error) {
+	// Follow pointers and interfaces; nils are nulls.
+	for rv.Kind() == reflect.Ptr || rv.Kind() == reflect.Interface {
+		if rv.IsNil() {
+			return ipld.Null, nil
+		}
+		if rv.Type().Implements(typeOfLink) {
+			break
+		}
+		if rv.Type().Implements(typeOfNode) {
+			return rv.Interface().(ipld.Node), nil
+		}
+		rv = rv.Elem()
+	}
+	if !rv.IsValid() {
+		return ipld.Null, nil
+	}
+	t := rv.Type()
+	switch {
+	case t.Implements(typeOfLink):
+		return &node{rv, ipld.ReprKind_Link}, nil
+	case t.Implements(typeOfNode):
+		return rv.Interface().(ipld.Node), nil
+	case t == typeOfBytes, t.Kind() == reflect.Slice && t.Elem().Kind() == reflect.Uint8:
+		if rv.IsNil() {
+			return ipld.Null, nil
+		}
+		return &node{rv, ipld.ReprKind_Bytes}, nil
+	}
+	switch t.Kind() {
+	case reflect.Struct:
+		if _, err := structFields(t); err != nil {
+			return nil, err
+		}
+		return &node{rv, ipld.ReprKind_Map}, nil
+	case reflect.Map:
+		if t.Key().Kind() != reflect.String {
+			return nil, ErrUnsupportedType{t}
+		}
+		if rv.IsNil() {
+			return ipld.Null, nil
+		}
+		return &node{rv, ipld.ReprKind_Map}, nil
+	case reflect.Slice:
+		if rv.IsNil() {
+			return ipld.Null, nil
+		}
+		return &node{rv, ipld.ReprKind_List}, nil
+	case reflect.Array:
+		return &node{rv, ipld.ReprKind_List}, nil
+	case reflect.Bool:
+		return &node{rv, ipld.ReprKind_Bool}, nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return &node{rv, ipld.ReprKind_Int}, nil
+	case reflect.Float32, reflect.Float64:
+		return &node{rv, ipld.ReprKind_Float}, nil
+	case reflect.String:
+		return &node{rv, ipld.ReprKind_String}, nil
+	default:
+		return nil, ErrUnsupportedType{t}
+	}
+}
+
+// ErrInvalidTag is returned when a struct type's "ipld" field tags
+// don't follow the grammar described in the package docs,
+// or would give two fields the same map key.
+type ErrInvalidTag struct {
+	Type   reflect.Type
+	Field  string // the golang field name.
+	Reason string
+}
+
+func (e ErrInvalidTag) Error() string {
+	return fmt.Sprintf("reflectnode: invalid ipld tag on field %s of %s: %s", e.Field, e.Type, e.Reason)
+}
+
+// structField describes one visible field of a struct type.
+type structField struct {
+	name      string // the map key: the field name, or the tag's rename.
+	index     int    // for reflect.Value.Field.
+	omitempty bool   // absent if the value is empty (see isEmpty).
+	optional  bool   // absent if the value is nil.
+}
+
+// absent reports whether the field is left out of the map for this struct value.
+func (f structField) absent(rv reflect.Value) bool {
+	fv := rv.Field(f.index)
+	switch {
+	case f.omitempty:
+		return isEmpty(fv)
+	case f.optional:
+		return fv.IsNil()
+	default:
+		return false
+	}
+}
+
+// structInfo is the parsed field list of a struct type, or the error from parsing it.
+type structInfo struct {
+	fields []structField
+	err    error
+}
+
+// structInfoCache holds the structInfo for each struct type we've seen,
+// so the field list (and tag parsing) is only computed once per type.
+var structInfoCache sync.Map // map[reflect.Type]structInfo
+
+func structFields(t reflect.Type) ([]structField, error) {
+	if si, ok := structInfoCache.Load(t); ok {
+		return si.(structInfo).fields, si.(structInfo).err
+	}
+	fields, err := parseStructFields(t)
+	structInfoCache.Store(t, structInfo{fields, err})
+	return fields, err
+}
+
+func parseStructFields(t reflect.Type) ([]structField, error) {
+	fields := make([]structField, 0, t.NumField())
+	seen := make(map[string]string, t.NumField()) // key -> golang field name.
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.PkgPath != "" { // unexported.
+			continue
+		}
+		sf := structField{name: f.Name, index: i}
+		if tag, ok := f.Tag.Lookup("ipld"); ok {
+			if tag == "-" {
+				continue
+			}
+			opts := strings.Split(tag, ",")
+			if opts[0] != "" {
+				sf.name = opts[0]
+			}
+			for _, opt := range opts[1:] {
+				switch opt {
+				case "omitempty":
+					sf.omitempty = true
+				case "optional":
+					switch f.Type.Kind() {
+					case reflect.Ptr, reflect.Interface, reflect.Map, reflect.Slice:
+						sf.optional = true
+					default:
+						return nil, ErrInvalidTag{t, f.Name, fmt.Sprintf("optional cannot be used on a field of kind %s", f.Type.Kind())}
+					}
+				default:
+					return nil, ErrInvalidTag{t, f.Name, fmt.Sprintf("unknown option %q", opt)}
+				}
+			}
+		}
+		if other, exists := seen[sf.name]; exists {
+			return nil, ErrInvalidTag{t, f.Name, fmt.Sprintf("key %q is already used by field %s", sf.name, other)}
+		}
+		seen[sf.name] = f.Name
+		fields = append(fields, sf)
+	}
+	return fields, nil
+}
+
+// isEmpty reports whether a value counts as empty for omitempty.
+// Structs are never empty.
+func isEmpty(rv reflect.Value) bool {
+	switch rv.Kind() {
+	case reflect.Bool:
+		return !rv.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return rv.Int() == 0
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return rv.Uint() == 0
+	case reflect.Float32, reflect.Float64:
+		return rv.Float() == 0
+	case reflect.String, reflect.Array, reflect.Map, reflect.Slice:
+		return rv.Len() == 0
+	case reflect.Ptr, reflect.Interface:
+		return rv.IsNil()
+	default:
+		return false
+	}
+}
+
+// sortedMapKeys returns the keys of a golang map (which must have string-kinded keys), sorted.
+func sortedMapKeys(rv reflect.Value) []reflect.Value {
+	keys := rv.MapKeys()
+	sort.Slice(keys, func(i, j int) bool {
+		return keys[i].String() < keys[j].String()
+	})
+	return keys
+}
@@ -0,0 +1,164 @@
+package reflectnode
+
+import (
+	"testing"
+
+	. "github.com/warpfork/go-wish"
+
+	ipld "github.com/ipld/go-ipld-prime"
+	basicnode "github.com/ipld/go-ipld-prime/node/basic"
+)
+
+type sampleInner struct {
+	Flag bool
+	Blob []byte
+}
+
+type sample struct {
+	Name    string
+	Count   uint8 `ipld:"n"`
+	Ratio   float32
+	Tags    []string
+	Inner   sampleInner
+	Ptr     *sampleInner
+	Extra   map[string]int
+	Ignored string `ipld:"-"`
+	hidden  int
+}
+
+func TestWrap(t *testing.T) {
+	v := sample{
+		Name:   "alpha",
+		Count:  3,
+		Ratio:  0.5,
+		Tags:   []string{"x", "y"},
+		Inner:  sampleInner{true, []byte{0x01}},
+		Extra:  map[string]int{"b": 2, "a": 1},
+		hidden: 7,
+	}
+	n, err := Wrap(&v)
+	Wish(t, err, ShouldEqual, nil)
+	Wish(t, n.ReprKind(), ShouldEqual, ipld.ReprKind_Map)
+	Wish(t, n.Length(), ShouldEqual, 7)
+
+	t.Run("iteration order and rendering", func(t *testing.T) {
+		Wish(t, ipld.Sprint(n), ShouldEqual, `{"Name": "alpha", "n": 3, "Ratio": 0.5, "Tags": ["x", "y"], "Inner": {"Flag": true, "Blob": bytes(01)}, "Ptr": null, "Extra": {"a": 1, "b": 2}}`)
+	})
+	t.Run("lookups", func(t *testing.T) {
+		tags, err := n.LookupString("Tags")
+		Wish(t, err, ShouldEqual, nil)
+		tag, err := tags.LookupIndex(1)
+		Wish(t, err, ShouldEqual, nil)
+		s, _ := tag.AsString()
+		Wish(t, s, ShouldEqual, "y")
+
+		_, err = tags.LookupIndex(2)
+		Wish(t, err, ShouldEqual, ipld.ErrNotExists{ipld.PathSegmentOfInt(2)})
+		for _, k := range []string{"Count", "Ignored", "hidden", "nope"} {
+			_, err = n.LookupString(k)
+			Wish(t, err, ShouldEqual, ipld.ErrNotExists{ipld.PathSegmentOfString(k)})
+		}
+
+		cnt, err := n.LookupSegment(ipld.PathSegmentOfString("n"))
+		Wish(t, err, ShouldEqual, nil)
+		i, err := cnt.AsInt()
+		Wish(t, err, ShouldEqual, nil)
+		Wish(t, i, ShouldEqual, 3)
+
+		extra, _ := n.LookupString("Extra")
+		b, err := extra.Lookup(basicnode.NewString("b"))
+		Wish(t, err, ShouldEqual, nil)
+		i, _ = b.AsInt()
+		Wish(t, i, ShouldEqual, 2)
+	})
+	t.Run("wrong kind", func(t *testing.T) {
+		_, err := n.AsString()
+		Wish(t, err, ShouldEqual, ipld.ErrWrongKind{TypeName: "reflectnode.sample", MethodName: "AsString", AppropriateKind: ipld.ReprKindSet_JustString, ActualKind: ipld.ReprKind_Map})
+		name, _ := n.LookupString("Name")
+		_, err = name.LookupIndex(0)
+		Wish(t, err, ShouldEqual, ipld.ErrWrongKind{TypeName: "string", MethodName: "LookupIndex", AppropriateKind: ipld.ReprKindSet_JustList, ActualKind: ipld.ReprKind_String})
+	})
+	t.Run("ints out of range", func(t *testing.T) {
+		big, err := Wrap(uint64(1 << 63))
+		Wish(t, err, ShouldEqual, nil)
+		_, err = big.AsInt()
+		Wish(t, err, ShouldEqual, ipld.ErrIntegerOverflow{Value: "9223372036854775808"})
+	})
+	t.Run("reads through to the value", func(t *testing.T) {
+		v.Name = "beta"
+		name, _ := n.LookupString("Name")
+		s, _ := name.AsString()
+		Wish(t, s, ShouldEqual, "beta")
+	})
+	t.Run("copy to basicnode", func(t *testing.T) {
+		nb := n.Style().NewBuilder()
+		Wish(t, ipld.Copy(n, nb), ShouldEqual, nil)
+		Wish(t, ipld.Sprint(nb.Build()), ShouldEqual, ipld.Sprint(n))
+	})
+}
+
+func TestWrapUnsupported(t *testing.T) {
+	_, err := Wrap(make(chan int))
+	Wish(t, err.Error(), ShouldEqual, "reflectnode: cannot wrap values of type chan int")
+
+	n, err := Wrap(struct{ F func() }{})
+	Wish(t, err, ShouldEqual, nil)
+	_, err = n.LookupString("F")
+	Wish(t, err.Error(), ShouldEqual, "reflectnode: cannot wrap values of type func()")
+	_, _, err = n.MapIterator().Next()
+	Wish(t, err.Error(), ShouldEqual, "reflectnode: cannot wrap values of type func()")
+}
+
+// k2 mirrors gendemo's K2 struct: the golang field names differ from the keys.
+type k2 struct {
+	Username string `ipld:"u"`
+	Index    int    `ipld:"i"`
+}
+
+func TestWrapTags(t *testing.T) {
+	t.Run("renamed fields", func(t *testing.T) {
+		n, err := Wrap(k2{"woo", 2})
+		Wish(t, err, ShouldEqual, nil)
+		Wish(t, ipld.Sprint(n), ShouldEqual, `{"u": "woo", "i": 2}`)
+		v, err := n.LookupString("u")
+		Wish(t, err, ShouldEqual, nil)
+		s, _ := v.AsString()
+		Wish(t, s, ShouldEqual, "woo")
+		_, err = n.LookupString("Username")
+		Wish(t, err, ShouldEqual, ipld.ErrNotExists{ipld.PathSegmentOfString("Username")})
+	})
+	t.Run("omitempty and optional", func(t *testing.T) {
+		type opts struct {
+			A string       `ipld:"a,omitempty"`
+			B *sampleInner `ipld:",optional"`
+			C []int        `ipld:"c,omitempty"`
+			D []int        `ipld:"d"`
+		}
+		n, err := Wrap(opts{C: []int{}})
+		Wish(t, err, ShouldEqual, nil)
+		Wish(t, n.Length(), ShouldEqual, 1)
+		Wish(t, ipld.Sprint(n), ShouldEqual, `{"d": null}`)
+		_, err = n.LookupString("B")
+		Wish(t, err, ShouldEqual, ipld.ErrNotExists{ipld.PathSegmentOfString("B")})
+
+		n, err = Wrap(opts{A: "x", B: &sampleInner{}, C: []int{1}})
+		Wish(t, err, ShouldEqual, nil)
+		Wish(t, n.Length(), ShouldEqual, 4)
+		Wish(t, ipld.Sprint(n), ShouldEqual, `{"a": "x", "B": {"Flag": false, "Blob": null}, "c": [1], "d": null}`)
+	})
+	t.Run("invalid tags", func(t *testing.T) {
+		_, err := Wrap(struct {
+			A int `ipld:"a,sometimes"`
+		}{})
+		Wish(t, err.Error(), ShouldEqual, `reflectnode: invalid ipld tag on field A of struct { A int "ipld:\"a,sometimes\"" }: unknown option "sometimes"`)
+		_, err = Wrap(struct {
+			A int `ipld:",optional"`
+		}{})
+		Wish(t, err.Error(), ShouldEqual, `reflectnode: invalid ipld tag on field A of struct { A int "ipld:\",optional\"" }: optional cannot be used on a field of kind int`)
+		_, err = Wrap(struct {
+			A int `ipld:"B"`
+			B int
+		}{})
+		Wish(t, err.Error(), ShouldEqual, `reflectnode: invalid ipld tag on field B of struct { A int "ipld:\"B\""; B int }: key "B" is already used by field A`)
+	})
+}
@@ -0,0 +1,77 @@
+package tests
+
+import (
+	"testing"
+
+	. "github.com/warpfork/go-wish"
+
+	ipld "github.com/ipld/go-ipld-prime"
+)
+
+// SpecTestConformance builds each of the fixtures (given as JSON) with the
+// given NodeStyle, and then checks the resulting nodes with CheckConformance.
+//
+// This is meant to be runnable against any Node implementation, so the
+// fixtures are chosen by the caller: they have to be data the style can hold.
+func SpecTestConformance(t *testing.T, ns ipld.NodeStyle, fixtures []string) {
+	for _, fixture := range fixtures {
+		t.Run(fixture, func(t *testing.T) {
+			CheckConformance(t, mustNodeFromJsonString(ns, fixture))
+		})
+	}
+}
+
+// CheckConformance checks that a node's methods agree with each other,
+// and then does the same for each of its children, recursively:
+//
+//   - for maps, Length matches the number of entries the iterator yields,
+//     and looking up each key (with both LookupString and Lookup) gives the same value the iterator did;
+//   - for lists, likewise with LookupIndex, and LookupIndex(Length()) is ErrNotExists;
+//   - the methods which don't make sense for the node's kind return the right ErrWrongKind (see AssertKindErrors).
+//
+// Values are compared by their ipld.Sprint rendering.
+func CheckConformance(t *testing.T, n ipld.Node) {
+	AssertKindErrors(t, n, n.ReprKind())
+	switch n.ReprKind() {
+	case ipld.ReprKind_Map:
+		count := 0
+		for itr := n.MapIterator(); !itr.Done(); count++ {
+			k, v, err := itr.Next()
+			Require(t, err, ShouldEqual, nil)
+			ks, err := k.AsString()
+			Require(t, err, ShouldEqual, nil)
+			t.Run(ks, func(t *testing.T) {
+				v2, err := n.LookupString(ks)
+				Wish(t, err, ShouldEqual, nil)
+				Wish(t, ipld.Sprint(v2), ShouldEqual, ipld.Sprint(v))
+				v3, err := n.Lookup(k)
+				Wish(t, err, ShouldEqual, nil)
+				Wish(t, ipld.Sprint(v3), ShouldEqual, ipld.Sprint(v))
+				CheckConformance(t, v)
+			})
+		}
+		Wish(t, n.Length(), ShouldEqual, count)
+		_, err := n.LookupString("\x00not a key\x00")
+		Wish(t, err, ShouldBeSameTypeAs, ipld.ErrNotExists{})
+	case ipld.ReprKind_List:
+		count := 0
+		for itr := n.ListIterator(); !itr.Done(); count++ {
+			idx, v, err := itr.Next()
+			Require(t, err, ShouldEqual, nil)
+			Wish(t, idx, ShouldEqual, count)
+			t.Run(ipld.PathSegmentOfInt(idx).String(), func(t *testing.T) {
+				v2, err := n.LookupIndex(idx)
+				Wish(t, err, ShouldEqual, nil)
+				Wish(t, ipld.Sprint(v2), ShouldEqual, ipld.Sprint(v))
+				CheckConformance(t, v)
+			})
+		}
+		Wish(t, n.Length(), ShouldEqual, count)
+		_, err := n.LookupIndex(count)
+		Wish(t, err, ShouldBeSameTypeAs, ipld.ErrNotExists{})
+	default:
+		Wish(t, n.Length(), ShouldEqual, -1)
+		Wish(t, n.MapIterator(), ShouldEqual, nil)
+		Wish(t, n.ListIterator(), ShouldEqual, nil)
+	}
+}
@@ -0,0 +1,45 @@
+package tests
+
+import (
+	"testing"
+
+	. "github.com/warpfork/go-wish"
+
+	ipld "github.com/ipld/go-ipld-prime"
+)
+
+// AssertKindErrors calls each of the AsFoo and LookupFoo methods of a node
+// which don't make sense for its kind, and checks that each one returns an
+// ErrWrongKind naming that method, the kinds appropriate for that method
+// (e.g. AsInt must say ReprKindSet_JustInt), and the given kind as the actual kind.
+//
+// Wrong-kind methods are mostly boilerplate, so this is a cheap way to catch
+// copy-paste mistakes in them.
+func AssertKindErrors(t *testing.T, n ipld.Node, kind ipld.ReprKind) {
+	for _, tc := range []struct {
+		method string
+		kinds  ipld.ReprKindSet
+		call   func() error
+	}{
+		{"LookupString", ipld.ReprKindSet_JustMap, func() error { _, err := n.LookupString("x"); return err }},
+		{"Lookup", ipld.ReprKindSet_JustMap, func() error { _, err := n.Lookup(ipld.Null); return err }},
+		{"LookupIndex", ipld.ReprKindSet_JustList, func() error { _, err := n.LookupIndex(0); return err }},
+		{"AsBool", ipld.ReprKindSet_JustBool, func() error { _, err := n.AsBool(); return err }},
+		{"AsInt", ipld.ReprKindSet_JustInt, func() error { _, err := n.AsInt(); return err }},
+		{"AsFloat", ipld.ReprKindSet_JustFloat, func() error { _, err := n.AsFloat(); return err }},
+		{"AsString", ipld.ReprKindSet_JustString, func() error { _, err := n.AsString(); return err }},
+		{"AsBytes", ipld.ReprKindSet_JustBytes, func() error { _, err := n.AsBytes(); return err }},
+		{"AsLink", ipld.ReprKindSet_JustLink, func() error { _, err := n.AsLink(); return err }},
+	} {
+		if tc.kinds.Contains(kind) {
+			continue
+		}
+		t.Run(tc.method, func(t *testing.T) {
+			err, ok := tc.call().(ipld.ErrWrongKind)
+			Wish(t, ok, ShouldEqual, true)
+			Wish(t, err.MethodName, ShouldEqual, tc.method)
+			Wish(t, err.AppropriateKind, ShouldEqual, tc.kinds)
+			Wish(t, err.ActualKind, ShouldEqual, kind)
+		})
+	}
+}
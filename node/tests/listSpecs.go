@@ -0,0 +1,81 @@
+package tests
+
+import (
+	"testing"
+
+	. "github.com/warpfork/go-wish"
+
+	ipld "github.com/ipld/go-ipld-prime"
+)
+
+// SpecTestListAssembler checks the ListAssembler contract:
+// values go in strictly by AssembleValue, positive sizeHints are bounds,
+// and a ListAssembler is never mistakable for a MapAssembler.
+//
+// The NodeStyle must be able to build lists containing ints.
+func SpecTestListAssembler(t *testing.T, ns ipld.NodeStyle) {
+	t.Run("list with exact sizeHint", func(t *testing.T) {
+		nb := ns.NewBuilder()
+		la, err := nb.BeginList(3)
+		Require(t, err, ShouldEqual, nil)
+		for i := 0; i < 3; i++ {
+			Wish(t, la.AssembleValue().AssignInt(i), ShouldEqual, nil)
+		}
+		Wish(t, la.Finish(), ShouldEqual, nil)
+		n := nb.Build()
+		Wish(t, n.ReprKind(), ShouldEqual, ipld.ReprKind_List)
+		Wish(t, n.Length(), ShouldEqual, 3)
+		v, err := n.LookupIndex(2)
+		Wish(t, err, ShouldEqual, nil)
+		v2, err := v.AsInt()
+		Wish(t, err, ShouldEqual, nil)
+		Wish(t, v2, ShouldEqual, 2)
+	})
+	t.Run("list exceeding sizeHint errors", func(t *testing.T) {
+		nb := ns.NewBuilder()
+		la, err := nb.BeginList(2)
+		Require(t, err, ShouldEqual, nil)
+		Wish(t, la.AssembleValue().AssignInt(0), ShouldEqual, nil)
+		Wish(t, la.AssembleValue().AssignInt(1), ShouldEqual, nil)
+		Wish(t, la.AssembleValue().AssignInt(2), ShouldEqual, ipld.ErrListOverrun{2})
+		_, err = la.AssembleValue().BeginList(0)
+		Wish(t, err, ShouldEqual, ipld.ErrListOverrun{2})
+		Wish(t, la.Finish(), ShouldEqual, nil)
+		Wish(t, nb.Build().Length(), ShouldEqual, 2)
+	})
+	t.Run("list with unknown sizeHint is unbounded", func(t *testing.T) {
+		for _, hint := range []int{-1, 0} {
+			nb := ns.NewBuilder()
+			la, err := nb.BeginList(hint)
+			Require(t, err, ShouldEqual, nil)
+			for i := 0; i < 10; i++ {
+				Wish(t, la.AssembleValue().AssignInt(i), ShouldEqual, nil)
+			}
+			Wish(t, la.Finish(), ShouldEqual, nil)
+			Wish(t, nb.Build().Length(), ShouldEqual, 10)
+		}
+	})
+	t.Run("list assembler is not a map assembler", func(t *testing.T) {
+		// Note that `la.(ipld.MapAssembler)` can't even be written:
+		// the ValueStyle signatures conflict, so the compiler (and vet) reject it.
+		la, err := ns.NewBuilder().BeginList(0)
+		Require(t, err, ShouldEqual, nil)
+
+		// If the implementation has the map-only methods at all, they must error rather than work.
+		if la2, ok := la.(interface {
+			AssembleEntry(string) (ipld.NodeAssembler, error)
+		}); ok {
+			_, err := la2.AssembleEntry("nope")
+			Wish(t, err, ShouldBeSameTypeAs, ipld.ErrWrongKind{})
+		}
+		if la2, ok := la.(interface {
+			AssembleKey() ipld.NodeAssembler
+		}); ok {
+			err := la2.AssembleKey().AssignString("nope")
+			Wish(t, err, ShouldBeSameTypeAs, ipld.ErrWrongKind{})
+		}
+		// Misuse didn't damage anything: the list still works.
+		Wish(t, la.AssembleValue().AssignInt(1), ShouldEqual, nil)
+		Wish(t, la.Finish(), ShouldEqual, nil)
+	})
+}
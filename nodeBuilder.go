@@ -91,6 +91,23 @@ type MapAssembler interface {
 	ValueStyle(k string) NodeStyle
 }
 
+// ListAssembler assembles a list node.
+//
+// The method set is deliberately smaller than MapAssembler's:
+// lists have no keys, so there is no AssembleKey and no AssembleEntry.
+// Values are added strictly in order by calling AssembleValue and then
+// using the yielded NodeAssembler to completion; when done, call 'Finish'.
+// A ListAssembler never satisfies the MapAssembler interface
+// (the ValueStyle signatures differ, and KeyStyle is absent).
+//
+// If BeginList was called with a positive sizeHint, implementations may treat
+// it as a bound: assembling more values than that will yield a NodeAssembler
+// that returns ErrListOverrun from any of its methods.
+// A sizeHint of zero or less means the length is unknown and is not bounded.
+//
+// Implementations which can be reached through the MapAssembler methods by
+// mistake (e.g. because they share a concrete type with other assemblers)
+// should return ErrWrongKind from AssembleKey and AssembleEntry rather than panic.
 type ListAssembler interface {
 	AssembleValue() NodeAssembler
 
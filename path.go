@@ -187,6 +187,16 @@ func (p Path) Parent() Path {
 	return Path{p.segments[0 : len(p.segments)-1]}
 }
 
+// Last returns the last segment of the path: e.g., during a traversal,
+// the map key or list index by which the current node was reached from its parent.
+// If the path is empty, it returns the zero PathSegment and false.
+func (p Path) Last() (PathSegment, bool) {
+	if len(p.segments) == 0 {
+		return PathSegment{}, false
+	}
+	return p.segments[len(p.segments)-1], true
+}
+
 // Truncate returns a path with only as many segments remaining as requested.
 func (p Path) Truncate(i int) Path {
 	return Path{p.segments[0:i]}
@@ -1,6 +1,7 @@
 package ipld
 
 import (
+	"fmt"
 	"strconv"
 )
 
@@ -85,10 +86,37 @@ func PathSegmentOfString(s string) PathSegment {
 }
 
 // PathSegmentOfString boxes an int into a PathSegment.
+//
+// Negative ints aren't valid list indexes in the Data Model, but some helpers
+// (e.g. LookupIndexRelative) give them a meaning, and they should at least
+// print correctly in errors; since we use negative ints as the sentinel for
+// string storage, they're stored in string form.
 func PathSegmentOfInt(i int) PathSegment {
+	if i < 0 {
+		return PathSegment{s: strconv.Itoa(i), i: -1}
+	}
 	return PathSegment{i: i}
 }
 
+// PathSegmentsFrom boxes each of a list of strings and ints into a PathSegment,
+// as by PathSegmentOfString and PathSegmentOfInt respectively;
+// it's a shorthand for building paths in code, e.g. `PathSegmentsFrom("a", 3, "x")`.
+// Values of any other type are an error.
+func PathSegmentsFrom(vals ...interface{}) ([]PathSegment, error) {
+	segs := make([]PathSegment, len(vals))
+	for i, v := range vals {
+		switch v2 := v.(type) {
+		case string:
+			segs[i] = PathSegmentOfString(v2)
+		case int:
+			segs[i] = PathSegmentOfInt(v2)
+		default:
+			return nil, fmt.Errorf("cannot make a PathSegment from value %d: %T is neither a string nor an int", i, v)
+		}
+	}
+	return segs, nil
+}
+
 // containsString is unexported because we use it to see what our *storage* form is,
 // but this is considered an implementation detail that's non-semantic.
 // If it returns false, it implicitly means "containsInt", as these are the only options.
@@ -128,6 +156,14 @@ func (ps PathSegment) Index() (int, error) {
 // (You should still typically prefer this method over converting two segments
 // to string and comparing those, because even though that may be functionally
 // correct, this method will be faster if they're both ints internally.)
+//
+// The string form of an int is its plain decimal form (as by strconv.Itoa),
+// and only that form is equal to the int: so, for example,
+// `PathSegmentOfInt(2).Equals(PathSegmentOfString("02")) == false`,
+// even though both have an Index of 2.
+// Anything that decides whether a segment "is" some index (e.g. selectors)
+// should use this method, rather than comparing the results of Index,
+// so that it agrees with code comparing by string (e.g. map lookups).
 func (x PathSegment) Equals(o PathSegment) bool {
 	if !x.containsString() && !o.containsString() {
 		return x.i == o.i
@@ -27,6 +27,51 @@ func TestParsePath(t *testing.T) {
 	})
 }
 
+func TestPathSegmentEquals(t *testing.T) {
+	t.Run("same storage compares directly", func(t *testing.T) {
+		Wish(t, PathSegmentOfInt(2).Equals(PathSegmentOfInt(2)), ShouldEqual, true)
+		Wish(t, PathSegmentOfInt(2).Equals(PathSegmentOfInt(3)), ShouldEqual, false)
+		Wish(t, PathSegmentOfString("a").Equals(PathSegmentOfString("a")), ShouldEqual, true)
+		Wish(t, PathSegmentOfString("a").Equals(PathSegmentOfString("b")), ShouldEqual, false)
+	})
+	t.Run("int and string storage are equal when the string is the canonical int", func(t *testing.T) {
+		Wish(t, PathSegmentOfInt(2).Equals(PathSegmentOfString("2")), ShouldEqual, true)
+		Wish(t, PathSegmentOfString("2").Equals(PathSegmentOfInt(2)), ShouldEqual, true)
+		Wish(t, ParsePathSegment("0").Equals(PathSegment{}), ShouldEqual, true)
+		Wish(t, PathSegmentOfInt(-1).Equals(PathSegmentOfString("-1")), ShouldEqual, true)
+	})
+	t.Run("non-canonical int strings are not equal to the int", func(t *testing.T) {
+		for _, s := range []string{"02", "+2", " 2", "2.0"} {
+			Wish(t, PathSegmentOfInt(2).Equals(PathSegmentOfString(s)), ShouldEqual, false)
+			Wish(t, PathSegmentOfString(s).Equals(PathSegmentOfInt(2)), ShouldEqual, false)
+		}
+		// ... even though some of them do parse as the same index.
+		i, err := PathSegmentOfString("02").Index()
+		Wish(t, err, ShouldEqual, nil)
+		Wish(t, i, ShouldEqual, 2)
+	})
+	t.Run("equality is symmetric and reflexive across storage", func(t *testing.T) {
+		segs := []PathSegment{PathSegmentOfInt(0), PathSegmentOfString("0"), PathSegmentOfInt(10), PathSegmentOfString("10"), PathSegmentOfString("x"), PathSegmentOfString("")}
+		for _, a := range segs {
+			Wish(t, a.Equals(a), ShouldEqual, true)
+			for _, b := range segs {
+				Wish(t, a.Equals(b), ShouldEqual, b.Equals(a))
+				Wish(t, a.Equals(b), ShouldEqual, a.String() == b.String())
+			}
+		}
+	})
+}
+
+func TestPathSegmentsFrom(t *testing.T) {
+	segs, err := PathSegmentsFrom("a", 3, "x")
+	Wish(t, err, ShouldEqual, nil)
+	Wish(t, segs, ShouldEqual, []PathSegment{{s: "a", i: -1}, {i: 3}, {s: "x", i: -1}})
+	Wish(t, NewPath(segs).String(), ShouldEqual, "a/3/x")
+
+	_, err = PathSegmentsFrom("a", 1.5)
+	Wish(t, err.Error(), ShouldEqual, "cannot make a PathSegment from value 1: float64 is neither a string nor an int")
+}
+
 func TestPathSegmentZeroValue(t *testing.T) {
 	Wish(t, PathSegment{}.String(), ShouldEqual, "0")
 	i, err := PathSegment{}.Index()
@@ -0,0 +1,131 @@
+package ipld
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Print writes a compact, single-line, human-readable rendering of a Node
+// to the writer.  It's meant for debugging, logging, and error messages;
+// the format is not a codec, and isn't meant to be parsed.
+//
+// Maps look like `{"k": "v", "k2": 1}` (keys are printed like any other
+// node, so complex keys work), lists like `[1, 2]`, strings are quoted,
+// bytes look like `bytes(0a0b)`, links like `link(<link.String()>)`,
+// and undefined nodes print as `undefined`.
+func Print(w io.Writer, n Node) error {
+	put := func(format string, args ...interface{}) error {
+		_, err := fmt.Fprintf(w, format, args...)
+		return err
+	}
+	var kd KindDispatch
+	kd = KindDispatch{
+		OnMap: func(n Node) error {
+			if err := put("{"); err != nil {
+				return err
+			}
+			for itr, first := n.MapIterator(), true; !itr.Done(); first = false {
+				k, v, err := itr.Next()
+				if err != nil {
+					return err
+				}
+				if !first {
+					if err := put(", "); err != nil {
+						return err
+					}
+				}
+				if err := kd.Do(k); err != nil {
+					return err
+				}
+				if err := put(": "); err != nil {
+					return err
+				}
+				if err := kd.Do(v); err != nil {
+					return err
+				}
+			}
+			return put("}")
+		},
+		OnList: func(n Node) error {
+			if err := put("["); err != nil {
+				return err
+			}
+			for itr := n.ListIterator(); !itr.Done(); {
+				idx, v, err := itr.Next()
+				if err != nil {
+					return err
+				}
+				if idx > 0 {
+					if err := put(", "); err != nil {
+						return err
+					}
+				}
+				if err := kd.Do(v); err != nil {
+					return err
+				}
+			}
+			return put("]")
+		},
+		OnNull: func(n Node) error {
+			return put("null")
+		},
+		OnBool: func(n Node) error {
+			v, err := n.AsBool()
+			if err != nil {
+				return err
+			}
+			return put("%t", v)
+		},
+		OnInt: func(n Node) error {
+			v, err := n.AsInt()
+			if err != nil {
+				return err
+			}
+			return put("%d", v)
+		},
+		OnFloat: func(n Node) error {
+			v, err := n.AsFloat()
+			if err != nil {
+				return err
+			}
+			return put("%v", v)
+		},
+		OnString: func(n Node) error {
+			v, err := n.AsString()
+			if err != nil {
+				return err
+			}
+			return put("%q", v)
+		},
+		OnBytes: func(n Node) error {
+			v, err := n.AsBytes()
+			if err != nil {
+				return err
+			}
+			return put("bytes(%x)", v)
+		},
+		OnLink: func(n Node) error {
+			v, err := n.AsLink()
+			if err != nil {
+				return err
+			}
+			return put("link(%s)", v.String())
+		},
+		Default: func(n Node) error {
+			return put("undefined")
+		},
+	}
+	return kd.Do(n)
+}
+
+// Sprint returns the same rendering as Print, as a string.
+// If an error is encountered partway through (e.g. a failing iterator),
+// the output so far is returned, followed by a description of the error.
+func Sprint(n Node) string {
+	var sb strings.Builder
+	if err := Print(&sb, n); err != nil {
+		fmt.Fprintf(&sb, "!(error: %s)", err)
+	}
+	return sb.String()
+}
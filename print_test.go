@@ -0,0 +1,28 @@
+package ipld_test
+
+import (
+	"testing"
+
+	. "github.com/warpfork/go-wish"
+
+	ipld "github.com/ipld/go-ipld-prime"
+	"github.com/ipld/go-ipld-prime/fluent"
+	basicnode "github.com/ipld/go-ipld-prime/node/basic"
+)
+
+func TestSprint(t *testing.T) {
+	n := fluent.MustBuildMap(basicnode.Style__Map{}, 4, func(na fluent.MapAssembler) {
+		na.AssembleEntry("str").AssignString("hi \"there\"")
+		na.AssembleEntry("list").CreateList(3, func(na fluent.ListAssembler) {
+			na.AssembleValue().AssignInt(1)
+			na.AssembleValue().AssignFloat(1.5)
+			na.AssembleValue().AssignNull()
+		})
+		na.AssembleEntry("bytes").AssignBytes([]byte{0x0a, 0xff})
+		na.AssembleEntry("nested").CreateMap(1, func(na fluent.MapAssembler) {
+			na.AssembleEntry("b").AssignBool(true)
+		})
+	})
+	Wish(t, ipld.Sprint(n), ShouldEqual, `{"str": "hi \"there\"", "list": [1, 1.5, null], "bytes": bytes(0aff), "nested": {"b": true}}`)
+	Wish(t, ipld.Sprint(ipld.Undef), ShouldEqual, `undefined`)
+}
@@ -0,0 +1,89 @@
+package schemadsl
+
+import (
+	"strconv"
+)
+
+// token is one lexical element of the schema DSL:
+// a word (type names, field names, and keywords alike), a quoted string,
+// or a single punctuation character.
+type token struct {
+	kind tokenKind
+	text string // for words, the word; for strings, the unquoted value; for punctuation, the character.
+	line int
+}
+
+type tokenKind uint8
+
+const (
+	tokenEOF tokenKind = iota
+	tokenWord
+	tokenString
+	tokenPunct
+)
+
+func (tk token) String() string {
+	switch tk.kind {
+	case tokenEOF:
+		return "end of input"
+	case tokenString:
+		return strconv.Quote(tk.text)
+	default:
+		return "'" + tk.text + "'"
+	}
+}
+
+// lex splits src into tokens, dropping whitespace and comments ('#' to the end of the line).
+// The last token is always tokenEOF.
+func lex(src string) ([]token, error) {
+	var tks []token
+	line := 1
+	for i := 0; i < len(src); {
+		c := src[i]
+		switch {
+		case c == '\n':
+			line++
+			i++
+		case c == ' ' || c == '\t' || c == '\r':
+			i++
+		case c == '#':
+			for i < len(src) && src[i] != '\n' {
+				i++
+			}
+		case isWordByte(c):
+			j := i
+			for j < len(src) && isWordByte(src[j]) {
+				j++
+			}
+			tks = append(tks, token{tokenWord, src[i:j], line})
+			i = j
+		case c == '"':
+			j := i + 1
+			for j < len(src) && src[j] != '"' && src[j] != '\n' {
+				if src[j] == '\\' {
+					j++
+				}
+				j++
+			}
+			if j >= len(src) || src[j] != '"' {
+				return nil, ErrParse{line, "unterminated string"}
+			}
+			s, err := strconv.Unquote(src[i : j+1])
+			if err != nil {
+				return nil, ErrParse{line, "invalid string " + src[i:j+1]}
+			}
+			tks = append(tks, token{tokenString, s, line})
+			i = j + 1
+		case c == '{' || c == '}' || c == '[' || c == ']' || c == '(' || c == ')' || c == ':' || c == ',' || c == '&' || c == '|':
+			tks = append(tks, token{tokenPunct, string(c), line})
+			i++
+		default:
+			return nil, ErrParse{line, "unexpected character " + strconv.QuoteRune(rune(c))}
+		}
+	}
+	return append(tks, token{tokenEOF, "", line}), nil
+}
+
+func isWordByte(c byte) bool {
+	return c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9'
+}
@@ -0,0 +1,388 @@
+// Package schemadsl parses the IPLD Schema DSL into schema.Type definitions.
+//
+// This is the language of the "ipldsch" comments in the gendemo package, e.g.:
+//
+//	type K2 struct { u string, i string } representation stringjoin (":")
+//	type T2 struct { a int, b int, c int, d int }
+//	type Root struct { mp {K2:T2} }
+//
+// The subset supported so far is:
+//
+//   - scalar types: `type Name string` (likewise int, bool, float, bytes, and link);
+//   - link types referring to another type: `type Name &Other`;
+//   - maps: `type Name {Key:Value}`, or `{Key:nullable Value}`;
+//   - lists: `type Name [Value]`, or `[nullable Value]`;
+//   - structs: `type Name struct { field Type, other optional nullable Type }`,
+//     with the fields separated by commas or newlines,
+//     and followed by a representation clause if it's not the default (map):
+//     `representation map { field a "A" }` (renaming the keys of fields),
+//     `representation tuple`,
+//     `representation stringjoin (":")` (or `stringjoin { join ":" }`),
+//     or `representation stringpairs { innerDelim "=" entryDelim "," }`.
+//
+// Wherever a type is referred to, it may be a named type (defined before or after),
+// one of the prelude's scalar types (String, Int, Bool, Float, Bytes, and Link,
+// also accepted in lowercase), or an anonymous map, list, or link type written inline.
+// Comments start with '#' and run to the end of the line.
+//
+// Unions, enums, and the representation strategies other than the above aren't supported yet,
+// and are rejected with an error, as are recursive types
+// (which the schema package can't yet represent, since its types refer to each other by value).
+package schemadsl
+
+import (
+	"fmt"
+
+	"github.com/ipld/go-ipld-prime/schema"
+)
+
+// ErrParse is returned by Parse for any problem with the DSL source,
+// whether in its syntax or in what it defines (e.g. referring to an unknown type).
+type ErrParse struct {
+	Line int // the line (counting from 1) at which the problem was found.
+	Msg  string
+}
+
+func (e ErrParse) Error() string {
+	return fmt.Sprintf("schema dsl: line %d: %s", e.Line, e.Msg)
+}
+
+// Parse reads schema DSL source, and returns a TypeSystem containing
+// the named types it defines, in the order they were defined.
+func Parse(src string) (*schema.TypeSystem, error) {
+	tks, err := lex(src)
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{tks: tks}
+	var decls []*typeDecl
+	for p.peek().kind != tokenEOF {
+		decl, err := p.parseDecl()
+		if err != nil {
+			return nil, err
+		}
+		decls = append(decls, decl)
+	}
+	return reify(decls)
+}
+
+// typeDecl is one parsed `type Name ...` definition.
+type typeDecl struct {
+	name string
+	line int
+	expr typeExpr
+}
+
+// typeExpr is a type as written in the DSL: one of the *Expr types below.
+type typeExpr interface{ exprLine() int }
+
+type scalarExpr struct { // a scalar kind, as the whole definition of a named type: e.g. `type K string`.
+	line int
+	kind string
+}
+type refExpr struct { // a reference to a named or prelude type.
+	line int
+	name string
+}
+type linkExpr struct { // &Name.
+	line int
+	ref  *refExpr
+}
+type mapExpr struct {
+	line     int
+	key      typeExpr
+	value    typeExpr
+	nullable bool
+}
+type listExpr struct {
+	line     int
+	value    typeExpr
+	nullable bool
+}
+type structExpr struct {
+	line   int
+	fields []fieldExpr
+	repr   structReprExpr
+}
+type fieldExpr struct {
+	line     int
+	name     string
+	typ      typeExpr
+	optional bool
+	nullable bool
+}
+type structReprExpr struct {
+	line    int
+	kind    string            // "map" (the default), "tuple", "stringjoin", or "stringpairs".
+	renames map[string]string // for map.
+	seps    [2]string         // for stringjoin (just the first) and stringpairs.
+}
+
+func (e *scalarExpr) exprLine() int { return e.line }
+func (e *refExpr) exprLine() int    { return e.line }
+func (e *linkExpr) exprLine() int   { return e.line }
+func (e *mapExpr) exprLine() int    { return e.line }
+func (e *listExpr) exprLine() int   { return e.line }
+func (e *structExpr) exprLine() int { return e.line }
+
+var scalarKinds = map[string]bool{"string": true, "int": true, "bool": true, "float": true, "bytes": true, "link": true}
+
+type parser struct {
+	tks []token
+	pos int
+}
+
+func (p *parser) peek() token {
+	return p.tks[p.pos]
+}
+
+func (p *parser) next() token {
+	tk := p.tks[p.pos]
+	if tk.kind != tokenEOF {
+		p.pos++
+	}
+	return tk
+}
+
+// accept consumes the next token if it's the given punctuation or word, and reports whether it did.
+func (p *parser) accept(text string) bool {
+	if tk := p.peek(); (tk.kind == tokenPunct || tk.kind == tokenWord) && tk.text == text {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *parser) expect(text string) error {
+	if !p.accept(text) {
+		return p.unexpected("'" + text + "'")
+	}
+	return nil
+}
+
+func (p *parser) expectWord(what string) (token, error) {
+	if p.peek().kind != tokenWord {
+		return token{}, p.unexpected(what)
+	}
+	return p.next(), nil
+}
+
+func (p *parser) expectString(what string) (string, error) {
+	if p.peek().kind != tokenString {
+		return "", p.unexpected(what)
+	}
+	return p.next().text, nil
+}
+
+func (p *parser) unexpected(wanted string) error {
+	tk := p.peek()
+	return ErrParse{tk.line, fmt.Sprintf("expected %s, found %s", wanted, tk)}
+}
+
+func (p *parser) parseDecl() (*typeDecl, error) {
+	if err := p.expect("type"); err != nil {
+		return nil, err
+	}
+	name, err := p.expectWord("type name")
+	if err != nil {
+		return nil, err
+	}
+	decl := &typeDecl{name: name.text, line: name.line}
+	tk := p.peek()
+	switch {
+	case tk.kind == tokenWord && scalarKinds[tk.text]:
+		p.next()
+		decl.expr = &scalarExpr{tk.line, tk.text}
+	case tk.kind == tokenWord && tk.text == "struct":
+		p.next()
+		decl.expr, err = p.parseStruct(tk.line)
+	case tk.kind == tokenWord && (tk.text == "union" || tk.text == "enum"):
+		return nil, ErrParse{tk.line, tk.text + " types are not yet supported"}
+	case tk.kind == tokenWord:
+		return nil, ErrParse{tk.line, fmt.Sprintf("type %s can't be defined as another named type (%s)", name.text, tk.text)}
+	default:
+		decl.expr, err = p.parseTypeExpr()
+	}
+	if err != nil {
+		return nil, err
+	}
+	if tk := p.peek(); tk.kind == tokenWord && tk.text == "representation" {
+		p.next()
+		return decl, p.parseRepresentation(decl)
+	}
+	return decl, nil
+}
+
+// parseTypeExpr parses a type in a position which refers to it: a field type, or a map key or value, etc.
+func (p *parser) parseTypeExpr() (typeExpr, error) {
+	tk := p.next()
+	switch {
+	case tk.kind == tokenWord:
+		return &refExpr{tk.line, tk.text}, nil
+	case tk.kind == tokenPunct && tk.text == "&":
+		name, err := p.expectWord("type name")
+		if err != nil {
+			return nil, err
+		}
+		return &linkExpr{tk.line, &refExpr{name.line, name.text}}, nil
+	case tk.kind == tokenPunct && tk.text == "{":
+		e := &mapExpr{line: tk.line}
+		var err error
+		if e.key, err = p.parseTypeExpr(); err != nil {
+			return nil, err
+		}
+		if err := p.expect(":"); err != nil {
+			return nil, err
+		}
+		e.nullable = p.accept("nullable")
+		if e.value, err = p.parseTypeExpr(); err != nil {
+			return nil, err
+		}
+		return e, p.expect("}")
+	case tk.kind == tokenPunct && tk.text == "[":
+		e := &listExpr{line: tk.line}
+		e.nullable = p.accept("nullable")
+		var err error
+		if e.value, err = p.parseTypeExpr(); err != nil {
+			return nil, err
+		}
+		return e, p.expect("]")
+	default:
+		if tk.kind != tokenEOF {
+			p.pos-- // so the error reports this token.
+		}
+		return nil, p.unexpected("a type")
+	}
+}
+
+func (p *parser) parseStruct(line int) (*structExpr, error) {
+	e := &structExpr{line: line, repr: structReprExpr{line: line, kind: "map"}}
+	if err := p.expect("{"); err != nil {
+		return nil, err
+	}
+	for !p.accept("}") {
+		name, err := p.expectWord("field name or '}'")
+		if err != nil {
+			return nil, err
+		}
+		f := fieldExpr{line: name.line, name: name.text}
+		for _, prev := range e.fields {
+			if prev.name == f.name {
+				return nil, ErrParse{name.line, fmt.Sprintf("duplicate field %q", f.name)}
+			}
+		}
+		f.optional = p.accept("optional")
+		f.nullable = p.accept("nullable")
+		if f.typ, err = p.parseTypeExpr(); err != nil {
+			return nil, err
+		}
+		e.fields = append(e.fields, f)
+		p.accept(",")
+	}
+	return e, nil
+}
+
+func (p *parser) parseRepresentation(decl *typeDecl) error {
+	strategy, err := p.expectWord("representation strategy")
+	if err != nil {
+		return err
+	}
+	switch e := decl.expr.(type) {
+	case *structExpr:
+		e.repr = structReprExpr{line: strategy.line, kind: strategy.text}
+		switch strategy.text {
+		case "map":
+			if !p.accept("{") {
+				return nil
+			}
+			for !p.accept("}") {
+				if err := p.expect("field"); err != nil {
+					return err
+				}
+				name, err := p.expectWord("field name")
+				if err != nil {
+					return err
+				}
+				key, err := p.expectString("the field's key")
+				if err != nil {
+					return err
+				}
+				if e.repr.renames == nil {
+					e.repr.renames = make(map[string]string)
+				}
+				if !hasField(e, name.text) {
+					return ErrParse{name.line, fmt.Sprintf("struct %s has no field %q", decl.name, name.text)}
+				}
+				e.repr.renames[name.text] = key
+			}
+			return nil
+		case "tuple":
+			return nil
+		case "stringjoin":
+			if p.accept("(") {
+				if e.repr.seps[0], err = p.expectString("join separator"); err != nil {
+					return err
+				}
+				return p.expect(")")
+			}
+			return p.parseReprParams([]string{"join"}, e.repr.seps[:1])
+		case "stringpairs":
+			return p.parseReprParams([]string{"innerDelim", "entryDelim"}, e.repr.seps[:])
+		}
+	case *mapExpr:
+		if strategy.text == "map" {
+			return nil
+		}
+	default:
+		return ErrParse{strategy.line, fmt.Sprintf("type %s can't have a representation clause", decl.name)}
+	}
+	return ErrParse{strategy.line, fmt.Sprintf("representation %s is not supported for type %s", strategy.text, decl.name)}
+}
+
+// parseReprParams parses a block of `name "value"` pairs, storing each value in the dest of the same index as its name.
+// Each of the names must be present, once.
+func (p *parser) parseReprParams(names []string, dests []string) error {
+	line := p.peek().line
+	if err := p.expect("{"); err != nil {
+		return err
+	}
+	seen := make([]bool, len(names))
+	for !p.accept("}") {
+		name, err := p.expectWord("parameter name")
+		if err != nil {
+			return err
+		}
+		i := indexOf(names, name.text)
+		if i < 0 || seen[i] {
+			return ErrParse{name.line, fmt.Sprintf("unexpected parameter %s", name.text)}
+		}
+		if dests[i], err = p.expectString(name.text); err != nil {
+			return err
+		}
+		seen[i] = true
+	}
+	for i, name := range names {
+		if !seen[i] {
+			return ErrParse{line, fmt.Sprintf("missing parameter %s", name)}
+		}
+	}
+	return nil
+}
+
+func indexOf(ss []string, s string) int {
+	for i := range ss {
+		if ss[i] == s {
+			return i
+		}
+	}
+	return -1
+}
+
+func hasField(e *structExpr, name string) bool {
+	for _, f := range e.fields {
+		if f.name == name {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,134 @@
+package schemadsl_test
+
+import (
+	"testing"
+
+	. "github.com/warpfork/go-wish"
+
+	"github.com/ipld/go-ipld-prime/fluent"
+	basicnode "github.com/ipld/go-ipld-prime/node/basic"
+	"github.com/ipld/go-ipld-prime/schema"
+	schemadsl "github.com/ipld/go-ipld-prime/schema/dsl"
+)
+
+func TestParseK2T2(t *testing.T) {
+	// As in the gendemo package's comments, but with Root defined before the types it uses.
+	ts, err := schemadsl.Parse(`
+		type Root struct { mp {K2:T2} } # nevermind the root part, the anonymous map is the point.
+		type K2 struct { u string, i string } representation stringjoin (":")
+		type T2 struct { a int, b int, c int, d int }
+	`)
+	Require(t, err, ShouldEqual, nil)
+	Wish(t, ts.Names(), ShouldEqual, []schema.TypeName{"Root", "K2", "T2"})
+
+	tString := schema.SpawnString("String")
+	tInt := schema.SpawnInt("Int")
+	tK2 := schema.SpawnStruct("K2",
+		[]schema.StructField{
+			schema.SpawnStructField("u", tString, false, false),
+			schema.SpawnStructField("i", tString, false, false),
+		},
+		schema.SpawnStructRepresentationStringJoin(":"),
+	)
+	tT2 := schema.SpawnStruct("T2",
+		[]schema.StructField{
+			schema.SpawnStructField("a", tInt, false, false),
+			schema.SpawnStructField("b", tInt, false, false),
+			schema.SpawnStructField("c", tInt, false, false),
+			schema.SpawnStructField("d", tInt, false, false),
+		},
+		schema.SpawnStructRepresentationMap(nil),
+	)
+	Wish(t, ts.TypeByName("K2"), ShouldEqual, tK2)
+	Wish(t, ts.TypeByName("T2"), ShouldEqual, tT2)
+
+	tMap := ts.TypeByName("Root").(schema.TypeStruct).Field("mp").Type().(schema.TypeMap)
+	Wish(t, tMap.IsAnonymous(), ShouldEqual, true)
+	Wish(t, tMap.Name(), ShouldEqual, schema.TypeName("{K2:T2}"))
+	Wish(t, tMap.KeyType(), ShouldEqual, tK2)
+	Wish(t, tMap.ValueType(), ShouldEqual, tT2)
+
+	t.Run("the types work with Validate", func(t *testing.T) {
+		n := fluent.MustBuildMap(basicnode.Style__Map{}, 1, func(ma fluent.MapAssembler) {
+			ma.AssembleEntry("mp").CreateMap(1, func(ma fluent.MapAssembler) {
+				ma.AssembleEntry("x:y").CreateMap(4, func(ma fluent.MapAssembler) {
+					ma.AssembleEntry("a").AssignInt(1)
+					ma.AssembleEntry("b").AssignInt(2)
+					ma.AssembleEntry("c").AssignInt(3)
+					ma.AssembleEntry("d").AssignInt(4)
+				})
+			})
+		})
+		Wish(t, schema.Validate(ts.TypeByName("Root"), n), ShouldEqual, nil)
+		Wish(t, schema.Validate(ts.TypeByName("K2"), basicnode.NewString("xy")).Error(), ShouldEqual, `invalid data at "": not a valid K2: expected 2 fields joined by ":", got 1`)
+	})
+}
+
+func TestParse(t *testing.T) {
+	t.Run("scalars, lists, and links", func(t *testing.T) {
+		ts, err := schemadsl.Parse(`
+			type K string
+			type T int
+			type Ts [nullable T]
+			type L &Ts
+			type Blob bytes
+		`)
+		Require(t, err, ShouldEqual, nil)
+		Wish(t, ts.TypeByName("K"), ShouldEqual, schema.SpawnString("K"))
+		Wish(t, ts.TypeByName("Ts"), ShouldEqual, schema.SpawnList("Ts", schema.SpawnInt("T"), true))
+		Wish(t, ts.TypeByName("L"), ShouldEqual, schema.SpawnLinkReference("L", ts.TypeByName("Ts")))
+		Wish(t, ts.TypeByName("Blob"), ShouldEqual, schema.SpawnBytes("Blob"))
+		Wish(t, ts.TypeByName("Nope"), ShouldEqual, nil)
+	})
+	t.Run("struct with renames, across lines", func(t *testing.T) {
+		ts, err := schemadsl.Parse(`
+			type S struct {
+				a String
+				b optional String
+				c optional nullable [Int]
+			} representation map {
+				field a "A"
+			}
+		`)
+		Require(t, err, ShouldEqual, nil)
+		tS := ts.TypeByName("S").(schema.TypeStruct)
+		Wish(t, tS.RepresentationStrategy(), ShouldEqual, schema.SpawnStructRepresentationMap(map[string]string{"a": "A"}))
+		Wish(t, tS.Field("b").IsOptional(), ShouldEqual, true)
+		Wish(t, tS.Field("c").IsNullable(), ShouldEqual, true)
+		Wish(t, tS.Field("c").Type().Name(), ShouldEqual, schema.TypeName("[Int]"))
+	})
+	t.Run("struct representations", func(t *testing.T) {
+		ts, err := schemadsl.Parse(`
+			type Tup struct { a int, b int } representation tuple
+			type Joined struct { a string, b string } representation stringjoin { join "-" }
+			type Pairs struct { a string, b string } representation stringpairs { innerDelim "=" entryDelim "," }
+		`)
+		Require(t, err, ShouldEqual, nil)
+		Wish(t, ts.TypeByName("Tup").(schema.TypeStruct).RepresentationStrategy(), ShouldEqual, schema.SpawnStructRepresentationTuple())
+		Wish(t, ts.TypeByName("Joined").(schema.TypeStruct).RepresentationStrategy(), ShouldEqual, schema.SpawnStructRepresentationStringJoin("-"))
+		Wish(t, ts.TypeByName("Pairs").(schema.TypeStruct).RepresentationStrategy(), ShouldEqual, schema.SpawnStructRepresentationStringPairs("=", ","))
+	})
+	t.Run("errors", func(t *testing.T) {
+		for _, tc := range []struct{ src, err string }{
+			{`type X strin`, `schema dsl: line 1: type X can't be defined as another named type (strin)`},
+			{`type X struct { a Y }`, `schema dsl: line 1: undefined type Y`},
+			{"type X struct {\n a int\n a int }", `schema dsl: line 3: duplicate field "a"`},
+			{"type X int\ntype X string", `schema dsl: line 2: type X is defined more than once`},
+			{`type X struct { a X }`, `schema dsl: line 1: type X refers to itself, and recursive types are not yet supported`},
+			{`type X {Int:String}`, `schema dsl: line 1: map keys must be represented as strings, and Int isn't`},
+			{`type X struct { a int } representation map { field b "B" }`, `schema dsl: line 1: struct X has no field "b"`},
+			{`type X struct { a int } representation keyed`, `schema dsl: line 1: representation keyed is not supported for type X`},
+			{`type X int representation int`, `schema dsl: line 1: type X can't have a representation clause`},
+			{`type X union { | Int int }`, `schema dsl: line 1: union types are not yet supported`},
+			{`type X [Int`, `schema dsl: line 1: expected ']', found end of input`},
+			{`type X struct { a "b" }`, `schema dsl: line 1: expected a type, found "b"`},
+			{`type X string; type Y string`, `schema dsl: line 1: unexpected character ';'`},
+		} {
+			_, err := schemadsl.Parse(tc.src)
+			Wish(t, err, ShouldBeSameTypeAs, schemadsl.ErrParse{})
+			if err != nil {
+				Wish(t, err.Error(), ShouldEqual, tc.err)
+			}
+		}
+	})
+}
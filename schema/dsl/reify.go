@@ -0,0 +1,206 @@
+package schemadsl
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ipld/go-ipld-prime/schema"
+)
+
+// prelude holds the types which may be referred to without being defined.
+// The lowercase names are the kinds, as used in shorthand like `struct { a int }`.
+var prelude = map[string]schema.Type{}
+
+func init() {
+	for _, t := range []schema.Type{
+		schema.SpawnString("String"),
+		schema.SpawnInt("Int"),
+		schema.SpawnBool("Bool"),
+		schema.SpawnFloat("Float"),
+		schema.SpawnBytes("Bytes"),
+		schema.SpawnLink("Link"),
+	} {
+		prelude[string(t.Name())] = t
+		prelude[strings.ToLower(string(t.Name()))] = t
+	}
+}
+
+// reifier turns parsed declarations into schema.Type values.
+//
+// The schema package's types hold the types they refer to by value,
+// so each named type has to be built after everything it refers to;
+// reifier builds them on demand (memoizing), and so also finds cycles.
+type reifier struct {
+	decls    map[string]*typeDecl
+	built    map[string]schema.Type
+	building map[string]bool
+}
+
+func reify(decls []*typeDecl) (*schema.TypeSystem, error) {
+	r := reifier{
+		decls:    make(map[string]*typeDecl, len(decls)),
+		built:    make(map[string]schema.Type, len(decls)),
+		building: make(map[string]bool),
+	}
+	for _, decl := range decls {
+		if _, exists := r.decls[decl.name]; exists {
+			return nil, ErrParse{decl.line, fmt.Sprintf("type %s is defined more than once", decl.name)}
+		}
+		r.decls[decl.name] = decl
+	}
+	types := make([]schema.Type, len(decls))
+	for i, decl := range decls {
+		t, err := r.named(decl.name, decl.line)
+		if err != nil {
+			return nil, err
+		}
+		types[i] = t
+	}
+	return schema.SpawnTypeSystem(types...)
+}
+
+// named returns the type with the given name, building it if need be.
+// line is where it's referred to, for errors.
+func (r *reifier) named(name string, line int) (schema.Type, error) {
+	if t, ok := r.built[name]; ok {
+		return t, nil
+	}
+	decl, ok := r.decls[name]
+	if !ok {
+		if t, ok := prelude[name]; ok {
+			return t, nil
+		}
+		return nil, ErrParse{line, fmt.Sprintf("undefined type %s", name)}
+	}
+	if r.building[name] {
+		return nil, ErrParse{line, fmt.Sprintf("type %s refers to itself, and recursive types are not yet supported", name)}
+	}
+	r.building[name] = true
+	t, err := r.define(decl)
+	if err != nil {
+		return nil, err
+	}
+	r.built[name] = t
+	return t, nil
+}
+
+// define builds the named type a declaration defines.
+func (r *reifier) define(decl *typeDecl) (schema.Type, error) {
+	name := schema.TypeName(decl.name)
+	switch e := decl.expr.(type) {
+	case *scalarExpr:
+		switch e.kind {
+		case "string":
+			return schema.SpawnString(name), nil
+		case "int":
+			return schema.SpawnInt(name), nil
+		case "bool":
+			return schema.SpawnBool(name), nil
+		case "float":
+			return schema.SpawnFloat(name), nil
+		case "bytes":
+			return schema.SpawnBytes(name), nil
+		default: // link
+			return schema.SpawnLink(name), nil
+		}
+	case *linkExpr:
+		ref, err := r.named(e.ref.name, e.ref.line)
+		if err != nil {
+			return nil, err
+		}
+		return schema.SpawnLinkReference(name, ref), nil
+	case *mapExpr:
+		k, v, err := r.mapMembers(e)
+		if err != nil {
+			return nil, err
+		}
+		return schema.SpawnMap(name, k, v, e.nullable), nil
+	case *listExpr:
+		v, err := r.ref(e.value)
+		if err != nil {
+			return nil, err
+		}
+		return schema.SpawnList(name, v, e.nullable), nil
+	case *structExpr:
+		return r.structType(name, e)
+	default:
+		panic("unreachable")
+	}
+}
+
+// ref builds a type where it's referred to: either a named type, or an anonymous one written inline.
+func (r *reifier) ref(e typeExpr) (schema.Type, error) {
+	switch e := e.(type) {
+	case *refExpr:
+		return r.named(e.name, e.line)
+	case *linkExpr:
+		ref, err := r.named(e.ref.name, e.ref.line)
+		if err != nil {
+			return nil, err
+		}
+		return schema.SpawnLinkReference(schema.TypeName("&"+e.ref.name), ref), nil
+	case *mapExpr:
+		k, v, err := r.mapMembers(e)
+		if err != nil {
+			return nil, err
+		}
+		return schema.SpawnAnonymousMap(k, v, e.nullable), nil
+	case *listExpr:
+		v, err := r.ref(e.value)
+		if err != nil {
+			return nil, err
+		}
+		return schema.SpawnAnonymousList(v, e.nullable), nil
+	default:
+		panic("unreachable") // the parser only produces scalarExpr and structExpr for a whole declaration.
+	}
+}
+
+func (r *reifier) mapMembers(e *mapExpr) (k, v schema.Type, err error) {
+	if k, err = r.ref(e.key); err != nil {
+		return nil, nil, err
+	}
+	if !isStringRepresented(k) {
+		return nil, nil, ErrParse{e.key.exprLine(), fmt.Sprintf("map keys must be represented as strings, and %s isn't", k.Name())}
+	}
+	if v, err = r.ref(e.value); err != nil {
+		return nil, nil, err
+	}
+	return k, v, nil
+}
+
+func isStringRepresented(t schema.Type) bool {
+	switch t2 := t.(type) {
+	case schema.TypeString:
+		return true
+	case schema.TypeStruct:
+		switch t2.RepresentationStrategy().(type) {
+		case schema.StructRepresentation_StringJoin, schema.StructRepresentation_StringPairs:
+			return true
+		}
+	}
+	return false
+}
+
+func (r *reifier) structType(name schema.TypeName, e *structExpr) (schema.Type, error) {
+	fields := make([]schema.StructField, len(e.fields))
+	for i, f := range e.fields {
+		t, err := r.ref(f.typ)
+		if err != nil {
+			return nil, err
+		}
+		fields[i] = schema.SpawnStructField(f.name, t, f.optional, f.nullable)
+	}
+	var repr schema.StructRepresentation
+	switch e.repr.kind {
+	case "map":
+		repr = schema.SpawnStructRepresentationMap(e.repr.renames)
+	case "tuple":
+		repr = schema.SpawnStructRepresentationTuple()
+	case "stringjoin":
+		repr = schema.SpawnStructRepresentationStringJoin(e.repr.seps[0])
+	case "stringpairs":
+		repr = schema.SpawnStructRepresentationStringPairs(e.repr.seps[0], e.repr.seps[1])
+	}
+	return schema.SpawnStruct(name, fields, repr), nil
+}
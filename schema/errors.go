@@ -2,6 +2,8 @@ package schema
 
 import (
 	"fmt"
+
+	ipld "github.com/ipld/go-ipld-prime"
 )
 
 // ErrNoSuchField may be returned from lookup functions on the Node
@@ -16,3 +18,37 @@ type ErrNoSuchField struct {
 func (e ErrNoSuchField) Error() string {
 	return fmt.Sprintf("no such field: %s.%s", e.Type.Name(), e.FieldName)
 }
+
+// ErrValueExceedsRange is returned when assigning a value to a sized int
+// (see TypeInt.Bits) which doesn't fit in its width.
+type ErrValueExceedsRange struct {
+	Type  TypeInt
+	Value int
+}
+
+func (e ErrValueExceedsRange) Error() string {
+	return fmt.Sprintf("value %d exceeds the range of %s (%d bits)", e.Value, e.Type.Name(), e.Type.Bits())
+}
+
+// ErrInvalidEnumValue is returned when assigning a value to an enum
+// which isn't one of its members (or, when assigning the representation,
+// which doesn't represent one of its members).
+type ErrInvalidEnumValue struct {
+	Type  TypeEnum
+	Value interface{} // the string or int which was assigned.
+}
+
+func (e ErrInvalidEnumValue) Error() string {
+	return fmt.Sprintf("%#v is not a member of the enum %s", e.Value, e.Type.Name())
+}
+
+// ErrInvalidData is returned by Validate when a node doesn't conform to a type.
+type ErrInvalidData struct {
+	Path   ipld.Path // where, within the node given to Validate, the problem is.
+	Type   Type      // the type which the node at Path should have conformed to.
+	Reason string
+}
+
+func (e ErrInvalidData) Error() string {
+	return fmt.Sprintf("invalid data at %q: not a valid %s: %s", e.Path.String(), e.Type.Name(), e.Reason)
+}
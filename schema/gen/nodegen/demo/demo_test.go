@@ -0,0 +1,189 @@
+package demo_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	refmtjson "github.com/polydawn/refmt/json"
+	. "github.com/warpfork/go-wish"
+
+	ipld "github.com/ipld/go-ipld-prime"
+	"github.com/ipld/go-ipld-prime/codec/dagjson"
+	"github.com/ipld/go-ipld-prime/node/tests"
+	"github.com/ipld/go-ipld-prime/schema"
+	"github.com/ipld/go-ipld-prime/schema/gen/nodegen/demo"
+)
+
+const fixture = `{"a:b":{"a":1,"b":2,"c":3,"d":4},"c:d":{"a":5,"b":6,"c":7,"d":8}}`
+
+func decode(t *testing.T, ns ipld.NodeStyle, json string) ipld.Node {
+	nb := ns.NewBuilder()
+	Require(t, dagjson.Decoder(nb, strings.NewReader(json)), ShouldEqual, nil)
+	return nb.Build()
+}
+
+func encode(t *testing.T, n ipld.Node) string {
+	var buf bytes.Buffer
+	Require(t, dagjson.Marshal(n, refmtjson.NewEncoder(&buf, refmtjson.EncodeOptions{})), ShouldEqual, nil)
+	return buf.String()
+}
+
+func TestConformance(t *testing.T) {
+	t.Run("T2", func(t *testing.T) {
+		tests.SpecTestConformance(t, demo.Style__T2{}, []string{`{"a":1,"b":2,"c":3,"d":4}`})
+		tests.SpecTestConformance(t, demo.ReprStyle__T2{}, []string{`{"a":1,"b":2,"c":3,"d":4}`})
+	})
+	t.Run("K2", func(t *testing.T) {
+		tests.SpecTestConformance(t, demo.Style__K2{}, []string{`{"u":"a","i":"b"}`})
+		n := decode(t, demo.ReprStyle__K2{}, `"a:b"`)
+		tests.CheckConformance(t, n)
+		tests.CheckConformance(t, n.(schema.TypedNode).Representation())
+	})
+	t.Run("Map_K2_T2", func(t *testing.T) {
+		// The type-level map's keys are K2 structs, which are maps, and CheckConformance needs string keys;
+		// so this checks the representation, whose keys are the K2s' stringjoin representations.
+		n := decode(t, demo.ReprStyle__Map_K2_T2{}, fixture)
+		tests.CheckConformance(t, n.(schema.TypedNode).Representation())
+		tests.AssertKindErrors(t, n, ipld.ReprKind_Map)
+	})
+}
+
+func TestMapK2T2(t *testing.T) {
+	n := decode(t, demo.ReprStyle__Map_K2_T2{}, fixture)
+	t.Run("type-level view", func(t *testing.T) {
+		Wish(t, n.Length(), ShouldEqual, 2)
+		itr := n.MapIterator()
+		k, v, err := itr.Next()
+		Require(t, err, ShouldEqual, nil)
+		Wish(t, ipld.Sprint(k), ShouldEqual, `{"u": "a", "i": "b"}`)
+		Wish(t, ipld.Sprint(v), ShouldEqual, `{"a": 1, "b": 2, "c": 3, "d": 4}`)
+		v2, err := n.Lookup(k)
+		Wish(t, err, ShouldEqual, nil)
+		Wish(t, v2, ShouldEqual, v)
+		v2, err = n.LookupString("c:d")
+		Wish(t, err, ShouldEqual, nil)
+		Wish(t, ipld.Sprint(v2), ShouldEqual, `{"a": 5, "b": 6, "c": 7, "d": 8}`)
+		_, err = n.LookupString("x:y")
+		Wish(t, err, ShouldEqual, ipld.ErrNotExists{Segment: ipld.PathSegmentOfString("x:y")})
+	})
+	t.Run("roundtrip", func(t *testing.T) {
+		Wish(t, encode(t, n.(schema.TypedNode).Representation()), ShouldEqual, fixture)
+	})
+	t.Run("copying", func(t *testing.T) {
+		// AssignNode from another implementation goes the generic way.
+		nb := demo.Style__T2{}.NewBuilder()
+		Require(t, nb.AssignNode(decode(t, demo.ReprStyle__T2{}, `{"a":1,"b":2,"c":3,"d":4}`)), ShouldEqual, nil)
+		Wish(t, ipld.Sprint(nb.Build()), ShouldEqual, `{"a": 1, "b": 2, "c": 3, "d": 4}`)
+		nb = demo.ReprStyle__Map_K2_T2{}.NewBuilder()
+		Require(t, nb.AssignNode(n.(schema.TypedNode).Representation()), ShouldEqual, nil)
+		Wish(t, encode(t, nb.Build().(schema.TypedNode).Representation()), ShouldEqual, fixture)
+	})
+}
+
+func TestMapK2T2Errors(t *testing.T) {
+	t.Run("repeated key", func(t *testing.T) {
+		nb := demo.ReprStyle__Map_K2_T2{}.NewBuilder()
+		ma, err := nb.BeginMap(2)
+		Require(t, err, ShouldEqual, nil)
+		va, err := ma.AssembleEntry("a:b")
+		Require(t, err, ShouldEqual, nil)
+		Require(t, va.AssignNode(decode(t, demo.ReprStyle__T2{}, `{"a":1,"b":2,"c":3,"d":4}`)), ShouldEqual, nil)
+		_, err = ma.AssembleEntry("a:b")
+		Wish(t, err, ShouldBeSameTypeAs, ipld.ErrRepeatedMapKey{})
+		if err != nil {
+			Wish(t, err.Error(), ShouldEqual, `cannot repeat map key ({"u": "a", "i": "b"})`)
+		}
+		Require(t, ma.Finish(), ShouldEqual, nil)
+		Wish(t, nb.Build().Length(), ShouldEqual, 1)
+	})
+	t.Run("invalid key", func(t *testing.T) {
+		nb := demo.ReprStyle__Map_K2_T2{}.NewBuilder()
+		ma, _ := nb.BeginMap(1)
+		_, err := ma.AssembleEntry("ab")
+		Wish(t, err, ShouldBeSameTypeAs, schema.ErrInvalidData{})
+		if err != nil {
+			Wish(t, err.Error(), ShouldEqual, `invalid data at "": not a valid K2: expected 2 fields joined by ":", got 1`)
+		}
+		Wish(t, ma.AssembleKey().AssignInt(1), ShouldBeSameTypeAs, ipld.ErrWrongKind{})
+		Require(t, ma.Finish(), ShouldEqual, nil)
+		Wish(t, nb.Build().Length(), ShouldEqual, 0)
+	})
+	t.Run("failed value is dropped", func(t *testing.T) {
+		nb := demo.ReprStyle__Map_K2_T2{}.NewBuilder()
+		ma, _ := nb.BeginMap(1)
+		va, err := ma.AssembleEntry("a:b")
+		Require(t, err, ShouldEqual, nil)
+		Wish(t, va.AssignString("nope"), ShouldBeSameTypeAs, ipld.ErrWrongKind{})
+		// The key can be used again, now that its entry is gone.
+		va, err = ma.AssembleEntry("a:b")
+		Require(t, err, ShouldEqual, nil)
+		Require(t, va.AssignNode(decode(t, demo.ReprStyle__T2{}, `{"a":1,"b":2,"c":3,"d":4}`)), ShouldEqual, nil)
+		Require(t, ma.Finish(), ShouldEqual, nil)
+		Wish(t, encode(t, nb.Build().(schema.TypedNode).Representation()), ShouldEqual, `{"a:b":{"a":1,"b":2,"c":3,"d":4}}`)
+	})
+	t.Run("field containing the separator", func(t *testing.T) {
+		// K2{u:"a:b", i:"c"} would be represented as "a:b:c", which can't be split back into it.
+		nb := demo.Style__K2{}.NewBuilder()
+		ma, _ := nb.BeginMap(2)
+		va, _ := ma.AssembleEntry("u")
+		err := va.AssignString("a:b")
+		Wish(t, err, ShouldBeSameTypeAs, schema.ErrInvalidData{})
+		if err != nil {
+			Wish(t, err.Error(), ShouldEqual, `invalid data at "": not a valid K2: field "u" contains the separator ":"`)
+		}
+		// The field can be assigned again, and then the K2 round-trips through its representation.
+		va, err = ma.AssembleEntry("u")
+		Require(t, err, ShouldEqual, nil)
+		Require(t, va.AssignString("a"), ShouldEqual, nil)
+		va, _ = ma.AssembleEntry("i")
+		Require(t, va.AssignString("c"), ShouldEqual, nil)
+		Require(t, ma.Finish(), ShouldEqual, nil)
+		n := nb.Build()
+		repr := encode(t, n.(schema.TypedNode).Representation())
+		Wish(t, repr, ShouldEqual, `"a:c"`)
+		Wish(t, ipld.DeepEqual(decode(t, demo.ReprStyle__K2{}, repr), n), ShouldEqual, true)
+
+		err = dagjson.Decoder(demo.Style__K2{}.NewBuilder(), strings.NewReader(`{"u":"a","i":"b:c"}`))
+		Wish(t, err, ShouldBeSameTypeAs, schema.ErrInvalidData{})
+	})
+	t.Run("missing and invalid fields", func(t *testing.T) {
+		nb := demo.Style__T2{}.NewBuilder()
+		ma, _ := nb.BeginMap(4)
+		_, err := ma.AssembleEntry("z")
+		Wish(t, err, ShouldEqual, ipld.ErrInvalidStructKey{TypeName: "demo.T2", Key: "z"})
+		va, _ := ma.AssembleEntry("a")
+		Require(t, va.AssignInt(1), ShouldEqual, nil)
+		_, err = ma.AssembleEntry("a")
+		Wish(t, err, ShouldBeSameTypeAs, ipld.ErrRepeatedMapKey{})
+		Wish(t, ma.Finish(), ShouldEqual, ipld.ErrMissingRequiredField{TypeName: "demo.T2", Missing: []string{"b", "c", "d"}})
+	})
+}
+
+func TestNesting(t *testing.T) {
+	t.Run("Root", func(t *testing.T) {
+		json := `{"entries":` + fixture + `,"L":"x"}`
+		n := decode(t, demo.ReprStyle__Root{}, json)
+		Wish(t, ipld.Sprint(n.(*demo.Root).FieldLabel()), ShouldEqual, `"x"`)
+		Wish(t, n.(*demo.Root).FieldEntries().Length(), ShouldEqual, 2)
+		tests.CheckConformance(t, n.(schema.TypedNode).Representation())
+		Wish(t, encode(t, n.(schema.TypedNode).Representation()), ShouldEqual, json)
+
+		// At the type level, the keys are the field names.
+		_, err := n.LookupString("L")
+		Wish(t, err, ShouldEqual, ipld.ErrNotExists{Segment: ipld.PathSegmentOfString("L")})
+		v, err := n.LookupString("label")
+		Wish(t, err, ShouldEqual, nil)
+		Wish(t, ipld.Sprint(v), ShouldEqual, `"x"`)
+	})
+	t.Run("Labels", func(t *testing.T) {
+		json := `{"x":"a:b","y":"c:d"}`
+		tests.SpecTestConformance(t, demo.ReprStyle__Labels{}, []string{json})
+		n := decode(t, demo.ReprStyle__Labels{}, json)
+		tests.CheckConformance(t, n.(schema.TypedNode).Representation())
+		Wish(t, encode(t, n.(schema.TypedNode).Representation()), ShouldEqual, json)
+		v, err := n.LookupString("y")
+		Wish(t, err, ShouldEqual, nil)
+		Wish(t, ipld.Sprint(v), ShouldEqual, `{"u": "c", "i": "d"}`)
+	})
+}
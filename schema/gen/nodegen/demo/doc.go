@@ -0,0 +1,6 @@
+// Package demo holds the code nodegen generates for the types in demo.ipldsch,
+// and tests of how that code behaves (including the node conformance tests).
+//
+// The generated code is kept up to date by the nodegen package's tests;
+// to regenerate it, run `go test -run TestDemo -update` in the nodegen package.
+package demo
@@ -0,0 +1,329 @@
+package nodegen
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"text/template"
+)
+
+// emit writes the whole file (unformatted; Generate runs it through gofmt).
+func (g *generator) emit(w io.Writer) {
+	doTemplate(w, tmplHeader, g)
+	if g.NeedsStructs {
+		doTemplate(w, tmplStructSupport, g)
+	}
+	for _, t := range g.types {
+		switch {
+		case t.IsScalar():
+			doTemplate(w, tmplScalar, t)
+		case t.Kind == "Struct":
+			doTemplate(w, tmplStruct, t)
+		case t.Kind == "Map":
+			doTemplate(w, tmplMap, t)
+		}
+	}
+}
+
+func doTemplate(w io.Writer, tmpl *template.Template, data interface{}) {
+	if err := tmpl.Execute(w, data); err != nil {
+		panic(err) // the templates are fixed, so any error is a bug.
+	}
+}
+
+var funcs = template.FuncMap{
+	"wrongAs":     wrongAs,
+	"wrongAssign": wrongAssign,
+	"child":       child,
+	"quote":       func(s string) string { return fmt.Sprintf("%q", s) },
+	"title":       func(s string) string { return strings.ToUpper(s[:1]) + s[1:] },
+}
+
+func mustTemplate(src string) *template.Template {
+	return template.Must(template.New("").Funcs(funcs).Parse(src))
+}
+
+var kinds = []struct{ name, goType, zero string }{
+	{"Bool", "bool", "false"},
+	{"Int", "int", "0"},
+	{"Float", "float64", "0"},
+	{"String", "string", `""`},
+	{"Bytes", "[]byte", "nil"},
+	{"Link", "ipld.Link", "nil"},
+}
+
+// wrongAs returns the As* methods (except the one for the kind given as own)
+// for a node type, each returning the error from the given mixin.
+func wrongAs(recv, mixin, own string) string {
+	var sb strings.Builder
+	for _, k := range kinds {
+		if k.name == own {
+			continue
+		}
+		fmt.Fprintf(&sb, "func (%s) As%s() (%s, error) {\n\treturn %s.As%s()\n}\n", recv, k.name, k.goType, mixin, k.name)
+	}
+	return sb.String()
+}
+
+// wrongAssign returns the Begin* and Assign* methods (except those listed as own)
+// for an assembler type, each returning the error from the given mixin,
+// after telling the assembler's parent (if any) that it's done.
+// If reprMixin isn't empty, it's used instead when the assembler is in repr mode.
+func wrongAssign(typ, mixin, reprMixin string, own ...string) string {
+	var sb strings.Builder
+	isOwn := func(method string) bool {
+		for _, o := range own {
+			if o == method {
+				return true
+			}
+		}
+		return false
+	}
+	for _, method := range []string{"BeginMap", "BeginList"} {
+		if isOwn(method) {
+			continue
+		}
+		ret := strings.TrimPrefix(method, "Begin") + "Assembler"
+		fmt.Fprintf(&sb, "func (na *%s) %s(int) (ipld.%s, error) {\n", typ, method, ret)
+		if reprMixin != "" {
+			fmt.Fprintf(&sb, "\tif na.repr {\n\t\t_, err := %s.%s(0)\n\t\treturn nil, na.done(err)\n\t}\n", reprMixin, method)
+		}
+		fmt.Fprintf(&sb, "\t_, err := %s.%s(0)\n\treturn nil, na.done(err)\n}\n", mixin, method)
+	}
+	methods := []struct{ name, arg, zero string }{{"AssignNull", "", ""}}
+	for _, k := range kinds {
+		methods = append(methods, struct{ name, arg, zero string }{"Assign" + k.name, k.goType, k.zero})
+	}
+	for _, m := range methods {
+		if isOwn(m.name) {
+			continue
+		}
+		fmt.Fprintf(&sb, "func (na *%s) %s(%s) error {\n", typ, m.name, m.arg)
+		if reprMixin != "" {
+			fmt.Fprintf(&sb, "\tif na.repr {\n\t\treturn na.done(%s.%s(%s))\n\t}\n", reprMixin, m.name, m.zero)
+		}
+		fmt.Fprintf(&sb, "\treturn na.done(%s.%s(%s))\n}\n", mixin, m.name, m.zero)
+	}
+	return sb.String()
+}
+
+// child returns an expression for a fresh assembler for type t, filling w,
+// and reporting to the parent p (if not empty), in repr mode if repr is true (or an expression which says).
+// Scalar assemblers have no repr mode (their representation is the same as the type-level node).
+func child(t *genType, w, p, repr string) string {
+	s := "_" + t.Name + "__Assembler{w: " + w
+	if p != "" {
+		s += ", p: " + p
+	}
+	if !t.IsScalar() && repr != "false" {
+		s += ", repr: " + repr
+	}
+	return s + "}"
+}
+
+var tmplHeader = mustTemplate(`// Code generated by nodegen. DO NOT EDIT.
+
+package {{ .Pkg }}
+
+import (
+	{{- if .NeedsStrings }}
+	"strconv"
+	"strings"
+	{{ end }}
+	ipld "github.com/ipld/go-ipld-prime"
+	{{- if .NeedsStructs }}
+	basicnode "github.com/ipld/go-ipld-prime/node/basic"
+	{{- end }}
+	"github.com/ipld/go-ipld-prime/node/mixins"
+	"github.com/ipld/go-ipld-prime/schema"
+)
+
+// maState is the state of a map (or struct) assembler.
+type maState uint8
+
+const (
+	maState_initial     maState = iota // also the state after each entry is complete.
+	maState_midKey                     // waiting for the key assembler to finish.
+	maState_expectValue                // the key is done; AssembleValue is next.
+	maState_midValue                   // waiting for the value assembler to finish.
+	maState_finished                   // Finish was called (or the whole value was assigned at once).
+)
+
+// _parent is implemented by the assemblers of maps and structs,
+// so that the assemblers of the keys and values inside them can report when they're done.
+// childDone is given the error (if any) which the child's assembly ended with,
+// and returns the error the child should return:
+// usually the same, but the parent may have a problem of its own (such as a repeated key).
+type _parent interface {
+	childDone(err error) error
+}
+
+// _copyEntries assembles all the entries of a map node, for the generic path of AssignNode.
+func _copyEntries(ma ipld.MapAssembler, n ipld.Node) error {
+	for itr := n.MapIterator(); !itr.Done(); {
+		k, v, err := itr.Next()
+		if err != nil {
+			return err
+		}
+		if err := ma.AssembleKey().AssignNode(k); err != nil {
+			return err
+		}
+		if err := ma.AssembleValue().AssignNode(v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+`)
+
+var tmplStructSupport = mustTemplate(`
+// _structAssembler is implemented by the assemblers of structs, so that they can share a key assembler.
+type _structAssembler interface {
+	prepareField(k string) error // finds the field for key k, or returns an error if it's not a valid key (or is repeated).
+	keyDone(err error) error     // called when the key assembler is done, with prepareField's error (or a wrong kind error).
+}
+
+type _structKeyAssembler struct {
+	ma _structAssembler
+}
+
+func (ka *_structKeyAssembler) done(err error) error {
+	return ka.ma.keyDone(err)
+}
+{{ wrongAssign "_structKeyAssembler" "mixins.StringAssembler{TypeName: \"string\"}" "" "AssignString" "AssignNode" }}
+func (ka *_structKeyAssembler) AssignString(k string) error {
+	return ka.ma.keyDone(ka.ma.prepareField(k))
+}
+func (ka *_structKeyAssembler) AssignNode(v ipld.Node) error {
+	k, err := v.AsString()
+	if err != nil {
+		return ka.ma.keyDone(err)
+	}
+	return ka.AssignString(k)
+}
+func (_structKeyAssembler) Style() ipld.NodeStyle {
+	return basicnode.Style__String{}
+}
+
+func _indexOf(keys []string, k string) int {
+	for i := range keys {
+		if keys[i] == k {
+			return i
+		}
+	}
+	return -1
+}
+`)
+
+var tmplScalar = mustTemplate(`
+// --- {{ .Name }}: {{ .Kind }} --->
+
+type {{ .Name }} {{ .GoType }}
+
+var Type__{{ .Name }} = schema.Spawn{{ .Kind }}("{{ .Name }}")
+
+var (
+	_ schema.TypedNode   = (*{{ .Name }})(nil)
+	_ ipld.NodeStyle     = Style__{{ .Name }}{}
+	_ ipld.NodeStyle     = ReprStyle__{{ .Name }}{}
+	_ ipld.NodeAssembler = (*_{{ .Name }}__Assembler)(nil)
+)
+
+func ({{ .Name }}) ReprKind() ipld.ReprKind {
+	return ipld.ReprKind_{{ .Kind }}
+}
+func ({{ .Name }}) LookupString(string) (ipld.Node, error) {
+	return mixins.{{ .Kind }}{TypeName: "{{ .Pkg }}.{{ .Name }}"}.LookupString("")
+}
+func ({{ .Name }}) Lookup(ipld.Node) (ipld.Node, error) {
+	return mixins.{{ .Kind }}{TypeName: "{{ .Pkg }}.{{ .Name }}"}.Lookup(nil)
+}
+func ({{ .Name }}) LookupIndex(int) (ipld.Node, error) {
+	return mixins.{{ .Kind }}{TypeName: "{{ .Pkg }}.{{ .Name }}"}.LookupIndex(0)
+}
+func ({{ .Name }}) LookupSegment(seg ipld.PathSegment) (ipld.Node, error) {
+	return mixins.{{ .Kind }}{TypeName: "{{ .Pkg }}.{{ .Name }}"}.LookupSegment(seg)
+}
+func ({{ .Name }}) MapIterator() ipld.MapIterator {
+	return nil
+}
+func ({{ .Name }}) ListIterator() ipld.ListIterator {
+	return nil
+}
+func ({{ .Name }}) Length() int {
+	return -1
+}
+func ({{ .Name }}) IsUndefined() bool {
+	return false
+}
+func ({{ .Name }}) IsNull() bool {
+	return false
+}
+{{ wrongAs .Name (printf "mixins.%s{TypeName: %q}" .Kind (printf "%s.%s" .Pkg .Name)) .Kind -}}
+func (n {{ .Name }}) As{{ .Kind }}() ({{ .GoType }}, error) {
+	return {{ .GoType }}(n), nil
+}
+func ({{ .Name }}) Style() ipld.NodeStyle {
+	return Style__{{ .Name }}{}
+}
+func ({{ .Name }}) Type() schema.Type {
+	return Type__{{ .Name }}
+}
+func (n {{ .Name }}) Representation() ipld.Node {
+	return n
+}
+
+type Style__{{ .Name }} struct{}
+
+func (Style__{{ .Name }}) NewBuilder() ipld.NodeBuilder {
+	var w {{ .Name }}
+	return &_{{ .Name }}__Builder{_{{ .Name }}__Assembler{w: &w}}
+}
+
+// ReprStyle__{{ .Name }} builds a {{ .Name }} from its representation, which for a {{ .Kind }} is the same as the type-level node.
+type ReprStyle__{{ .Name }} struct{}
+
+func (ReprStyle__{{ .Name }}) NewBuilder() ipld.NodeBuilder {
+	return Style__{{ .Name }}{}.NewBuilder()
+}
+
+type _{{ .Name }}__Builder struct {
+	_{{ .Name }}__Assembler
+}
+
+func (nb *_{{ .Name }}__Builder) Build() ipld.Node {
+	return nb.w
+}
+func (nb *_{{ .Name }}__Builder) Reset() {
+	var w {{ .Name }}
+	*nb = _{{ .Name }}__Builder{_{{ .Name }}__Assembler{w: &w}}
+}
+
+type _{{ .Name }}__Assembler struct {
+	w *{{ .Name }}
+	p _parent // if assembling a key or value within a map or struct, the assembler to tell when done.
+}
+
+func (na *_{{ .Name }}__Assembler) done(err error) error {
+	if na.p != nil {
+		err = na.p.childDone(err)
+		na.p = nil
+	}
+	return err
+}
+{{ wrongAssign (printf "_%s__Assembler" .Name) (printf "mixins.%sAssembler{TypeName: %q}" .Kind (printf "%s.%s" .Pkg .Name)) "" (printf "Assign%s" .Kind) "AssignNode" -}}
+func (na *_{{ .Name }}__Assembler) Assign{{ .Kind }}(v {{ .GoType }}) error {
+	*na.w = {{ .Name }}(v)
+	return na.done(nil)
+}
+func (na *_{{ .Name }}__Assembler) AssignNode(v ipld.Node) error {
+	v2, err := v.As{{ .Kind }}()
+	if err != nil {
+		return na.done(err)
+	}
+	return na.Assign{{ .Kind }}(v2)
+}
+func (_{{ .Name }}__Assembler) Style() ipld.NodeStyle {
+	return Style__{{ .Name }}{}
+}
+`)
@@ -0,0 +1,199 @@
+// Package nodegen generates Go source for Node implementations of schema types,
+// following the patterns prototyped by hand in the node/gendemo package:
+// concrete Go types for each schema type, with type-level and representation-level
+// nodes, NodeStyles, and assemblers which fill the concrete types directly.
+//
+// For each type T, the generated code has:
+//
+//   - the Go type T, which (as a pointer) is a schema.TypedNode;
+//   - Style__T, the NodeStyle for building T;
+//   - ReprStyle__T, the NodeStyle for building T from its representation
+//     (e.g. while decoding); it builds the same type-level node;
+//   - Type__T, the schema.Type of T.
+//
+// The subset of schemas supported so far is: the scalar kinds (except links);
+// structs (with the map or stringjoin representations) whose fields are neither optional nor nullable;
+// and maps, with keys which are strings or stringjoin structs, and values which aren't nullable.
+// Every type must be named (anonymous maps and lists aren't supported yet).
+//
+// The demo package holds the code generated for the types in demo/demo.ipldsch
+// (which include the K2, T2, and Map_K2_T2 types of node/gendemo), and tests of its behavior.
+package nodegen
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"go/token"
+	"io"
+
+	"github.com/ipld/go-ipld-prime/schema"
+	schemadsl "github.com/ipld/go-ipld-prime/schema/dsl"
+)
+
+// GenerateFromDSL parses schema DSL source (see the schemadsl package),
+// and generates code for the types it defines, as Generate does.
+func GenerateFromDSL(w io.Writer, pkgName string, src string) error {
+	ts, err := schemadsl.Parse(src)
+	if err != nil {
+		return err
+	}
+	return Generate(w, pkgName, ts)
+}
+
+// Generate writes a Go source file, in the package named pkgName,
+// with code for all the named types in the TypeSystem,
+// and for the prelude types (e.g. String and Int) which they use.
+//
+// If any type isn't supported (see the package docs), an error is returned and nothing is written.
+func Generate(w io.Writer, pkgName string, ts *schema.TypeSystem) error {
+	g := generator{Pkg: pkgName, seen: make(map[schema.TypeName]bool)}
+	for _, name := range ts.Names() {
+		if err := g.add(ts.TypeByName(name)); err != nil {
+			return err
+		}
+	}
+	var buf bytes.Buffer
+	g.emit(&buf)
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		return fmt.Errorf("nodegen: generated invalid code (this is a bug): %s", err)
+	}
+	_, err = w.Write(src)
+	return err
+}
+
+// generator collects the types to generate code for, checking each is supported.
+type generator struct {
+	Pkg   string
+	types []*genType
+	seen  map[schema.TypeName]bool // true once a type is started; it's only added to types once finished.
+
+	NeedsStrings bool // true if any type uses the strings package (for stringjoin).
+	NeedsStructs bool // true if there are any structs (which need basicnode, and the struct key assembler).
+}
+
+// genType is a type's information as the templates use it.
+type genType struct {
+	Pkg  string
+	Name string
+	Kind string // "String", "Int", "Float", "Bool", or "Bytes" for scalars; "Struct" or "Map".
+
+	GoType string // for scalars: the Go type of the value.
+
+	Fields     []genField // for structs.
+	StringJoin bool       // for structs: true if the representation is stringjoin, rather than map.
+	Sep        string     // for structs with stringjoin representation.
+	Renames    []genField // for structs with map representation: the fields whose key is renamed.
+
+	Key, Value *genType // for maps.
+}
+
+type genField struct {
+	Name    string
+	Index   int
+	ReprKey string
+	Type    *genType
+}
+
+// IsScalar is true for the scalar kinds, whose representation is the same as the type-level node.
+func (t *genType) IsScalar() bool {
+	return t.Kind != "Struct" && t.Kind != "Map"
+}
+
+var scalarGoTypes = map[string]string{"String": "string", "Int": "int", "Float": "float64", "Bool": "bool", "Bytes": "[]byte"}
+
+func unsupported(t schema.Type, format string, args ...interface{}) error {
+	return fmt.Errorf("nodegen: type %s: %s", t.Name(), fmt.Sprintf(format, args...))
+}
+
+// add adds a type (and the types it uses) to the set to be generated.
+func (g *generator) add(t schema.Type) error {
+	_, err := g.lookup(t)
+	return err
+}
+
+// lookup returns the genType for a type, adding it (and the types it uses) first if need be.
+func (g *generator) lookup(t schema.Type) (*genType, error) {
+	if g.seen[t.Name()] {
+		for _, gt := range g.types {
+			if gt.Name == string(t.Name()) {
+				return gt, nil
+			}
+		}
+		// Still being built: only possible for recursive types, which the schema package can't express yet.
+		return nil, unsupported(t, "recursive types are not supported")
+	}
+	g.seen[t.Name()] = true
+	name := string(t.Name())
+	if !token.IsIdentifier(name) || token.IsKeyword(name) {
+		return nil, unsupported(t, "the name isn't a valid Go identifier (anonymous types aren't supported yet; give it a name)")
+	}
+	gt := &genType{Pkg: g.Pkg, Name: name}
+	switch t2 := t.(type) {
+	case schema.TypeString, schema.TypeInt, schema.TypeFloat, schema.TypeBool, schema.TypeBytes:
+		gt.Kind = t.Kind().String()
+		gt.GoType = scalarGoTypes[gt.Kind]
+		if it, ok := t.(schema.TypeInt); ok && it.Bits() != 0 {
+			return nil, unsupported(t, "sized ints are not supported yet")
+		}
+	case schema.TypeStruct:
+		gt.Kind = "Struct"
+		g.NeedsStructs = true
+		renames := map[string]string{}
+		switch r := t2.RepresentationStrategy().(type) {
+		case schema.StructRepresentation_Map:
+			for _, f := range t2.Fields() {
+				if k := r.GetFieldKey(f); k != f.Name() {
+					renames[f.Name()] = k
+				}
+			}
+		case schema.StructRepresentation_StringJoin:
+			gt.StringJoin = true
+			gt.Sep = r.Separator()
+			g.NeedsStrings = true
+		default:
+			return nil, unsupported(t, "only the map and stringjoin struct representations are supported")
+		}
+		for i, f := range t2.Fields() {
+			if f.IsOptional() || f.IsNullable() {
+				return nil, unsupported(t, "field %s: optional and nullable fields are not supported yet", f.Name())
+			}
+			if !token.IsIdentifier(f.Name()) || token.IsKeyword(f.Name()) {
+				return nil, unsupported(t, "field %s: the name isn't a valid Go identifier", f.Name())
+			}
+			ft, err := g.lookup(f.Type())
+			if err != nil {
+				return nil, err
+			}
+			if gt.StringJoin && ft.Kind != "String" {
+				return nil, unsupported(t, "field %s: the fields of a stringjoin struct must be strings", f.Name())
+			}
+			gf := genField{Name: f.Name(), Index: i, ReprKey: f.Name(), Type: ft}
+			if k, ok := renames[f.Name()]; ok {
+				gf.ReprKey = k
+				gt.Renames = append(gt.Renames, gf)
+			}
+			gt.Fields = append(gt.Fields, gf)
+		}
+	case schema.TypeMap:
+		gt.Kind = "Map"
+		if t2.ValueIsNullable() {
+			return nil, unsupported(t, "nullable map values are not supported yet")
+		}
+		var err error
+		if gt.Key, err = g.lookup(t2.KeyType()); err != nil {
+			return nil, err
+		}
+		if gt.Key.Kind != "String" && !gt.Key.StringJoin {
+			return nil, unsupported(t, "map keys must be strings or stringjoin structs")
+		}
+		if gt.Value, err = g.lookup(t2.ValueType()); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, unsupported(t, "%s types are not supported yet", t.Kind())
+	}
+	g.types = append(g.types, gt)
+	return gt, nil
+}
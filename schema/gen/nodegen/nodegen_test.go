@@ -0,0 +1,51 @@
+package nodegen_test
+
+import (
+	"bytes"
+	"flag"
+	"io/ioutil"
+	"testing"
+
+	. "github.com/warpfork/go-wish"
+
+	"github.com/ipld/go-ipld-prime/schema/gen/nodegen"
+)
+
+var update = flag.Bool("update", false, "rewrite demo/demo.go, rather than checking it's up to date")
+
+// TestDemo checks that the generated code in the demo package (which has its own tests of how the code behaves)
+// is what the generator currently produces from demo/demo.ipldsch.
+func TestDemo(t *testing.T) {
+	src, err := ioutil.ReadFile("demo/demo.ipldsch")
+	Require(t, err, ShouldEqual, nil)
+	var buf bytes.Buffer
+	Require(t, nodegen.GenerateFromDSL(&buf, "demo", string(src)), ShouldEqual, nil)
+	if *update {
+		Require(t, ioutil.WriteFile("demo/demo.go", buf.Bytes(), 0644), ShouldEqual, nil)
+		return
+	}
+	current, err := ioutil.ReadFile("demo/demo.go")
+	Require(t, err, ShouldEqual, nil)
+	if !bytes.Equal(current, buf.Bytes()) {
+		t.Errorf("demo/demo.go is out of date; run `go test -run TestDemo -update` in this package")
+	}
+}
+
+func TestUnsupported(t *testing.T) {
+	for _, tc := range []struct{ src, err string }{
+		{`type S struct { a optional String }`, `nodegen: type S: field a: optional and nullable fields are not supported yet`},
+		{`type S struct { a [String] }`, `nodegen: type [String]: the name isn't a valid Go identifier (anonymous types aren't supported yet; give it a name)`},
+		{`type S struct { a Int, b Int } representation tuple`, `nodegen: type S: only the map and stringjoin struct representations are supported`},
+		{`type S struct { a Int, b Int } representation stringjoin (":")`, `nodegen: type S: field a: the fields of a stringjoin struct must be strings`},
+		{`type M {String:nullable Int}`, `nodegen: type M: nullable map values are not supported yet`},
+		{`type L [String]`, `nodegen: type L: List types are not supported yet`},
+		{`type S struct { type String }`, `nodegen: type S: field type: the name isn't a valid Go identifier`},
+	} {
+		err := nodegen.GenerateFromDSL(ioutil.Discard, "x", tc.src)
+		if err == nil {
+			t.Errorf("expected an error for %q", tc.src)
+			continue
+		}
+		Wish(t, err.Error(), ShouldEqual, tc.err)
+	}
+}
@@ -0,0 +1,351 @@
+package nodegen
+
+var tmplMap = mustTemplate(`
+{{- $T := .Name }}{{ $pkgT := printf "%s.%s" .Pkg .Name }}{{ $K := .Key.Name }}{{ $V := .Value.Name }}
+// --- {{ $T }}: Map --->
+
+// {{ $T }} keeps its entries in the order they were assembled.
+type {{ $T }} struct {
+	m map[{{ $K }}]int // the index of each key's entry in t.
+	t []_{{ $T }}__entry
+}
+
+type _{{ $T }}__entry struct {
+	k {{ $K }}
+	v {{ $V }}
+}
+
+var Type__{{ $T }} = schema.SpawnMap("{{ $T }}", Type__{{ $K }}, Type__{{ $V }}, false)
+
+var (
+	_ schema.TypedNode  = (*{{ $T }})(nil)
+	_ ipld.Node         = (*_{{ $T }}__Repr)(nil)
+	_ ipld.NodeStyle    = Style__{{ $T }}{}
+	_ ipld.NodeStyle    = ReprStyle__{{ $T }}{}
+	_ ipld.MapAssembler = (*_{{ $T }}__Assembler)(nil)
+	_ _parent           = (*_{{ $T }}__Assembler)(nil)
+)
+
+// Get returns the value for a key, or ErrNotExists if there's no such entry.
+func (n *{{ $T }}) Get(key *{{ $K }}) (*{{ $V }}, error) {
+	i, exists := n.m[*key]
+	if !exists {
+		ks, _ := key.Representation().AsString()
+		return nil, ipld.ErrNotExists{Segment: ipld.PathSegmentOfString(ks)}
+	}
+	return &n.t[i].v, nil
+}
+
+func ({{ $T }}) ReprKind() ipld.ReprKind {
+	return ipld.ReprKind_Map
+}
+
+// LookupString reads the key as the representation of a {{ $K }}.
+// A string which isn't a valid {{ $K }} can't be a key in the map, so returns ErrNotExists.
+func (n *{{ $T }}) LookupString(key string) (ipld.Node, error) {
+	var k {{ $K }}
+	ka := {{ child .Key "&k" "" "true" }}
+	if err := ka.AssignString(key); err != nil {
+		return nil, ipld.ErrNotExists{Segment: ipld.PathSegmentOfString(key)}
+	}
+	return n.lookup(&k)
+}
+func (n *{{ $T }}) Lookup(key ipld.Node) (ipld.Node, error) {
+	if k, ok := key.(*{{ $K }}); ok {
+		return n.lookup(k)
+	}
+	var k {{ $K }}
+	ka := {{ child .Key "&k" "" "false" }}
+	if err := ka.AssignNode(key); err != nil {
+		return nil, err
+	}
+	return n.lookup(&k)
+}
+func (n *{{ $T }}) lookup(k *{{ $K }}) (ipld.Node, error) {
+	v, err := n.Get(k)
+	if err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+func ({{ $T }}) LookupIndex(int) (ipld.Node, error) {
+	return mixins.Map{TypeName: "{{ $pkgT }}"}.LookupIndex(0)
+}
+func (n *{{ $T }}) LookupSegment(seg ipld.PathSegment) (ipld.Node, error) {
+	return n.LookupString(seg.String())
+}
+func (n *{{ $T }}) MapIterator() ipld.MapIterator {
+	return &_{{ $T }}__MapItr{n: n}
+}
+func ({{ $T }}) ListIterator() ipld.ListIterator {
+	return nil
+}
+func (n *{{ $T }}) Length() int {
+	return len(n.t)
+}
+func ({{ $T }}) IsUndefined() bool {
+	return false
+}
+func ({{ $T }}) IsNull() bool {
+	return false
+}
+{{ wrongAs $T (printf "mixins.Map{TypeName: %q}" $pkgT) "" -}}
+func ({{ $T }}) Style() ipld.NodeStyle {
+	return Style__{{ $T }}{}
+}
+func ({{ $T }}) Type() schema.Type {
+	return Type__{{ $T }}
+}
+func (n *{{ $T }}) Representation() ipld.Node {
+	return &_{{ $T }}__Repr{n}
+}
+
+// _{{ $T }}__MapItr iterates over the entries of a {{ $T }}; or, in repr mode, over their representations.
+type _{{ $T }}__MapItr struct {
+	n    *{{ $T }}
+	repr bool
+	idx  int
+}
+
+func (itr *_{{ $T }}__MapItr) Next() (k ipld.Node, v ipld.Node, _ error) {
+	if itr.idx >= len(itr.n.t) {
+		return nil, nil, ipld.ErrIteratorOverread{}
+	}
+	e := &itr.n.t[itr.idx]
+	if itr.repr {
+		k, v = e.k.Representation(), e.v.Representation()
+	} else {
+		k, v = &e.k, &e.v
+	}
+	itr.idx++
+	return
+}
+func (itr *_{{ $T }}__MapItr) Done() bool {
+	return itr.idx >= len(itr.n.t)
+}
+
+// _{{ $T }}__Repr is the representation of a {{ $T }}: a map, keyed by the representations of the keys, with the representations of the values.
+type _{{ $T }}__Repr struct {
+	n *{{ $T }}
+}
+
+func (_{{ $T }}__Repr) ReprKind() ipld.ReprKind {
+	return ipld.ReprKind_Map
+}
+func (r _{{ $T }}__Repr) LookupString(key string) (ipld.Node, error) {
+	v, err := r.n.LookupString(key)
+	if err != nil {
+		return nil, err
+	}
+	return v.(*{{ $V }}).Representation(), nil
+}
+func (r _{{ $T }}__Repr) Lookup(key ipld.Node) (ipld.Node, error) {
+	ks, err := key.AsString()
+	if err != nil {
+		return nil, err
+	}
+	return r.LookupString(ks)
+}
+func (_{{ $T }}__Repr) LookupIndex(int) (ipld.Node, error) {
+	return mixins.Map{TypeName: "{{ $pkgT }}.Repr"}.LookupIndex(0)
+}
+func (r _{{ $T }}__Repr) LookupSegment(seg ipld.PathSegment) (ipld.Node, error) {
+	return r.LookupString(seg.String())
+}
+func (r _{{ $T }}__Repr) MapIterator() ipld.MapIterator {
+	return &_{{ $T }}__MapItr{n: r.n, repr: true}
+}
+func (_{{ $T }}__Repr) ListIterator() ipld.ListIterator {
+	return nil
+}
+func (r _{{ $T }}__Repr) Length() int {
+	return len(r.n.t)
+}
+func (_{{ $T }}__Repr) IsUndefined() bool {
+	return false
+}
+func (_{{ $T }}__Repr) IsNull() bool {
+	return false
+}
+{{ wrongAs (printf "_%s__Repr" $T) (printf "mixins.Map{TypeName: %q}" (printf "%s.Repr" $pkgT)) "" -}}
+func (_{{ $T }}__Repr) Style() ipld.NodeStyle {
+	return ReprStyle__{{ $T }}{}
+}
+
+type Style__{{ $T }} struct{}
+
+func (Style__{{ $T }}) NewBuilder() ipld.NodeBuilder {
+	var w {{ $T }}
+	return &_{{ $T }}__Builder{_{{ $T }}__Assembler{w: &w}}
+}
+
+// ReprStyle__{{ $T }} builds a {{ $T }} from its representation.
+type ReprStyle__{{ $T }} struct{}
+
+func (ReprStyle__{{ $T }}) NewBuilder() ipld.NodeBuilder {
+	var w {{ $T }}
+	return &_{{ $T }}__Builder{_{{ $T }}__Assembler{w: &w, repr: true}}
+}
+
+type _{{ $T }}__Builder struct {
+	_{{ $T }}__Assembler
+}
+
+func (nb *_{{ $T }}__Builder) Build() ipld.Node {
+	if nb.state != maState_finished {
+		panic("invalid state: assembler must be 'finished' before Build can be called!")
+	}
+	return nb.w
+}
+func (nb *_{{ $T }}__Builder) Reset() {
+	var w {{ $T }}
+	*nb = _{{ $T }}__Builder{_{{ $T }}__Assembler{w: &w, repr: nb.repr}}
+}
+
+type _{{ $T }}__Assembler struct {
+	w     *{{ $T }}
+	p     _parent // if assembling a key or value within a map or struct, the assembler to tell when done.
+	repr  bool    // if true, assembling from the representation (with the keys' and values' representations).
+	state maState
+	ka    _{{ $K }}__Assembler
+	va    _{{ $V }}__Assembler
+}
+
+func (na *_{{ $T }}__Assembler) done(err error) error {
+	if na.p != nil {
+		err = na.p.childDone(err)
+		na.p = nil
+	}
+	return err
+}
+
+func (na *_{{ $T }}__Assembler) BeginMap(sizeHint int) (ipld.MapAssembler, error) {
+	if sizeHint < 0 {
+		sizeHint = 0
+	}
+	na.w.m = make(map[{{ $K }}]int, sizeHint)
+	na.w.t = make([]_{{ $T }}__entry, 0, sizeHint)
+	return na, nil
+}
+{{ wrongAssign (printf "_%s__Assembler" $T) (printf "mixins.MapAssembler{TypeName: %q}" $pkgT) "" "BeginMap" "AssignNode" -}}
+func (na *_{{ $T }}__Assembler) AssignNode(v ipld.Node) error {
+	if v2, ok := v.(*{{ $T }}); ok && !na.repr {
+		*na.w = *v2
+		na.state = maState_finished
+		return na.done(nil)
+	}
+	if v2, ok := v.(*_{{ $T }}__Repr); ok && na.repr {
+		*na.w = *v2.n
+		na.state = maState_finished
+		return na.done(nil)
+	}
+	if v.ReprKind() != ipld.ReprKind_Map {
+		return na.done(ipld.ErrWrongKind{TypeName: "{{ $pkgT }}", MethodName: "AssignNode", AppropriateKind: ipld.ReprKindSet_JustMap, ActualKind: v.ReprKind()})
+	}
+	ma, _ := na.BeginMap(v.Length())
+	if err := _copyEntries(ma, v); err != nil {
+		return na.done(err)
+	}
+	return ma.Finish()
+}
+func (na *_{{ $T }}__Assembler) Style() ipld.NodeStyle {
+	if na.repr {
+		return ReprStyle__{{ $T }}{}
+	}
+	return Style__{{ $T }}{}
+}
+
+// addKey indexes the key of the last entry; or, if the key is repeated, drops the entry.
+func (na *_{{ $T }}__Assembler) addKey() error {
+	i := len(na.w.t) - 1
+	k := na.w.t[i].k
+	if _, exists := na.w.m[k]; exists {
+		na.w.t = na.w.t[:i]
+		return ipld.ErrRepeatedMapKey{Key: &k}
+	}
+	na.w.m[k] = i
+	return nil
+}
+func (na *_{{ $T }}__Assembler) childDone(err error) error {
+	i := len(na.w.t) - 1
+	switch na.state {
+	case maState_midKey:
+		if err == nil {
+			err = na.addKey()
+		} else {
+			na.w.t = na.w.t[:i]
+		}
+		if err != nil {
+			na.state = maState_initial
+			return err
+		}
+		na.state = maState_expectValue
+		return nil
+	case maState_midValue:
+		if err != nil {
+			delete(na.w.m, na.w.t[i].k)
+			na.w.t = na.w.t[:i]
+		}
+		na.state = maState_initial
+		return err
+	default:
+		panic("unreachable")
+	}
+}
+
+// AssembleEntry reads the key as the representation of a {{ $K }}.
+func (na *_{{ $T }}__Assembler) AssembleEntry(k string) (ipld.NodeAssembler, error) {
+	if na.state != maState_initial {
+		panic("misuse")
+	}
+	na.w.t = append(na.w.t, _{{ $T }}__entry{})
+	e := &na.w.t[len(na.w.t)-1]
+	ka := {{ child .Key "&e.k" "" "true" }}
+	if err := ka.AssignString(k); err != nil {
+		na.w.t = na.w.t[:len(na.w.t)-1]
+		return nil, err
+	}
+	if err := na.addKey(); err != nil {
+		return nil, err
+	}
+	na.state = maState_midValue
+	na.va = {{ child .Value "&e.v" "na" "na.repr" }}
+	return &na.va, nil
+}
+func (na *_{{ $T }}__Assembler) AssembleKey() ipld.NodeAssembler {
+	if na.state != maState_initial {
+		panic("misuse")
+	}
+	na.w.t = append(na.w.t, _{{ $T }}__entry{})
+	na.state = maState_midKey
+	na.ka = {{ child .Key "&na.w.t[len(na.w.t)-1].k" "na" "na.repr" }}
+	return &na.ka
+}
+func (na *_{{ $T }}__Assembler) AssembleValue() ipld.NodeAssembler {
+	if na.state != maState_expectValue {
+		panic("misuse")
+	}
+	na.state = maState_midValue
+	na.va = {{ child .Value "&na.w.t[len(na.w.t)-1].v" "na" "na.repr" }}
+	return &na.va
+}
+func (na *_{{ $T }}__Assembler) Finish() error {
+	if na.state != maState_initial {
+		panic("misuse")
+	}
+	na.state = maState_finished
+	return na.done(nil)
+}
+func (na *_{{ $T }}__Assembler) KeyStyle() ipld.NodeStyle {
+	if na.repr {
+		return ReprStyle__{{ $K }}{}
+	}
+	return Style__{{ $K }}{}
+}
+func (na *_{{ $T }}__Assembler) ValueStyle(string) ipld.NodeStyle {
+	if na.repr {
+		return ReprStyle__{{ $V }}{}
+	}
+	return Style__{{ $V }}{}
+}
+`)
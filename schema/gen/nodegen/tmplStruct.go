@@ -0,0 +1,492 @@
+package nodegen
+
+var tmplStruct = mustTemplate(`
+{{- $T := .Name }}{{ $pkgT := printf "%s.%s" .Pkg .Name }}{{ $N := len .Fields }}
+// --- {{ $T }}: Struct --->
+
+type {{ $T }} struct {
+{{- range .Fields }}
+	{{ .Name }} {{ .Type.Name }}
+{{- end }}
+}
+
+var Type__{{ $T }} = schema.SpawnStruct("{{ $T }}",
+	[]schema.StructField{
+{{- range .Fields }}
+		schema.SpawnStructField("{{ .Name }}", Type__{{ .Type.Name }}, false, false),
+{{- end }}
+	},
+{{- if .StringJoin }}
+	schema.SpawnStructRepresentationStringJoin({{ quote .Sep }}),
+{{- else if .Renames }}
+	schema.SpawnStructRepresentationMap(map[string]string{
+{{- range .Renames }}
+		"{{ .Name }}": {{ quote .ReprKey }},
+{{- end }}
+	}),
+{{- else }}
+	schema.SpawnStructRepresentationMap(nil),
+{{- end }}
+)
+
+var (
+	_ schema.TypedNode  = (*{{ $T }})(nil)
+	_ ipld.Node         = (*_{{ $T }}__Repr)(nil)
+	_ ipld.NodeStyle    = Style__{{ $T }}{}
+	_ ipld.NodeStyle    = ReprStyle__{{ $T }}{}
+	_ ipld.MapAssembler = (*_{{ $T }}__Assembler)(nil)
+	_ _parent           = (*_{{ $T }}__Assembler)(nil)
+	_ _structAssembler  = (*_{{ $T }}__Assembler)(nil)
+)
+
+var (
+	_{{ $T }}__FieldNames     = [{{ $N }}]string{ {{- range $i, $f := .Fields }}{{ if $i }}, {{ end }}"{{ $f.Name }}"{{ end -}} }
+	_{{ $T }}__FieldNameNodes = [{{ $N }}]ipld.Node{ {{- range $i, $f := .Fields }}{{ if $i }}, {{ end }}basicnode.NewString("{{ $f.Name }}"){{ end -}} }
+{{- if not .StringJoin }}
+	_{{ $T }}__ReprKeys     = [{{ $N }}]string{ {{- range $i, $f := .Fields }}{{ if $i }}, {{ end }}{{ quote $f.ReprKey }}{{ end -}} }
+	_{{ $T }}__ReprKeyNodes = [{{ $N }}]ipld.Node{ {{- range $i, $f := .Fields }}{{ if $i }}, {{ end }}basicnode.NewString({{ quote $f.ReprKey }}){{ end -}} }
+{{- end }}
+)
+{{ range .Fields }}
+func (n *{{ $T }}) Field{{ title .Name }}() *{{ .Type.Name }} {
+	return &n.{{ .Name }}
+}
+{{- end }}
+
+// field returns the field with the given index.
+func (n *{{ $T }}) field(idx int) ipld.Node {
+	switch idx {
+{{- range .Fields }}
+	case {{ .Index }}:
+		return &n.{{ .Name }}
+{{- end }}
+	default:
+		panic("unreachable")
+	}
+}
+
+// fieldRepr returns the representation of the field with the given index.
+func (n *{{ $T }}) fieldRepr(idx int) ipld.Node {
+	switch idx {
+{{- range .Fields }}
+	case {{ .Index }}:
+		return n.{{ .Name }}.Representation()
+{{- end }}
+	default:
+		panic("unreachable")
+	}
+}
+
+func ({{ $T }}) ReprKind() ipld.ReprKind {
+	return ipld.ReprKind_Map
+}
+func (n *{{ $T }}) LookupString(key string) (ipld.Node, error) {
+	idx := _indexOf(_{{ $T }}__FieldNames[:], key)
+	if idx < 0 {
+		return nil, ipld.ErrNotExists{Segment: ipld.PathSegmentOfString(key)}
+	}
+	return n.field(idx), nil
+}
+func (n *{{ $T }}) Lookup(key ipld.Node) (ipld.Node, error) {
+	ks, err := key.AsString()
+	if err != nil {
+		return nil, err
+	}
+	return n.LookupString(ks)
+}
+func ({{ $T }}) LookupIndex(int) (ipld.Node, error) {
+	return mixins.Map{TypeName: "{{ $pkgT }}"}.LookupIndex(0)
+}
+func (n *{{ $T }}) LookupSegment(seg ipld.PathSegment) (ipld.Node, error) {
+	return n.LookupString(seg.String())
+}
+func (n *{{ $T }}) MapIterator() ipld.MapIterator {
+	return &_{{ $T }}__MapItr{n: n}
+}
+func ({{ $T }}) ListIterator() ipld.ListIterator {
+	return nil
+}
+func ({{ $T }}) Length() int {
+	return {{ $N }}
+}
+func ({{ $T }}) IsUndefined() bool {
+	return false
+}
+func ({{ $T }}) IsNull() bool {
+	return false
+}
+{{ wrongAs $T (printf "mixins.Map{TypeName: %q}" $pkgT) "" -}}
+func ({{ $T }}) Style() ipld.NodeStyle {
+	return Style__{{ $T }}{}
+}
+func ({{ $T }}) Type() schema.Type {
+	return Type__{{ $T }}
+}
+func (n *{{ $T }}) Representation() ipld.Node {
+	return &_{{ $T }}__Repr{n}
+}
+
+// _{{ $T }}__MapItr iterates over the fields of a {{ $T }}{{ if not .StringJoin }}; or, in repr mode, over its representation{{ end }}.
+type _{{ $T }}__MapItr struct {
+	n    *{{ $T }}
+	repr bool
+	idx  int
+}
+
+func (itr *_{{ $T }}__MapItr) Next() (k ipld.Node, v ipld.Node, _ error) {
+	if itr.idx >= {{ $N }} {
+		return nil, nil, ipld.ErrIteratorOverread{}
+	}
+{{- if .StringJoin }}
+	k, v = _{{ $T }}__FieldNameNodes[itr.idx], itr.n.field(itr.idx)
+{{- else }}
+	if itr.repr {
+		k, v = _{{ $T }}__ReprKeyNodes[itr.idx], itr.n.fieldRepr(itr.idx)
+	} else {
+		k, v = _{{ $T }}__FieldNameNodes[itr.idx], itr.n.field(itr.idx)
+	}
+{{- end }}
+	itr.idx++
+	return
+}
+func (itr *_{{ $T }}__MapItr) Done() bool {
+	return itr.idx >= {{ $N }}
+}
+{{ if .StringJoin }}
+// _{{ $T }}__Repr is the representation of a {{ $T }}: a string, of its fields joined by {{ quote .Sep }}.
+type _{{ $T }}__Repr struct {
+	n *{{ $T }}
+}
+
+func (_{{ $T }}__Repr) ReprKind() ipld.ReprKind {
+	return ipld.ReprKind_String
+}
+func (_{{ $T }}__Repr) LookupString(string) (ipld.Node, error) {
+	return mixins.String{TypeName: "{{ $pkgT }}.Repr"}.LookupString("")
+}
+func (_{{ $T }}__Repr) Lookup(ipld.Node) (ipld.Node, error) {
+	return mixins.String{TypeName: "{{ $pkgT }}.Repr"}.Lookup(nil)
+}
+func (_{{ $T }}__Repr) LookupIndex(int) (ipld.Node, error) {
+	return mixins.String{TypeName: "{{ $pkgT }}.Repr"}.LookupIndex(0)
+}
+func (_{{ $T }}__Repr) LookupSegment(seg ipld.PathSegment) (ipld.Node, error) {
+	return mixins.String{TypeName: "{{ $pkgT }}.Repr"}.LookupSegment(seg)
+}
+func (_{{ $T }}__Repr) MapIterator() ipld.MapIterator {
+	return nil
+}
+func (_{{ $T }}__Repr) ListIterator() ipld.ListIterator {
+	return nil
+}
+func (_{{ $T }}__Repr) Length() int {
+	return -1
+}
+func (_{{ $T }}__Repr) IsUndefined() bool {
+	return false
+}
+func (_{{ $T }}__Repr) IsNull() bool {
+	return false
+}
+{{ wrongAs (printf "_%s__Repr" $T) (printf "mixins.String{TypeName: %q}" (printf "%s.Repr" $pkgT)) "String" -}}
+func (r _{{ $T }}__Repr) AsString() (string, error) {
+	return {{ range $i, $f := .Fields }}{{ if $i }} + {{ quote $.Sep }} + {{ end }}string(r.n.{{ $f.Name }}){{ end }}, nil
+}
+func (_{{ $T }}__Repr) Style() ipld.NodeStyle {
+	return ReprStyle__{{ $T }}{}
+}
+{{ else }}
+// _{{ $T }}__Repr is the representation of a {{ $T }}: a map, keyed by the fields' representation keys.
+type _{{ $T }}__Repr struct {
+	n *{{ $T }}
+}
+
+func (_{{ $T }}__Repr) ReprKind() ipld.ReprKind {
+	return ipld.ReprKind_Map
+}
+func (r _{{ $T }}__Repr) LookupString(key string) (ipld.Node, error) {
+	idx := _indexOf(_{{ $T }}__ReprKeys[:], key)
+	if idx < 0 {
+		return nil, ipld.ErrNotExists{Segment: ipld.PathSegmentOfString(key)}
+	}
+	return r.n.fieldRepr(idx), nil
+}
+func (r _{{ $T }}__Repr) Lookup(key ipld.Node) (ipld.Node, error) {
+	ks, err := key.AsString()
+	if err != nil {
+		return nil, err
+	}
+	return r.LookupString(ks)
+}
+func (_{{ $T }}__Repr) LookupIndex(int) (ipld.Node, error) {
+	return mixins.Map{TypeName: "{{ $pkgT }}.Repr"}.LookupIndex(0)
+}
+func (r _{{ $T }}__Repr) LookupSegment(seg ipld.PathSegment) (ipld.Node, error) {
+	return r.LookupString(seg.String())
+}
+func (r _{{ $T }}__Repr) MapIterator() ipld.MapIterator {
+	return &_{{ $T }}__MapItr{n: r.n, repr: true}
+}
+func (_{{ $T }}__Repr) ListIterator() ipld.ListIterator {
+	return nil
+}
+func (_{{ $T }}__Repr) Length() int {
+	return {{ $N }}
+}
+func (_{{ $T }}__Repr) IsUndefined() bool {
+	return false
+}
+func (_{{ $T }}__Repr) IsNull() bool {
+	return false
+}
+{{ wrongAs (printf "_%s__Repr" $T) (printf "mixins.Map{TypeName: %q}" (printf "%s.Repr" $pkgT)) "" -}}
+func (_{{ $T }}__Repr) Style() ipld.NodeStyle {
+	return ReprStyle__{{ $T }}{}
+}
+{{ end }}
+type Style__{{ $T }} struct{}
+
+func (Style__{{ $T }}) NewBuilder() ipld.NodeBuilder {
+	var w {{ $T }}
+	return &_{{ $T }}__Builder{_{{ $T }}__Assembler{w: &w}}
+}
+
+// ReprStyle__{{ $T }} builds a {{ $T }} from its representation.
+type ReprStyle__{{ $T }} struct{}
+
+func (ReprStyle__{{ $T }}) NewBuilder() ipld.NodeBuilder {
+	var w {{ $T }}
+	return &_{{ $T }}__Builder{_{{ $T }}__Assembler{w: &w, repr: true}}
+}
+
+type _{{ $T }}__Builder struct {
+	_{{ $T }}__Assembler
+}
+
+func (nb *_{{ $T }}__Builder) Build() ipld.Node {
+	if nb.state != maState_finished {
+		panic("invalid state: assembler must be 'finished' before Build can be called!")
+	}
+	return nb.w
+}
+func (nb *_{{ $T }}__Builder) Reset() {
+	var w {{ $T }}
+	*nb = _{{ $T }}__Builder{_{{ $T }}__Assembler{w: &w, repr: nb.repr}}
+}
+
+type _{{ $T }}__Assembler struct {
+	w     *{{ $T }}
+	p     _parent // if assembling a key or value within a map or struct, the assembler to tell when done.
+	repr  bool    // if true, assembling from the representation{{ if .StringJoin }} (a string){{ else }} (keyed by the fields' representation keys, with the fields' representations as values){{ end }}.
+	state maState
+	f     int // the index of the field being assembled, between prepareField and childDone.
+	isset [{{ $N }}]bool
+	ka    _structKeyAssembler
+{{- range .Fields }}
+	ca_{{ .Name }} _{{ .Type.Name }}__Assembler
+{{- end }}
+}
+
+func (na *_{{ $T }}__Assembler) done(err error) error {
+	if na.p != nil {
+		err = na.p.childDone(err)
+		na.p = nil
+	}
+	return err
+}
+
+// keys returns the keys of the fields, as they're given in the mode the assembler is in.
+func (na *_{{ $T }}__Assembler) keys() []string {
+{{- if not .StringJoin }}
+	if na.repr {
+		return _{{ $T }}__ReprKeys[:]
+	}
+{{- end }}
+	return _{{ $T }}__FieldNames[:]
+}
+
+func (na *_{{ $T }}__Assembler) BeginMap(int) (ipld.MapAssembler, error) {
+{{- if .StringJoin }}
+	if na.repr {
+		_, err := mixins.StringAssembler{TypeName: "{{ $pkgT }}.Repr"}.BeginMap(0)
+		return nil, na.done(err)
+	}
+{{- end }}
+	return na, nil
+}
+{{ if .StringJoin -}}
+{{ wrongAssign (printf "_%s__Assembler" $T) (printf "mixins.MapAssembler{TypeName: %q}" $pkgT) (printf "mixins.StringAssembler{TypeName: %q}" (printf "%s.Repr" $pkgT)) "BeginMap" "AssignString" "AssignNode" -}}
+func (na *_{{ $T }}__Assembler) AssignString(v string) error {
+	if !na.repr {
+		return na.done(mixins.MapAssembler{TypeName: "{{ $pkgT }}"}.AssignString(""))
+	}
+	parts := strings.Split(v, {{ quote .Sep }})
+	if len(parts) != {{ $N }} {
+		return na.done(schema.ErrInvalidData{Type: Type__{{ $T }}, Reason: {{ quote (printf "expected %d fields joined by %q, got " $N .Sep) }} + strconv.Itoa(len(parts))})
+	}
+	*na.w = {{ $T }}{ {{- range $i, $f := .Fields }}{{ if $i }}, {{ end }}{{ $f.Name }}: {{ $f.Type.Name }}(parts[{{ $f.Index }}]){{ end -}} }
+	na.state = maState_finished
+	return na.done(nil)
+}
+{{ else -}}
+{{ wrongAssign (printf "_%s__Assembler" $T) (printf "mixins.MapAssembler{TypeName: %q}" $pkgT) "" "BeginMap" "AssignNode" -}}
+{{ end -}}
+func (na *_{{ $T }}__Assembler) AssignNode(v ipld.Node) error {
+	if v2, ok := v.(*{{ $T }}); ok && !na.repr {
+		*na.w = *v2
+		na.state = maState_finished
+		return na.done(nil)
+	}
+	if v2, ok := v.(*_{{ $T }}__Repr); ok && na.repr {
+		*na.w = *v2.n
+		na.state = maState_finished
+		return na.done(nil)
+	}
+{{- if .StringJoin }}
+	if na.repr {
+		s, err := v.AsString()
+		if err != nil {
+			return na.done(err)
+		}
+		return na.AssignString(s)
+	}
+{{- end }}
+	if v.ReprKind() != ipld.ReprKind_Map {
+		return na.done(ipld.ErrWrongKind{TypeName: "{{ $pkgT }}", MethodName: "AssignNode", AppropriateKind: ipld.ReprKindSet_JustMap, ActualKind: v.ReprKind()})
+	}
+	if err := _copyEntries(na, v); err != nil {
+		return na.done(err)
+	}
+	if err := na.Finish(); err != nil {
+		return na.done(err)
+	}
+	return nil
+}
+func (na *_{{ $T }}__Assembler) Style() ipld.NodeStyle {
+	if na.repr {
+		return ReprStyle__{{ $T }}{}
+	}
+	return Style__{{ $T }}{}
+}
+
+func (na *_{{ $T }}__Assembler) prepareField(k string) error {
+	idx := _indexOf(na.keys(), k)
+	if idx < 0 {
+		return ipld.ErrInvalidStructKey{TypeName: "{{ $pkgT }}", Key: k}
+	}
+	if na.isset[idx] {
+		return ipld.ErrRepeatedMapKey{Key: basicnode.NewString(k)}
+	}
+	na.f = idx
+	return nil
+}
+func (na *_{{ $T }}__Assembler) keyDone(err error) error {
+	if err != nil {
+		na.state = maState_initial
+		return err
+	}
+	na.state = maState_expectValue
+	return nil
+}
+func (na *_{{ $T }}__Assembler) childDone(err error) error {
+{{- if .StringJoin }}
+	if err == nil {
+		err = na.checkSep()
+	}
+{{- end }}
+	if err == nil {
+		na.isset[na.f] = true
+	}
+	na.state = maState_initial
+	return err
+}
+{{- if .StringJoin }}
+
+// checkSep rejects a field which contains the separator:
+// the representation couldn't be split back into the same fields.
+func (na *_{{ $T }}__Assembler) checkSep() error {
+	var v string
+	switch na.f {
+{{- range .Fields }}
+	case {{ .Index }}:
+		v = string(na.w.{{ .Name }})
+{{- end }}
+	}
+	if strings.Contains(v, {{ quote .Sep }}) {
+		return schema.ErrInvalidData{Type: Type__{{ $T }}, Reason: "field " + strconv.Quote(na.keys()[na.f]) + {{ quote (printf " contains the separator %q" .Sep) }}}
+	}
+	return nil
+}
+{{- end }}
+
+// fieldAssembler returns an assembler for the field which prepareField found.
+func (na *_{{ $T }}__Assembler) fieldAssembler() ipld.NodeAssembler {
+	switch na.f {
+{{- range .Fields }}
+	case {{ .Index }}:
+		na.ca_{{ .Name }} = {{ child .Type (printf "&na.w.%s" .Name) "na" "na.repr" }}
+		return &na.ca_{{ .Name }}
+{{- end }}
+	default:
+		panic("unreachable")
+	}
+}
+
+func (na *_{{ $T }}__Assembler) AssembleEntry(k string) (ipld.NodeAssembler, error) {
+	if na.state != maState_initial {
+		panic("misuse")
+	}
+	if err := na.prepareField(k); err != nil {
+		return nil, err
+	}
+	na.state = maState_midValue
+	return na.fieldAssembler(), nil
+}
+func (na *_{{ $T }}__Assembler) AssembleKey() ipld.NodeAssembler {
+	if na.state != maState_initial {
+		panic("misuse")
+	}
+	na.state = maState_midKey
+	na.ka = _structKeyAssembler{na}
+	return &na.ka
+}
+func (na *_{{ $T }}__Assembler) AssembleValue() ipld.NodeAssembler {
+	if na.state != maState_expectValue {
+		panic("misuse")
+	}
+	na.state = maState_midValue
+	return na.fieldAssembler()
+}
+func (na *_{{ $T }}__Assembler) Finish() error {
+	if na.state != maState_initial {
+		panic("misuse")
+	}
+	var missing []string
+	for i, isset := range na.isset {
+		if !isset {
+			missing = append(missing, na.keys()[i])
+		}
+	}
+	if missing != nil {
+		return ipld.ErrMissingRequiredField{TypeName: "{{ $pkgT }}", Missing: missing}
+	}
+	na.state = maState_finished
+	return na.done(nil)
+}
+func (_{{ $T }}__Assembler) KeyStyle() ipld.NodeStyle {
+	return basicnode.Style__String{}
+}
+func (na *_{{ $T }}__Assembler) ValueStyle(k string) ipld.NodeStyle {
+	switch _indexOf(na.keys(), k) {
+{{- range .Fields }}
+	case {{ .Index }}:
+		if na.repr {
+			return ReprStyle__{{ .Type.Name }}{}
+		}
+		return Style__{{ .Type.Name }}{}
+{{- end }}
+	default:
+		return nil
+	}
+}
+`)
@@ -1,5 +1,9 @@
 package schema
 
+import (
+	"fmt"
+)
+
 // Everything in this file is __a temporary hack__ and will be __removed__.
 //
 // These methods will only hang around until more of the "ast" packages are finished;
@@ -9,12 +13,29 @@ package schema
 //
 // (Meanwhile, we're using these methods in the codegen prototypes.)
 
+func SpawnBool(name TypeName) TypeBool {
+	return TypeBool{anyType{name, nil}}
+}
+
 func SpawnString(name TypeName) TypeString {
 	return TypeString{anyType{name, nil}}
 }
 
 func SpawnInt(name TypeName) TypeInt {
-	return TypeInt{anyType{name, nil}}
+	return TypeInt{anyType{name, nil}, 0}
+}
+
+func SpawnIntWidth(name TypeName, bits int) TypeInt {
+	switch bits {
+	case 8, 16, 32, 64:
+		return TypeInt{anyType{name, nil}, bits}
+	default:
+		panic("invalid int width!")
+	}
+}
+
+func SpawnFloat(name TypeName) TypeFloat {
+	return TypeFloat{anyType{name, nil}}
 }
 
 func SpawnBytes(name TypeName) TypeBytes {
@@ -31,6 +52,23 @@ func SpawnLinkReference(name TypeName, referenceType Type) TypeLink {
 func SpawnList(name TypeName, typ Type, nullable bool) TypeList {
 	return TypeList{anyType{name, nil}, false, typ, nullable}
 }
+func SpawnAnonymousList(typ Type, nullable bool) TypeList {
+	return TypeList{anyType{TypeName("[" + nullablePrefix(nullable) + string(typ.Name()) + "]"), nil}, true, typ, nullable}
+}
+
+func SpawnMap(name TypeName, keyType Type, valueType Type, nullable bool) TypeMap {
+	return TypeMap{anyType{name, nil}, false, keyType, valueType, nullable}
+}
+func SpawnAnonymousMap(keyType Type, valueType Type, nullable bool) TypeMap {
+	return TypeMap{anyType{TypeName("{" + string(keyType.Name()) + ":" + nullablePrefix(nullable) + string(valueType.Name()) + "}"), nil}, true, keyType, valueType, nullable}
+}
+
+func nullablePrefix(nullable bool) string {
+	if nullable {
+		return "nullable "
+	}
+	return ""
+}
 
 func SpawnStruct(name TypeName, fields []StructField, repr StructRepresentation) TypeStruct {
 	fieldsMap := make(map[string]StructField, len(fields))
@@ -39,6 +77,45 @@ func SpawnStruct(name TypeName, fields []StructField, repr StructRepresentation)
 	}
 	return TypeStruct{anyType{name, nil}, fields, fieldsMap, repr}
 }
+func SpawnStructRepresentationMap(renames map[string]string) StructRepresentation_Map {
+	return StructRepresentation_Map{renames, nil}
+}
+func SpawnStructRepresentationTuple() StructRepresentation_Tuple {
+	return StructRepresentation_Tuple{}
+}
+func SpawnStructRepresentationStringJoin(sep string) StructRepresentation_StringJoin {
+	return StructRepresentation_StringJoin{sep}
+}
+func SpawnStructRepresentationStringPairs(sep1, sep2 string) StructRepresentation_StringPairs {
+	return StructRepresentation_StringPairs{sep1, sep2}
+}
+func SpawnEnum(name TypeName, members []string, repr EnumRepresentation) TypeEnum {
+	return TypeEnum{anyType{name, nil}, members, repr}
+}
+func SpawnEnumRepresentationString(renames map[string]string) EnumRepresentation_String {
+	return EnumRepresentation_String{renames}
+}
+func SpawnEnumRepresentationInt(values map[string]int) EnumRepresentation_Int {
+	return EnumRepresentation_Int{values}
+}
+func SpawnUnionKeyed(name TypeName, members map[string]Type) TypeUnion {
+	return TypeUnion{anyType{name, nil}, UnionStyle_Keyed, nil, members, "", ""}
+}
 func SpawnStructField(name string, typ Type, optional bool, nullable bool) StructField {
 	return StructField{name, typ, optional, nullable}
 }
+
+// SpawnTypeSystem gathers named types into a TypeSystem.
+// It returns an error if two of them have the same name.
+// (The types' TypeSystem methods still return nil: see the note at the top of this file.)
+func SpawnTypeSystem(types ...Type) (*TypeSystem, error) {
+	ts := &TypeSystem{namedTypes: make(map[TypeName]Type, len(types))}
+	for _, t := range types {
+		if _, exists := ts.namedTypes[t.Name()]; exists {
+			return nil, fmt.Errorf("duplicate type name %q", t.Name())
+		}
+		ts.namedTypes[t.Name()] = t
+		ts.names = append(ts.names, t.Name())
+	}
+	return ts, nil
+}
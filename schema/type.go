@@ -105,6 +105,7 @@ type TypeBytes struct {
 
 type TypeInt struct {
 	anyType
+	bits int // 0 if the type has no width of its own (it's just the native int).
 }
 
 type TypeFloat struct {
@@ -184,5 +185,18 @@ type StructRepresentation_StringJoin struct{ sep string }
 
 type TypeEnum struct {
 	anyType
-	members []string
+	members        []string
+	representation EnumRepresentation // nil means the default: EnumRepresentation_String, without renames.
+}
+
+type EnumRepresentation interface{ _EnumRepresentation() }
+
+func (EnumRepresentation_String) _EnumRepresentation() {}
+func (EnumRepresentation_Int) _EnumRepresentation()    {}
+
+type EnumRepresentation_String struct {
+	renames map[string]string // member -> string in the representation, for members which aren't represented as themselves.
+}
+type EnumRepresentation_Int struct {
+	values map[string]int // member -> int in the representation.
 }
@@ -20,6 +20,23 @@ func (TypeEnum) Kind() Kind   { return Kind_Enum }
 
 /* interesting methods per Type type */
 
+// Bits returns the width of the int type in bits (8, 16, 32, or 64),
+// or zero if the type has no width of its own and holds any native int.
+// Values of a sized int type must fit in a signed integer of that width.
+func (t TypeInt) Bits() int {
+	return t.bits
+}
+
+// InRange reports whether the value fits in the width of the int type
+// (see Bits).  If the type has no width, all values are in range.
+func (t TypeInt) InRange(v int) bool {
+	if t.bits == 0 || t.bits == 64 {
+		return true
+	}
+	max := int64(1)<<uint(t.bits-1) - 1
+	return int64(v) <= max && int64(v) >= -max-1
+}
+
 // IsAnonymous is returns true if the type was unnamed.  Unnamed types will
 // claim to have a Name property like `{Foo:Bar}`, and this is not guaranteed
 // to be a unique string for all types in the universe.
@@ -80,6 +97,39 @@ func (t TypeUnion) UnionMembers() map[Type]struct{} {
 	return m
 }
 
+// memberOfKey returns the member type which a keyed union's discriminant stands for,
+// and false if there isn't one.
+func (t TypeUnion) memberOfKey(key string) (Type, bool) {
+	v, ok := t.values[key]
+	return v, ok
+}
+
+// keyOfMember returns the discriminant which stands for a keyed union's member type.
+func (t TypeUnion) keyOfMember(member Type) string {
+	for k, v := range t.values {
+		if v.Name() == member.Name() {
+			return k
+		}
+	}
+	panic("unreachable") // (members are only ever looked up from the union's own values.)
+}
+
+// memberOfName returns the member type which has the given name,
+// and false if there isn't one.
+func (t TypeUnion) memberOfName(name string) (Type, bool) {
+	for _, v := range t.values {
+		if string(v.Name()) == name {
+			return v, true
+		}
+	}
+	for _, v := range t.valuesKinded {
+		if string(v.Name()) == name {
+			return v, true
+		}
+	}
+	return nil, false
+}
+
 // Fields returns a slice of descriptions of the object's fields.
 func (t TypeStruct) Fields() []StructField {
 	a := make([]StructField, len(t.fields))
@@ -107,7 +157,7 @@ func (f StructField) Name() string { return f.name }
 func (f StructField) Type() Type { return f.typ }
 
 // IsOptional returns true if the field is allowed to be absent from the object.
-// If IsOptional is false, the field may be absent from the serial representation
+// If IsOptional is true, the field may be absent from the serial representation
 // of the object entirely.
 //
 // Note being optional is different than saying the value is permitted to be null!
@@ -135,6 +185,11 @@ func (r StructRepresentation_Map) GetFieldKey(field StructField) string {
 	return field.name
 }
 
+// Separator returns the string which joins the fields' values in the representation.
+func (r StructRepresentation_StringJoin) Separator() string {
+	return r.sep
+}
+
 // Members returns a slice the strings which are valid inhabitants of this enum.
 func (t TypeEnum) Members() []string {
 	a := make([]string, len(t.members))
@@ -144,6 +199,60 @@ func (t TypeEnum) Members() []string {
 	return a
 }
 
+// HasMember reports whether the string is one of the enum's members.
+func (t TypeEnum) HasMember(s string) bool {
+	for _, m := range t.members {
+		if s == m {
+			return true
+		}
+	}
+	return false
+}
+
+// RepresentationStrategy returns how the enum's members are represented:
+// either EnumRepresentation_String (the default) or EnumRepresentation_Int.
+func (t TypeEnum) RepresentationStrategy() EnumRepresentation {
+	if t.representation == nil {
+		return EnumRepresentation_String{}
+	}
+	return t.representation
+}
+
+// GetString returns the string which represents the member.
+// That's the member itself, unless it's renamed.
+func (r EnumRepresentation_String) GetString(member string) string {
+	if s, ok := r.renames[member]; ok {
+		return s
+	}
+	return member
+}
+
+// GetInt returns the int which represents the member,
+// and false if there's none given for it.
+func (r EnumRepresentation_Int) GetInt(member string) (int, bool) {
+	v, ok := r.values[member]
+	return v, ok
+}
+
+// memberOfRepresentation returns the member of the enum which the representation
+// (a string or an int, according to the representation strategy) stands for,
+// and false if there isn't one.
+func (t TypeEnum) memberOfRepresentation(v interface{}) (string, bool) {
+	for _, m := range t.members {
+		switch r := t.RepresentationStrategy().(type) {
+		case EnumRepresentation_String:
+			if r.GetString(m) == v {
+				return m, true
+			}
+		case EnumRepresentation_Int:
+			if i, ok := r.GetInt(m); ok && i == v {
+				return m, true
+			}
+		}
+	}
+	return "", false
+}
+
 // Links can keep a referenced type, which is a hint only about the data on the
 // other side of the link, no something that can be explicitly validated without
 // loading the link
@@ -0,0 +1,244 @@
+package schema
+
+// This file has the parts which the typed nodes of this package share.
+
+import (
+	ipld "github.com/ipld/go-ipld-prime"
+	"github.com/ipld/go-ipld-prime/node/mixins"
+)
+
+var (
+	_ ipld.Node          = plainString("")
+	_ ipld.NodeStyle     = plainString__Style{}
+	_ ipld.NodeBuilder   = &plainString__Builder{}
+	_ ipld.NodeAssembler = &stringKeyAssembler{}
+)
+
+// typedStyleOf returns the style for building values of the given type
+// (e.g. a union's member, or a struct's field):
+// one of the typed styles in this package, if there is one for the type;
+// or otherwise vs (which may be nil).
+func typedStyleOf(t Type, repr bool, vs ipld.NodeStyle) ipld.NodeStyle {
+	switch t2 := t.(type) {
+	case TypeInt:
+		return Style__TypedInt{t2}
+	case TypeEnum:
+		if repr {
+			return Style__TypedEnumRepr{t2}
+		}
+		return Style__TypedEnum{t2}
+	case TypeUnion:
+		if t2.style != UnionStyle_Keyed {
+			return vs
+		}
+		if repr {
+			return Style__TypedUnionRepr{t2, vs}
+		}
+		return Style__TypedUnion{t2, vs}
+	case TypeStruct:
+		if _, ok := t2.representation.(StructRepresentation_Map); !ok {
+			return vs
+		}
+		if repr {
+			return Style__TypedStructRepr{t2, vs}
+		}
+		return Style__TypedStruct{t2, vs}
+	default:
+		return vs
+	}
+}
+
+// representationOf returns the representation of a value held by a typed node.
+// (Values which were built with a ValueStyle, rather than one of the typed styles
+// in this package, are already in their representation form.)
+func representationOf(v ipld.Node) ipld.Node {
+	if tn, ok := v.(TypedNode); ok {
+		return tn.Representation()
+	}
+	return v
+}
+
+// maState is the state of the map assemblers of typed nodes.
+// Since each value is built by a NodeBuilder of its own,
+// the assembler doesn't see when the value is finished:
+// it goes straight back to maState_initial once the value's assembler is handed out.
+type maState uint8
+
+const (
+	maState_initial     maState = iota // also the state between entries.
+	maState_midKey                     // the key's assembler has been handed out.
+	maState_expectValue                // the key has been assigned; AssembleValue is next.
+	maState_finished
+)
+
+// stringKeyAssembler assembles the key of a map entry, which must be a string,
+// and hands it to assign.
+type stringKeyAssembler struct {
+	typeName string // of the map; the key's is this with ".Key" on the end.
+	assign   func(string) error
+}
+
+func (ka *stringKeyAssembler) BeginMap(sizeHint int) (ipld.MapAssembler, error) {
+	return mixins.StringAssembler{ka.typeName + ".Key"}.BeginMap(0)
+}
+func (ka *stringKeyAssembler) BeginList(sizeHint int) (ipld.ListAssembler, error) {
+	return mixins.StringAssembler{ka.typeName + ".Key"}.BeginList(0)
+}
+func (ka *stringKeyAssembler) AssignNull() error {
+	return mixins.StringAssembler{ka.typeName + ".Key"}.AssignNull()
+}
+func (ka *stringKeyAssembler) AssignBool(bool) error {
+	return mixins.StringAssembler{ka.typeName + ".Key"}.AssignBool(false)
+}
+func (ka *stringKeyAssembler) AssignInt(int) error {
+	return mixins.StringAssembler{ka.typeName + ".Key"}.AssignInt(0)
+}
+func (ka *stringKeyAssembler) AssignFloat(float64) error {
+	return mixins.StringAssembler{ka.typeName + ".Key"}.AssignFloat(0)
+}
+func (ka *stringKeyAssembler) AssignString(k string) error {
+	return ka.assign(k)
+}
+func (ka *stringKeyAssembler) AssignBytes([]byte) error {
+	return mixins.StringAssembler{ka.typeName + ".Key"}.AssignBytes(nil)
+}
+func (ka *stringKeyAssembler) AssignLink(ipld.Link) error {
+	return mixins.StringAssembler{ka.typeName + ".Key"}.AssignLink(nil)
+}
+func (ka *stringKeyAssembler) AssignNode(v ipld.Node) error {
+	if v2, err := v.AsString(); err != nil {
+		return err
+	} else {
+		return ka.AssignString(v2)
+	}
+}
+func (stringKeyAssembler) Style() ipld.NodeStyle {
+	return plainString__Style{}
+}
+
+// plainString is a plain string node, for the keys of unions and structs.
+// (This package can't use basicnode, which uses this package in its tests.)
+type plainString string
+
+func (plainString) ReprKind() ipld.ReprKind {
+	return ipld.ReprKind_String
+}
+func (plainString) LookupString(string) (ipld.Node, error) {
+	return mixins.String{}.LookupString("")
+}
+func (plainString) Lookup(key ipld.Node) (ipld.Node, error) {
+	return mixins.String{}.Lookup(nil)
+}
+func (plainString) LookupIndex(idx int) (ipld.Node, error) {
+	return mixins.String{}.LookupIndex(0)
+}
+func (plainString) LookupSegment(seg ipld.PathSegment) (ipld.Node, error) {
+	return mixins.String{}.LookupSegment(seg)
+}
+func (plainString) MapIterator() ipld.MapIterator {
+	return nil
+}
+func (plainString) ListIterator() ipld.ListIterator {
+	return nil
+}
+func (plainString) Length() int {
+	return -1
+}
+func (plainString) IsUndefined() bool {
+	return false
+}
+func (plainString) IsNull() bool {
+	return false
+}
+func (plainString) AsBool() (bool, error) {
+	return mixins.String{}.AsBool()
+}
+func (plainString) AsInt() (int, error) {
+	return mixins.String{}.AsInt()
+}
+func (plainString) AsFloat() (float64, error) {
+	return mixins.String{}.AsFloat()
+}
+func (n plainString) AsString() (string, error) {
+	return string(n), nil
+}
+func (plainString) AsBytes() ([]byte, error) {
+	return mixins.String{}.AsBytes()
+}
+func (plainString) AsLink() (ipld.Link, error) {
+	return mixins.String{}.AsLink()
+}
+func (plainString) Style() ipld.NodeStyle {
+	return plainString__Style{}
+}
+
+// plainString__Style builds plainString nodes.  It's the KeyStyle of unions and structs.
+type plainString__Style struct{}
+
+func (plainString__Style) NewBuilder() ipld.NodeBuilder {
+	return &plainString__Builder{}
+}
+
+// plainString__Builder builds a plainString, with the same rules as typedInt__Builder:
+// one assignment, then Build (which may be repeated), then ipld.ErrBuilderConsumed until Reset.
+type plainString__Builder struct {
+	w        plainString
+	assigned bool
+	built    bool
+}
+
+func (nb *plainString__Builder) Build() ipld.Node {
+	if !nb.assigned {
+		panic("misuse")
+	}
+	nb.built = true
+	return nb.w
+}
+func (nb *plainString__Builder) Reset() {
+	*nb = plainString__Builder{}
+}
+func (nb *plainString__Builder) BeginMap(sizeHint int) (ipld.MapAssembler, error) {
+	return mixins.StringAssembler{"string"}.BeginMap(0)
+}
+func (nb *plainString__Builder) BeginList(sizeHint int) (ipld.ListAssembler, error) {
+	return mixins.StringAssembler{"string"}.BeginList(0)
+}
+func (nb *plainString__Builder) AssignNull() error {
+	return mixins.StringAssembler{"string"}.AssignNull()
+}
+func (nb *plainString__Builder) AssignBool(bool) error {
+	return mixins.StringAssembler{"string"}.AssignBool(false)
+}
+func (nb *plainString__Builder) AssignInt(int) error {
+	return mixins.StringAssembler{"string"}.AssignInt(0)
+}
+func (nb *plainString__Builder) AssignFloat(float64) error {
+	return mixins.StringAssembler{"string"}.AssignFloat(0)
+}
+func (nb *plainString__Builder) AssignString(v string) error {
+	if nb.built {
+		return ipld.ErrBuilderConsumed{"AssignString"}
+	}
+	if nb.assigned {
+		panic("misuse")
+	}
+	nb.w = plainString(v)
+	nb.assigned = true
+	return nil
+}
+func (nb *plainString__Builder) AssignBytes([]byte) error {
+	return mixins.StringAssembler{"string"}.AssignBytes(nil)
+}
+func (nb *plainString__Builder) AssignLink(ipld.Link) error {
+	return mixins.StringAssembler{"string"}.AssignLink(nil)
+}
+func (nb *plainString__Builder) AssignNode(v ipld.Node) error {
+	if v2, err := v.AsString(); err != nil {
+		return err
+	} else {
+		return nb.AssignString(v2)
+	}
+}
+func (plainString__Builder) Style() ipld.NodeStyle {
+	return plainString__Style{}
+}
@@ -0,0 +1,318 @@
+package schema
+
+import (
+	ipld "github.com/ipld/go-ipld-prime"
+	"github.com/ipld/go-ipld-prime/node/mixins"
+)
+
+var (
+	_ ipld.Node          = &typedEnum{}
+	_ TypedNode          = &typedEnum{}
+	_ ipld.Node          = &typedEnum__Repr{}
+	_ ipld.NodeStyle     = Style__TypedEnum{}
+	_ ipld.NodeStyle     = Style__TypedEnumRepr{}
+	_ ipld.NodeBuilder   = &typedEnum__Builder{}
+	_ ipld.NodeAssembler = &typedEnum__Assembler{}
+)
+
+// typedEnum is one of the members of a TypeEnum, which was checked
+// to be a member when it was assigned.
+// It acts like a plain string (the member's name) otherwise;
+// its representation is a string or an int, per the type's representation strategy.
+type typedEnum struct {
+	t TypeEnum
+	v string
+}
+
+// -- Node interface methods -->
+
+func (typedEnum) ReprKind() ipld.ReprKind {
+	return ipld.ReprKind_String
+}
+func (n *typedEnum) LookupString(string) (ipld.Node, error) {
+	return mixins.String{string(n.t.Name())}.LookupString("")
+}
+func (n *typedEnum) Lookup(key ipld.Node) (ipld.Node, error) {
+	return mixins.String{string(n.t.Name())}.Lookup(nil)
+}
+func (n *typedEnum) LookupIndex(idx int) (ipld.Node, error) {
+	return mixins.String{string(n.t.Name())}.LookupIndex(0)
+}
+func (n *typedEnum) LookupSegment(seg ipld.PathSegment) (ipld.Node, error) {
+	return mixins.String{string(n.t.Name())}.LookupSegment(seg)
+}
+func (typedEnum) MapIterator() ipld.MapIterator {
+	return nil
+}
+func (typedEnum) ListIterator() ipld.ListIterator {
+	return nil
+}
+func (typedEnum) Length() int {
+	return -1
+}
+func (typedEnum) IsUndefined() bool {
+	return false
+}
+func (typedEnum) IsNull() bool {
+	return false
+}
+func (n *typedEnum) AsBool() (bool, error) {
+	return mixins.String{string(n.t.Name())}.AsBool()
+}
+func (n *typedEnum) AsInt() (int, error) {
+	return mixins.String{string(n.t.Name())}.AsInt()
+}
+func (n *typedEnum) AsFloat() (float64, error) {
+	return mixins.String{string(n.t.Name())}.AsFloat()
+}
+func (n *typedEnum) AsString() (string, error) {
+	return n.v, nil
+}
+func (n *typedEnum) AsBytes() ([]byte, error) {
+	return mixins.String{string(n.t.Name())}.AsBytes()
+}
+func (n *typedEnum) AsLink() (ipld.Link, error) {
+	return mixins.String{string(n.t.Name())}.AsLink()
+}
+func (n *typedEnum) Style() ipld.NodeStyle {
+	return Style__TypedEnum{n.t}
+}
+
+// -- TypedNode interface methods -->
+
+func (n *typedEnum) Type() Type {
+	return n.t
+}
+
+// Representation returns a node which is a plain string or int,
+// per the type's representation strategy.
+func (n *typedEnum) Representation() ipld.Node {
+	return (*typedEnum__Repr)(n)
+}
+
+// -- representation Node -->
+
+// typedEnum__Repr is the representation of a typedEnum:
+// the string or int which stands for its member.
+type typedEnum__Repr typedEnum
+
+func (n *typedEnum__Repr) ReprKind() ipld.ReprKind {
+	if _, ok := n.t.RepresentationStrategy().(EnumRepresentation_Int); ok {
+		return ipld.ReprKind_Int
+	}
+	return ipld.ReprKind_String
+}
+func (n *typedEnum__Repr) kindMixin() interface {
+	LookupString(string) (ipld.Node, error)
+	Lookup(ipld.Node) (ipld.Node, error)
+	LookupIndex(int) (ipld.Node, error)
+	LookupSegment(ipld.PathSegment) (ipld.Node, error)
+	AsBool() (bool, error)
+	AsFloat() (float64, error)
+	AsBytes() ([]byte, error)
+	AsLink() (ipld.Link, error)
+} {
+	if n.ReprKind() == ipld.ReprKind_Int {
+		return mixins.Int{string(n.t.Name()) + ".Repr"}
+	}
+	return mixins.String{string(n.t.Name()) + ".Repr"}
+}
+func (n *typedEnum__Repr) LookupString(string) (ipld.Node, error) {
+	return n.kindMixin().LookupString("")
+}
+func (n *typedEnum__Repr) Lookup(key ipld.Node) (ipld.Node, error) {
+	return n.kindMixin().Lookup(nil)
+}
+func (n *typedEnum__Repr) LookupIndex(idx int) (ipld.Node, error) {
+	return n.kindMixin().LookupIndex(0)
+}
+func (n *typedEnum__Repr) LookupSegment(seg ipld.PathSegment) (ipld.Node, error) {
+	return n.kindMixin().LookupSegment(seg)
+}
+func (typedEnum__Repr) MapIterator() ipld.MapIterator {
+	return nil
+}
+func (typedEnum__Repr) ListIterator() ipld.ListIterator {
+	return nil
+}
+func (typedEnum__Repr) Length() int {
+	return -1
+}
+func (typedEnum__Repr) IsUndefined() bool {
+	return false
+}
+func (typedEnum__Repr) IsNull() bool {
+	return false
+}
+func (n *typedEnum__Repr) AsBool() (bool, error) {
+	return n.kindMixin().AsBool()
+}
+func (n *typedEnum__Repr) AsInt() (int, error) {
+	r, ok := n.t.RepresentationStrategy().(EnumRepresentation_Int)
+	if !ok {
+		return mixins.String{string(n.t.Name()) + ".Repr"}.AsInt()
+	}
+	v, _ := r.GetInt(n.v) // (every member has one, or it couldn't have been assigned.)
+	return v, nil
+}
+func (n *typedEnum__Repr) AsFloat() (float64, error) {
+	return n.kindMixin().AsFloat()
+}
+func (n *typedEnum__Repr) AsString() (string, error) {
+	r, ok := n.t.RepresentationStrategy().(EnumRepresentation_String)
+	if !ok {
+		return mixins.Int{string(n.t.Name()) + ".Repr"}.AsString()
+	}
+	return r.GetString(n.v), nil
+}
+func (n *typedEnum__Repr) AsBytes() ([]byte, error) {
+	return n.kindMixin().AsBytes()
+}
+func (n *typedEnum__Repr) AsLink() (ipld.Link, error) {
+	return n.kindMixin().AsLink()
+}
+func (n *typedEnum__Repr) Style() ipld.NodeStyle {
+	return Style__TypedEnumRepr{n.t}
+}
+
+// -- NodeStyle -->
+
+// Style__TypedEnum builds nodes of the given TypeEnum, from the names of its members:
+// assigning any other string is rejected with ErrInvalidEnumValue.
+// The resulting nodes are TypedNodes, and report ReprKind_String;
+// their AsString returns the member's name.
+type Style__TypedEnum struct {
+	Type TypeEnum
+}
+
+func (ns Style__TypedEnum) NewBuilder() ipld.NodeBuilder {
+	return &typedEnum__Builder{typedEnum__Assembler{w: &typedEnum{t: ns.Type}}}
+}
+
+// Style__TypedEnumRepr builds the same nodes as Style__TypedEnum,
+// but from the type's representation: a string or an int, per its representation strategy
+// (so it's what to decode into).
+// Assigning a value which doesn't represent any member is rejected with ErrInvalidEnumValue;
+// assigning the other kind is rejected with ipld.ErrWrongKind.
+type Style__TypedEnumRepr struct {
+	Type TypeEnum
+}
+
+func (ns Style__TypedEnumRepr) NewBuilder() ipld.NodeBuilder {
+	return &typedEnum__Builder{typedEnum__Assembler{w: &typedEnum{t: ns.Type}, repr: true}}
+}
+
+// -- NodeBuilder -->
+
+type typedEnum__Builder struct {
+	typedEnum__Assembler
+}
+
+func (nb *typedEnum__Builder) Build() ipld.Node {
+	return nb.w
+}
+func (nb *typedEnum__Builder) Reset() {
+	*nb = typedEnum__Builder{typedEnum__Assembler{w: &typedEnum{t: nb.w.t}, repr: nb.repr}}
+}
+
+// -- NodeAssembler -->
+
+type typedEnum__Assembler struct {
+	w    *typedEnum
+	repr bool // if true, assign the representation, rather than the member name.
+}
+
+// kindMixin returns the mixin for rejecting kinds the assembler doesn't take.
+// (AssignInt and AssignString, which differ between the two, pick their own.)
+func (na *typedEnum__Assembler) kindMixin() interface {
+	BeginMap(int) (ipld.MapAssembler, error)
+	BeginList(int) (ipld.ListAssembler, error)
+	AssignNull() error
+	AssignBool(bool) error
+	AssignFloat(float64) error
+	AssignBytes([]byte) error
+	AssignLink(ipld.Link) error
+} {
+	if _, ok := na.w.t.RepresentationStrategy().(EnumRepresentation_Int); ok && na.repr {
+		return mixins.IntAssembler{na.typeName()}
+	}
+	return mixins.StringAssembler{na.typeName()}
+}
+
+func (na *typedEnum__Assembler) typeName() string {
+	if na.repr {
+		return string(na.w.t.Name()) + ".Repr"
+	}
+	return string(na.w.t.Name())
+}
+
+func (na *typedEnum__Assembler) BeginMap(sizeHint int) (ipld.MapAssembler, error) {
+	return na.kindMixin().BeginMap(0)
+}
+func (na *typedEnum__Assembler) BeginList(sizeHint int) (ipld.ListAssembler, error) {
+	return na.kindMixin().BeginList(0)
+}
+func (na *typedEnum__Assembler) AssignNull() error {
+	return na.kindMixin().AssignNull()
+}
+func (na *typedEnum__Assembler) AssignBool(bool) error {
+	return na.kindMixin().AssignBool(false)
+}
+func (na *typedEnum__Assembler) AssignInt(v int) error {
+	if _, ok := na.w.t.RepresentationStrategy().(EnumRepresentation_Int); !ok || !na.repr {
+		return mixins.StringAssembler{na.typeName()}.AssignInt(0)
+	}
+	m, ok := na.w.t.memberOfRepresentation(v)
+	if !ok {
+		return ErrInvalidEnumValue{na.w.t, v}
+	}
+	na.w.v = m
+	return nil
+}
+func (na *typedEnum__Assembler) AssignFloat(float64) error {
+	return na.kindMixin().AssignFloat(0)
+}
+func (na *typedEnum__Assembler) AssignString(v string) error {
+	if !na.repr {
+		if !na.w.t.HasMember(v) {
+			return ErrInvalidEnumValue{na.w.t, v}
+		}
+		na.w.v = v
+		return nil
+	}
+	if _, ok := na.w.t.RepresentationStrategy().(EnumRepresentation_String); !ok {
+		return mixins.IntAssembler{na.typeName()}.AssignString("")
+	}
+	m, ok := na.w.t.memberOfRepresentation(v)
+	if !ok {
+		return ErrInvalidEnumValue{na.w.t, v}
+	}
+	na.w.v = m
+	return nil
+}
+func (na *typedEnum__Assembler) AssignBytes([]byte) error {
+	return na.kindMixin().AssignBytes(nil)
+}
+func (na *typedEnum__Assembler) AssignLink(ipld.Link) error {
+	return na.kindMixin().AssignLink(nil)
+}
+func (na *typedEnum__Assembler) AssignNode(v ipld.Node) error {
+	if v.ReprKind() == ipld.ReprKind_Int {
+		if v2, err := v.AsInt(); err != nil {
+			return err
+		} else {
+			return na.AssignInt(v2)
+		}
+	}
+	if v2, err := v.AsString(); err != nil {
+		return err
+	} else {
+		return na.AssignString(v2)
+	}
+}
+func (na *typedEnum__Assembler) Style() ipld.NodeStyle {
+	if na.repr {
+		return Style__TypedEnumRepr{na.w.t}
+	}
+	return Style__TypedEnum{na.w.t}
+}
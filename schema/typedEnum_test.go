@@ -0,0 +1,84 @@
+package schema_test
+
+import (
+	"testing"
+
+	. "github.com/warpfork/go-wish"
+
+	ipld "github.com/ipld/go-ipld-prime"
+	basicnode "github.com/ipld/go-ipld-prime/node/basic"
+	"github.com/ipld/go-ipld-prime/schema"
+)
+
+func TestTypedEnum(t *testing.T) {
+	members := []string{"Red", "Green"}
+	t.Run("string representation", func(t *testing.T) {
+		typ := schema.SpawnEnum("Color", members, schema.SpawnEnumRepresentationString(map[string]string{"Green": "g"}))
+		for _, tcase := range []struct{ member, repr string }{{"Red", "Red"}, {"Green", "g"}} {
+			nb := schema.Style__TypedEnum{typ}.NewBuilder()
+			Wish(t, nb.AssignString(tcase.member), ShouldEqual, nil)
+			n := nb.Build()
+			Wish(t, n.ReprKind(), ShouldEqual, ipld.ReprKind_String)
+			s, err := n.AsString()
+			Wish(t, err, ShouldEqual, nil)
+			Wish(t, s, ShouldEqual, tcase.member)
+			Wish(t, n.(schema.TypedNode).Type(), ShouldEqual, typ)
+			rn := n.(schema.TypedNode).Representation()
+			Wish(t, rn.ReprKind(), ShouldEqual, ipld.ReprKind_String)
+			rs, err := rn.AsString()
+			Wish(t, err, ShouldEqual, nil)
+			Wish(t, rs, ShouldEqual, tcase.repr)
+
+			nb = schema.Style__TypedEnumRepr{typ}.NewBuilder()
+			Wish(t, nb.AssignString(tcase.repr), ShouldEqual, nil)
+			Wish(t, nb.Build(), ShouldEqual, n)
+			Wish(t, schema.Validate(typ, basicnode.NewString(tcase.repr)), ShouldEqual, nil)
+		}
+
+		nb := schema.Style__TypedEnum{typ}.NewBuilder()
+		Wish(t, nb.AssignString("Blue"), ShouldEqual, schema.ErrInvalidEnumValue{typ, "Blue"})
+		Wish(t, nb.AssignNode(basicnode.NewString("g")), ShouldEqual, schema.ErrInvalidEnumValue{typ, "g"})
+		Wish(t, nb.AssignInt(1), ShouldEqual, ipld.ErrWrongKind{TypeName: "Color", MethodName: "AssignInt", AppropriateKind: ipld.ReprKindSet_JustInt, ActualKind: ipld.ReprKind_String})
+		nb = schema.Style__TypedEnumRepr{typ}.NewBuilder()
+		Wish(t, nb.AssignString("Green"), ShouldEqual, schema.ErrInvalidEnumValue{typ, "Green"})
+		Wish(t, schema.Validate(typ, basicnode.NewString("Green")) != nil, ShouldEqual, true)
+	})
+	t.Run("int representation", func(t *testing.T) {
+		typ := schema.SpawnEnum("Color", members, schema.SpawnEnumRepresentationInt(map[string]int{"Red": 1, "Green": 2}))
+		for _, tcase := range []struct {
+			member string
+			repr   int
+		}{{"Red", 1}, {"Green", 2}} {
+			nb := schema.Style__TypedEnum{typ}.NewBuilder()
+			Wish(t, nb.AssignString(tcase.member), ShouldEqual, nil)
+			n := nb.Build()
+			Wish(t, n.ReprKind(), ShouldEqual, ipld.ReprKind_String)
+			s, err := n.AsString()
+			Wish(t, err, ShouldEqual, nil)
+			Wish(t, s, ShouldEqual, tcase.member)
+			rn := n.(schema.TypedNode).Representation()
+			Wish(t, rn.ReprKind(), ShouldEqual, ipld.ReprKind_Int)
+			ri, err := rn.AsInt()
+			Wish(t, err, ShouldEqual, nil)
+			Wish(t, ri, ShouldEqual, tcase.repr)
+			_, err = rn.AsString()
+			Wish(t, err, ShouldEqual, ipld.ErrWrongKind{TypeName: "Color.Repr", MethodName: "AsString", AppropriateKind: ipld.ReprKindSet_JustString, ActualKind: ipld.ReprKind_Int})
+
+			nb = schema.Style__TypedEnumRepr{typ}.NewBuilder()
+			Wish(t, nb.AssignNode(basicnode.NewInt(tcase.repr)), ShouldEqual, nil)
+			Wish(t, nb.Build(), ShouldEqual, n)
+			Wish(t, schema.Validate(typ, basicnode.NewInt(tcase.repr)), ShouldEqual, nil)
+		}
+
+		nb := schema.Style__TypedEnumRepr{typ}.NewBuilder()
+		Wish(t, nb.AssignInt(3), ShouldEqual, schema.ErrInvalidEnumValue{typ, 3})
+		Wish(t, nb.AssignString("Red"), ShouldEqual, ipld.ErrWrongKind{TypeName: "Color.Repr", MethodName: "AssignString", AppropriateKind: ipld.ReprKindSet_JustString, ActualKind: ipld.ReprKind_Int})
+		Wish(t, schema.Validate(typ, basicnode.NewInt(3)) != nil, ShouldEqual, true)
+		Wish(t, schema.Validate(typ, basicnode.NewString("Red")) != nil, ShouldEqual, true)
+	})
+	t.Run("error message", func(t *testing.T) {
+		typ := schema.SpawnEnum("Color", members, nil)
+		err := schema.Style__TypedEnum{typ}.NewBuilder().AssignString("Blue")
+		Wish(t, err.Error(), ShouldEqual, `"Blue" is not a member of the enum Color`)
+	})
+}
@@ -0,0 +1,178 @@
+package schema
+
+import (
+	ipld "github.com/ipld/go-ipld-prime"
+	"github.com/ipld/go-ipld-prime/node/mixins"
+)
+
+var (
+	_ ipld.Node          = &typedInt{}
+	_ TypedNode          = &typedInt{}
+	_ ipld.NodeStyle     = Style__TypedInt{}
+	_ ipld.NodeBuilder   = &typedInt__Builder{}
+	_ ipld.NodeAssembler = &typedInt__Assembler{}
+)
+
+// typedInt is a boxed int with a TypeInt, which was range checked
+// against the type's width (if it has one) when it was assigned.
+// It acts (and is represented) just like a plain int otherwise.
+type typedInt struct {
+	t TypeInt
+	v int
+}
+
+// -- Node interface methods -->
+
+func (typedInt) ReprKind() ipld.ReprKind {
+	return ipld.ReprKind_Int
+}
+func (n *typedInt) LookupString(string) (ipld.Node, error) {
+	return mixins.Int{string(n.t.Name())}.LookupString("")
+}
+func (n *typedInt) Lookup(key ipld.Node) (ipld.Node, error) {
+	return mixins.Int{string(n.t.Name())}.Lookup(nil)
+}
+func (n *typedInt) LookupIndex(idx int) (ipld.Node, error) {
+	return mixins.Int{string(n.t.Name())}.LookupIndex(0)
+}
+func (n *typedInt) LookupSegment(seg ipld.PathSegment) (ipld.Node, error) {
+	return mixins.Int{string(n.t.Name())}.LookupSegment(seg)
+}
+func (typedInt) MapIterator() ipld.MapIterator {
+	return nil
+}
+func (typedInt) ListIterator() ipld.ListIterator {
+	return nil
+}
+func (typedInt) Length() int {
+	return -1
+}
+func (typedInt) IsUndefined() bool {
+	return false
+}
+func (typedInt) IsNull() bool {
+	return false
+}
+func (n *typedInt) AsBool() (bool, error) {
+	return mixins.Int{string(n.t.Name())}.AsBool()
+}
+func (n *typedInt) AsInt() (int, error) {
+	return n.v, nil
+}
+func (n *typedInt) AsFloat() (float64, error) {
+	return mixins.Int{string(n.t.Name())}.AsFloat()
+}
+func (n *typedInt) AsString() (string, error) {
+	return mixins.Int{string(n.t.Name())}.AsString()
+}
+func (n *typedInt) AsBytes() ([]byte, error) {
+	return mixins.Int{string(n.t.Name())}.AsBytes()
+}
+func (n *typedInt) AsLink() (ipld.Link, error) {
+	return mixins.Int{string(n.t.Name())}.AsLink()
+}
+func (n *typedInt) Style() ipld.NodeStyle {
+	return Style__TypedInt{n.t}
+}
+
+// -- TypedNode interface methods -->
+
+func (n *typedInt) Type() Type {
+	return n.t
+}
+func (n *typedInt) Representation() ipld.Node {
+	return n
+}
+
+// -- NodeStyle -->
+
+// Style__TypedInt builds int nodes of the given TypeInt.
+// If the type is sized (see TypeInt.Bits), assigning a value which doesn't fit
+// in its width is rejected with ErrValueExceedsRange.
+// The resulting nodes are TypedNodes, and report ReprKind_Int.
+//
+// Its assemblers take exactly one value: a second assignment, or a Build
+// before anything was assigned, is misuse, and panics.
+// Assigning after Build (without a Reset) returns ipld.ErrBuilderConsumed, as basicnode's builders do.
+// (A value rejected as out of range doesn't count; the assembler may be given another.)
+type Style__TypedInt struct {
+	Type TypeInt
+}
+
+func (ns Style__TypedInt) NewBuilder() ipld.NodeBuilder {
+	return &typedInt__Builder{typedInt__Assembler{w: &typedInt{t: ns.Type}}}
+}
+
+// -- NodeBuilder -->
+
+type typedInt__Builder struct {
+	typedInt__Assembler
+}
+
+func (nb *typedInt__Builder) Build() ipld.Node {
+	if !nb.assigned {
+		panic("misuse")
+	}
+	nb.built = true
+	return nb.w
+}
+func (nb *typedInt__Builder) Reset() {
+	*nb = typedInt__Builder{typedInt__Assembler{w: &typedInt{t: nb.w.t}}}
+}
+
+// -- NodeAssembler -->
+
+type typedInt__Assembler struct {
+	w        *typedInt
+	assigned bool // set by the first successful assignment; any more are misuse.
+	built    bool // set by Build; after that, w belongs to the caller, and mustn't change.
+}
+
+func (na *typedInt__Assembler) BeginMap(sizeHint int) (ipld.MapAssembler, error) {
+	return mixins.IntAssembler{string(na.w.t.Name())}.BeginMap(0)
+}
+func (na *typedInt__Assembler) BeginList(sizeHint int) (ipld.ListAssembler, error) {
+	return mixins.IntAssembler{string(na.w.t.Name())}.BeginList(0)
+}
+func (na *typedInt__Assembler) AssignNull() error {
+	return mixins.IntAssembler{string(na.w.t.Name())}.AssignNull()
+}
+func (na *typedInt__Assembler) AssignBool(bool) error {
+	return mixins.IntAssembler{string(na.w.t.Name())}.AssignBool(false)
+}
+func (na *typedInt__Assembler) AssignInt(v int) error {
+	if na.built {
+		return ipld.ErrBuilderConsumed{"AssignInt"}
+	}
+	if na.assigned {
+		panic("misuse")
+	}
+	if !na.w.t.InRange(v) {
+		return ErrValueExceedsRange{na.w.t, v}
+	}
+	na.w.v = v
+	na.assigned = true
+	return nil
+}
+func (na *typedInt__Assembler) AssignFloat(float64) error {
+	return mixins.IntAssembler{string(na.w.t.Name())}.AssignFloat(0)
+}
+func (na *typedInt__Assembler) AssignString(string) error {
+	return mixins.IntAssembler{string(na.w.t.Name())}.AssignString("")
+}
+func (na *typedInt__Assembler) AssignBytes([]byte) error {
+	return mixins.IntAssembler{string(na.w.t.Name())}.AssignBytes(nil)
+}
+func (na *typedInt__Assembler) AssignLink(ipld.Link) error {
+	return mixins.IntAssembler{string(na.w.t.Name())}.AssignLink(nil)
+}
+func (na *typedInt__Assembler) AssignNode(v ipld.Node) error {
+	if v2, err := v.AsInt(); err != nil {
+		return err
+	} else {
+		return na.AssignInt(v2)
+	}
+}
+func (na *typedInt__Assembler) Style() ipld.NodeStyle {
+	return Style__TypedInt{na.w.t}
+}
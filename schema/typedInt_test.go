@@ -0,0 +1,71 @@
+package schema_test
+
+import (
+	"testing"
+
+	. "github.com/warpfork/go-wish"
+
+	ipld "github.com/ipld/go-ipld-prime"
+	basicnode "github.com/ipld/go-ipld-prime/node/basic"
+	"github.com/ipld/go-ipld-prime/schema"
+)
+
+func TestTypedInt(t *testing.T) {
+	for _, tcase := range []struct {
+		typ     schema.TypeInt
+		in, out []int
+	}{
+		{schema.SpawnIntWidth("Int8", 8), []int{0, 127, -128}, []int{128, -129, 300}},
+		{schema.SpawnIntWidth("Int16", 16), []int{32767, -32768}, []int{32768, -32769}},
+		{schema.SpawnInt("Int"), []int{0, 300, -300}, nil},
+	} {
+		t.Run(string(tcase.typ.Name()), func(t *testing.T) {
+			for _, v := range tcase.in {
+				nb := schema.Style__TypedInt{tcase.typ}.NewBuilder()
+				Wish(t, nb.AssignInt(v), ShouldEqual, nil)
+				n := nb.Build()
+				Wish(t, n.ReprKind(), ShouldEqual, ipld.ReprKind_Int)
+				v2, err := n.AsInt()
+				Wish(t, err, ShouldEqual, nil)
+				Wish(t, v2, ShouldEqual, v)
+				Wish(t, n.(schema.TypedNode).Type(), ShouldEqual, tcase.typ)
+				Wish(t, schema.Validate(tcase.typ, n), ShouldEqual, nil)
+			}
+			for _, v := range tcase.out {
+				nb := schema.Style__TypedInt{tcase.typ}.NewBuilder()
+				Wish(t, nb.AssignInt(v), ShouldEqual, schema.ErrValueExceedsRange{tcase.typ, v})
+				Wish(t, nb.AssignNode(basicnode.NewInt(v)), ShouldEqual, schema.ErrValueExceedsRange{tcase.typ, v})
+				Wish(t, schema.Validate(tcase.typ, basicnode.NewInt(v)) != nil, ShouldEqual, true)
+			}
+		})
+	}
+	t.Run("misuse", func(t *testing.T) {
+		nb := schema.Style__TypedInt{schema.SpawnIntWidth("Int8", 8)}.NewBuilder()
+		Wish(t, panicOf(func() { nb.Build() }), ShouldEqual, "misuse")
+		Wish(t, nb.AssignInt(300), ShouldBeSameTypeAs, schema.ErrValueExceedsRange{})
+		Wish(t, nb.AssignInt(3), ShouldEqual, nil)
+		Wish(t, panicOf(func() { nb.AssignInt(4) }), ShouldEqual, "misuse")
+		n := nb.Build()
+		Wish(t, nb.AssignNode(basicnode.NewInt(5)), ShouldEqual, ipld.ErrBuilderConsumed{"AssignInt"})
+		Wish(t, nb.Build() == n, ShouldEqual, true)
+		v, _ := n.AsInt()
+		Wish(t, v, ShouldEqual, 3)
+		nb.Reset()
+		Wish(t, nb.AssignInt(5), ShouldEqual, nil)
+		v, _ = nb.Build().AsInt()
+		Wish(t, v, ShouldEqual, 5)
+		v, _ = n.AsInt()
+		Wish(t, v, ShouldEqual, 3)
+	})
+	t.Run("error message", func(t *testing.T) {
+		err := schema.Style__TypedInt{schema.SpawnIntWidth("Int8", 8)}.NewBuilder().AssignInt(300)
+		Wish(t, err.Error(), ShouldEqual, "value 300 exceeds the range of Int8 (8 bits)")
+	})
+}
+
+// panicOf calls fn, and returns what it panicked with (or nil, if it didn't).
+func panicOf(fn func()) (r interface{}) {
+	defer func() { r = recover() }()
+	fn()
+	return nil
+}
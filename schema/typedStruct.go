@@ -0,0 +1,512 @@
+package schema
+
+import (
+	"fmt"
+
+	ipld "github.com/ipld/go-ipld-prime"
+	"github.com/ipld/go-ipld-prime/node/mixins"
+)
+
+var (
+	_ ipld.Node                           = &typedStruct{}
+	_ TypedNode                           = &typedStruct{}
+	_ ipld.Node                           = &typedStruct__Repr{}
+	_ ipld.NodeStyle                      = Style__TypedStruct{}
+	_ ipld.NodeStyle                      = Style__TypedStructRepr{}
+	_ ipld.NodeBuilder                    = &typedStruct__Builder{}
+	_ ipld.NodeAssembler                  = &typedStruct__Assembler{}
+	_ ipld.MapAssembler                   = &typedStruct__Assembler{}
+	_ ipld.MapIteratorSupportingUndefined = &typedStruct__MapItr{}
+)
+
+// typedStruct is a value of a TypeStruct, whose fields were checked when it was assigned:
+// required fields are all present; null is only given to nullable fields;
+// and each value is (if possible) a valid value of its field's type.
+//
+// It acts like a map, keyed by field name;
+// its representation is a map too, keyed by each field's representation key.
+// Absent (optional) fields are left out of both, unless the iterator is asked to
+// yield them (see ipld.MapIteratorSupportingUndefined), in which case they're ipld.Undef;
+// and looking them up gives ipld.Undef.
+// (Only the map representation strategy is supported so far.)
+type typedStruct struct {
+	t      TypeStruct
+	vs     ipld.NodeStyle // the ValueStyle it was built with.
+	values []ipld.Node    // in field order; nil for absent fields, and ipld.Null for null ones.
+}
+
+// field returns the value of the field at the given index, or Undef if it's absent.
+func (n *typedStruct) field(idx int) ipld.Node {
+	if n.values[idx] == nil {
+		return ipld.Undef
+	}
+	return n.values[idx]
+}
+
+// fieldIndex returns the index of the field with the given name
+// (or, for the representation, the given key), or -1.
+func (n *typedStruct) fieldIndex(key string, repr bool) int {
+	r := n.t.representation.(StructRepresentation_Map)
+	for i, f := range n.t.fields {
+		if (repr && r.GetFieldKey(f) == key) || (!repr && f.name == key) {
+			return i
+		}
+	}
+	return -1
+}
+
+// fieldKey returns the key of the field at the given index: its name,
+// or, for the representation, its representation key.
+func (n *typedStruct) fieldKey(idx int, repr bool) string {
+	if repr {
+		return n.t.representation.(StructRepresentation_Map).GetFieldKey(n.t.fields[idx])
+	}
+	return n.t.fields[idx].name
+}
+
+// -- Node interface methods -->
+
+func (typedStruct) ReprKind() ipld.ReprKind {
+	return ipld.ReprKind_Map
+}
+func (n *typedStruct) LookupString(key string) (ipld.Node, error) {
+	idx := n.fieldIndex(key, false)
+	if idx < 0 {
+		return nil, ErrNoSuchField{Type: n.t, FieldName: key}
+	}
+	return n.field(idx), nil
+}
+func (n *typedStruct) Lookup(key ipld.Node) (ipld.Node, error) {
+	ks, err := key.AsString()
+	if err != nil {
+		return nil, err
+	}
+	return n.LookupString(ks)
+}
+func (n *typedStruct) LookupIndex(idx int) (ipld.Node, error) {
+	return mixins.Map{string(n.t.Name())}.LookupIndex(0)
+}
+func (n *typedStruct) LookupSegment(seg ipld.PathSegment) (ipld.Node, error) {
+	return n.LookupString(seg.String())
+}
+func (n *typedStruct) MapIterator() ipld.MapIterator {
+	return &typedStruct__MapItr{n: n}
+}
+func (typedStruct) ListIterator() ipld.ListIterator {
+	return nil
+}
+func (n *typedStruct) Length() int {
+	// Counts only the present fields, same as the default iteration mode.
+	l := 0
+	for _, v := range n.values {
+		if v != nil {
+			l++
+		}
+	}
+	return l
+}
+func (typedStruct) IsUndefined() bool {
+	return false
+}
+func (typedStruct) IsNull() bool {
+	return false
+}
+func (n *typedStruct) AsBool() (bool, error) {
+	return mixins.Map{string(n.t.Name())}.AsBool()
+}
+func (n *typedStruct) AsInt() (int, error) {
+	return mixins.Map{string(n.t.Name())}.AsInt()
+}
+func (n *typedStruct) AsFloat() (float64, error) {
+	return mixins.Map{string(n.t.Name())}.AsFloat()
+}
+func (n *typedStruct) AsString() (string, error) {
+	return mixins.Map{string(n.t.Name())}.AsString()
+}
+func (n *typedStruct) AsBytes() ([]byte, error) {
+	return mixins.Map{string(n.t.Name())}.AsBytes()
+}
+func (n *typedStruct) AsLink() (ipld.Link, error) {
+	return mixins.Map{string(n.t.Name())}.AsLink()
+}
+func (n *typedStruct) Style() ipld.NodeStyle {
+	return Style__TypedStruct{n.t, n.vs}
+}
+
+// -- TypedNode interface methods -->
+
+func (n *typedStruct) Type() Type {
+	return n.t
+}
+
+// Representation returns a map keyed by the fields' representation keys,
+// whose values are the fields' representations.
+func (n *typedStruct) Representation() ipld.Node {
+	return (*typedStruct__Repr)(n)
+}
+
+// -- representation Node -->
+
+// typedStruct__Repr is the (map) representation of a typedStruct.
+// Unknown keys are ErrNotExists here (rather than ErrNoSuchField),
+// since the representation isn't a TypedNode.
+type typedStruct__Repr typedStruct
+
+func (typedStruct__Repr) ReprKind() ipld.ReprKind {
+	return ipld.ReprKind_Map
+}
+func (n *typedStruct__Repr) LookupString(key string) (ipld.Node, error) {
+	idx := (*typedStruct)(n).fieldIndex(key, true)
+	if idx < 0 {
+		return nil, ipld.ErrNotExists{ipld.PathSegmentOfString(key)}
+	}
+	return representationOf((*typedStruct)(n).field(idx)), nil
+}
+func (n *typedStruct__Repr) Lookup(key ipld.Node) (ipld.Node, error) {
+	ks, err := key.AsString()
+	if err != nil {
+		return nil, err
+	}
+	return n.LookupString(ks)
+}
+func (n *typedStruct__Repr) LookupIndex(idx int) (ipld.Node, error) {
+	return mixins.Map{string(n.t.Name()) + ".Repr"}.LookupIndex(0)
+}
+func (n *typedStruct__Repr) LookupSegment(seg ipld.PathSegment) (ipld.Node, error) {
+	return n.LookupString(seg.String())
+}
+func (n *typedStruct__Repr) MapIterator() ipld.MapIterator {
+	return &typedStruct__MapItr{n: (*typedStruct)(n), repr: true}
+}
+func (typedStruct__Repr) ListIterator() ipld.ListIterator {
+	return nil
+}
+func (n *typedStruct__Repr) Length() int {
+	return (*typedStruct)(n).Length()
+}
+func (typedStruct__Repr) IsUndefined() bool {
+	return false
+}
+func (typedStruct__Repr) IsNull() bool {
+	return false
+}
+func (n *typedStruct__Repr) AsBool() (bool, error) {
+	return mixins.Map{string(n.t.Name()) + ".Repr"}.AsBool()
+}
+func (n *typedStruct__Repr) AsInt() (int, error) {
+	return mixins.Map{string(n.t.Name()) + ".Repr"}.AsInt()
+}
+func (n *typedStruct__Repr) AsFloat() (float64, error) {
+	return mixins.Map{string(n.t.Name()) + ".Repr"}.AsFloat()
+}
+func (n *typedStruct__Repr) AsString() (string, error) {
+	return mixins.Map{string(n.t.Name()) + ".Repr"}.AsString()
+}
+func (n *typedStruct__Repr) AsBytes() ([]byte, error) {
+	return mixins.Map{string(n.t.Name()) + ".Repr"}.AsBytes()
+}
+func (n *typedStruct__Repr) AsLink() (ipld.Link, error) {
+	return mixins.Map{string(n.t.Name()) + ".Repr"}.AsLink()
+}
+func (n *typedStruct__Repr) Style() ipld.NodeStyle {
+	return Style__TypedStructRepr{n.t, n.vs}
+}
+
+// -- MapIterator -->
+
+// typedStruct__MapItr skips absent optional fields, unless asked to yield them as undefined.
+// It serves both views of the struct.
+type typedStruct__MapItr struct {
+	n          *typedStruct
+	repr       bool // if true, yield representation keys and values.
+	idx        int
+	yieldUndef bool
+}
+
+func (itr *typedStruct__MapItr) YieldUndefined(yes bool) {
+	itr.yieldUndef = yes
+}
+
+// next returns the index of the next field to yield, having skipped any absent fields if in that mode.
+// It doesn't modify the iterator, so that Done can use it too.
+func (itr *typedStruct__MapItr) next() int {
+	idx := itr.idx
+	for !itr.yieldUndef && idx < len(itr.n.values) && itr.n.values[idx] == nil {
+		idx++
+	}
+	return idx
+}
+func (itr *typedStruct__MapItr) Next() (k ipld.Node, v ipld.Node, _ error) {
+	itr.idx = itr.next()
+	if itr.idx >= len(itr.n.values) {
+		return nil, nil, ipld.ErrIteratorOverread{}
+	}
+	k = plainString(itr.n.fieldKey(itr.idx, itr.repr))
+	v = itr.n.field(itr.idx)
+	if itr.repr {
+		v = representationOf(v)
+	}
+	itr.idx++
+	return
+}
+func (itr *typedStruct__MapItr) Done() bool {
+	return itr.next() >= len(itr.n.values)
+}
+
+// -- NodeStyle -->
+
+// Style__TypedStruct builds nodes of the given TypeStruct, as a map keyed by field name.
+// A key which isn't a field is rejected with ipld.ErrInvalidStructKey,
+// and a repeated key with ipld.ErrRepeatedMapKey.
+// Finishing without all the required fields (those which aren't optional)
+// is rejected with ipld.ErrMissingRequiredField.
+// Only nullable fields may be null; assigning null to any other field is an error
+// (ipld.ErrWrongKind, or from Validate; see below).
+// The resulting nodes are TypedNodes, and report ReprKind_Map.
+//
+// Values of fields which are ints, enums, keyed unions, or structs (with the map representation)
+// are built with the typed styles of this package (so they're checked as they're assigned,
+// and are TypedNodes).
+// Values of any other type are built with ValueStyle (e.g. basicnode.Style__Any),
+// in their representation form, and are checked with Validate when the struct is finished;
+// if ValueStyle is nil, giving such a field is an error.
+//
+// Only structs with the map representation are supported so far: NewBuilder panics for any other.
+type Style__TypedStruct struct {
+	Type       TypeStruct
+	ValueStyle ipld.NodeStyle
+}
+
+func (ns Style__TypedStruct) NewBuilder() ipld.NodeBuilder {
+	mustBeMapRepr(ns.Type)
+	return &typedStruct__Builder{newTypedStructAssembler(ns.Type, ns.ValueStyle, false)}
+}
+
+// Style__TypedStructRepr builds the same nodes as Style__TypedStruct,
+// but from the type's (map) representation: a map keyed by the fields' representation keys
+// (so it's what to decode into).
+// Errors are the same as for Style__TypedStruct.
+type Style__TypedStructRepr struct {
+	Type       TypeStruct
+	ValueStyle ipld.NodeStyle
+}
+
+func (ns Style__TypedStructRepr) NewBuilder() ipld.NodeBuilder {
+	mustBeMapRepr(ns.Type)
+	return &typedStruct__Builder{newTypedStructAssembler(ns.Type, ns.ValueStyle, true)}
+}
+
+func mustBeMapRepr(t TypeStruct) {
+	if _, ok := t.representation.(StructRepresentation_Map); !ok {
+		panic(fmt.Sprintf("schema: typed nodes for structs with the %T representation aren't supported yet", t.representation))
+	}
+}
+
+// -- NodeBuilder -->
+
+type typedStruct__Builder struct {
+	typedStruct__Assembler
+}
+
+func (nb *typedStruct__Builder) Build() ipld.Node {
+	return nb.w
+}
+func (nb *typedStruct__Builder) Reset() {
+	*nb = typedStruct__Builder{newTypedStructAssembler(nb.w.t, nb.w.vs, nb.repr)}
+}
+
+// -- NodeAssembler -->
+
+type typedStruct__Assembler struct {
+	w     *typedStruct
+	repr  bool // if true, assign the representation, keyed by representation key, rather than by field name.
+	state maState
+	fas   []*typedStruct__FieldAssembler // in field order; nil for fields not given yet.
+	cur   int                            // the index of the field whose key was just assembled.
+	ka    stringKeyAssembler
+}
+
+func newTypedStructAssembler(t TypeStruct, vs ipld.NodeStyle, repr bool) typedStruct__Assembler {
+	return typedStruct__Assembler{
+		w:    &typedStruct{t: t, vs: vs, values: make([]ipld.Node, len(t.fields))},
+		repr: repr,
+		fas:  make([]*typedStruct__FieldAssembler, len(t.fields)),
+	}
+}
+
+func (na *typedStruct__Assembler) typeName() string {
+	if na.repr {
+		return string(na.w.t.Name()) + ".Repr"
+	}
+	return string(na.w.t.Name())
+}
+
+func (na *typedStruct__Assembler) BeginMap(sizeHint int) (ipld.MapAssembler, error) {
+	return na, nil
+}
+func (na *typedStruct__Assembler) BeginList(sizeHint int) (ipld.ListAssembler, error) {
+	return mixins.MapAssembler{na.typeName()}.BeginList(0)
+}
+func (na *typedStruct__Assembler) AssignNull() error {
+	return mixins.MapAssembler{na.typeName()}.AssignNull()
+}
+func (na *typedStruct__Assembler) AssignBool(bool) error {
+	return mixins.MapAssembler{na.typeName()}.AssignBool(false)
+}
+func (na *typedStruct__Assembler) AssignInt(int) error {
+	return mixins.MapAssembler{na.typeName()}.AssignInt(0)
+}
+func (na *typedStruct__Assembler) AssignFloat(float64) error {
+	return mixins.MapAssembler{na.typeName()}.AssignFloat(0)
+}
+func (na *typedStruct__Assembler) AssignString(string) error {
+	return mixins.MapAssembler{na.typeName()}.AssignString("")
+}
+func (na *typedStruct__Assembler) AssignBytes([]byte) error {
+	return mixins.MapAssembler{na.typeName()}.AssignBytes(nil)
+}
+func (na *typedStruct__Assembler) AssignLink(ipld.Link) error {
+	return mixins.MapAssembler{na.typeName()}.AssignLink(nil)
+}
+func (na *typedStruct__Assembler) AssignNode(v ipld.Node) error {
+	if v2, ok := v.(*typedStruct); ok && !na.repr && v2.t.Name() == na.w.t.Name() {
+		*na.w = *v2
+		na.state = maState_finished
+		return nil
+	}
+	return ipld.Copy(v, na)
+}
+func (na *typedStruct__Assembler) Style() ipld.NodeStyle {
+	if na.repr {
+		return Style__TypedStructRepr{na.w.t, na.w.vs}
+	}
+	return Style__TypedStruct{na.w.t, na.w.vs}
+}
+
+// -- MapAssembler -->
+
+func (ma *typedStruct__Assembler) AssembleEntry(k string) (ipld.NodeAssembler, error) {
+	if ma.state != maState_initial {
+		panic("misuse")
+	}
+	if err := ma.prepareField(k); err != nil {
+		return nil, err
+	}
+	return ma.fas[ma.cur], nil
+}
+
+// prepareField finds the field which the key names (by representation key, or by name),
+// and sets up the assembler for its value.
+func (ma *typedStruct__Assembler) prepareField(k string) error {
+	idx := ma.w.fieldIndex(k, ma.repr)
+	if idx < 0 {
+		return ipld.ErrInvalidStructKey{TypeName: ma.typeName(), Key: k}
+	}
+	if ma.fas[idx] != nil {
+		return ipld.ErrRepeatedMapKey{plainString(k)}
+	}
+	f := ma.w.t.fields[idx]
+	ns := typedStyleOf(f.typ, ma.repr, ma.w.vs)
+	if ns == nil {
+		return fmt.Errorf("schema: no NodeStyle for the %s field of struct %s (it needs a ValueStyle)", f.name, ma.w.t.Name())
+	}
+	ma.fas[idx] = &typedStruct__FieldAssembler{ns.NewBuilder(), f.nullable, false}
+	ma.cur = idx
+	return nil
+}
+func (ma *typedStruct__Assembler) AssembleKey() ipld.NodeAssembler {
+	if ma.state != maState_initial {
+		panic("misuse")
+	}
+	ma.state = maState_midKey
+	ma.ka = stringKeyAssembler{ma.typeName(), ma.assignKey}
+	return &ma.ka
+}
+func (ma *typedStruct__Assembler) assignKey(k string) error {
+	if ma.state != maState_midKey {
+		panic("misuse")
+	}
+	if err := ma.prepareField(k); err != nil {
+		return err
+	}
+	ma.state = maState_expectValue
+	return nil
+}
+func (ma *typedStruct__Assembler) AssembleValue() ipld.NodeAssembler {
+	if ma.state != maState_expectValue {
+		panic("misuse")
+	}
+	ma.state = maState_initial
+	return ma.fas[ma.cur]
+}
+func (ma *typedStruct__Assembler) Finish() error {
+	if ma.state != maState_initial {
+		panic("misuse")
+	}
+	var missing []string
+	for i, f := range ma.w.t.fields {
+		if ma.fas[i] == nil && !f.optional {
+			missing = append(missing, ma.w.fieldKey(i, ma.repr))
+		}
+	}
+	if missing != nil {
+		return ipld.ErrMissingRequiredField{TypeName: ma.typeName(), Missing: missing}
+	}
+	for i, fa := range ma.fas {
+		if fa == nil {
+			continue
+		}
+		if fa.null {
+			ma.w.values[i] = ipld.Null
+			continue
+		}
+		v := fa.Build()
+		if _, ok := v.(TypedNode); !ok {
+			// Built with the ValueStyle, so it's not been checked yet.
+			// (The error's Path is from the struct, as if Validate had been given the whole struct.)
+			p := ipld.Path{}.AppendSegmentString(ma.w.fieldKey(i, ma.repr))
+			if err := validate(p, ma.w.t.fields[i].typ, v); err != nil {
+				return err
+			}
+		}
+		ma.w.values[i] = v
+	}
+	ma.state = maState_finished
+	return nil
+}
+
+// KeyStyle builds plain strings: keys are field names, or representation keys for the representation.
+func (typedStruct__Assembler) KeyStyle() ipld.NodeStyle {
+	return plainString__Style{}
+}
+func (ma *typedStruct__Assembler) ValueStyle(k string) ipld.NodeStyle {
+	idx := ma.w.fieldIndex(k, ma.repr)
+	if idx < 0 {
+		return nil
+	}
+	return typedStyleOf(ma.w.t.fields[idx].typ, ma.repr, ma.w.vs)
+}
+
+// -- field NodeAssembler -->
+
+// typedStruct__FieldAssembler assembles a field's value with a builder for the field's type,
+// except for null, which nullable fields take themselves.
+type typedStruct__FieldAssembler struct {
+	ipld.NodeBuilder
+	nullable bool
+	null     bool // set if null was assigned to a nullable field.
+}
+
+func (fa *typedStruct__FieldAssembler) AssignNull() error {
+	if !fa.nullable {
+		return fa.NodeBuilder.AssignNull()
+	}
+	fa.null = true
+	return nil
+}
+func (fa *typedStruct__FieldAssembler) AssignNode(v ipld.Node) error {
+	if v.IsNull() {
+		return fa.AssignNull()
+	}
+	return fa.NodeBuilder.AssignNode(v)
+}
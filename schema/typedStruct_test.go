@@ -0,0 +1,144 @@
+package schema_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	. "github.com/warpfork/go-wish"
+
+	ipld "github.com/ipld/go-ipld-prime"
+	"github.com/ipld/go-ipld-prime/codec/dagjson"
+	basicnode "github.com/ipld/go-ipld-prime/node/basic"
+	"github.com/ipld/go-ipld-prime/schema"
+)
+
+func TestTypedStruct(t *testing.T) {
+	tInt := schema.SpawnInt("Int")
+	tString := schema.SpawnString("String")
+	typ := schema.SpawnStruct("S",
+		[]schema.StructField{
+			schema.SpawnStructField("req", tInt, false, false),
+			schema.SpawnStructField("opt", tString, true, false),
+			schema.SpawnStructField("nul", tInt, false, true),
+		},
+		schema.SpawnStructRepresentationMap(map[string]string{"req": "r"}),
+	)
+	reprStyle := schema.Style__TypedStructRepr{typ, basicnode.Style__Any{}}
+
+	decode := func(s string) (ipld.Node, error) {
+		nb := reprStyle.NewBuilder()
+		err := dagjson.Decoder(nb, strings.NewReader(s))
+		return nb.Build(), err
+	}
+	encode := func(n ipld.Node) string { // (compacted: none of the strings here have spaces.)
+		var buf bytes.Buffer
+		Require(t, dagjson.Encoder(n, &buf), ShouldEqual, nil)
+		return strings.Join(strings.Fields(buf.String()), "")
+	}
+	// entries lists what iterating yields, optionally including absent fields.
+	entries := func(n ipld.Node, yieldUndef bool) []string {
+		var got []string
+		itr := n.MapIterator()
+		itr.(ipld.MapIteratorSupportingUndefined).YieldUndefined(yieldUndef)
+		Require(t, ipld.DrainMapIterator(itr, func(k, v ipld.Node) error {
+			ks, _ := k.AsString()
+			switch {
+			case v.IsUndefined():
+				got = append(got, ks+"=undefined")
+			case v.IsNull():
+				got = append(got, ks+"=null")
+			default:
+				got = append(got, ks+"="+ipld.Sprint(v))
+			}
+			return nil
+		}), ShouldEqual, nil)
+		return got
+	}
+
+	t.Run("all fields present, with the nullable one null", func(t *testing.T) {
+		n, err := decode(`{"r":1,"opt":"x","nul":null}`)
+		Require(t, err, ShouldEqual, nil)
+		Wish(t, n.Length(), ShouldEqual, 3)
+		Wish(t, entries(n, false), ShouldEqual, []string{"req=1", `opt="x"`, "nul=null"})
+		v, err := n.LookupString("nul")
+		Wish(t, err, ShouldEqual, nil)
+		Wish(t, v.IsNull(), ShouldEqual, true)
+		Wish(t, encode(n.(schema.TypedNode).Representation()), ShouldEqual, `{"r":1,"opt":"x","nul":null}`)
+	})
+	t.Run("optional field absent", func(t *testing.T) {
+		n, err := decode(`{"nul":2,"r":1}`)
+		Require(t, err, ShouldEqual, nil)
+		Wish(t, n.Length(), ShouldEqual, 2)
+		Wish(t, entries(n, false), ShouldEqual, []string{"req=1", "nul=2"})
+		Wish(t, entries(n, true), ShouldEqual, []string{"req=1", "opt=undefined", "nul=2"})
+		Wish(t, entries(n.(schema.TypedNode).Representation(), true), ShouldEqual, []string{"r=1", "opt=undefined", "nul=2"})
+		v, err := n.LookupString("opt")
+		Wish(t, err, ShouldEqual, nil)
+		Wish(t, v.IsUndefined(), ShouldEqual, true)
+		Wish(t, encode(n.(schema.TypedNode).Representation()), ShouldEqual, `{"r":1,"nul":2}`)
+		Wish(t, encode(n), ShouldEqual, `{"req":1,"nul":2}`)
+	})
+	t.Run("required fields missing", func(t *testing.T) {
+		_, err := decode(`{"opt":"x"}`)
+		Wish(t, err, ShouldEqual, ipld.ErrMissingRequiredField{TypeName: "S.Repr", Missing: []string{"r", "nul"}})
+
+		nb := schema.Style__TypedStruct{typ, basicnode.Style__Any{}}.NewBuilder()
+		ma, err := nb.BeginMap(1)
+		Require(t, err, ShouldEqual, nil)
+		va, err := ma.AssembleEntry("nul")
+		Require(t, err, ShouldEqual, nil)
+		Wish(t, va.AssignNull(), ShouldEqual, nil)
+		Wish(t, ma.Finish(), ShouldEqual, ipld.ErrMissingRequiredField{TypeName: "S", Missing: []string{"req"}})
+	})
+	t.Run("null is only for nullable fields", func(t *testing.T) {
+		_, err := decode(`{"r":null,"nul":null}`)
+		Wish(t, err, ShouldEqual, ipld.ErrWrongKind{TypeName: "Int", MethodName: "AssignNull", AppropriateKind: ipld.ReprKindSet_JustNull, ActualKind: ipld.ReprKind_Int})
+		_, err = decode(`{"r":1,"opt":null,"nul":null}`) // (built with the ValueStyle, so checked by Validate.)
+		Wish(t, err, ShouldEqual, schema.ErrInvalidData{ipld.ParsePath("opt"), tString, "expected String, got Null"})
+	})
+	t.Run("KeyStyle builds the keys", func(t *testing.T) {
+		// LenientMapAssembler builds each key with KeyStyle before handing it on.
+		nb := reprStyle.NewBuilder()
+		ma, err := nb.BeginMap(2)
+		Require(t, err, ShouldEqual, nil)
+		ma = basicnode.LenientMapAssembler(ma)
+		Wish(t, ma.AssembleKey().AssignString("r"), ShouldEqual, nil)
+		Wish(t, ma.AssembleValue().AssignInt(1), ShouldEqual, nil)
+		Wish(t, ma.AssembleKey().AssignString("nul"), ShouldEqual, nil)
+		Wish(t, ma.AssembleValue().AssignInt(2), ShouldEqual, nil)
+		Wish(t, ma.Finish(), ShouldEqual, nil)
+		Wish(t, encode(nb.Build().(schema.TypedNode).Representation()), ShouldEqual, `{"r":1,"nul":2}`)
+	})
+	t.Run("invalid keys", func(t *testing.T) {
+		_, err := decode(`{"req":1}`)
+		Wish(t, err, ShouldEqual, ipld.ErrInvalidStructKey{TypeName: "S.Repr", Key: "req"})
+		_, err = decode(`{"r":1,"r":2}`)
+		Wish(t, err.Error(), ShouldEqual, `cannot repeat map key ("r")`)
+	})
+	t.Run("Validate agrees", func(t *testing.T) {
+		for _, tcase := range []struct {
+			json string
+			ok   bool
+		}{
+			{`{"r":1,"opt":"x","nul":null}`, true},
+			{`{"r":1,"nul":2}`, true},
+			{`{"opt":"x"}`, false},
+			{`{"r":null,"nul":null}`, false},
+		} {
+			nb := basicnode.Style__Any{}.NewBuilder()
+			Require(t, dagjson.Decoder(nb, strings.NewReader(tcase.json)), ShouldEqual, nil)
+			Wish(t, schema.Validate(typ, nb.Build()) == nil, ShouldEqual, tcase.ok)
+			_, err := decode(tcase.json)
+			Wish(t, err == nil, ShouldEqual, tcase.ok)
+		}
+	})
+	t.Run("as a union member", func(t *testing.T) {
+		u := schema.SpawnUnionKeyed("U", map[string]schema.Type{"s": typ})
+		nb := schema.Style__TypedUnionRepr{u, basicnode.Style__Any{}}.NewBuilder()
+		Require(t, dagjson.Decoder(nb, strings.NewReader(`{"s":{"r":1,"nul":null}}`)), ShouldEqual, nil)
+		n := nb.Build()
+		Wish(t, encode(n), ShouldEqual, `{"S":{"req":1,"nul":null}}`)
+		Wish(t, encode(n.(schema.TypedNode).Representation()), ShouldEqual, `{"s":{"r":1,"nul":null}}`)
+	})
+}
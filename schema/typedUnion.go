@@ -0,0 +1,407 @@
+package schema
+
+import (
+	"fmt"
+
+	ipld "github.com/ipld/go-ipld-prime"
+	"github.com/ipld/go-ipld-prime/node/mixins"
+)
+
+var (
+	_ ipld.Node          = &typedUnion{}
+	_ TypedNode          = &typedUnion{}
+	_ ipld.Node          = &typedUnion__Repr{}
+	_ ipld.NodeStyle     = Style__TypedUnion{}
+	_ ipld.NodeStyle     = Style__TypedUnionRepr{}
+	_ ipld.NodeBuilder   = &typedUnion__Builder{}
+	_ ipld.NodeAssembler = &typedUnion__Assembler{}
+	_ ipld.MapAssembler  = &typedUnion__Assembler{}
+)
+
+// typedUnion is a value of a TypeUnion: one of its members, which was checked
+// to be a member (and, if possible, to be a valid value of that member's type) when it was assigned.
+//
+// It acts like a map with a single entry, whose key is the name of the member's type;
+// its representation is a map with a single entry too, whose key is the member's discriminant.
+// (Only the keyed representation strategy is supported so far.)
+type typedUnion struct {
+	t      TypeUnion
+	vs     ipld.NodeStyle // the ValueStyle it was built with.
+	member Type
+	v      ipld.Node
+}
+
+// -- Node interface methods -->
+
+func (typedUnion) ReprKind() ipld.ReprKind {
+	return ipld.ReprKind_Map
+}
+func (n *typedUnion) LookupString(key string) (ipld.Node, error) {
+	if string(n.member.Name()) == key {
+		return n.v, nil
+	}
+	if _, ok := n.t.memberOfName(key); ok {
+		return nil, ipld.ErrNotExists{ipld.PathSegmentOfString(key)}
+	}
+	return nil, ErrNoSuchField{Type: n.t, FieldName: key}
+}
+func (n *typedUnion) Lookup(key ipld.Node) (ipld.Node, error) {
+	ks, err := key.AsString()
+	if err != nil {
+		return nil, err
+	}
+	return n.LookupString(ks)
+}
+func (n *typedUnion) LookupIndex(idx int) (ipld.Node, error) {
+	return mixins.Map{string(n.t.Name())}.LookupIndex(0)
+}
+func (n *typedUnion) LookupSegment(seg ipld.PathSegment) (ipld.Node, error) {
+	return n.LookupString(seg.String())
+}
+func (n *typedUnion) MapIterator() ipld.MapIterator {
+	return &singleEntryMapIterator{k: plainString(n.member.Name()), v: n.v}
+}
+func (typedUnion) ListIterator() ipld.ListIterator {
+	return nil
+}
+func (typedUnion) Length() int {
+	return 1
+}
+func (typedUnion) IsUndefined() bool {
+	return false
+}
+func (typedUnion) IsNull() bool {
+	return false
+}
+func (n *typedUnion) AsBool() (bool, error) {
+	return mixins.Map{string(n.t.Name())}.AsBool()
+}
+func (n *typedUnion) AsInt() (int, error) {
+	return mixins.Map{string(n.t.Name())}.AsInt()
+}
+func (n *typedUnion) AsFloat() (float64, error) {
+	return mixins.Map{string(n.t.Name())}.AsFloat()
+}
+func (n *typedUnion) AsString() (string, error) {
+	return mixins.Map{string(n.t.Name())}.AsString()
+}
+func (n *typedUnion) AsBytes() ([]byte, error) {
+	return mixins.Map{string(n.t.Name())}.AsBytes()
+}
+func (n *typedUnion) AsLink() (ipld.Link, error) {
+	return mixins.Map{string(n.t.Name())}.AsLink()
+}
+func (n *typedUnion) Style() ipld.NodeStyle {
+	return Style__TypedUnion{n.t, n.vs}
+}
+
+// -- TypedNode interface methods -->
+
+func (n *typedUnion) Type() Type {
+	return n.t
+}
+
+// Representation returns a map with a single entry,
+// whose key is the member's discriminant, and whose value is the member's representation.
+func (n *typedUnion) Representation() ipld.Node {
+	return (*typedUnion__Repr)(n)
+}
+
+// -- representation Node -->
+
+// typedUnion__Repr is the (keyed) representation of a typedUnion.
+type typedUnion__Repr typedUnion
+
+func (typedUnion__Repr) ReprKind() ipld.ReprKind {
+	return ipld.ReprKind_Map
+}
+func (n *typedUnion__Repr) LookupString(key string) (ipld.Node, error) {
+	if n.t.keyOfMember(n.member) == key {
+		return representationOf(n.v), nil
+	}
+	return nil, ipld.ErrNotExists{ipld.PathSegmentOfString(key)}
+}
+func (n *typedUnion__Repr) Lookup(key ipld.Node) (ipld.Node, error) {
+	ks, err := key.AsString()
+	if err != nil {
+		return nil, err
+	}
+	return n.LookupString(ks)
+}
+func (n *typedUnion__Repr) LookupIndex(idx int) (ipld.Node, error) {
+	return mixins.Map{string(n.t.Name()) + ".Repr"}.LookupIndex(0)
+}
+func (n *typedUnion__Repr) LookupSegment(seg ipld.PathSegment) (ipld.Node, error) {
+	return n.LookupString(seg.String())
+}
+func (n *typedUnion__Repr) MapIterator() ipld.MapIterator {
+	return &singleEntryMapIterator{k: plainString(n.t.keyOfMember(n.member)), v: representationOf(n.v)}
+}
+func (typedUnion__Repr) ListIterator() ipld.ListIterator {
+	return nil
+}
+func (typedUnion__Repr) Length() int {
+	return 1
+}
+func (typedUnion__Repr) IsUndefined() bool {
+	return false
+}
+func (typedUnion__Repr) IsNull() bool {
+	return false
+}
+func (n *typedUnion__Repr) AsBool() (bool, error) {
+	return mixins.Map{string(n.t.Name()) + ".Repr"}.AsBool()
+}
+func (n *typedUnion__Repr) AsInt() (int, error) {
+	return mixins.Map{string(n.t.Name()) + ".Repr"}.AsInt()
+}
+func (n *typedUnion__Repr) AsFloat() (float64, error) {
+	return mixins.Map{string(n.t.Name()) + ".Repr"}.AsFloat()
+}
+func (n *typedUnion__Repr) AsString() (string, error) {
+	return mixins.Map{string(n.t.Name()) + ".Repr"}.AsString()
+}
+func (n *typedUnion__Repr) AsBytes() ([]byte, error) {
+	return mixins.Map{string(n.t.Name()) + ".Repr"}.AsBytes()
+}
+func (n *typedUnion__Repr) AsLink() (ipld.Link, error) {
+	return mixins.Map{string(n.t.Name()) + ".Repr"}.AsLink()
+}
+func (n *typedUnion__Repr) Style() ipld.NodeStyle {
+	return Style__TypedUnionRepr{n.t, n.vs}
+}
+
+// -- NodeStyle -->
+
+// Style__TypedUnion builds nodes of the given TypeUnion, as a map with a single entry:
+// the key is the name of the member's type, and the value is the member's value.
+// A key which isn't the name of a member is rejected with ipld.ErrInvalidUnionDiscriminant,
+// and giving more or less than one member is rejected with ipld.ErrUnionMemberCount.
+// The resulting nodes are TypedNodes, and report ReprKind_Map.
+//
+// Values of members which are ints, enums, keyed unions, or structs (with the map representation) are built with the typed styles
+// of this package (so they're checked as they're assigned, and are TypedNodes).
+// Values of any other type are built with ValueStyle (e.g. basicnode.Style__Any),
+// in their representation form, and are checked with Validate when the union is finished;
+// if ValueStyle is nil, giving such a member is an error.
+//
+// Only keyed unions are supported so far: NewBuilder panics for any other.
+type Style__TypedUnion struct {
+	Type       TypeUnion
+	ValueStyle ipld.NodeStyle
+}
+
+func (ns Style__TypedUnion) NewBuilder() ipld.NodeBuilder {
+	mustBeKeyed(ns.Type)
+	return &typedUnion__Builder{typedUnion__Assembler{w: &typedUnion{t: ns.Type, vs: ns.ValueStyle}}}
+}
+
+// Style__TypedUnionRepr builds the same nodes as Style__TypedUnion,
+// but from the type's (keyed) representation: a map with a single entry,
+// whose key is the member's discriminant, and whose value is the member's representation
+// (so it's what to decode into).
+// Errors are the same as for Style__TypedUnion.
+type Style__TypedUnionRepr struct {
+	Type       TypeUnion
+	ValueStyle ipld.NodeStyle
+}
+
+func (ns Style__TypedUnionRepr) NewBuilder() ipld.NodeBuilder {
+	mustBeKeyed(ns.Type)
+	return &typedUnion__Builder{typedUnion__Assembler{w: &typedUnion{t: ns.Type, vs: ns.ValueStyle}, repr: true}}
+}
+
+func mustBeKeyed(t TypeUnion) {
+	if t.style != UnionStyle_Keyed {
+		panic(fmt.Sprintf("schema: typed nodes for %s unions aren't supported yet", t.style.x))
+	}
+}
+
+// -- NodeBuilder -->
+
+type typedUnion__Builder struct {
+	typedUnion__Assembler
+}
+
+func (nb *typedUnion__Builder) Build() ipld.Node {
+	return nb.w
+}
+func (nb *typedUnion__Builder) Reset() {
+	*nb = typedUnion__Builder{typedUnion__Assembler{w: &typedUnion{t: nb.w.t, vs: nb.w.vs}, repr: nb.repr}}
+}
+
+// -- NodeAssembler -->
+
+type typedUnion__Assembler struct {
+	w     *typedUnion
+	repr  bool // if true, assign the representation, keyed by discriminant, rather than by type name.
+	state maState
+	vb    ipld.NodeBuilder // builds the member's value; Build is called when the union is finished.
+	ka    stringKeyAssembler
+}
+
+func (na *typedUnion__Assembler) typeName() string {
+	if na.repr {
+		return string(na.w.t.Name()) + ".Repr"
+	}
+	return string(na.w.t.Name())
+}
+
+func (na *typedUnion__Assembler) BeginMap(sizeHint int) (ipld.MapAssembler, error) {
+	return na, nil
+}
+func (na *typedUnion__Assembler) BeginList(sizeHint int) (ipld.ListAssembler, error) {
+	return mixins.MapAssembler{na.typeName()}.BeginList(0)
+}
+func (na *typedUnion__Assembler) AssignNull() error {
+	return mixins.MapAssembler{na.typeName()}.AssignNull()
+}
+func (na *typedUnion__Assembler) AssignBool(bool) error {
+	return mixins.MapAssembler{na.typeName()}.AssignBool(false)
+}
+func (na *typedUnion__Assembler) AssignInt(int) error {
+	return mixins.MapAssembler{na.typeName()}.AssignInt(0)
+}
+func (na *typedUnion__Assembler) AssignFloat(float64) error {
+	return mixins.MapAssembler{na.typeName()}.AssignFloat(0)
+}
+func (na *typedUnion__Assembler) AssignString(string) error {
+	return mixins.MapAssembler{na.typeName()}.AssignString("")
+}
+func (na *typedUnion__Assembler) AssignBytes([]byte) error {
+	return mixins.MapAssembler{na.typeName()}.AssignBytes(nil)
+}
+func (na *typedUnion__Assembler) AssignLink(ipld.Link) error {
+	return mixins.MapAssembler{na.typeName()}.AssignLink(nil)
+}
+func (na *typedUnion__Assembler) AssignNode(v ipld.Node) error {
+	if v2, ok := v.(*typedUnion); ok && !na.repr && v2.t.Name() == na.w.t.Name() {
+		*na.w = *v2
+		na.state = maState_finished
+		return nil
+	}
+	return ipld.Copy(v, na)
+}
+func (na *typedUnion__Assembler) Style() ipld.NodeStyle {
+	if na.repr {
+		return Style__TypedUnionRepr{na.w.t, na.w.vs}
+	}
+	return Style__TypedUnion{na.w.t, na.w.vs}
+}
+
+// -- MapAssembler -->
+
+func (ma *typedUnion__Assembler) AssembleEntry(k string) (ipld.NodeAssembler, error) {
+	if ma.state != maState_initial {
+		panic("misuse")
+	}
+	if err := ma.prepareMember(k); err != nil {
+		return nil, err
+	}
+	return ma.vb, nil
+}
+
+// prepareMember finds the member which the key names (by discriminant, or by type name),
+// and sets up the builder for its value.
+func (ma *typedUnion__Assembler) prepareMember(k string) error {
+	var member Type
+	var ok bool
+	if ma.repr {
+		member, ok = ma.w.t.memberOfKey(k)
+	} else {
+		member, ok = ma.w.t.memberOfName(k)
+	}
+	if !ok {
+		return ipld.ErrInvalidUnionDiscriminant{TypeName: ma.typeName(), Key: k}
+	}
+	if ma.w.member != nil {
+		return ipld.ErrUnionMemberCount{TypeName: ma.typeName(), Count: 2}
+	}
+	ns := typedStyleOf(member, ma.repr, ma.w.vs)
+	if ns == nil {
+		return fmt.Errorf("schema: no NodeStyle for the %s member of union %s (it needs a ValueStyle)", member.Name(), ma.w.t.Name())
+	}
+	ma.w.member = member
+	ma.vb = ns.NewBuilder()
+	return nil
+}
+func (ma *typedUnion__Assembler) AssembleKey() ipld.NodeAssembler {
+	if ma.state != maState_initial {
+		panic("misuse")
+	}
+	ma.state = maState_midKey
+	ma.ka = stringKeyAssembler{ma.typeName(), ma.assignKey}
+	return &ma.ka
+}
+func (ma *typedUnion__Assembler) assignKey(k string) error {
+	if ma.state != maState_midKey {
+		panic("misuse")
+	}
+	if err := ma.prepareMember(k); err != nil {
+		return err
+	}
+	ma.state = maState_expectValue
+	return nil
+}
+func (ma *typedUnion__Assembler) AssembleValue() ipld.NodeAssembler {
+	if ma.state != maState_expectValue {
+		panic("misuse")
+	}
+	ma.state = maState_initial
+	return ma.vb
+}
+func (ma *typedUnion__Assembler) Finish() error {
+	if ma.state != maState_initial {
+		panic("misuse")
+	}
+	if ma.w.member == nil {
+		return ipld.ErrUnionMemberCount{TypeName: ma.typeName(), Count: 0}
+	}
+	v := ma.vb.Build()
+	if _, ok := v.(TypedNode); !ok {
+		// Built with the ValueStyle, so it's not been checked yet.
+		if err := Validate(ma.w.member, v); err != nil {
+			return err
+		}
+	}
+	ma.w.v = v
+	ma.state = maState_finished
+	return nil
+}
+
+// KeyStyle builds plain strings: keys are type names, or discriminants for the representation.
+func (typedUnion__Assembler) KeyStyle() ipld.NodeStyle {
+	return plainString__Style{}
+}
+func (ma *typedUnion__Assembler) ValueStyle(k string) ipld.NodeStyle {
+	var member Type
+	var ok bool
+	if ma.repr {
+		member, ok = ma.w.t.memberOfKey(k)
+	} else {
+		member, ok = ma.w.t.memberOfName(k)
+	}
+	if !ok {
+		return nil
+	}
+	return typedStyleOf(member, ma.repr, ma.w.vs)
+}
+
+// -- MapIterator -->
+
+// singleEntryMapIterator iterates over a map with one entry.
+type singleEntryMapIterator struct {
+	k, v ipld.Node
+	done bool
+}
+
+func (itr *singleEntryMapIterator) Next() (ipld.Node, ipld.Node, error) {
+	if itr.done {
+		return nil, nil, ipld.ErrIteratorOverread{}
+	}
+	itr.done = true
+	return itr.k, itr.v, nil
+}
+func (itr *singleEntryMapIterator) Done() bool {
+	return itr.done
+}
@@ -0,0 +1,146 @@
+package schema_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	. "github.com/warpfork/go-wish"
+
+	ipld "github.com/ipld/go-ipld-prime"
+	"github.com/ipld/go-ipld-prime/codec/dagjson"
+	basicnode "github.com/ipld/go-ipld-prime/node/basic"
+	"github.com/ipld/go-ipld-prime/schema"
+)
+
+func TestTypedUnionKeyed(t *testing.T) {
+	tColor := schema.SpawnEnum("Color", []string{"Red", "Green"}, schema.SpawnEnumRepresentationInt(map[string]int{"Red": 1, "Green": 2}))
+	tCount := schema.SpawnIntWidth("Count", 8)
+	tName := schema.SpawnString("Name")
+	typ := schema.SpawnUnionKeyed("Thing", map[string]schema.Type{
+		"color": tColor,
+		"count": tCount,
+		"name":  tName,
+	})
+	reprStyle := schema.Style__TypedUnionRepr{typ, basicnode.Style__Any{}}
+
+	decode := func(s string) (ipld.Node, error) {
+		nb := reprStyle.NewBuilder()
+		err := dagjson.Decoder(nb, strings.NewReader(s))
+		return nb.Build(), err
+	}
+	encode := func(n ipld.Node) string { // (compacted: none of the strings here have spaces.)
+		var buf bytes.Buffer
+		Require(t, dagjson.Encoder(n, &buf), ShouldEqual, nil)
+		return strings.Join(strings.Fields(buf.String()), "")
+	}
+
+	t.Run("round trip through the representation", func(t *testing.T) {
+		for _, tcase := range []struct {
+			json   string
+			member string // the name of the member's type.
+			value  interface{}
+		}{
+			{`{"color":2}`, "Color", "Green"},
+			{`{"count":5}`, "Count", 5},
+			{`{"name":"x"}`, "Name", "x"},
+		} {
+			n, err := decode(tcase.json)
+			Require(t, err, ShouldEqual, nil)
+			Wish(t, n.ReprKind(), ShouldEqual, ipld.ReprKind_Map)
+			Wish(t, n.Length(), ShouldEqual, 1)
+			Wish(t, n.(schema.TypedNode).Type(), ShouldEqual, typ)
+			v, err := n.LookupString(tcase.member)
+			Require(t, err, ShouldEqual, nil)
+			switch tcase.value.(type) {
+			case string:
+				s, err := v.AsString()
+				Wish(t, err, ShouldEqual, nil)
+				Wish(t, s, ShouldEqual, tcase.value)
+			case int:
+				i, err := v.AsInt()
+				Wish(t, err, ShouldEqual, nil)
+				Wish(t, i, ShouldEqual, tcase.value)
+			}
+			Wish(t, encode(n.(schema.TypedNode).Representation()), ShouldEqual, tcase.json)
+		}
+	})
+	t.Run("the type-level view is keyed by type name", func(t *testing.T) {
+		n, err := decode(`{"color":1}`)
+		Require(t, err, ShouldEqual, nil)
+		Wish(t, encode(n), ShouldEqual, `{"Color":"Red"}`)
+		_, err = n.LookupString("Count")
+		Wish(t, err, ShouldEqual, ipld.ErrNotExists{ipld.PathSegmentOfString("Count")})
+		_, err = n.LookupString("color")
+		Wish(t, err, ShouldEqual, schema.ErrNoSuchField{Type: typ, FieldName: "color"})
+
+		nb := schema.Style__TypedUnion{typ, basicnode.Style__Any{}}.NewBuilder()
+		ma, err := nb.BeginMap(1)
+		Require(t, err, ShouldEqual, nil)
+		va, err := ma.AssembleEntry("Count")
+		Require(t, err, ShouldEqual, nil)
+		Wish(t, va.AssignInt(7), ShouldEqual, nil)
+		Wish(t, ma.Finish(), ShouldEqual, nil)
+		Wish(t, encode(nb.Build().(schema.TypedNode).Representation()), ShouldEqual, `{"count":7}`)
+	})
+	t.Run("KeyStyle builds the keys", func(t *testing.T) {
+		// LenientMapAssembler builds each key with KeyStyle before handing it on.
+		nb := reprStyle.NewBuilder()
+		ma, err := nb.BeginMap(1)
+		Require(t, err, ShouldEqual, nil)
+		ma = basicnode.LenientMapAssembler(ma)
+		Wish(t, ma.AssembleKey().AssignString("count"), ShouldEqual, nil)
+		Wish(t, ma.AssembleValue().AssignInt(3), ShouldEqual, nil)
+		Wish(t, ma.Finish(), ShouldEqual, nil)
+		Wish(t, encode(nb.Build().(schema.TypedNode).Representation()), ShouldEqual, `{"count":3}`)
+
+		kb := ma.KeyStyle().NewBuilder()
+		Wish(t, kb.AssignInt(1), ShouldBeSameTypeAs, ipld.ErrWrongKind{})
+		Require(t, kb.AssignString("name"), ShouldEqual, nil)
+		Wish(t, ipld.DeepEqual(kb.Build(), basicnode.NewString("name")), ShouldEqual, true)
+		Wish(t, kb.Build().Style(), ShouldEqual, ma.KeyStyle())
+		Wish(t, kb.AssignString("x"), ShouldEqual, ipld.ErrBuilderConsumed{"AssignString"})
+	})
+	t.Run("invalid unions are rejected", func(t *testing.T) {
+		_, err := decode(`{"size":1}`)
+		Wish(t, err, ShouldEqual, ipld.ErrInvalidUnionDiscriminant{TypeName: "Thing.Repr", Key: "size"})
+		_, err = decode(`{"count":1,"name":"x"}`)
+		Wish(t, err, ShouldEqual, ipld.ErrUnionMemberCount{TypeName: "Thing.Repr", Count: 2})
+		_, err = decode(`{}`)
+		Wish(t, err, ShouldEqual, ipld.ErrUnionMemberCount{TypeName: "Thing.Repr", Count: 0})
+		_, err = decode(`"count"`)
+		Wish(t, err, ShouldEqual, ipld.ErrWrongKind{TypeName: "Thing.Repr", MethodName: "AssignString", AppropriateKind: ipld.ReprKindSet_JustString, ActualKind: ipld.ReprKind_Map})
+	})
+	t.Run("invalid members are rejected", func(t *testing.T) {
+		_, err := decode(`{"color":3}`)
+		Wish(t, err, ShouldEqual, schema.ErrInvalidEnumValue{tColor, 3})
+		_, err = decode(`{"count":300}`)
+		Wish(t, err, ShouldEqual, schema.ErrValueExceedsRange{tCount, 300})
+		_, err = decode(`{"name":1}`) // (built with the ValueStyle, so checked by Validate.)
+		Wish(t, err, ShouldEqual, schema.ErrInvalidData{ipld.Path{}, tName, "expected String, got Int"})
+	})
+	t.Run("Validate", func(t *testing.T) {
+		for _, tcase := range []struct {
+			json string
+			ok   bool
+		}{
+			{`{"color":2}`, true},
+			{`{"name":"x"}`, true},
+			{`{"color":3}`, false},
+			{`{"size":1}`, false},
+			{`{"count":1,"name":"x"}`, false},
+			{`{}`, false},
+			{`[]`, false},
+		} {
+			nb := basicnode.Style__Any{}.NewBuilder()
+			Require(t, dagjson.Decoder(nb, strings.NewReader(tcase.json)), ShouldEqual, nil)
+			Wish(t, schema.Validate(typ, nb.Build()) == nil, ShouldEqual, tcase.ok)
+		}
+	})
+	t.Run("error messages", func(t *testing.T) {
+		_, err := decode(`{"size":1}`)
+		Wish(t, err.Error(), ShouldEqual, `invalid discriminant for union Thing.Repr: "size" is not a member`)
+		_, err = decode(`{}`)
+		Wish(t, err.Error(), ShouldEqual, `union Thing.Repr must have exactly one member, but 0 were given`)
+	})
+}
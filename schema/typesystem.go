@@ -11,4 +11,19 @@ type TypeSystem struct {
 	// definition if those type are either A) in this namedTypes map,
 	// or B) are IsAnonymous==true.
 	namedTypes map[TypeName]Type
+
+	// names lists the keys of namedTypes, in the order they were given.
+	names []TypeName
+}
+
+// TypeByName returns the named type with the given name, or nil if there's none.
+func (ts *TypeSystem) TypeByName(name TypeName) Type {
+	return ts.namedTypes[name]
+}
+
+// Names returns the names of all the named types, in the order they were defined.
+//
+// It is not lawful to mutate nor append the returned slice.
+func (ts *TypeSystem) Names() []TypeName {
+	return ts.names
 }
@@ -68,3 +68,217 @@ package schema
 	returns *only* errors: only then we can have it in the schema package.
 
 */
+
+import (
+	"fmt"
+	"strings"
+
+	ipld "github.com/ipld/go-ipld-prime"
+)
+
+// Validate checks whether a Node conforms to a Type, without building
+// a typed node: it's "option 1" from the notes above.
+//
+// The node is expected to be in the type's representation form -- that is,
+// the shape the data has when serialized, as e.g. a decoder would produce it.
+// So a struct with a map representation must be a map (with keys renamed as
+// the representation says), one with a tuple representation must be a list,
+// and so on.
+//
+// Validate stops at the first problem found, and returns it as an ErrInvalidData.
+// Links are checked to be links, but not loaded.
+// Of unions, only those with the keyed representation are supported so far;
+// the others result in an error.
+func Validate(t Type, n ipld.Node) error {
+	return validate(ipld.Path{}, t, n)
+}
+
+func invalid(p ipld.Path, t Type, format string, args ...interface{}) error {
+	return ErrInvalidData{p, t, fmt.Sprintf(format, args...)}
+}
+
+func validate(p ipld.Path, t Type, n ipld.Node) error {
+	if n.IsUndefined() {
+		return invalid(p, t, "node is undefined")
+	}
+	switch t2 := t.(type) {
+	case TypeBool:
+		return validateKind(p, t, n, ipld.ReprKind_Bool)
+	case TypeString:
+		return validateKind(p, t, n, ipld.ReprKind_String)
+	case TypeBytes:
+		return validateKind(p, t, n, ipld.ReprKind_Bytes)
+	case TypeInt:
+		if err := validateKind(p, t, n, ipld.ReprKind_Int); err != nil {
+			return err
+		}
+		if v, _ := n.AsInt(); !t2.InRange(v) {
+			return invalid(p, t, "%d does not fit in %d bits", v, t2.Bits())
+		}
+		return nil
+	case TypeFloat:
+		return validateKind(p, t, n, ipld.ReprKind_Float)
+	case TypeLink:
+		return validateKind(p, t, n, ipld.ReprKind_Link)
+	case TypeEnum:
+		var v interface{}
+		if _, ok := t2.RepresentationStrategy().(EnumRepresentation_Int); ok {
+			if err := validateKind(p, t, n, ipld.ReprKind_Int); err != nil {
+				return err
+			}
+			v, _ = n.AsInt()
+		} else {
+			if err := validateKind(p, t, n, ipld.ReprKind_String); err != nil {
+				return err
+			}
+			v, _ = n.AsString()
+		}
+		if _, ok := t2.memberOfRepresentation(v); !ok {
+			return invalid(p, t, "%#v is not a member of the enum", v)
+		}
+		return nil
+	case TypeList:
+		if err := validateKind(p, t, n, ipld.ReprKind_List); err != nil {
+			return err
+		}
+		for itr := n.ListIterator(); !itr.Done(); {
+			idx, v, err := itr.Next()
+			if err != nil {
+				return err
+			}
+			if err := validateValue(p.AppendSegment(ipld.PathSegmentOfInt(idx)), t2.valueType, t2.valueNullable, v); err != nil {
+				return err
+			}
+		}
+		return nil
+	case TypeMap:
+		if err := validateKind(p, t, n, ipld.ReprKind_Map); err != nil {
+			return err
+		}
+		return ipld.DrainMapIterator(n.MapIterator(), func(k ipld.Node, v ipld.Node) error {
+			ks, err := k.AsString()
+			if err != nil {
+				return err
+			}
+			kp := p.AppendSegmentString(ks)
+			if err := validate(kp, t2.keyType, k); err != nil {
+				return err
+			}
+			return validateValue(kp, t2.valueType, t2.valueNullable, v)
+		})
+	case TypeStruct:
+		return validateStruct(p, t2, n)
+	case TypeUnion:
+		if t2.style != UnionStyle_Keyed {
+			return invalid(p, t, "validation of %s unions is not yet supported", t2.style.x)
+		}
+		if err := validateKind(p, t, n, ipld.ReprKind_Map); err != nil {
+			return err
+		}
+		if n.Length() != 1 {
+			return invalid(p, t, "expected exactly one member, got %d", n.Length())
+		}
+		return ipld.DrainMapIterator(n.MapIterator(), func(k ipld.Node, v ipld.Node) error {
+			ks, err := k.AsString()
+			if err != nil {
+				return err
+			}
+			member, ok := t2.memberOfKey(ks)
+			if !ok {
+				return invalid(p, t, "%q is not a member of the union", ks)
+			}
+			return validate(p.AppendSegmentString(ks), member, v)
+		})
+	default:
+		panic("unreachable")
+	}
+}
+
+func validateKind(p ipld.Path, t Type, n ipld.Node, k ipld.ReprKind) error {
+	if n.ReprKind() != k {
+		return invalid(p, t, "expected %s, got %s", k, n.ReprKind())
+	}
+	return nil
+}
+
+// validateValue validates a map, list, or struct member, which may be null if nullable.
+func validateValue(p ipld.Path, t Type, nullable bool, n ipld.Node) error {
+	if nullable && n.IsNull() {
+		return nil
+	}
+	return validate(p, t, n)
+}
+
+func validateStruct(p ipld.Path, t TypeStruct, n ipld.Node) error {
+	switch r := t.representation.(type) {
+	case StructRepresentation_Map:
+		if err := validateKind(p, t, n, ipld.ReprKind_Map); err != nil {
+			return err
+		}
+		known := make(map[string]struct{}, len(t.fields))
+		for _, f := range t.fields {
+			key := r.GetFieldKey(f)
+			known[key] = struct{}{}
+			v, err := n.LookupString(key)
+			if _, ok := err.(ipld.ErrNotExists); ok {
+				if _, implicit := r.implicits[f.name]; !f.optional && !implicit {
+					return invalid(p, t, "missing required field %q", key)
+				}
+				continue
+			}
+			if err != nil {
+				return err
+			}
+			if err := validateValue(p.AppendSegmentString(key), f.typ, f.nullable, v); err != nil {
+				return err
+			}
+		}
+		return ipld.DrainMapIterator(n.MapIterator(), func(k ipld.Node, _ ipld.Node) error {
+			ks, err := k.AsString()
+			if err != nil {
+				return err
+			}
+			if _, ok := known[ks]; !ok {
+				return invalid(p, t, "unexpected field %q", ks)
+			}
+			return nil
+		})
+	case StructRepresentation_Tuple:
+		if err := validateKind(p, t, n, ipld.ReprKind_List); err != nil {
+			return err
+		}
+		l := n.Length()
+		if l > len(t.fields) {
+			return invalid(p, t, "expected at most %d entries, got %d", len(t.fields), l)
+		}
+		for i, f := range t.fields {
+			if i >= l {
+				if !f.optional {
+					return invalid(p, t, "missing required field %q", f.name)
+				}
+				continue
+			}
+			v, err := n.LookupIndex(i)
+			if err != nil {
+				return err
+			}
+			if err := validateValue(p.AppendSegment(ipld.PathSegmentOfInt(i)), f.typ, f.nullable, v); err != nil {
+				return err
+			}
+		}
+		return nil
+	case StructRepresentation_StringJoin:
+		if err := validateKind(p, t, n, ipld.ReprKind_String); err != nil {
+			return err
+		}
+		s, _ := n.AsString()
+		if parts := strings.Split(s, r.sep); len(parts) != len(t.fields) {
+			return invalid(p, t, "expected %d fields joined by %q, got %d", len(t.fields), r.sep, len(parts))
+		}
+		return nil
+	case StructRepresentation_StringPairs:
+		return validateKind(p, t, n, ipld.ReprKind_String)
+	default:
+		return invalid(p, t, "unknown struct representation strategy")
+	}
+}
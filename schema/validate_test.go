@@ -0,0 +1,102 @@
+package schema_test
+
+import (
+	"testing"
+
+	. "github.com/warpfork/go-wish"
+
+	ipld "github.com/ipld/go-ipld-prime"
+	"github.com/ipld/go-ipld-prime/fluent"
+	basicnode "github.com/ipld/go-ipld-prime/node/basic"
+	"github.com/ipld/go-ipld-prime/schema"
+)
+
+func TestValidateStruct(t *testing.T) {
+	tInt := schema.SpawnInt("Int")
+	tString := schema.SpawnString("String")
+	tT2 := schema.SpawnStruct("T2",
+		[]schema.StructField{
+			schema.SpawnStructField("a", tInt, false, false),
+			schema.SpawnStructField("b", tInt, false, false),
+			schema.SpawnStructField("c", tInt, false, false),
+			schema.SpawnStructField("d", tInt, false, false),
+			schema.SpawnStructField("e", tString, true, true),
+		},
+		schema.StructRepresentation_Map{},
+	)
+	build := func(fn func(fluent.MapAssembler)) ipld.Node {
+		return fluent.MustBuildMap(basicnode.Style__Map{}, 5, fn)
+	}
+
+	t.Run("valid", func(t *testing.T) {
+		n := build(func(ma fluent.MapAssembler) {
+			ma.AssembleEntry("a").AssignInt(1)
+			ma.AssembleEntry("b").AssignInt(2)
+			ma.AssembleEntry("c").AssignInt(3)
+			ma.AssembleEntry("d").AssignInt(4)
+		})
+		Wish(t, schema.Validate(tT2, n), ShouldEqual, nil)
+	})
+	t.Run("valid with optional nullable field null", func(t *testing.T) {
+		n := build(func(ma fluent.MapAssembler) {
+			ma.AssembleEntry("a").AssignInt(1)
+			ma.AssembleEntry("b").AssignInt(2)
+			ma.AssembleEntry("c").AssignInt(3)
+			ma.AssembleEntry("d").AssignInt(4)
+			ma.AssembleEntry("e").AssignNull()
+		})
+		Wish(t, schema.Validate(tT2, n), ShouldEqual, nil)
+	})
+	t.Run("missing field", func(t *testing.T) {
+		n := build(func(ma fluent.MapAssembler) {
+			ma.AssembleEntry("a").AssignInt(1)
+			ma.AssembleEntry("b").AssignInt(2)
+			ma.AssembleEntry("c").AssignInt(3)
+		})
+		err := schema.Validate(tT2, n)
+		Wish(t, err, ShouldEqual, schema.ErrInvalidData{
+			Path:   ipld.Path{},
+			Type:   tT2,
+			Reason: `missing required field "d"`,
+		})
+		Wish(t, err.Error(), ShouldEqual, `invalid data at "": not a valid T2: missing required field "d"`)
+	})
+	t.Run("wrong field kind", func(t *testing.T) {
+		n := build(func(ma fluent.MapAssembler) {
+			ma.AssembleEntry("a").AssignInt(1)
+			ma.AssembleEntry("b").AssignString("two")
+			ma.AssembleEntry("c").AssignInt(3)
+			ma.AssembleEntry("d").AssignInt(4)
+		})
+		err := schema.Validate(tT2, n)
+		Wish(t, err.Error(), ShouldEqual, `invalid data at "b": not a valid Int: expected Int, got String`)
+	})
+	t.Run("unexpected field", func(t *testing.T) {
+		n := build(func(ma fluent.MapAssembler) {
+			ma.AssembleEntry("a").AssignInt(1)
+			ma.AssembleEntry("b").AssignInt(2)
+			ma.AssembleEntry("c").AssignInt(3)
+			ma.AssembleEntry("d").AssignInt(4)
+			ma.AssembleEntry("z").AssignInt(5)
+		})
+		err := schema.Validate(tT2, n)
+		Wish(t, err.Error(), ShouldEqual, `invalid data at "": not a valid T2: unexpected field "z"`)
+	})
+	t.Run("not a map", func(t *testing.T) {
+		err := schema.Validate(tT2, basicnode.NewInt(1))
+		Wish(t, err.Error(), ShouldEqual, `invalid data at "": not a valid T2: expected Map, got Int`)
+	})
+}
+
+func TestValidateScalars(t *testing.T) {
+	Wish(t, schema.Validate(schema.SpawnString("S"), basicnode.NewString("x")), ShouldEqual, nil)
+	Wish(t, schema.Validate(schema.SpawnBytes("B"), basicnode.NewBytes([]byte{1})), ShouldEqual, nil)
+	Wish(t, schema.Validate(schema.SpawnInt("I"), basicnode.NewString("x")).Error(), ShouldEqual, `invalid data at "": not a valid I: expected Int, got String`)
+
+	tList := schema.SpawnList("L", schema.SpawnInt("I"), false)
+	n := fluent.MustBuildList(basicnode.Style__List{}, 2, func(la fluent.ListAssembler) {
+		la.AssembleValue().AssignInt(1)
+		la.AssembleValue().AssignNull()
+	})
+	Wish(t, schema.Validate(tList, n).Error(), ShouldEqual, `invalid data at "1": not a valid I: expected Int, got Null`)
+}
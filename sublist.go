@@ -0,0 +1,107 @@
+package ipld
+
+// SubList returns a view of the elements of a list from index 'from' up to
+// (but not including) index 'to', without copying them: the view looks up
+// each element in the original list when it's asked for.
+// Indexes in the view start at 0, so the view's element 0 is the list's element 'from'.
+//
+// The bounds must satisfy 0 <= from <= to <= n.Length(); otherwise ErrSliceBounds is returned.
+// If n isn't a list, ErrWrongKind is returned.
+//
+// The view reports the same Style as the original list,
+// so building a new node "like" it produces an ordinary list.
+// A SubList of a SubList is a view directly onto the original list.
+func SubList(n Node, from, to int) (Node, error) {
+	if n.ReprKind() != ReprKind_List {
+		return nil, ErrWrongKind{TypeName: "list view", MethodName: "SubList", AppropriateKind: ReprKindSet_JustList, ActualKind: n.ReprKind()}
+	}
+	if from < 0 || to < from || to > n.Length() {
+		return nil, ErrSliceBounds{from, to, n.Length()}
+	}
+	if sl, ok := n.(subList); ok {
+		return subList{sl.n, sl.from + from, sl.from + to}, nil
+	}
+	return subList{n, from, to}, nil
+}
+
+type subList struct {
+	n        Node // the original list.
+	from, to int  // the range of n which this is a view of.
+}
+
+func (subList) ReprKind() ReprKind {
+	return ReprKind_List
+}
+func (subList) LookupString(string) (Node, error) {
+	return nil, ErrWrongKind{TypeName: "list view", MethodName: "LookupString", AppropriateKind: ReprKindSet_JustMap, ActualKind: ReprKind_List}
+}
+func (subList) Lookup(Node) (Node, error) {
+	return nil, ErrWrongKind{TypeName: "list view", MethodName: "Lookup", AppropriateKind: ReprKindSet_JustMap, ActualKind: ReprKind_List}
+}
+func (sl subList) LookupIndex(idx int) (Node, error) {
+	if idx < 0 || idx >= sl.to-sl.from {
+		return nil, ErrNotExists{PathSegmentOfInt(idx)}
+	}
+	return sl.n.LookupIndex(sl.from + idx)
+}
+func (sl subList) LookupSegment(seg PathSegment) (Node, error) {
+	idx, err := seg.Index()
+	if err != nil {
+		return nil, ErrNotExists{seg} // a segment that isn't a number can't be in a list.
+	}
+	return sl.LookupIndex(idx)
+}
+func (subList) MapIterator() MapIterator {
+	return nil
+}
+func (sl subList) ListIterator() ListIterator {
+	return &subListIterator{sl, 0}
+}
+func (sl subList) Length() int {
+	return sl.to - sl.from
+}
+func (subList) IsUndefined() bool {
+	return false
+}
+func (subList) IsNull() bool {
+	return false
+}
+func (subList) AsBool() (bool, error) {
+	return false, ErrWrongKind{TypeName: "list view", MethodName: "AsBool", AppropriateKind: ReprKindSet_JustBool, ActualKind: ReprKind_List}
+}
+func (subList) AsInt() (int, error) {
+	return 0, ErrWrongKind{TypeName: "list view", MethodName: "AsInt", AppropriateKind: ReprKindSet_JustInt, ActualKind: ReprKind_List}
+}
+func (subList) AsFloat() (float64, error) {
+	return 0, ErrWrongKind{TypeName: "list view", MethodName: "AsFloat", AppropriateKind: ReprKindSet_JustFloat, ActualKind: ReprKind_List}
+}
+func (subList) AsString() (string, error) {
+	return "", ErrWrongKind{TypeName: "list view", MethodName: "AsString", AppropriateKind: ReprKindSet_JustString, ActualKind: ReprKind_List}
+}
+func (subList) AsBytes() ([]byte, error) {
+	return nil, ErrWrongKind{TypeName: "list view", MethodName: "AsBytes", AppropriateKind: ReprKindSet_JustBytes, ActualKind: ReprKind_List}
+}
+func (subList) AsLink() (Link, error) {
+	return nil, ErrWrongKind{TypeName: "list view", MethodName: "AsLink", AppropriateKind: ReprKindSet_JustLink, ActualKind: ReprKind_List}
+}
+func (sl subList) Style() NodeStyle {
+	return sl.n.Style()
+}
+
+type subListIterator struct {
+	sl  subList
+	idx int
+}
+
+func (itr *subListIterator) Next() (idx int, v Node, err error) {
+	if itr.Done() {
+		return -1, nil, ErrIteratorOverread{}
+	}
+	idx = itr.idx
+	v, err = itr.sl.LookupIndex(idx)
+	itr.idx++
+	return
+}
+func (itr *subListIterator) Done() bool {
+	return itr.idx >= itr.sl.Length()
+}
@@ -0,0 +1,66 @@
+package ipld_test
+
+import (
+	"testing"
+
+	. "github.com/warpfork/go-wish"
+
+	ipld "github.com/ipld/go-ipld-prime"
+	"github.com/ipld/go-ipld-prime/fluent"
+	basicnode "github.com/ipld/go-ipld-prime/node/basic"
+	"github.com/ipld/go-ipld-prime/node/tests"
+)
+
+func TestSubList(t *testing.T) {
+	list := fluent.MustBuildList(basicnode.Style__List{}, 6, func(na fluent.ListAssembler) {
+		for i := 0; i < 6; i++ {
+			na.AssembleValue().AssignInt(i * 10)
+		}
+	})
+	sl, err := ipld.SubList(list, 2, 5)
+	Require(t, err, ShouldEqual, nil)
+	Wish(t, sl.Length(), ShouldEqual, 3)
+	Wish(t, ipld.Sprint(sl), ShouldEqual, "[20, 30, 40]")
+	tests.CheckConformance(t, sl)
+
+	t.Run("indexes start at 0", func(t *testing.T) {
+		v, err := sl.LookupIndex(0)
+		Wish(t, err, ShouldEqual, nil)
+		Wish(t, v, ShouldEqual, basicnode.NewInt(20))
+		_, err = sl.LookupIndex(3)
+		Wish(t, err, ShouldEqual, ipld.ErrNotExists{ipld.PathSegmentOfInt(3)})
+		var idxs []int
+		for itr := sl.ListIterator(); !itr.Done(); {
+			idx, _, err := itr.Next()
+			Require(t, err, ShouldEqual, nil)
+			idxs = append(idxs, idx)
+		}
+		Wish(t, idxs, ShouldEqual, []int{0, 1, 2})
+	})
+	t.Run("sublist of a sublist", func(t *testing.T) {
+		sl2, err := ipld.SubList(sl, 1, 3)
+		Require(t, err, ShouldEqual, nil)
+		Wish(t, ipld.Sprint(sl2), ShouldEqual, "[30, 40]")
+		_, err = ipld.SubList(sl, 1, 4)
+		Wish(t, err, ShouldEqual, ipld.ErrSliceBounds{1, 4, 3})
+	})
+	t.Run("bounds out of range", func(t *testing.T) {
+		_, err := ipld.SubList(list, -1, 2)
+		Wish(t, err, ShouldEqual, ipld.ErrSliceBounds{-1, 2, 6})
+		_, err = ipld.SubList(list, 4, 3)
+		Wish(t, err, ShouldEqual, ipld.ErrSliceBounds{4, 3, 6})
+		_, err = ipld.SubList(list, 0, 7)
+		Wish(t, err, ShouldEqual, ipld.ErrSliceBounds{0, 7, 6})
+		Wish(t, err.Error(), ShouldEqual, "slice bounds out of range: [0:7] of a list of length 6")
+	})
+	t.Run("empty range", func(t *testing.T) {
+		sl, err := ipld.SubList(list, 6, 6)
+		Require(t, err, ShouldEqual, nil)
+		Wish(t, sl.Length(), ShouldEqual, 0)
+		Wish(t, sl.ListIterator().Done(), ShouldEqual, true)
+	})
+	t.Run("not a list", func(t *testing.T) {
+		_, err := ipld.SubList(basicnode.NewString("abc"), 0, 1)
+		Wish(t, err, ShouldBeSameTypeAs, ipld.ErrWrongKind{})
+	})
+}
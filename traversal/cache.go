@@ -0,0 +1,146 @@
+package traversal
+
+import (
+	"container/list"
+	"reflect"
+	"sync"
+
+	ipld "github.com/ipld/go-ipld-prime"
+)
+
+// NodeCache holds recently loaded Nodes, keyed by the Link they were loaded from,
+// so that a traversal which reaches the same Link more than once
+// (for example, in a diamond-shaped DAG) only loads and decodes it once.
+// Set it as Config.NodeCache to use it.
+//
+// Reusing a Node is safe because Nodes are immutable.
+// Entries are keyed by the Link and by the NodeStyle chosen for it,
+// so the same block loaded as two different styles is two entries.
+// Links and NodeStyles are compared with == (for cidlink.Link, this means
+// comparing CIDs); if either isn't of a comparable type, the load just
+// isn't cached.
+//
+// The cache holds at most the number of Nodes given to NewNodeCache,
+// evicting the least recently used.
+// A NodeCache is safe for concurrent use, and may be shared by several traversals.
+type NodeCache struct {
+	mu      sync.Mutex
+	size    int
+	ll      *list.List // of *nodeCacheEntry; front is most recently used.
+	entries map[nodeCacheKey]*list.Element
+	stats   NodeCacheStats
+}
+
+// NodeCacheStats counts how a NodeCache has been used.
+type NodeCacheStats struct {
+	Hits   int // Loads that were satisfied from the cache.
+	Misses int // Loads that had to go to the LinkLoader (and were then cached).
+}
+
+type nodeCacheKey struct {
+	lnk ipld.Link
+	ns  ipld.NodeStyle
+}
+
+type nodeCacheEntry struct {
+	key nodeCacheKey
+	n   ipld.Node
+}
+
+// NewNodeCache returns a NodeCache which holds at most size Nodes.
+// A size less than 1 is treated as 1.
+func NewNodeCache(size int) *NodeCache {
+	if size < 1 {
+		size = 1
+	}
+	return &NodeCache{
+		size:    size,
+		ll:      list.New(),
+		entries: make(map[nodeCacheKey]*list.Element, size),
+	}
+}
+
+// Stats returns the hit and miss counts so far.
+func (c *NodeCache) Stats() NodeCacheStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.stats
+}
+
+// Len returns the number of Nodes currently held.
+func (c *NodeCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.ll.Len()
+}
+
+func (c *NodeCache) get(k nodeCacheKey) (ipld.Node, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if el, ok := c.entries[k]; ok {
+		c.ll.MoveToFront(el)
+		c.stats.Hits++
+		return el.Value.(*nodeCacheEntry).n, true
+	}
+	c.stats.Misses++
+	return nil, false
+}
+
+func (c *NodeCache) put(k nodeCacheKey, n ipld.Node) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if el, ok := c.entries[k]; ok { // a concurrent traversal got here first.
+		c.ll.MoveToFront(el)
+		return
+	}
+	c.entries[k] = c.ll.PushFront(&nodeCacheEntry{k, n})
+	if c.ll.Len() > c.size {
+		oldest := c.ll.Back()
+		c.ll.Remove(oldest)
+		delete(c.entries, oldest.Value.(*nodeCacheEntry).key)
+	}
+}
+
+// loadLink loads the Node for a Link, built with the given NodeStyle,
+// going through the Config.NodeCache if there is one.
+// Errors from the Link (including SkipMe from the LinkLoader) are returned unwrapped.
+func (cfg *Config) loadLink(lnk ipld.Link, lnkCtx ipld.LinkContext, ns ipld.NodeStyle) (ipld.Node, error) {
+	cache := cfg.NodeCache
+	key := nodeCacheKey{lnk, ns}
+	if cache != nil && !(isComparable(lnk) && isComparable(ns)) {
+		cache = nil
+	}
+	if cache != nil {
+		if n, ok := cache.get(key); ok {
+			if cfg.Stats != nil {
+				cfg.Stats.LinksCached++
+			}
+			if cfg.OnEvent != nil {
+				cfg.emit(EventKind_LinkLoaded, lnkCtx.LinkPath, lnk)
+			}
+			return n, nil
+		}
+	}
+	nb := ns.NewBuilder()
+	if err := lnk.Load(cfg.Ctx, lnkCtx, nb, cfg.Stats.countingLoader(cfg.LinkLoader)); err != nil {
+		if _, ok := err.(SkipMe); ok && cfg.Stats != nil {
+			cfg.Stats.LinksSkipped++
+		}
+		return nil, err
+	}
+	if cfg.Stats != nil {
+		cfg.Stats.LinksLoaded++
+	}
+	if cfg.OnEvent != nil {
+		cfg.emit(EventKind_LinkLoaded, lnkCtx.LinkPath, lnk)
+	}
+	n := nb.Build()
+	if cache != nil {
+		cache.put(key, n)
+	}
+	return n, nil
+}
+
+func isComparable(v interface{}) bool {
+	return reflect.TypeOf(v).Comparable()
+}
@@ -0,0 +1,91 @@
+package traversal_test
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	. "github.com/warpfork/go-wish"
+
+	ipld "github.com/ipld/go-ipld-prime"
+	"github.com/ipld/go-ipld-prime/fluent"
+	basicnode "github.com/ipld/go-ipld-prime/node/basic"
+	"github.com/ipld/go-ipld-prime/traversal"
+	"github.com/ipld/go-ipld-prime/traversal/selector"
+	"github.com/ipld/go-ipld-prime/traversal/selector/builder"
+)
+
+func TestNodeCache(t *testing.T) {
+	// A diamond: the root links to two middles, which both link to one shared leaf.
+	shared, sharedLnk := encode(fluent.MustBuildMap(basicnode.Style__Map{}, 1, func(na fluent.MapAssembler) {
+		na.AssembleEntry("shared").AssignBool(true)
+	}))
+	_, leftLnk := encode(fluent.MustBuildMap(basicnode.Style__Map{}, 2, func(na fluent.MapAssembler) {
+		na.AssembleEntry("side").AssignString("left")
+		na.AssembleEntry("leaf").AssignLink(sharedLnk)
+	}))
+	_, rightLnk := encode(fluent.MustBuildMap(basicnode.Style__Map{}, 2, func(na fluent.MapAssembler) {
+		na.AssembleEntry("side").AssignString("right")
+		na.AssembleEntry("leaf").AssignLink(sharedLnk)
+	}))
+	root := fluent.MustBuildMap(basicnode.Style__Map{}, 2, func(na fluent.MapAssembler) {
+		na.AssembleEntry("left").AssignLink(leftLnk)
+		na.AssembleEntry("right").AssignLink(rightLnk)
+	})
+	ssb := builder.NewSelectorSpecBuilder(basicnode.Style__Any{})
+	s, err := ssb.ExploreRecursive(selector.RecursionLimitNone(), ssb.ExploreAll(ssb.ExploreRecursiveEdge())).Selector()
+	Require(t, err, ShouldEqual, nil)
+
+	walk := func(cache *traversal.NodeCache) (loads map[ipld.Link]int, visited []ipld.Node) {
+		loads = make(map[ipld.Link]int)
+		err := traversal.Progress{
+			Cfg: &traversal.Config{
+				LinkLoader: func(lnk ipld.Link, _ ipld.LinkContext) (io.Reader, error) {
+					loads[lnk]++
+					return bytes.NewBuffer(storage[lnk]), nil
+				},
+				LinkTargetNodeStyleChooser: func(_ ipld.Link, _ ipld.LinkContext) (ipld.NodeStyle, error) {
+					return basicnode.Style__Any{}, nil
+				},
+				NodeCache: cache,
+			},
+		}.WalkAdv(root, s, func(prog traversal.Progress, n ipld.Node, _ traversal.VisitReason) error {
+			if prog.Path.String() == "left/leaf" || prog.Path.String() == "right/leaf" {
+				visited = append(visited, n)
+			}
+			return nil
+		})
+		Wish(t, err, ShouldEqual, nil)
+		return
+	}
+	t.Run("without a cache, the shared node is loaded twice", func(t *testing.T) {
+		loads, visited := walk(nil)
+		Wish(t, loads[sharedLnk], ShouldEqual, 2)
+		Wish(t, len(visited), ShouldEqual, 2)
+	})
+	t.Run("with a cache, the shared node is loaded once", func(t *testing.T) {
+		cache := traversal.NewNodeCache(10)
+		loads, visited := walk(cache)
+		Wish(t, loads[sharedLnk], ShouldEqual, 1)
+		Wish(t, loads[leftLnk], ShouldEqual, 1)
+		Wish(t, loads[rightLnk], ShouldEqual, 1)
+		Wish(t, cache.Stats(), ShouldEqual, traversal.NodeCacheStats{Hits: 1, Misses: 3})
+		Require(t, len(visited), ShouldEqual, 2)
+		Wish(t, visited[0] == visited[1], ShouldEqual, true) // literally the same Node.
+		Wish(t, visited[0], ShouldEqual, shared)
+
+		// A second walk with the same cache loads nothing at all.
+		loads, _ = walk(cache)
+		Wish(t, len(loads), ShouldEqual, 0)
+		Wish(t, cache.Stats(), ShouldEqual, traversal.NodeCacheStats{Hits: 5, Misses: 3})
+	})
+	t.Run("the cache evicts the least recently used", func(t *testing.T) {
+		cache := traversal.NewNodeCache(1)
+		loads, _ := walk(cache)
+		// Traversal order is left, left/leaf, right, right/leaf: with room for one node,
+		// the shared leaf is evicted by "right" before it's needed again.
+		Wish(t, loads[sharedLnk], ShouldEqual, 2)
+		Wish(t, cache.Stats(), ShouldEqual, traversal.NodeCacheStats{Hits: 0, Misses: 4})
+		Wish(t, cache.Len(), ShouldEqual, 1)
+	})
+}
@@ -12,18 +12,15 @@ import (
 // init sets all the values in TraveralConfig to reasonable defaults
 // if they're currently the zero value.
 //
-// Note that you're absolutely going to need to replace the
-// LinkLoader and LinkNodeBuilderChooser if you want automatic link traversal;
-// the defaults return error and/or panic.
+// Note that you're absolutely going to need to set the
+// LinkLoader and LinkNodeBuilderChooser if you want automatic link traversal.
+// LinkLoader is left nil, and a traversal which needs to load a link
+// without one halts with ErrLinkLoaderRequired;
+// the default LinkTargetNodeStyleChooser returns an error for any link which isn't typed.
 func (tc *Config) init() {
 	if tc.Ctx == nil {
 		tc.Ctx = context.Background()
 	}
-	if tc.LinkLoader == nil {
-		tc.LinkLoader = func(ipld.Link, ipld.LinkContext) (io.Reader, error) {
-			return nil, fmt.Errorf("no link loader configured")
-		}
-	}
 	if tc.LinkTargetNodeStyleChooser == nil {
 		tc.LinkTargetNodeStyleChooser = func(lnk ipld.Link, lnkCtx ipld.LinkContext) (ipld.NodeStyle, error) {
 			if tlnkNd, ok := lnkCtx.LinkNode.(schema.TypedLinkNode); ok {
@@ -44,4 +41,17 @@ func (prog *Progress) init() {
 		prog.Cfg = &Config{}
 	}
 	prog.Cfg.init()
+	if prog.steps == nil {
+		prog.steps = new(int)
+	}
+}
+
+// step counts reaching another node,
+// and returns ErrRecursionLimit if that exceeds Config.MaxSteps.
+func (prog Progress) step() error {
+	*prog.steps++
+	if prog.Cfg.MaxSteps > 0 && *prog.steps > prog.Cfg.MaxSteps {
+		return ErrRecursionLimit{prog.Path, prog.Cfg.MaxSteps}
+	}
+	return nil
 }
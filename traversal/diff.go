@@ -0,0 +1,218 @@
+package traversal
+
+import (
+	"sort"
+	"strconv"
+
+	ipld "github.com/ipld/go-ipld-prime"
+)
+
+// DiffKind describes how a node differs between the two trees given to Diff.
+type DiffKind byte
+
+const (
+	DiffKind_Added   DiffKind = '+' // The path exists only in the new tree; Old is nil.
+	DiffKind_Removed DiffKind = '-' // The path exists only in the old tree; New is nil.
+	DiffKind_Changed DiffKind = '~' // The path exists in both trees, but the nodes differ.
+)
+
+func (k DiffKind) String() string {
+	switch k {
+	case DiffKind_Added:
+		return "Added"
+	case DiffKind_Removed:
+		return "Removed"
+	case DiffKind_Changed:
+		return "Changed"
+	default:
+		return "Invalid"
+	}
+}
+
+// DiffEntry records one difference found by Diff.
+type DiffEntry struct {
+	Path ipld.Path
+	Kind DiffKind
+	Old  ipld.Node
+	New  ipld.Node
+}
+
+// Diff walks two Node trees in parallel and returns their differences.
+//
+// Maps are compared key by key, over the union of both maps' keys,
+// and lists index by index; a key or index present on only one side
+// is reported as Added or Removed.  Where both sides have a map (or both a list),
+// Diff recurses, so a change is reported at the deepest path where the trees differ.
+// Anything else is reported as Changed if the two nodes differ in kind or value,
+// as ipld.Compare judges it: so links are equal if their String forms are
+// (which, for CIDs, means the CIDs are), and aren't loaded.
+//
+// The entries are in path order: map keys are visited in the order ipld.Compare
+// sorts them in (regardless of the maps' own iteration order), and list indexes in ascending order,
+// so the result is stable for a given pair of trees.
+// Keys of any kind are matched by value; keys which aren't strings
+// get path segments of their own kind for ints, and of their string form otherwise.
+//
+// Errors from the nodes (e.g. from iterators) are returned as-is,
+// along with the entries found so far.
+func Diff(a, b ipld.Node) ([]DiffEntry, error) {
+	var d differ
+	err := d.diff(ipld.Path{}, a, b)
+	return d.entries, err
+}
+
+type differ struct {
+	entries []DiffEntry
+}
+
+func (d *differ) add(p ipld.Path, kind DiffKind, a, b ipld.Node) {
+	d.entries = append(d.entries, DiffEntry{p, kind, a, b})
+}
+
+func (d *differ) diff(p ipld.Path, a, b ipld.Node) error {
+	if a.ReprKind() != b.ReprKind() {
+		d.add(p, DiffKind_Changed, a, b)
+		return nil
+	}
+	switch a.ReprKind() {
+	case ipld.ReprKind_Map:
+		return d.diffMaps(p, a, b)
+	case ipld.ReprKind_List:
+		return d.diffLists(p, a, b)
+	default:
+		c, err := ipld.CompareErr(a, b)
+		if err != nil {
+			return err
+		}
+		if c != 0 {
+			d.add(p, DiffKind_Changed, a, b)
+		}
+		return nil
+	}
+}
+
+func (d *differ) diffMaps(p ipld.Path, a, b ipld.Node) error {
+	as, err := mapEntries(a)
+	if err != nil {
+		return err
+	}
+	bs, err := mapEntries(b)
+	if err != nil {
+		return err
+	}
+	for len(as) > 0 || len(bs) > 0 {
+		var c int
+		switch {
+		case len(bs) == 0:
+			c = -1
+		case len(as) == 0:
+			c = 1
+		default:
+			if c, err = ipld.CompareErr(as[0].k, bs[0].k); err != nil {
+				return err
+			}
+		}
+		var k ipld.Node
+		if c <= 0 {
+			k = as[0].k
+		} else {
+			k = bs[0].k
+		}
+		ps, err := keySegment(k)
+		if err != nil {
+			return err
+		}
+		kp := p.AppendSegment(ps)
+		switch {
+		case c < 0:
+			d.add(kp, DiffKind_Removed, as[0].v, nil)
+			as = as[1:]
+		case c > 0:
+			d.add(kp, DiffKind_Added, nil, bs[0].v)
+			bs = bs[1:]
+		default:
+			if err := d.diff(kp, as[0].v, bs[0].v); err != nil {
+				return err
+			}
+			as, bs = as[1:], bs[1:]
+		}
+	}
+	return nil
+}
+
+type mapEntry struct {
+	k, v ipld.Node
+}
+
+// mapEntries returns the entries of a map, sorted by key in the order of ipld.Compare.
+func mapEntries(n ipld.Node) ([]mapEntry, error) {
+	m := make([]mapEntry, 0, n.Length())
+	err := ipld.DrainMapIterator(n.MapIterator(), func(k ipld.Node, v ipld.Node) error {
+		m = append(m, mapEntry{k, v})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(m, func(i, j int) bool {
+		c, e := ipld.CompareErr(m[i].k, m[j].k)
+		if e != nil && err == nil {
+			err = e
+		}
+		return c < 0
+	})
+	return m, err
+}
+
+// keySegment returns the path segment for a map key of any kind
+// (ints as ints; bytes and bools in the string forms basicnode keys them by).
+func keySegment(k ipld.Node) (ipld.PathSegment, error) {
+	switch k.ReprKind() {
+	case ipld.ReprKind_Int:
+		i, err := k.AsInt()
+		return ipld.PathSegmentOfInt(i), err
+	case ipld.ReprKind_Bytes:
+		b, err := k.AsBytes()
+		return ipld.PathSegmentOfString(string(b)), err
+	case ipld.ReprKind_Bool:
+		b, err := k.AsBool()
+		return ipld.PathSegmentOfString(strconv.FormatBool(b)), err
+	default:
+		s, err := k.AsString()
+		return ipld.PathSegmentOfString(s), err
+	}
+}
+
+func (d *differ) diffLists(p ipld.Path, a, b ipld.Node) error {
+	al, bl := a.Length(), b.Length()
+	for i := 0; i < al || i < bl; i++ {
+		ip := p.AppendSegment(ipld.PathSegmentOfInt(i))
+		switch {
+		case i >= bl:
+			av, err := a.LookupIndex(i)
+			if err != nil {
+				return err
+			}
+			d.add(ip, DiffKind_Removed, av, nil)
+		case i >= al:
+			bv, err := b.LookupIndex(i)
+			if err != nil {
+				return err
+			}
+			d.add(ip, DiffKind_Added, nil, bv)
+		default:
+			av, err := a.LookupIndex(i)
+			if err != nil {
+				return err
+			}
+			bv, err := b.LookupIndex(i)
+			if err != nil {
+				return err
+			}
+			if err := d.diff(ip, av, bv); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,107 @@
+package traversal_test
+
+import (
+	"testing"
+
+	cid "github.com/ipfs/go-cid"
+	. "github.com/warpfork/go-wish"
+
+	ipld "github.com/ipld/go-ipld-prime"
+	"github.com/ipld/go-ipld-prime/fluent"
+	cidlink "github.com/ipld/go-ipld-prime/linking/cid"
+	basicnode "github.com/ipld/go-ipld-prime/node/basic"
+	"github.com/ipld/go-ipld-prime/traversal"
+)
+
+func TestDiff(t *testing.T) {
+	build := func(deep int, extra bool) ipld.Node {
+		return fluent.MustBuildMap(basicnode.Style__Map{}, 3, func(na fluent.MapAssembler) {
+			if extra {
+				na.AssembleEntry("z").AssignBool(true)
+			}
+			na.AssembleEntry("nested").CreateMap(2, func(na fluent.MapAssembler) {
+				na.AssembleEntry("deep").AssignInt(deep)
+				na.AssembleEntry("same").AssignString("x")
+			})
+			na.AssembleEntry("list").CreateList(2, func(na fluent.ListAssembler) {
+				na.AssembleValue().AssignInt(1)
+				na.AssembleValue().AssignLink(leafAlphaLnk)
+			})
+		})
+	}
+	t.Run("identical trees", func(t *testing.T) {
+		d, err := traversal.Diff(build(1, false), build(1, false))
+		Wish(t, err, ShouldEqual, nil)
+		Wish(t, len(d), ShouldEqual, 0)
+	})
+	t.Run("one nested field changed", func(t *testing.T) {
+		d, err := traversal.Diff(build(1, false), build(2, false))
+		Wish(t, err, ShouldEqual, nil)
+		Wish(t, d, ShouldEqual, []traversal.DiffEntry{
+			{ipld.ParsePath("nested/deep"), traversal.DiffKind_Changed, basicnode.NewInt(1), basicnode.NewInt(2)},
+		})
+	})
+	t.Run("added and removed", func(t *testing.T) {
+		a := build(1, true)
+		b := fluent.MustBuildMap(basicnode.Style__Map{}, 2, func(na fluent.MapAssembler) {
+			na.AssembleEntry("nested").AssignString("flat")
+			na.AssembleEntry("list").CreateList(3, func(na fluent.ListAssembler) {
+				na.AssembleValue().AssignInt(1)
+				na.AssembleValue().AssignLink(leafAlphaLnk)
+				na.AssembleValue().AssignNull()
+			})
+			na.AssembleEntry("a").AssignInt(0)
+		})
+		d, err := traversal.Diff(a, b)
+		Wish(t, err, ShouldEqual, nil)
+		var summary []string
+		for _, e := range d {
+			summary = append(summary, e.Kind.String()+" "+e.Path.String())
+		}
+		Wish(t, summary, ShouldEqual, []string{
+			"Added a",
+			"Added list/2",
+			"Changed nested",
+			"Removed z",
+		})
+	})
+	t.Run("keys of other kinds", func(t *testing.T) {
+		build := func(v int) ipld.Node {
+			nb := basicnode.Style__Map{}.NewBuilder()
+			ma, _ := nb.BeginMap(4)
+			ma.AssembleKey().AssignInt(12)
+			ma.AssembleValue().AssignInt(v)
+			ma.AssembleKey().AssignBytes([]byte{0xff})
+			ma.AssembleValue().AssignInt(1)
+			if v > 1 {
+				ma.AssembleKey().AssignBool(true)
+				ma.AssembleValue().AssignInt(1)
+			}
+			ma.AssembleKey().AssignString("12")
+			ma.AssembleValue().AssignInt(1)
+			ma.Finish()
+			return nb.Build()
+		}
+		d, err := traversal.Diff(build(1), build(1))
+		Wish(t, err, ShouldEqual, nil)
+		Wish(t, len(d), ShouldEqual, 0)
+		d, err = traversal.Diff(build(1), build(2))
+		Wish(t, err, ShouldEqual, nil)
+		Wish(t, d, ShouldEqual, []traversal.DiffEntry{
+			{ipld.ParsePath("true"), traversal.DiffKind_Added, nil, basicnode.NewInt(1)},
+			{ipld.NewPath([]ipld.PathSegment{ipld.PathSegmentOfInt(12)}), traversal.DiffKind_Changed, basicnode.NewInt(1), basicnode.NewInt(2)},
+		})
+	})
+	t.Run("links compare as ipld.Compare does", func(t *testing.T) {
+		c := leafAlphaLnk.(cidlink.Link).Cid
+		same := cidlink.Link{cid.NewCidV1(c.Prefix().Codec, append([]byte(nil), c.Hash()...))}
+		recoded := cidlink.Link{cid.NewCidV1(0x55, c.Hash())}
+		d, err := traversal.Diff(basicnode.NewLink(leafAlphaLnk), basicnode.NewLink(same))
+		Wish(t, err, ShouldEqual, nil)
+		Wish(t, len(d), ShouldEqual, 0)
+		d, err = traversal.Diff(basicnode.NewLink(leafAlphaLnk), basicnode.NewLink(recoded))
+		Wish(t, err, ShouldEqual, nil)
+		Wish(t, len(d), ShouldEqual, 1)
+		Wish(t, d[0].Kind, ShouldEqual, traversal.DiffKind_Changed)
+	})
+}
@@ -0,0 +1,29 @@
+package traversal
+
+import (
+	ipld "github.com/ipld/go-ipld-prime"
+)
+
+// Event describes one step of a traversal, as reported to Config.OnEvent.
+// Events are for observability (e.g. progress bars on long walks),
+// not for collecting results: use the visit function for that.
+type Event struct {
+	Kind EventKind
+	Path ipld.Path // Path is where the event happened: the node reached, or the position of the link loaded.
+	Link ipld.Link // Link is the link loaded, for EventKind_LinkLoaded; nil otherwise.
+}
+
+// EventKind says what happened in an Event.
+type EventKind byte
+
+const (
+	EventKind_NodeVisited EventKind = 'v' // A node was reached by the walk.  (This is reported before the visit function is called for it.)
+	EventKind_LinkLoaded  EventKind = 'l' // A link was loaded (or satisfied from Config.NodeCache).  Links skipped or failing to load aren't reported.
+	EventKind_Matched     EventKind = 'm' // The selector matched the node just visited.
+)
+
+// emit reports an event to Config.OnEvent.
+// Callers check OnEvent isn't nil first, so that unobserved traversals don't even build the Event.
+func (cfg *Config) emit(kind EventKind, p ipld.Path, lnk ipld.Link) {
+	cfg.OnEvent(Event{kind, p, lnk})
+}
@@ -0,0 +1,64 @@
+package traversal_test
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	. "github.com/warpfork/go-wish"
+
+	ipld "github.com/ipld/go-ipld-prime"
+	basicnode "github.com/ipld/go-ipld-prime/node/basic"
+	"github.com/ipld/go-ipld-prime/traversal"
+	"github.com/ipld/go-ipld-prime/traversal/selector/builder"
+)
+
+func TestWalkEvents(t *testing.T) {
+	ssb := builder.NewSelectorSpecBuilder(basicnode.Style__Any{})
+	s, err := ssb.ExploreFields(func(efsb builder.ExploreFieldsSpecBuilder) {
+		efsb.Insert("plain", ssb.Matcher())
+		efsb.Insert("linkedMap", ssb.ExploreFields(func(efsb builder.ExploreFieldsSpecBuilder) {
+			efsb.Insert("nested", ssb.ExploreAll(ssb.Matcher()))
+		}))
+	}).Selector()
+	Require(t, err, ShouldEqual, nil)
+
+	var events []string
+	var visits []string
+	err = traversal.Progress{
+		Cfg: &traversal.Config{
+			LinkLoader: func(lnk ipld.Link, _ ipld.LinkContext) (io.Reader, error) {
+				return bytes.NewBuffer(storage[lnk]), nil
+			},
+			LinkTargetNodeStyleChooser: func(_ ipld.Link, _ ipld.LinkContext) (ipld.NodeStyle, error) {
+				return basicnode.Style__Any{}, nil
+			},
+			OnEvent: func(ev traversal.Event) {
+				summary := string(ev.Kind) + " " + ev.Path.String()
+				if ev.Link != nil {
+					summary += " " + ev.Link.String()
+				}
+				events = append(events, summary)
+			},
+		},
+	}.WalkMatching(rootNode, s, func(prog traversal.Progress, n ipld.Node) error {
+		visits = append(visits, prog.Path.String())
+		return nil
+	})
+	Wish(t, err, ShouldEqual, nil)
+	Wish(t, events, ShouldEqual, []string{
+		"v ",
+		"v plain",
+		"m plain",
+		"l linkedMap " + middleMapNodeLnk.String(),
+		"v linkedMap",
+		"v linkedMap/nested",
+		"l linkedMap/nested/alink " + leafAlphaLnk.String(),
+		"v linkedMap/nested/alink",
+		"m linkedMap/nested/alink",
+		"v linkedMap/nested/nonlink",
+		"m linkedMap/nested/nonlink",
+	})
+	// Events are only observations: the visits are the same as ever.
+	Wish(t, visits, ShouldEqual, []string{"plain", "linkedMap/nested/alink", "linkedMap/nested/nonlink"})
+}
@@ -0,0 +1,76 @@
+package traversal
+
+import (
+	"strings"
+
+	ipld "github.com/ipld/go-ipld-prime"
+)
+
+// Flatten walks a Node tree and returns every leaf in it, keyed by its path
+// (as rendered by FlattenKey).  This is handy for building indexes over IPLD data.
+//
+// Maps and lists are descended; everything else is a leaf, including links
+// (which are not loaded) and null.  Empty maps and lists have no leaves,
+// so they don't appear in the result at all.
+// If the root itself is a leaf, the result has one entry, under the empty string.
+//
+// Errors from the nodes (e.g. from iterators, or map keys which aren't strings)
+// are returned as-is, along with the leaves found so far.
+func Flatten(n ipld.Node) (map[string]ipld.Node, error) {
+	leaves := make(map[string]ipld.Node)
+	err := flatten(leaves, ipld.Path{}, n)
+	return leaves, err
+}
+
+func flatten(leaves map[string]ipld.Node, p ipld.Path, n ipld.Node) error {
+	switch n.ReprKind() {
+	case ipld.ReprKind_Map:
+		itr := n.MapIterator()
+		for !itr.Done() {
+			k, v, err := itr.Next()
+			if err != nil {
+				return err
+			}
+			ks, err := k.AsString()
+			if err != nil {
+				return err
+			}
+			if err := flatten(leaves, p.AppendSegmentString(ks), v); err != nil {
+				return err
+			}
+		}
+		return nil
+	case ipld.ReprKind_List:
+		itr := n.ListIterator()
+		for !itr.Done() {
+			i, v, err := itr.Next()
+			if err != nil {
+				return err
+			}
+			if err := flatten(leaves, p.AppendSegment(ipld.PathSegmentOfInt(i)), v); err != nil {
+				return err
+			}
+		}
+		return nil
+	default:
+		leaves[FlattenKey(p)] = n
+		return nil
+	}
+}
+
+// flattenKeyEscaper escapes path segments the way JSON Pointer (RFC 6901) does.
+// "~" must be escaped first, so that the "~" introduced for "/" isn't escaped again.
+var flattenKeyEscaper = strings.NewReplacer("~", "~0", "/", "~1")
+
+// FlattenKey renders a Path as the keys in the result of Flatten are rendered:
+// segments joined with "/", like Path.String, but with any "/" within a segment
+// escaped as "~1" (and "~" itself as "~0", so the escaping can be reversed).
+// Use it to look up a known path in Flatten's result.
+func FlattenKey(p ipld.Path) string {
+	segs := p.Segments()
+	ss := make([]string, len(segs))
+	for i, seg := range segs {
+		ss[i] = flattenKeyEscaper.Replace(seg.String())
+	}
+	return strings.Join(ss, "/")
+}
@@ -0,0 +1,50 @@
+package traversal_test
+
+import (
+	"testing"
+
+	. "github.com/warpfork/go-wish"
+
+	ipld "github.com/ipld/go-ipld-prime"
+	"github.com/ipld/go-ipld-prime/fluent"
+	basicnode "github.com/ipld/go-ipld-prime/node/basic"
+	"github.com/ipld/go-ipld-prime/traversal"
+)
+
+func TestFlatten(t *testing.T) {
+	n := fluent.MustBuildMap(basicnode.Style__Map{}, 4, func(na fluent.MapAssembler) {
+		na.AssembleEntry("a").CreateMap(2, func(na fluent.MapAssembler) {
+			na.AssembleEntry("b/c").AssignInt(1)
+			na.AssembleEntry("~d").AssignNull()
+		})
+		na.AssembleEntry("list").CreateList(2, func(na fluent.ListAssembler) {
+			na.AssembleValue().AssignString("x")
+			na.AssembleValue().AssignLink(leafAlphaLnk)
+		})
+		na.AssembleEntry("empty").CreateMap(0, func(na fluent.MapAssembler) {})
+		na.AssembleEntry("top").AssignBool(true)
+	})
+	leaves, err := traversal.Flatten(n)
+	Wish(t, err, ShouldEqual, nil)
+	summary := make(map[string]string, len(leaves))
+	for k, v := range leaves {
+		summary[k] = ipld.Sprint(v)
+	}
+	Wish(t, summary, ShouldEqual, map[string]string{
+		"a/b~1c": "1",
+		"a/~0d":  "null",
+		"list/0": `"x"`,
+		"list/1": "link(" + leafAlphaLnk.String() + ")",
+		"top":    "true",
+	})
+
+	t.Run("keys can be looked up by path", func(t *testing.T) {
+		p := ipld.NewPath([]ipld.PathSegment{ipld.PathSegmentOfString("a"), ipld.PathSegmentOfString("b/c")})
+		Wish(t, leaves[traversal.FlattenKey(p)], ShouldEqual, basicnode.NewInt(1))
+	})
+	t.Run("scalar root", func(t *testing.T) {
+		leaves, err := traversal.Flatten(basicnode.NewString("alone"))
+		Wish(t, err, ShouldEqual, nil)
+		Wish(t, leaves, ShouldEqual, map[string]ipld.Node{"": basicnode.NewString("alone")})
+	})
+}
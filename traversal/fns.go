@@ -2,8 +2,10 @@ package traversal
 
 import (
 	"context"
+	"fmt"
 
 	ipld "github.com/ipld/go-ipld-prime"
+	"github.com/ipld/go-ipld-prime/schema"
 )
 
 // This file defines interfaces for things users provide,
@@ -35,6 +37,13 @@ type Progress struct {
 		Path ipld.Path
 		Link ipld.Link
 	}
+	MatchLabels []string // MatchLabels holds the labels of the Matchers which selected the current node.  (Only set when visiting with VisitReason_SelectionMatch.)
+
+	steps *int // steps counts the nodes reached so far, for Config.MaxSteps.  Shared by all the Progress values of one traversal (including traversals nested in a visitor).
+
+	resuming bool               // resuming is true while a walk started from a Cursor hasn't yet reached the Cursor's path.
+	resumeAt []ipld.PathSegment // resumeAt is what's left of the Cursor's path, below the current node.  (Only used while resuming.)
+	page     *int               // page counts down the matches a paginated walk may still visit.  Nil if the walk isn't paginated.
 }
 
 type Config struct {
@@ -42,6 +51,12 @@ type Config struct {
 	LinkLoader                 ipld.Loader                // Loader used for automatic link traversal.
 	LinkTargetNodeStyleChooser LinkTargetNodeStyleChooser // Chooser for Node implementations to produce during automatic link traversal.
 	LinkStorer                 ipld.Storer                // Storer used if any mutation features (e.g. traversal.Transform) are used.
+	MaxSteps                   int                        // If positive, a traversal which reaches more than this many nodes halts with ErrRecursionLimit.  Optional; it's a defense against cyclic Node implementations (which would otherwise make recursive selectors loop forever), not a budget.
+	MatchPerLabel              bool                       // If true, a node matched by several Matchers at once (e.g. via the branches of an ExploreUnion) is visited once per label, rather than once with all the labels.
+	NodeCache                  *NodeCache                 // Cache for Nodes loaded during automatic link traversal.  Optional; use it if the same links are reached repeatedly (e.g. in diamond-shaped DAGs).
+	Stats                      *WalkStats                 // If set, traversals using this Config count the work they do here.  Optional; see WalkStats.
+	OnEvent                    func(Event)                // If set, called for each node visited, link loaded, and match found, in the order they happen.  Optional; see Event.
+	StrictInterests            bool                       // If true, a segment the selector specifically targets (e.g. by ExploreFields, ExploreIndex, or ExploreRange) which is absent from the data halts the traversal with ErrSelectorMismatch.  By default, such segments just select nothing, as does any lookup which fails.  (When strict, a lookup which fails with anything but ErrNotExists or schema.ErrNoSuchField halts the traversal with that error.)
 }
 
 // LinkTargetNodeStyleChooser is a function that returns a NodeStyle based on
@@ -57,13 +72,110 @@ type LinkTargetNodeStyleChooser func(ipld.Link, ipld.LinkContext) (ipld.NodeStyl
 
 // SkipMe is a signalling "error" which can be used to tell traverse to skip some data.
 //
-// SkipMe can be returned by the Config.LinkLoader to skip entire blocks without aborting the walk.
+// SkipMe can be returned by the Config.LinkLoader (or the LinkTargetNodeStyleChooser,
+// which is consulted first) to skip entire blocks without aborting the walk.
 // (This can be useful if you know you don't have data on hand,
 // but want to continue the walk in other areas anyway;
 // or, if you're doing a way where you know that it's valid to memoize seen
 // areas based on Link alone.)
+//
+// SkipMe can also be returned by the VisitFn (or AdvVisitFn) of a walk,
+// to skip descending into the node just visited, like filepath.SkipDir.
+// In both cases, the walk carries on with the skipped node's siblings.
 type SkipMe struct{}
 
 func (SkipMe) Error() string {
 	return "skip"
 }
+
+// ErrSelectorMismatch is returned from a traversal with Config.StrictInterests set,
+// when a selector targets a specific segment which isn't there in the data --
+// a sign that the selector was written for data of a different shape.
+type ErrSelectorMismatch struct {
+	Path    ipld.Path        // Path to the node which lacked the targeted segment.
+	Segment ipld.PathSegment // Segment is what the selector targeted.
+	Kind    ipld.ReprKind    // Kind of the node at Path.
+	Length  int              // Length of the node at Path (or -1, if it's not a map or list).
+}
+
+func (e ErrSelectorMismatch) Error() string {
+	var msg string
+	_, notIndex := e.Segment.Index()
+	switch {
+	case e.Kind == ipld.ReprKind_List && notIndex == nil:
+		msg = fmt.Sprintf("selector targeted index %s but list length %d", e.Segment, e.Length)
+	case e.Kind == ipld.ReprKind_Map:
+		msg = fmt.Sprintf("selector targeted field %q but map has no such key", e.Segment.String())
+	default:
+		msg = fmt.Sprintf("selector targeted %q but node is of kind %s", e.Segment.String(), e.Kind)
+	}
+	if len(e.Path.Segments()) > 0 {
+		msg += fmt.Sprintf(" (at path %q)", e.Path.String())
+	}
+	return msg
+}
+
+// isNotExists reports whether a lookup error just means the segment isn't there,
+// which is what Config.StrictInterests regards (as opposed to a failed lookup).
+// Typed structs and unions say that with schema.ErrNoSuchField.
+func isNotExists(err error) bool {
+	switch err.(type) {
+	case ipld.ErrNotExists, schema.ErrNoSuchField:
+		return true
+	default:
+		return false
+	}
+}
+
+// ErrRecursionLimit is returned from a traversal which reaches more nodes
+// than Config.MaxSteps allows.  In data which is acyclic (as Nodes should be),
+// this just means the traversal was too big; but a Node implementation
+// which contains itself would otherwise make a recursive selector
+// traverse it forever.
+type ErrRecursionLimit struct {
+	Path  ipld.Path // Path to the node at which the limit was exceeded.
+	Limit int       // Limit is the Config.MaxSteps which was exceeded.
+}
+
+func (e ErrRecursionLimit) Error() string {
+	return fmt.Sprintf("traversal exceeded the limit of %d steps at %q (is the data cyclic?)", e.Limit, e.Path.String())
+}
+
+// ErrLinkLoaderRequired is returned from a traversal which needs to load a link
+// (to go on past it, or to reach a path through it) when the Config has no LinkLoader.
+// Without this, the missing loader would only show up as whatever error
+// the other link-handling parts of the Config happened to return first.
+type ErrLinkLoaderRequired struct {
+	Path ipld.Path // Path to the link node.
+	Link ipld.Link // Link which couldn't be loaded.
+}
+
+func (e ErrLinkLoaderRequired) Error() string {
+	return fmt.Sprintf("cannot traverse link %q at %q: no LinkLoader configured", e.Link, e.Path.String())
+}
+
+// ErrUnexpectedKind is returned from a traversal when a selector requires
+// a node to be of certain kinds (see selector.ExpectedKinds), and it isn't.
+type ErrUnexpectedKind struct {
+	Path     ipld.Path        // Path to the node of the wrong kind.
+	Expected ipld.ReprKindSet // Expected is the kinds the selector allows.
+	Actual   ipld.ReprKind    // Actual is the kind of the node at Path.
+}
+
+func (e ErrUnexpectedKind) Error() string {
+	return fmt.Sprintf("selector requires node at %q to be %s, but it is %s", e.Path.String(), e.Expected, e.Actual)
+}
+
+// ErrCursorMismatch is returned from a walk resumed from a Cursor
+// (see WalkMatchingFrom) when the Cursor's path can't be followed:
+// the data doesn't have it, or the selector doesn't reach it.
+// Usually this means the walk was resumed on different data, or with a different selector,
+// than the walk which produced the Cursor.
+type ErrCursorMismatch struct {
+	Path ipld.Path // Path to the deepest node on the Cursor's path which could be reached.
+	Want ipld.PathSegment
+}
+
+func (e ErrCursorMismatch) Error() string {
+	return fmt.Sprintf("cannot resume traversal: no segment %q reachable at %q", e.Want.String(), e.Path.String())
+}
@@ -2,6 +2,7 @@ package traversal
 
 import (
 	"fmt"
+	"reflect"
 
 	ipld "github.com/ipld/go-ipld-prime"
 )
@@ -29,6 +30,34 @@ func FocusedTransform(n ipld.Node, p ipld.Path, fn TransformFn) (ipld.Node, erro
 	return Progress{}.FocusedTransform(n, p, fn)
 }
 
+// GetPath returns the node reached by following a path, given in string form
+// (e.g. "a/3/x"; see ipld.ParsePath), from the root node.
+// Segments index into lists when the node reached so far is a list,
+// and into maps otherwise, just as Focus does.
+//
+// GetPath cannot cross links; see Focus.
+func GetPath(root ipld.Node, path string) (ipld.Node, error) {
+	var found ipld.Node
+	err := Focus(root, ipld.ParsePath(path), func(_ Progress, n ipld.Node) error {
+		found = n
+		return nil
+	})
+	return found, err
+}
+
+// SetPath returns a copy of the root node with the node at the given path
+// (in string form, as for GetPath) replaced by value.
+// If the last segment of the path is a key that doesn't exist in its map yet,
+// it's added; all the other segments must already exist.
+// The root node is unchanged.
+//
+// SetPath cannot cross links; see FocusedTransform.
+func SetPath(root ipld.Node, path string, value ipld.Node) (ipld.Node, error) {
+	return FocusedTransform(root, ipld.ParsePath(path), func(Progress, ipld.Node) (ipld.Node, error) {
+		return value, nil
+	})
+}
+
 // Focus traverses a Node graph according to a path, reaches a single Node,
 // and calls the given VisitFn on that reached node.
 //
@@ -81,25 +110,22 @@ func (prog Progress) Focus(n ipld.Node, p ipld.Path, fn VisitFn) error {
 				LinkNode:   n,
 				ParentNode: prev,
 			}
+			if prog.Cfg.LinkLoader == nil {
+				return ErrLinkLoaderRequired{Path: p.Truncate(i + 1), Link: lnk}
+			}
 			// Pick what in-memory format we will build.
 			ns, err := prog.Cfg.LinkTargetNodeStyleChooser(lnk, lnkCtx)
 			if err != nil {
 				return fmt.Errorf("error traversing node at %q: could not load link %q: %s", p.Truncate(i+1), lnk, err)
 			}
-			nb := ns.NewBuilder()
-			// Load link!
-			err = lnk.Load(
-				prog.Cfg.Ctx,
-				lnkCtx,
-				nb,
-				prog.Cfg.LinkLoader,
-			)
+			// Load link!  (Or reuse it, if it's cached.)
+			next, err := prog.Cfg.loadLink(lnk, lnkCtx, ns)
 			if err != nil {
 				return fmt.Errorf("error traversing node at %q: could not load link %q: %s", p.Truncate(i+1), lnk, err)
 			}
 			prog.LastBlock.Path = p.Truncate(i + 1)
 			prog.LastBlock.Link = lnk
-			prev, n = n, nb.Build()
+			prev, n = n, next
 		}
 	}
 	prog.Path = prog.Path.Join(p)
@@ -136,7 +162,140 @@ func (prog Progress) Focus(n ipld.Node, p ipld.Path, fn VisitFn) error {
 // does a large amount of the intermediate bookkeeping that's useful when
 // creating new values which are partial updates to existing values.
 //
-// This feature is not yet implemented.
+// If the last segment of the path names a map key which doesn't exist,
+// the TransformFn is called with ipld.Undef, and the node it returns is
+// added to the map under that key.  (Returning ipld.Undef again leaves the
+// map as it was.)  Any other segment which can't be found is an error.
+//
+// Replaced parents are built with the NodeStyle of the node they replace.
+//
+// Crossing links is not yet supported: transforming a path which passes
+// through a link would require storing a new block and updating the link,
+// and FocusedTransform will return an error instead.
+// (A link at the very end of the path is fine: it's just handed to the TransformFn.)
 func (prog Progress) FocusedTransform(n ipld.Node, p ipld.Path, fn TransformFn) (ipld.Node, error) {
-	panic("TODO") // TODO surprisingly different from Focus -- need to store nodes we traversed, and able do building.
+	prog.init()
+	return prog.focusedTransform(n, p, 0, fn)
+}
+
+func (prog Progress) focusedTransform(n ipld.Node, p ipld.Path, i int, fn TransformFn) (ipld.Node, error) {
+	segments := p.Segments()
+	if i == len(segments) {
+		prog.Path = prog.Path.Join(p)
+		return fn(prog, n)
+	}
+	seg := segments[i]
+	switch n.ReprKind() {
+	case ipld.ReprKind_Invalid:
+		return nil, fmt.Errorf("cannot traverse node at %q: it is undefined", p.Truncate(i))
+	case ipld.ReprKind_Map:
+		prev, err := n.LookupString(seg.String())
+		if _, ok := err.(ipld.ErrNotExists); ok && i == len(segments)-1 {
+			prev = ipld.Undef
+		} else if err != nil {
+			return nil, fmt.Errorf("error traversing segment %q on node at %q: %s", seg, p.Truncate(i), err)
+		}
+		next, err := prog.focusedTransformChild(prev, p, i, fn)
+		if err != nil || sameNode(next, prev) {
+			return n, err
+		}
+		return replaceMapEntry(n, seg.String(), next)
+	case ipld.ReprKind_List:
+		intSeg, err := seg.Index()
+		if err != nil {
+			return nil, fmt.Errorf("error traversing segment %q on node at %q: the segment cannot be parsed as a number and the node is a list", seg, p.Truncate(i))
+		}
+		prev, err := n.LookupIndex(intSeg)
+		if err != nil {
+			return nil, fmt.Errorf("error traversing segment %q on node at %q: %s", seg, p.Truncate(i), err)
+		}
+		next, err := prog.focusedTransformChild(prev, p, i, fn)
+		if err != nil || sameNode(next, prev) {
+			return n, err
+		}
+		return replaceListEntry(n, intSeg, next)
+	default:
+		return nil, fmt.Errorf("cannot traverse node at %q: %s", p.Truncate(i), fmt.Errorf("cannot traverse terminals"))
+	}
+}
+
+// focusedTransformChild continues focusedTransform into the child reached by segment i,
+// refusing to go further if the child is a link and there's more path left.
+func (prog Progress) focusedTransformChild(child ipld.Node, p ipld.Path, i int, fn TransformFn) (ipld.Node, error) {
+	if child.ReprKind() == ipld.ReprKind_Link && i+1 < len(p.Segments()) {
+		return nil, fmt.Errorf("cannot transform node at %q: crossing links is not yet supported", p.Truncate(i+1))
+	}
+	return prog.focusedTransform(child, p, i+1, fn)
+}
+
+// sameNode reports whether two nodes are identical (not just equal in content),
+// without panicking on node implementations that aren't comparable.
+func sameNode(a, b ipld.Node) bool {
+	if t := reflect.TypeOf(a); t != reflect.TypeOf(b) || t == nil || !t.Comparable() {
+		return false
+	}
+	return a == b
+}
+
+// replaceMapEntry builds a copy of the map n, with the value for key replaced by v
+// (or removed, if v is ipld.Undef; or appended, if the key wasn't there yet).
+func replaceMapEntry(n ipld.Node, key string, v ipld.Node) (ipld.Node, error) {
+	nb := n.Style().NewBuilder()
+	ma, err := nb.BeginMap(n.Length() + 1)
+	if err != nil {
+		return nil, err
+	}
+	found := false
+	err = ipld.DrainMapIterator(n.MapIterator(), func(k ipld.Node, v2 ipld.Node) error {
+		if ks, _ := k.AsString(); ks == key {
+			found, v2 = true, v
+			if v2.IsUndefined() {
+				return nil
+			}
+		}
+		if err := ma.AssembleKey().AssignNode(k); err != nil {
+			return err
+		}
+		return ma.AssembleValue().AssignNode(v2)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if !found && !v.IsUndefined() {
+		if err := ma.AssembleKey().AssignString(key); err != nil {
+			return nil, err
+		}
+		if err := ma.AssembleValue().AssignNode(v); err != nil {
+			return nil, err
+		}
+	}
+	if err := ma.Finish(); err != nil {
+		return nil, err
+	}
+	return nb.Build(), nil
+}
+
+// replaceListEntry builds a copy of the list n, with the value at idx replaced by v.
+func replaceListEntry(n ipld.Node, idx int, v ipld.Node) (ipld.Node, error) {
+	nb := n.Style().NewBuilder()
+	la, err := nb.BeginList(n.Length())
+	if err != nil {
+		return nil, err
+	}
+	for itr := n.ListIterator(); !itr.Done(); {
+		i, v2, err := itr.Next()
+		if err != nil {
+			return nil, err
+		}
+		if i == idx {
+			v2 = v
+		}
+		if err := la.AssembleValue().AssignNode(v2); err != nil {
+			return nil, err
+		}
+	}
+	if err := la.Finish(); err != nil {
+		return nil, err
+	}
+	return nb.Build(), nil
 }
@@ -54,9 +54,14 @@ var (
 // just gimme a link and stuff the bytes in a map.
 // (also return the node again for convenient assignment.)
 func encode(n ipld.Node) (ipld.Node, ipld.Link) {
+	return encodeWithCodec(n, 0x0129)
+}
+
+// encodeWithCodec is encode, but with a choice of multicodec.
+func encodeWithCodec(n ipld.Node, codec uint64) (ipld.Node, ipld.Link) {
 	lb := cidlink.LinkBuilder{cid.Prefix{
 		Version:  1,
-		Codec:    0x0129,
+		Codec:    codec,
 		MhType:   0x17,
 		MhLength: 4,
 	}}
@@ -130,14 +135,14 @@ func TestFocusWithLinkLoading(t *testing.T) {
 				t.Errorf("should not be reached; no way to load this path")
 				return nil
 			})
-			Wish(t, err.Error(), ShouldEqual, `error traversing node at "nested/alink": could not load link "`+leafAlphaLnk.String()+`": no LinkTargetNodeStyleChooser configured`)
+			Wish(t, err, ShouldEqual, traversal.ErrLinkLoaderRequired{Path: ipld.ParsePath("nested/alink"), Link: leafAlphaLnk})
 		})
 		t.Run("mid-path link should fail", func(t *testing.T) {
 			err := traversal.Focus(rootNode, ipld.ParsePath("linkedMap/nested/nonlink"), func(prog traversal.Progress, n ipld.Node) error {
 				t.Errorf("should not be reached; no way to load this path")
 				return nil
 			})
-			Wish(t, err.Error(), ShouldEqual, `error traversing node at "linkedMap": could not load link "`+middleMapNodeLnk.String()+`": no LinkTargetNodeStyleChooser configured`)
+			Wish(t, err, ShouldEqual, traversal.ErrLinkLoaderRequired{Path: ipld.ParsePath("linkedMap"), Link: middleMapNodeLnk})
 		})
 	})
 	t.Run("link traversal with loader should work", func(t *testing.T) {
@@ -160,3 +165,82 @@ func TestFocusWithLinkLoading(t *testing.T) {
 		Wish(t, err, ShouldEqual, nil)
 	})
 }
+
+func TestFocusedTransform(t *testing.T) {
+	t.Run("replacing a nested map value rebuilds the parents", func(t *testing.T) {
+		n, err := traversal.FocusedTransform(middleMapNode, ipld.ParsePath("nested/nonlink"), func(prog traversal.Progress, prev ipld.Node) (ipld.Node, error) {
+			Wish(t, prev, ShouldEqual, basicnode.NewString("zoo"))
+			Wish(t, prog.Path, ShouldEqual, ipld.ParsePath("nested/nonlink"))
+			return basicnode.NewString("new"), nil
+		})
+		Wish(t, err, ShouldEqual, nil)
+		Wish(t, mustGetPath(t, n, "nested/nonlink"), ShouldEqual, basicnode.NewString("new"))
+		Wish(t, mustGetPath(t, n, "foo"), ShouldEqual, basicnode.NewBool(true))
+		Wish(t, mustGetPath(t, middleMapNode, "nested/nonlink"), ShouldEqual, basicnode.NewString("zoo"))
+	})
+	t.Run("returning the same node is a no-op", func(t *testing.T) {
+		n, err := traversal.FocusedTransform(middleMapNode, ipld.ParsePath("nested"), func(_ traversal.Progress, prev ipld.Node) (ipld.Node, error) {
+			return prev, nil
+		})
+		Wish(t, err, ShouldEqual, nil)
+		Wish(t, n == middleMapNode, ShouldEqual, true)
+	})
+	t.Run("returning undef for a missing key is a no-op", func(t *testing.T) {
+		n, err := traversal.FocusedTransform(middleMapNode, ipld.ParsePath("nope"), func(_ traversal.Progress, prev ipld.Node) (ipld.Node, error) {
+			Wish(t, prev.IsUndefined(), ShouldEqual, true)
+			return prev, nil
+		})
+		Wish(t, err, ShouldEqual, nil)
+		Wish(t, n == middleMapNode, ShouldEqual, true)
+	})
+	t.Run("mid-path link should fail", func(t *testing.T) {
+		_, err := traversal.FocusedTransform(rootNode, ipld.ParsePath("linkedMap/foo"), func(traversal.Progress, ipld.Node) (ipld.Node, error) {
+			t.Errorf("should not be reached; cannot cross links")
+			return nil, nil
+		})
+		Wish(t, err.Error(), ShouldEqual, `cannot transform node at "linkedMap": crossing links is not yet supported`)
+	})
+}
+
+func TestSetPath(t *testing.T) {
+	root := fluent.MustBuildMap(basicnode.Style__Map{}, 1, func(na fluent.MapAssembler) {
+		na.AssembleEntry("a").CreateList(2, func(na fluent.ListAssembler) {
+			na.AssembleValue().AssignInt(0)
+			na.AssembleValue().CreateMap(1, func(na fluent.MapAssembler) {
+				na.AssembleEntry("x").AssignString("old")
+			})
+		})
+	})
+	t.Run("set then get an existing path", func(t *testing.T) {
+		n, err := traversal.SetPath(root, "a/1/x", basicnode.NewString("new"))
+		Wish(t, err, ShouldEqual, nil)
+		Wish(t, mustGetPath(t, n, "a/1/x"), ShouldEqual, basicnode.NewString("new"))
+		Wish(t, mustGetPath(t, n, "a/0"), ShouldEqual, basicnode.NewInt(0))
+		Wish(t, mustGetPath(t, root, "a/1/x"), ShouldEqual, basicnode.NewString("old"))
+	})
+	t.Run("set then get a new key", func(t *testing.T) {
+		n, err := traversal.SetPath(root, "a/1/y", basicnode.NewInt(7))
+		Wish(t, err, ShouldEqual, nil)
+		Wish(t, mustGetPath(t, n, "a/1/y"), ShouldEqual, basicnode.NewInt(7))
+		Wish(t, mustGetPath(t, n, "a/1/x"), ShouldEqual, basicnode.NewString("old"))
+	})
+	t.Run("missing intermediate segments are an error", func(t *testing.T) {
+		_, err := traversal.SetPath(root, "b/x", basicnode.NewInt(7))
+		Wish(t, err.Error(), ShouldEqual, `error traversing segment "b" on node at "": key not found: "b"`)
+		_, err = traversal.SetPath(root, "a/5", basicnode.NewInt(7))
+		Wish(t, err.Error(), ShouldEqual, `error traversing segment "5" on node at "a": key not found: "5"`)
+	})
+	t.Run("get on a missing path is an error", func(t *testing.T) {
+		_, err := traversal.GetPath(root, "a/1/nope")
+		Wish(t, err.Error(), ShouldEqual, `error traversing segment "nope" on node at "a/1": key not found: "nope"`)
+	})
+}
+
+func mustGetPath(t *testing.T, n ipld.Node, path string) ipld.Node {
+	t.Helper()
+	v, err := traversal.GetPath(n, path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return v
+}
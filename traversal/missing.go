@@ -0,0 +1,66 @@
+package traversal
+
+import (
+	"io"
+
+	ipld "github.com/ipld/go-ipld-prime"
+	"github.com/ipld/go-ipld-prime/traversal/selector"
+)
+
+// WalkMissing walks a graph of Nodes as WalkAdv would, and returns the Links
+// the Selector would have it follow which aren't available locally,
+// according to the 'has' function.  Missing links are never loaded;
+// this is for working out what to fetch before (or instead of) doing a full walk.
+//
+// This function is a helper function which starts a new walk with default configuration.
+// It can't descend into links at all (since this requires configuration),
+// so it only reports the missing links reachable without crossing a link.
+// Use the equivalent WalkMissing function on the Progress structure
+// to descend into the links which are present.
+func WalkMissing(n ipld.Node, s selector.Selector, has func(ipld.Link) bool) ([]ipld.Link, error) {
+	return Progress{}.WalkMissing(n, s, has)
+}
+
+// WalkMissing walks a graph of Nodes as WalkAdv would, and returns the Links
+// the Selector would have it follow for which 'has' returns false.
+// The walk doesn't descend into those links (it carries on with their siblings,
+// as if the LinkLoader had returned SkipMe), so nothing is reported from beneath them.
+//
+// Links for which 'has' returns true are loaded with the Config's LinkLoader
+// and LinkTargetNodeStyleChooser, and walked as usual.
+// If the Config has no LinkLoader, they're skipped instead:
+// the result is then only what's missing on the way to the first links present.
+//
+// Each missing Link is reported once, in the order the walk first reached it,
+// even if it's reached by several paths.
+func (prog Progress) WalkMissing(n ipld.Node, s selector.Selector, has func(ipld.Link) bool) ([]ipld.Link, error) {
+	canLoad := prog.Cfg != nil && prog.Cfg.LinkLoader != nil
+	prog.init()
+	cfg := *prog.Cfg
+	var missing []ipld.Link
+	seen := make(map[string]struct{})
+	chooser := cfg.LinkTargetNodeStyleChooser
+	cfg.LinkTargetNodeStyleChooser = func(lnk ipld.Link, lnkCtx ipld.LinkContext) (ipld.NodeStyle, error) {
+		if !has(lnk) {
+			if _, ok := seen[lnk.String()]; !ok {
+				seen[lnk.String()] = struct{}{}
+				missing = append(missing, lnk)
+			}
+			return nil, SkipMe{}
+		}
+		if !canLoad {
+			return nil, SkipMe{}
+		}
+		return chooser(lnk, lnkCtx)
+	}
+	if !canLoad {
+		cfg.LinkLoader = func(ipld.Link, ipld.LinkContext) (io.Reader, error) {
+			return nil, SkipMe{}
+		}
+	}
+	prog.Cfg = &cfg
+	err := prog.walkAdv(n, s, func(Progress, ipld.Node, VisitReason) error {
+		return nil
+	})
+	return missing, err
+}
@@ -0,0 +1,55 @@
+package traversal_test
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	. "github.com/warpfork/go-wish"
+
+	ipld "github.com/ipld/go-ipld-prime"
+	basicnode "github.com/ipld/go-ipld-prime/node/basic"
+	"github.com/ipld/go-ipld-prime/traversal"
+	"github.com/ipld/go-ipld-prime/traversal/selector"
+	"github.com/ipld/go-ipld-prime/traversal/selector/builder"
+)
+
+func TestWalkMissing(t *testing.T) {
+	ssb := builder.NewSelectorSpecBuilder(basicnode.Style__Any{})
+	s, err := ssb.ExploreRecursive(selector.RecursionLimitNone(), ssb.ExploreAll(ssb.ExploreRecursiveEdge())).Selector()
+	Require(t, err, ShouldEqual, nil)
+	// Both middle nodes are on hand, but neither leaf is.
+	// leafAlpha is reached four times (from the root, the map, and the list), but is reported once;
+	// leafBeta is only reached through the list.
+	has := func(lnk ipld.Link) bool {
+		return lnk == middleMapNodeLnk || lnk == middleListNodeLnk
+	}
+
+	t.Run("present links are descended", func(t *testing.T) {
+		var loaded []ipld.Link
+		missing, err := traversal.Progress{
+			Cfg: &traversal.Config{
+				LinkLoader: func(lnk ipld.Link, _ ipld.LinkContext) (io.Reader, error) {
+					loaded = append(loaded, lnk)
+					return bytes.NewBuffer(storage[lnk]), nil
+				},
+				LinkTargetNodeStyleChooser: func(_ ipld.Link, _ ipld.LinkContext) (ipld.NodeStyle, error) {
+					return basicnode.Style__Any{}, nil
+				},
+			},
+		}.WalkMissing(rootNode, s, has)
+		Wish(t, err, ShouldEqual, nil)
+		Wish(t, missing, ShouldEqual, []ipld.Link{leafAlphaLnk, leafBetaLnk})
+		Wish(t, loaded, ShouldEqual, []ipld.Link{middleMapNodeLnk, middleListNodeLnk})
+	})
+	t.Run("without a loader, present links are skipped", func(t *testing.T) {
+		missing, err := traversal.WalkMissing(rootNode, s, has)
+		Wish(t, err, ShouldEqual, nil)
+		Wish(t, missing, ShouldEqual, []ipld.Link{leafAlphaLnk})
+	})
+	t.Run("a missing link hides what's beneath it", func(t *testing.T) {
+		missing, err := traversal.WalkMissing(rootNode, s, func(ipld.Link) bool { return false })
+		Wish(t, err, ShouldEqual, nil)
+		Wish(t, missing, ShouldEqual, []ipld.Link{leafAlphaLnk, middleMapNodeLnk, middleListNodeLnk})
+	})
+}
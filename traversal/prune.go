@@ -0,0 +1,159 @@
+package traversal
+
+import (
+	"fmt"
+	"io"
+
+	ipld "github.com/ipld/go-ipld-prime"
+	"github.com/ipld/go-ipld-prime/traversal/selector"
+)
+
+// Prune returns a copy of a Node graph cut down to just the parts a selector matches.
+//
+// This function is a helper function which starts a new traversal with default configuration.
+// It cannot cross links automatically (since this requires configuration).
+// Use the equivalent Prune function on the Progress structure
+// for more advanced and configurable walks.
+func Prune(n ipld.Node, s selector.Selector) (ipld.Node, error) {
+	return Progress{}.Prune(n, s)
+}
+
+// Prune returns a copy of a Node graph cut down to just the parts a selector matches.
+//
+// Each matched node is kept whole, with everything beneath it.
+// Each ancestor of a matched node is kept as a partial map or list, holding
+// only the entries which lead to matches (in their original order);
+// all other entries are omitted.  Note that this means the indexes of
+// elements in a partial list may differ from their indexes in the original.
+// Links which the selector explores through are replaced with
+// (the pruned form of) the content they link to.
+//
+// Partial maps and lists are built with the NodeStyle of the node they stand in for.
+// If the selector matches nothing at all, Prune returns nil.
+func (prog Progress) Prune(n ipld.Node, s selector.Selector) (ipld.Node, error) {
+	prog.init()
+	// The walk visits each node before those beneath it, so the nodes on the path
+	// to the one being visited are a stack, and each is its predecessor's child.
+	// Matched nodes are kept whole, so there's no need to walk beneath them.
+	base := len(prog.Path.Segments())
+	var stack []*prunedNode
+	err := prog.walkAdv(n, s, func(prog Progress, n ipld.Node, tr VisitReason) error {
+		depth := len(prog.Path.Segments()) - base
+		stack = stack[:depth]
+		pn := &prunedNode{n: n, matched: tr == VisitReason_SelectionMatch}
+		if depth > 0 {
+			pn.ps, _ = prog.Path.Last()
+			parent := stack[depth-1]
+			parent.children = append(parent.children, pn)
+		}
+		stack = append(stack, pn)
+		if pn.matched {
+			return SkipMe{}
+		}
+		return nil
+	})
+	if err != nil || len(stack) == 0 {
+		return nil, err
+	}
+	return stack[0].build()
+}
+
+// EncodeSelective encodes only the parts of a Node graph which a selector matches,
+// as described by Prune, using the given encoder
+// (e.g. dagjson.Encoder, or any other cidlink.MulticodecEncoder).
+//
+// It cannot cross links; to do that, use Progress.Prune, and encode the result.
+// If the selector matches nothing at all, EncodeSelective returns an error,
+// and writes nothing.
+func EncodeSelective(n ipld.Node, s selector.Selector, encoder func(ipld.Node, io.Writer) error, w io.Writer) error {
+	pruned, err := Prune(n, s)
+	if err != nil {
+		return err
+	}
+	if pruned == nil {
+		return fmt.Errorf("nothing to encode: selector %s matched nothing", s)
+	}
+	return encoder(pruned, w)
+}
+
+// prunedNode is a node visited by Prune's walk, and those visited beneath it.
+type prunedNode struct {
+	n        ipld.Node
+	ps       ipld.PathSegment // where n is in its parent.
+	matched  bool
+	children []*prunedNode
+}
+
+// build returns the pruned form of the node: the node itself if it was matched,
+// or else a partial copy holding the pruned forms of its children (or nil if there are none).
+func (pn *prunedNode) build() (ipld.Node, error) {
+	if pn.matched {
+		return pn.n, nil
+	}
+	kept := make(map[string]ipld.Node)
+	for _, c := range pn.children {
+		v, err := c.build()
+		if err != nil {
+			return nil, err
+		}
+		if v != nil {
+			kept[c.ps.String()] = v
+		}
+	}
+	if len(kept) == 0 {
+		return nil, nil
+	}
+	return rebuildPartial(pn.n, kept)
+}
+
+// rebuildPartial builds a map or list like n, but with only the entries in kept
+// (keyed by path segment string), in n's order.
+func rebuildPartial(n ipld.Node, kept map[string]ipld.Node) (ipld.Node, error) {
+	nb := n.Style().NewBuilder()
+	if n.ReprKind() == ipld.ReprKind_List {
+		la, err := nb.BeginList(len(kept))
+		if err != nil {
+			return nil, err
+		}
+		for itr := n.ListIterator(); !itr.Done(); {
+			idx, _, err := itr.Next()
+			if err != nil {
+				return nil, err
+			}
+			if v, ok := kept[ipld.PathSegmentOfInt(idx).String()]; ok {
+				if err := la.AssembleValue().AssignNode(v); err != nil {
+					return nil, err
+				}
+			}
+		}
+		if err := la.Finish(); err != nil {
+			return nil, err
+		}
+		return nb.Build(), nil
+	}
+	ma, err := nb.BeginMap(len(kept))
+	if err != nil {
+		return nil, err
+	}
+	err = ipld.ForEach(n, func(k ipld.Node, _ ipld.Node) error {
+		ks, err := k.AsString()
+		if err != nil {
+			return err
+		}
+		v, ok := kept[ks]
+		if !ok {
+			return nil
+		}
+		if err := ma.AssembleKey().AssignNode(k); err != nil {
+			return err
+		}
+		return ma.AssembleValue().AssignNode(v)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := ma.Finish(); err != nil {
+		return nil, err
+	}
+	return nb.Build(), nil
+}
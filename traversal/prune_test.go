@@ -0,0 +1,73 @@
+package traversal_test
+
+import (
+	"bytes"
+	"testing"
+
+	. "github.com/warpfork/go-wish"
+
+	ipld "github.com/ipld/go-ipld-prime"
+	"github.com/ipld/go-ipld-prime/codec/dagjson"
+	"github.com/ipld/go-ipld-prime/fluent"
+	basicnode "github.com/ipld/go-ipld-prime/node/basic"
+	"github.com/ipld/go-ipld-prime/traversal"
+	"github.com/ipld/go-ipld-prime/traversal/selector"
+)
+
+func TestEncodeSelective(t *testing.T) {
+	n := fluent.MustBuildMap(basicnode.Style__Map{}, 3, func(na fluent.MapAssembler) {
+		na.AssembleEntry("a").AssignInt(1)
+		na.AssembleEntry("b").CreateMap(1, func(na fluent.MapAssembler) {
+			na.AssembleEntry("x").AssignString("big")
+		})
+		na.AssembleEntry("c").CreateList(3, func(na fluent.ListAssembler) {
+			na.AssembleValue().AssignString("zero")
+			na.AssembleValue().AssignString("one")
+			na.AssembleValue().AssignString("two")
+		})
+	})
+	encodeSelective := func(spec string) (string, error) {
+		s, err := selector.ParseFromJSON(basicnode.Style__Any{}, []byte(spec))
+		Require(t, err, ShouldEqual, nil)
+		var buf bytes.Buffer
+		err = traversal.EncodeSelective(n, s, dagjson.Encoder, &buf)
+		return buf.String(), err
+	}
+	t.Run("one field of a map", func(t *testing.T) {
+		out, err := encodeSelective(`{"f": {"f>": {"a": {".": {}}}}}`)
+		Wish(t, err, ShouldEqual, nil)
+		Wish(t, out, ShouldEqual, "{\n\t\"a\": 1\n}\n")
+	})
+	t.Run("matched subtrees are kept whole", func(t *testing.T) {
+		out, err := encodeSelective(`{"f": {"f>": {"b": {".": {}}}}}`)
+		Wish(t, err, ShouldEqual, nil)
+		Wish(t, out, ShouldEqual, "{\n\t\"b\": {\n\t\t\"x\": \"big\"\n\t}\n}\n")
+	})
+	t.Run("partial lists omit unmatched elements", func(t *testing.T) {
+		out, err := encodeSelective(`{"f": {"f>": {"c": {"r": {"^": 1, "$": 3, ">": {".": {"k": ["String"]}}}}, "a": {".": {}}}}}`)
+		Wish(t, err, ShouldEqual, nil)
+		Wish(t, out, ShouldEqual, "{\n\t\"a\": 1,\n\t\"c\": [\n\t\t\"one\",\n\t\t\"two\"\n\t]\n}\n")
+	})
+	t.Run("nothing matched", func(t *testing.T) {
+		out, err := encodeSelective(`{"f": {"f>": {"nope": {".": {}}}}}`)
+		Wish(t, err.Error(), ShouldEqual, "nothing to encode: selector ExploreFields{nope: Matcher} matched nothing")
+		Wish(t, out, ShouldEqual, "")
+	})
+}
+
+func TestPrune(t *testing.T) {
+	// Pruning leaves the original alone, and shares the matched nodes with it.
+	s, err := selector.ParseFromJSON(basicnode.Style__Any{}, []byte(`{"f": {"f>": {"nested": {"f": {"f>": {"nonlink": {".": {}}}}}}}}`))
+	Require(t, err, ShouldEqual, nil)
+	pruned, err := traversal.Prune(middleMapNode, s)
+	Require(t, err, ShouldEqual, nil)
+	Wish(t, ipld.Sprint(pruned), ShouldEqual, `{"nested": {"nonlink": "zoo"}}`)
+	Wish(t, middleMapNode.Length(), ShouldEqual, 3)
+
+	// Pruning is a walk like any other, configured the same way.
+	var stats traversal.WalkStats
+	pruned, err = traversal.Progress{Cfg: &traversal.Config{Stats: &stats}}.Prune(middleMapNode, s)
+	Require(t, err, ShouldEqual, nil)
+	Wish(t, ipld.Sprint(pruned), ShouldEqual, `{"nested": {"nonlink": "zoo"}}`)
+	Wish(t, stats, ShouldEqual, traversal.WalkStats{NodesVisited: 3, Matches: 1})
+}
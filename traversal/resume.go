@@ -0,0 +1,135 @@
+package traversal
+
+import (
+	ipld "github.com/ipld/go-ipld-prime"
+	"github.com/ipld/go-ipld-prime/traversal/selector"
+)
+
+// Cursor marks where a paginated walk (see WalkMatchingFrom) stopped:
+// it's the path of the next match the walk would have visited.
+//
+// A Cursor is only a path, because that's all that's needed:
+// the state of the selector at any node is determined by the selector
+// at the root and the path to that node, so resuming re-derives it
+// by exploring down the Cursor's path again.
+// This means a Cursor is only meaningful with the same root node and selector
+// as the walk which produced it; resuming with others is likely to give
+// ErrCursorMismatch.
+//
+// A Cursor can be saved with String and restored with ParseCursor.
+// (Like ipld.Path, this can't represent map keys which contain "/".)
+type Cursor struct {
+	Path ipld.Path
+}
+
+// String returns the Cursor's path in the form ParseCursor accepts.
+func (c Cursor) String() string {
+	return c.Path.String()
+}
+
+// ParseCursor restores a Cursor saved with Cursor.String.
+func ParseCursor(s string) Cursor {
+	return Cursor{ipld.ParsePath(s)}
+}
+
+// pauseWalk is returned up through a paginated walk when it's visited as many matches as it may.
+type pauseWalk struct {
+	next ipld.Path // the path of the next match, which wasn't visited.
+}
+
+func (pauseWalk) Error() string {
+	return "traversal paused"
+}
+
+// WalkMatchingFrom is like WalkMatching, but can walk in pages.
+//
+// This function is a helper function which starts a new walk with default configuration.
+// It cannot cross links automatically (since this requires configuration).
+// Use the equivalent WalkMatchingFrom function on the Progress structure
+// for more advanced and configurable walks.
+func WalkMatchingFrom(n ipld.Node, s selector.Selector, from *Cursor, limit int, fn VisitFn) (*Cursor, error) {
+	return Progress{}.WalkMatchingFrom(n, s, from, limit, fn)
+}
+
+// WalkMatchingFrom is like WalkMatching, but can walk in pages.
+//
+// If from is nil, the walk starts at the beginning; otherwise it starts from
+// the given Cursor, as returned by an earlier WalkMatchingFrom on the same
+// node and selector.  If limit is positive, the walk stops before visiting
+// more than that many matches.
+//
+// It returns a Cursor from which to continue the walk, or nil if the walk
+// is complete.  (To be able to tell those apart, the walk looks ahead as far
+// as the next match, but doesn't visit it.)
+//
+// Nodes on the path to the Cursor are not visited again when resuming,
+// nor counted in Config.Stats or reported to Config.OnEvent again;
+// and the match a page pauses on is left to the next page to count and report.
+// So the pages of a walk add up to the same stats and events as the whole walk.
+// Other than that, the walk is the same as WalkMatching would do, so for
+// example links on the path to the Cursor are loaded again, and visitors
+// returning SkipMe work as usual.
+// With Config.MatchPerLabel, the limit counts nodes, not visits: a page never
+// stops between two of the visits for one node.
+func (prog Progress) WalkMatchingFrom(n ipld.Node, s selector.Selector, from *Cursor, limit int, fn VisitFn) (*Cursor, error) {
+	prog.init()
+	if from != nil {
+		prog.resuming = true
+		prog.resumeAt = from.Path.Segments()
+	}
+	if limit > 0 {
+		prog.page = &limit
+	}
+	err := prog.WalkMatching(n, s, func(prog Progress, n ipld.Node) error {
+		prog.page = nil // traversals nested in the visitor aren't part of this page.
+		return fn(prog, n)
+	})
+	if p, ok := err.(pauseWalk); ok {
+		return &Cursor{p.next}, nil
+	}
+	return nil, err
+}
+
+// takeMatch is called before visiting a match.
+// In a paginated walk, it uses up one of the page's matches,
+// or returns pauseWalk if there are none left.
+func (prog Progress) takeMatch() error {
+	if prog.page == nil {
+		return nil
+	}
+	if *prog.page == 0 {
+		return pauseWalk{prog.Path}
+	}
+	*prog.page--
+	return nil
+}
+
+// resumeChild is used while iterating the children of a node, when resuming a walk.
+// It reports whether the child at ps comes before the Cursor's path (and so should be skipped),
+// and sets up progNext for the children which don't.
+// found records whether the child on the Cursor's path has been reached yet.
+func (prog Progress) resumeChild(ps ipld.PathSegment, found *bool, progNext *Progress) (skip bool) {
+	switch {
+	case !prog.resuming:
+		return false
+	case *found:
+		progNext.resuming = false
+		progNext.resumeAt = nil
+		return false
+	case ps.String() != prog.resumeAt[0].String():
+		return true
+	default:
+		*found = true
+		progNext.resumeAt = prog.resumeAt[1:]
+		return false
+	}
+}
+
+// resumeDone checks, after iterating the children of a node when resuming a walk,
+// that the child on the Cursor's path was found.
+func (prog Progress) resumeDone(found bool) error {
+	if prog.resuming && !found {
+		return ErrCursorMismatch{prog.Path, prog.resumeAt[0]}
+	}
+	return nil
+}
@@ -0,0 +1,116 @@
+package traversal
+
+import (
+	ipld "github.com/ipld/go-ipld-prime"
+	"github.com/ipld/go-ipld-prime/traversal/selector"
+)
+
+// SelectedNode pairs a node matched by a selector with the path it was reached by.
+type SelectedNode struct {
+	Path ipld.Path
+	Node ipld.Node
+}
+
+// SelectAll walks a graph of Nodes with WalkMatching, and returns every
+// node the Selector matched, in the order they were visited.
+//
+// This function is a helper function which starts a new walk with default configuration.
+// It cannot cross links automatically (since this requires configuration).
+// Use the equivalent SelectAll function on the Progress structure
+// for more advanced and configurable walks.
+func SelectAll(n ipld.Node, s selector.Selector) ([]ipld.Node, error) {
+	return Progress{}.SelectAll(n, s)
+}
+
+// SelectAllWithPaths is identical to SelectAll, except the path of each match is also returned.
+func SelectAllWithPaths(n ipld.Node, s selector.Selector) ([]SelectedNode, error) {
+	return Progress{}.SelectAllWithPaths(n, s)
+}
+
+// SelectLabeled walks a graph of Nodes with WalkMatching, and returns the
+// nodes the Selector matched, grouped by the labels of the Matchers which matched them.
+//
+// This function is a helper function which starts a new walk with default configuration.
+// It cannot cross links automatically (since this requires configuration).
+// Use the equivalent SelectLabeled function on the Progress structure
+// for more advanced and configurable walks.
+func SelectLabeled(n ipld.Node, s selector.Selector) (map[string][]ipld.Node, error) {
+	return Progress{}.SelectLabeled(n, s)
+}
+
+// SelectAll walks a graph of Nodes with WalkMatching, and returns every
+// node the Selector matched, in the order they were visited.
+//
+// All results are accumulated in memory before returning;
+// see SelectStream if the result set may be very large.
+// If the walk errors, the results accumulated so far are returned with the error.
+func (prog Progress) SelectAll(n ipld.Node, s selector.Selector) ([]ipld.Node, error) {
+	var results []ipld.Node
+	err := prog.WalkMatching(n, s, func(prog Progress, n ipld.Node) error {
+		results = append(results, n)
+		return nil
+	})
+	return results, err
+}
+
+// SelectAllWithPaths is identical to SelectAll, except the path of each match is also returned.
+func (prog Progress) SelectAllWithPaths(n ipld.Node, s selector.Selector) ([]SelectedNode, error) {
+	var results []SelectedNode
+	err := prog.WalkMatching(n, s, func(prog Progress, n ipld.Node) error {
+		results = append(results, SelectedNode{prog.Path, n})
+		return nil
+	})
+	return results, err
+}
+
+// SelectLabeled walks a graph of Nodes with WalkMatching, and returns the
+// nodes the Selector matched, grouped by the labels of the Matchers which matched them
+// (see Progress.MatchLabels).  Within each group, nodes are in the order they were visited.
+//
+// A node matched under several labels at once (e.g. by Matchers in different
+// branches of an ExploreUnion) appears in each of those groups.
+// Matchers without a label put their matches in the "" group.
+// Labels which matched nothing have no entry in the result.
+//
+// As with SelectAll, results accumulated before an error are returned with it.
+func (prog Progress) SelectLabeled(n ipld.Node, s selector.Selector) (map[string][]ipld.Node, error) {
+	results := map[string][]ipld.Node{}
+	err := prog.WalkMatching(n, s, func(prog Progress, n ipld.Node) error {
+		for _, label := range prog.MatchLabels {
+			results[label] = append(results[label], n)
+		}
+		return nil
+	})
+	return results, err
+}
+
+// SelectStream is like SelectAllWithPaths, but rather than accumulating the
+// results in memory, it runs the walk in a new goroutine, and sends each
+// match on the returned channel as soon as it's found.
+//
+// The results channel is closed when the walk ends.
+// The error channel then yields exactly one value (nil if the walk completed), and is closed.
+//
+// If the caller wants to stop receiving results before the walk is complete,
+// it must cancel the context in Cfg.Ctx; otherwise the walk's goroutine will
+// block forever trying to send the next result.
+// (The walk then ends with the context's error.)
+func (prog Progress) SelectStream(n ipld.Node, s selector.Selector) (<-chan SelectedNode, <-chan error) {
+	prog.init()
+	results := make(chan SelectedNode)
+	errCh := make(chan error, 1)
+	go func() {
+		defer close(errCh)
+		err := prog.WalkMatching(n, s, func(prog Progress, n ipld.Node) error {
+			select {
+			case results <- SelectedNode{prog.Path, n}:
+				return nil
+			case <-prog.Cfg.Ctx.Done():
+				return prog.Cfg.Ctx.Err()
+			}
+		})
+		close(results)
+		errCh <- err
+	}()
+	return results, errCh
+}
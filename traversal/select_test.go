@@ -0,0 +1,89 @@
+package traversal_test
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/warpfork/go-wish"
+
+	ipld "github.com/ipld/go-ipld-prime"
+	"github.com/ipld/go-ipld-prime/fluent"
+	basicnode "github.com/ipld/go-ipld-prime/node/basic"
+	"github.com/ipld/go-ipld-prime/traversal"
+	"github.com/ipld/go-ipld-prime/traversal/selector"
+	"github.com/ipld/go-ipld-prime/traversal/selector/builder"
+)
+
+func TestSelectAll(t *testing.T) {
+	ssb := builder.NewSelectorSpecBuilder(basicnode.Style__Any{})
+	t.Run("select two fields", func(t *testing.T) {
+		s, err := ssb.ExploreFields(func(efsb builder.ExploreFieldsSpecBuilder) {
+			efsb.Insert("foo", ssb.Matcher())
+			efsb.Insert("bar", ssb.Matcher())
+		}).Selector()
+		Require(t, err, ShouldEqual, nil)
+		results, err := traversal.SelectAll(middleMapNode, s)
+		Wish(t, err, ShouldEqual, nil)
+		Wish(t, results, ShouldEqual, []ipld.Node{basicnode.NewBool(true), basicnode.NewBool(false)})
+
+		withPaths, err := traversal.SelectAllWithPaths(middleMapNode, s)
+		Wish(t, err, ShouldEqual, nil)
+		Require(t, len(withPaths), ShouldEqual, 2)
+		Wish(t, withPaths[0].Path.String(), ShouldEqual, "foo")
+		Wish(t, withPaths[1].Path.String(), ShouldEqual, "bar")
+	})
+	t.Run("select all of a list", func(t *testing.T) {
+		n := fluent.MustBuildList(basicnode.Style__List{}, 3, func(na fluent.ListAssembler) {
+			na.AssembleValue().AssignInt(1)
+			na.AssembleValue().AssignInt(2)
+			na.AssembleValue().AssignInt(3)
+		})
+		s, err := ssb.ExploreAll(ssb.Matcher()).Selector()
+		Require(t, err, ShouldEqual, nil)
+		results, err := traversal.SelectAll(n, s)
+		Wish(t, err, ShouldEqual, nil)
+		Wish(t, results, ShouldEqual, []ipld.Node{basicnode.NewInt(1), basicnode.NewInt(2), basicnode.NewInt(3)})
+
+		results2, errCh := traversal.Progress{}.SelectStream(n, s)
+		var paths []string
+		for r := range results2 {
+			paths = append(paths, r.Path.String())
+		}
+		Wish(t, <-errCh, ShouldEqual, nil)
+		Wish(t, paths, ShouldEqual, []string{"0", "1", "2"})
+	})
+	t.Run("stream can be abandoned by cancelling", func(t *testing.T) {
+		s, err := ssb.ExploreAll(ssb.Matcher()).Selector()
+		Require(t, err, ShouldEqual, nil)
+		ctx, cancel := context.WithCancel(context.Background())
+		n := fluent.MustBuildList(basicnode.Style__List{}, 3, func(na fluent.ListAssembler) {
+			na.AssembleValue().AssignInt(1)
+			na.AssembleValue().AssignInt(2)
+			na.AssembleValue().AssignInt(3)
+		})
+		results, errCh := traversal.Progress{Cfg: &traversal.Config{Ctx: ctx}}.SelectStream(n, s)
+		<-results
+		cancel() // the walk is now blocked sending the second result; it should give up.
+		Wish(t, <-errCh, ShouldEqual, context.Canceled)
+	})
+}
+
+func TestSelectLabeled(t *testing.T) {
+	s, err := selector.ParseFromJSON(basicnode.Style__Any{}, []byte(`{"|": [
+		{"f": {"f>": {
+			"foo": {"|": [{".": {"label": "flags"}}, {".": {"label": "first"}}]},
+			"bar": {".": {"label": "flags"}}
+		}}},
+		{"f": {"f>": {
+			"nested": {"f": {"f>": {"nonlink": {".": {}}}}}
+		}}}
+	]}`))
+	Require(t, err, ShouldEqual, nil)
+	results, err := traversal.SelectLabeled(middleMapNode, s)
+	Wish(t, err, ShouldEqual, nil)
+	Wish(t, results, ShouldEqual, map[string][]ipld.Node{
+		"flags": {basicnode.NewBool(true), basicnode.NewBool(false)},
+		"first": {basicnode.NewBool(true)},
+		"":      {basicnode.NewString("zoo")},
+	})
+}
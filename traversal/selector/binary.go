@@ -0,0 +1,367 @@
+package selector
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+
+	ipld "github.com/ipld/go-ipld-prime"
+	"github.com/ipld/go-ipld-prime/fluent"
+)
+
+// The binary form of a selector is a version byte, then the selector:
+// a tag byte saying which type of selector it is, followed by that type's contents.
+// Ints are varints (zigzag-encoded, as by encoding/binary), counts and lengths are uvarints,
+// strings are a length then the bytes, and kinds are a count then a byte per kind
+// (the ReprKind itself), with a count of zero meaning "none given".
+// The tags are the selector spec's union keys, plus 'L' for MatchLeaves (whose key is two bytes),
+// and 'C' for an ExploreRecursive which is partway through its sequence:
+// that's written as for 'R', followed by the current selector.
+//
+// The format only ever gets new tags; if it ever has to change otherwise, the version byte will too.
+const binaryVersion = 1
+
+const (
+	binaryTag_Matcher              = '.'
+	binaryTag_ExploreAll           = 'a'
+	binaryTag_ExploreFields        = 'f'
+	binaryTag_ExploreIndex         = 'i'
+	binaryTag_ExploreRange         = 'r'
+	binaryTag_ExploreRecursive     = 'R'
+	binaryTag_ExploreUnion         = '|'
+	binaryTag_ExploreRecursiveEdge = '@'
+
+	binaryTag_MatchLeaves             = 'L'
+	binaryTag_ExploreRecursivePartway = 'C'
+)
+
+// Bits of the flags byte which follows a Matcher's tag, saying which of its optional parts follow.
+const (
+	binaryMatcher_Label = 1 << iota
+	binaryMatcher_Kinds
+	binaryMatcher_Subset
+)
+
+// MarshalBinary returns a compact binary serialization of a Selector,
+// for storing large numbers of them; UnmarshalBinary reverses it.
+// The format is particular to this package (not a standard codec),
+// but it's stable: bytes from MarshalBinary will always unmarshal.
+// It's typically a fifth of the size of the selector's spec as (compact) dag-json.
+//
+// What's serialized is the same as the spec Encode would return,
+// so the same things are true of it: it works for any of the selector types
+// defined in this package (and returns ErrNotEncodable for others),
+// and includes the state of a selector which is partway through a traversal.
+func MarshalBinary(s Selector) ([]byte, error) {
+	b := []byte{binaryVersion}
+	return appendBinary(b, s)
+}
+
+// UnmarshalBinary decodes a selector serialized by MarshalBinary,
+// building its spec with the given NodeStyle (see ParseFromJSON),
+// then parses the spec as with ParseEncoded.
+//
+// If the bytes are truncated or otherwise malformed, the error is an ErrDecode.
+func UnmarshalBinary(ns ipld.NodeStyle, b []byte) (Selector, error) {
+	if len(b) == 0 || b[0] != binaryVersion {
+		return nil, ErrDecode{"binary", fmt.Errorf("not a selector in a known binary format")}
+	}
+	r := &binaryReader{b: b[1:]}
+	nb := ns.NewBuilder()
+	if err := fluent.Recover(func() {
+		r.selector(fluent.WrapAssembler(nb))
+		if len(r.b) > 0 {
+			r.fail(fmt.Errorf("%d unexpected bytes after the selector", len(r.b)))
+		}
+	}); err != nil {
+		if e2, ok := err.(fluent.Error); ok {
+			err = e2.Err
+		}
+		return nil, ErrDecode{"binary", err}
+	}
+	return ParseEncoded(nb.Build())
+}
+
+func appendBinary(b []byte, s Selector) ([]byte, error) {
+	var err error
+	switch s2 := s.(type) {
+	case Matcher:
+		if s2.leaves {
+			return append(b, binaryTag_MatchLeaves), nil
+		}
+		var flags byte
+		if s2.Label != "" {
+			flags |= binaryMatcher_Label
+		}
+		if len(s2.Kinds) > 0 {
+			flags |= binaryMatcher_Kinds
+		}
+		if s2.Slice != nil {
+			flags |= binaryMatcher_Subset
+		}
+		b = append(b, binaryTag_Matcher, flags)
+		if s2.Label != "" {
+			b = appendString(b, s2.Label)
+		}
+		if len(s2.Kinds) > 0 {
+			b = appendKinds(b, s2.Kinds)
+		}
+		if s2.Slice != nil {
+			b = appendVarint(b, s2.Slice.From)
+			b = appendVarint(b, s2.Slice.To)
+		}
+		return b, nil
+	case ExploreAll:
+		return appendBinary(append(b, binaryTag_ExploreAll), s2.next)
+	case ExploreFields:
+		b = append(b, binaryTag_ExploreFields)
+		b = appendUvarint(b, len(s2.interests))
+		for _, ps := range s2.interests {
+			b = appendString(b, ps.String())
+			b = appendKinds(b, s2.kinds[ps.String()])
+			if b, err = appendBinary(b, s2.selections[ps.String()]); err != nil {
+				return nil, err
+			}
+		}
+		return b, nil
+	case ExploreIndex:
+		idx, _ := s2.interest[0].Index()
+		b = appendVarint(append(b, binaryTag_ExploreIndex), idx)
+		return appendBinary(b, s2.next)
+	case ExploreRange:
+		b = appendVarint(append(b, binaryTag_ExploreRange), s2.start)
+		b = appendVarint(b, s2.end)
+		return appendBinary(b, s2.next)
+	case ExploreUnion:
+		b = appendUvarint(append(b, binaryTag_ExploreUnion), len(s2.Members))
+		for _, m := range s2.Members {
+			if b, err = appendBinary(b, m); err != nil {
+				return nil, err
+			}
+		}
+		return b, nil
+	case ExploreRecursive:
+		// The depth is written as one more than it is, so that zero can mean there's no limit.
+		depth := 0
+		if s2.limit.Mode() == RecursionLimit_Depth {
+			depth = s2.limit.Depth() + 1
+		}
+		if !s2.partway() {
+			b = appendUvarint(append(b, binaryTag_ExploreRecursive), depth)
+			return appendBinary(b, s2.sequence)
+		}
+		b = appendUvarint(append(b, binaryTag_ExploreRecursivePartway), depth)
+		if b, err = appendBinary(b, s2.sequence); err != nil {
+			return nil, err
+		}
+		return appendBinary(b, s2.current)
+	case ExploreRecursiveEdge:
+		return append(b, binaryTag_ExploreRecursiveEdge), nil
+	default:
+		return nil, ErrNotEncodable{s}
+	}
+}
+
+func appendUvarint(b []byte, v int) []byte {
+	var buf [binary.MaxVarintLen64]byte
+	return append(b, buf[:binary.PutUvarint(buf[:], uint64(v))]...)
+}
+
+func appendVarint(b []byte, v int) []byte {
+	var buf [binary.MaxVarintLen64]byte
+	return append(b, buf[:binary.PutVarint(buf[:], int64(v))]...)
+}
+
+func appendString(b []byte, s string) []byte {
+	return append(appendUvarint(b, len(s)), s...)
+}
+
+func appendKinds(b []byte, ks ipld.ReprKindSet) []byte {
+	b = appendUvarint(b, len(ks))
+	for _, k := range ks {
+		b = append(b, byte(k))
+	}
+	return b
+}
+
+// binaryReader consumes the binary form of a selector, assembling its spec;
+// like the fluent assemblers it feeds, it panics with fluent.Error on failure.
+type binaryReader struct {
+	b []byte // what's left to read.
+}
+
+func (r *binaryReader) fail(err error) {
+	panic(fluent.Error{err})
+}
+
+func (r *binaryReader) byte() byte {
+	if len(r.b) == 0 {
+		r.fail(io.ErrUnexpectedEOF)
+	}
+	c := r.b[0]
+	r.b = r.b[1:]
+	return c
+}
+
+func (r *binaryReader) uvarint() int {
+	v, n := binary.Uvarint(r.b)
+	if n <= 0 || int(v) < 0 {
+		r.fail(fmt.Errorf("malformed uvarint"))
+	}
+	r.b = r.b[n:]
+	return int(v)
+}
+
+// count reads a uvarint which counts things still to be read, each at least one byte long,
+// so it can't be more than the number of bytes left.
+func (r *binaryReader) count() int {
+	v := r.uvarint()
+	if v > len(r.b) {
+		r.fail(io.ErrUnexpectedEOF)
+	}
+	return v
+}
+
+func (r *binaryReader) varint() int {
+	v, n := binary.Varint(r.b)
+	if n <= 0 {
+		r.fail(fmt.Errorf("malformed varint"))
+	}
+	r.b = r.b[n:]
+	return int(v)
+}
+
+func (r *binaryReader) string() string {
+	l := r.count()
+	s := string(r.b[:l])
+	r.b = r.b[l:]
+	return s
+}
+
+// kinds reads a list of kinds, returning nil if the count was zero.
+func (r *binaryReader) kinds() []string {
+	l := r.count()
+	if l == 0 {
+		return nil
+	}
+	names := make([]string, l)
+	for i := range names {
+		k := ipld.ReprKind(r.byte())
+		if !isKind(k) {
+			r.fail(fmt.Errorf("%#x is not a kind", byte(k)))
+		}
+		names[i] = k.String()
+	}
+	return names
+}
+
+func isKind(k ipld.ReprKind) bool {
+	return ipld.ReprKindSet_Recursive.Contains(k) || ipld.ReprKindSet_Scalar.Contains(k)
+}
+
+// selector reads a selector, assembling its spec as encode would.
+func (r *binaryReader) selector(na fluent.NodeAssembler) {
+	switch tag := r.byte(); tag {
+	case binaryTag_Matcher:
+		flags := r.byte()
+		if flags&^(binaryMatcher_Label|binaryMatcher_Kinds|binaryMatcher_Subset) != 0 {
+			r.fail(fmt.Errorf("unknown Matcher flags %#x", flags))
+		}
+		encodeMember(na, SelectorKey_Matcher, func(na fluent.MapAssembler) {
+			if flags&binaryMatcher_Label != 0 {
+				na.AssembleEntry(SelectorKey_Label).AssignString(r.string())
+			}
+			if flags&binaryMatcher_Kinds != 0 {
+				assignKinds(na.AssembleEntry(SelectorKey_Kinds), r.kinds())
+			}
+			if flags&binaryMatcher_Subset != 0 {
+				na.AssembleEntry(SelectorKey_Subset).CreateMap(2, func(na fluent.MapAssembler) {
+					na.AssembleEntry(SelectorKey_From).AssignInt(r.varint())
+					na.AssembleEntry(SelectorKey_To).AssignInt(r.varint())
+				})
+			}
+		})
+	case binaryTag_MatchLeaves:
+		encodeMember(na, SelectorKey_MatchLeaves, func(fluent.MapAssembler) {})
+	case binaryTag_ExploreAll:
+		encodeMember(na, SelectorKey_ExploreAll, func(na fluent.MapAssembler) {
+			r.selector(na.AssembleEntry(SelectorKey_Next))
+		})
+	case binaryTag_ExploreFields:
+		l := r.count()
+		names := make([]string, l)
+		kinds := make([][]string, l)
+		encodeMember(na, SelectorKey_ExploreFields, func(na fluent.MapAssembler) {
+			na.AssembleEntry(SelectorKey_Fields).CreateMap(l, func(na fluent.MapAssembler) {
+				for i := range names {
+					names[i] = r.string()
+					kinds[i] = r.kinds()
+					r.selector(na.AssembleEntry(names[i]))
+				}
+			})
+			var nKinds int
+			for _, ks := range kinds {
+				if ks != nil {
+					nKinds++
+				}
+			}
+			if nKinds > 0 {
+				na.AssembleEntry(SelectorKey_Kinds).CreateMap(nKinds, func(na fluent.MapAssembler) {
+					for i, ks := range kinds {
+						if ks != nil {
+							assignKinds(na.AssembleEntry(names[i]), ks)
+						}
+					}
+				})
+			}
+		})
+	case binaryTag_ExploreIndex:
+		encodeMember(na, SelectorKey_ExploreIndex, func(na fluent.MapAssembler) {
+			na.AssembleEntry(SelectorKey_Index).AssignInt(r.varint())
+			r.selector(na.AssembleEntry(SelectorKey_Next))
+		})
+	case binaryTag_ExploreRange:
+		encodeMember(na, SelectorKey_ExploreRange, func(na fluent.MapAssembler) {
+			na.AssembleEntry(SelectorKey_Start).AssignInt(r.varint())
+			na.AssembleEntry(SelectorKey_End).AssignInt(r.varint())
+			r.selector(na.AssembleEntry(SelectorKey_Next))
+		})
+	case binaryTag_ExploreUnion:
+		l := r.count()
+		na.CreateMap(1, func(na fluent.MapAssembler) {
+			na.AssembleEntry(SelectorKey_ExploreUnion).CreateList(l, func(na fluent.ListAssembler) {
+				for i := 0; i < l; i++ {
+					r.selector(na.AssembleValue())
+				}
+			})
+		})
+	case binaryTag_ExploreRecursive, binaryTag_ExploreRecursivePartway:
+		depth := r.uvarint()
+		encodeMember(na, SelectorKey_ExploreRecursive, func(na fluent.MapAssembler) {
+			na.AssembleEntry(SelectorKey_Limit).CreateMap(1, func(na fluent.MapAssembler) {
+				if depth > 0 {
+					na.AssembleEntry(SelectorKey_LimitDepth).AssignInt(depth - 1)
+				} else {
+					na.AssembleEntry(SelectorKey_LimitNone).CreateMap(0, func(na fluent.MapAssembler) {})
+				}
+			})
+			r.selector(na.AssembleEntry(SelectorKey_Sequence))
+			if tag == binaryTag_ExploreRecursivePartway {
+				r.selector(na.AssembleEntry(SelectorKey_Current))
+			}
+		})
+	case binaryTag_ExploreRecursiveEdge:
+		encodeMember(na, SelectorKey_ExploreRecursiveEdge, func(na fluent.MapAssembler) {})
+	default:
+		r.fail(fmt.Errorf("unknown selector tag %q", tag))
+	}
+}
+
+// assignKinds assembles a list of kind names, as parseKinds expects.
+func assignKinds(na fluent.NodeAssembler, names []string) {
+	na.CreateList(len(names), func(na fluent.ListAssembler) {
+		for _, name := range names {
+			na.AssembleValue().AssignString(name)
+		}
+	})
+}
@@ -0,0 +1,138 @@
+package selector
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	refmtjson "github.com/polydawn/refmt/json"
+	. "github.com/warpfork/go-wish"
+
+	ipld "github.com/ipld/go-ipld-prime"
+	"github.com/ipld/go-ipld-prime/codec/dagjson"
+	basicnode "github.com/ipld/go-ipld-prime/node/basic"
+)
+
+func TestBinary(t *testing.T) {
+	var binTotal, jsonTotal int
+	for _, tc := range []struct {
+		name string
+		spec string
+	}{
+		{"Matcher", `{".": {}}`},
+		{"Matcher with everything", `{".": {"label": "x", "k": ["String", "Bytes"], "subset": {"[": 2, "]": 500}}}`},
+		{"MatchLeaves", `{"..": {}}`},
+		{"ExploreAll", `{"a": {">": {".": {"label": "x"}}}}`},
+		{"ExploreFields", `{"f": {"f>": {"zed": {".": {}}, "alpha": {"a": {">": {".": {"k": ["Int", "String"]}}}}}}}`},
+		{"ExploreFields with kinds", `{"f": {"f>": {"zed": {".": {}}, "alpha": {".": {}}}, "k": {"alpha": ["Map", "List"]}}}`},
+		{"ExploreIndex", `{"i": {"i": 2, ">": {".": {}}}}`},
+		{"ExploreRange", `{"r": {"^": 1, "$": 3, ">": {".": {}}}}`},
+		{"ExploreUnion", `{"|": [{".": {}}, {"i": {"i": 0, ">": {".": {}}}}]}`},
+		{"ExploreRecursive", `{"R": {"l": {"depth": 3}, ":>": {"a": {">": {"@": {}}}}}}`},
+		{"ExploreRecursive to depth 0", `{"R": {"l": {"depth": 0}, ":>": {"a": {">": {"@": {}}}}}}`},
+		{"ExploreRecursive without limit", `{"R": {"l": {"none": {}}, ":>": {"|": [{".": {}}, {"a": {">": {"@": {}}}}]}}}`},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			s, err := ParseFromJSON(basicnode.Style__Any{}, []byte(tc.spec))
+			Require(t, err, ShouldEqual, nil)
+			b, err := MarshalBinary(s)
+			Require(t, err, ShouldEqual, nil)
+			s2, err := UnmarshalBinary(basicnode.Style__Any{}, b)
+			Require(t, err, ShouldEqual, nil)
+			Wish(t, s2.String(), ShouldEqual, s.String())
+			n, err := Encode(basicnode.Style__Any{}, s)
+			Require(t, err, ShouldEqual, nil)
+			n2, err := Encode(basicnode.Style__Any{}, s2)
+			Require(t, err, ShouldEqual, nil)
+			if !reflect.DeepEqual(n, n2) {
+				t.Errorf("spec after round trip differs:\n%s\n%s", tc.spec, ipld.Sprint(n2))
+			}
+			spec := basicnode.Style__Any{}.NewBuilder()
+			Require(t, dagjson.Decoder(spec, bytes.NewBufferString(tc.spec)), ShouldEqual, nil)
+			Wish(t, ipld.Sprint(n2), ShouldEqual, ipld.Sprint(spec.Build()))
+
+			// Compare with the spec as (compact) dag-json.
+			var buf bytes.Buffer
+			Require(t, dagjson.Marshal(n, refmtjson.NewEncoder(&buf, refmtjson.EncodeOptions{})), ShouldEqual, nil)
+			if len(b) >= buf.Len() {
+				t.Errorf("binary form is %d bytes; the dag-json is only %d", len(b), buf.Len())
+			}
+			binTotal += len(b)
+			jsonTotal += buf.Len()
+		})
+	}
+	t.Logf("all told, the binary forms are %d bytes, and the dag-json %d", binTotal, jsonTotal)
+	if binTotal*4 > jsonTotal {
+		t.Errorf("expected the binary forms to be at most a quarter the size of the dag-json")
+	}
+
+	t.Run("encoding within a traversal", func(t *testing.T) {
+		s, err := ParseFromJSON(basicnode.Style__Any{}, []byte(`{"R": {"l": {"depth": 3}, ":>": {"a": {">": {"@": {}}}}}}`))
+		Require(t, err, ShouldEqual, nil)
+		s = s.Explore(basicnode.NewInt(0), ipld.PathSegmentOfInt(0)) // (the node doesn't matter here.)
+		b, err := MarshalBinary(s)
+		Require(t, err, ShouldEqual, nil)
+		s2, err := UnmarshalBinary(basicnode.Style__Any{}, b)
+		Require(t, err, ShouldEqual, nil)
+		Wish(t, s2.String(), ShouldEqual, s.String())
+
+		// Partway through the sequence, the current selector goes along too.
+		s, err = ParseFromJSON(basicnode.Style__Any{}, []byte(`{"R": {"l": {"depth": 3}, ":>": {"f": {"f>": {"a": {"i": {"i": 0, ">": {"@": {}}}}}}}}}`))
+		Require(t, err, ShouldEqual, nil)
+		s = s.Explore(basicnode.NewInt(0), ipld.PathSegmentOfString("a"))
+		b, err = MarshalBinary(s)
+		Require(t, err, ShouldEqual, nil)
+		s2, err = UnmarshalBinary(basicnode.Style__Any{}, b)
+		Require(t, err, ShouldEqual, nil)
+		Wish(t, s2.String(), ShouldEqual, "ExploreRecursive(depth=3, ExploreFields{a: ExploreIndex(0 -> ExploreRecursiveEdge)} @ ExploreIndex(0 -> ExploreRecursiveEdge))")
+		Wish(t, s2, ShouldEqual, s)
+	})
+	t.Run("foreign selector type", func(t *testing.T) {
+		_, err := MarshalBinary(ExploreAll{notEncodable{}})
+		Wish(t, err, ShouldEqual, ErrNotEncodable{notEncodable{}})
+	})
+}
+
+func TestBinaryErrors(t *testing.T) {
+	s, err := ParseFromJSON(basicnode.Style__Any{}, []byte(`{"f": {"f>": {"zed": {".": {"label": "x"}}}, "k": {"zed": ["Map"]}}}`))
+	Require(t, err, ShouldEqual, nil)
+	b, err := MarshalBinary(s)
+	Require(t, err, ShouldEqual, nil)
+
+	t.Run("every truncation is an ErrDecode", func(t *testing.T) {
+		for i := 0; i < len(b); i++ {
+			_, err := UnmarshalBinary(basicnode.Style__Any{}, b[:i])
+			Wish(t, err, ShouldBeSameTypeAs, ErrDecode{})
+		}
+	})
+	for _, tc := range []struct {
+		name string
+		b    []byte
+		err  string
+	}{
+		{"unknown version", []byte{2, '@'}, "selector decode failed (binary): not a selector in a known binary format"},
+		{"trailing bytes", append(append([]byte{}, b...), 0, 0), "selector decode failed (binary): 2 unexpected bytes after the selector"},
+		{"unknown tag", []byte{1, 'z'}, `selector decode failed (binary): unknown selector tag 'z'`},
+		{"unknown flags", []byte{1, '.', 0x80}, "selector decode failed (binary): unknown Matcher flags 0x80"},
+		{"invalid kind", []byte{1, '.', 2, 1, 'q'}, "selector decode failed (binary): 0x71 is not a kind"},
+		{"overlong count", []byte{1, '|', 3, '@'}, "selector decode failed (binary): unexpected EOF"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := UnmarshalBinary(basicnode.Style__Any{}, tc.b)
+			Wish(t, err, ShouldBeSameTypeAs, ErrDecode{})
+			if err != nil {
+				Wish(t, err.Error(), ShouldEqual, tc.err)
+			}
+		})
+	}
+	t.Run("decodable but not a valid selector", func(t *testing.T) {
+		// A range which ends before it starts decodes fine, but doesn't parse.
+		_, err := UnmarshalBinary(basicnode.Style__Any{}, []byte{1, 'r', 6, 2, '.', 0})
+		if err == nil {
+			t.Fatalf("expected an error")
+		}
+		if _, ok := err.(ErrDecode); ok {
+			t.Errorf("expected a parse error, not %v", err)
+		}
+	})
+}
@@ -33,6 +33,11 @@ type SelectorSpecBuilder interface {
 	Matcher() SelectorSpec
 }
 
+// The less common selectors (ExplorePath, ExploreSegments, MatchLeaves, and MatchNull)
+// are functions which take a SelectorSpecBuilder, rather than methods of it,
+// so that adding them doesn't oblige other implementations of SelectorSpecBuilder to change.
+// They work with any implementation.
+
 // ExploreFieldsSpecBuildingClosure is a function that provided to SelectorSpecBuilder's
 // ExploreFields method that assembles the fields map in the selector using
 // an ExploreFieldsSpecBuilder
@@ -150,6 +155,56 @@ func (ssb *selectorSpecBuilder) ExploreFields(specBuilder ExploreFieldsSpecBuild
 	}
 }
 
+// ExplorePath builds a chain of selectors which follows a single path,
+// given in the same slash-separated form as ipld.ParsePath, and then applies next.
+// Each segment which is a non-negative integer becomes an ExploreIndex;
+// every other segment becomes an ExploreFields with just that one field.
+// (So a map key which happens to be all digits can't be reached this way;
+// use ExploreFields directly for that.)
+//
+// An empty path yields next itself.
+func ExplorePath(ssb SelectorSpecBuilder, path string, next SelectorSpec) SelectorSpec {
+	segs := ipld.ParsePath(path).Segments()
+	return explorePath(ssb, segs, func(i int) bool {
+		idx, err := segs[i].Index()
+		return err == nil && idx >= 0
+	}, next)
+}
+
+// ExploreSegments is like ExplorePath, but takes the path as a list of
+// strings and ints, as ipld.PathSegmentsFrom does: each int becomes an ExploreIndex,
+// and each string an ExploreFields.  (So unlike with ExplorePath,
+// a map key which is all digits is no problem.)
+// Values of any other type are an error.
+func ExploreSegments(ssb SelectorSpecBuilder, next SelectorSpec, segs ...interface{}) (SelectorSpec, error) {
+	ps, err := ipld.PathSegmentsFrom(segs...)
+	if err != nil {
+		return nil, err
+	}
+	return explorePath(ssb, ps, func(i int) bool {
+		_, ok := segs[i].(int)
+		return ok
+	}, next), nil
+}
+
+// explorePath builds the chain of selectors for ExplorePath and ExploreSegments,
+// which differ in how they decide which segments are list indexes.
+func explorePath(ssb SelectorSpecBuilder, segs []ipld.PathSegment, isIndex func(i int) bool, next SelectorSpec) SelectorSpec {
+	for i := len(segs) - 1; i >= 0; i-- {
+		seg := segs[i]
+		if isIndex(i) {
+			idx, _ := seg.Index()
+			next = ssb.ExploreIndex(idx, next)
+			continue
+		}
+		inner := next
+		next = ssb.ExploreFields(func(efsb ExploreFieldsSpecBuilder) {
+			efsb.Insert(seg.String(), inner)
+		})
+	}
+	return next
+}
+
 func (ssb *selectorSpecBuilder) Matcher() SelectorSpec {
 	return selectorSpec{
 		fluent.MustBuildMap(ssb.ns, 1, func(na fluent.MapAssembler) {
@@ -158,6 +213,39 @@ func (ssb *selectorSpecBuilder) Matcher() SelectorSpec {
 	}
 }
 
+// MatchLeaves builds a MatchLeaves selector, which matches only the scalars
+// (see selector.LeafKinds), and not the maps and lists containing them.
+func MatchLeaves(ssb SelectorSpecBuilder) SelectorSpec {
+	return selectorSpec{
+		fluent.MustBuildMap(specStyle(ssb), 1, func(na fluent.MapAssembler) {
+			na.AssembleEntry(selector.SelectorKey_MatchLeaves).CreateMap(0, func(na fluent.MapAssembler) {})
+		}),
+	}
+}
+
+// MatchNull builds a Matcher limited to the Null kind,
+// which matches values which are present and null (and not absent ones).
+func MatchNull(ssb SelectorSpecBuilder) SelectorSpec {
+	return selectorSpec{
+		fluent.MustBuildMap(specStyle(ssb), 1, func(na fluent.MapAssembler) {
+			na.AssembleEntry(selector.SelectorKey_Matcher).CreateMap(1, func(na fluent.MapAssembler) {
+				na.AssembleEntry(selector.SelectorKey_Kinds).CreateList(1, func(na fluent.ListAssembler) {
+					na.AssembleValue().AssignString(ipld.ReprKind_Null.String())
+				})
+			})
+		}),
+	}
+}
+
+// specStyle returns the NodeStyle which ssb builds specs with.
+// For other implementations of SelectorSpecBuilder, that's the style of their Matcher spec's node.
+func specStyle(ssb SelectorSpecBuilder) ipld.NodeStyle {
+	if ssb2, ok := ssb.(*selectorSpecBuilder); ok {
+		return ssb2.ns
+	}
+	return ssb.Matcher().Node().Style()
+}
+
 type exploreFieldsSpecBuilder struct {
 	na fluent.MapAssembler
 }
@@ -3,8 +3,10 @@ package builder
 import (
 	"testing"
 
+	ipld "github.com/ipld/go-ipld-prime"
 	"github.com/ipld/go-ipld-prime/fluent"
 	basicnode "github.com/ipld/go-ipld-prime/node/basic"
+	"github.com/ipld/go-ipld-prime/traversal"
 	"github.com/ipld/go-ipld-prime/traversal/selector"
 	. "github.com/warpfork/go-wish"
 )
@@ -19,6 +21,24 @@ func TestBuildingSelectors(t *testing.T) {
 		})
 		Wish(t, sn, ShouldEqual, esn)
 	})
+	t.Run("MatchLeaves builds MatchLeaves nodes", func(t *testing.T) {
+		sn := MatchLeaves(ssb).Node()
+		esn := fluent.MustBuildMap(ns, 1, func(na fluent.MapAssembler) {
+			na.AssembleEntry(selector.SelectorKey_MatchLeaves).CreateMap(0, func(na fluent.MapAssembler) {})
+		})
+		Wish(t, sn, ShouldEqual, esn)
+	})
+	t.Run("MatchNull builds matcher nodes limited to Null", func(t *testing.T) {
+		sn := MatchNull(ssb).Node()
+		esn := fluent.MustBuildMap(ns, 1, func(na fluent.MapAssembler) {
+			na.AssembleEntry(selector.SelectorKey_Matcher).CreateMap(1, func(na fluent.MapAssembler) {
+				na.AssembleEntry(selector.SelectorKey_Kinds).CreateList(1, func(na fluent.ListAssembler) {
+					na.AssembleValue().AssignString("Null")
+				})
+			})
+		})
+		Wish(t, sn, ShouldEqual, esn)
+	})
 	t.Run("ExploreRecursiveEdge builds ExploreRecursiveEdge nodes", func(t *testing.T) {
 		sn := ssb.ExploreRecursiveEdge().Node()
 		esn := fluent.MustBuildMap(ns, 1, func(na fluent.MapAssembler) {
@@ -128,4 +148,65 @@ func TestBuildingSelectors(t *testing.T) {
 		})
 		Wish(t, sn, ShouldEqual, esn)
 	})
+	t.Run("ExplorePath builds nested ExploreFields and ExploreIndex nodes", func(t *testing.T) {
+		sn := ExplorePath(ssb, "a/0", ssb.Matcher()).Node()
+		esn := ssb.ExploreFields(func(efsb ExploreFieldsSpecBuilder) {
+			efsb.Insert("a", ssb.ExploreIndex(0, ssb.Matcher()))
+		}).Node()
+		Wish(t, sn, ShouldEqual, esn)
+		Wish(t, ExplorePath(ssb, "", ssb.Matcher()).Node(), ShouldEqual, ssb.Matcher().Node())
+	})
+	t.Run("ExploreSegments builds nested ExploreFields and ExploreIndex nodes by the segments' types", func(t *testing.T) {
+		ss, err := ExploreSegments(ssb, ssb.Matcher(), "a", 3, "x")
+		Require(t, err, ShouldEqual, nil)
+		Wish(t, ss.Node(), ShouldEqual, ExplorePath(ssb, "a/3/x", ssb.Matcher()).Node())
+		ss, err = ExploreSegments(ssb, ssb.Matcher(), "3")
+		Require(t, err, ShouldEqual, nil)
+		esn := ssb.ExploreFields(func(efsb ExploreFieldsSpecBuilder) {
+			efsb.Insert("3", ssb.Matcher())
+		}).Node()
+		Wish(t, ss.Node(), ShouldEqual, esn)
+		_, err = ExploreSegments(ssb, ssb.Matcher(), "a", true)
+		Wish(t, err.Error(), ShouldEqual, "cannot make a PathSegment from value 1: bool is neither a string nor an int")
+	})
+	t.Run("the functions work with other implementations of SelectorSpecBuilder", func(t *testing.T) {
+		other := otherSpecBuilder{ssb}
+		Wish(t, MatchLeaves(other).Node(), ShouldEqual, MatchLeaves(ssb).Node())
+		Wish(t, MatchNull(other).Node(), ShouldEqual, MatchNull(ssb).Node())
+		Wish(t, ExplorePath(other, "a/0", other.Matcher()).Node(), ShouldEqual, ExplorePath(ssb, "a/0", ssb.Matcher()).Node())
+	})
+}
+
+// otherSpecBuilder stands in for an implementation of SelectorSpecBuilder from outside this package.
+type otherSpecBuilder struct {
+	SelectorSpecBuilder
+}
+
+func TestExplorePathWalk(t *testing.T) {
+	ns := basicnode.Style__Any{}
+	ssb := NewSelectorSpecBuilder(ns)
+	n := fluent.MustBuildMap(ns, 2, func(na fluent.MapAssembler) {
+		na.AssembleEntry("a").CreateList(2, func(na fluent.ListAssembler) {
+			na.AssembleValue().CreateMap(2, func(na fluent.MapAssembler) {
+				na.AssembleEntry("x").AssignInt(1)
+				na.AssembleEntry("y").AssignInt(2)
+			})
+			na.AssembleValue().CreateMap(1, func(na fluent.MapAssembler) {
+				na.AssembleEntry("x").AssignInt(3)
+			})
+		})
+		na.AssembleEntry("x").AssignInt(4)
+	})
+	s, err := ExplorePath(ssb, "a/0/x", ssb.Matcher()).Selector()
+	Require(t, err, ShouldEqual, nil)
+	var paths []string
+	var values []ipld.Node
+	err = traversal.WalkMatching(n, s, func(prog traversal.Progress, n ipld.Node) error {
+		paths = append(paths, prog.Path.String())
+		values = append(values, n)
+		return nil
+	})
+	Wish(t, err, ShouldEqual, nil)
+	Wish(t, paths, ShouldEqual, []string{"a/0/x"})
+	Wish(t, values, ShouldEqual, []ipld.Node{basicnode.NewInt(1)})
 }
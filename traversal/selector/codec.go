@@ -0,0 +1,49 @@
+package selector
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+
+	ipld "github.com/ipld/go-ipld-prime"
+	"github.com/ipld/go-ipld-prime/codec/dagcbor"
+	"github.com/ipld/go-ipld-prime/codec/dagjson"
+)
+
+// ErrDecode is returned by ParseFromJSON, ParseFromCBOR, and UnmarshalBinary
+// when the bytes couldn't be decoded into a Node at all.
+// Any other error from those functions means the bytes decoded fine,
+// but the data isn't a valid selector spec (the same errors as from ParseSelector).
+type ErrDecode struct {
+	Codec string // "dag-json", "dag-cbor", or "binary".
+	Err   error
+}
+
+func (e ErrDecode) Error() string {
+	return fmt.Sprintf("selector decode failed (%s): %s", e.Codec, e.Err)
+}
+
+// ParseFromJSON decodes a selector spec serialized as dag-json, then parses it
+// as with ParseSelector.
+//
+// The NodeStyle is used to hold the decoded spec before parsing;
+// any style which can hold maps, lists, strings, and ints will do
+// (typically, basicnode.Style__Any{}).
+// (This package can't pick one itself, because the basicnode package's tests depend on this one.)
+func ParseFromJSON(ns ipld.NodeStyle, b []byte) (Selector, error) {
+	return parseFrom("dag-json", dagjson.Decoder, ns, b)
+}
+
+// ParseFromCBOR decodes a selector spec serialized as dag-cbor, then parses it
+// as with ParseSelector.  See ParseFromJSON for more about the NodeStyle.
+func ParseFromCBOR(ns ipld.NodeStyle, b []byte) (Selector, error) {
+	return parseFrom("dag-cbor", dagcbor.Decoder, ns, b)
+}
+
+func parseFrom(codecName string, decoder func(ipld.NodeAssembler, io.Reader) error, ns ipld.NodeStyle, b []byte) (Selector, error) {
+	nb := ns.NewBuilder()
+	if err := decoder(nb, bytes.NewReader(b)); err != nil {
+		return nil, ErrDecode{codecName, err}
+	}
+	return ParseSelector(nb.Build())
+}
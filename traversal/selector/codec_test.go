@@ -0,0 +1,54 @@
+package selector
+
+import (
+	"bytes"
+	"testing"
+
+	. "github.com/warpfork/go-wish"
+
+	"github.com/ipld/go-ipld-prime/codec/dagcbor"
+	"github.com/ipld/go-ipld-prime/codec/dagjson"
+	basicnode "github.com/ipld/go-ipld-prime/node/basic"
+)
+
+func TestParseFromBytes(t *testing.T) {
+	// This is the "explore all Parents, recursively, to a depth of 5" example from the IPLD selector spec.
+	specJSON := `{
+		"R": {
+			"l": {"depth": 5},
+			":>": {
+				"a": {
+					">": {"@": {}}
+				}
+			}
+		}
+	}`
+	expect := "ExploreRecursive(depth=5, ExploreAll(ExploreRecursiveEdge))"
+	t.Run("json", func(t *testing.T) {
+		s, err := ParseFromJSON(basicnode.Style__Any{}, []byte(specJSON))
+		Require(t, err, ShouldEqual, nil)
+		Wish(t, s.String(), ShouldEqual, expect)
+	})
+	t.Run("cbor", func(t *testing.T) {
+		nb := basicnode.Style__Any{}.NewBuilder()
+		Require(t, dagjson.Decoder(nb, bytes.NewBufferString(specJSON)), ShouldEqual, nil)
+		var buf bytes.Buffer
+		Require(t, dagcbor.Encoder(nb.Build(), &buf), ShouldEqual, nil)
+		s, err := ParseFromCBOR(basicnode.Style__Any{}, buf.Bytes())
+		Require(t, err, ShouldEqual, nil)
+		Wish(t, s.String(), ShouldEqual, expect)
+	})
+	t.Run("decode errors are distinguished from spec errors", func(t *testing.T) {
+		_, err := ParseFromJSON(basicnode.Style__Any{}, []byte(`{"a": `))
+		Wish(t, err, ShouldBeSameTypeAs, ErrDecode{})
+		Wish(t, err.(ErrDecode).Codec, ShouldEqual, "dag-json")
+		_, err = ParseFromCBOR(basicnode.Style__Any{}, []byte{0xff})
+		Wish(t, err, ShouldBeSameTypeAs, ErrDecode{})
+		Wish(t, err.(ErrDecode).Codec, ShouldEqual, "dag-cbor")
+
+		_, err = ParseFromJSON(basicnode.Style__Any{}, []byte(`{"nope": {}}`))
+		_, isDecodeErr := err.(ErrDecode)
+		Wish(t, isDecodeErr, ShouldEqual, false)
+		Wish(t, err.Error(), ShouldEqual, `selector spec parse rejected: "nope" is not a known member of the selector union`)
+	})
+}
@@ -0,0 +1,154 @@
+package selector
+
+import (
+	"fmt"
+
+	ipld "github.com/ipld/go-ipld-prime"
+	"github.com/ipld/go-ipld-prime/fluent"
+)
+
+// ErrNotEncodable is returned by Encode for a Selector which isn't one of
+// the types defined in this package, and so has no spec form.
+type ErrNotEncodable struct {
+	Selector Selector
+}
+
+func (e ErrNotEncodable) Error() string {
+	return fmt.Sprintf("selector encode failed: %T is not a selector type with a spec form", e.Selector)
+}
+
+// Encode returns the selector spec Node for a Selector:
+// the map form which ParseEncoded will parse back to an equivalent Selector
+// (as will ParseSelector, unless the selector is partway through a recursion; see below).
+// It's for storing or transmitting a Selector which was built or parsed earlier.
+//
+// The NodeStyle is used to build the spec; as with ParseFromJSON,
+// any style which can hold maps, lists, strings, and ints will do.
+//
+// ExploreFields are encoded with their fields in the order they were given.
+// Selectors which are partway through a traversal (i.e. were returned
+// by Explore) encode the state they're in: for example,
+// an ExploreRecursive encodes its remaining depth limit.
+// An ExploreRecursive which is partway through its sequence also encodes
+// the selector it's at, under a "current" key beside the sequence.
+// That key isn't part of the selector spec, so ParseSelector refuses it, and only ParseEncoded reads it;
+// but it's left out whenever it isn't needed (including for anything ParseSelector returns).
+func Encode(ns ipld.NodeStyle, s Selector) (ipld.Node, error) {
+	nb := ns.NewBuilder()
+	if err := fluent.Recover(func() {
+		encode(fluent.WrapAssembler(nb), s)
+	}); err != nil {
+		return nil, err
+	}
+	return nb.Build(), nil
+}
+
+// ParseEncoded parses a selector Node made by Encode.
+// It's ParseSelector, but also accepts the state Encode writes for an ExploreRecursive
+// which is partway through its sequence: that isn't part of the selector spec,
+// so use ParseSelector for selectors from anywhere else.
+func ParseEncoded(n ipld.Node) (Selector, error) {
+	return ParseContext{encoded: true}.ParseSelector(n)
+}
+
+// encode assembles the spec for s, panicking with fluent.Error on failure.
+func encode(na fluent.NodeAssembler, s Selector) {
+	switch s2 := s.(type) {
+	case Matcher:
+		if s2.leaves {
+			encodeMember(na, SelectorKey_MatchLeaves, func(fluent.MapAssembler) {})
+			return
+		}
+		encodeMember(na, SelectorKey_Matcher, func(na fluent.MapAssembler) {
+			if s2.Label != "" {
+				na.AssembleEntry(SelectorKey_Label).AssignString(s2.Label)
+			}
+			if len(s2.Kinds) > 0 {
+				encodeKinds(na.AssembleEntry(SelectorKey_Kinds), s2.Kinds)
+			}
+			if s2.Slice != nil {
+				na.AssembleEntry(SelectorKey_Subset).CreateMap(2, func(na fluent.MapAssembler) {
+					na.AssembleEntry(SelectorKey_From).AssignInt(s2.Slice.From)
+					na.AssembleEntry(SelectorKey_To).AssignInt(s2.Slice.To)
+				})
+			}
+		})
+	case ExploreAll:
+		encodeMember(na, SelectorKey_ExploreAll, func(na fluent.MapAssembler) {
+			encode(na.AssembleEntry(SelectorKey_Next), s2.next)
+		})
+	case ExploreFields:
+		encodeMember(na, SelectorKey_ExploreFields, func(na fluent.MapAssembler) {
+			na.AssembleEntry(SelectorKey_Fields).CreateMap(len(s2.interests), func(na fluent.MapAssembler) {
+				for _, ps := range s2.interests {
+					encode(na.AssembleEntry(ps.String()), s2.selections[ps.String()])
+				}
+			})
+			if len(s2.kinds) > 0 {
+				na.AssembleEntry(SelectorKey_Kinds).CreateMap(len(s2.kinds), func(na fluent.MapAssembler) {
+					for _, ps := range s2.interests {
+						if ks := s2.kinds[ps.String()]; ks != nil {
+							encodeKinds(na.AssembleEntry(ps.String()), ks)
+						}
+					}
+				})
+			}
+		})
+	case ExploreIndex:
+		encodeMember(na, SelectorKey_ExploreIndex, func(na fluent.MapAssembler) {
+			idx, _ := s2.interest[0].Index()
+			na.AssembleEntry(SelectorKey_Index).AssignInt(idx)
+			encode(na.AssembleEntry(SelectorKey_Next), s2.next)
+		})
+	case ExploreRange:
+		encodeMember(na, SelectorKey_ExploreRange, func(na fluent.MapAssembler) {
+			na.AssembleEntry(SelectorKey_Start).AssignInt(s2.start)
+			na.AssembleEntry(SelectorKey_End).AssignInt(s2.end)
+			encode(na.AssembleEntry(SelectorKey_Next), s2.next)
+		})
+	case ExploreUnion:
+		na.CreateMap(1, func(na fluent.MapAssembler) {
+			na.AssembleEntry(SelectorKey_ExploreUnion).CreateList(len(s2.Members), func(na fluent.ListAssembler) {
+				for _, m := range s2.Members {
+					encode(na.AssembleValue(), m)
+				}
+			})
+		})
+	case ExploreRecursive:
+		encodeMember(na, SelectorKey_ExploreRecursive, func(na fluent.MapAssembler) {
+			na.AssembleEntry(SelectorKey_Limit).CreateMap(1, func(na fluent.MapAssembler) {
+				switch s2.limit.Mode() {
+				case RecursionLimit_Depth:
+					na.AssembleEntry(SelectorKey_LimitDepth).AssignInt(s2.limit.Depth())
+				default:
+					na.AssembleEntry(SelectorKey_LimitNone).CreateMap(0, func(na fluent.MapAssembler) {})
+				}
+			})
+			encode(na.AssembleEntry(SelectorKey_Sequence), s2.sequence)
+			if s2.partway() {
+				encode(na.AssembleEntry(SelectorKey_Current), s2.current)
+			}
+		})
+	case ExploreRecursiveEdge:
+		encodeMember(na, SelectorKey_ExploreRecursiveEdge, func(na fluent.MapAssembler) {})
+	default:
+		panic(fluent.Error{ErrNotEncodable{s}})
+	}
+}
+
+// encodeMember assembles a single-entry map, {key: {...body...}},
+// which is the shape of most members of the selector union.
+func encodeMember(na fluent.NodeAssembler, key string, body func(fluent.MapAssembler)) {
+	na.CreateMap(1, func(na fluent.MapAssembler) {
+		na.AssembleEntry(key).CreateMap(-1, body)
+	})
+}
+
+// encodeKinds assembles a list of kind names, as parseKinds expects.
+func encodeKinds(na fluent.NodeAssembler, ks ipld.ReprKindSet) {
+	na.CreateList(len(ks), func(na fluent.ListAssembler) {
+		for _, k := range ks {
+			na.AssembleValue().AssignString(k.String())
+		}
+	})
+}
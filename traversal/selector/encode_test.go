@@ -0,0 +1,78 @@
+package selector
+
+import (
+	"bytes"
+	"testing"
+
+	refmtjson "github.com/polydawn/refmt/json"
+	. "github.com/warpfork/go-wish"
+
+	ipld "github.com/ipld/go-ipld-prime"
+	"github.com/ipld/go-ipld-prime/codec/dagjson"
+	"github.com/ipld/go-ipld-prime/fluent"
+	basicnode "github.com/ipld/go-ipld-prime/node/basic"
+)
+
+func TestEncode(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		spec string
+	}{
+		{"ExploreIndex", `{"i": {"i": 2, ">": {".": {}}}}`},
+		{"ExploreAll", `{"a": {">": {".": {"label": "x"}}}}`},
+		{"Matcher with subset", `{".": {"subset": {"[": 2, "]": 5}}}`},
+		{"MatchLeaves", `{"a": {">": {"..": {}}}}`},
+		{"ExploreFields", `{"f": {"f>": {"zed": {".": {}}, "alpha": {"a": {">": {".": {"k": ["Int", "String"]}}}}}}}`},
+		{"ExploreFields with kinds", `{"f": {"f>": {"zed": {".": {}}, "alpha": {".": {}}}, "k": {"alpha": ["Map", "List"]}}}`},
+		{"ExploreRange", `{"r": {"^": 1, "$": 3, ">": {".": {}}}}`},
+		{"ExploreUnion", `{"|": [{".": {}}, {"i": {"i": 0, ">": {".": {}}}}]}`},
+		{"ExploreRecursive", `{"R": {"l": {"depth": 3}, ":>": {"a": {">": {"@": {}}}}}}`},
+		{"ExploreRecursive without limit", `{"R": {"l": {"none": {}}, ":>": {"|": [{".": {}}, {"a": {">": {"@": {}}}}]}}}`},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			nb := basicnode.Style__Any{}.NewBuilder()
+			Require(t, dagjson.Decoder(nb, bytes.NewBufferString(tc.spec)), ShouldEqual, nil)
+			n := nb.Build()
+			s, err := ParseSelector(n)
+			Require(t, err, ShouldEqual, nil)
+			n2, err := Encode(basicnode.Style__Any{}, s)
+			Wish(t, err, ShouldEqual, nil)
+			Wish(t, ipld.Sprint(n2), ShouldEqual, ipld.Sprint(n))
+			s2, err := ParseSelector(n2)
+			Wish(t, err, ShouldEqual, nil)
+			Wish(t, s2.String(), ShouldEqual, s.String())
+		})
+	}
+	t.Run("partway through a recursion", func(t *testing.T) {
+		s, err := ParseFromJSON(basicnode.Style__Any{}, []byte(`{"R": {"l": {"depth": 3}, ":>": {"f": {"f>": {"a": {"i": {"i": 0, ">": {"@": {}}}}}}}}}`))
+		Require(t, err, ShouldEqual, nil)
+		s = s.Explore(basicnode.NewInt(0), ipld.PathSegmentOfString("a")) // (the node doesn't matter here.)
+		n, err := Encode(basicnode.Style__Any{}, s)
+		Require(t, err, ShouldEqual, nil)
+		var buf bytes.Buffer
+		Require(t, dagjson.Marshal(n, refmtjson.NewEncoder(&buf, refmtjson.EncodeOptions{})), ShouldEqual, nil)
+		Wish(t, buf.String(), ShouldEqual, `{"R":{"l":{"depth":3},":>":{"f":{"f>":{"a":{"i":{"i":0,">":{"@":{}}}}}}},"current":{"i":{"i":0,">":{"@":{}}}}}}`)
+		_, err = ParseSelector(n)
+		Wish(t, err.Error(), ShouldEqual, `selector spec parse rejected: "current" is not a field of ExploreRecursive (Encode writes it, for a selector partway through a traversal; use ParseEncoded to read that back)`)
+		s2, err := ParseEncoded(n)
+		Require(t, err, ShouldEqual, nil)
+		Wish(t, s2, ShouldEqual, s)
+
+		// Carrying on from the round-tripped selector goes the same way as from the original.
+		list := fluent.MustBuildList(basicnode.Style__List{}, 0, func(na fluent.ListAssembler) {})
+		next := s.Explore(list, ipld.PathSegmentOfInt(0))
+		Wish(t, s2.Explore(list, ipld.PathSegmentOfInt(0)), ShouldEqual, next)
+		n, err = Encode(basicnode.Style__Any{}, next)
+		Require(t, err, ShouldEqual, nil)
+		rn, err := n.LookupString(SelectorKey_ExploreRecursive)
+		Require(t, err, ShouldEqual, nil)
+		_, err = rn.LookupString(SelectorKey_Current)
+		Wish(t, err, ShouldBeSameTypeAs, ipld.ErrNotExists{})
+	})
+	t.Run("foreign selector type", func(t *testing.T) {
+		_, err := Encode(basicnode.Style__Any{}, ExploreAll{notEncodable{}})
+		Wish(t, err.Error(), ShouldEqual, "selector encode failed: selector.notEncodable is not a selector type with a spec form")
+	})
+}
+
+type notEncodable struct{ Matcher }
@@ -27,6 +27,11 @@ func (s ExploreAll) Decide(n ipld.Node) bool {
 	return false
 }
 
+// String renders the selector compactly, e.g. "ExploreAll(Matcher)".
+func (s ExploreAll) String() string {
+	return fmt.Sprintf("ExploreAll(%v)", s.next)
+}
+
 // ParseExploreAll assembles a Selector from a ExploreAll selector node
 func (pc ParseContext) ParseExploreAll(n ipld.Node) (Selector, error) {
 	if n.ReprKind() != ipld.ReprKind_Map {
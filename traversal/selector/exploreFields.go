@@ -2,6 +2,7 @@ package selector
 
 import (
 	"fmt"
+	"strings"
 
 	ipld "github.com/ipld/go-ipld-prime"
 )
@@ -17,14 +18,33 @@ import (
 // ExploreFields also works for selecting specific elements out of a list;
 // if a "field" is a base-10 int, it will be coerced and do the right thing.
 // ExploreIndex or ExploreRange is more appropriate, however, and should be preferred.
+//
+// Fields which are named in the selector but absent in the data are skipped:
+// they select nothing, and that's not an error.
+// (That includes the absent optional fields of a struct, which are looked up as ipld.Undef;
+// but a field which is present with a null value isn't absent,
+// and the null node is explored like any other value.)
+// For validation, where an absent field means the data is the wrong shape,
+// set traversal.Config.StrictInterests, which makes the traversal halt
+// with a traversal.ErrSelectorMismatch instead.
+//
+// ExploreFields can also require fields to be of certain kinds, so that
+// a selector can double as a (very lightweight) shape check.
+// In the selector spec, this is an optional "k" entry beside "f>", mapping
+// field names to lists of kind names, e.g. `{"f>": {"a": ...}, "k": {"a": ["Int"]}}`.
+// A traversal which reaches a field whose value isn't one of the required kinds
+// halts with a traversal.ErrUnexpectedKind.
+// The check is on the value as it appears in the map, before any link is loaded.
 type ExploreFields struct {
 	selections map[string]Selector
-	interests  []ipld.PathSegment // keys of above; already boxed as that's the only way we consume them
+	interests  []ipld.PathSegment          // keys of above; already boxed as that's the only way we consume them
+	kinds      map[string]ipld.ReprKindSet // required kinds of field values, if any; nil if none were given
 }
 
-// Interests for ExploreFields are the fields listed in the selector node
+// Interests for ExploreFields are the fields listed in the selector node.
+// The slice is a copy, so the caller may modify it.
 func (s ExploreFields) Interests() []ipld.PathSegment {
-	return s.interests
+	return copyInterests(s.interests)
 }
 
 // Explore returns the selector for the given path if it is a field in
@@ -33,11 +53,37 @@ func (s ExploreFields) Explore(n ipld.Node, p ipld.PathSegment) Selector {
 	return s.selections[p.String()]
 }
 
+// ExpectedKinds returns the kinds the value of the given field is required to have,
+// or nil if the selector doesn't constrain it.
+func (s ExploreFields) ExpectedKinds(p ipld.PathSegment) ipld.ReprKindSet {
+	return s.kinds[p.String()]
+}
+
 // Decide always returns false because this is not a matcher
 func (s ExploreFields) Decide(n ipld.Node) bool {
 	return false
 }
 
+// String renders the selector compactly, e.g. "ExploreFields{a: Matcher, b: ExploreAll(Matcher)}".
+// Fields appear in the order they were given in the selector spec.
+// Fields with required kinds have them in parentheses, e.g. "ExploreFields{a (Int or String): Matcher}".
+func (s ExploreFields) String() string {
+	var sb strings.Builder
+	sb.WriteString("ExploreFields{")
+	for i, ps := range s.interests {
+		if i > 0 {
+			sb.WriteString(", ")
+		}
+		sb.WriteString(ps.String())
+		if ks := s.kinds[ps.String()]; ks != nil {
+			fmt.Fprintf(&sb, " (%s)", ks)
+		}
+		fmt.Fprintf(&sb, ": %v", s.selections[ps.String()])
+	}
+	sb.WriteString("}")
+	return sb.String()
+}
+
 // ParseExploreFields assembles a Selector
 // from a ExploreFields selector node
 func (pc ParseContext) ParseExploreFields(n ipld.Node) (Selector, error) {
@@ -52,8 +98,8 @@ func (pc ParseContext) ParseExploreFields(n ipld.Node) (Selector, error) {
 		return nil, fmt.Errorf("selector spec parse rejected: fields in ExploreFields selector must be a map")
 	}
 	x := ExploreFields{
-		make(map[string]Selector, fields.Length()),
-		make([]ipld.PathSegment, 0, fields.Length()),
+		selections: make(map[string]Selector, fields.Length()),
+		interests:  make([]ipld.PathSegment, 0, fields.Length()),
 	}
 	for itr := fields.MapIterator(); !itr.Done(); {
 		kn, v, err := itr.Next()
@@ -68,5 +114,27 @@ func (pc ParseContext) ParseExploreFields(n ipld.Node) (Selector, error) {
 			return nil, err
 		}
 	}
+	kinds, err := n.LookupString(SelectorKey_Kinds)
+	if err != nil {
+		return x, nil
+	}
+	if kinds.ReprKind() != ipld.ReprKind_Map {
+		return nil, fmt.Errorf("selector spec parse rejected: kinds in ExploreFields selector must be a map")
+	}
+	x.kinds = make(map[string]ipld.ReprKindSet, kinds.Length())
+	for itr := kinds.MapIterator(); !itr.Done(); {
+		kn, v, err := itr.Next()
+		if err != nil {
+			return nil, fmt.Errorf("error during selector spec parse: %s", err)
+		}
+		kstr, _ := kn.AsString()
+		if _, ok := x.selections[kstr]; !ok {
+			return nil, fmt.Errorf("selector spec parse rejected: kinds in ExploreFields selector given for %q, which is not in fields", kstr)
+		}
+		x.kinds[kstr], err = parseKinds(v)
+		if err != nil {
+			return nil, err
+		}
+	}
 	return x, nil
 }
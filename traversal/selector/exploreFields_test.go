@@ -48,6 +48,31 @@ func TestParseExploreFields(t *testing.T) {
 		})
 		s, err := ParseContext{}.ParseExploreFields(sn)
 		Wish(t, err, ShouldEqual, nil)
-		Wish(t, s, ShouldEqual, ExploreFields{map[string]Selector{"applesauce": Matcher{}}, []ipld.PathSegment{ipld.PathSegmentOfString("applesauce")}})
+		Wish(t, s, ShouldEqual, ExploreFields{selections: map[string]Selector{"applesauce": Matcher{}}, interests: []ipld.PathSegment{ipld.PathSegmentOfString("applesauce")}})
+	})
+}
+
+func TestParseExploreFieldsKinds(t *testing.T) {
+	parse := func(spec string) (Selector, error) {
+		return ParseFromJSON(basicnode.Style__Any{}, []byte(spec))
+	}
+	t.Run("kinds for a selected field should parse", func(t *testing.T) {
+		s, err := parse(`{"f": {"f>": {"a": {".": {}}, "b": {".": {}}}, "k": {"a": ["Int"]}}}`)
+		Wish(t, err, ShouldEqual, nil)
+		Wish(t, ExpectedKinds(s, ipld.PathSegmentOfString("a")), ShouldEqual, ipld.ReprKindSet_JustInt)
+		Wish(t, ExpectedKinds(s, ipld.PathSegmentOfString("b")), ShouldEqual, ipld.ReprKindSet(nil))
+		Wish(t, s.String(), ShouldEqual, "ExploreFields{a (Int): Matcher, b: Matcher}")
+	})
+	t.Run("kinds that are not a map should error", func(t *testing.T) {
+		_, err := parse(`{"f": {"f>": {"a": {".": {}}}, "k": ["Int"]}}`)
+		Wish(t, err, ShouldEqual, fmt.Errorf("selector spec parse rejected: kinds in ExploreFields selector must be a map"))
+	})
+	t.Run("kinds for a field that is not selected should error", func(t *testing.T) {
+		_, err := parse(`{"f": {"f>": {"a": {".": {}}}, "k": {"b": ["Int"]}}}`)
+		Wish(t, err, ShouldEqual, fmt.Errorf(`selector spec parse rejected: kinds in ExploreFields selector given for "b", which is not in fields`))
+	})
+	t.Run("kinds that are not kind names should error", func(t *testing.T) {
+		_, err := parse(`{"f": {"f>": {"a": {".": {}}}, "k": {"a": ["Integer"]}}}`)
+		Wish(t, err, ShouldEqual, fmt.Errorf(`selector spec parse rejected: "Integer" is not a kind`))
 	})
 }
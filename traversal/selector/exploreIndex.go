@@ -13,20 +13,23 @@ type ExploreIndex struct {
 	interest [1]ipld.PathSegment // index of element we're interested in
 }
 
-// Interests for ExploreIndex is just the index specified by the selector node
+// Interests for ExploreIndex is just the index specified by the selector node.
+// The slice is backed by the receiver, which is already a copy (this is a
+// value method), so the caller may modify it without affecting the selector;
+// the only cost per call is that single segment.
 func (s ExploreIndex) Interests() []ipld.PathSegment {
 	return s.interest[:]
 }
 
 // Explore returns the node's selector if
-// the path matches the index the index for this selector or nil if not
+// the path matches the index the index for this selector or nil if not.
+// Matching is per PathSegment.Equals, so a non-canonical string form
+// of the index (e.g. "03" for 3) does not match.
 func (s ExploreIndex) Explore(n ipld.Node, p ipld.PathSegment) Selector {
 	if n.ReprKind() != ipld.ReprKind_List {
 		return nil
 	}
-	expectedIndex, expectedErr := p.Index()
-	actualIndex, actualErr := s.interest[0].Index()
-	if expectedErr != nil || actualErr != nil || expectedIndex != actualIndex {
+	if !p.Equals(s.interest[0]) {
 		return nil
 	}
 	return s.next
@@ -37,6 +40,11 @@ func (s ExploreIndex) Decide(n ipld.Node) bool {
 	return false
 }
 
+// String renders the selector compactly, e.g. "ExploreIndex(3 -> Matcher)".
+func (s ExploreIndex) String() string {
+	return fmt.Sprintf("ExploreIndex(%s -> %v)", s.interest[0].String(), s.next)
+}
+
 // ParseExploreIndex assembles a Selector
 // from a ExploreIndex selector node
 func (pc ParseContext) ParseExploreIndex(n ipld.Node) (Selector, error) {
@@ -89,4 +89,9 @@ func TestExploreIndexExplore(t *testing.T) {
 		returnedSelector := s.Explore(n, ipld.PathSegmentOfInt(3))
 		Wish(t, returnedSelector, ShouldEqual, Matcher{})
 	})
+	t.Run("exploring should match the index in string form, but only the canonical one", func(t *testing.T) {
+		Wish(t, s.Explore(n, ipld.PathSegmentOfString("3")), ShouldEqual, Matcher{})
+		Wish(t, s.Explore(n, ipld.PathSegmentOfString("03")), ShouldEqual, nil)
+		Wish(t, s.Explore(n, ipld.PathSegmentOfString("+3")), ShouldEqual, nil)
+	})
 }
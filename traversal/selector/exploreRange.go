@@ -15,19 +15,21 @@ type ExploreRange struct {
 	interest []ipld.PathSegment // index of element we're interested in
 }
 
-// Interests for ExploreRange are all path segments within the iteration range
+// Interests for ExploreRange are all path segments within the iteration range.
+// The slice is a copy, so the caller may modify it.
 func (s ExploreRange) Interests() []ipld.PathSegment {
-	return s.interest
+	return copyInterests(s.interest)
 }
 
 // Explore returns the node's selector if
-// the path matches an index in the range of this selector
+// the path matches an index in the range of this selector.
+// As with ExploreIndex, the path must be the canonical form of the index.
 func (s ExploreRange) Explore(n ipld.Node, p ipld.PathSegment) Selector {
 	if n.ReprKind() != ipld.ReprKind_List {
 		return nil
 	}
 	index, err := p.Index()
-	if err != nil {
+	if err != nil || !p.Equals(ipld.PathSegmentOfInt(index)) {
 		return nil
 	}
 	if index < s.start || index >= s.end {
@@ -41,6 +43,12 @@ func (s ExploreRange) Decide(n ipld.Node) bool {
 	return false
 }
 
+// String renders the selector compactly, e.g. "ExploreRange(2:5 -> Matcher)".
+// As with slice expressions, the start is inclusive and the end is exclusive.
+func (s ExploreRange) String() string {
+	return fmt.Sprintf("ExploreRange(%d:%d -> %v)", s.start, s.end, s.next)
+}
+
 // ParseExploreRange assembles a Selector
 // from a ExploreRange selector node
 func (pc ParseContext) ParseExploreRange(n ipld.Node) (Selector, error) {
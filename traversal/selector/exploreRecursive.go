@@ -2,6 +2,7 @@ package selector
 
 import (
 	"fmt"
+	"reflect"
 
 	ipld "github.com/ipld/go-ipld-prime"
 )
@@ -85,6 +86,10 @@ func (s ExploreRecursive) Interests() []ipld.PathSegment {
 }
 
 // Explore returns the node's selector for all fields
+//
+// The recursion state (the sequence to restart, and the remaining depth)
+// travels in the returned ExploreRecursive value, so recursing a level costs
+// one new ExploreRecursive wrapper: the sequence itself is shared, never copied.
 func (s ExploreRecursive) Explore(n ipld.Node, p ipld.PathSegment) Selector {
 	nextSelector := s.current.Explore(n, p)
 	limit := s.limit
@@ -92,61 +97,68 @@ func (s ExploreRecursive) Explore(n ipld.Node, p ipld.PathSegment) Selector {
 	if nextSelector == nil {
 		return nil
 	}
-	if !s.hasRecursiveEdge(nextSelector) {
-		return ExploreRecursive{s.sequence, nextSelector, limit}
-	}
 	switch limit.mode {
 	case RecursionLimit_Depth:
 		if limit.depth < 2 {
-			return s.replaceRecursiveEdge(nextSelector, nil)
+			next, found := substituteRecursiveEdge(nextSelector, nil)
+			if !found {
+				return ExploreRecursive{s.sequence, nextSelector, limit}
+			}
+			return next
+		}
+		next, found := substituteRecursiveEdge(nextSelector, s.sequence)
+		if !found {
+			return ExploreRecursive{s.sequence, nextSelector, limit}
 		}
-		return ExploreRecursive{s.sequence, s.replaceRecursiveEdge(nextSelector, s.sequence), RecursionLimit{RecursionLimit_Depth, limit.depth - 1}}
+		return ExploreRecursive{s.sequence, next, RecursionLimit{RecursionLimit_Depth, limit.depth - 1}}
 	case RecursionLimit_None:
-		return ExploreRecursive{s.sequence, s.replaceRecursiveEdge(nextSelector, s.sequence), limit}
+		next, _ := substituteRecursiveEdge(nextSelector, s.sequence)
+		return ExploreRecursive{s.sequence, next, limit}
 	default:
 		panic("Unsupported recursion limit type")
 	}
 }
 
-func (s ExploreRecursive) hasRecursiveEdge(nextSelector Selector) bool {
-	_, isRecursiveEdge := nextSelector.(ExploreRecursiveEdge)
-	if isRecursiveEdge {
-		return true
-	}
-	exploreUnion, isUnion := nextSelector.(ExploreUnion)
-	if isUnion {
-		for _, selector := range exploreUnion.Members {
-			if s.hasRecursiveEdge(selector) {
-				return true
+// substituteRecursiveEdge replaces any ExploreRecursiveEdge in nextSelector
+// (either the selector itself, or members of an ExploreUnion) with the replacement,
+// and reports whether there were any.
+// If there were none, nextSelector is returned as-is, without allocating.
+// A nil replacement drops the edge; a union left with one member is unwrapped,
+// and one left with none becomes nil.
+func substituteRecursiveEdge(nextSelector Selector, replacement Selector) (Selector, bool) {
+	switch sel := nextSelector.(type) {
+	case ExploreRecursiveEdge:
+		return replacement, true
+	case ExploreUnion:
+		var replacementMembers []Selector // allocated only once we know an edge is present.
+		for i, member := range sel.Members {
+			newSelector, found := substituteRecursiveEdge(member, replacement)
+			if found && replacementMembers == nil {
+				replacementMembers = make([]Selector, 0, len(sel.Members))
+				replacementMembers = append(replacementMembers, sel.Members[:i]...)
 			}
-		}
-	}
-	return false
-}
-
-func (s ExploreRecursive) replaceRecursiveEdge(nextSelector Selector, replacement Selector) Selector {
-	_, isRecursiveEdge := nextSelector.(ExploreRecursiveEdge)
-	if isRecursiveEdge {
-		return replacement
-	}
-	exploreUnion, isUnion := nextSelector.(ExploreUnion)
-	if isUnion {
-		replacementMembers := make([]Selector, 0, len(exploreUnion.Members))
-		for _, selector := range exploreUnion.Members {
-			newSelector := s.replaceRecursiveEdge(selector, replacement)
-			if newSelector != nil {
+			if replacementMembers != nil && newSelector != nil {
 				replacementMembers = append(replacementMembers, newSelector)
 			}
 		}
-		if len(replacementMembers) == 0 {
-			return nil
-		}
-		if len(replacementMembers) == 1 {
-			return replacementMembers[0]
+		switch {
+		case replacementMembers == nil:
+			return nextSelector, false
+		case len(replacementMembers) == 0:
+			return nil, true
+		case len(replacementMembers) == 1:
+			return replacementMembers[0], true
+		default:
+			return ExploreUnion{replacementMembers}, true
 		}
-		return ExploreUnion{replacementMembers}
+	default:
+		return nextSelector, false
 	}
-	return nextSelector
+}
+
+// ExpectedKinds defers to the current selector, same as Explore.
+func (s ExploreRecursive) ExpectedKinds(p ipld.PathSegment) ipld.ReprKindSet {
+	return ExpectedKinds(s.current, p)
 }
 
 // Decide always returns false because this is not a matcher
@@ -154,6 +166,32 @@ func (s ExploreRecursive) Decide(n ipld.Node) bool {
 	return s.current.Decide(n)
 }
 
+// DecideAt defers to the current selector, same as Decide.
+func (s ExploreRecursive) DecideAt(n ipld.Node, p ipld.Path) bool {
+	return DecideAt(s.current, n, p)
+}
+
+// String renders the selector compactly, e.g. "ExploreRecursive(depth=3, ExploreAll(ExploreRecursiveEdge))".
+// When the selector is partway through its sequence,
+// the current position is appended, e.g. "ExploreRecursive(depth=3, ... @ ...)".
+func (s ExploreRecursive) String() string {
+	seq := fmt.Sprintf("%v", s.sequence)
+	cur := fmt.Sprintf("%v", s.current)
+	var limit string
+	if s.limit.mode == RecursionLimit_Depth {
+		limit = fmt.Sprintf("depth=%d, ", s.limit.depth)
+	}
+	if cur == seq {
+		return fmt.Sprintf("ExploreRecursive(%s%s)", limit, seq)
+	}
+	return fmt.Sprintf("ExploreRecursive(%s%s @ %s)", limit, seq, cur)
+}
+
+// DecideLabels defers to the current selector, same as Decide.
+func (s ExploreRecursive) DecideLabels(n ipld.Node) []string {
+	return DecideLabels(s.current, n)
+}
+
 type exploreRecursiveContext struct {
 	edgesFound int
 }
@@ -192,7 +230,22 @@ func (pc ParseContext) ParseExploreRecursive(n ipld.Node) (Selector, error) {
 	if erc.edgesFound == 0 {
 		return nil, fmt.Errorf("selector spec parse rejected: ExploreRecursive must have at least one ExploreRecursiveEdge")
 	}
-	return ExploreRecursive{selector, selector, limit}, nil
+	current := selector
+	if currentNode, err := n.LookupString(SelectorKey_Current); err == nil {
+		if !pc.encoded {
+			return nil, fmt.Errorf("selector spec parse rejected: %q is not a field of ExploreRecursive (Encode writes it, for a selector partway through a traversal; use ParseEncoded to read that back)", SelectorKey_Current)
+		}
+		if current, err = pc.PushParent(erc).ParseSelector(currentNode); err != nil {
+			return nil, err
+		}
+	}
+	return ExploreRecursive{selector, current, limit}, nil
+}
+
+// partway reports whether the selector is partway through its sequence
+// (i.e. was returned by Explore, and has a current selector other than the sequence itself).
+func (s ExploreRecursive) partway() bool {
+	return !reflect.DeepEqual(s.current, s.sequence)
 }
 
 func parseLimit(n ipld.Node) (RecursionLimit, error) {
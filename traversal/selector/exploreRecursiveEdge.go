@@ -31,6 +31,11 @@ func (s ExploreRecursiveEdge) Decide(n ipld.Node) bool {
 	panic("Traversed Explore Recursive Edge Node With No Parent")
 }
 
+// String renders the selector as "ExploreRecursiveEdge".
+func (s ExploreRecursiveEdge) String() string {
+	return "ExploreRecursiveEdge"
+}
+
 // ParseExploreRecursiveEdge assembles a Selector
 // from a exploreRecursiveEdge selector node
 func (pc ParseContext) ParseExploreRecursiveEdge(n ipld.Node) (Selector, error) {
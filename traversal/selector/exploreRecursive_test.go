@@ -110,6 +110,13 @@ func TestParseExploreRecursive(t *testing.T) {
 		_, err := ParseContext{}.ParseExploreRecursive(sn)
 		Wish(t, err, ShouldEqual, fmt.Errorf("selector spec parse rejected: ExploreRecursive must have at least one ExploreRecursiveEdge"))
 	})
+	t.Run("an edge belongs only to its nearest ExploreRecursive", func(t *testing.T) {
+		// The outer recursion's only edge is inside the inner one, so the outer has none.
+		_, err := ParseFromJSON(basicnode.Style__Any{}, []byte(`{"R": {"l": {"none": {}}, ":>": {"R": {"l": {"none": {}}, ":>": {"a": {">": {"@": {}}}}}}}}`))
+		Wish(t, err, ShouldEqual, fmt.Errorf("selector spec parse rejected: ExploreRecursive must have at least one ExploreRecursiveEdge"))
+		_, err = ParseFromJSON(basicnode.Style__Any{}, []byte(`{"R": {"l": {"none": {}}, ":>": {"|": [{"@": {}}, {"R": {"l": {"none": {}}, ":>": {"a": {">": {"@": {}}}}}}]}}}`))
+		Wish(t, err, ShouldEqual, nil)
+	})
 	t.Run("parsing map node that is ExploreRecursiveEdge without ExploreRecursive parent should not parse", func(t *testing.T) {
 		sn := fluent.MustBuildMap(basicnode.Style__Map{}, 0, func(na fluent.MapAssembler) {})
 		_, err := ParseContext{}.ParseExploreRecursiveEdge(sn)
@@ -180,7 +187,7 @@ func TestExploreRecursiveExplore(t *testing.T) {
 	var rs Selector
 	t.Run("exploring should traverse until we get to maxDepth", func(t *testing.T) {
 		parentsSelector := ExploreAll{recursiveEdge}
-		subTree := ExploreFields{map[string]Selector{"Parents": parentsSelector}, []ipld.PathSegment{ipld.PathSegmentOfString("Parents")}}
+		subTree := ExploreFields{selections: map[string]Selector{"Parents": parentsSelector}, interests: []ipld.PathSegment{ipld.PathSegmentOfString("Parents")}}
 		rs = ExploreRecursive{subTree, subTree, RecursionLimit{RecursionLimit_Depth, maxDepth}}
 		nodeString := `{
 			"Parents": [
@@ -231,7 +238,7 @@ func TestExploreRecursiveExplore(t *testing.T) {
 
 	t.Run("exploring should traverse indefinitely if no depth specified", func(t *testing.T) {
 		parentsSelector := ExploreAll{recursiveEdge}
-		subTree := ExploreFields{map[string]Selector{"Parents": parentsSelector}, []ipld.PathSegment{ipld.PathSegmentOfString("Parents")}}
+		subTree := ExploreFields{selections: map[string]Selector{"Parents": parentsSelector}, interests: []ipld.PathSegment{ipld.PathSegmentOfString("Parents")}}
 		rs = ExploreRecursive{subTree, subTree, RecursionLimit{RecursionLimit_None, 0}}
 		nodeString := `{
 			"Parents": [
@@ -285,7 +292,7 @@ func TestExploreRecursiveExplore(t *testing.T) {
 
 	t.Run("exploring should continue till we get to selector that returns nil on explore", func(t *testing.T) {
 		parentsSelector := ExploreIndex{recursiveEdge, [1]ipld.PathSegment{ipld.PathSegmentOfInt(1)}}
-		subTree := ExploreFields{map[string]Selector{"Parents": parentsSelector}, []ipld.PathSegment{ipld.PathSegmentOfString("Parents")}}
+		subTree := ExploreFields{selections: map[string]Selector{"Parents": parentsSelector}, interests: []ipld.PathSegment{ipld.PathSegmentOfString("Parents")}}
 		rs = ExploreRecursive{subTree, subTree, RecursionLimit{RecursionLimit_Depth, maxDepth}}
 		nodeString := `{
 			"Parents": {
@@ -306,10 +313,10 @@ func TestExploreRecursiveExplore(t *testing.T) {
 	t.Run("exploring should work when there is nested recursion", func(t *testing.T) {
 		parentsSelector := ExploreAll{recursiveEdge}
 		sideSelector := ExploreAll{recursiveEdge}
-		subTree := ExploreFields{map[string]Selector{
+		subTree := ExploreFields{selections: map[string]Selector{
 			"Parents": parentsSelector,
 			"Side":    ExploreRecursive{sideSelector, sideSelector, RecursionLimit{RecursionLimit_Depth, maxDepth}},
-		}, []ipld.PathSegment{
+		}, interests: []ipld.PathSegment{
 			ipld.PathSegmentOfString("Parents"),
 			ipld.PathSegmentOfString("Side"),
 		},
@@ -404,7 +411,7 @@ func TestExploreRecursiveExplore(t *testing.T) {
 	})
 	t.Run("exploring should work with explore union and recursion", func(t *testing.T) {
 		parentsSelector := ExploreUnion{[]Selector{ExploreAll{Matcher{}}, ExploreIndex{recursiveEdge, [1]ipld.PathSegment{ipld.PathSegmentOfInt(0)}}}}
-		subTree := ExploreFields{map[string]Selector{"Parents": parentsSelector}, []ipld.PathSegment{ipld.PathSegmentOfString("Parents")}}
+		subTree := ExploreFields{selections: map[string]Selector{"Parents": parentsSelector}, interests: []ipld.PathSegment{ipld.PathSegmentOfString("Parents")}}
 		rs = ExploreRecursive{subTree, subTree, RecursionLimit{RecursionLimit_Depth, maxDepth}}
 		nodeString := `{
 			"Parents": [
@@ -445,3 +452,165 @@ func TestExploreRecursiveExplore(t *testing.T) {
 		Wish(t, err, ShouldEqual, nil)
 	})
 }
+
+func TestSubstituteRecursiveEdge(t *testing.T) {
+	seq := ExploreAll{ExploreRecursiveEdge{}}
+	t.Run("bare edge", func(t *testing.T) {
+		s, found := substituteRecursiveEdge(ExploreRecursiveEdge{}, seq)
+		Wish(t, found, ShouldEqual, true)
+		Wish(t, s, ShouldEqual, seq)
+	})
+	t.Run("union keeps member order", func(t *testing.T) {
+		u := ExploreUnion{[]Selector{Matcher{}, ExploreRecursiveEdge{}, ExploreIndex{Matcher{}, [1]ipld.PathSegment{ipld.PathSegmentOfInt(1)}}}}
+		s, found := substituteRecursiveEdge(u, seq)
+		Wish(t, found, ShouldEqual, true)
+		Wish(t, s, ShouldEqual, ExploreUnion{[]Selector{Matcher{}, seq, ExploreIndex{Matcher{}, [1]ipld.PathSegment{ipld.PathSegmentOfInt(1)}}}})
+	})
+	t.Run("union without edges is returned as-is", func(t *testing.T) {
+		u := ExploreUnion{[]Selector{Matcher{}, seq}}
+		s, found := substituteRecursiveEdge(u, nil)
+		Wish(t, found, ShouldEqual, false)
+		Wish(t, s, ShouldEqual, u)
+	})
+	t.Run("nil replacement drops edges and unwraps", func(t *testing.T) {
+		s, found := substituteRecursiveEdge(ExploreUnion{[]Selector{Matcher{}, ExploreRecursiveEdge{}}}, nil)
+		Wish(t, found, ShouldEqual, true)
+		Wish(t, s, ShouldEqual, Matcher{})
+		s, found = substituteRecursiveEdge(ExploreUnion{[]Selector{ExploreRecursiveEdge{}, ExploreRecursiveEdge{}}}, nil)
+		Wish(t, found, ShouldEqual, true)
+		Wish(t, s, ShouldEqual, nil)
+	})
+}
+
+// BenchmarkExploreRecursiveDeep explores 1000 levels of nested lists,
+// the way a walk would: one Explore call per level.
+// Allocations per op are the interesting number: recursion state is carried
+// in the ExploreRecursive value, so a level should cost about one small alloc
+// (plus whatever the sequence itself costs), never a copy of the sequence.
+//
+// Each case runs twice: "current" is ExploreRecursive as it is,
+// and "naive" is naiveExploreRecursive, the straightforward approach it replaced,
+// for comparison.
+func BenchmarkExploreRecursiveDeep(b *testing.B) {
+	const depth = 1000
+	nodes := make([]ipld.Node, depth+1)
+	nodes[depth] = fluent.MustBuildList(basicnode.Style__List{}, 0, func(na fluent.ListAssembler) {})
+	for i := depth - 1; i >= 0; i-- {
+		child := nodes[i+1]
+		nodes[i] = fluent.MustBuildList(basicnode.Style__List{}, 1, func(na fluent.ListAssembler) {
+			na.AssembleValue().AssignNode(child)
+		})
+	}
+	for _, tc := range []struct {
+		name     string
+		sequence Selector
+	}{
+		{"edge", ExploreAll{ExploreRecursiveEdge{}}},
+		{"union", ExploreUnion{[]Selector{Matcher{}, ExploreAll{ExploreRecursiveEdge{}}}}},
+	} {
+		for _, impl := range []struct {
+			name string
+			new  func() Selector
+		}{
+			{"current", func() Selector { return ExploreRecursive{tc.sequence, tc.sequence, RecursionLimitDepth(depth)} }},
+			{"naive", func() Selector { return naiveExploreRecursive{tc.sequence, tc.sequence, depth} }},
+		} {
+			b.Run(tc.name+"/"+impl.name, func(b *testing.B) {
+				b.ReportAllocs()
+				for i := 0; i < b.N; i++ {
+					s := impl.new()
+					for lvl := 0; s != nil; lvl++ {
+						s = s.Explore(nodes[lvl], ipld.PathSegmentOfInt(0))
+					}
+				}
+			})
+		}
+	}
+}
+
+// naiveExploreRecursive is the straightforward way to explore recursively,
+// kept only as a baseline for BenchmarkExploreRecursiveDeep:
+// each level searches the next selector for an edge, and then walks it again
+// to substitute, rebuilding every union along the way whether or not it held an edge;
+// and exploring a union always collects its members' results in a new slice.
+type naiveExploreRecursive struct {
+	sequence Selector
+	current  Selector
+	depth    int
+}
+
+func (s naiveExploreRecursive) Interests() []ipld.PathSegment { return s.current.Interests() }
+func (s naiveExploreRecursive) Decide(n ipld.Node) bool       { return s.current.Decide(n) }
+func (s naiveExploreRecursive) String() string                { return "naive" }
+func (s naiveExploreRecursive) Explore(n ipld.Node, p ipld.PathSegment) Selector {
+	next := naiveExplore(s.current, n, p)
+	if next == nil {
+		return nil
+	}
+	if !naiveHasEdge(next) {
+		return naiveExploreRecursive{s.sequence, next, s.depth}
+	}
+	if s.depth < 2 {
+		return naiveReplaceEdge(next, nil)
+	}
+	return naiveExploreRecursive{s.sequence, naiveReplaceEdge(next, s.sequence), s.depth - 1}
+}
+
+func naiveExplore(s Selector, n ipld.Node, p ipld.PathSegment) Selector {
+	u, ok := s.(ExploreUnion)
+	if !ok {
+		return s.Explore(n, p)
+	}
+	results := make([]Selector, 0, len(u.Members))
+	for _, member := range u.Members {
+		if r := naiveExplore(member, n, p); r != nil {
+			results = append(results, r)
+		}
+	}
+	switch len(results) {
+	case 0:
+		return nil
+	case 1:
+		return results[0]
+	default:
+		return ExploreUnion{results}
+	}
+}
+
+func naiveHasEdge(s Selector) bool {
+	switch sel := s.(type) {
+	case ExploreRecursiveEdge:
+		return true
+	case ExploreUnion:
+		for _, member := range sel.Members {
+			if naiveHasEdge(member) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func naiveReplaceEdge(s Selector, replacement Selector) Selector {
+	switch sel := s.(type) {
+	case ExploreRecursiveEdge:
+		return replacement
+	case ExploreUnion:
+		members := make([]Selector, 0, len(sel.Members))
+		for _, member := range sel.Members {
+			if m := naiveReplaceEdge(member, replacement); m != nil {
+				members = append(members, m)
+			}
+		}
+		switch len(members) {
+		case 0:
+			return nil
+		case 1:
+			return members[0]
+		default:
+			return ExploreUnion{members}
+		}
+	default:
+		return s
+	}
+}
@@ -2,6 +2,7 @@ package selector
 
 import (
 	"fmt"
+	"strings"
 
 	ipld "github.com/ipld/go-ipld-prime"
 )
@@ -43,21 +44,28 @@ func (s ExploreUnion) Interests() []ipld.PathSegment {
 // - if exactly one member returns a selector, that selector
 // - nil if no members return a selector
 func (s ExploreUnion) Explore(n ipld.Node, p ipld.PathSegment) Selector {
-	// TODO: memory efficient?
-	nonNilResults := make([]Selector, 0, len(s.Members))
+	// The common case is that only one member continues (e.g. a Matcher
+	//  alongside an explore), so hold off allocating until we see a second.
+	var first Selector
+	var nonNilResults []Selector
 	for _, member := range s.Members {
 		resultSelector := member.Explore(n, p)
-		if resultSelector != nil {
+		switch {
+		case resultSelector == nil:
+			// drop it.
+		case first == nil:
+			first = resultSelector
+		case nonNilResults == nil:
+			nonNilResults = make([]Selector, 0, len(s.Members))
+			nonNilResults = append(nonNilResults, first, resultSelector)
+		default:
 			nonNilResults = append(nonNilResults, resultSelector)
 		}
 	}
-	if len(nonNilResults) == 0 {
-		return nil
+	if nonNilResults != nil {
+		return ExploreUnion{nonNilResults}
 	}
-	if len(nonNilResults) == 1 {
-		return nonNilResults[0]
-	}
-	return ExploreUnion{nonNilResults}
+	return first
 }
 
 // Decide returns true for a Union selector if any of the member selectors
@@ -71,6 +79,58 @@ func (s ExploreUnion) Decide(n ipld.Node) bool {
 	return false
 }
 
+// DecideAt returns true if any of the member selectors do, given the node's position
+// (see the package-level DecideAt).
+func (s ExploreUnion) DecideAt(n ipld.Node, p ipld.Path) bool {
+	for _, m := range s.Members {
+		if DecideAt(m, n, p) {
+			return true
+		}
+	}
+	return false
+}
+
+// ExpectedKinds returns all the kinds which any member requires of the node
+// at the given segment (see the package-level ExpectedKinds),
+// or nil if no member makes any requirement.
+func (s ExploreUnion) ExpectedKinds(p ipld.PathSegment) ipld.ReprKindSet {
+	var ks ipld.ReprKindSet
+	for _, m := range s.Members {
+		for _, k := range ExpectedKinds(m, p) {
+			if !ks.Contains(k) {
+				ks = append(ks, k)
+			}
+		}
+	}
+	return ks
+}
+
+// String renders the selector compactly, e.g. "ExploreUnion(Matcher | ExploreAll(Matcher))".
+func (s ExploreUnion) String() string {
+	var sb strings.Builder
+	sb.WriteString("ExploreUnion(")
+	for i, m := range s.Members {
+		if i > 0 {
+			sb.WriteString(" | ")
+		}
+		fmt.Fprintf(&sb, "%v", m)
+	}
+	sb.WriteString(")")
+	return sb.String()
+}
+
+// DecideLabels returns the labels reported by every member selector which
+// decides in favor of the node, in member order.
+// If several members match, the node was matched "for several reasons",
+// and all of them are reported (this is how traversals tell the branches apart).
+func (s ExploreUnion) DecideLabels(n ipld.Node) []string {
+	var labels []string
+	for _, m := range s.Members {
+		labels = append(labels, DecideLabels(m, n)...)
+	}
+	return labels
+}
+
 // ParseExploreUnion assembles a Selector
 // from an ExploreUnion selector node
 func (pc ParseContext) ParseExploreUnion(n ipld.Node) (Selector, error) {
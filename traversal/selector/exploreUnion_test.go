@@ -72,7 +72,7 @@ func TestExploreUnionExplore(t *testing.T) {
 			Matcher{},
 			ExploreIndex{Matcher{}, [1]ipld.PathSegment{ipld.PathSegmentOfInt(2)}},
 			ExploreRange{Matcher{}, 2, 3, []ipld.PathSegment{ipld.PathSegmentOfInt(2)}},
-			ExploreFields{map[string]Selector{"applesauce": Matcher{}}, []ipld.PathSegment{ipld.PathSegmentOfString("applesauce")}},
+			ExploreFields{selections: map[string]Selector{"applesauce": Matcher{}}, interests: []ipld.PathSegment{ipld.PathSegmentOfString("applesauce")}},
 		}}
 
 		returnedSelector := s.Explore(n, ipld.PathSegmentOfInt(2))
@@ -91,7 +91,7 @@ func TestExploreUnionInterests(t *testing.T) {
 	})
 	t.Run("if no member selector is high-cardinality, interests should be combination of member selectors interests", func(t *testing.T) {
 		s := ExploreUnion{[]Selector{
-			ExploreFields{map[string]Selector{"applesauce": Matcher{}}, []ipld.PathSegment{ipld.PathSegmentOfString("applesauce")}},
+			ExploreFields{selections: map[string]Selector{"applesauce": Matcher{}}, interests: []ipld.PathSegment{ipld.PathSegmentOfString("applesauce")}},
 			Matcher{},
 			ExploreIndex{Matcher{}, [1]ipld.PathSegment{ipld.PathSegmentOfInt(2)}},
 		}}
@@ -111,10 +111,22 @@ func TestExploreUnionDecide(t *testing.T) {
 	})
 	t.Run("if no member selector returns true, decide should be false", func(t *testing.T) {
 		s := ExploreUnion{[]Selector{
-			ExploreFields{map[string]Selector{"applesauce": Matcher{}}, []ipld.PathSegment{ipld.PathSegmentOfString("applesauce")}},
+			ExploreFields{selections: map[string]Selector{"applesauce": Matcher{}}, interests: []ipld.PathSegment{ipld.PathSegmentOfString("applesauce")}},
 			ExploreAll{Matcher{}},
 			ExploreIndex{Matcher{}, [1]ipld.PathSegment{ipld.PathSegmentOfInt(2)}},
 		}}
 		Wish(t, s.Decide(n), ShouldEqual, false)
 	})
 }
+
+func TestExploreUnionDecideLabels(t *testing.T) {
+	n := basicnode.NewInt(1)
+	t.Run("each matching member reports its label", func(t *testing.T) {
+		s := ExploreUnion{[]Selector{Matcher{Label: "a"}, ExploreAll{Matcher{Label: "x"}}, Matcher{Label: "b"}}}
+		Wish(t, DecideLabels(s, n), ShouldEqual, []string{"a", "b"})
+	})
+	t.Run("no matching members reports nil", func(t *testing.T) {
+		s := ExploreUnion{[]Selector{ExploreAll{Matcher{Label: "x"}}}}
+		Wish(t, DecideLabels(s, n), ShouldEqual, []string(nil))
+	})
+}
@@ -2,6 +2,7 @@ package selector
 
 const (
 	SelectorKey_Matcher              = "."
+	SelectorKey_MatchLeaves          = ".."
 	SelectorKey_ExploreAll           = "a"
 	SelectorKey_ExploreFields        = "f"
 	SelectorKey_ExploreIndex         = "i"
@@ -21,5 +22,11 @@ const (
 	SelectorKey_LimitNone            = "none"
 	SelectorKey_StopAt               = "!"
 	SelectorKey_Condition            = "&"
+	SelectorKey_Label                = "label"
+	SelectorKey_Kinds                = "k"
+	SelectorKey_Subset               = "subset"
+	SelectorKey_From                 = "["
+	SelectorKey_To                   = "]"
+	SelectorKey_Current              = "current" // not part of the spec; written by Encode, and read only by ParseEncoded.
 	// not filling conditional keys since it's not complete
 )
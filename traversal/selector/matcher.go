@@ -15,8 +15,35 @@ import (
 //
 // A selector tree with only "explore*"-type selectors and no Matcher selectors
 // is valid; it will just generate a "covered" set of nodes and no "result" set.
-// TODO: From spec: implement conditions and labels
-type Matcher struct{}
+//
+// A Matcher may carry a Label, which is reported to traversals (see DecideLabels)
+// so they can tell which Matcher caused a match -- this is useful when several
+// Matchers are reachable at once, e.g. in the branches of an ExploreUnion.
+//
+// A Matcher may also be limited to nodes of certain Kinds: if Kinds is non-empty,
+// only nodes whose ReprKind is in the set are matched.
+// Combined with ExploreRecursive and ExploreAll, this selects
+// e.g. "all the strings in this tree".
+//
+// A Matcher may also carry a Slice, to select only part of a bytes or string node.
+// The Slice doesn't change what Decide matches; use Matched to get the part of
+// a matched node which the Slice selects.
+// TODO: From spec: implement conditions
+type Matcher struct {
+	Label string
+	Kinds ipld.ReprKindSet
+	Slice *Slice
+
+	leaves bool // leaves is set by ParseMatchLeaves, so that Encode and MarshalBinary give back the MatchLeaves form.
+}
+
+// Slice describes a range within a bytes or string node, as byte offsets:
+// From is inclusive, and To exclusive.  Bounds past the end of a node are
+// clamped to its length, so e.g. a Slice of {2, 5} of a 3-byte node yields only its last byte.
+type Slice struct {
+	From int
+	To   int
+}
 
 // Interests are empty for a matcher (for now) because
 // It is always just there to match, not explore further
@@ -29,18 +56,198 @@ func (s Matcher) Explore(n ipld.Node, p ipld.PathSegment) Selector {
 	return nil
 }
 
-// Decide is always true for a match cause it's in the result set
+// Decide is true for any node, unless Kinds is set,
+// in which case it's true only for nodes of those kinds.
+// A Kinds of just Null matches nodes which are present and null (IsNull);
+// ipld.Undef is never matched by any Kinds, even though its ReprKind is Null,
+// because it means the value is absent.
 // TODO: Implement boolean logic for conditionals
 func (s Matcher) Decide(n ipld.Node) bool {
-	return true
+	if len(s.Kinds) == 0 {
+		return true
+	}
+	return !n.IsUndefined() && s.Kinds.Contains(n.ReprKind())
+}
+
+// Matched returns the part of n which the Matcher selects.
+// That's n itself, unless the Matcher has a Slice and n is bytes or a string,
+// in which case it's a new node of the same style holding just that range.
+// Matched doesn't call Decide; use it on nodes which Decide has already matched.
+func (s Matcher) Matched(n ipld.Node) (ipld.Node, error) {
+	if s.Slice == nil {
+		return n, nil
+	}
+	switch n.ReprKind() {
+	case ipld.ReprKind_Bytes:
+		b, err := n.AsBytes()
+		if err != nil {
+			return nil, err
+		}
+		from, to := s.Slice.bounds(len(b))
+		nb := n.Style().NewBuilder()
+		if err := nb.AssignBytes(b[from:to]); err != nil {
+			return nil, err
+		}
+		return nb.Build(), nil
+	case ipld.ReprKind_String:
+		str, err := n.AsString()
+		if err != nil {
+			return nil, err
+		}
+		from, to := s.Slice.bounds(len(str))
+		nb := n.Style().NewBuilder()
+		if err := nb.AssignString(str[from:to]); err != nil {
+			return nil, err
+		}
+		return nb.Build(), nil
+	default:
+		return n, nil
+	}
+}
+
+// bounds clamps the Slice to a node of length l.
+func (s Slice) bounds(l int) (from, to int) {
+	from, to = s.From, s.To
+	if to > l {
+		to = l
+	}
+	if from > to {
+		from = to
+	}
+	return from, to
+}
+
+// String renders the selector as "Matcher", or with its label and kinds,
+// e.g. `Matcher("lbl")` or `Matcher("lbl", Int or String)`.
+// A Slice is appended in brackets, e.g. `Matcher[2:5]`.
+func (s Matcher) String() string {
+	var str string
+	switch {
+	case s.Label == "" && len(s.Kinds) == 0:
+		str = "Matcher"
+	case len(s.Kinds) == 0:
+		str = fmt.Sprintf("Matcher(%q)", s.Label)
+	case s.Label == "":
+		str = fmt.Sprintf("Matcher(%v)", s.Kinds)
+	default:
+		str = fmt.Sprintf("Matcher(%q, %v)", s.Label, s.Kinds)
+	}
+	if s.Slice != nil {
+		str += fmt.Sprintf("[%d:%d]", s.Slice.From, s.Slice.To)
+	}
+	return str
+}
+
+// DecideLabels reports the Matcher's label if Decide is true, and nil otherwise.
+func (s Matcher) DecideLabels(n ipld.Node) []string {
+	if !s.Decide(n) {
+		return nil
+	}
+	return []string{s.Label}
 }
 
 // ParseMatcher assembles a Selector
 // from a matcher selector node
-// TODO: Parse labels and conditions
+// TODO: Parse conditions
 func (pc ParseContext) ParseMatcher(n ipld.Node) (Selector, error) {
 	if n.ReprKind() != ipld.ReprKind_Map {
 		return nil, fmt.Errorf("selector spec parse rejected: selector body must be a map")
 	}
-	return Matcher{}, nil
+	var m Matcher
+	if labelNode, err := n.LookupString(SelectorKey_Label); err == nil {
+		m.Label, err = labelNode.AsString()
+		if err != nil {
+			return nil, fmt.Errorf("selector spec parse rejected: label field must be a string")
+		}
+	}
+	if kindsNode, err := n.LookupString(SelectorKey_Kinds); err == nil {
+		m.Kinds, err = parseKinds(kindsNode)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if subsetNode, err := n.LookupString(SelectorKey_Subset); err == nil {
+		m.Slice, err = parseSlice(subsetNode)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return m, nil
+}
+
+// LeafKinds are the kinds of the nodes which have no children to explore:
+// the scalars, except for links (which a traversal may load and explore).
+var LeafKinds = ipld.ReprKindSet{ipld.ReprKind_Null, ipld.ReprKind_Bool, ipld.ReprKind_Int, ipld.ReprKind_Float, ipld.ReprKind_String, ipld.ReprKind_Bytes}
+
+// ParseMatchLeaves assembles a Selector from a MatchLeaves selector node,
+// which is a marker for a Matcher of the LeafKinds.
+// The Matcher remembers where it came from: it encodes as MatchLeaves again.
+// Combined with ExploreRecursive and ExploreAll, it selects all the scalars in a tree,
+// without the maps and lists containing them.
+func (pc ParseContext) ParseMatchLeaves(n ipld.Node) (Selector, error) {
+	if n.ReprKind() != ipld.ReprKind_Map {
+		return nil, fmt.Errorf("selector spec parse rejected: selector body must be a map")
+	}
+	return Matcher{Kinds: LeafKinds, leaves: true}, nil
 }
+
+// parseSlice reads a subset, which is a map with "[" and "]" bounds.
+func parseSlice(n ipld.Node) (*Slice, error) {
+	if n.ReprKind() != ipld.ReprKind_Map {
+		return nil, fmt.Errorf("selector spec parse rejected: subset field must be a map")
+	}
+	fromNode, err := n.LookupString(SelectorKey_From)
+	if err != nil {
+		return nil, fmt.Errorf("selector spec parse rejected: subset field must have a %s bound", SelectorKey_From)
+	}
+	from, err := fromNode.AsInt()
+	if err != nil {
+		return nil, fmt.Errorf("selector spec parse rejected: subset bounds must be ints")
+	}
+	toNode, err := n.LookupString(SelectorKey_To)
+	if err != nil {
+		return nil, fmt.Errorf("selector spec parse rejected: subset field must have a %s bound", SelectorKey_To)
+	}
+	to, err := toNode.AsInt()
+	if err != nil {
+		return nil, fmt.Errorf("selector spec parse rejected: subset bounds must be ints")
+	}
+	if from < 0 || to < from {
+		return nil, fmt.Errorf("selector spec parse rejected: subset bounds must satisfy 0 <= %s <= %s", SelectorKey_From, SelectorKey_To)
+	}
+	return &Slice{from, to}, nil
+}
+
+// parseKinds reads a list of kind names, as given by ReprKind.String (e.g. "Int").
+func parseKinds(n ipld.Node) (ipld.ReprKindSet, error) {
+	if n.ReprKind() != ipld.ReprKind_List || n.Length() == 0 {
+		return nil, fmt.Errorf("selector spec parse rejected: kinds field must be a non-empty list")
+	}
+	ks := make(ipld.ReprKindSet, 0, n.Length())
+	for itr := n.ListIterator(); !itr.Done(); {
+		_, v, err := itr.Next()
+		if err != nil {
+			return nil, err
+		}
+		name, err := v.AsString()
+		if err != nil {
+			return nil, fmt.Errorf("selector spec parse rejected: kinds field must contain strings")
+		}
+		k, ok := kindsByName[name]
+		if !ok {
+			return nil, fmt.Errorf("selector spec parse rejected: %q is not a kind", name)
+		}
+		ks = append(ks, k)
+	}
+	return ks, nil
+}
+
+var kindsByName = func() map[string]ipld.ReprKind {
+	m := make(map[string]ipld.ReprKind, len(ipld.ReprKindSet_Recursive)+len(ipld.ReprKindSet_Scalar))
+	for _, ks := range []ipld.ReprKindSet{ipld.ReprKindSet_Recursive, ipld.ReprKindSet_Scalar} {
+		for _, k := range ks {
+			m[k.String()] = k
+		}
+	}
+	return m
+}()
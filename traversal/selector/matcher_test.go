@@ -0,0 +1,138 @@
+package selector
+
+import (
+	"fmt"
+	"testing"
+
+	. "github.com/warpfork/go-wish"
+
+	ipld "github.com/ipld/go-ipld-prime"
+	"github.com/ipld/go-ipld-prime/fluent"
+	basicnode "github.com/ipld/go-ipld-prime/node/basic"
+)
+
+func TestParseMatcher(t *testing.T) {
+	t.Run("parsing empty map node should parse", func(t *testing.T) {
+		sn := fluent.MustBuildMap(basicnode.Style__Map{}, 0, func(na fluent.MapAssembler) {})
+		s, err := ParseContext{}.ParseMatcher(sn)
+		Wish(t, err, ShouldEqual, nil)
+		Wish(t, s, ShouldEqual, Matcher{})
+	})
+	t.Run("parsing MatchLeaves should give a Matcher of the leaf kinds", func(t *testing.T) {
+		sn := fluent.MustBuildMap(basicnode.Style__Map{}, 1, func(na fluent.MapAssembler) {
+			na.AssembleEntry(SelectorKey_MatchLeaves).CreateMap(0, func(na fluent.MapAssembler) {})
+		})
+		s, err := ParseSelector(sn)
+		Wish(t, err, ShouldEqual, nil)
+		Wish(t, s.(Matcher).Kinds, ShouldEqual, LeafKinds)
+		Wish(t, s.String(), ShouldEqual, "Matcher(Null or Bool or Int or Float or String or Bytes)")
+	})
+	t.Run("parsing map node with label should parse", func(t *testing.T) {
+		sn := fluent.MustBuildMap(basicnode.Style__Map{}, 1, func(na fluent.MapAssembler) {
+			na.AssembleEntry(SelectorKey_Label).AssignString("lbl")
+		})
+		s, err := ParseContext{}.ParseMatcher(sn)
+		Wish(t, err, ShouldEqual, nil)
+		Wish(t, s, ShouldEqual, Matcher{Label: "lbl"})
+	})
+	t.Run("parsing map node with non-string label should error", func(t *testing.T) {
+		sn := fluent.MustBuildMap(basicnode.Style__Map{}, 1, func(na fluent.MapAssembler) {
+			na.AssembleEntry(SelectorKey_Label).AssignInt(2)
+		})
+		_, err := ParseContext{}.ParseMatcher(sn)
+		Wish(t, err, ShouldEqual, fmt.Errorf("selector spec parse rejected: label field must be a string"))
+	})
+	t.Run("parsing map node with kinds should parse", func(t *testing.T) {
+		sn := fluent.MustBuildMap(basicnode.Style__Map{}, 2, func(na fluent.MapAssembler) {
+			na.AssembleEntry(SelectorKey_Label).AssignString("lbl")
+			na.AssembleEntry(SelectorKey_Kinds).CreateList(2, func(na fluent.ListAssembler) {
+				na.AssembleValue().AssignString("Int")
+				na.AssembleValue().AssignString("String")
+			})
+		})
+		s, err := ParseContext{}.ParseMatcher(sn)
+		Wish(t, err, ShouldEqual, nil)
+		Wish(t, s, ShouldEqual, Matcher{Label: "lbl", Kinds: ipld.ReprKindSet{ipld.ReprKind_Int, ipld.ReprKind_String}})
+		Wish(t, s.String(), ShouldEqual, `Matcher("lbl", Int or String)`)
+	})
+	t.Run("parsing map node with subset should parse", func(t *testing.T) {
+		sn := fluent.MustBuildMap(basicnode.Style__Map{}, 1, func(na fluent.MapAssembler) {
+			na.AssembleEntry(SelectorKey_Subset).CreateMap(2, func(na fluent.MapAssembler) {
+				na.AssembleEntry(SelectorKey_From).AssignInt(2)
+				na.AssembleEntry(SelectorKey_To).AssignInt(5)
+			})
+		})
+		s, err := ParseContext{}.ParseMatcher(sn)
+		Wish(t, err, ShouldEqual, nil)
+		Wish(t, s, ShouldEqual, Matcher{Slice: &Slice{2, 5}})
+		Wish(t, s.String(), ShouldEqual, "Matcher[2:5]")
+	})
+	t.Run("parsing map node with backwards subset should error", func(t *testing.T) {
+		sn := fluent.MustBuildMap(basicnode.Style__Map{}, 1, func(na fluent.MapAssembler) {
+			na.AssembleEntry(SelectorKey_Subset).CreateMap(2, func(na fluent.MapAssembler) {
+				na.AssembleEntry(SelectorKey_From).AssignInt(5)
+				na.AssembleEntry(SelectorKey_To).AssignInt(2)
+			})
+		})
+		_, err := ParseContext{}.ParseMatcher(sn)
+		Wish(t, err, ShouldEqual, fmt.Errorf("selector spec parse rejected: subset bounds must satisfy 0 <= [ <= ]"))
+	})
+	t.Run("parsing map node with unknown kind should error", func(t *testing.T) {
+		sn := fluent.MustBuildMap(basicnode.Style__Map{}, 1, func(na fluent.MapAssembler) {
+			na.AssembleEntry(SelectorKey_Kinds).CreateList(1, func(na fluent.ListAssembler) {
+				na.AssembleValue().AssignString("int")
+			})
+		})
+		_, err := ParseContext{}.ParseMatcher(sn)
+		Wish(t, err, ShouldEqual, fmt.Errorf("selector spec parse rejected: \"int\" is not a kind"))
+	})
+	t.Run("parsing map node with empty kinds should error", func(t *testing.T) {
+		sn := fluent.MustBuildMap(basicnode.Style__Map{}, 1, func(na fluent.MapAssembler) {
+			na.AssembleEntry(SelectorKey_Kinds).CreateList(0, func(na fluent.ListAssembler) {})
+		})
+		_, err := ParseContext{}.ParseMatcher(sn)
+		Wish(t, err, ShouldEqual, fmt.Errorf("selector spec parse rejected: kinds field must be a non-empty list"))
+	})
+}
+
+func TestMatcherKinds(t *testing.T) {
+	s := Matcher{Kinds: ipld.ReprKindSet_JustInt}
+	Wish(t, s.Decide(basicnode.NewInt(1)), ShouldEqual, true)
+	Wish(t, s.Decide(basicnode.NewString("x")), ShouldEqual, false)
+	Wish(t, DecideLabels(s, basicnode.NewString("x")), ShouldEqual, []string(nil))
+	Wish(t, Matcher{}.Decide(basicnode.NewString("x")), ShouldEqual, true)
+
+	// Null is present and null; Undef is absent, though its ReprKind is Null too.
+	s = Matcher{Kinds: ipld.ReprKindSet{ipld.ReprKind_Null}}
+	Wish(t, s.Decide(ipld.Null), ShouldEqual, true)
+	Wish(t, s.Decide(ipld.Undef), ShouldEqual, false)
+}
+
+func TestMatcherSlice(t *testing.T) {
+	s := Matcher{Slice: &Slice{2, 5}}
+	t.Run("bytes", func(t *testing.T) {
+		n := basicnode.NewBytes([]byte{0, 1, 2, 3, 4, 5, 6, 7})
+		Wish(t, s.Decide(n), ShouldEqual, true)
+		m, err := s.Matched(n)
+		Wish(t, err, ShouldEqual, nil)
+		Wish(t, m, ShouldEqual, basicnode.NewBytes([]byte{2, 3, 4}))
+	})
+	t.Run("string", func(t *testing.T) {
+		m, err := s.Matched(basicnode.NewString("abcdefg"))
+		Wish(t, err, ShouldEqual, nil)
+		Wish(t, m, ShouldEqual, basicnode.NewString("cde"))
+	})
+	t.Run("bounds past the end are clamped", func(t *testing.T) {
+		m, err := s.Matched(basicnode.NewBytes([]byte{0, 1, 2, 3}))
+		Wish(t, err, ShouldEqual, nil)
+		Wish(t, m, ShouldEqual, basicnode.NewBytes([]byte{2, 3}))
+		m, err = s.Matched(basicnode.NewBytes([]byte{0}))
+		Wish(t, err, ShouldEqual, nil)
+		Wish(t, m, ShouldEqual, basicnode.NewBytes([]byte{}))
+	})
+	t.Run("other kinds are whole", func(t *testing.T) {
+		m, err := s.Matched(basicnode.NewInt(12))
+		Wish(t, err, ShouldEqual, nil)
+		Wish(t, m, ShouldEqual, basicnode.NewInt(12))
+	})
+}
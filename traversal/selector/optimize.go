@@ -0,0 +1,130 @@
+package selector
+
+import (
+	ipld "github.com/ipld/go-ipld-prime"
+)
+
+// Optimize returns a selector which selects the same nodes as s,
+// with the same labels, but which may be quicker to traverse with,
+// because redundant parts of it have been folded away.
+//
+// The folding rules are:
+//
+//   - an ExploreUnion with only one member becomes that member;
+//   - an ExploreUnion which is a member of another ExploreUnion has its members merged into the outer one;
+//   - an ExploreRange of a single index becomes an ExploreIndex;
+//   - an ExploreRecursive limited to depth 0 or 1 (which only ever runs through its sequence once)
+//     becomes its sequence, with the ExploreRecursiveEdges dropped.
+//
+// These apply all through the selector (including within the sequences of ExploreRecursive).
+// The optimized selector may visit fewer of the nodes which don't match, so a traversal with it
+// may load fewer links, and (with StrictInterests) report fewer mismatches for missing data;
+// but the nodes matched are the same.
+//
+// Selectors of types from outside this package are left as they are.
+// The result may share parts with s.
+func Optimize(s Selector) Selector {
+	switch s2 := s.(type) {
+	case ExploreAll:
+		return ExploreAll{Optimize(s2.next)}
+	case ExploreFields:
+		x := ExploreFields{
+			selections: make(map[string]Selector, len(s2.selections)),
+			interests:  s2.interests,
+			kinds:      s2.kinds,
+		}
+		for k, v := range s2.selections {
+			x.selections[k] = Optimize(v)
+		}
+		return x
+	case ExploreIndex:
+		return ExploreIndex{Optimize(s2.next), s2.interest}
+	case ExploreRange:
+		if s2.end-s2.start == 1 {
+			return ExploreIndex{Optimize(s2.next), [1]ipld.PathSegment{ipld.PathSegmentOfInt(s2.start)}}
+		}
+		return ExploreRange{Optimize(s2.next), s2.start, s2.end, s2.interest}
+	case ExploreUnion:
+		members := make([]Selector, 0, len(s2.Members))
+		for _, m := range s2.Members {
+			m = Optimize(m)
+			if u, ok := m.(ExploreUnion); ok {
+				members = append(members, u.Members...)
+			} else {
+				members = append(members, m)
+			}
+		}
+		if len(members) == 1 {
+			return members[0]
+		}
+		return ExploreUnion{members}
+	case ExploreRecursive:
+		x := ExploreRecursive{Optimize(s2.sequence), Optimize(s2.current), s2.limit}
+		if x.limit.mode == RecursionLimit_Depth && x.limit.depth < 2 {
+			if s3 := dropRecursiveEdges(x.current); s3 != nil {
+				return s3
+			}
+		}
+		return x
+	default:
+		return s
+	}
+}
+
+// dropRecursiveEdges returns s without its ExploreRecursiveEdges
+// (but not those of any ExploreRecursive inside it, which are its own),
+// or nil if nothing is left of it.
+func dropRecursiveEdges(s Selector) Selector {
+	switch s2 := s.(type) {
+	case ExploreRecursiveEdge:
+		return nil
+	case ExploreAll:
+		if next := dropRecursiveEdges(s2.next); next != nil {
+			return ExploreAll{next}
+		}
+		return nil
+	case ExploreFields:
+		x := ExploreFields{
+			selections: make(map[string]Selector, len(s2.selections)),
+			interests:  make([]ipld.PathSegment, 0, len(s2.interests)),
+			kinds:      s2.kinds,
+		}
+		for _, ps := range s2.interests {
+			if next := dropRecursiveEdges(s2.selections[ps.String()]); next != nil {
+				x.interests = append(x.interests, ps)
+				x.selections[ps.String()] = next
+			}
+		}
+		if len(x.interests) == 0 {
+			return nil
+		}
+		return x
+	case ExploreIndex:
+		if next := dropRecursiveEdges(s2.next); next != nil {
+			return ExploreIndex{next, s2.interest}
+		}
+		return nil
+	case ExploreRange:
+		if next := dropRecursiveEdges(s2.next); next != nil {
+			return ExploreRange{next, s2.start, s2.end, s2.interest}
+		}
+		return nil
+	case ExploreUnion:
+		members := make([]Selector, 0, len(s2.Members))
+		for _, m := range s2.Members {
+			if m = dropRecursiveEdges(m); m != nil {
+				members = append(members, m)
+			}
+		}
+		switch len(members) {
+		case 0:
+			return nil
+		case 1:
+			return members[0]
+		default:
+			return ExploreUnion{members}
+		}
+	default:
+		return s
+	}
+}
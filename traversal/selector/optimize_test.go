@@ -0,0 +1,82 @@
+package selector_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	. "github.com/warpfork/go-wish"
+
+	ipld "github.com/ipld/go-ipld-prime"
+	"github.com/ipld/go-ipld-prime/codec/dagjson"
+	basicnode "github.com/ipld/go-ipld-prime/node/basic"
+	"github.com/ipld/go-ipld-prime/traversal"
+	"github.com/ipld/go-ipld-prime/traversal/selector"
+)
+
+func TestOptimize(t *testing.T) {
+	nb := basicnode.Style__Any{}.NewBuilder()
+	Require(t, dagjson.Decoder(nb, strings.NewReader(`{
+		"a": {"a": {"a": "deep", "b": [1, 2]}, "b": "x"},
+		"b": [{"a": 1}, {"a": [3, 4, {"a": 5}]}, "y"],
+		"c": {"d": null}
+	}`)), ShouldEqual, nil)
+	data := nb.Build()
+
+	for _, tc := range []struct {
+		name      string
+		spec      string
+		optimized string // the String of the optimized selector.
+	}{
+		{"union of one",
+			`{"|": [{"a": {">": {".": {"label": "x"}}}}]}`,
+			`ExploreAll(Matcher("x"))`},
+		{"nested unions",
+			`{"|": [{".": {"label": "top"}}, {"|": [{"f": {"f>": {"a": {".": {}}}}}, {"|": [{"a": {">": {".": {"label": "all"}}}}]}]}]}`,
+			`ExploreUnion(Matcher("top") | ExploreFields{a: Matcher} | ExploreAll(Matcher("all")))`},
+		{"range of one",
+			`{"f": {"f>": {"b": {"r": {"^": 1, "$": 2, ">": {"a": {">": {".": {}}}}}}}}}`,
+			`ExploreFields{b: ExploreIndex(1 -> ExploreAll(Matcher))}`},
+		{"recursion to depth 0",
+			`{"R": {"l": {"depth": 0}, ":>": {"|": [{".": {}}, {"a": {">": {"@": {}}}}]}}}`,
+			`Matcher`},
+		{"recursion to depth 1",
+			`{"R": {"l": {"depth": 1}, ":>": {"a": {">": {"|": [{".": {}}, {"f": {"f>": {"a": {"@": {}}, "b": {".": {}}}}}]}}}}}`,
+			`ExploreAll(ExploreUnion(Matcher | ExploreFields{b: Matcher}))`},
+		{"deeper recursion is kept, but its sequence is optimized",
+			`{"R": {"l": {"depth": 3}, ":>": {"|": [{".": {}}, {"|": [{"a": {">": {"@": {}}}}]}]}}}`,
+			`ExploreRecursive(depth=3, ExploreUnion(Matcher | ExploreAll(ExploreRecursiveEdge)))`},
+		{"recursion which would be left with nothing is kept",
+			`{"|": [{".": {}}, {"R": {"l": {"depth": 1}, ":>": {"a": {">": {"@": {}}}}}}]}`,
+			`ExploreUnion(Matcher | ExploreRecursive(depth=1, ExploreAll(ExploreRecursiveEdge)))`},
+		{"union of one with kinds",
+			`{"|": [{"f": {"f>": {"a": {".": {}}, "b": {".": {}}}, "k": {"b": ["List"]}}}]}`,
+			`ExploreFields{a: Matcher, b (List): Matcher}`},
+		{"recursion to depth 1 with kinds",
+			`{"R": {"l": {"depth": 1}, ":>": {"f": {"f>": {"a": {"@": {}}, "b": {".": {}}}, "k": {"a": ["Map"], "b": ["List"]}}}}}`,
+			`ExploreFields{b (List): Matcher}`},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			s, err := selector.ParseFromJSON(basicnode.Style__Any{}, []byte(tc.spec))
+			Require(t, err, ShouldEqual, nil)
+			s2 := selector.Optimize(s)
+			Wish(t, s2.String(), ShouldEqual, tc.optimized)
+			want := walkMatches(t, data, s)
+			if len(want) == 0 {
+				t.Fatalf("test selector matches nothing")
+			}
+			Wish(t, walkMatches(t, data, s2), ShouldEqual, want)
+		})
+	}
+}
+
+// walkMatches returns the paths and labels of all the nodes which s matches in n.
+func walkMatches(t *testing.T, n ipld.Node, s selector.Selector) []string {
+	var matches []string
+	err := traversal.WalkMatching(n, s, func(prog traversal.Progress, n ipld.Node) error {
+		matches = append(matches, fmt.Sprintf("%s %q", prog.Path, prog.MatchLabels))
+		return nil
+	})
+	Wish(t, err, ShouldEqual, nil)
+	return matches
+}
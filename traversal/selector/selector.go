@@ -9,9 +9,75 @@ import (
 // Selector is the programmatic representation of an IPLD Selector Node
 // and can be applied to traverse a given IPLD DAG
 type Selector interface {
-	Interests() []ipld.PathSegment                // returns the segments we're likely interested in **or nil** if we're a high-cardinality or expression based matcher and need all segments proposed to us.
+	Interests() []ipld.PathSegment                // returns the segments we're likely interested in **or nil** if we're a high-cardinality or expression based matcher and need all segments proposed to us.  the slice belongs to the caller: implementations must not return one they keep and reuse, so callers are free to modify (or append to) it.
 	Explore(ipld.Node, ipld.PathSegment) Selector // explore one step -- iteration comes from outside (either whole node, or by following suggestions of Interests).  returns nil if no interest.  you have to traverse to the next node yourself (the selector doesn't do it for you because you might be considering multiple selection reasons at the same time).
 	Decide(ipld.Node) bool
+	String() string // renders a compact, human-readable description of the selector, e.g. "ExploreIndex(3 -> Matcher)".  meant for debugging and logging; the format is stable, but not parsable.
+}
+
+// copyInterests returns a copy of a selector's stored interests, for returning
+// from Interests.  It's never nil (even if empty), since nil means "all segments".
+func copyInterests(ps []ipld.PathSegment) []ipld.PathSegment {
+	v := make([]ipld.PathSegment, len(ps))
+	copy(v, ps)
+	return v
+}
+
+// DecideLabels is like Decide, but rather than a bool, it returns the labels
+// of each Matcher which decided in favor of the node (or nil if none did).
+// An unlabelled Matcher reports an empty string.
+//
+// Selectors which can match a node in more than one way (e.g. ExploreUnion)
+// implement a DecideLabels method of the same shape, which is used if present;
+// for any other Selector, this falls back to Decide and reports a single empty label.
+func DecideLabels(s Selector, n ipld.Node) []string {
+	if s2, ok := s.(interface {
+		DecideLabels(ipld.Node) []string
+	}); ok {
+		return s2.DecideLabels(n)
+	}
+	if s.Decide(n) {
+		return []string{""}
+	}
+	return nil
+}
+
+// DecideAt is like Decide, but also tells the selector where the node is:
+// p is the path by which the traversal reached it, so p.Last() is the map key
+// or list index of the node in its parent (and p is empty at the root).
+// Traversals use this, rather than Decide, to decide on each node they visit.
+//
+// This lets a selector decide by position, e.g. under ExploreAll, where the
+// selector it explores to is the same for every element.
+// Selectors which decide by position, or which contain others that may
+// (ExploreUnion and ExploreRecursive), implement a DecideAt method of the same shape,
+// which is used if present; for any other Selector, this falls back to Decide.
+// (DecideLabels doesn't see the position: a node matched only by position
+// is reported by traversals as matched by an unlabelled Matcher.)
+func DecideAt(s Selector, n ipld.Node, p ipld.Path) bool {
+	if s2, ok := s.(interface {
+		DecideAt(ipld.Node, ipld.Path) bool
+	}); ok {
+		return s2.DecideAt(n, p)
+	}
+	return s.Decide(n)
+}
+
+// ExpectedKinds returns the kinds which a selector requires the node at the given
+// segment (of the node it's exploring) to have, or nil if it has no such requirement.
+// Traversals check this before exploring into the segment.
+//
+// Selectors which can make such requirements (e.g. ExploreFields),
+// or which contain others that can (ExploreUnion and ExploreRecursive),
+// implement an ExpectedKinds method of the same shape, which is used if present;
+// for any other Selector, there's no requirement.
+func ExpectedKinds(s Selector, p ipld.PathSegment) ipld.ReprKindSet {
+	if s2, ok := s.(interface {
+		ExpectedKinds(ipld.PathSegment) ipld.ReprKindSet
+	}); ok {
+		return s2.ExpectedKinds(p)
+	}
+	return nil
 }
 
 // ParsedParent is created whenever you are parsing a selector node that may have
@@ -23,6 +89,7 @@ type ParsedParent interface {
 // ParseContext tracks the progress when parsing a selector
 type ParseContext struct {
 	parentStack []ParsedParent
+	encoded     bool // whether to accept the state Encode writes for a selector partway through a traversal; see ParseEncoded.
 }
 
 // ParseSelector creates a Selector that can be traversed from an IPLD Selector node
@@ -59,6 +126,8 @@ func (pc ParseContext) ParseSelector(n ipld.Node) (Selector, error) {
 		return pc.ParseExploreRecursiveEdge(v)
 	case SelectorKey_Matcher:
 		return pc.ParseMatcher(v)
+	case SelectorKey_MatchLeaves:
+		return pc.ParseMatchLeaves(v)
 	default:
 		return nil, fmt.Errorf("selector spec parse rejected: %q is not a known member of the selector union", kstr)
 	}
@@ -70,7 +139,7 @@ func (pc ParseContext) PushParent(parent ParsedParent) ParseContext {
 	parents := make([]ParsedParent, 0, l+1)
 	parents = append(parents, parent)
 	parents = append(parents, pc.parentStack...)
-	return ParseContext{parents}
+	return ParseContext{parents, pc.encoded}
 }
 
 // SegmentIterator iterates either a list or a map, generating PathSegments
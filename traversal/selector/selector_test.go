@@ -0,0 +1,91 @@
+package selector
+
+import (
+	"testing"
+
+	. "github.com/warpfork/go-wish"
+
+	ipld "github.com/ipld/go-ipld-prime"
+	"github.com/ipld/go-ipld-prime/fluent"
+	basicnode "github.com/ipld/go-ipld-prime/node/basic"
+)
+
+func TestSelectorString(t *testing.T) {
+	t.Run("simple nestings render compactly", func(t *testing.T) {
+		Wish(t, ExploreIndex{Matcher{}, [1]ipld.PathSegment{ipld.PathSegmentOfInt(3)}}.String(), ShouldEqual, "ExploreIndex(3 -> Matcher)")
+		Wish(t, ExploreRange{Matcher{Label: "x"}, 2, 5, nil}.String(), ShouldEqual, `ExploreRange(2:5 -> Matcher("x"))`)
+		Wish(t, ExploreUnion{[]Selector{Matcher{}, ExploreAll{Matcher{}}}}.String(), ShouldEqual, "ExploreUnion(Matcher | ExploreAll(Matcher))")
+	})
+	t.Run("parsed selectors render in spec order", func(t *testing.T) {
+		sn := fluent.MustBuildMap(basicnode.Style__Map{}, 1, func(na fluent.MapAssembler) {
+			na.AssembleEntry(SelectorKey_ExploreFields).CreateMap(1, func(na fluent.MapAssembler) {
+				na.AssembleEntry(SelectorKey_Fields).CreateMap(2, func(na fluent.MapAssembler) {
+					na.AssembleEntry("a").CreateMap(1, func(na fluent.MapAssembler) {
+						na.AssembleEntry(SelectorKey_Matcher).CreateMap(0, func(na fluent.MapAssembler) {})
+					})
+					na.AssembleEntry("b").CreateMap(1, func(na fluent.MapAssembler) {
+						na.AssembleEntry(SelectorKey_ExploreAll).CreateMap(1, func(na fluent.MapAssembler) {
+							na.AssembleEntry(SelectorKey_Next).CreateMap(1, func(na fluent.MapAssembler) {
+								na.AssembleEntry(SelectorKey_Matcher).CreateMap(0, func(na fluent.MapAssembler) {})
+							})
+						})
+					})
+				})
+			})
+		})
+		s, err := ParseSelector(sn)
+		Require(t, err, ShouldEqual, nil)
+		Wish(t, s.String(), ShouldEqual, "ExploreFields{a: Matcher, b: ExploreAll(Matcher)}")
+	})
+	t.Run("recursion shows limit and progress", func(t *testing.T) {
+		seq := ExploreAll{ExploreRecursiveEdge{}}
+		s := ExploreRecursive{seq, seq, RecursionLimitDepth(3)}
+		Wish(t, s.String(), ShouldEqual, "ExploreRecursive(depth=3, ExploreAll(ExploreRecursiveEdge))")
+		s2 := ExploreRecursive{ExploreIndex{seq, [1]ipld.PathSegment{ipld.PathSegmentOfInt(0)}}, seq, RecursionLimitNone()}
+		Wish(t, s2.String(), ShouldEqual, "ExploreRecursive(ExploreIndex(0 -> ExploreAll(ExploreRecursiveEdge)) @ ExploreAll(ExploreRecursiveEdge))")
+	})
+}
+
+func TestInterests(t *testing.T) {
+	for _, tc := range []struct {
+		spec string
+		want []ipld.PathSegment
+	}{
+		{`{"i": {"i": 1, ">": {".": {}}}}`, []ipld.PathSegment{ipld.PathSegmentOfInt(1)}},
+		{`{"r": {"^": 1, "$": 3, ">": {".": {}}}}`, []ipld.PathSegment{ipld.PathSegmentOfInt(1), ipld.PathSegmentOfInt(2)}},
+		{`{"f": {"f>": {"a": {".": {}}, "b": {".": {}}}}}`, []ipld.PathSegment{ipld.PathSegmentOfString("a"), ipld.PathSegmentOfString("b")}},
+		{`{"|": [{"i": {"i": 0, ">": {".": {}}}}, {"r": {"^": 1, "$": 2, ">": {".": {}}}}]}`, []ipld.PathSegment{ipld.PathSegmentOfInt(0), ipld.PathSegmentOfInt(1)}},
+		{`{"R": {"l": {"depth": 2}, ":>": {"i": {"i": 1, ">": {"@": {}}}}}}`, []ipld.PathSegment{ipld.PathSegmentOfInt(1)}},
+	} {
+		s, err := ParseFromJSON(basicnode.Style__Any{}, []byte(tc.spec))
+		Wish(t, err, ShouldEqual, nil)
+		Wish(t, s.Interests(), ShouldEqual, tc.want)
+	}
+	t.Run("empty interests stay non-nil", func(t *testing.T) {
+		s, err := ParseFromJSON(basicnode.Style__Any{}, []byte(`{"f": {"f>": {}}}`))
+		Wish(t, err, ShouldEqual, nil)
+		Wish(t, s.Interests() != nil, ShouldEqual, true)
+	})
+}
+
+func TestInterestsAreCallerOwned(t *testing.T) {
+	for _, spec := range []string{
+		`{"i": {"i": 1, ">": {".": {}}}}`,
+		`{"r": {"^": 1, "$": 3, ">": {".": {}}}}`,
+		`{"f": {"f>": {"a": {".": {}}, "b": {".": {}}}}}`,
+		`{"|": [{"i": {"i": 0, ">": {".": {}}}}, {"r": {"^": 1, "$": 2, ">": {".": {}}}}]}`,
+		`{"R": {"l": {"depth": 2}, ":>": {"i": {"i": 1, ">": {"@": {}}}}}}`,
+	} {
+		s, err := ParseFromJSON(basicnode.Style__Any{}, []byte(spec))
+		Wish(t, err, ShouldEqual, nil)
+		before := s.String()
+		want := s.Interests()
+		got := s.Interests()
+		for i := range got {
+			got[i] = ipld.PathSegmentOfString("clobbered")
+		}
+		_ = append(got[:0], ipld.PathSegmentOfString("appended"))
+		Wish(t, s.Interests(), ShouldEqual, want)
+		Wish(t, s.String(), ShouldEqual, before)
+	}
+}
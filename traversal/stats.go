@@ -0,0 +1,56 @@
+package traversal
+
+import (
+	"io"
+
+	ipld "github.com/ipld/go-ipld-prime"
+)
+
+// WalkStats counts the work done by traversals, for resource accounting
+// (e.g. in services which walk DAGs from untrusted sources).
+// Set a *WalkStats as Config.Stats, and traversals using that Config add to it
+// as they go.  Nothing resets the counts, so one WalkStats can total several walks.
+//
+// The counts are accurate whether or not the traversal succeeds,
+// and include only work that was actually done: subtrees pruned with SkipMe
+// (by the visit function or the LinkLoader) aren't visited, so aren't counted;
+// and links satisfied from Config.NodeCache count as LinksCached,
+// with no LinksLoaded or BytesRead.
+//
+// NodesVisited and Matches are counted by WalkMatching, WalkAdv, and Prune;
+// the link counts are kept by every traversal function which loads links.
+// A WalkStats isn't safe to share between concurrent traversals.
+type WalkStats struct {
+	NodesVisited int   // Nodes the walk reached (and so called the visit function for, with any VisitReason).
+	Matches      int   // Of NodesVisited, how many the selector matched.
+	LinksLoaded  int   // Links loaded successfully via the LinkLoader.
+	LinksCached  int   // Links satisfied from Config.NodeCache, without loading.
+	LinksSkipped int   // Links for which the LinkLoader returned SkipMe.
+	BytesRead    int64 // Bytes read from the readers the LinkLoader returned (even for loads which then failed).
+}
+
+// countingLoader wraps a Loader so that the bytes read from it are added to BytesRead.
+// If the stats are nil, the Loader is returned as-is.
+func (stats *WalkStats) countingLoader(loader ipld.Loader) ipld.Loader {
+	if stats == nil || loader == nil {
+		return loader
+	}
+	return func(lnk ipld.Link, lnkCtx ipld.LinkContext) (io.Reader, error) {
+		r, err := loader(lnk, lnkCtx)
+		if err != nil {
+			return nil, err
+		}
+		return &countingReader{r, &stats.BytesRead}, nil
+	}
+}
+
+type countingReader struct {
+	r io.Reader
+	n *int64
+}
+
+func (cr *countingReader) Read(p []byte) (int, error) {
+	n, err := cr.r.Read(p)
+	*cr.n += int64(n)
+	return n, err
+}
@@ -0,0 +1,106 @@
+package traversal_test
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	. "github.com/warpfork/go-wish"
+
+	ipld "github.com/ipld/go-ipld-prime"
+	"github.com/ipld/go-ipld-prime/fluent"
+	basicnode "github.com/ipld/go-ipld-prime/node/basic"
+	"github.com/ipld/go-ipld-prime/traversal"
+	"github.com/ipld/go-ipld-prime/traversal/selector"
+	"github.com/ipld/go-ipld-prime/traversal/selector/builder"
+)
+
+func TestWalkStats(t *testing.T) {
+	// The same diamond as TestNodeCache: two middles linking to one shared leaf.
+	_, sharedLnk := encode(fluent.MustBuildMap(basicnode.Style__Map{}, 1, func(na fluent.MapAssembler) {
+		na.AssembleEntry("shared").AssignBool(true)
+	}))
+	_, leftLnk := encode(fluent.MustBuildMap(basicnode.Style__Map{}, 2, func(na fluent.MapAssembler) {
+		na.AssembleEntry("side").AssignString("left")
+		na.AssembleEntry("leaf").AssignLink(sharedLnk)
+	}))
+	_, rightLnk := encode(fluent.MustBuildMap(basicnode.Style__Map{}, 2, func(na fluent.MapAssembler) {
+		na.AssembleEntry("side").AssignString("right")
+		na.AssembleEntry("leaf").AssignLink(sharedLnk)
+	}))
+	root := fluent.MustBuildMap(basicnode.Style__Map{}, 2, func(na fluent.MapAssembler) {
+		na.AssembleEntry("left").AssignLink(leftLnk)
+		na.AssembleEntry("right").AssignLink(rightLnk)
+	})
+	ssb := builder.NewSelectorSpecBuilder(basicnode.Style__Any{})
+	s, err := ssb.ExploreRecursive(selector.RecursionLimitNone(), ssb.ExploreUnion(ssb.Matcher(), ssb.ExploreAll(ssb.ExploreRecursiveEdge()))).Selector()
+	Require(t, err, ShouldEqual, nil)
+	size := func(lnks ...ipld.Link) (n int64) {
+		for _, lnk := range lnks {
+			n += int64(len(storage[lnk]))
+		}
+		return
+	}
+
+	// walk returns the stats of a walk in which the loader skips skipLink,
+	// and the visitor skips the subtree at skipPath ("-" for none, since "" is the root).
+	walk := func(cache *traversal.NodeCache, skipLink ipld.Link, skipPath string) traversal.WalkStats {
+		var stats traversal.WalkStats
+		err := traversal.Progress{
+			Cfg: &traversal.Config{
+				LinkLoader: func(lnk ipld.Link, _ ipld.LinkContext) (io.Reader, error) {
+					if lnk == skipLink {
+						return nil, traversal.SkipMe{}
+					}
+					return bytes.NewBuffer(storage[lnk]), nil
+				},
+				LinkTargetNodeStyleChooser: func(_ ipld.Link, _ ipld.LinkContext) (ipld.NodeStyle, error) {
+					return basicnode.Style__Any{}, nil
+				},
+				NodeCache: cache,
+				Stats:     &stats,
+			},
+		}.WalkMatching(root, s, func(prog traversal.Progress, n ipld.Node) error {
+			if prog.Path.String() == skipPath {
+				return traversal.SkipMe{}
+			}
+			return nil
+		})
+		Wish(t, err, ShouldEqual, nil)
+		return stats
+	}
+	t.Run("full walk", func(t *testing.T) {
+		Wish(t, walk(nil, nil, "-"), ShouldEqual, traversal.WalkStats{
+			NodesVisited: 9,
+			Matches:      9,
+			LinksLoaded:  4,
+			BytesRead:    size(leftLnk, rightLnk, sharedLnk, sharedLnk),
+		})
+	})
+	t.Run("cached links are not loaded or read", func(t *testing.T) {
+		Wish(t, walk(traversal.NewNodeCache(10), nil, "-"), ShouldEqual, traversal.WalkStats{
+			NodesVisited: 9,
+			Matches:      9,
+			LinksLoaded:  3,
+			LinksCached:  1,
+			BytesRead:    size(leftLnk, rightLnk, sharedLnk),
+		})
+	})
+	t.Run("subtrees pruned by the visitor are not counted", func(t *testing.T) {
+		Wish(t, walk(nil, nil, "left"), ShouldEqual, traversal.WalkStats{
+			NodesVisited: 6, // root, left, right, right/side, right/leaf, right/leaf/shared.
+			Matches:      6,
+			LinksLoaded:  3,
+			BytesRead:    size(leftLnk, rightLnk, sharedLnk),
+		})
+	})
+	t.Run("links skipped by the loader are counted as such", func(t *testing.T) {
+		Wish(t, walk(nil, sharedLnk, "-"), ShouldEqual, traversal.WalkStats{
+			NodesVisited: 5, // root, left, left/side, right, right/side.
+			Matches:      5,
+			LinksLoaded:  2,
+			LinksSkipped: 2,
+			BytesRead:    size(leftLnk, rightLnk),
+		})
+	})
+}
@@ -0,0 +1,73 @@
+package traversal
+
+import (
+	"reflect"
+
+	ipld "github.com/ipld/go-ipld-prime"
+	"github.com/ipld/go-ipld-prime/traversal/selector"
+)
+
+// TransformLinks returns a copy of a Node tree with every link in it
+// replaced by what fn returns for it.  This is handy for re-pointing a tree
+// at differently-hashed (or re-pinned) copies of the blocks it links to.
+//
+// Links are not loaded: only the tree rooted at root is walked,
+// and fn sees each link exactly where it occurs.
+// Nodes which are neither links nor contain any are left alone;
+// the result shares every subtree in which nothing changed with the original,
+// and a map or list is only rebuilt (with its own NodeStyle) when one of its
+// entries did change.  If nothing changed at all, root itself is returned.
+//
+// The first error from fn, or from building a replacement node, is returned as-is.
+func TransformLinks(root ipld.Node, fn func(ipld.Link) (ipld.Link, error)) (ipld.Node, error) {
+	switch root.ReprKind() {
+	case ipld.ReprKind_Link:
+		lnk, err := root.AsLink()
+		if err != nil {
+			return nil, err
+		}
+		lnk2, err := fn(lnk)
+		if err != nil {
+			return nil, err
+		}
+		if sameLink(lnk, lnk2) {
+			return root, nil
+		}
+		nb := root.Style().NewBuilder()
+		if err := nb.AssignLink(lnk2); err != nil {
+			return nil, err
+		}
+		return nb.Build(), nil
+	case ipld.ReprKind_Map, ipld.ReprKind_List:
+		entries := make(map[string]ipld.Node, root.Length())
+		changed := false
+		for itr := selector.NewSegmentIterator(root); !itr.Done(); {
+			ps, v, err := itr.Next()
+			if err != nil {
+				return nil, err
+			}
+			v2, err := TransformLinks(v, fn)
+			if err != nil {
+				return nil, err
+			}
+			if !sameNode(v, v2) {
+				changed = true
+			}
+			entries[ps.String()] = v2
+		}
+		if !changed {
+			return root, nil
+		}
+		return rebuildPartial(root, entries)
+	default:
+		return root, nil
+	}
+}
+
+// sameLink is like sameNode, but for links.
+func sameLink(a, b ipld.Link) bool {
+	if t := reflect.TypeOf(a); t != reflect.TypeOf(b) || t == nil || !t.Comparable() {
+		return false
+	}
+	return a == b
+}
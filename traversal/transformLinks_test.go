@@ -0,0 +1,83 @@
+package traversal_test
+
+import (
+	"fmt"
+	"testing"
+
+	. "github.com/warpfork/go-wish"
+
+	ipld "github.com/ipld/go-ipld-prime"
+	"github.com/ipld/go-ipld-prime/fluent"
+	basicnode "github.com/ipld/go-ipld-prime/node/basic"
+	"github.com/ipld/go-ipld-prime/traversal"
+)
+
+func TestTransformLinks(t *testing.T) {
+	// rehash maps each fixture link to the same content, encoded with another codec.
+	rehash := map[ipld.Link]ipld.Link{}
+	for _, n := range []ipld.Node{leafAlpha, leafBeta, middleMapNode} {
+		_, lnk := encode(n)
+		_, lnk2 := encodeWithCodec(n, 0x71)
+		rehash[lnk] = lnk2
+	}
+	fn := func(lnk ipld.Link) (ipld.Link, error) {
+		return rehash[lnk], nil
+	}
+
+	t.Run("map of links", func(t *testing.T) {
+		n := fluent.MustBuildMap(basicnode.Style__Map{}, 3, func(na fluent.MapAssembler) {
+			na.AssembleEntry("a").AssignLink(leafAlphaLnk)
+			na.AssembleEntry("b").AssignLink(leafBetaLnk)
+			na.AssembleEntry("m").AssignLink(middleMapNodeLnk)
+		})
+		n2, err := traversal.TransformLinks(n, fn)
+		Require(t, err, ShouldEqual, nil)
+		Wish(t, n2.Length(), ShouldEqual, 3)
+		for _, k := range []string{"a", "b", "m"} {
+			v, err := n.LookupString(k)
+			Require(t, err, ShouldEqual, nil)
+			lnk, _ := v.AsLink()
+			v2, err := n2.LookupString(k)
+			Require(t, err, ShouldEqual, nil)
+			lnk2, err := v2.AsLink()
+			Wish(t, err, ShouldEqual, nil)
+			Wish(t, lnk2, ShouldEqual, rehash[lnk])
+		}
+		// the original is untouched.
+		v, _ := n.LookupString("a")
+		lnk, _ := v.AsLink()
+		Wish(t, lnk, ShouldEqual, leafAlphaLnk)
+	})
+	t.Run("unchanged subtrees are shared", func(t *testing.T) {
+		n := fluent.MustBuildMap(basicnode.Style__Map{}, 2, func(na fluent.MapAssembler) {
+			na.AssembleEntry("plain").CreateList(1, func(na fluent.ListAssembler) {
+				na.AssembleValue().AssignString("x")
+			})
+			na.AssembleEntry("deep").CreateList(2, func(na fluent.ListAssembler) {
+				na.AssembleValue().AssignInt(1)
+				na.AssembleValue().AssignLink(leafBetaLnk)
+			})
+		})
+		n2, err := traversal.TransformLinks(n, fn)
+		Require(t, err, ShouldEqual, nil)
+		plain, _ := n.LookupString("plain")
+		plain2, _ := n2.LookupString("plain")
+		Wish(t, plain2 == plain, ShouldEqual, true)
+		deep, _ := n2.LookupString("deep")
+		v, err := deep.LookupIndex(1)
+		Require(t, err, ShouldEqual, nil)
+		lnk, _ := v.AsLink()
+		Wish(t, lnk, ShouldEqual, rehash[leafBetaLnk])
+
+		// with nothing to change, the root itself comes back.
+		n3, err := traversal.TransformLinks(plain, fn)
+		Wish(t, err, ShouldEqual, nil)
+		Wish(t, n3 == plain, ShouldEqual, true)
+	})
+	t.Run("errors from fn are returned", func(t *testing.T) {
+		_, err := traversal.TransformLinks(middleMapNode, func(ipld.Link) (ipld.Link, error) {
+			return nil, fmt.Errorf("nope")
+		})
+		Wish(t, err, ShouldEqual, fmt.Errorf("nope"))
+	})
+}
@@ -65,6 +65,12 @@ func WalkTransforming(n ipld.Node, s selector.Selector, fn TransformFn) (ipld.No
 // the Path recorded of the traversal so far will continue to be extended,
 // and thus continued nested uses of Walk and Focus will see the fully contextualized Path.
 //
+// The Progress handed to the VisitFn also carries MatchLabels:
+// the labels of the Matchers which selected the node.
+// A node is visited once per path even if it was matched by several Matchers
+// (e.g. in different branches of an ExploreUnion), and MatchLabels then lists each distinct label;
+// set Config.MatchPerLabel to instead get one visit per matching Matcher.
+//
 func (prog Progress) WalkMatching(n ipld.Node, s selector.Selector, fn VisitFn) error {
 	prog.init()
 	return prog.walkAdv(n, s, func(prog Progress, n ipld.Node, tr VisitReason) error {
@@ -79,18 +85,26 @@ func (prog Progress) WalkMatching(n ipld.Node, s selector.Selector, fn VisitFn)
 // visited (not just matching nodes), together with the reason for the visit.
 // An AdvVisitFn is used instead of a VisitFn, so that the reason can be provided.
 //
+// Since the AdvVisitFn is called for every node, returning SkipMe from it
+// can prune any subtree, not only ones below a match.
+//
 func (prog Progress) WalkAdv(n ipld.Node, s selector.Selector, fn AdvVisitFn) error {
 	prog.init()
 	return prog.walkAdv(n, s, fn)
 }
 
 func (prog Progress) walkAdv(n ipld.Node, s selector.Selector, fn AdvVisitFn) error {
-	if s.Decide(n) {
-		if err := fn(prog, n, VisitReason_SelectionMatch); err != nil {
-			return err
-		}
-	} else {
-		if err := fn(prog, n, VisitReason_SelectionCandidate); err != nil {
+	if err := prog.step(); err != nil {
+		return err
+	}
+	if prog.resuming && len(prog.resumeAt) == 0 {
+		prog.resuming = false // this is the Cursor's node; from here on, the walk is as usual.
+	}
+	if !prog.resuming { // (nodes on the path to a Cursor were visited, and reported, before it was made.)
+		if err := prog.visit(n, s, fn); err != nil {
+			if _, ok := err.(SkipMe); ok {
+				return nil // the visitor asked us not to descend here; the caller carries on with siblings.
+			}
 			return err
 		}
 	}
@@ -98,7 +112,7 @@ func (prog Progress) walkAdv(n ipld.Node, s selector.Selector, fn AdvVisitFn) er
 	switch nk {
 	case ipld.ReprKind_Map, ipld.ReprKind_List: // continue
 	default:
-		return nil
+		return prog.resumeDone(false)
 	}
 	attn := s.Interests()
 	if attn == nil {
@@ -108,16 +122,100 @@ func (prog Progress) walkAdv(n ipld.Node, s selector.Selector, fn AdvVisitFn) er
 
 }
 
+// visit counts and reports a node reached by walkAdv, and calls the visitor for it.
+// A match which the page has no room for returns pauseWalk before any of that,
+// so that it's counted and reported only by the page which visits it.
+func (prog Progress) visit(n ipld.Node, s selector.Selector, fn AdvVisitFn) error {
+	matched := selector.DecideAt(s, n, prog.Path)
+	if matched {
+		if err := prog.takeMatch(); err != nil {
+			return err
+		}
+	}
+	if prog.Cfg.Stats != nil {
+		prog.Cfg.Stats.NodesVisited++
+		if matched {
+			prog.Cfg.Stats.Matches++
+		}
+	}
+	if prog.Cfg.OnEvent != nil {
+		prog.Cfg.emit(EventKind_NodeVisited, prog.Path, nil)
+		if matched {
+			prog.Cfg.emit(EventKind_Matched, prog.Path, nil)
+		}
+	}
+	if matched {
+		labels := selector.DecideLabels(s, n)
+		if len(labels) == 0 {
+			labels = []string{""} // matched by position alone; see selector.DecideAt.
+		}
+		return prog.visitMatch(n, labels, fn)
+	}
+	return fn(prog, n, VisitReason_SelectionCandidate)
+}
+
+// visitMatch calls the visitor for a matched node, with Progress.MatchLabels set.
+//
+// By default, this is one call, with the labels deduplicated (a node matched
+// by two unlabelled Matchers in a union is still just one match).
+// If Config.MatchPerLabel is set, this is one call per label, in selector order,
+// without deduplication.
+func (prog Progress) visitMatch(n ipld.Node, labels []string, fn AdvVisitFn) error {
+	if prog.Cfg.MatchPerLabel {
+		var skip error
+		for _, label := range labels {
+			prog.MatchLabels = []string{label}
+			if err := fn(prog, n, VisitReason_SelectionMatch); err != nil {
+				if _, ok := err.(SkipMe); ok {
+					skip = err // still visit for the remaining labels; skip the subtree after.
+					continue
+				}
+				return err
+			}
+		}
+		return skip
+	}
+	prog.MatchLabels = dedupLabels(labels)
+	return fn(prog, n, VisitReason_SelectionMatch)
+}
+
+func dedupLabels(labels []string) []string {
+	if len(labels) < 2 {
+		return labels
+	}
+	result := make([]string, 0, len(labels))
+outer:
+	for _, label := range labels {
+		for _, seen := range result {
+			if seen == label {
+				continue outer
+			}
+		}
+		result = append(result, label)
+	}
+	return result
+}
+
 func (prog Progress) walkAdv_iterateAll(n ipld.Node, s selector.Selector, fn AdvVisitFn) error {
+	found := false
 	for itr := selector.NewSegmentIterator(n); !itr.Done(); {
 		ps, v, err := itr.Next()
 		if err != nil {
 			return err
 		}
+		progNext := prog
+		if prog.resumeChild(ps, &found, &progNext) {
+			continue
+		}
 		sNext := s.Explore(n, ps)
+		if sNext == nil && progNext.resuming {
+			return ErrCursorMismatch{prog.Path, ps}
+		}
 		if sNext != nil {
-			progNext := prog
 			progNext.Path = prog.Path.AppendSegment(ps)
+			if ks := selector.ExpectedKinds(s, ps); ks != nil && !ks.Contains(v.ReprKind()) {
+				return ErrUnexpectedKind{progNext.Path, ks, v.ReprKind()}
+			}
 			if v.ReprKind() == ipld.ReprKind_Link {
 				lnk, _ := v.AsLink()
 				progNext.LastBlock.Path = progNext.Path
@@ -125,7 +223,7 @@ func (prog Progress) walkAdv_iterateAll(n ipld.Node, s selector.Selector, fn Adv
 				v, err = progNext.loadLink(v, n)
 				if err != nil {
 					if _, ok := err.(SkipMe); ok {
-						return nil
+						continue
 					}
 					return err
 				}
@@ -137,19 +235,42 @@ func (prog Progress) walkAdv_iterateAll(n ipld.Node, s selector.Selector, fn Adv
 			}
 		}
 	}
-	return nil
+	return prog.resumeDone(found)
 }
 
 func (prog Progress) walkAdv_iterateSelective(n ipld.Node, attn []ipld.PathSegment, s selector.Selector, fn AdvVisitFn) error {
+	found := false
 	for _, ps := range attn {
+		progNext := prog
+		if prog.resumeChild(ps, &found, &progNext) {
+			continue
+		}
 		v, err := n.LookupSegment(ps)
-		if err != nil {
+		// An absent field of a struct (which looks up as Undef) is as missing as a key that's not in a map.
+		// (A field which is present but null is visited, as any other value is.)
+		// Under StrictInterests, an error which doesn't just mean the segment isn't there halts the walk;
+		// otherwise, a failed lookup selects nothing, like any other miss.
+		if err != nil && !isNotExists(err) && prog.Cfg.StrictInterests {
+			return err
+		}
+		if err != nil || v.IsUndefined() {
+			if progNext.resuming {
+				return ErrCursorMismatch{prog.Path, ps}
+			}
+			if prog.Cfg.StrictInterests {
+				return ErrSelectorMismatch{prog.Path, ps, n.ReprKind(), n.Length()}
+			}
 			continue
 		}
 		sNext := s.Explore(n, ps)
+		if sNext == nil && progNext.resuming {
+			return ErrCursorMismatch{prog.Path, ps}
+		}
 		if sNext != nil {
-			progNext := prog
 			progNext.Path = prog.Path.AppendSegment(ps)
+			if ks := selector.ExpectedKinds(s, ps); ks != nil && !ks.Contains(v.ReprKind()) {
+				return ErrUnexpectedKind{progNext.Path, ks, v.ReprKind()}
+			}
 			if v.ReprKind() == ipld.ReprKind_Link {
 				lnk, _ := v.AsLink()
 				progNext.LastBlock.Path = progNext.Path
@@ -157,7 +278,7 @@ func (prog Progress) walkAdv_iterateSelective(n ipld.Node, attn []ipld.PathSegme
 				v, err = progNext.loadLink(v, n)
 				if err != nil {
 					if _, ok := err.(SkipMe); ok {
-						return nil
+						continue
 					}
 					return err
 				}
@@ -169,7 +290,7 @@ func (prog Progress) walkAdv_iterateSelective(n ipld.Node, attn []ipld.PathSegme
 			}
 		}
 	}
-	return nil
+	return prog.resumeDone(found)
 }
 
 func (prog Progress) loadLink(v ipld.Node, parent ipld.Node) (ipld.Node, error) {
@@ -183,26 +304,26 @@ func (prog Progress) loadLink(v ipld.Node, parent ipld.Node) (ipld.Node, error)
 		LinkNode:   v,
 		ParentNode: parent,
 	}
+	if prog.Cfg.LinkLoader == nil {
+		return nil, ErrLinkLoaderRequired{Path: prog.Path, Link: lnk}
+	}
 	// Pick what in-memory format we will build.
 	ns, err := prog.Cfg.LinkTargetNodeStyleChooser(lnk, lnkCtx)
 	if err != nil {
+		if _, ok := err.(SkipMe); ok {
+			return nil, err
+		}
 		return nil, fmt.Errorf("error traversing node at %q: could not load link %q: %s", prog.Path, lnk, err)
 	}
-	nb := ns.NewBuilder()
-	// Load link!
-	err = lnk.Load(
-		prog.Cfg.Ctx,
-		lnkCtx,
-		nb,
-		prog.Cfg.LinkLoader,
-	)
+	// Load link!  (Or reuse it, if it's cached.)
+	n, err := prog.Cfg.loadLink(lnk, lnkCtx, ns)
 	if err != nil {
 		if _, ok := err.(SkipMe); ok {
 			return nil, err
 		}
 		return nil, fmt.Errorf("error traversing node at %q: could not load link %q: %s", prog.Path, lnk, err)
 	}
-	return nb.Build(), nil
+	return n, nil
 }
 
 // WalkTransforming walks a graph of Nodes, deciding which to alter by applying a Selector,
@@ -2,6 +2,7 @@ package traversal_test
 
 import (
 	"bytes"
+	"fmt"
 	"io"
 	"testing"
 
@@ -9,8 +10,12 @@ import (
 
 	ipld "github.com/ipld/go-ipld-prime"
 	_ "github.com/ipld/go-ipld-prime/codec/dagjson"
+	_ "github.com/ipld/go-ipld-prime/codec/raw"
 	"github.com/ipld/go-ipld-prime/fluent"
+	"github.com/ipld/go-ipld-prime/node/mixins"
 	basicnode "github.com/ipld/go-ipld-prime/node/basic"
+	"github.com/ipld/go-ipld-prime/node/gendemo"
+	"github.com/ipld/go-ipld-prime/schema"
 	"github.com/ipld/go-ipld-prime/traversal"
 	"github.com/ipld/go-ipld-prime/traversal/selector"
 	"github.com/ipld/go-ipld-prime/traversal/selector/builder"
@@ -264,3 +269,741 @@ func TestWalkMatching(t *testing.T) {
 		Wish(t, order, ShouldEqual, 7)
 	})
 }
+
+func TestWalkMatchingLabels(t *testing.T) {
+	// Two branches of a union both match the root node.
+	s := selector.ExploreUnion{[]selector.Selector{
+		selector.Matcher{Label: "first"},
+		selector.ExploreFields{}, // doesn't match anything itself.
+		selector.Matcher{Label: "second"},
+	}}
+	t.Run("dedup (default) visits once with all labels", func(t *testing.T) {
+		var visits [][]string
+		err := traversal.WalkMatching(basicnode.NewString("x"), s, func(prog traversal.Progress, n ipld.Node) error {
+			visits = append(visits, prog.MatchLabels)
+			return nil
+		})
+		Wish(t, err, ShouldEqual, nil)
+		Wish(t, visits, ShouldEqual, [][]string{{"first", "second"}})
+	})
+	t.Run("MatchPerLabel visits once per label", func(t *testing.T) {
+		var visits [][]string
+		err := traversal.Progress{Cfg: &traversal.Config{MatchPerLabel: true}}.WalkMatching(basicnode.NewString("x"), s, func(prog traversal.Progress, n ipld.Node) error {
+			visits = append(visits, prog.MatchLabels)
+			return nil
+		})
+		Wish(t, err, ShouldEqual, nil)
+		Wish(t, visits, ShouldEqual, [][]string{{"first"}, {"second"}})
+	})
+	t.Run("repeated labels are deduplicated by default", func(t *testing.T) {
+		s := selector.ExploreUnion{[]selector.Selector{selector.Matcher{}, selector.Matcher{}}}
+		var visits [][]string
+		err := traversal.WalkMatching(basicnode.NewString("x"), s, func(prog traversal.Progress, n ipld.Node) error {
+			visits = append(visits, prog.MatchLabels)
+			return nil
+		})
+		Wish(t, err, ShouldEqual, nil)
+		Wish(t, visits, ShouldEqual, [][]string{{""}})
+	})
+}
+
+func TestWalkMatchingKinds(t *testing.T) {
+	// Every int in a mixed tree, at any depth.
+	n := fluent.MustBuildMap(basicnode.Style__Map{}, 3, func(na fluent.MapAssembler) {
+		na.AssembleEntry("a").AssignInt(1)
+		na.AssembleEntry("b").AssignString("2")
+		na.AssembleEntry("c").CreateList(3, func(na fluent.ListAssembler) {
+			na.AssembleValue().AssignInt(3)
+			na.AssembleValue().AssignFloat(4)
+			na.AssembleValue().CreateMap(2, func(na fluent.MapAssembler) {
+				na.AssembleEntry("d").AssignInt(5)
+				na.AssembleEntry("e").AssignNull()
+			})
+		})
+	})
+	s, err := selector.ParseFromJSON(basicnode.Style__Any{}, []byte(`{"R": {"l": {"none": {}}, ":>": {"|": [{".": {"k": ["Int"]}}, {"a": {">": {"@": {}}}}]}}}`))
+	Wish(t, err, ShouldEqual, nil)
+	var visited []string
+	err = traversal.WalkMatching(n, s, func(prog traversal.Progress, n ipld.Node) error {
+		v, err := n.AsInt()
+		visited = append(visited, fmt.Sprintf("%s=%d", prog.Path, v))
+		return err
+	})
+	Wish(t, err, ShouldEqual, nil)
+	Wish(t, visited, ShouldEqual, []string{"a=1", "c/0=3", "c/2/d=5"})
+}
+
+func TestWalkMatchingLeaves(t *testing.T) {
+	// Every scalar in a nested tree, and none of the maps and lists containing them.
+	n := fluent.MustBuildMap(basicnode.Style__Map{}, 3, func(na fluent.MapAssembler) {
+		na.AssembleEntry("a").AssignInt(1)
+		na.AssembleEntry("b").CreateMap(2, func(na fluent.MapAssembler) {
+			na.AssembleEntry("c").AssignString("2")
+			na.AssembleEntry("d").CreateList(2, func(na fluent.ListAssembler) {
+				na.AssembleValue().AssignBool(true)
+				na.AssembleValue().CreateList(0, func(na fluent.ListAssembler) {})
+			})
+		})
+		na.AssembleEntry("e").AssignNull()
+	})
+	ssb := builder.NewSelectorSpecBuilder(basicnode.Style__Any{})
+	s, err := ssb.ExploreRecursive(selector.RecursionLimitNone(), ssb.ExploreUnion(
+		builder.MatchLeaves(ssb),
+		ssb.ExploreAll(ssb.ExploreRecursiveEdge()),
+	)).Selector()
+	Wish(t, err, ShouldEqual, nil)
+	var visited []string
+	err = traversal.WalkMatching(n, s, func(prog traversal.Progress, n ipld.Node) error {
+		visited = append(visited, fmt.Sprintf("%s=%s", prog.Path, ipld.Sprint(n)))
+		return nil
+	})
+	Wish(t, err, ShouldEqual, nil)
+	Wish(t, visited, ShouldEqual, []string{"a=1", "b/c=\"2\"", "b/d/0=true", "e=null"})
+}
+
+func TestWalkMatchingNull(t *testing.T) {
+	ssb := builder.NewSelectorSpecBuilder(basicnode.Style__Any{})
+	visit := func(n ipld.Node, s selector.Selector, cfg *traversal.Config) ([]string, error) {
+		var visited []string
+		err := traversal.Progress{Cfg: cfg}.WalkMatching(n, s, func(prog traversal.Progress, n ipld.Node) error {
+			visited = append(visited, fmt.Sprintf("%s=%s (null: %v)", prog.Path, ipld.Sprint(n), n.IsNull()))
+			return nil
+		})
+		return visited, err
+	}
+	t.Run("ExploreFields visits a field which is present and null", func(t *testing.T) {
+		n := fluent.MustBuildMap(basicnode.Style__Map{}, 2, func(na fluent.MapAssembler) {
+			na.AssembleEntry("a").AssignNull()
+			na.AssembleEntry("b").AssignInt(1)
+		})
+		s, err := ssb.ExploreFields(func(efsb builder.ExploreFieldsSpecBuilder) {
+			efsb.Insert("a", ssb.Matcher())
+			efsb.Insert("c", ssb.Matcher())
+		}).Selector()
+		Require(t, err, ShouldEqual, nil)
+		visited, err := visit(n, s, nil)
+		Wish(t, err, ShouldEqual, nil)
+		Wish(t, visited, ShouldEqual, []string{"a=null (null: true)"})
+	})
+	t.Run("MatchNull matches only the null values", func(t *testing.T) {
+		n := fluent.MustBuildMap(basicnode.Style__Map{}, 3, func(na fluent.MapAssembler) {
+			na.AssembleEntry("a").AssignNull()
+			na.AssembleEntry("b").AssignInt(1)
+			na.AssembleEntry("c").AssignNull()
+		})
+		s, err := ssb.ExploreAll(builder.MatchNull(ssb)).Selector()
+		Require(t, err, ShouldEqual, nil)
+		visited, err := visit(n, s, nil)
+		Wish(t, err, ShouldEqual, nil)
+		Wish(t, visited, ShouldEqual, []string{"a=null (null: true)", "c=null (null: true)"})
+	})
+	t.Run("an absent struct field isn't visited", func(t *testing.T) {
+		// The zero S has its optional field, b, absent; it looks up as ipld.Undef.
+		s, err := ssb.ExploreFields(func(efsb builder.ExploreFieldsSpecBuilder) {
+			efsb.Insert("a", ssb.Matcher())
+			efsb.Insert("b", ssb.Matcher())
+		}).Selector()
+		Require(t, err, ShouldEqual, nil)
+		visited, err := visit(&gendemo.S{}, s, nil)
+		Wish(t, err, ShouldEqual, nil)
+		Wish(t, visited, ShouldEqual, []string{`a="" (null: false)`})
+
+		_, err = visit(&gendemo.S{}, s, &traversal.Config{StrictInterests: true})
+		Wish(t, err, ShouldEqual, traversal.ErrSelectorMismatch{ipld.Path{}, ipld.PathSegmentOfString("b"), ipld.ReprKind_Map, 1})
+	})
+}
+
+func TestWalkMatchingSegments(t *testing.T) {
+	// The segment by which each node was reached is the last in its Path,
+	// whether it's a list index or a map key.
+	ssb := builder.NewSelectorSpecBuilder(basicnode.Style__Any{})
+	s, err := ssb.ExploreAll(ssb.Matcher()).Selector()
+	Require(t, err, ShouldEqual, nil)
+	record := func(n ipld.Node) (indexes []int, keys []string) {
+		err := traversal.WalkMatching(n, s, func(prog traversal.Progress, n ipld.Node) error {
+			seg, ok := prog.Path.Last()
+			Wish(t, ok, ShouldEqual, true)
+			if idx, err := seg.Index(); err == nil {
+				indexes = append(indexes, idx)
+			} else {
+				keys = append(keys, seg.String())
+			}
+			return nil
+		})
+		Wish(t, err, ShouldEqual, nil)
+		return
+	}
+	t.Run("list elements", func(t *testing.T) {
+		indexes, keys := record(fluent.MustBuildList(basicnode.Style__List{}, 3, func(na fluent.ListAssembler) {
+			na.AssembleValue().AssignString("a")
+			na.AssembleValue().AssignString("b")
+			na.AssembleValue().AssignString("c")
+		}))
+		Wish(t, indexes, ShouldEqual, []int{0, 1, 2})
+		Wish(t, keys, ShouldEqual, []string(nil))
+	})
+	t.Run("map entries", func(t *testing.T) {
+		indexes, keys := record(fluent.MustBuildMap(basicnode.Style__Map{}, 2, func(na fluent.MapAssembler) {
+			na.AssembleEntry("foo").AssignBool(true)
+			na.AssembleEntry("bar").AssignBool(false)
+		}))
+		Wish(t, indexes, ShouldEqual, []int(nil))
+		Wish(t, keys, ShouldEqual, []string{"foo", "bar"})
+	})
+	t.Run("the root has no segment", func(t *testing.T) {
+		_, ok := traversal.Progress{}.Path.Last()
+		Wish(t, ok, ShouldEqual, false)
+	})
+}
+
+// exploreEvery explores every element, as ExploreAll does, to the same next selector.
+type exploreEvery struct{ next selector.Selector }
+
+func (exploreEvery) Interests() []ipld.PathSegment                           { return nil }
+func (s exploreEvery) Explore(ipld.Node, ipld.PathSegment) selector.Selector { return s.next }
+func (exploreEvery) Decide(ipld.Node) bool                                   { return false }
+func (exploreEvery) String() string                                          { return "exploreEvery" }
+
+// matchEvenIndexes decides by position: it matches the elements of a list at even indexes.
+type matchEvenIndexes struct{}
+
+func (matchEvenIndexes) Interests() []ipld.PathSegment                         { return []ipld.PathSegment{} }
+func (matchEvenIndexes) Explore(ipld.Node, ipld.PathSegment) selector.Selector { return nil }
+func (matchEvenIndexes) Decide(ipld.Node) bool                                 { return false }
+func (matchEvenIndexes) String() string                                        { return "matchEvenIndexes" }
+func (matchEvenIndexes) DecideAt(_ ipld.Node, p ipld.Path) bool {
+	seg, ok := p.Last()
+	if !ok {
+		return false
+	}
+	idx, err := seg.Index()
+	return err == nil && idx%2 == 0
+}
+
+func TestWalkMatchingByPosition(t *testing.T) {
+	n := fluent.MustBuildList(basicnode.Style__List{}, 5, func(na fluent.ListAssembler) {
+		for _, s := range []string{"a", "b", "c", "d", "e"} {
+			na.AssembleValue().AssignString(s)
+		}
+	})
+	visit := func(s selector.Selector) (visited []string) {
+		err := traversal.WalkMatching(n, s, func(prog traversal.Progress, n ipld.Node) error {
+			visited = append(visited, prog.Path.String()+"="+ipld.Sprint(n))
+			return nil
+		})
+		Wish(t, err, ShouldEqual, nil)
+		return
+	}
+	want := []string{`0="a"`, `2="c"`, `4="e"`}
+	t.Run("directly", func(t *testing.T) {
+		Wish(t, visit(exploreEvery{matchEvenIndexes{}}), ShouldEqual, want)
+	})
+	t.Run("inside a union", func(t *testing.T) {
+		Wish(t, visit(exploreEvery{selector.ExploreUnion{Members: []selector.Selector{matchEvenIndexes{}}}}), ShouldEqual, want)
+	})
+	t.Run("Decide alone doesn't see it", func(t *testing.T) {
+		Wish(t, matchEvenIndexes{}.Decide(n), ShouldEqual, false)
+		Wish(t, selector.DecideAt(matchEvenIndexes{}, n, ipld.ParsePath("2")), ShouldEqual, true)
+		Wish(t, selector.DecideAt(matchEvenIndexes{}, n, ipld.ParsePath("3")), ShouldEqual, false)
+	})
+}
+
+func TestWalkStrictInterests(t *testing.T) {
+	ssb := builder.NewSelectorSpecBuilder(basicnode.Style__Any{})
+	n := fluent.MustBuildMap(basicnode.Style__Map{}, 1, func(na fluent.MapAssembler) {
+		na.AssembleEntry("list").CreateList(3, func(na fluent.ListAssembler) {
+			na.AssembleValue().AssignInt(0)
+			na.AssembleValue().AssignInt(1)
+			na.AssembleValue().AssignInt(2)
+		})
+	})
+	s, err := ssb.ExploreFields(func(efsb builder.ExploreFieldsSpecBuilder) {
+		efsb.Insert("list", ssb.ExploreIndex(5, ssb.Matcher()))
+	}).Selector()
+	Require(t, err, ShouldEqual, nil)
+	visit := func(prog traversal.Progress, n ipld.Node) error {
+		t.Errorf("nothing should be visited")
+		return nil
+	}
+	t.Run("misses select nothing by default", func(t *testing.T) {
+		Wish(t, traversal.WalkMatching(n, s, visit), ShouldEqual, nil)
+	})
+	t.Run("misses error when strict", func(t *testing.T) {
+		err := traversal.Progress{Cfg: &traversal.Config{StrictInterests: true}}.WalkMatching(n, s, visit)
+		Wish(t, err, ShouldEqual, traversal.ErrSelectorMismatch{ipld.ParsePath("list"), ipld.PathSegmentOfInt(5), ipld.ReprKind_List, 3})
+		Wish(t, err.Error(), ShouldEqual, `selector targeted index 5 but list length 3 (at path "list")`)
+	})
+	t.Run("missing fields error when strict", func(t *testing.T) {
+		s, err := ssb.ExploreFields(func(efsb builder.ExploreFieldsSpecBuilder) {
+			efsb.Insert("nope", ssb.Matcher())
+		}).Selector()
+		Require(t, err, ShouldEqual, nil)
+		Wish(t, traversal.WalkMatching(n, s, visit), ShouldEqual, nil)
+		err = traversal.Progress{Cfg: &traversal.Config{StrictInterests: true}}.WalkMatching(n, s, visit)
+		Wish(t, err, ShouldEqual, traversal.ErrSelectorMismatch{ipld.Path{}, ipld.PathSegmentOfString("nope"), ipld.ReprKind_Map, 1})
+		Wish(t, err.Error(), ShouldEqual, `selector targeted field "nope" but map has no such key`)
+	})
+	t.Run("failed lookups are misses by default, and errors when strict", func(t *testing.T) {
+		s, err := ssb.ExploreFields(func(efsb builder.ExploreFieldsSpecBuilder) {
+			efsb.Insert("list", ssb.Matcher())
+		}).Selector()
+		Require(t, err, ShouldEqual, nil)
+		boom := fmt.Errorf("boom")
+		n := failingLookupNode{n, boom}
+		Wish(t, traversal.WalkMatching(n, s, visit), ShouldEqual, nil)
+		err = traversal.Progress{Cfg: &traversal.Config{StrictInterests: true}}.WalkMatching(n, s, visit)
+		Wish(t, err, ShouldEqual, boom)
+	})
+	t.Run("fields a typed struct lacks are misses", func(t *testing.T) {
+		typ := schema.SpawnStruct("S",
+			[]schema.StructField{schema.SpawnStructField("a", schema.SpawnInt("Int"), false, false)},
+			schema.SpawnStructRepresentationMap(nil),
+		)
+		nb := schema.Style__TypedStruct{typ, basicnode.Style__Any{}}.NewBuilder()
+		ma, err := nb.BeginMap(1)
+		Require(t, err, ShouldEqual, nil)
+		Require(t, ma.AssembleKey().AssignString("a"), ShouldEqual, nil)
+		Require(t, ma.AssembleValue().AssignInt(1), ShouldEqual, nil)
+		Require(t, ma.Finish(), ShouldEqual, nil)
+		n := nb.Build()
+		s, err := ssb.ExploreFields(func(efsb builder.ExploreFieldsSpecBuilder) {
+			efsb.Insert("other", ssb.Matcher())
+		}).Selector()
+		Require(t, err, ShouldEqual, nil)
+		Wish(t, traversal.WalkMatching(n, s, visit), ShouldEqual, nil)
+		err = traversal.Progress{Cfg: &traversal.Config{StrictInterests: true}}.WalkMatching(n, s, visit)
+		Wish(t, err, ShouldEqual, traversal.ErrSelectorMismatch{ipld.Path{}, ipld.PathSegmentOfString("other"), ipld.ReprKind_Map, 1})
+	})
+	t.Run("missing nested fields report their path when strict", func(t *testing.T) {
+		n := fluent.MustBuildMap(basicnode.Style__Map{}, 1, func(na fluent.MapAssembler) {
+			na.AssembleEntry("outer").CreateMap(1, func(na fluent.MapAssembler) {
+				na.AssembleEntry("present").AssignBool(true)
+			})
+		})
+		s, err := ssb.ExploreFields(func(efsb builder.ExploreFieldsSpecBuilder) {
+			efsb.Insert("outer", ssb.ExploreFields(func(efsb builder.ExploreFieldsSpecBuilder) {
+				efsb.Insert("present", ssb.Matcher())
+				efsb.Insert("absent", ssb.Matcher())
+			}))
+		}).Selector()
+		Require(t, err, ShouldEqual, nil)
+		var visited []string
+		visitPaths := func(prog traversal.Progress, n ipld.Node) error {
+			visited = append(visited, prog.Path.String())
+			return nil
+		}
+		Wish(t, traversal.WalkMatching(n, s, visitPaths), ShouldEqual, nil)
+		Wish(t, visited, ShouldEqual, []string{"outer/present"})
+		err = traversal.Progress{Cfg: &traversal.Config{StrictInterests: true}}.WalkMatching(n, s, visitPaths)
+		Wish(t, err.Error(), ShouldEqual, `selector targeted field "absent" but map has no such key (at path "outer")`)
+	})
+	t.Run("fields targeted on a list error when strict", func(t *testing.T) {
+		s, err := ssb.ExploreFields(func(efsb builder.ExploreFieldsSpecBuilder) {
+			efsb.Insert("list", ssb.ExploreFields(func(efsb builder.ExploreFieldsSpecBuilder) {
+				efsb.Insert("x", ssb.Matcher())
+			}))
+		}).Selector()
+		Require(t, err, ShouldEqual, nil)
+		err = traversal.Progress{Cfg: &traversal.Config{StrictInterests: true}}.WalkMatching(n, s, visit)
+		Wish(t, err.Error(), ShouldEqual, `selector targeted "x" but node is of kind List (at path "list")`)
+	})
+}
+
+func TestWalkSkipSubtree(t *testing.T) {
+	ssb := builder.NewSelectorSpecBuilder(basicnode.Style__Any{})
+	s, err := ssb.ExploreRecursive(selector.RecursionLimitNone(), ssb.ExploreUnion(
+		ssb.Matcher(),
+		ssb.ExploreAll(ssb.ExploreRecursiveEdge()),
+	)).Selector()
+	Require(t, err, ShouldEqual, nil)
+	n := fluent.MustBuildMap(basicnode.Style__Map{}, 3, func(na fluent.MapAssembler) {
+		na.AssembleEntry("a").CreateMap(1, func(na fluent.MapAssembler) {
+			na.AssembleEntry("x").AssignInt(1)
+		})
+		na.AssembleEntry("skip").CreateMap(2, func(na fluent.MapAssembler) {
+			na.AssembleEntry("y").AssignInt(2)
+			na.AssembleEntry("z").CreateList(1, func(na fluent.ListAssembler) {
+				na.AssembleValue().AssignInt(3)
+			})
+		})
+		na.AssembleEntry("b").AssignString("c")
+	})
+	t.Run("WalkMatching prunes the subtree and continues with siblings", func(t *testing.T) {
+		var visited []string
+		err := traversal.WalkMatching(n, s, func(prog traversal.Progress, n ipld.Node) error {
+			visited = append(visited, prog.Path.String())
+			if prog.Path.String() == "skip" {
+				return traversal.SkipMe{}
+			}
+			return nil
+		})
+		Wish(t, err, ShouldEqual, nil)
+		Wish(t, visited, ShouldEqual, []string{"", "a", "a/x", "skip", "b"})
+	})
+	t.Run("WalkAdv can prune below non-matching nodes", func(t *testing.T) {
+		s, err := ssb.ExploreRecursive(selector.RecursionLimitNone(), ssb.ExploreAll(ssb.ExploreRecursiveEdge())).Selector()
+		Require(t, err, ShouldEqual, nil)
+		var visited []string
+		err = traversal.WalkAdv(n, s, func(prog traversal.Progress, n ipld.Node, tr traversal.VisitReason) error {
+			Wish(t, tr, ShouldEqual, traversal.VisitReason_SelectionCandidate)
+			visited = append(visited, prog.Path.String())
+			if prog.Path.String() == "skip" {
+				return traversal.SkipMe{}
+			}
+			return nil
+		})
+		Wish(t, err, ShouldEqual, nil)
+		Wish(t, visited, ShouldEqual, []string{"", "a", "a/x", "skip", "b"})
+	})
+	t.Run("other errors still halt the walk", func(t *testing.T) {
+		err := traversal.WalkMatching(n, s, func(prog traversal.Progress, n ipld.Node) error {
+			if prog.Path.String() == "skip" {
+				return fmt.Errorf("stop")
+			}
+			return nil
+		})
+		Wish(t, err, ShouldEqual, fmt.Errorf("stop"))
+	})
+	t.Run("a LinkLoader's SkipMe also continues with siblings", func(t *testing.T) {
+		var visited []string
+		err := traversal.Progress{
+			Cfg: &traversal.Config{
+				LinkLoader: func(lnk ipld.Link, _ ipld.LinkContext) (io.Reader, error) {
+					if lnk == leafAlphaLnk {
+						return nil, traversal.SkipMe{}
+					}
+					return bytes.NewBuffer(storage[lnk]), nil
+				},
+				LinkTargetNodeStyleChooser: func(_ ipld.Link, _ ipld.LinkContext) (ipld.NodeStyle, error) {
+					return basicnode.Style__Any{}, nil
+				},
+			},
+		}.WalkMatching(middleListNode, s, func(prog traversal.Progress, n ipld.Node) error {
+			visited = append(visited, prog.Path.String())
+			return nil
+		})
+		Wish(t, err, ShouldEqual, nil)
+		Wish(t, visited, ShouldEqual, []string{"", "2"})
+	})
+}
+
+func TestWalkMixedCodecs(t *testing.T) {
+	// The decoder for each block is chosen by the codec in its link,
+	// so one traversal can cross dag-json and raw blocks alike.
+	rawLeaf, rawLeafLnk := encodeWithCodec(basicnode.NewBytes([]byte("raw leaf")), 0x55)
+	root := fluent.MustBuildMap(basicnode.Style__Map{}, 2, func(na fluent.MapAssembler) {
+		na.AssembleEntry("json").AssignLink(leafAlphaLnk)
+		na.AssembleEntry("raw").AssignLink(rawLeafLnk)
+	})
+	ssb := builder.NewSelectorSpecBuilder(basicnode.Style__Any{})
+	s, err := ssb.ExploreAll(ssb.Matcher()).Selector()
+	Require(t, err, ShouldEqual, nil)
+	var visited []ipld.Node
+	err = traversal.Progress{
+		Cfg: &traversal.Config{
+			LinkLoader: func(lnk ipld.Link, _ ipld.LinkContext) (io.Reader, error) {
+				return bytes.NewBuffer(storage[lnk]), nil
+			},
+			LinkTargetNodeStyleChooser: func(_ ipld.Link, _ ipld.LinkContext) (ipld.NodeStyle, error) {
+				return basicnode.Style__Any{}, nil
+			},
+		},
+	}.WalkMatching(root, s, func(prog traversal.Progress, n ipld.Node) error {
+		visited = append(visited, n)
+		return nil
+	})
+	Wish(t, err, ShouldEqual, nil)
+	Wish(t, visited, ShouldEqual, []ipld.Node{leafAlpha, rawLeaf})
+	Wish(t, visited[1].ReprKind(), ShouldEqual, ipld.ReprKind_Bytes)
+}
+
+func TestWalkIntoRawLeaf(t *testing.T) {
+	// A raw block decodes to a plain bytes node, so selectors can't descend into it:
+	// exploring it just finds nothing, rather than erroring.
+	_, rawLeafLnk := encodeWithCodec(basicnode.NewBytes([]byte("raw leaf")), 0x55)
+	root := fluent.MustBuildMap(basicnode.Style__Map{}, 1, func(na fluent.MapAssembler) {
+		na.AssembleEntry("raw").AssignLink(rawLeafLnk)
+	})
+	ssb := builder.NewSelectorSpecBuilder(basicnode.Style__Any{})
+	s, err := ssb.ExploreFields(func(efsb builder.ExploreFieldsSpecBuilder) {
+		efsb.Insert("raw", ssb.ExploreAll(ssb.Matcher()))
+	}).Selector()
+	Require(t, err, ShouldEqual, nil)
+	var visited []string
+	err = traversal.Progress{
+		Cfg: &traversal.Config{
+			LinkLoader: func(lnk ipld.Link, _ ipld.LinkContext) (io.Reader, error) {
+				return bytes.NewBuffer(storage[lnk]), nil
+			},
+			LinkTargetNodeStyleChooser: func(_ ipld.Link, _ ipld.LinkContext) (ipld.NodeStyle, error) {
+				return basicnode.Style__Any{}, nil
+			},
+		},
+	}.WalkAdv(root, s, func(prog traversal.Progress, n ipld.Node, tr traversal.VisitReason) error {
+		visited = append(visited, fmt.Sprintf("%s %s %c", prog.Path, n.ReprKind(), tr))
+		return nil
+	})
+	Wish(t, err, ShouldEqual, nil)
+	Wish(t, visited, ShouldEqual, []string{" Map x", "raw Bytes x"})
+}
+
+func TestWalkExpectedKinds(t *testing.T) {
+	s, err := selector.ParseFromJSON(basicnode.Style__Any{}, []byte(`{"f": {"f>": {"a": {".": {}}}, "k": {"a": ["Int"]}}}`))
+	Require(t, err, ShouldEqual, nil)
+	t.Run("field of the expected kind is visited", func(t *testing.T) {
+		n := fluent.MustBuildMap(basicnode.Style__Map{}, 1, func(na fluent.MapAssembler) {
+			na.AssembleEntry("a").AssignInt(1)
+		})
+		var visited []string
+		err := traversal.WalkMatching(n, s, func(prog traversal.Progress, n ipld.Node) error {
+			visited = append(visited, prog.Path.String())
+			return nil
+		})
+		Wish(t, err, ShouldEqual, nil)
+		Wish(t, visited, ShouldEqual, []string{"a"})
+	})
+	t.Run("field of another kind halts the walk", func(t *testing.T) {
+		n := fluent.MustBuildMap(basicnode.Style__Map{}, 1, func(na fluent.MapAssembler) {
+			na.AssembleEntry("a").AssignString("1")
+		})
+		err := traversal.WalkMatching(n, s, func(prog traversal.Progress, n ipld.Node) error {
+			t.Errorf("nothing should be visited")
+			return nil
+		})
+		Wish(t, err, ShouldEqual, traversal.ErrUnexpectedKind{ipld.ParsePath("a"), ipld.ReprKindSet_JustInt, ipld.ReprKind_String})
+		Wish(t, err.Error(), ShouldEqual, `selector requires node at "a" to be Int, but it is String`)
+	})
+	t.Run("kinds are checked within unions and recursions", func(t *testing.T) {
+		n := fluent.MustBuildMap(basicnode.Style__Map{}, 1, func(na fluent.MapAssembler) {
+			na.AssembleEntry("a").CreateMap(1, func(na fluent.MapAssembler) {
+				na.AssembleEntry("a").AssignString("1")
+			})
+		})
+		for _, tc := range []struct {
+			spec string
+			want error
+		}{
+			{`{"|": [{".": {}}, {"f": {"f>": {"a": {".": {}}}, "k": {"a": ["Int", "Bool"]}}}, {"f": {"f>": {"a": {".": {}}}, "k": {"a": ["Int", "List"]}}}]}`,
+				traversal.ErrUnexpectedKind{ipld.ParsePath("a"), ipld.ReprKindSet{ipld.ReprKind_Int, ipld.ReprKind_Bool, ipld.ReprKind_List}, ipld.ReprKind_Map}},
+			{`{"R": {"l": {"none": {}}, ":>": {"f": {"f>": {"a": {"@": {}}}, "k": {"a": ["Map", "Int"]}}}}}`,
+				traversal.ErrUnexpectedKind{ipld.ParsePath("a/a"), ipld.ReprKindSet{ipld.ReprKind_Map, ipld.ReprKind_Int}, ipld.ReprKind_String}},
+		} {
+			s, err := selector.ParseFromJSON(basicnode.Style__Any{}, []byte(tc.spec))
+			Require(t, err, ShouldEqual, nil)
+			err = traversal.WalkMatching(n, s, func(prog traversal.Progress, n ipld.Node) error { return nil })
+			Wish(t, err, ShouldEqual, tc.want)
+		}
+	})
+}
+
+// failingLookupNode is a node whose LookupSegment always fails (as, say, a lazily decoded node might).
+type failingLookupNode struct {
+	ipld.Node
+	err error
+}
+
+func (n failingLookupNode) LookupSegment(ipld.PathSegment) (ipld.Node, error) {
+	return nil, n.err
+}
+
+// cyclicNode is a (deliberately broken) map which contains itself, under the key "self".
+type cyclicNode struct {
+	mixins.Map
+}
+
+func (n *cyclicNode) LookupString(key string) (ipld.Node, error) {
+	if key != "self" {
+		return nil, ipld.ErrNotExists{ipld.PathSegmentOfString(key)}
+	}
+	return n, nil
+}
+func (n *cyclicNode) Lookup(key ipld.Node) (ipld.Node, error) {
+	ks, err := key.AsString()
+	if err != nil {
+		return nil, err
+	}
+	return n.LookupString(ks)
+}
+func (n *cyclicNode) LookupSegment(seg ipld.PathSegment) (ipld.Node, error) {
+	return n.LookupString(seg.String())
+}
+func (n *cyclicNode) MapIterator() ipld.MapIterator {
+	return &cyclicNodeIterator{n, false}
+}
+func (n *cyclicNode) Length() int {
+	return 1
+}
+func (n *cyclicNode) Style() ipld.NodeStyle {
+	return basicnode.Style__Map{}
+}
+
+type cyclicNodeIterator struct {
+	n    *cyclicNode
+	done bool
+}
+
+func (itr *cyclicNodeIterator) Next() (ipld.Node, ipld.Node, error) {
+	if itr.done {
+		return nil, nil, ipld.ErrIteratorOverread{}
+	}
+	itr.done = true
+	return basicnode.NewString("self"), itr.n, nil
+}
+func (itr *cyclicNodeIterator) Done() bool {
+	return itr.done
+}
+
+func TestWalkMaxSteps(t *testing.T) {
+	ssb := builder.NewSelectorSpecBuilder(basicnode.Style__Any{})
+	s, err := ssb.ExploreRecursive(selector.RecursionLimitNone(), ssb.ExploreAll(ssb.ExploreRecursiveEdge())).Selector()
+	Require(t, err, ShouldEqual, nil)
+	cfg := &traversal.Config{MaxSteps: 5}
+
+	t.Run("a cyclic node halts the walk", func(t *testing.T) {
+		var visits int
+		err := traversal.Progress{Cfg: cfg}.WalkAdv(&cyclicNode{}, s, func(traversal.Progress, ipld.Node, traversal.VisitReason) error {
+			visits++
+			return nil
+		})
+		Wish(t, err, ShouldEqual, traversal.ErrRecursionLimit{ipld.ParsePath("self/self/self/self/self"), 5})
+		Wish(t, visits, ShouldEqual, 5)
+	})
+	t.Run("and so does pruning it", func(t *testing.T) {
+		_, err := traversal.Progress{Cfg: cfg}.Prune(&cyclicNode{}, s)
+		Wish(t, err, ShouldBeSameTypeAs, traversal.ErrRecursionLimit{})
+	})
+	t.Run("walks within the limit are unaffected", func(t *testing.T) {
+		n := fluent.MustBuildMap(basicnode.Style__Map{}, 2, func(na fluent.MapAssembler) {
+			na.AssembleEntry("a").AssignInt(1)
+			na.AssembleEntry("b").CreateList(2, func(na fluent.ListAssembler) {
+				na.AssembleValue().AssignInt(2)
+				na.AssembleValue().AssignInt(3)
+			})
+		})
+		var visits int
+		err := traversal.Progress{Cfg: cfg}.WalkAdv(n, s, func(traversal.Progress, ipld.Node, traversal.VisitReason) error {
+			visits++
+			return nil
+		})
+		Wish(t, err, ShouldEqual, nil)
+		Wish(t, visits, ShouldEqual, 5)
+	})
+}
+
+func TestWalkLinkLoaderRequired(t *testing.T) {
+	ssb := builder.NewSelectorSpecBuilder(basicnode.Style__Any{})
+	s, err := ssb.ExploreFields(func(efsb builder.ExploreFieldsSpecBuilder) {
+		efsb.Insert("plain", ssb.Matcher())
+		efsb.Insert("linkedMap", ssb.ExploreAll(ssb.Matcher()))
+	}).Selector()
+	Require(t, err, ShouldEqual, nil)
+	var order []string
+	err = traversal.WalkMatching(rootNode, s, func(prog traversal.Progress, n ipld.Node) error {
+		order = append(order, prog.Path.String())
+		return nil
+	})
+	Wish(t, err, ShouldEqual, traversal.ErrLinkLoaderRequired{Path: ipld.ParsePath("linkedMap"), Link: middleMapNodeLnk})
+	Wish(t, order, ShouldEqual, []string{"plain"})
+}
+
+func TestWalkMatchingFrom(t *testing.T) {
+	ssb := builder.NewSelectorSpecBuilder(basicnode.Style__Any{})
+	t.Run("resuming a list walk page by page", func(t *testing.T) {
+		n := fluent.MustBuildList(basicnode.Style__List{}, 8, func(na fluent.ListAssembler) {
+			for i := 0; i < 8; i++ {
+				na.AssembleValue().AssignInt(i * 10)
+			}
+		})
+		s, err := ssb.ExploreAll(ssb.Matcher()).Selector()
+		Require(t, err, ShouldEqual, nil)
+		var pages [][]string
+		var cursors []string
+		var cursor *traversal.Cursor
+		for {
+			var page []string
+			cursor, err = traversal.WalkMatchingFrom(n, s, cursor, 3, func(prog traversal.Progress, n ipld.Node) error {
+				page = append(page, prog.Path.String()+"="+ipld.Sprint(n))
+				return nil
+			})
+			Require(t, err, ShouldEqual, nil)
+			pages = append(pages, page)
+			if cursor == nil {
+				break
+			}
+			cursors = append(cursors, cursor.String())
+			// Round-trip it through its serial form, as a caller saving it between requests would.
+			c := traversal.ParseCursor(cursor.String())
+			cursor = &c
+		}
+		Wish(t, pages, ShouldEqual, [][]string{
+			{"0=0", "1=10", "2=20"},
+			{"3=30", "4=40", "5=50"},
+			{"6=60", "7=70"},
+		})
+		Wish(t, cursors, ShouldEqual, []string{"3", "6"})
+	})
+	t.Run("pages of a recursive walk add up to the whole walk", func(t *testing.T) {
+		n := fluent.MustBuildMap(basicnode.Style__Map{}, 3, func(na fluent.MapAssembler) {
+			na.AssembleEntry("a").CreateList(2, func(na fluent.ListAssembler) {
+				na.AssembleValue().AssignInt(1)
+				na.AssembleValue().CreateMap(1, func(na fluent.MapAssembler) {
+					na.AssembleEntry("b").AssignInt(2)
+				})
+			})
+			na.AssembleEntry("c").CreateMap(1, func(na fluent.MapAssembler) {
+				na.AssembleEntry("d").CreateList(2, func(na fluent.ListAssembler) {
+					na.AssembleValue().AssignInt(3)
+					na.AssembleValue().AssignInt(4)
+				})
+			})
+			na.AssembleEntry("e").AssignInt(5)
+		})
+		s, err := ssb.ExploreRecursive(selector.RecursionLimitNone(), ssb.ExploreUnion(ssb.Matcher(), ssb.ExploreAll(ssb.ExploreRecursiveEdge()))).Selector()
+		Require(t, err, ShouldEqual, nil)
+		// Stats and events too should add up: nodes replayed on the way back to a Cursor,
+		// and the match a page pauses on, aren't counted or reported again.
+		var stats traversal.WalkStats
+		var events []string
+		cfg := &traversal.Config{
+			Stats: &stats,
+			OnEvent: func(ev traversal.Event) {
+				events = append(events, string(ev.Kind)+":"+ev.Path.String())
+			},
+		}
+		var whole []string
+		err = traversal.Progress{Cfg: cfg}.WalkMatching(n, s, func(prog traversal.Progress, n ipld.Node) error {
+			whole = append(whole, prog.Path.String())
+			return nil
+		})
+		Require(t, err, ShouldEqual, nil)
+		Require(t, len(whole), ShouldEqual, 10)
+		Require(t, stats.Matches, ShouldEqual, 10)
+		wholeStats, wholeEvents := stats, events
+		for limit := 1; limit <= len(whole); limit++ {
+			stats, events = traversal.WalkStats{}, nil
+			var paged []string
+			var cursor *traversal.Cursor
+			for {
+				cursor, err = traversal.Progress{Cfg: cfg}.WalkMatchingFrom(n, s, cursor, limit, func(prog traversal.Progress, n ipld.Node) error {
+					paged = append(paged, prog.Path.String())
+					return nil
+				})
+				Require(t, err, ShouldEqual, nil)
+				if cursor == nil {
+					break
+				}
+			}
+			Wish(t, paged, ShouldEqual, whole)
+			Wish(t, stats, ShouldEqual, wholeStats)
+			Wish(t, events, ShouldEqual, wholeEvents)
+		}
+	})
+	t.Run("cursor from other data", func(t *testing.T) {
+		s, err := ssb.ExploreAll(ssb.Matcher()).Selector()
+		Require(t, err, ShouldEqual, nil)
+		c := traversal.ParseCursor("nope")
+		_, err = traversal.WalkMatchingFrom(middleMapNode, s, &c, 1, func(traversal.Progress, ipld.Node) error { return nil })
+		Wish(t, err, ShouldEqual, traversal.ErrCursorMismatch{ipld.Path{}, ipld.PathSegmentOfString("nope")})
+		Wish(t, err.Error(), ShouldEqual, `cannot resume traversal: no segment "nope" reachable at ""`)
+	})
+}
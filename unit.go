@@ -62,6 +62,10 @@ func (nullStyle) NewBuilder() NodeBuilder {
 	panic("cannot build null nodes") // TODO: okay, fine, we could grind out a simple closing of the loop here.
 }
 
+// Undef is the undefined node: it's what a struct's absent optional fields look up as.
+// Its ReprKind is Null (for want of any better), and its Length is -1,
+// but its lookup and AsX methods all return ErrUndefinedNode, rather than ErrWrongKind,
+// so it can be told apart from a null (or any other wrong kind) by its errors too.
 var Undef Node = undefNode{}
 
 type undefNode struct{}
@@ -70,16 +74,16 @@ func (undefNode) ReprKind() ReprKind {
 	return ReprKind_Null
 }
 func (undefNode) LookupString(key string) (Node, error) {
-	return nil, ErrWrongKind{TypeName: "undef", MethodName: "LookupString", AppropriateKind: ReprKindSet_JustMap, ActualKind: ReprKind_Null}
+	return nil, ErrUndefinedNode{MethodName: "LookupString"}
 }
 func (undefNode) Lookup(key Node) (Node, error) {
-	return nil, ErrWrongKind{TypeName: "undef", MethodName: "Lookup", AppropriateKind: ReprKindSet_JustMap, ActualKind: ReprKind_Null}
+	return nil, ErrUndefinedNode{MethodName: "Lookup"}
 }
 func (undefNode) LookupIndex(idx int) (Node, error) {
-	return nil, ErrWrongKind{TypeName: "undef", MethodName: "LookupIndex", AppropriateKind: ReprKindSet_JustList, ActualKind: ReprKind_Null}
+	return nil, ErrUndefinedNode{MethodName: "LookupIndex"}
 }
 func (undefNode) LookupSegment(seg PathSegment) (Node, error) {
-	return nil, ErrWrongKind{TypeName: "undef", MethodName: "LookupSegment", AppropriateKind: ReprKindSet_Recursive, ActualKind: ReprKind_Null}
+	return nil, ErrUndefinedNode{MethodName: "LookupSegment"}
 }
 func (undefNode) MapIterator() MapIterator {
 	return nil
@@ -97,22 +101,22 @@ func (undefNode) IsNull() bool {
 	return false
 }
 func (undefNode) AsBool() (bool, error) {
-	return false, ErrWrongKind{TypeName: "undef", MethodName: "AsBool", AppropriateKind: ReprKindSet_JustBool, ActualKind: ReprKind_Null}
+	return false, ErrUndefinedNode{MethodName: "AsBool"}
 }
 func (undefNode) AsInt() (int, error) {
-	return 0, ErrWrongKind{TypeName: "undef", MethodName: "AsInt", AppropriateKind: ReprKindSet_JustInt, ActualKind: ReprKind_Null}
+	return 0, ErrUndefinedNode{MethodName: "AsInt"}
 }
 func (undefNode) AsFloat() (float64, error) {
-	return 0, ErrWrongKind{TypeName: "undef", MethodName: "AsFloat", AppropriateKind: ReprKindSet_JustFloat, ActualKind: ReprKind_Null}
+	return 0, ErrUndefinedNode{MethodName: "AsFloat"}
 }
 func (undefNode) AsString() (string, error) {
-	return "", ErrWrongKind{TypeName: "undef", MethodName: "AsString", AppropriateKind: ReprKindSet_JustString, ActualKind: ReprKind_Null}
+	return "", ErrUndefinedNode{MethodName: "AsString"}
 }
 func (undefNode) AsBytes() ([]byte, error) {
-	return nil, ErrWrongKind{TypeName: "undef", MethodName: "AsBytes", AppropriateKind: ReprKindSet_JustBytes, ActualKind: ReprKind_Null}
+	return nil, ErrUndefinedNode{MethodName: "AsBytes"}
 }
 func (undefNode) AsLink() (Link, error) {
-	return nil, ErrWrongKind{TypeName: "undef", MethodName: "AsLink", AppropriateKind: ReprKindSet_JustLink, ActualKind: ReprKind_Null}
+	return nil, ErrUndefinedNode{MethodName: "AsLink"}
 }
 func (undefNode) Style() NodeStyle {
 	return undefStyle{}